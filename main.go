@@ -1,12 +1,20 @@
 package main
 
 import (
+	"errors"
+	"os"
+
 	log "github.com/sirupsen/logrus"
 	"github.com/wolfi-dev/wolfictl/pkg/cli"
 )
 
 func main() {
 	if err := cli.New().Execute(); err != nil {
+		var exitErr *cli.ExitCodeError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.Code)
+		}
+
 		log.Fatalf("error during command execution: %v", err)
 	}
 }
@@ -0,0 +1,153 @@
+// Package advise produces maintenance recommendations for a distro by
+// combining its build configurations, its advisory data, and its history.
+package advise
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/openvex/go-vex/pkg/vex"
+	"github.com/samber/lo"
+	"github.com/wolfi-dev/wolfictl/pkg/configs"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	"github.com/wolfi-dev/wolfictl/pkg/dag"
+)
+
+// RebuildsOptions configures the Rebuilds operation.
+type RebuildsOptions struct {
+	// Graph is the dependency graph of the distro's packages.
+	Graph *dag.Graph
+
+	// AdvisoryCfgs is the Index of advisories used to find recent fixes. If nil,
+	// advisory data is not considered.
+	AdvisoryCfgs *configs.Index[advisoryconfigs.Document]
+
+	// Since is the point in time after which changes are considered.
+	Since time.Time
+
+	// UpdatedPackages maps the names of origin packages whose version changed
+	// since Since to a description of the change (e.g. "1.2.3 -> 1.3.0").
+	UpdatedPackages map[string]string
+
+	// SoNameChanges lists the names of origin packages known to have changed the
+	// so-names of the shared libraries they provide.
+	SoNameChanges []string
+}
+
+// Recommendation is a package that should get an epoch bump and rebuild,
+// along with the reasons why.
+type Recommendation struct {
+	Package string
+	Reasons []string
+}
+
+// Rebuilds recommends which packages need an epoch-bump rebuild because
+// something they depend on has changed since the given time. Recommendations
+// are returned in build order, i.e. a package is listed after any other
+// recommended package it depends on.
+func Rebuilds(opts RebuildsOptions) ([]Recommendation, error) {
+	if opts.Graph == nil {
+		return nil, fmt.Errorf("a package graph is required")
+	}
+
+	triggers := make(map[string][]string)
+
+	for _, name := range opts.SoNameChanges {
+		triggers[name] = append(triggers[name], "changed its so-name")
+	}
+
+	for name, change := range opts.UpdatedPackages {
+		triggers[name] = append(triggers[name], fmt.Sprintf("was updated (%s)", change))
+	}
+
+	if opts.AdvisoryCfgs != nil {
+		for name, vulns := range fixesSince(opts.AdvisoryCfgs, opts.Since) {
+			triggers[name] = append(triggers[name], fmt.Sprintf("fixed %s", joinVulns(vulns)))
+		}
+	}
+
+	sorted, err := opts.Graph.ReverseSorted()
+	if err != nil {
+		return nil, fmt.Errorf("unable to sort package graph: %w", err)
+	}
+	buildOrder := lo.Uniq(lo.FilterMap(sorted, func(p dag.Package, _ int) (string, bool) {
+		c, ok := p.(*dag.Configuration)
+		if !ok {
+			return "", false
+		}
+		return c.Package.Name, true
+	}))
+
+	dependents, err := opts.Graph.DependentsOf(lo.Keys(triggers)...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find dependents of changed packages: %w", err)
+	}
+
+	return recommend(triggers, dependents, buildOrder), nil
+}
+
+// recommend turns the set of changed packages (mapped to descriptions of their
+// changes) into recommendations for their dependents, ordered by buildOrder.
+// The dependents map is keyed by the names of the changed packages.
+func recommend(triggers, dependents map[string][]string, buildOrder []string) []Recommendation {
+	reasonsByPackage := make(map[string][]string)
+
+	names := lo.Keys(triggers)
+	sort.Strings(names)
+
+	for _, name := range names {
+		for _, change := range triggers[name] {
+			for _, d := range dependents[name] {
+				reasonsByPackage[d] = append(reasonsByPackage[d], fmt.Sprintf("depends on %s, which %s", name, change))
+			}
+		}
+	}
+
+	var recommendations []Recommendation
+	for _, name := range buildOrder {
+		reasons, ok := reasonsByPackage[name]
+		if !ok {
+			continue
+		}
+
+		recommendations = append(recommendations, Recommendation{
+			Package: name,
+			Reasons: reasons,
+		})
+	}
+
+	return recommendations
+}
+
+// fixesSince returns the vulnerabilities that were marked as fixed after the
+// given time, keyed by package name.
+func fixesSince(index *configs.Index[advisoryconfigs.Document], since time.Time) map[string][]string {
+	fixes := make(map[string][]string)
+
+	for _, doc := range index.Select().Configurations() {
+		for vulnID, entries := range doc.Advisories {
+			for _, entry := range entries {
+				if entry.Status == vex.StatusFixed && entry.Timestamp.After(since) {
+					fixes[doc.Package.Name] = append(fixes[doc.Package.Name], vulnID)
+					break
+				}
+			}
+		}
+	}
+
+	for _, vulns := range fixes {
+		sort.Strings(vulns)
+	}
+
+	return fixes
+}
+
+func joinVulns(vulns []string) string {
+	const max = 3
+	if len(vulns) <= max {
+		return fmt.Sprint(vulns)
+	}
+
+	return fmt.Sprintf("%v and %d more", vulns[:max], len(vulns)-max)
+}
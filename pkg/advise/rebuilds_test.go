@@ -0,0 +1,38 @@
+package advise
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecommend(t *testing.T) {
+	dependents := map[string][]string{
+		"openssl": {"curl", "python-3.11"},
+		"zlib":    {"curl"},
+	}
+
+	triggers := map[string][]string{
+		"openssl": {"fixed [CVE-2023-0464]"},
+		"zlib":    {"changed its so-name"},
+	}
+
+	buildOrder := []string{"openssl", "zlib", "python-3.11", "curl"}
+
+	got := recommend(triggers, dependents, buildOrder)
+
+	expected := []Recommendation{
+		{
+			Package: "python-3.11",
+			Reasons: []string{"depends on openssl, which fixed [CVE-2023-0464]"},
+		},
+		{
+			Package: "curl",
+			Reasons: []string{
+				"depends on openssl, which fixed [CVE-2023-0464]",
+				"depends on zlib, which changed its so-name",
+			},
+		},
+	}
+	assert.Equal(t, expected, got)
+}
@@ -0,0 +1,125 @@
+package advise
+
+import (
+	"fmt"
+	"strings"
+
+	version "github.com/knqyf263/go-apk-version"
+	"github.com/openvex/go-vex/pkg/vex"
+	"github.com/wolfi-dev/wolfictl/pkg/vuln"
+	"github.com/wolfi-dev/wolfictl/pkg/vuln/nvdapi"
+)
+
+// TriageSuggestion is a proposed advisory event for an open finding, derived
+// from vulnerability data. It's meant to be confirmed by a human before being
+// recorded.
+type TriageSuggestion struct {
+	Package       string
+	Vulnerability string
+
+	// Status is the suggested status of the package with respect to the
+	// vulnerability. It's empty when the data isn't conclusive enough to suggest
+	// anything.
+	Status vex.Status
+
+	// Justification is set when Status is vex.StatusNotAffected.
+	Justification vex.Justification
+
+	// FixedVersion is set when Status is vex.StatusFixed.
+	FixedVersion string
+
+	// Reason explains how the suggestion was reached.
+	Reason string
+}
+
+// SuggestTriage compares the vulnerable products recorded by NVD for the given
+// CVE against the given package and its full version (e.g. "1.2.3-r1"), and
+// suggests how the package's advisory should be resolved. The match is the
+// result of matching the CVE against the package (see
+// nvdapi.Detector.MatchPackage), and is nil if none of the CVE's vulnerable
+// CPEs are for the package.
+func SuggestTriage(cve *nvdapi.Cve, match *vuln.Match, pkg, fullVersion string) TriageSuggestion {
+	s := TriageSuggestion{
+		Package:       pkg,
+		Vulnerability: cve.ID,
+	}
+
+	if match == nil {
+		products := nvdapi.VulnerableProducts(cve)
+		if len(products) == 0 {
+			s.Reason = "NVD hasn't recorded any vulnerable products for this CVE yet"
+			return s
+		}
+
+		s.Status = vex.StatusNotAffected
+		s.Justification = vex.ComponentNotPresent
+		s.Reason = fmt.Sprintf("NVD lists only other products as vulnerable: %s", strings.Join(products, ", "))
+		return s
+	}
+
+	upstreamVersion := trimEpoch(fullVersion)
+	vr := match.CPE.VersionRange
+
+	if vr.Includes(upstreamVersion) {
+		s.Status = vex.StatusAffected
+		s.Reason = fmt.Sprintf("version %s is within the vulnerable range %s", upstreamVersion, describeRange(vr))
+		return s
+	}
+
+	current, err := version.NewVersion(upstreamVersion)
+	if err != nil {
+		s.Reason = fmt.Sprintf("unable to parse version %q: %v", upstreamVersion, err)
+		return s
+	}
+
+	if vr.VersionRangeUpper != "" {
+		upper, err := version.NewVersion(vr.VersionRangeUpper)
+		if err == nil && !current.LessThan(upper) {
+			s.Status = vex.StatusFixed
+			s.FixedVersion = fullVersion
+			s.Reason = fmt.Sprintf("version %s is beyond the vulnerable range %s", upstreamVersion, describeRange(vr))
+			return s
+		}
+	}
+
+	s.Status = vex.StatusNotAffected
+	s.Justification = vex.VulnerableCodeNotPresent
+	s.Reason = fmt.Sprintf("version %s predates the vulnerable range %s", upstreamVersion, describeRange(vr))
+	return s
+}
+
+// trimEpoch removes the "-r<epoch>" suffix from an APK package version, leaving
+// the upstream version.
+func trimEpoch(v string) string {
+	if i := strings.LastIndex(v, "-r"); i != -1 {
+		return v[:i]
+	}
+
+	return v
+}
+
+// describeRange renders the VersionRange in interval notation, e.g.
+// "[1.0.0, 1.2.3)".
+func describeRange(vr vuln.VersionRange) string {
+	if vr.SingleVersion != "" {
+		return vr.SingleVersion
+	}
+
+	lowerBracket, lower := "(", "*"
+	if vr.VersionRangeLower != "" {
+		lower = vr.VersionRangeLower
+		if vr.VersionRangeLowerInclusive {
+			lowerBracket = "["
+		}
+	}
+
+	upperBracket, upper := ")", "*"
+	if vr.VersionRangeUpper != "" {
+		upper = vr.VersionRangeUpper
+		if vr.VersionRangeUpperInclusive {
+			upperBracket = "]"
+		}
+	}
+
+	return fmt.Sprintf("%s%s, %s%s", lowerBracket, lower, upper, upperBracket)
+}
@@ -0,0 +1,121 @@
+package advise
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/openvex/go-vex/pkg/vex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wolfi-dev/wolfictl/pkg/vuln"
+	"github.com/wolfi-dev/wolfictl/pkg/vuln/nvdapi"
+)
+
+func TestSuggestTriage(t *testing.T) {
+	const cveJSON = `{
+  "id": "CVE-2023-1234",
+  "configurations": [
+    {
+      "nodes": [
+        {
+          "operator": "OR",
+          "cpeMatch": [
+            {
+              "vulnerable": true,
+              "criteria": "cpe:2.3:a:example:other:*:*:*:*:*:*:*:*",
+              "versionEndExcluding": "2.0.0"
+            }
+          ]
+        }
+      ]
+    }
+  ]
+}`
+
+	var cve nvdapi.Cve
+	require.NoError(t, json.Unmarshal([]byte(cveJSON), &cve))
+
+	match := &vuln.Match{
+		CPE: vuln.CPE{
+			VersionRange: vuln.VersionRange{
+				VersionRangeLower:          "1.2.0",
+				VersionRangeLowerInclusive: true,
+				VersionRangeUpper:          "1.4.0",
+			},
+		},
+	}
+
+	cases := []struct {
+		name              string
+		cve               nvdapi.Cve
+		match             *vuln.Match
+		version           string
+		wantStatus        vex.Status
+		wantJustification vex.Justification
+		wantFixedVersion  string
+	}{
+		{
+			name:       "no vulnerable products recorded",
+			cve:        nvdapi.Cve{ID: "CVE-2023-1234"},
+			version:    "1.3.0-r0",
+			wantStatus: "",
+		},
+		{
+			name:              "other product",
+			cve:               cve,
+			version:           "1.3.0-r0",
+			wantStatus:        vex.StatusNotAffected,
+			wantJustification: vex.ComponentNotPresent,
+		},
+		{
+			name:       "within range",
+			cve:        cve,
+			match:      match,
+			version:    "1.3.0-r2",
+			wantStatus: vex.StatusAffected,
+		},
+		{
+			name:             "beyond range",
+			cve:              cve,
+			match:            match,
+			version:          "1.4.0-r1",
+			wantStatus:       vex.StatusFixed,
+			wantFixedVersion: "1.4.0-r1",
+		},
+		{
+			name:              "before range",
+			cve:               cve,
+			match:             match,
+			version:           "1.1.9-r0",
+			wantStatus:        vex.StatusNotAffected,
+			wantJustification: vex.VulnerableCodeNotPresent,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			cve := tt.cve
+			s := SuggestTriage(&cve, tt.match, "foo", tt.version)
+
+			assert.Equal(t, "foo", s.Package)
+			assert.Equal(t, "CVE-2023-1234", s.Vulnerability)
+			assert.Equal(t, tt.wantStatus, s.Status)
+			assert.Equal(t, tt.wantJustification, s.Justification)
+			assert.Equal(t, tt.wantFixedVersion, s.FixedVersion)
+			assert.NotEmpty(t, s.Reason)
+		})
+	}
+}
+
+func Test_describeRange(t *testing.T) {
+	assert.Equal(t, "[1.0, 2.0)", describeRange(vuln.VersionRange{
+		VersionRangeLower:          "1.0",
+		VersionRangeLowerInclusive: true,
+		VersionRangeUpper:          "2.0",
+	}))
+	assert.Equal(t, "(*, 2.0]", describeRange(vuln.VersionRange{
+		VersionRangeUpper:          "2.0",
+		VersionRangeUpperInclusive: true,
+	}))
+	assert.Equal(t, "1.5", describeRange(vuln.VersionRange{SingleVersion: "1.5"}))
+}
@@ -3,6 +3,7 @@ package advisory
 import (
 	"sort"
 
+	"github.com/wolfi-dev/wolfictl/pkg/configs"
 	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
 )
 
@@ -24,3 +25,17 @@ func Latest(entries []advisoryconfigs.Entry) *advisoryconfigs.Entry {
 	latestEntry := items[len(items)-1]
 	return &latestEntry
 }
+
+// LatestForPackage returns the latest entry recorded for the named package
+// under any of the given vulnerability IDs (e.g. a GHSA ID and its CVE alias).
+// If there are no such entries, LatestForPackage returns nil.
+func LatestForPackage(cfgs *configs.Index[advisoryconfigs.Document], packageName string, vulnIDs ...string) *advisoryconfigs.Entry {
+	var entries []advisoryconfigs.Entry
+	for _, doc := range cfgs.Select().WhereName(packageName).Configurations() {
+		for _, id := range vulnIDs {
+			entries = append(entries, doc.Advisories[id]...)
+		}
+	}
+
+	return Latest(entries)
+}
@@ -0,0 +1,22 @@
+package advisory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
+)
+
+func TestLatestForPackage(t *testing.T) {
+	cfgs, err := advisoryconfigs.NewIndex(rwos.DirFS("./testdata/export/advisories"))
+	require.NoError(t, err)
+
+	latest := LatestForPackage(cfgs, "ko", "CVE-2023-0000", "GHSA-2h5h-59f5-c5x9")
+	require.NotNil(t, latest)
+	assert.Equal(t, "0.13.0-r3", latest.FixedVersion)
+
+	assert.Nil(t, LatestForPackage(cfgs, "ko", "CVE-2023-0000"))
+	assert.Nil(t, LatestForPackage(cfgs, "openssl", "GHSA-2h5h-59f5-c5x9"))
+}
@@ -0,0 +1,205 @@
+package advisory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/samber/lo"
+	"github.com/wolfi-dev/wolfictl/pkg/configs"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+)
+
+// AliasLookup looks up the known aliases of a vulnerability ID. It's
+// implemented by vuln.AliasFinder.
+type AliasLookup interface {
+	Aliases(ctx context.Context, id string) ([]string, error)
+}
+
+// SyncAliasesOptions configures the SyncAliases operation.
+type SyncAliasesOptions struct {
+	// AdvisoryCfgs is the Index of advisories whose aliases are synchronized.
+	AdvisoryCfgs *configs.Index[advisoryconfigs.Document]
+
+	// Lookup is used to find the aliases of each advisory's vulnerability.
+	Lookup AliasLookup
+
+	// Package, if set, limits the operation to the named package's advisories.
+	Package string
+
+	// DryRun reports the missing aliases without recording them.
+	DryRun bool
+}
+
+// AliasChange is a set of aliases that were missing from an advisory.
+type AliasChange struct {
+	Package       string
+	Vulnerability string
+	Added         []string
+}
+
+// AliasConflict is an inconsistency between an advisory's aliases and the
+// other advisory data or the alias data from the lookup.
+type AliasConflict struct {
+	Package       string
+	Vulnerability string
+	Detail        string
+}
+
+func (c AliasConflict) String() string {
+	return fmt.Sprintf("%s: %s: %s", c.Package, c.Vulnerability, c.Detail)
+}
+
+// SyncAliasesResult is the outcome of SyncAliases.
+type SyncAliasesResult struct {
+	Changes   []AliasChange
+	Conflicts []AliasConflict
+}
+
+// SyncAliases resolves the complete alias set of each advisory's vulnerability
+// (following aliases of aliases, e.g. GHSA -> CVE -> GO) and records the
+// aliases missing from the advisory documents. It reports as conflicts any
+// recorded aliases the lookup doesn't know about, and any advisories of the same
+// package that are aliases of each other.
+func SyncAliases(ctx context.Context, opts SyncAliasesOptions) (*SyncAliasesResult, error) {
+	sel := opts.AdvisoryCfgs.Select()
+	if opts.Package != "" {
+		sel = sel.WhereName(opts.Package)
+	}
+
+	docs := sel.Configurations()
+	sort.Slice(docs, func(i, j int) bool {
+		return docs[i].Name() < docs[j].Name()
+	})
+
+	r := aliasResolver{
+		lookup: opts.Lookup,
+		cache:  make(map[string][]string),
+	}
+	result := &SyncAliasesResult{}
+
+	for _, doc := range docs {
+		ids := lo.Keys(doc.Advisories)
+		sort.Strings(ids)
+
+		updated := make(advisoryconfigs.Aliases)
+		for _, id := range ids {
+			resolved, err := r.resolve(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			recorded := doc.Aliases[id]
+
+			for _, other := range ids {
+				if other != id && lo.Contains(resolved, other) && id < other {
+					result.Conflicts = append(result.Conflicts, AliasConflict{
+						Package:       doc.Name(),
+						Vulnerability: id,
+						Detail:        fmt.Sprintf("duplicates advisory %s, which is an alias", other),
+					})
+				}
+			}
+
+			if len(resolved) > 0 {
+				for _, alias := range recorded {
+					// CGA IDs are the distro's own, so the lookup can't know them.
+					if !lo.Contains(resolved, alias) && !IsCGAID(alias) {
+						result.Conflicts = append(result.Conflicts, AliasConflict{
+							Package:       doc.Name(),
+							Vulnerability: id,
+							Detail:        fmt.Sprintf("recorded alias %s is not a known alias", alias),
+						})
+					}
+				}
+			}
+
+			missing, _ := lo.Difference(resolved, recorded)
+			if len(missing) == 0 {
+				continue
+			}
+
+			result.Changes = append(result.Changes, AliasChange{
+				Package:       doc.Name(),
+				Vulnerability: id,
+				Added:         missing,
+			})
+
+			aliases := append(append([]string{}, recorded...), missing...)
+			sort.Strings(aliases)
+			updated[id] = aliases
+		}
+
+		if len(updated) == 0 || opts.DryRun {
+			continue
+		}
+
+		u := advisoryconfigs.NewAliasesSectionUpdater(func(cfg advisoryconfigs.Document) (advisoryconfigs.Aliases, error) {
+			aliases := cfg.Aliases
+			if aliases == nil {
+				aliases = make(advisoryconfigs.Aliases)
+			}
+			for id, ids := range updated {
+				aliases[id] = ids
+			}
+
+			return aliases, nil
+		})
+		if err := opts.AdvisoryCfgs.Select().WhereName(doc.Name()).Update(u); err != nil {
+			return nil, fmt.Errorf("unable to update aliases for %q: %w", doc.Name(), err)
+		}
+	}
+
+	return result, nil
+}
+
+// aliasResolver finds the complete, transitive alias sets of vulnerability IDs,
+// caching lookups along the way.
+type aliasResolver struct {
+	lookup AliasLookup
+	cache  map[string][]string
+}
+
+func (r aliasResolver) aliases(ctx context.Context, id string) ([]string, error) {
+	if aliases, ok := r.cache[id]; ok {
+		return aliases, nil
+	}
+
+	aliases, err := r.lookup.Aliases(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	r.cache[id] = aliases
+
+	return aliases, nil
+}
+
+// resolve returns the sorted aliases of the given ID, not including the ID
+// itself.
+func (r aliasResolver) resolve(ctx context.Context, id string) ([]string, error) {
+	seen := map[string]struct{}{id: {}}
+	queue := []string{id}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		aliases, err := r.aliases(ctx, current)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, alias := range aliases {
+			if _, ok := seen[alias]; ok {
+				continue
+			}
+			seen[alias] = struct{}{}
+			queue = append(queue, alias)
+		}
+	}
+
+	delete(seen, id)
+	resolved := lo.Keys(seen)
+	sort.Strings(resolved)
+
+	return resolved, nil
+}
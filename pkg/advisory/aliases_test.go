@@ -0,0 +1,78 @@
+package advisory
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
+)
+
+type fakeAliasLookup map[string][]string
+
+func (l fakeAliasLookup) Aliases(_ context.Context, id string) ([]string, error) {
+	return l[id], nil
+}
+
+func TestSyncAliases(t *testing.T) {
+	const doc = `package:
+  name: foo
+
+advisories:
+  CVE-2023-1:
+    - timestamp: 2023-05-04T10:34:34Z
+      status: under_investigation
+  GHSA-aaaa-bbbb-cccc:
+    - timestamp: 2023-05-04T10:34:34Z
+      status: under_investigation
+  GHSA-dddd-eeee-ffff:
+    - timestamp: 2023-05-04T10:34:34Z
+      status: under_investigation
+
+aliases:
+  GHSA-dddd-eeee-ffff:
+    - CVE-2023-9
+`
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "foo.advisories.yaml"), []byte(doc), 0o644))
+
+	cfgs, err := advisoryconfigs.NewIndex(rwos.DirFS(dir))
+	require.NoError(t, err)
+
+	lookup := fakeAliasLookup{
+		"GHSA-aaaa-bbbb-cccc": {"CVE-2023-1"},
+		"CVE-2023-1":          {"GHSA-aaaa-bbbb-cccc", "GO-2023-1"},
+		"GHSA-dddd-eeee-ffff": {"CVE-2023-2"},
+	}
+
+	result, err := SyncAliases(context.Background(), SyncAliasesOptions{
+		AdvisoryCfgs: cfgs,
+		Lookup:       lookup,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []AliasChange{
+		{Package: "foo", Vulnerability: "CVE-2023-1", Added: []string{"GHSA-aaaa-bbbb-cccc", "GO-2023-1"}},
+		{Package: "foo", Vulnerability: "GHSA-aaaa-bbbb-cccc", Added: []string{"CVE-2023-1", "GO-2023-1"}},
+		{Package: "foo", Vulnerability: "GHSA-dddd-eeee-ffff", Added: []string{"CVE-2023-2"}},
+	}, result.Changes)
+
+	assert.Equal(t, []AliasConflict{
+		{Package: "foo", Vulnerability: "CVE-2023-1", Detail: "duplicates advisory GHSA-aaaa-bbbb-cccc, which is an alias"},
+		{Package: "foo", Vulnerability: "GHSA-dddd-eeee-ffff", Detail: "recorded alias CVE-2023-9 is not a known alias"},
+	}, result.Conflicts)
+
+	updated, err := advisoryconfigs.NewIndex(rwos.DirFS(dir))
+	require.NoError(t, err)
+	aliases := updated.Select().WhereName("foo").Configurations()[0].Aliases
+	assert.Equal(t, advisoryconfigs.Aliases{
+		"CVE-2023-1":          {"GHSA-aaaa-bbbb-cccc", "GO-2023-1"},
+		"GHSA-aaaa-bbbb-cccc": {"CVE-2023-1", "GO-2023-1"},
+		"GHSA-dddd-eeee-ffff": {"CVE-2023-2", "CVE-2023-9"},
+	}, aliases)
+}
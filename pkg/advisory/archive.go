@@ -0,0 +1,163 @@
+package advisory
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/openvex/go-vex/pkg/vex"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	"github.com/wolfi-dev/wolfictl/pkg/yamledit"
+)
+
+// ArchiveDir is the directory, within an advisories repository, that holds the
+// frozen advisory documents of packages that have been removed from the
+// distro. It's not part of the index of active advisory documents.
+const ArchiveDir = "archived"
+
+// ArchiveOptions configures the Archive operation.
+type ArchiveOptions struct {
+	// AdvisoriesDir is the advisories repository directory.
+	AdvisoriesDir string
+
+	// Package is the name of the package whose advisory data is archived.
+	Package string
+
+	// Reason is recorded in the archived document and in the final VEX
+	// statements, e.g. "replaced by foo-2".
+	Reason string
+
+	// ProductNamespace is used to build the product IDs for the final VEX
+	// statements, as in "pkg:apk/<namespace>/<package>".
+	ProductNamespace string
+
+	// Now is the time of archiving. If zero, the current time is used.
+	Now time.Time
+}
+
+// Archive moves the package's advisory document into ArchiveDir, marking it as
+// archived, and returns a final VEX document for the package. The VEX document
+// carries over each advisory's resolution, and marks advisories that were
+// still unresolved as affected with no fix forthcoming, since the package is no
+// longer supported.
+func Archive(opts ArchiveOptions) (*vex.VEX, error) {
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	name := fmt.Sprintf("%s.advisories.yaml", opts.Package)
+	src := filepath.Join(opts.AdvisoriesDir, name)
+	dst := filepath.Join(opts.AdvisoriesDir, ArchiveDir, name)
+
+	if _, err := os.Stat(dst); err == nil {
+		return nil, fmt.Errorf("advisories for %q are already archived", opts.Package)
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	f, err := os.Open(src)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open advisories for %q: %w", opts.Package, err)
+	}
+	doc, err := advisoryconfigs.DecodeDocument(f)
+	f.Close()
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode %s: %w", src, err)
+	}
+
+	// Edit the original YAML, rather than re-encoding doc, to keep its comments.
+	yamlDoc, err := yamledit.ReadFile(src)
+	if err != nil {
+		return nil, err
+	}
+	archived := advisoryconfigs.Archived{Timestamp: now, Reason: opts.Reason}
+	if err := yamlDoc.Set(archived, "archived"); err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return nil, err
+	}
+	if err := yamlDoc.WriteFile(dst); err != nil {
+		return nil, err
+	}
+	if err := os.Remove(src); err != nil {
+		return nil, fmt.Errorf("unable to remove %s: %w", src, err)
+	}
+
+	doc.Archived = &archived
+	return FinalVEX(*doc, opts.ProductNamespace), nil
+}
+
+// FinalVEX returns the VEX document that states the final status of each of the
+// archived package's advisories. Only public advisory data is included.
+func FinalVEX(doc advisoryconfigs.Document, productNamespace string) *vex.VEX {
+	doc = PublicDocument(doc)
+
+	v := vex.New()
+	v.Tooling = "wolfictl"
+	if doc.Archived != nil {
+		ts := doc.Archived.Timestamp
+		v.Timestamp = &ts
+	}
+
+	product := fmt.Sprintf("pkg:apk/%s/%s", productNamespace, doc.Package.Name)
+
+	ids := make([]string, 0, len(doc.Advisories))
+	for id := range doc.Advisories {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		latest := Latest(doc.Advisories[id])
+		if latest == nil {
+			continue
+		}
+
+		stmt := vex.Statement{
+			Vulnerability:   id,
+			Timestamp:       v.Timestamp,
+			Products:        []string{product},
+			Status:          latest.Status,
+			Justification:   latest.Justification,
+			ImpactStatement: latest.ImpactStatement,
+			ActionStatement: latest.ActionStatement,
+		}
+
+		switch latest.Status {
+		case vex.StatusFixed:
+			stmt.StatusNotes = fmt.Sprintf("fixed in version %s", latest.FixedVersion)
+		case vex.StatusAffected, vex.StatusUnderInvestigation:
+			stmt.Status = vex.StatusAffected
+			stmt.Justification = ""
+			stmt.ImpactStatement = ""
+			stmt.ActionStatement = endOfLifeStatement(doc.Archived)
+		}
+
+		if o := SeverityOverrideOf(doc.Advisories[id]); o != nil {
+			notes := describeSeverityOverride(*o)
+			if stmt.StatusNotes != "" {
+				notes = stmt.StatusNotes + ". " + notes
+			}
+			stmt.StatusNotes = notes
+		}
+
+		v.Statements = append(v.Statements, stmt)
+	}
+
+	return &v
+}
+
+func endOfLifeStatement(archived *advisoryconfigs.Archived) string {
+	s := "This package has been removed from the distro and is no longer supported, so no fix will be provided. Stop using it or switch to a supported alternative."
+	if archived != nil && archived.Reason != "" {
+		s += fmt.Sprintf(" (%s)", archived.Reason)
+	}
+	return s
+}
@@ -0,0 +1,88 @@
+package advisory
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/openvex/go-vex/pkg/vex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
+)
+
+const archiveTestDocument = `package:
+  name: foo
+advisories:
+  CVE-2023-0001:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: fixed
+      fixed-version: 1.2.3-r1
+  CVE-2023-0002:
+    - timestamp: 2023-05-02T00:00:00Z
+      status: under_investigation
+`
+
+func TestArchive(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "foo.advisories.yaml"), []byte(archiveTestDocument), 0o644))
+
+	archivedAt := time.Date(2023, time.June, 1, 0, 0, 0, 0, time.UTC)
+	v, err := Archive(ArchiveOptions{
+		AdvisoriesDir:    dir,
+		Package:          "foo",
+		Reason:           "replaced by foo-2",
+		ProductNamespace: "wolfi",
+		Now:              archivedAt,
+	})
+	require.NoError(t, err)
+
+	_, err = os.Stat(filepath.Join(dir, "foo.advisories.yaml"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	f, err := os.Open(filepath.Join(dir, ArchiveDir, "foo.advisories.yaml"))
+	require.NoError(t, err)
+	defer f.Close()
+	doc, err := advisoryconfigs.DecodeDocument(f)
+	require.NoError(t, err)
+	require.NotNil(t, doc.Archived)
+	assert.True(t, archivedAt.Equal(doc.Archived.Timestamp))
+	assert.Equal(t, "replaced by foo-2", doc.Archived.Reason)
+	assert.Len(t, doc.Advisories, 2)
+
+	require.Len(t, v.Statements, 2)
+	assert.Equal(t, "CVE-2023-0001", v.Statements[0].Vulnerability)
+	assert.Equal(t, vex.StatusFixed, v.Statements[0].Status)
+	assert.Equal(t, []string{"pkg:apk/wolfi/foo"}, v.Statements[0].Products)
+	assert.Equal(t, "CVE-2023-0002", v.Statements[1].Vulnerability)
+	assert.Equal(t, vex.StatusAffected, v.Statements[1].Status)
+	assert.Contains(t, v.Statements[1].ActionStatement, "replaced by foo-2")
+
+	_, err = Archive(ArchiveOptions{AdvisoriesDir: dir, Package: "foo"})
+	assert.Error(t, err, "archiving twice should fail")
+
+	active, err := advisoryconfigs.NewIndex(rwos.DirFS(dir))
+	require.NoError(t, err)
+	archived, err := advisoryconfigs.NewIndex(rwos.DirFS(filepath.Join(dir, ArchiveDir)))
+	require.NoError(t, err)
+	assert.Nil(t, Validate(ValidateOptions{AdvisoryCfgs: active, ArchivedAdvisoryCfgs: archived}))
+}
+
+func TestValidateArchivedDocument(t *testing.T) {
+	archivedAt := time.Date(2023, time.June, 1, 0, 0, 0, 0, time.UTC)
+	doc := func(eventTime time.Time, archived *advisoryconfigs.Archived) advisoryconfigs.Document {
+		return advisoryconfigs.Document{
+			Package:  advisoryconfigs.Package{Name: "foo"},
+			Archived: archived,
+			Advisories: advisoryconfigs.Advisories{
+				"CVE-2023-0001": {{Timestamp: eventTime, Status: vex.StatusUnderInvestigation}},
+			},
+		}
+	}
+
+	assert.Nil(t, validateArchivedDocument(doc(archivedAt.AddDate(0, -1, 0), &advisoryconfigs.Archived{Timestamp: archivedAt})))
+	assert.NotNil(t, validateArchivedDocument(doc(archivedAt.AddDate(0, 1, 0), &advisoryconfigs.Archived{Timestamp: archivedAt})))
+	assert.NotNil(t, validateArchivedDocument(doc(archivedAt, nil)))
+}
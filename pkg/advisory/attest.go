@@ -0,0 +1,91 @@
+package advisory
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/openvex/go-vex/pkg/vex"
+)
+
+const (
+	// VEXAttestationType is the cosign predicate type of VEX attestations.
+	VEXAttestationType = "openvex"
+
+	// VEXAttestationSuffix is appended to the path of an attested APK file to get
+	// the path of the attestation (a signed in-toto statement, in a DSSE
+	// envelope) of its VEX document.
+	VEXAttestationSuffix = ".openvex.intoto.json"
+)
+
+// AttestVEXImage signs the OpenVEX document at the given path with cosign and
+// attaches it to the image at the given reference as an attestation, so that
+// consumers pulling the image from its registry can verify the exploitability
+// data with 'cosign verify-attestation --type openvex'. Unless a key is given,
+// keyless signing is used, which records the attestation in the Rekor
+// transparency log.
+func AttestVEXImage(ref, vexPath string, opts SigningOptions) error {
+	if err := validateVEXDocument(vexPath); err != nil {
+		return err
+	}
+
+	args := []string{"attest", "--yes", "--type", VEXAttestationType, "--predicate", vexPath}
+	if opts.Key != "" {
+		args = append(args, "--key", opts.Key)
+	}
+	args = append(args, ref)
+
+	cmd := exec.Command(cosignCommand, args...) //nolint:gosec
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("unable to attest VEX document to image %s: %w: %s", ref, err, strings.TrimSpace(string(out)))
+	}
+
+	return nil
+}
+
+// AttestVEXBlob signs the OpenVEX document at the given path with cosign as an
+// attestation about the file (such as an APK) at blobPath. Since files don't
+// have a registry to carry attestations, the attestation is written next to
+// the file, along with a Sigstore bundle holding the signing certificate and
+// transparency log entry, and both paths are returned. The bundle is always
+// written, since without it a keyless attestation can't be verified. Unless a
+// key is given, keyless signing is used, which records the attestation in the
+// Rekor transparency log.
+func AttestVEXBlob(blobPath, vexPath string, opts SigningOptions) (attestationPath, bundlePath string, err error) {
+	if err := validateVEXDocument(vexPath); err != nil {
+		return "", "", err
+	}
+
+	attestationPath = blobPath + VEXAttestationSuffix
+	bundlePath = attestationPath + ExportBundleSuffix
+
+	args := []string{"attest-blob", "--yes", "--type", VEXAttestationType, "--predicate", vexPath, "--output-attestation", attestationPath, "--bundle", bundlePath}
+	if opts.Key != "" {
+		args = append(args, "--key", opts.Key)
+	}
+	args = append(args, blobPath)
+
+	cmd := exec.Command(cosignCommand, args...) //nolint:gosec
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return "", "", fmt.Errorf("unable to attest VEX document to %s: %w: %s", blobPath, err, strings.TrimSpace(string(out)))
+	}
+
+	return attestationPath, bundlePath, nil
+}
+
+// validateVEXDocument returns an error if the file at the given path isn't a
+// valid OpenVEX document, so that invalid data is never signed.
+func validateVEXDocument(p string) error {
+	doc, err := vex.Load(p)
+	if err != nil {
+		return fmt.Errorf("unable to read VEX document %q: %w", p, err)
+	}
+
+	for i, stmt := range doc.Statements {
+		if err := stmt.Validate(); err != nil {
+			return fmt.Errorf("VEX document %q has an invalid statement (#%d, about %s): %w", p, i+1, stmt.Vulnerability, err)
+		}
+	}
+
+	return nil
+}
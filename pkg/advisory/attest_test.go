@@ -0,0 +1,61 @@
+package advisory
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/openvex/go-vex/pkg/vex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeCosign replaces cosign with a script that records its arguments, and
+// returns a function that reads them.
+func fakeCosign(t *testing.T) func() []string {
+	dir := t.TempDir()
+	argsPath := filepath.Join(dir, "args")
+	script := filepath.Join(dir, "cosign")
+	require.NoError(t, os.WriteFile(script, []byte("#!/bin/sh\nfor a in \"$@\"; do echo \"$a\"; done > "+argsPath+"\n"), 0o755))
+
+	original := cosignCommand
+	cosignCommand = script
+	t.Cleanup(func() { cosignCommand = original })
+
+	return func() []string {
+		b, err := os.ReadFile(argsPath)
+		require.NoError(t, err)
+		return strings.Fields(string(b))
+	}
+}
+
+func writeVEXDocument(t *testing.T, stmts ...vex.Statement) string {
+	v := vex.New()
+	v.Statements = stmts
+
+	p := filepath.Join(t.TempDir(), "doc.openvex.json")
+	f, err := os.Create(p)
+	require.NoError(t, err)
+	defer f.Close()
+	require.NoError(t, v.ToJSON(f))
+
+	return p
+}
+
+func TestAttestVEX(t *testing.T) {
+	args := fakeCosign(t)
+	doc := writeVEXDocument(t, vex.Statement{Vulnerability: "CVE-2023-1", Products: []string{"pkg:apk/wolfi/foo@1.2.3-r1"}, Status: vex.StatusFixed})
+
+	require.NoError(t, AttestVEXImage("cgr.dev/chainguard/foo:latest", doc, SigningOptions{Key: "cosign.key"}))
+	assert.Equal(t, []string{"attest", "--yes", "--type", "openvex", "--predicate", doc, "--key", "cosign.key", "cgr.dev/chainguard/foo:latest"}, args())
+
+	attestation, bundle, err := AttestVEXBlob("foo-1.2.3-r1.apk", doc, SigningOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "foo-1.2.3-r1.apk.openvex.intoto.json", attestation)
+	assert.Equal(t, "foo-1.2.3-r1.apk.openvex.intoto.json.bundle", bundle)
+	assert.Equal(t, []string{"attest-blob", "--yes", "--type", "openvex", "--predicate", doc, "--output-attestation", attestation, "--bundle", bundle, "foo-1.2.3-r1.apk"}, args())
+
+	invalid := writeVEXDocument(t, vex.Statement{Vulnerability: "CVE-2023-1", Products: []string{"pkg:apk/wolfi/foo"}, Status: vex.StatusNotAffected})
+	assert.ErrorContains(t, AttestVEXImage("cgr.dev/chainguard/foo:latest", invalid, SigningOptions{}), "invalid statement (#1, about CVE-2023-1)")
+}
@@ -0,0 +1,57 @@
+package advisory
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	apkversion "github.com/knqyf263/go-apk-version"
+	"github.com/samber/lo"
+	"gitlab.alpinelinux.org/alpine/go/repository"
+)
+
+// ReadPackageList reads a list of package names, one per line. Blank lines and
+// lines starting with "#" are ignored, and duplicate names are dropped.
+func ReadPackageList(r io.Reader) ([]string, error) {
+	var names []string
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		names = append(names, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read package list: %w", err)
+	}
+
+	return lo.Uniq(names), nil
+}
+
+// LatestPublishedVersion returns the latest version of the package published in
+// any of the given APKINDEXes, counting the versions of its subpackages too. It
+// returns an empty string if the package hasn't been published.
+func LatestPublishedVersion(apkindexes []*repository.ApkIndex, packageName string) string {
+	var latest string
+	var latestVersion apkversion.Version
+	for _, apkindex := range apkindexes {
+		for _, pkg := range apkindex.Packages {
+			if pkg.Name != packageName && pkg.Origin != packageName {
+				continue
+			}
+
+			v, err := apkversion.NewVersion(pkg.Version)
+			if err != nil {
+				continue
+			}
+			if latest == "" || latestVersion.LessThan(v) {
+				latest, latestVersion = pkg.Version, v
+			}
+		}
+	}
+
+	return latest
+}
@@ -0,0 +1,41 @@
+package advisory
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gitlab.alpinelinux.org/alpine/go/repository"
+)
+
+func TestReadPackageList(t *testing.T) {
+	const list = `# packages vendoring the library
+foo
+
+bar
+  baz
+foo
+`
+
+	names, err := ReadPackageList(strings.NewReader(list))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"foo", "bar", "baz"}, names)
+}
+
+func TestLatestPublishedVersion(t *testing.T) {
+	apkindexes := []*repository.ApkIndex{
+		{Packages: []*repository.Package{
+			{Name: "foo", Version: "1.2.3-r0"},
+			{Name: "foo", Version: "1.10.0-r9"},
+			{Name: "bar", Version: "2.0.0-r0"},
+		}},
+		{Packages: []*repository.Package{
+			{Name: "foo-dev", Origin: "foo", Version: "1.10.0-r10"},
+		}},
+	}
+
+	assert.Equal(t, "1.10.0-r10", LatestPublishedVersion(apkindexes, "foo"))
+	assert.Equal(t, "2.0.0-r0", LatestPublishedVersion(apkindexes, "bar"))
+	assert.Empty(t, LatestPublishedVersion(apkindexes, "baz"))
+}
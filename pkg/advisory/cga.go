@@ -0,0 +1,271 @@
+package advisory
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/samber/lo"
+	"github.com/wolfi-dev/wolfictl/pkg/configs"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+)
+
+// cgaIDAlphabet is the set of characters in the segments of a CGA ID. Like
+// GHSA IDs, CGA IDs leave out vowels and easily confused characters.
+const cgaIDAlphabet = "23456789cfghjmpqrvwx"
+
+var cgaIDPattern = regexp.MustCompile(`^CGA(-[23456789cfghjmpqrvwx]{4}){3}$`)
+
+// IsCGAID returns true if the given ID is a well-formed CGA ID, the distro's own
+// advisory ID (e.g. "CGA-2fm3-x7g4-q9wh").
+func IsCGAID(id string) bool {
+	return cgaIDPattern.MatchString(id)
+}
+
+// looksLikeCGAID returns true if the given ID is meant to be a CGA ID, even if
+// it's malformed.
+func looksLikeCGAID(id string) bool {
+	return strings.HasPrefix(strings.ToUpper(id), "CGA-")
+}
+
+// NewCGAID returns a new random CGA ID, reading randomness from r, or from
+// crypto/rand if r is nil.
+//
+// CGA IDs have 12 characters from a 20-character alphabet, about 52 bits of
+// randomness. Allocation already avoids the IDs in the index, so only IDs
+// minted concurrently (e.g. on different branches of the advisories
+// repository) can collide, and even among 100,000 of those the chance of any
+// collision is about one in a million. Validate reports any that do.
+func NewCGAID(r io.Reader) (string, error) {
+	if r == nil {
+		r = rand.Reader
+	}
+
+	// Bytes are mapped onto the alphabet by their remainder, so bytes beyond the
+	// largest multiple of the alphabet's size are rejected to keep the
+	// characters uniformly distributed.
+	limit := byte(256 - 256%len(cgaIDAlphabet))
+
+	var chars []byte
+	buf := make([]byte, 16)
+	for len(chars) < 12 {
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return "", fmt.Errorf("unable to generate CGA ID: %w", err)
+		}
+
+		for _, b := range buf {
+			if b >= limit || len(chars) == 12 {
+				continue
+			}
+			chars = append(chars, cgaIDAlphabet[int(b)%len(cgaIDAlphabet)])
+		}
+	}
+
+	return fmt.Sprintf("CGA-%s-%s-%s", chars[0:4], chars[4:8], chars[8:12]), nil
+}
+
+// CGAIDOf returns the CGA ID among the given aliases of an advisory, or "" if
+// there isn't one.
+func CGAIDOf(aliases []string) string {
+	id, _ := lo.Find(aliases, IsCGAID)
+	return id
+}
+
+// AllocateCGAIDsOptions configures the AllocateCGAIDs operation.
+type AllocateCGAIDsOptions struct {
+	// AdvisoryCfgs is the Index of advisories to allocate CGA IDs for.
+	AdvisoryCfgs *configs.Index[advisoryconfigs.Document]
+
+	// Package and Vulnerability, if set, limit the operation to the advisories of
+	// the named package and for the given vulnerability ID, respectively.
+	Package, Vulnerability string
+
+	// Rand is the source of randomness for new IDs. If nil, crypto/rand is used.
+	Rand io.Reader
+
+	// DryRun reports the IDs that would be allocated without recording them.
+	DryRun bool
+}
+
+// CGAAllocation is a CGA ID allocated to an advisory.
+type CGAAllocation struct {
+	Package       string
+	Vulnerability string
+	CGAID         string
+}
+
+// AllocateCGAIDs allocates a new CGA ID to each advisory that doesn't have one
+// yet, recording it among the advisory's aliases. New IDs never collide with
+// any ID or alias already in the advisory data. Allocations are sorted by
+// package and vulnerability.
+func AllocateCGAIDs(opts AllocateCGAIDsOptions) ([]CGAAllocation, error) {
+	taken := make(map[string]bool)
+	for _, doc := range opts.AdvisoryCfgs.Select().Configurations() {
+		for id := range doc.Advisories {
+			taken[id] = true
+		}
+		for _, aliases := range doc.Aliases {
+			for _, alias := range aliases {
+				taken[alias] = true
+			}
+		}
+	}
+
+	sel := opts.AdvisoryCfgs.Select()
+	if opts.Package != "" {
+		sel = sel.WhereName(opts.Package)
+	}
+	docs := sel.Configurations()
+	sort.Slice(docs, func(i, j int) bool {
+		return docs[i].Name() < docs[j].Name()
+	})
+
+	var allocations []CGAAllocation
+	for _, doc := range docs {
+		ids := lo.Keys(doc.Advisories)
+		sort.Strings(ids)
+
+		allocated := make(map[string]string)
+		for _, id := range ids {
+			if opts.Vulnerability != "" && id != opts.Vulnerability {
+				continue
+			}
+			if CGAIDOf(doc.Aliases[id]) != "" {
+				continue
+			}
+
+			cgaID, err := NewCGAID(opts.Rand)
+			for err == nil && taken[cgaID] {
+				cgaID, err = NewCGAID(opts.Rand)
+			}
+			if err != nil {
+				return nil, err
+			}
+			taken[cgaID] = true
+
+			allocated[id] = cgaID
+			allocations = append(allocations, CGAAllocation{
+				Package:       doc.Name(),
+				Vulnerability: id,
+				CGAID:         cgaID,
+			})
+		}
+
+		if len(allocated) == 0 || opts.DryRun {
+			continue
+		}
+
+		u := advisoryconfigs.NewAliasesSectionUpdater(func(cfg advisoryconfigs.Document) (advisoryconfigs.Aliases, error) {
+			aliases := cfg.Aliases
+			if aliases == nil {
+				aliases = make(advisoryconfigs.Aliases)
+			}
+			for id, cgaID := range allocated {
+				updated := append(append([]string{}, aliases[id]...), cgaID)
+				sort.Strings(updated)
+				aliases[id] = updated
+			}
+
+			return aliases, nil
+		})
+		if err := opts.AdvisoryCfgs.Select().WhereName(doc.Name()).Update(u); err != nil {
+			return nil, fmt.Errorf("unable to record CGA IDs for %q: %w", doc.Name(), err)
+		}
+	}
+
+	return allocations, nil
+}
+
+// CGAResolution is an advisory found by ResolveCGAID.
+type CGAResolution struct {
+	Package       string
+	Vulnerability string
+	CGAID         string
+
+	// Aliases are the advisory's other aliases (i.e. not its CGA ID).
+	Aliases []string
+}
+
+// ResolveCGAID finds the advisories identified by the given ID. For a CGA ID,
+// that's the advisory it was allocated to. For any other ID (e.g. a CVE or GHSA
+// ID), it's every advisory recorded under that ID or with it as an alias, one
+// per package. Resolutions are sorted by package and vulnerability.
+func ResolveCGAID(cfgs *configs.Index[advisoryconfigs.Document], id string) []CGAResolution {
+	var resolutions []CGAResolution
+	for _, doc := range cfgs.Select().Configurations() {
+		for vulnID := range doc.Advisories {
+			aliases := doc.Aliases[vulnID]
+			if vulnID != id && !lo.Contains(aliases, id) {
+				continue
+			}
+
+			resolutions = append(resolutions, CGAResolution{
+				Package:       doc.Name(),
+				Vulnerability: vulnID,
+				CGAID:         CGAIDOf(aliases),
+				Aliases:       lo.Reject(aliases, func(alias string, _ int) bool { return IsCGAID(alias) }),
+			})
+		}
+	}
+
+	sort.Slice(resolutions, func(i, j int) bool {
+		if resolutions[i].Package != resolutions[j].Package {
+			return resolutions[i].Package < resolutions[j].Package
+		}
+		return resolutions[i].Vulnerability < resolutions[j].Vulnerability
+	})
+
+	return resolutions
+}
+
+// validateCGAIDAliases checks that each of the given advisory's aliases that's
+// meant to be a CGA ID is well-formed, and that it has at most one.
+func validateCGAIDAliases(aliases []string) []error {
+	var errs []error
+	var cgaIDs []string
+	for _, alias := range aliases {
+		if !looksLikeCGAID(alias) {
+			continue
+		}
+		if !IsCGAID(alias) {
+			errs = append(errs, fmt.Errorf("alias %q is not a valid CGA ID", alias))
+			continue
+		}
+		cgaIDs = append(cgaIDs, alias)
+	}
+
+	if len(cgaIDs) > 1 {
+		errs = append(errs, fmt.Errorf("advisory must have at most one CGA ID, but has %s", strings.Join(cgaIDs, ", ")))
+	}
+
+	return errs
+}
+
+// duplicateCGAIDErrors reports each CGA ID that's been allocated to more than
+// one advisory in the given documents.
+func duplicateCGAIDErrors(docs []advisoryconfigs.Document) []error {
+	owners := make(map[string][]string)
+	for _, doc := range docs {
+		for vulnID, aliases := range doc.Aliases {
+			if cgaID := CGAIDOf(aliases); cgaID != "" {
+				owners[cgaID] = append(owners[cgaID], fmt.Sprintf("%s/%s", doc.Name(), vulnID))
+			}
+		}
+	}
+
+	ids := lo.Keys(owners)
+	sort.Strings(ids)
+
+	var errs []error
+	for _, id := range ids {
+		if len(owners[id]) > 1 {
+			sort.Strings(owners[id])
+			errs = append(errs, fmt.Errorf("CGA ID %s is allocated to more than one advisory: %s", id, strings.Join(owners[id], ", ")))
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,107 @@
+package advisory
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
+)
+
+func TestNewCGAID(t *testing.T) {
+	// 0xff is rejected to keep the characters uniformly distributed.
+	r := bytes.NewReader([]byte{0, 1, 2, 3, 0xff, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14})
+
+	id, err := NewCGAID(r)
+	require.NoError(t, err)
+	assert.Equal(t, "CGA-2345-6789-cfgh", id)
+	assert.True(t, IsCGAID(id))
+
+	id, err = NewCGAID(nil)
+	require.NoError(t, err)
+	assert.True(t, IsCGAID(id))
+
+	_, err = NewCGAID(bytes.NewReader(nil))
+	assert.Error(t, err)
+
+	assert.False(t, IsCGAID("CGA-2345-6789-abcd"))
+	assert.False(t, IsCGAID("CVE-2023-1234"))
+}
+
+func TestAllocateAndResolveCGAIDs(t *testing.T) {
+	const foo = `package:
+  name: foo
+
+advisories:
+  CVE-2023-1:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: under_investigation
+  CVE-2023-2:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: under_investigation
+
+aliases:
+  CVE-2023-1:
+    - CGA-2222-3333-4444
+    - GHSA-2222-3333-4444
+  CVE-2023-2:
+    - CGA-2222-2222-2222
+`
+	const bar = `package:
+  name: bar
+
+advisories:
+  CVE-2023-1:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: under_investigation
+`
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "foo.advisories.yaml"), []byte(foo), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bar.advisories.yaml"), []byte(bar), 0o644))
+
+	index, err := advisoryconfigs.NewIndex(rwos.DirFS(dir))
+	require.NoError(t, err)
+
+	// The first ID generated collides with an existing one, so it's skipped.
+	r := bytes.NewReader(append(bytes.Repeat([]byte{0}, 16), bytes.Repeat([]byte{1}, 16)...))
+
+	allocations, err := AllocateCGAIDs(AllocateCGAIDsOptions{AdvisoryCfgs: index, Rand: r})
+	require.NoError(t, err)
+	assert.Equal(t, []CGAAllocation{
+		{Package: "bar", Vulnerability: "CVE-2023-1", CGAID: "CGA-3333-3333-3333"},
+	}, allocations)
+
+	resolved := ResolveCGAID(index, "CGA-3333-3333-3333")
+	assert.Equal(t, []CGAResolution{{Package: "bar", Vulnerability: "CVE-2023-1", CGAID: "CGA-3333-3333-3333", Aliases: []string{}}}, resolved)
+
+	resolved = ResolveCGAID(index, "CVE-2023-1")
+	require.Len(t, resolved, 2)
+	assert.Equal(t, "CGA-3333-3333-3333", resolved[0].CGAID)
+	assert.Equal(t, CGAResolution{
+		Package:       "foo",
+		Vulnerability: "CVE-2023-1",
+		CGAID:         "CGA-2222-3333-4444",
+		Aliases:       []string{"GHSA-2222-3333-4444"},
+	}, resolved[1])
+
+	assert.Empty(t, ResolveCGAID(index, "CGA-4444-4444-4444"))
+}
+
+func TestValidateCGAIDs(t *testing.T) {
+	assert.Empty(t, validateCGAIDAliases([]string{"CGA-2222-3333-4444", "GHSA-2222-3333-4444"}))
+	assert.Len(t, validateCGAIDAliases([]string{"CGA-2222-3333-4444", "CGA-2222-3333-5555"}), 1)
+	assert.EqualError(t, validateCGAIDAliases([]string{"cga-abcd"})[0], `alias "cga-abcd" is not a valid CGA ID`)
+
+	docs := []advisoryconfigs.Document{
+		{Package: advisoryconfigs.Package{Name: "foo"}, Aliases: advisoryconfigs.Aliases{"CVE-2023-1": {"CGA-2222-3333-4444"}}},
+		{Package: advisoryconfigs.Package{Name: "bar"}, Aliases: advisoryconfigs.Aliases{"CVE-2023-1": {"CGA-2222-3333-4444"}}},
+	}
+	errs := duplicateCGAIDErrors(docs)
+	require.Len(t, errs, 1)
+	assert.EqualError(t, errs[0], "CGA ID CGA-2222-3333-4444 is allocated to more than one advisory: bar/CVE-2023-1, foo/CVE-2023-1")
+}
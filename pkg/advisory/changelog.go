@@ -0,0 +1,186 @@
+package advisory
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	apkversion "github.com/knqyf263/go-apk-version"
+	"github.com/openvex/go-vex/pkg/vex"
+	"github.com/wolfi-dev/wolfictl/pkg/configs"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+)
+
+const (
+	ChangelogFormatMarkdown = "markdown"
+	ChangelogFormatHTML     = "html"
+)
+
+// ChangelogOptions configures the changelog operations.
+type ChangelogOptions struct {
+	AdvisoryCfgIndices []*configs.Index[advisoryconfigs.Document]
+
+	// Since and Until bound the timestamps of the fixed events included. Either
+	// can be zero to leave the range open on that side.
+	Since, Until time.Time
+
+	// Format is the format of the exported changelog: ChangelogFormatMarkdown
+	// (the default) or ChangelogFormatHTML.
+	Format string
+}
+
+// ChangelogPackage lists the vulnerabilities fixed in a package.
+type ChangelogPackage struct {
+	Package string
+	Fixes   []ChangelogFix
+}
+
+// ChangelogFix is a vulnerability fixed in a package.
+type ChangelogFix struct {
+	Vulnerability string
+	FixedVersion  string
+	Timestamp     time.Time
+}
+
+// Changelog returns the vulnerabilities that were fixed within the options'
+// time range, according to the public fixed events of each advisory, grouped
+// by package. Packages are sorted by name, and their fixes by version and then
+// vulnerability.
+func Changelog(opts ChangelogOptions) []ChangelogPackage {
+	fixesByPackage := make(map[string][]ChangelogFix)
+
+	for _, index := range opts.AdvisoryCfgIndices {
+		for _, doc := range index.Select().Configurations() {
+			for vulnID, entries := range doc.Advisories {
+				for _, e := range PublicEntries(entries) {
+					if e.Status != vex.StatusFixed {
+						continue
+					}
+					if !opts.Since.IsZero() && e.Timestamp.Before(opts.Since) {
+						continue
+					}
+					if !opts.Until.IsZero() && e.Timestamp.After(opts.Until) {
+						continue
+					}
+
+					fixesByPackage[doc.Package.Name] = append(fixesByPackage[doc.Package.Name], ChangelogFix{
+						Vulnerability: vulnID,
+						FixedVersion:  e.FixedVersion,
+						Timestamp:     e.Timestamp,
+					})
+				}
+			}
+		}
+	}
+
+	pkgs := make([]ChangelogPackage, 0, len(fixesByPackage))
+	for name, fixes := range fixesByPackage {
+		sort.Slice(fixes, func(i, j int) bool {
+			if fixes[i].FixedVersion != fixes[j].FixedVersion {
+				return versionLess(fixes[i].FixedVersion, fixes[j].FixedVersion)
+			}
+			return fixes[i].Vulnerability < fixes[j].Vulnerability
+		})
+		pkgs = append(pkgs, ChangelogPackage{Package: name, Fixes: fixes})
+	}
+	sort.Slice(pkgs, func(i, j int) bool {
+		return pkgs[i].Package < pkgs[j].Package
+	})
+
+	return pkgs
+}
+
+// ExportChangelog returns a reader of a human-readable security changelog, as
+// computed by Changelog, suitable for publishing in release notes.
+func ExportChangelog(opts ChangelogOptions) (io.Reader, error) {
+	pkgs := Changelog(opts)
+	title := changelogTitle(opts.Since, opts.Until)
+
+	buf := new(bytes.Buffer)
+	switch opts.Format {
+	case "", ChangelogFormatMarkdown:
+		fmt.Fprintf(buf, "# %s\n", title)
+		if len(pkgs) == 0 {
+			buf.WriteString("\nNo vulnerabilities were fixed.\n")
+		}
+		for _, pkg := range pkgs {
+			fmt.Fprintf(buf, "\n## %s\n\n", pkg.Package)
+			for _, fix := range pkg.Fixes {
+				fmt.Fprintf(buf, "- %s: fixed in %s (%s)\n", fix.Vulnerability, fix.FixedVersion, fix.Timestamp.UTC().Format("2006-01-02"))
+			}
+		}
+
+	case ChangelogFormatHTML:
+		err := changelogHTMLTemplate.Execute(buf, struct {
+			Title    string
+			Packages []ChangelogPackage
+		}{title, pkgs})
+		if err != nil {
+			return nil, err
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported changelog format %q (must be %s or %s)", opts.Format, ChangelogFormatMarkdown, ChangelogFormatHTML)
+	}
+
+	return buf, nil
+}
+
+func changelogTitle(since, until time.Time) string {
+	const layout = "2006-01-02"
+
+	var parts []string
+	if !since.IsZero() {
+		parts = append(parts, "from "+since.UTC().Format(layout))
+	}
+	if !until.IsZero() {
+		parts = append(parts, "to "+until.UTC().Format(layout))
+	}
+
+	if len(parts) == 0 {
+		return "Security changelog"
+	}
+
+	return "Security changelog " + strings.Join(parts, " ")
+}
+
+var changelogHTMLTemplate = template.Must(template.New("changelog").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{ .Title }}</title>
+</head>
+<body>
+<h1>{{ .Title }}</h1>
+{{- range .Packages }}
+<h2>{{ .Package }}</h2>
+<ul>
+{{- range .Fixes }}
+<li>{{ .Vulnerability }}: fixed in {{ .FixedVersion }} ({{ .Timestamp.UTC.Format "2006-01-02" }})</li>
+{{- end }}
+</ul>
+{{- else }}
+<p>No vulnerabilities were fixed.</p>
+{{- end }}
+</body>
+</html>
+`))
+
+// versionLess compares APK versions, falling back to comparing them as strings
+// if either can't be parsed.
+func versionLess(a, b string) bool {
+	va, err := apkversion.NewVersion(a)
+	if err != nil {
+		return a < b
+	}
+	vb, err := apkversion.NewVersion(b)
+	if err != nil {
+		return a < b
+	}
+
+	return va.LessThan(vb)
+}
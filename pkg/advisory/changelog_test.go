@@ -0,0 +1,86 @@
+package advisory
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wolfi-dev/wolfictl/pkg/configs"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
+)
+
+func TestExportChangelog(t *testing.T) {
+	const foo = `package:
+  name: foo
+
+advisories:
+  CVE-2023-2:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: under_investigation
+    - timestamp: 2023-05-03T00:00:00Z
+      status: fixed
+      fixed-version: 1.10.0-r0
+  CVE-2023-1:
+    - timestamp: 2023-05-02T00:00:00Z
+      status: fixed
+      fixed-version: 1.9.0-r0
+  CVE-2023-3:
+    - timestamp: 2023-05-04T00:00:00Z
+      status: fixed
+      fixed-version: 1.10.0-r0
+      internal: true
+  CVE-2023-4:
+    - timestamp: 2023-04-01T00:00:00Z
+      status: fixed
+      fixed-version: 1.8.0-r0
+`
+	const bar = `package:
+  name: bar
+
+advisories:
+  CVE-2023-5:
+    - timestamp: 2023-05-05T00:00:00Z
+      status: not_affected
+      justification: component_not_present
+`
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "foo.advisories.yaml"), []byte(foo), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bar.advisories.yaml"), []byte(bar), 0o644))
+
+	index, err := advisoryconfigs.NewIndex(rwos.DirFS(dir))
+	require.NoError(t, err)
+
+	r, err := ExportChangelog(ChangelogOptions{
+		AdvisoryCfgIndices: []*configs.Index[advisoryconfigs.Document]{index},
+		Since:              time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC),
+		Until:              time.Date(2023, 5, 8, 0, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+
+	b, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, `# Security changelog from 2023-05-01 to 2023-05-08
+
+## foo
+
+- CVE-2023-1: fixed in 1.9.0-r0 (2023-05-02)
+- CVE-2023-2: fixed in 1.10.0-r0 (2023-05-03)
+`, string(b))
+
+	r, err = ExportChangelog(ChangelogOptions{
+		AdvisoryCfgIndices: []*configs.Index[advisoryconfigs.Document]{index},
+		Format:             ChangelogFormatHTML,
+	})
+	require.NoError(t, err)
+
+	b, err = io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), "<h1>Security changelog</h1>")
+	assert.Contains(t, string(b), "<li>CVE-2023-4: fixed in 1.8.0-r0 (2023-04-01)</li>")
+}
@@ -57,6 +57,7 @@ func createAdvisoryConfig(cfgs *configs.Index[advisory.Document], req Request) e
 	advisories[vulnID] = append(advisories[vulnID], req.toAdvisoryEntry())
 
 	err := cfgs.Create(fmt.Sprintf("%s.advisories.yaml", req.Package), advisory.Document{
+		SchemaVersion: advisory.SchemaVersion,
 		Package: advisory.Package{
 			Name: req.Package,
 		},
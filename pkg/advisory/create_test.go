@@ -0,0 +1,91 @@
+package advisory
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/openvex/go-vex/pkg/vex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wolfi-dev/wolfictl/pkg/configs"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	"github.com/wolfi-dev/wolfictl/pkg/configs/rwfs"
+	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
+)
+
+func TestCreateConcurrentWriters(t *testing.T) {
+	const foo = `package:
+  name: foo
+
+advisories:
+  CVE-2023-1:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: under_investigation
+`
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "foo.advisories.yaml"), []byte(foo), 0o644))
+
+	// Each writer indexes the advisory data before any of them writes, like
+	// separate automation jobs would.
+	const writers = 8
+	indices := make([]*configs.Index[advisoryconfigs.Document], writers)
+	for i := range indices {
+		index, err := advisoryconfigs.NewIndex(rwos.DirFS(dir))
+		require.NoError(t, err)
+		indices[i] = index
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, writers)
+	for i := range indices {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = Create(Request{
+				Package:       "foo",
+				Vulnerability: fmt.Sprintf("CVE-2023-%d", i+2),
+				Status:        vex.StatusUnderInvestigation,
+				Timestamp:     time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC),
+			}, CreateOptions{AdvisoryCfgs: indices[i]})
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+
+	index, err := advisoryconfigs.NewIndex(rwos.DirFS(dir))
+	require.NoError(t, err)
+	doc := index.Select().WhereName("foo").Configurations()[0]
+	assert.Len(t, doc.Advisories, writers+1)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "lock and temp files should be removed")
+}
+
+func TestCreateLocked(t *testing.T) {
+	dir := t.TempDir()
+	index, err := advisoryconfigs.NewIndex(rwos.DirFS(dir))
+	require.NoError(t, err)
+
+	timeout := rwos.LockTimeout
+	rwos.LockTimeout = 50 * time.Millisecond
+	t.Cleanup(func() { rwos.LockTimeout = timeout })
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "foo.advisories.yaml.lock"), nil, 0o644))
+
+	err = Create(Request{
+		Package:       "foo",
+		Vulnerability: "CVE-2023-1",
+		Status:        vex.StatusUnderInvestigation,
+		Timestamp:     time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC),
+	}, CreateOptions{AdvisoryCfgs: index})
+	assert.ErrorIs(t, err, rwfs.ErrLocked)
+}
@@ -0,0 +1,337 @@
+package advisory
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openvex/go-vex/pkg/vex"
+	"github.com/samber/lo"
+	"github.com/wolfi-dev/wolfictl/pkg/configs"
+	"github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+)
+
+// CSAF is a CSAF 2.0 document using the VEX profile (see
+// https://docs.oasis-open.org/csaf/csaf/v2.0/csaf-v2.0.html). Only the parts of
+// the schema used by ExportCSAF are modeled.
+type CSAF struct {
+	Document        CSAFDocument        `json:"document"`
+	ProductTree     CSAFProductTree     `json:"product_tree"`
+	Vulnerabilities []CSAFVulnerability `json:"vulnerabilities"`
+}
+
+type CSAFDocument struct {
+	Category    string        `json:"category"`
+	CSAFVersion string        `json:"csaf_version"`
+	Publisher   CSAFPublisher `json:"publisher"`
+	Title       string        `json:"title"`
+	Tracking    CSAFTracking  `json:"tracking"`
+}
+
+type CSAFPublisher struct {
+	Category  string `json:"category"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
+
+type CSAFTracking struct {
+	ID                 string         `json:"id"`
+	Status             string         `json:"status"`
+	Version            string         `json:"version"`
+	InitialReleaseDate time.Time      `json:"initial_release_date"`
+	CurrentReleaseDate time.Time      `json:"current_release_date"`
+	RevisionHistory    []CSAFRevision `json:"revision_history"`
+}
+
+type CSAFRevision struct {
+	Date    time.Time `json:"date"`
+	Number  string    `json:"number"`
+	Summary string    `json:"summary"`
+}
+
+type CSAFProductTree struct {
+	FullProductNames []CSAFProduct `json:"full_product_names"`
+}
+
+type CSAFProduct struct {
+	Name                        string            `json:"name"`
+	ProductID                   string            `json:"product_id"`
+	ProductIdentificationHelper CSAFProductHelper `json:"product_identification_helper"`
+}
+
+type CSAFProductHelper struct {
+	Purl string `json:"purl"`
+}
+
+type CSAFVulnerability struct {
+	CVE           string            `json:"cve,omitempty"`
+	IDs           []CSAFID          `json:"ids,omitempty"`
+	ProductStatus CSAFProductStatus `json:"product_status"`
+	Flags         []CSAFFlag        `json:"flags,omitempty"`
+	Threats       []CSAFThreat      `json:"threats,omitempty"`
+	Remediations  []CSAFRemediation `json:"remediations,omitempty"`
+	References    []CSAFReference   `json:"references,omitempty"`
+
+	// products is the set of product IDs with a status so far.
+	products map[string]struct{}
+}
+
+type CSAFID struct {
+	SystemName string `json:"system_name"`
+	Text       string `json:"text"`
+}
+
+// CSAFProductStatus lists product IDs by their status with respect to a
+// vulnerability.
+type CSAFProductStatus struct {
+	Fixed              []string `json:"fixed,omitempty"`
+	KnownAffected      []string `json:"known_affected,omitempty"`
+	KnownNotAffected   []string `json:"known_not_affected,omitempty"`
+	UnderInvestigation []string `json:"under_investigation,omitempty"`
+}
+
+type CSAFFlag struct {
+	Label      string   `json:"label"`
+	ProductIDs []string `json:"product_ids"`
+}
+
+type CSAFThreat struct {
+	Category   string   `json:"category"`
+	Details    string   `json:"details"`
+	ProductIDs []string `json:"product_ids"`
+}
+
+type CSAFReference struct {
+	Category string `json:"category"`
+	Summary  string `json:"summary"`
+	URL      string `json:"url"`
+}
+
+// csafReferenceSummaries describe each type of advisory reference.
+var csafReferenceSummaries = map[advisory.ReferenceType]string{
+	advisory.ReferenceFixCommit:        "Upstream fix commit",
+	advisory.ReferenceUpstreamAdvisory: "Upstream advisory",
+	advisory.ReferenceDistroPR:         "Distro pull request",
+	advisory.ReferenceOther:            "Reference",
+}
+
+type CSAFRemediation struct {
+	Category   string   `json:"category"`
+	Details    string   `json:"details"`
+	ProductIDs []string `json:"product_ids"`
+}
+
+// ExportCSAFOptions configures ExportCSAF.
+type ExportCSAFOptions struct {
+	AdvisoryCfgIndices []*configs.Index[advisory.Document]
+
+	// ProductNamespace is used to build the products' purls, as in
+	// "pkg:apk/<namespace>/<package>".
+	ProductNamespace string
+
+	// PublisherName and PublisherNamespace (a URL) identify the publisher of the
+	// document.
+	PublisherName      string
+	PublisherNamespace string
+
+	// Now is the release date of the document. If zero, the current time is used.
+	Now time.Time
+}
+
+// ExportCSAF returns a CSAF VEX document stating the status of each package
+// with respect to each vulnerability it has an advisory for, based on the
+// latest public event of the advisory. A fixed package is identified by its
+// fixed version, and other packages by name.
+func ExportCSAF(opts ExportCSAFOptions) (*CSAF, error) {
+	if opts.ProductNamespace == "" || opts.PublisherName == "" || opts.PublisherNamespace == "" {
+		return nil, fmt.Errorf("product namespace, publisher name, and publisher namespace are all required")
+	}
+
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	now = now.UTC()
+
+	products := make(map[string]CSAFProduct)
+	vulns := make(map[string]*CSAFVulnerability)
+
+	for _, index := range opts.AdvisoryCfgIndices {
+		for _, doc := range index.Select().Configurations() {
+			for vulnID, entries := range doc.Advisories {
+				public := PublicEntries(entries)
+				latest := Latest(public)
+				if latest == nil {
+					continue
+				}
+
+				product := csafProduct(opts.ProductNamespace, doc.Package.Name, latest)
+				products[product.ProductID] = product
+
+				v, ok := vulns[vulnID]
+				if !ok {
+					v = newCSAFVulnerability(vulnID)
+					vulns[vulnID] = v
+				}
+				v.add(product.ProductID, *latest, SeverityOverrideOf(public))
+				v.addReferences(ReferencesOf(public))
+			}
+		}
+	}
+
+	csaf := &CSAF{
+		Document: CSAFDocument{
+			Category:    "csaf_vex",
+			CSAFVersion: "2.0",
+			Publisher: CSAFPublisher{
+				Category:  "vendor",
+				Name:      opts.PublisherName,
+				Namespace: opts.PublisherNamespace,
+			},
+			Title: fmt.Sprintf("%s package vulnerability statuses", opts.PublisherName),
+			Tracking: CSAFTracking{
+				ID:                 fmt.Sprintf("%s-vex-%s", opts.ProductNamespace, now.Format("20060102T150405Z")),
+				Status:             "final",
+				Version:            "1",
+				InitialReleaseDate: now,
+				CurrentReleaseDate: now,
+				RevisionHistory: []CSAFRevision{
+					{Date: now, Number: "1", Summary: "Exported from advisory data"},
+				},
+			},
+		},
+		ProductTree: CSAFProductTree{
+			FullProductNames: lo.Values(products),
+		},
+	}
+	sort.Slice(csaf.ProductTree.FullProductNames, func(i, j int) bool {
+		return csaf.ProductTree.FullProductNames[i].ProductID < csaf.ProductTree.FullProductNames[j].ProductID
+	})
+
+	ids := lo.Keys(vulns)
+	sort.Strings(ids)
+	for _, id := range ids {
+		v := vulns[id]
+		v.sort()
+		csaf.Vulnerabilities = append(csaf.Vulnerabilities, *v)
+	}
+
+	return csaf, nil
+}
+
+func csafProduct(namespace, pkg string, latest *advisory.Entry) CSAFProduct {
+	if latest.Status == vex.StatusFixed && latest.FixedVersion != "" {
+		return CSAFProduct{
+			Name:      fmt.Sprintf("%s %s", pkg, latest.FixedVersion),
+			ProductID: fmt.Sprintf("%s-%s", pkg, latest.FixedVersion),
+			ProductIdentificationHelper: CSAFProductHelper{
+				Purl: fmt.Sprintf("pkg:apk/%s/%s@%s", namespace, pkg, latest.FixedVersion),
+			},
+		}
+	}
+
+	return CSAFProduct{
+		Name:      pkg,
+		ProductID: pkg,
+		ProductIdentificationHelper: CSAFProductHelper{
+			Purl: fmt.Sprintf("pkg:apk/%s/%s", namespace, pkg),
+		},
+	}
+}
+
+func newCSAFVulnerability(id string) *CSAFVulnerability {
+	v := &CSAFVulnerability{products: make(map[string]struct{})}
+	if strings.HasPrefix(id, "CVE-") {
+		v.CVE = id
+	} else {
+		system := id
+		if i := strings.Index(id, "-"); i > 0 {
+			system = id[:i]
+		}
+		v.IDs = []CSAFID{{SystemName: system, Text: id}}
+	}
+
+	return v
+}
+
+// add records the product's status, as given by the latest advisory event. In
+// CSAF, a product that's known not to be affected must have a justification
+// flag or an impact statement, and one that's known to be affected must have a
+// remediation. The advisory's severity override, if any, is recorded as an
+// impact threat.
+func (v *CSAFVulnerability) add(productID string, latest advisory.Entry, override *advisory.SeverityOverride) {
+	if _, ok := v.products[productID]; ok {
+		return
+	}
+	v.products[productID] = struct{}{}
+
+	if override != nil {
+		v.Threats = append(v.Threats, CSAFThreat{Category: "impact", Details: describeSeverityOverride(*override), ProductIDs: []string{productID}})
+	}
+
+	switch latest.Status {
+	case vex.StatusFixed:
+		v.ProductStatus.Fixed = append(v.ProductStatus.Fixed, productID)
+
+	case vex.StatusNotAffected:
+		v.ProductStatus.KnownNotAffected = append(v.ProductStatus.KnownNotAffected, productID)
+		if latest.Justification != "" {
+			v.addFlag(string(latest.Justification), productID)
+		}
+		if latest.ImpactStatement != "" || latest.Justification == "" {
+			details := latest.ImpactStatement
+			if details == "" {
+				details = "Not affected."
+			}
+			v.Threats = append(v.Threats, CSAFThreat{Category: "impact", Details: details, ProductIDs: []string{productID}})
+		}
+
+	case vex.StatusAffected:
+		v.ProductStatus.KnownAffected = append(v.ProductStatus.KnownAffected, productID)
+		details := latest.ActionStatement
+		if details == "" {
+			details = "No fix is available yet."
+		}
+		v.Remediations = append(v.Remediations, CSAFRemediation{Category: "none_available", Details: details, ProductIDs: []string{productID}})
+
+	case vex.StatusUnderInvestigation:
+		v.ProductStatus.UnderInvestigation = append(v.ProductStatus.UnderInvestigation, productID)
+	}
+}
+
+// addReferences records the advisory's references as external references of
+// the vulnerability, skipping any URLs already recorded.
+func (v *CSAFVulnerability) addReferences(refs []advisory.Reference) {
+	for _, ref := range refs {
+		if lo.ContainsBy(v.References, func(r CSAFReference) bool { return r.URL == ref.URL }) {
+			continue
+		}
+		v.References = append(v.References, CSAFReference{Category: "external", Summary: csafReferenceSummaries[ref.Type], URL: ref.URL})
+	}
+}
+
+func (v *CSAFVulnerability) addFlag(label, productID string) {
+	for i := range v.Flags {
+		if v.Flags[i].Label == label {
+			v.Flags[i].ProductIDs = append(v.Flags[i].ProductIDs, productID)
+			return
+		}
+	}
+
+	v.Flags = append(v.Flags, CSAFFlag{Label: label, ProductIDs: []string{productID}})
+}
+
+// sort orders the vulnerability's product IDs, so that the output is stable.
+func (v *CSAFVulnerability) sort() {
+	for _, ids := range [][]string{v.ProductStatus.Fixed, v.ProductStatus.KnownAffected, v.ProductStatus.KnownNotAffected, v.ProductStatus.UnderInvestigation} {
+		sort.Strings(ids)
+	}
+	for i := range v.Flags {
+		sort.Strings(v.Flags[i].ProductIDs)
+	}
+	sort.Slice(v.Flags, func(i, j int) bool { return v.Flags[i].Label < v.Flags[j].Label })
+	sort.Slice(v.Threats, func(i, j int) bool { return v.Threats[i].ProductIDs[0] < v.Threats[j].ProductIDs[0] })
+	sort.Slice(v.Remediations, func(i, j int) bool { return v.Remediations[i].ProductIDs[0] < v.Remediations[j].ProductIDs[0] })
+	sort.Slice(v.References, func(i, j int) bool { return v.References[i].URL < v.References[j].URL })
+}
@@ -0,0 +1,64 @@
+package advisory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wolfi-dev/wolfictl/pkg/configs"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
+)
+
+func TestExportCSAF(t *testing.T) {
+	cfgs, err := advisoryconfigs.NewIndex(rwos.DirFS("./testdata/export/advisories"))
+	require.NoError(t, err)
+
+	now := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	csaf, err := ExportCSAF(ExportCSAFOptions{
+		AdvisoryCfgIndices: []*configs.Index[advisoryconfigs.Document]{cfgs},
+		ProductNamespace:   "wolfi",
+		PublisherName:      "Wolfi",
+		PublisherNamespace: "https://wolfi.dev",
+		Now:                now,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "csaf_vex", csaf.Document.Category)
+	assert.Equal(t, "wolfi-vex-20230601T000000Z", csaf.Document.Tracking.ID)
+
+	// One product per fixed version, plus unversioned openssl for the advisory
+	// it's not affected by.
+	assert.Len(t, csaf.ProductTree.FullProductNames, 9)
+	assert.Contains(t, csaf.ProductTree.FullProductNames, CSAFProduct{
+		Name:                        "brotli 1.0.9-r0",
+		ProductID:                   "brotli-1.0.9-r0",
+		ProductIdentificationHelper: CSAFProductHelper{Purl: "pkg:apk/wolfi/brotli@1.0.9-r0"},
+	})
+
+	require.Len(t, csaf.Vulnerabilities, 22)
+	byID := make(map[string]CSAFVulnerability)
+	for _, v := range csaf.Vulnerabilities {
+		id := v.CVE
+		if id == "" {
+			id = v.IDs[0].Text
+		}
+		byID[id] = v
+	}
+
+	assert.Equal(t, []string{"brotli-1.0.9-r0"}, byID["CVE-2020-8927"].ProductStatus.Fixed)
+
+	notAffected := byID["CVE-2023-0466"]
+	assert.Equal(t, []string{"openssl"}, notAffected.ProductStatus.KnownNotAffected)
+	assert.Equal(t, []CSAFFlag{{Label: "vulnerable_code_not_present", ProductIDs: []string{"openssl"}}}, notAffected.Flags)
+	require.Len(t, notAffected.Threats, 1)
+	assert.Equal(t, "impact", notAffected.Threats[0].Category)
+
+	ghsa := byID["GHSA-2h5h-59f5-c5x9"]
+	assert.Equal(t, []CSAFID{{SystemName: "GHSA", Text: "GHSA-2h5h-59f5-c5x9"}}, ghsa.IDs)
+	assert.Equal(t, []string{"ko-0.13.0-r3"}, ghsa.ProductStatus.Fixed)
+
+	_, err = ExportCSAF(ExportCSAFOptions{AdvisoryCfgIndices: []*configs.Index[advisoryconfigs.Document]{cfgs}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,447 @@
+package advisory
+
+import (
+	"fmt"
+	"html/template"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openvex/go-vex/pkg/vex"
+	"github.com/wolfi-dev/wolfictl/pkg/configs"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+)
+
+// DefaultDashboardActivityLimit is the default number of events listed in a
+// dashboard's recent activity feed.
+const DefaultDashboardActivityLimit = 100
+
+// DashboardOptions configures the BuildDashboard operation.
+type DashboardOptions struct {
+	AdvisoryCfgIndices []*configs.Index[advisoryconfigs.Document]
+
+	// Title is the title of the dashboard's pages.
+	Title string
+
+	// ActivityLimit is the number of events listed in the recent activity feed.
+	// If zero, DefaultDashboardActivityLimit is used.
+	ActivityLimit int
+
+	// Now is the time against which the age of open investigations is measured,
+	// and which is shown as the dashboard's generation time.
+	Now time.Time
+}
+
+// Dashboard is the public advisory data, arranged for rendering as a static
+// site with WriteDashboard.
+type Dashboard struct {
+	Title     string
+	Generated time.Time
+
+	// Packages are sorted by name.
+	Packages []DashboardPackage
+
+	// Vulnerabilities are sorted by ID.
+	Vulnerabilities []DashboardVulnerability
+
+	// Investigations are the advisories whose latest event is
+	// under_investigation, longest open first.
+	Investigations []DashboardAdvisory
+
+	// Activity is the most recent events, newest first.
+	Activity []DashboardEvent
+}
+
+// DashboardPackage is a package's page of a Dashboard.
+type DashboardPackage struct {
+	Name     string
+	Archived *advisoryconfigs.Archived
+
+	// Advisories are sorted by vulnerability ID.
+	Advisories []DashboardAdvisory
+}
+
+// DashboardVulnerability is a vulnerability's page of a Dashboard.
+type DashboardVulnerability struct {
+	ID      string
+	Aliases []string
+
+	// Advisories are the vulnerability's advisories, sorted by package.
+	Advisories []DashboardAdvisory
+}
+
+// DashboardAdvisory is a package's advisory for a vulnerability.
+type DashboardAdvisory struct {
+	Package       string
+	Vulnerability string
+	Latest        advisoryconfigs.Entry
+
+	// Events are sorted by timestamp.
+	Events []advisoryconfigs.Entry
+
+	// Open is how long the advisory has been open, measured from its first
+	// event. It's only set for investigations.
+	Open time.Duration
+}
+
+// DashboardEvent is an event in the recent activity feed.
+type DashboardEvent struct {
+	Package       string
+	Vulnerability string
+	advisoryconfigs.Entry
+}
+
+// BuildDashboard arranges the public advisory data of the given indices into a
+// Dashboard.
+func BuildDashboard(opts DashboardOptions) Dashboard {
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	limit := opts.ActivityLimit
+	if limit == 0 {
+		limit = DefaultDashboardActivityLimit
+	}
+
+	d := Dashboard{
+		Title:     opts.Title,
+		Generated: now,
+	}
+	vulns := make(map[string]*DashboardVulnerability)
+
+	for _, index := range opts.AdvisoryCfgIndices {
+		for _, doc := range index.Select().Configurations() {
+			doc := PublicDocument(doc)
+			pkg := DashboardPackage{Name: doc.Package.Name, Archived: doc.Archived}
+
+			for vulnID, entries := range doc.Advisories {
+				latest := Latest(entries)
+				if latest == nil {
+					continue
+				}
+
+				events := make([]advisoryconfigs.Entry, len(entries))
+				copy(events, entries)
+				sort.SliceStable(events, func(i, j int) bool {
+					return events[i].Timestamp.Before(events[j].Timestamp)
+				})
+
+				adv := DashboardAdvisory{
+					Package:       doc.Package.Name,
+					Vulnerability: vulnID,
+					Latest:        *latest,
+					Events:        events,
+				}
+				pkg.Advisories = append(pkg.Advisories, adv)
+
+				v, ok := vulns[vulnID]
+				if !ok {
+					v = &DashboardVulnerability{ID: vulnID}
+					vulns[vulnID] = v
+				}
+				v.Advisories = append(v.Advisories, adv)
+				v.Aliases = append(v.Aliases, doc.Aliases[vulnID]...)
+
+				if latest.Status == vex.StatusUnderInvestigation {
+					adv.Open = now.Sub(events[0].Timestamp)
+					d.Investigations = append(d.Investigations, adv)
+				}
+
+				for _, e := range events {
+					d.Activity = append(d.Activity, DashboardEvent{
+						Package:       doc.Package.Name,
+						Vulnerability: vulnID,
+						Entry:         e,
+					})
+				}
+			}
+
+			sort.Slice(pkg.Advisories, func(i, j int) bool {
+				return pkg.Advisories[i].Vulnerability < pkg.Advisories[j].Vulnerability
+			})
+			d.Packages = append(d.Packages, pkg)
+		}
+	}
+
+	sort.Slice(d.Packages, func(i, j int) bool {
+		return d.Packages[i].Name < d.Packages[j].Name
+	})
+
+	for _, v := range vulns {
+		sort.Slice(v.Advisories, func(i, j int) bool {
+			return v.Advisories[i].Package < v.Advisories[j].Package
+		})
+		v.Aliases = uniqueSorted(v.Aliases)
+		d.Vulnerabilities = append(d.Vulnerabilities, *v)
+	}
+	sort.Slice(d.Vulnerabilities, func(i, j int) bool {
+		return d.Vulnerabilities[i].ID < d.Vulnerabilities[j].ID
+	})
+
+	sort.SliceStable(d.Investigations, func(i, j int) bool {
+		if d.Investigations[i].Open != d.Investigations[j].Open {
+			return d.Investigations[i].Open > d.Investigations[j].Open
+		}
+		return d.Investigations[i].Package < d.Investigations[j].Package
+	})
+
+	sort.SliceStable(d.Activity, func(i, j int) bool {
+		return d.Activity[i].Timestamp.After(d.Activity[j].Timestamp)
+	})
+	if len(d.Activity) > limit {
+		d.Activity = d.Activity[:limit]
+	}
+
+	return d
+}
+
+// WriteDashboard renders the dashboard as a static HTML site in the given
+// directory, which is created if needed: an overview page (index.html), a page
+// per package and per vulnerability (under packages/ and vulnerabilities/,
+// each with an index), the queue of open investigations (investigations.html),
+// and the recent activity feed (activity.html). It returns the paths of the
+// written files.
+func WriteDashboard(d Dashboard, dir string) ([]string, error) {
+	for _, sub := range []string{"packages", "vulnerabilities"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0o755); err != nil {
+			return nil, fmt.Errorf("unable to create dashboard directory: %w", err)
+		}
+	}
+
+	type page struct {
+		path, template string
+		data           any
+	}
+	pages := []page{
+		{"index.html", "index", d},
+		{"investigations.html", "investigations", d},
+		{"activity.html", "activity", d},
+		{filepath.Join("packages", "index.html"), "packages", d},
+		{filepath.Join("vulnerabilities", "index.html"), "vulnerabilities", d},
+	}
+	for _, pkg := range d.Packages {
+		pages = append(pages, page{filepath.Join("packages", pkg.Name+".html"), "package", pkg})
+	}
+	for _, v := range d.Vulnerabilities {
+		pages = append(pages, page{filepath.Join("vulnerabilities", v.ID+".html"), "vulnerability", v})
+	}
+
+	paths := make([]string, 0, len(pages))
+	for _, pg := range pages {
+		p := filepath.Join(dir, pg.path)
+
+		root := "."
+		if strings.Contains(pg.path, string(filepath.Separator)) {
+			root = ".."
+		}
+
+		if err := writeDashboardPage(p, pg.template, dashboardPageData{
+			Title:     d.Title,
+			Generated: d.Generated,
+			Root:      root,
+			Data:      pg.data,
+		}); err != nil {
+			return nil, err
+		}
+		paths = append(paths, p)
+	}
+
+	return paths, nil
+}
+
+type dashboardPageData struct {
+	Title     string
+	Generated time.Time
+
+	// Root is the relative path from the page to the site's root directory.
+	Root string
+
+	Data any
+}
+
+// dashboardLink is the data of the templates linking to a package's or
+// vulnerability's page.
+type dashboardLink struct {
+	Root, Name string
+}
+
+func writeDashboardPage(path, name string, data dashboardPageData) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("unable to create dashboard page: %w", err)
+	}
+	defer f.Close()
+
+	if err := dashboardTemplates.ExecuteTemplate(f, name, data); err != nil {
+		return fmt.Errorf("unable to render dashboard page %q: %w", path, err)
+	}
+
+	return nil
+}
+
+func uniqueSorted(values []string) []string {
+	seen := make(map[string]struct{}, len(values))
+	var result []string
+	for _, v := range values {
+		if _, ok := seen[v]; ok {
+			continue
+		}
+		seen[v] = struct{}{}
+		result = append(result, v)
+	}
+	sort.Strings(result)
+
+	return result
+}
+
+var dashboardTemplates = template.Must(template.New("dashboard").Funcs(template.FuncMap{
+	"pathEscape": url.PathEscape,
+	"link": func(root, name string) dashboardLink {
+		return dashboardLink{Root: root, Name: name}
+	},
+	"date": func(t time.Time) string {
+		return t.UTC().Format("2006-01-02")
+	},
+	"days": func(d time.Duration) int {
+		return int(d.Hours() / 24)
+	},
+}).Parse(`
+{{- define "header" -}}
+<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{ .Title }}</title>
+</head>
+<body>
+<nav>
+<a href="{{ .Root }}/index.html">Overview</a> |
+<a href="{{ .Root }}/packages/index.html">Packages</a> |
+<a href="{{ .Root }}/vulnerabilities/index.html">Vulnerabilities</a> |
+<a href="{{ .Root }}/investigations.html">Open investigations</a> |
+<a href="{{ .Root }}/activity.html">Recent activity</a>
+</nav>
+{{- end }}
+
+{{- define "footer" }}
+<footer>
+<p>Generated {{ .Generated.UTC.Format "2006-01-02 15:04:05 MST" }}</p>
+</footer>
+</body>
+</html>
+{{ end }}
+
+{{- define "status" -}}
+{{ .Status }}
+{{- if .FixedVersion }} in {{ .FixedVersion }}{{ end }}
+{{- if .Justification }} ({{ .Justification }}){{ end }}
+{{- end }}
+
+{{- define "package-link" -}}
+<a href="{{ .Root }}/packages/{{ pathEscape .Name }}.html">{{ .Name }}</a>
+{{- end }}
+
+{{- define "vulnerability-link" -}}
+<a href="{{ .Root }}/vulnerabilities/{{ pathEscape .Name }}.html">{{ .Name }}</a>
+{{- end }}
+
+{{- define "index" }}
+{{- template "header" . }}
+<h1>{{ .Title }}</h1>
+<ul>
+<li><a href="packages/index.html">{{ len .Data.Packages }} packages</a></li>
+<li><a href="vulnerabilities/index.html">{{ len .Data.Vulnerabilities }} vulnerabilities</a></li>
+<li><a href="investigations.html">{{ len .Data.Investigations }} open investigations</a></li>
+</ul>
+{{- template "footer" . }}
+{{- end }}
+
+{{- define "packages" }}
+{{- template "header" . }}
+<h1>Packages</h1>
+<table>
+<tr><th>Package</th><th>Advisories</th></tr>
+{{- range .Data.Packages }}
+<tr><td>{{ template "package-link" (link $.Root .Name) }}{{ if .Archived }} (archived){{ end }}</td><td>{{ len .Advisories }}</td></tr>
+{{- end }}
+</table>
+{{- template "footer" . }}
+{{- end }}
+
+{{- define "package" }}
+{{- template "header" . }}
+<h1>{{ .Data.Name }}</h1>
+{{- with .Data.Archived }}
+<p>Archived on {{ date .Timestamp }}{{ with .Reason }}: {{ . }}{{ end }}</p>
+{{- end }}
+{{- range .Data.Advisories }}
+<h2>{{ template "vulnerability-link" (link $.Root .Vulnerability) }}</h2>
+<p>{{ template "status" .Latest }}</p>
+<ul>
+{{- range .Events }}
+<li>{{ date .Timestamp }}: {{ template "status" . }}{{ with .ImpactStatement }} — {{ . }}{{ end }}{{ with .ActionStatement }} — {{ . }}{{ end }}</li>
+{{- end }}
+</ul>
+{{- else }}
+<p>No advisories.</p>
+{{- end }}
+{{- template "footer" . }}
+{{- end }}
+
+{{- define "vulnerabilities" }}
+{{- template "header" . }}
+<h1>Vulnerabilities</h1>
+<table>
+<tr><th>Vulnerability</th><th>Packages</th></tr>
+{{- range .Data.Vulnerabilities }}
+<tr><td>{{ template "vulnerability-link" (link $.Root .ID) }}</td><td>{{ len .Advisories }}</td></tr>
+{{- end }}
+</table>
+{{- template "footer" . }}
+{{- end }}
+
+{{- define "vulnerability" }}
+{{- template "header" . }}
+<h1>{{ .Data.ID }}</h1>
+{{- with .Data.Aliases }}
+<p>Also known as: {{ range $i, $alias := . }}{{ if $i }}, {{ end }}{{ $alias }}{{ end }}</p>
+{{- end }}
+<table>
+<tr><th>Package</th><th>Status</th><th>Updated</th></tr>
+{{- range .Data.Advisories }}
+<tr><td>{{ template "package-link" (link $.Root .Package) }}</td><td>{{ template "status" .Latest }}</td><td>{{ date .Latest.Timestamp }}</td></tr>
+{{- end }}
+</table>
+{{- template "footer" . }}
+{{- end }}
+
+{{- define "investigations" }}
+{{- template "header" . }}
+<h1>Open investigations</h1>
+<table>
+<tr><th>Package</th><th>Vulnerability</th><th>Open for</th></tr>
+{{- range .Data.Investigations }}
+<tr><td>{{ template "package-link" (link $.Root .Package) }}</td><td>{{ template "vulnerability-link" (link $.Root .Vulnerability) }}</td><td>{{ days .Open }} days</td></tr>
+{{- else }}
+<tr><td colspan="3">No open investigations.</td></tr>
+{{- end }}
+</table>
+{{- template "footer" . }}
+{{- end }}
+
+{{- define "activity" }}
+{{- template "header" . }}
+<h1>Recent activity</h1>
+<ul>
+{{- range .Data.Activity }}
+<li>{{ date .Timestamp }}: {{ template "package-link" (link $.Root .Package) }} / {{ template "vulnerability-link" (link $.Root .Vulnerability) }}: {{ template "status" .Entry }}</li>
+{{- else }}
+<li>No activity.</li>
+{{- end }}
+</ul>
+{{- template "footer" . }}
+{{- end }}
+`))
@@ -0,0 +1,98 @@
+package advisory
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/openvex/go-vex/pkg/vex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wolfi-dev/wolfictl/pkg/configs"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
+)
+
+func TestDashboard(t *testing.T) {
+	const foo = `package:
+  name: foo
+
+advisories:
+  CVE-2023-1:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: under_investigation
+    - timestamp: 2023-05-03T00:00:00Z
+      status: fixed
+      fixed-version: 1.2.3-r1
+  CVE-2023-2:
+    - timestamp: 2023-05-02T00:00:00Z
+      status: under_investigation
+  CVE-2023-3:
+    - timestamp: 2023-05-04T00:00:00Z
+      status: under_investigation
+      internal: true
+
+aliases:
+  CVE-2023-1:
+    - GHSA-1
+`
+	const bar = `package:
+  name: bar
+
+advisories:
+  CVE-2023-1:
+    - timestamp: 2023-04-01T00:00:00Z
+      status: under_investigation
+`
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "foo.advisories.yaml"), []byte(foo), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bar.advisories.yaml"), []byte(bar), 0o644))
+
+	index, err := advisoryconfigs.NewIndex(rwos.DirFS(dir))
+	require.NoError(t, err)
+
+	now := time.Date(2023, 5, 11, 0, 0, 0, 0, time.UTC)
+	d := BuildDashboard(DashboardOptions{
+		AdvisoryCfgIndices: []*configs.Index[advisoryconfigs.Document]{index},
+		Title:              "Wolfi advisories",
+		ActivityLimit:      3,
+		Now:                now,
+	})
+
+	require.Len(t, d.Packages, 2)
+	assert.Equal(t, "bar", d.Packages[0].Name)
+	assert.Equal(t, "foo", d.Packages[1].Name)
+	// The internal-only advisory is left out.
+	assert.Len(t, d.Packages[1].Advisories, 2)
+
+	require.Len(t, d.Vulnerabilities, 2)
+	assert.Equal(t, "CVE-2023-1", d.Vulnerabilities[0].ID)
+	assert.Equal(t, []string{"GHSA-1"}, d.Vulnerabilities[0].Aliases)
+	assert.Len(t, d.Vulnerabilities[0].Advisories, 2)
+
+	require.Len(t, d.Investigations, 2)
+	assert.Equal(t, "bar", d.Investigations[0].Package)
+	assert.Equal(t, 40*24*time.Hour, d.Investigations[0].Open)
+	assert.Equal(t, "CVE-2023-2", d.Investigations[1].Vulnerability)
+
+	require.Len(t, d.Activity, 3)
+	assert.Equal(t, vex.StatusFixed, d.Activity[0].Status)
+	assert.Equal(t, "CVE-2023-2", d.Activity[1].Vulnerability)
+
+	out := t.TempDir()
+	paths, err := WriteDashboard(d, out)
+	require.NoError(t, err)
+	assert.Len(t, paths, 9)
+
+	b, err := os.ReadFile(filepath.Join(out, "packages", "foo.html"))
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `<a href="../vulnerabilities/CVE-2023-1.html">CVE-2023-1</a>`)
+	assert.Contains(t, string(b), "fixed in 1.2.3-r1")
+	assert.NotContains(t, string(b), "CVE-2023-3")
+
+	b, err = os.ReadFile(filepath.Join(out, "investigations.html"))
+	require.NoError(t, err)
+	assert.Contains(t, string(b), "<td>40 days</td>")
+}
@@ -3,13 +3,16 @@ package advisory
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"sort"
 
+	"github.com/hashicorp/go-multierror"
 	"github.com/openvex/go-vex/pkg/vex"
 	"github.com/samber/lo"
 	"github.com/wolfi-dev/wolfictl/pkg/advisory/secdb"
 	"github.com/wolfi-dev/wolfictl/pkg/configs"
 	"github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	"gitlab.alpinelinux.org/alpine/go/repository"
 )
 
 const apkURL = "{{urlprefix}}/{{reponame}}/{{arch}}/{{pkg.name}}-{{pkg.ver}}.apk"
@@ -41,14 +44,19 @@ func BuildDatabase(opts BuildDatabaseOptions) ([]byte, error) {
 			sort.Strings(advisoryVulns)
 
 			secfixes := make(secdb.Secfixes)
+			severities := make(map[string]string)
 
 			for _, vuln := range advisoryVulns {
-				entries := cfg.Advisories[vuln]
+				entries := PublicEntries(cfg.Advisories[vuln])
 
 				if len(entries) == 0 {
 					continue
 				}
 
+				if o := SeverityOverrideOf(entries); o != nil {
+					severities[vuln] = o.Severity
+				}
+
 				latest := Latest(entries)
 				switch latest.Status {
 				case vex.StatusFixed:
@@ -59,7 +67,7 @@ func BuildDatabase(opts BuildDatabaseOptions) ([]byte, error) {
 				}
 			}
 
-			if len(secfixes) == 0 {
+			if len(secfixes) == 0 && len(severities) == 0 {
 				continue
 			}
 
@@ -69,6 +77,9 @@ func BuildDatabase(opts BuildDatabaseOptions) ([]byte, error) {
 					Secfixes: secfixes,
 				},
 			}
+			if len(severities) > 0 {
+				pe.Pkg.Severities = severities
+			}
 
 			cfgPackageEntries = append(cfgPackageEntries, pe)
 		}
@@ -91,3 +102,51 @@ func BuildDatabase(opts BuildDatabaseOptions) ([]byte, error) {
 
 	return json.MarshalIndent(db, "", "  ")
 }
+
+// VerifyFixedVersions checks that each fixed version that BuildDatabase would
+// list for the given advisories has been published, i.e. that it appears in at
+// least one of the given APKINDEXes, either as the version of the package
+// itself or of a subpackage built from it. It returns an error for each fixed
+// version that's missing.
+func VerifyFixedVersions(indices []*configs.Index[advisory.Document], apkindexes []*repository.ApkIndex) error {
+	published := make(map[string]struct{})
+	for _, apkindex := range apkindexes {
+		for _, pkg := range apkindex.Packages {
+			published[pkg.Name+"-"+pkg.Version] = struct{}{}
+			if pkg.Origin != "" {
+				published[pkg.Origin+"-"+pkg.Version] = struct{}{}
+			}
+		}
+	}
+
+	var merr *multierror.Error
+	for _, index := range indices {
+		cfgs := index.Select().Configurations()
+		sort.Slice(cfgs, func(i, j int) bool {
+			return cfgs[i].Name() < cfgs[j].Name()
+		})
+
+		for _, cfg := range cfgs {
+			vulns := lo.Keys(cfg.Advisories)
+			sort.Strings(vulns)
+
+			for _, vuln := range vulns {
+				entries := PublicEntries(cfg.Advisories[vuln])
+				if len(entries) == 0 {
+					continue
+				}
+
+				latest := Latest(entries)
+				if latest.Status != vex.StatusFixed {
+					continue
+				}
+
+				if _, ok := published[cfg.Package.Name+"-"+latest.FixedVersion]; !ok {
+					merr = multierror.Append(merr, fmt.Errorf("%s: %s: fixed version %s has not been published", cfg.Package.Name, vuln, latest.FixedVersion))
+				}
+			}
+		}
+	}
+
+	return merr.ErrorOrNil()
+}
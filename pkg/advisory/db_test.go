@@ -5,11 +5,13 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/go-multierror"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/wolfi-dev/wolfictl/pkg/configs"
 	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
 	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
+	"gitlab.alpinelinux.org/alpine/go/repository"
 )
 
 func TestBuildDatabase(t *testing.T) {
@@ -80,3 +82,32 @@ func TestBuildDatabase(t *testing.T) {
 		})
 	}
 }
+
+func TestVerifyFixedVersions(t *testing.T) {
+	advisoryCfgs, err := advisoryconfigs.NewIndex(rwos.DirFS("./testdata/db/advisories"))
+	require.NoError(t, err)
+	indices := []*configs.Index[advisoryconfigs.Document]{advisoryCfgs}
+
+	apkindex := &repository.ApkIndex{
+		Packages: []*repository.Package{
+			{Name: "brotli", Version: "1.0.9-r0"},
+			{Name: "ko", Version: "0.13.0-r3"},
+			{Name: "libcrypto3", Origin: "openssl", Version: "3.0.7-r0"},
+			{Name: "openssl", Version: "3.0.7-r1"},
+			{Name: "openssl", Version: "3.0.8-r0"},
+			{Name: "openssl", Version: "3.1.0-r1"},
+			{Name: "openssl", Version: "3.1.0-r2"},
+		},
+	}
+
+	err = VerifyFixedVersions(indices, []*repository.ApkIndex{apkindex})
+	require.Error(t, err)
+
+	var merr *multierror.Error
+	require.ErrorAs(t, err, &merr)
+	require.Len(t, merr.Errors, 1)
+	assert.EqualError(t, merr.Errors[0], "openssl: CVE-2023-1255: fixed version 3.1.0-r5 has not been published")
+
+	apkindex.Packages = append(apkindex.Packages, &repository.Package{Name: "openssl", Version: "3.1.0-r5"})
+	assert.NoError(t, VerifyFixedVersions(indices, []*repository.ApkIndex{apkindex}))
+}
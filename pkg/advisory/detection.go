@@ -0,0 +1,53 @@
+package advisory
+
+import (
+	"fmt"
+	"regexp"
+
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	"github.com/wolfi-dev/wolfictl/pkg/scan"
+)
+
+var sha256DigestRegex = regexp.MustCompile(`^sha256:[0-9a-f]{64}$`)
+
+// DetectionFromScan returns the provenance of the given finding, reported by the
+// given scanner for the APK file with the given digest ("sha256:<hex>"). Either
+// of scanner and apkDigest may be unknown (nil or empty). It returns nil if
+// nothing about the finding's provenance is known.
+func DetectionFromScan(scanner *scan.Scanner, apkDigest string, f *scan.Finding) *advisoryconfigs.Detection {
+	d := &advisoryconfigs.Detection{
+		PURL:      f.Package.PURL,
+		APKDigest: apkDigest,
+	}
+	if scanner != nil {
+		d.Scanner = scanner.Name
+		d.ScannerVersion = scanner.Version
+		d.VulnerabilityDBBuilt = scanner.VulnerabilityDB.Built
+	}
+
+	if *d == (advisoryconfigs.Detection{}) {
+		return nil
+	}
+
+	return d
+}
+
+func validateDetection(d *advisoryconfigs.Detection) error {
+	if d == nil {
+		return nil
+	}
+
+	if *d == (advisoryconfigs.Detection{}) {
+		return fmt.Errorf("detection is empty")
+	}
+
+	if d.ScannerVersion != "" && d.Scanner == "" {
+		return fmt.Errorf("detection has scanner version %q but no scanner", d.ScannerVersion)
+	}
+
+	if d.APKDigest != "" && !sha256DigestRegex.MatchString(d.APKDigest) {
+		return fmt.Errorf("detection APK digest is %q but must be of the form sha256:<hex>", d.APKDigest)
+	}
+
+	return nil
+}
@@ -0,0 +1,200 @@
+package advisory
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/openvex/go-vex/pkg/vex"
+	"github.com/samber/lo"
+	"github.com/wolfi-dev/wolfictl/pkg/configs"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+)
+
+// DiffResult describes how advisory data changed between two versions of it.
+type DiffResult struct {
+	Added    []AdvisoryChange
+	Removed  []AdvisoryChange
+	Modified []AdvisoryChange
+}
+
+// AdvisoryChange is a change to a single advisory. For an added advisory, all
+// its events are in AddedEvents, and for a removed one, all its events are in
+// RemovedEvents.
+type AdvisoryChange struct {
+	Package       string
+	Vulnerability string
+	AddedEvents   []advisoryconfigs.Entry
+	RemovedEvents []advisoryconfigs.Entry
+}
+
+// IsEmpty reports whether nothing changed.
+func (d DiffResult) IsEmpty() bool {
+	return len(d.Added)+len(d.Removed)+len(d.Modified) == 0
+}
+
+// Diff compares two versions of advisory data. Changes are sorted by package
+// and then by vulnerability, and events are listed in the order they're
+// recorded. An event that was edited shows up as removed in its old form and
+// added in its new form.
+func Diff(from, to *configs.Index[advisoryconfigs.Document]) DiffResult {
+	fromAdvisories := advisoriesByKey(from)
+	toAdvisories := advisoriesByKey(to)
+
+	var d DiffResult
+
+	for key, toEntries := range toAdvisories {
+		fromEntries, ok := fromAdvisories[key]
+		if !ok {
+			d.Added = append(d.Added, AdvisoryChange{
+				Package:       key[0],
+				Vulnerability: key[1],
+				AddedEvents:   toEntries,
+			})
+			continue
+		}
+
+		added := entriesNotIn(toEntries, fromEntries)
+		removed := entriesNotIn(fromEntries, toEntries)
+		if len(added)+len(removed) > 0 {
+			d.Modified = append(d.Modified, AdvisoryChange{
+				Package:       key[0],
+				Vulnerability: key[1],
+				AddedEvents:   added,
+				RemovedEvents: removed,
+			})
+		}
+	}
+
+	for key, fromEntries := range fromAdvisories {
+		if _, ok := toAdvisories[key]; !ok {
+			d.Removed = append(d.Removed, AdvisoryChange{
+				Package:       key[0],
+				Vulnerability: key[1],
+				RemovedEvents: fromEntries,
+			})
+		}
+	}
+
+	for _, changes := range [][]AdvisoryChange{d.Added, d.Removed, d.Modified} {
+		sort.Slice(changes, func(i, j int) bool {
+			if changes[i].Package != changes[j].Package {
+				return changes[i].Package < changes[j].Package
+			}
+			return changes[i].Vulnerability < changes[j].Vulnerability
+		})
+	}
+
+	return d
+}
+
+// Summary describes each change in a short, human-readable sentence, such as
+// "marked CVE-2023-1234 fixed in foo 1.2.3-r1", for use in e.g. pull request
+// comments. An advisory with new events is described by its latest new event.
+// Sentences are sorted by package and then by vulnerability.
+func (d DiffResult) Summary() []string {
+	type sentence struct {
+		change AdvisoryChange
+		text   string
+	}
+
+	var sentences []sentence
+	for _, c := range append(append([]AdvisoryChange{}, d.Added...), d.Modified...) {
+		var text string
+		switch {
+		case len(c.AddedEvents) == 0:
+			text = fmt.Sprintf("removed %s from the advisory for %s in %s", pluralizeEvents(len(c.RemovedEvents)), c.Vulnerability, c.Package)
+		case len(c.RemovedEvents) > 0:
+			text = fmt.Sprintf("%s, replacing %s", describeEvent(c.Package, c.Vulnerability, latestEntry(c.AddedEvents)), pluralizeEvents(len(c.RemovedEvents)))
+		default:
+			text = describeEvent(c.Package, c.Vulnerability, latestEntry(c.AddedEvents))
+		}
+		sentences = append(sentences, sentence{c, text})
+	}
+	for _, c := range d.Removed {
+		sentences = append(sentences, sentence{c, fmt.Sprintf("removed the advisory for %s in %s", c.Vulnerability, c.Package)})
+	}
+
+	sort.SliceStable(sentences, func(i, j int) bool {
+		a, b := sentences[i].change, sentences[j].change
+		if a.Package != b.Package {
+			return a.Package < b.Package
+		}
+		return a.Vulnerability < b.Vulnerability
+	})
+
+	return lo.Map(sentences, func(s sentence, _ int) string { return s.text })
+}
+
+// describeEvent describes the effect of recording the event in the advisory for
+// vuln in pkg.
+func describeEvent(pkg, vuln string, e advisoryconfigs.Entry) string {
+	switch e.Status {
+	case vex.StatusUnderInvestigation:
+		return fmt.Sprintf("opened investigation for %s in %s", vuln, pkg)
+
+	case vex.StatusAffected:
+		if e.ActionStatement != "" {
+			return fmt.Sprintf("marked %s affected in %s (%s)", vuln, pkg, e.ActionStatement)
+		}
+		return fmt.Sprintf("marked %s affected in %s", vuln, pkg)
+
+	case vex.StatusFixed:
+		return fmt.Sprintf("marked %s fixed in %s %s", vuln, pkg, e.FixedVersion)
+
+	case vex.StatusNotAffected:
+		return fmt.Sprintf("marked %s not affected in %s (%s)", vuln, pkg, strings.ReplaceAll(string(e.Justification), "_", " "))
+	}
+
+	return fmt.Sprintf("recorded a %q event for %s in %s", e.Status, vuln, pkg)
+}
+
+// latestEntry returns the entry with the latest timestamp, preferring the last
+// of any tied entries.
+func latestEntry(entries []advisoryconfigs.Entry) advisoryconfigs.Entry {
+	latest := entries[0]
+	for _, e := range entries[1:] {
+		if !e.Timestamp.Before(latest.Timestamp) {
+			latest = e
+		}
+	}
+
+	return latest
+}
+
+func pluralizeEvents(n int) string {
+	if n == 1 {
+		return "1 event"
+	}
+	return fmt.Sprintf("%d events", n)
+}
+
+// advisoriesByKey returns the advisories' events, keyed by package name and
+// vulnerability ID.
+func advisoriesByKey(index *configs.Index[advisoryconfigs.Document]) map[[2]string][]advisoryconfigs.Entry {
+	m := make(map[[2]string][]advisoryconfigs.Entry)
+	for _, doc := range index.Select().Configurations() {
+		for vuln, entries := range doc.Advisories {
+			key := [2]string{doc.Package.Name, vuln}
+			m[key] = append(m[key], entries...)
+		}
+	}
+
+	return m
+}
+
+// entriesNotIn returns the entries in a that aren't in b, counting duplicates.
+func entriesNotIn(a, b []advisoryconfigs.Entry) []advisoryconfigs.Entry {
+	matched := make([]bool, len(b))
+
+	return lo.Filter(a, func(e advisoryconfigs.Entry, _ int) bool {
+		for i := range b {
+			if !matched[i] && reflect.DeepEqual(e, b[i]) {
+				matched[i] = true
+				return false
+			}
+		}
+		return true
+	})
+}
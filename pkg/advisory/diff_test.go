@@ -0,0 +1,89 @@
+package advisory
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/openvex/go-vex/pkg/vex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wolfi-dev/wolfictl/pkg/configs"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
+)
+
+func TestDiff(t *testing.T) {
+	index := func(docs map[string]string) *configs.Index[advisoryconfigs.Document] {
+		dir := t.TempDir()
+		for name, doc := range docs {
+			require.NoError(t, os.WriteFile(filepath.Join(dir, name+".advisories.yaml"), []byte(doc), 0o644))
+		}
+		cfgs, err := advisoryconfigs.NewIndex(rwos.DirFS(dir))
+		require.NoError(t, err)
+		return cfgs
+	}
+
+	from := index(map[string]string{
+		"foo": `package:
+  name: foo
+advisories:
+  CVE-2023-0001:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: under_investigation
+  CVE-2023-0002:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: under_investigation
+  CVE-2023-0003:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: under_investigation
+`,
+	})
+	to := index(map[string]string{
+		"foo": `package:
+  name: foo
+advisories:
+  CVE-2023-0001:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: under_investigation
+    - timestamp: 2023-05-02T00:00:00Z
+      status: fixed
+      fixed-version: 1.2.3-r1
+  CVE-2023-0003:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: under_investigation
+`,
+		"bar": `package:
+  name: bar
+advisories:
+  CVE-2023-0004:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: under_investigation
+`,
+	})
+
+	d := Diff(from, to)
+
+	require.Len(t, d.Added, 1)
+	assert.Equal(t, "bar", d.Added[0].Package)
+	assert.Equal(t, "CVE-2023-0004", d.Added[0].Vulnerability)
+	assert.Len(t, d.Added[0].AddedEvents, 1)
+
+	require.Len(t, d.Removed, 1)
+	assert.Equal(t, "CVE-2023-0002", d.Removed[0].Vulnerability)
+
+	require.Len(t, d.Modified, 1)
+	assert.Equal(t, "CVE-2023-0001", d.Modified[0].Vulnerability)
+	require.Len(t, d.Modified[0].AddedEvents, 1)
+	assert.Equal(t, vex.StatusFixed, d.Modified[0].AddedEvents[0].Status)
+	assert.Empty(t, d.Modified[0].RemovedEvents)
+
+	assert.Equal(t, []string{
+		"opened investigation for CVE-2023-0004 in bar",
+		"marked CVE-2023-0001 fixed in foo 1.2.3-r1",
+		"removed the advisory for CVE-2023-0002 in foo",
+	}, d.Summary())
+
+	assert.True(t, Diff(from, from).IsEmpty())
+	assert.Empty(t, Diff(from, from).Summary())
+}
@@ -37,6 +37,12 @@ type DiscoverOptions struct {
 
 	// VulnerabilityDetector is how Discover finds for vulnerabilities for packages.
 	VulnerabilityDetector vuln.Detector
+
+	// Since, if set, limits discovery to vulnerabilities published since this
+	// time, which requires VulnerabilityDetector to be a vuln.RecentDetector.
+	// This is much faster than a full discovery, and so is suited to reacting to
+	// new disclosures.
+	Since time.Time
 }
 
 // Discover searches for new vulnerabilities that match packages in a config
@@ -61,7 +67,17 @@ func Discover(opts DiscoverOptions) error {
 
 	packagesToLookup := determinePackagesToLookup(apkindexes, opts.SelectedPackages)
 
-	vulnMatches, err := opts.VulnerabilityDetector.VulnerabilitiesForPackages(ctx, packagesToLookup...)
+	var vulnMatches map[string][]vuln.Match
+	var err error
+	if opts.Since.IsZero() {
+		vulnMatches, err = opts.VulnerabilityDetector.VulnerabilitiesForPackages(ctx, packagesToLookup...)
+	} else {
+		recentDetector, ok := opts.VulnerabilityDetector.(vuln.RecentDetector)
+		if !ok {
+			return fmt.Errorf("vulnerability detector doesn't support discovering recently published vulnerabilities")
+		}
+		vulnMatches, err = recentDetector.RecentVulnerabilitiesForPackages(ctx, opts.Since, packagesToLookup...)
+	}
 	if err != nil {
 		return err
 	}
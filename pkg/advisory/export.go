@@ -13,6 +13,10 @@ import (
 
 type ExportOptions struct {
 	AdvisoryCfgIndices []*configs.Index[advisory.Document]
+
+	// Packages limits the export to these packages (e.g. the version streams of
+	// a project). If empty, all packages are exported.
+	Packages []string
 }
 
 // Export returns a reader of advisory data encoded as CSV.
@@ -32,11 +36,15 @@ func Export(opts ExportOptions) (io.Reader, error) {
 		pkgs := index.Select().Configurations()
 
 		for _, pkg := range pkgs {
+			if len(opts.Packages) > 0 && !lo.Contains(opts.Packages, pkg.Package.Name) {
+				continue
+			}
+
 			ids := lo.Keys(pkg.Advisories)
 			sort.Strings(ids)
 
 			for _, advisoryID := range ids {
-				entries := pkg.Advisories[advisoryID]
+				entries := PublicEntries(pkg.Advisories[advisoryID])
 				latest := Latest(entries)
 
 				if latest == nil {
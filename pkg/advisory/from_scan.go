@@ -0,0 +1,76 @@
+package advisory
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/wolfi-dev/wolfictl/pkg/configs"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	"github.com/wolfi-dev/wolfictl/pkg/scan"
+)
+
+// RequestsFromScan returns a request for each distinct package and
+// vulnerability among the findings of the given scan results, with every other
+// field copied from tmpl. A finding's package is the origin (or name) of the
+// scanned APK, or defaultPackage for results that aren't for an APK. Requests
+// are sorted by package and then vulnerability. Each request records the
+// provenance of the first finding of its vulnerability.
+func RequestsFromScan(results []scan.JSONResult, defaultPackage string, tmpl Request) ([]Request, error) {
+	seen := make(map[[2]string]struct{})
+	var reqs []Request
+
+	for _, result := range results {
+		pkg := defaultPackage
+		apkDigest := ""
+		if result.APK != nil {
+			apkDigest = result.Digest
+			pkg = result.APK.Origin
+			if pkg == "" {
+				pkg = result.APK.Name
+			}
+		}
+
+		for _, f := range result.Findings {
+			if pkg == "" {
+				return nil, fmt.Errorf("unable to tell which package %s's findings belong to", result.Target)
+			}
+
+			key := [2]string{pkg, f.Vulnerability.ID}
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+
+			req := tmpl
+			req.Package = pkg
+			req.Vulnerability = f.Vulnerability.ID
+			req.Detection = DetectionFromScan(result.Scanner, apkDigest, f)
+			reqs = append(reqs, req)
+		}
+	}
+
+	sort.SliceStable(reqs, func(i, j int) bool {
+		if reqs[i].Package != reqs[j].Package {
+			return reqs[i].Package < reqs[j].Package
+		}
+		return reqs[i].Vulnerability < reqs[j].Vulnerability
+	})
+
+	return reqs, nil
+}
+
+// Import creates an advisory for the request's vulnerability, or adds an entry
+// to the existing advisory. It returns false without changing anything if the
+// existing advisory's latest entry already has the request's status.
+func Import(req Request, cfgs *configs.Index[advisoryconfigs.Document]) (bool, error) {
+	latest := LatestForPackage(cfgs, req.Package, req.Vulnerability)
+	if latest == nil {
+		return true, Create(req, CreateOptions{AdvisoryCfgs: cfgs})
+	}
+
+	if latest.Status == req.Status {
+		return false, nil
+	}
+
+	return true, Update(req, UpdateOptions{AdvisoryCfgs: cfgs})
+}
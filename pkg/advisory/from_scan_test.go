@@ -0,0 +1,115 @@
+package advisory
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/openvex/go-vex/pkg/vex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
+	"github.com/wolfi-dev/wolfictl/pkg/scan"
+)
+
+func TestRequestsFromScan(t *testing.T) {
+	finding := func(id string) *scan.Finding {
+		return &scan.Finding{Vulnerability: scan.Vulnerability{ID: id}}
+	}
+	results := []scan.JSONResult{
+		{Target: "ko-0.13.0-r3.apk", APK: &scan.PackageInfo{Name: "ko"}, Findings: []*scan.Finding{finding("GHSA-2"), finding("GHSA-1"), finding("GHSA-2")}},
+		{Target: "crane-0.15.2-r0.apk", APK: &scan.PackageInfo{Name: "crane", Origin: "go-containerregistry"}, Findings: []*scan.Finding{finding("GHSA-1")}},
+		{Target: "sbom.json", Findings: []*scan.Finding{finding("CVE-2023-1")}},
+	}
+	tmpl := Request{Status: vex.StatusUnderInvestigation}
+
+	reqs, err := RequestsFromScan(results, "ko", tmpl)
+	require.NoError(t, err)
+	assert.Equal(t, []Request{
+		{Package: "go-containerregistry", Vulnerability: "GHSA-1", Status: vex.StatusUnderInvestigation},
+		{Package: "ko", Vulnerability: "CVE-2023-1", Status: vex.StatusUnderInvestigation},
+		{Package: "ko", Vulnerability: "GHSA-1", Status: vex.StatusUnderInvestigation},
+		{Package: "ko", Vulnerability: "GHSA-2", Status: vex.StatusUnderInvestigation},
+	}, reqs)
+
+	_, err = RequestsFromScan(results, "", tmpl)
+	assert.Error(t, err)
+}
+
+func TestRequestsFromScanDetection(t *testing.T) {
+	const digest = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	built := time.Date(2023, 5, 31, 1, 2, 3, 0, time.UTC)
+	scanner := &scan.Scanner{Name: "grype", Version: "v0.62.3", VulnerabilityDB: scan.VulnerabilityDBInfo{Built: built}}
+	finding := &scan.Finding{
+		Package:       scan.Package{PURL: "pkg:golang/golang.org/x/net@v0.7.0"},
+		Vulnerability: scan.Vulnerability{ID: "GHSA-1"},
+	}
+	results := []scan.JSONResult{
+		{Target: "ko-0.13.0-r3.apk", APK: &scan.PackageInfo{Name: "ko"}, Digest: digest, Scanner: scanner, Findings: []*scan.Finding{finding}},
+		{Target: "sbom.json", Digest: digest, Findings: []*scan.Finding{{Vulnerability: scan.Vulnerability{ID: "CVE-2023-1"}}}},
+	}
+
+	reqs, err := RequestsFromScan(results, "ko", Request{Status: vex.StatusUnderInvestigation, Timestamp: built})
+	require.NoError(t, err)
+	require.Len(t, reqs, 2)
+
+	// The SBOM's digest isn't an APK's, and nothing else is known about it.
+	assert.Nil(t, reqs[0].Detection)
+
+	want := &advisoryconfigs.Detection{
+		Scanner:              "grype",
+		ScannerVersion:       "v0.62.3",
+		VulnerabilityDBBuilt: built,
+		PURL:                 "pkg:golang/golang.org/x/net@v0.7.0",
+		APKDigest:            digest,
+	}
+	assert.Equal(t, want, reqs[1].Detection)
+
+	cfgs, err := advisoryconfigs.NewIndex(rwos.DirFS(t.TempDir()))
+	require.NoError(t, err)
+	require.NoError(t, Create(reqs[1], CreateOptions{AdvisoryCfgs: cfgs}))
+	assert.Equal(t, want, LatestForPackage(cfgs, "ko", "GHSA-1").Detection)
+}
+
+func TestValidateDetection(t *testing.T) {
+	assert.NoError(t, validateDetection(nil))
+	assert.NoError(t, validateDetection(&advisoryconfigs.Detection{PURL: "pkg:apk/wolfi/ko@0.13.0-r3"}))
+	assert.ErrorContains(t, validateDetection(&advisoryconfigs.Detection{}), "detection is empty")
+	assert.ErrorContains(t, validateDetection(&advisoryconfigs.Detection{ScannerVersion: "v0.62.3"}), "no scanner")
+	assert.ErrorContains(t, validateDetection(&advisoryconfigs.Detection{Scanner: "grype", APKDigest: "0123"}), "sha256:<hex>")
+}
+
+func TestImport(t *testing.T) {
+	dir := t.TempDir()
+	b, err := os.ReadFile("./testdata/export/advisories/ko.advisories.yaml")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ko.advisories.yaml"), b, 0o644))
+
+	cfgs, err := advisoryconfigs.NewIndex(rwos.DirFS(dir))
+	require.NoError(t, err)
+
+	now := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		req     Request
+		changed bool
+	}{
+		{"new advisory", Request{Package: "ko", Vulnerability: "GHSA-new", Status: vex.StatusUnderInvestigation, Timestamp: now}, true},
+		{"new package", Request{Package: "crane", Vulnerability: "GHSA-new", Status: vex.StatusUnderInvestigation, Timestamp: now}, true},
+		{"same status", Request{Package: "ko", Vulnerability: "GHSA-2h5h-59f5-c5x9", Status: vex.StatusFixed, FixedVersion: "0.13.0-r3", Timestamp: now}, false},
+		{"new status", Request{Package: "ko", Vulnerability: "GHSA-6wrf-mxfj-pf5p", Status: vex.StatusUnderInvestigation, Timestamp: now}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changed, err := Import(tt.req, cfgs)
+			require.NoError(t, err)
+			assert.Equal(t, tt.changed, changed)
+
+			latest := LatestForPackage(cfgs, tt.req.Package, tt.req.Vulnerability)
+			require.NotNil(t, latest)
+			assert.Equal(t, tt.req.Status, latest.Status)
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package advisory
+
+import (
+	"sort"
+
+	"github.com/wolfi-dev/wolfictl/pkg/configs"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	"gitlab.alpinelinux.org/alpine/go/repository"
+)
+
+// RemovedPackages returns the names of the packages that have advisory
+// documents but no longer appear in any of the given APKINDEXes, either as a
+// package or as the origin of a subpackage. Names are sorted.
+func RemovedPackages(advisoryCfgs *configs.Index[advisoryconfigs.Document], apkindexes []*repository.ApkIndex) []string {
+	published := make(map[string]struct{})
+	for _, apkindex := range apkindexes {
+		for _, pkg := range apkindex.Packages {
+			published[pkg.Name] = struct{}{}
+			if pkg.Origin != "" {
+				published[pkg.Origin] = struct{}{}
+			}
+		}
+	}
+
+	var removed []string
+	for _, doc := range advisoryCfgs.Select().Configurations() {
+		if _, ok := published[doc.Package.Name]; !ok {
+			removed = append(removed, doc.Package.Name)
+		}
+	}
+	sort.Strings(removed)
+
+	return removed
+}
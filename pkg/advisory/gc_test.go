@@ -0,0 +1,35 @@
+package advisory
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
+	"gitlab.alpinelinux.org/alpine/go/repository"
+)
+
+func TestRemovedPackages(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"foo", "bar", "baz", "qux"} {
+		doc := "package:\n  name: " + name + "\n"
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name+".advisories.yaml"), []byte(doc), 0o644))
+	}
+
+	cfgs, err := advisoryconfigs.NewIndex(rwos.DirFS(dir))
+	require.NoError(t, err)
+
+	apkindexes := []*repository.ApkIndex{
+		{Packages: []*repository.Package{
+			{Name: "foo", Version: "1.0.0-r0"},
+		}},
+		{Packages: []*repository.Package{
+			{Name: "bar-dev", Origin: "bar", Version: "1.0.0-r0"},
+		}},
+	}
+
+	assert.Equal(t, []string{"baz", "qux"}, RemovedPackages(cfgs, apkindexes))
+}
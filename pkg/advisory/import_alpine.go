@@ -0,0 +1,198 @@
+package advisory
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	apkversion "github.com/knqyf263/go-apk-version"
+	"github.com/openvex/go-vex/pkg/vex"
+	"github.com/samber/lo"
+	"github.com/wolfi-dev/wolfictl/pkg/advisory/secdb"
+	"github.com/wolfi-dev/wolfictl/pkg/configs"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+)
+
+// alpineNotAffectedImpact is the impact statement of the not_affected events
+// imported from Alpine's secdb, which doesn't say why a package isn't affected.
+const alpineNotAffectedImpact = "Alpine's security database lists this vulnerability as not affecting the package."
+
+// ImportAlpineSecDBOptions configures the ImportAlpineSecDB operation.
+type ImportAlpineSecDBOptions struct {
+	// AdvisoryCfgs is the Index of advisory configurations on which to operate.
+	AdvisoryCfgs *configs.Index[advisoryconfigs.Document]
+
+	// DB is the Alpine security database to import.
+	DB *secdb.Database
+
+	// Packages are the names of the distro's packages. Only the secdb entries of
+	// packages with these names are imported.
+	Packages []string
+
+	// PublishedVersions returns the published versions of the given package.
+	PublishedVersions func(packageName string) []string
+
+	// Now is the timestamp of the imported events. If zero, the current time is
+	// used.
+	Now time.Time
+
+	// DryRun returns the requests that would be made without making them.
+	DryRun bool
+}
+
+// ImportAlpineSecDB converts the secfixes of Alpine's security database into
+// advisories, for the packages shared with Alpine that don't already have an
+// advisory for the vulnerability (under its ID or as an alias). It returns the
+// requests made, sorted by package and vulnerability.
+//
+// Alpine's release numbers (the "-rN" of a version) are its own, so a
+// vulnerability that Alpine fixed in a version is recorded as fixed in the
+// earliest published version of the package that's no older than Alpine's
+// version. If there's no such version, the vulnerability is recorded as under
+// investigation instead. Vulnerabilities that Alpine lists as not affecting
+// the package are recorded as not_affected.
+func ImportAlpineSecDB(opts ImportAlpineSecDBOptions) ([]Request, error) {
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	var reqs []Request
+	for _, entry := range opts.DB.Packages {
+		name := entry.Pkg.Name
+		if !lo.Contains(opts.Packages, name) {
+			continue
+		}
+
+		existing := existingVulnerabilityIDs(opts.AdvisoryCfgs, name)
+
+		var published []string
+		if opts.PublishedVersions != nil {
+			published = opts.PublishedVersions(name)
+		}
+
+		seen := make(map[string]struct{})
+		for _, alpineVersion := range sortedSecfixesVersions(entry.Pkg.Secfixes) {
+			for _, id := range secfixesVulnerabilityIDs(entry.Pkg.Secfixes[alpineVersion]) {
+				if _, ok := existing[id]; ok {
+					continue
+				}
+				if _, ok := seen[id]; ok {
+					// Listed as fixed more than once; the earliest fix wins.
+					continue
+				}
+				seen[id] = struct{}{}
+
+				req := Request{
+					Package:       name,
+					Vulnerability: id,
+					Timestamp:     now,
+				}
+
+				switch {
+				case alpineVersion == secdb.NAK:
+					req.Status = vex.StatusNotAffected
+					req.Justification = vex.VulnerableCodeNotPresent
+					req.Impact = alpineNotAffectedImpact
+
+				default:
+					if v := earliestVersionAtLeast(published, alpineVersion); v != "" {
+						req.Status = vex.StatusFixed
+						req.FixedVersion = v
+					} else {
+						req.Status = vex.StatusUnderInvestigation
+					}
+				}
+
+				reqs = append(reqs, req)
+			}
+		}
+	}
+
+	sort.SliceStable(reqs, func(i, j int) bool {
+		if reqs[i].Package != reqs[j].Package {
+			return reqs[i].Package < reqs[j].Package
+		}
+		return reqs[i].Vulnerability < reqs[j].Vulnerability
+	})
+
+	if opts.DryRun {
+		return reqs, nil
+	}
+
+	for _, req := range reqs {
+		if err := Create(req, CreateOptions{AdvisoryCfgs: opts.AdvisoryCfgs}); err != nil {
+			return nil, err
+		}
+	}
+
+	return reqs, nil
+}
+
+// existingVulnerabilityIDs returns the IDs of the package's advisories and all
+// of their known aliases.
+func existingVulnerabilityIDs(cfgs *configs.Index[advisoryconfigs.Document], packageName string) map[string]struct{} {
+	ids := make(map[string]struct{})
+	for _, doc := range cfgs.Select().WhereName(packageName).Configurations() {
+		for id := range doc.Advisories {
+			ids[id] = struct{}{}
+			for _, alias := range doc.Aliases[id] {
+				ids[alias] = struct{}{}
+			}
+		}
+	}
+
+	return ids
+}
+
+// sortedSecfixesVersions returns the versions of the secfixes from earliest to
+// latest, with the "not affected" pseudo-version first.
+func sortedSecfixesVersions(secfixes secdb.Secfixes) []string {
+	versions := lo.Keys(secfixes)
+	sort.Slice(versions, func(i, j int) bool {
+		if versions[i] == secdb.NAK || versions[j] == secdb.NAK {
+			return versions[i] == secdb.NAK && versions[j] != secdb.NAK
+		}
+		return versionLess(versions[i], versions[j])
+	})
+
+	return versions
+}
+
+// secfixesVulnerabilityIDs extracts the vulnerability IDs from secfixes items,
+// which can list several IDs, and sometimes notes, separated by spaces.
+func secfixesVulnerabilityIDs(items []string) []string {
+	var ids []string
+	for _, item := range items {
+		for _, field := range strings.Fields(item) {
+			if isKnownVulnerabilityID(field) {
+				ids = append(ids, field)
+			}
+		}
+	}
+
+	return ids
+}
+
+// earliestVersionAtLeast returns the earliest of the versions that isn't older
+// than minimum, or an empty string if there's none.
+func earliestVersionAtLeast(versions []string, minimum string) string {
+	minVersion, err := apkversion.NewVersion(minimum)
+	if err != nil {
+		return ""
+	}
+
+	var earliest string
+	var earliestVersion apkversion.Version
+	for _, v := range versions {
+		parsed, err := apkversion.NewVersion(v)
+		if err != nil || parsed.LessThan(minVersion) {
+			continue
+		}
+		if earliest == "" || parsed.LessThan(earliestVersion) {
+			earliest, earliestVersion = v, parsed
+		}
+	}
+
+	return earliest
+}
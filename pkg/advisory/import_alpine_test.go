@@ -0,0 +1,79 @@
+package advisory
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/openvex/go-vex/pkg/vex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wolfi-dev/wolfictl/pkg/advisory/secdb"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
+)
+
+func TestImportAlpineSecDB(t *testing.T) {
+	const foo = `package:
+  name: foo
+
+advisories:
+  CVE-2023-0001:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: under_investigation
+
+aliases:
+  CVE-2023-0001:
+    - GHSA-2222-3333-4444
+`
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "foo.advisories.yaml"), []byte(foo), 0o644))
+
+	cfgs, err := advisoryconfigs.NewIndex(rwos.DirFS(dir))
+	require.NoError(t, err)
+
+	db := &secdb.Database{
+		Packages: []secdb.PackageEntry{
+			{Pkg: secdb.Package{Name: "foo", Secfixes: secdb.Secfixes{
+				"1.2.3-r2":  {"CVE-2023-0001", "GHSA-2222-3333-4444", "CVE-2023-0002 CVE-2023-0003"},
+				"1.1.0-r0":  {"CVE-2023-0003"},
+				"2.0.0-r0":  {"CVE-2023-0004"},
+				secdb.NAK:   {"CVE-2023-0005 (disputed)"},
+				"1.0.0-r10": {"XSA-123"},
+			}}},
+			{Pkg: secdb.Package{Name: "alpine-only", Secfixes: secdb.Secfixes{
+				"1.0.0-r0": {"CVE-2023-0006"},
+			}}},
+		},
+	}
+
+	now := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	opts := ImportAlpineSecDBOptions{
+		AdvisoryCfgs: cfgs,
+		DB:           db,
+		Packages:     []string{"foo", "bar"},
+		PublishedVersions: func(string) []string {
+			return []string{"1.2.3-r0", "1.2.3-r1", "1.2.4-r0", "1.2.5-r0", "1.0.0-r0"}
+		},
+		Now: now,
+	}
+
+	reqs, err := ImportAlpineSecDB(opts)
+	require.NoError(t, err)
+	assert.Equal(t, []Request{
+		{Package: "foo", Vulnerability: "CVE-2023-0002", Status: vex.StatusFixed, FixedVersion: "1.2.4-r0", Timestamp: now},
+		{Package: "foo", Vulnerability: "CVE-2023-0003", Status: vex.StatusFixed, FixedVersion: "1.2.3-r0", Timestamp: now},
+		{Package: "foo", Vulnerability: "CVE-2023-0004", Status: vex.StatusUnderInvestigation, Timestamp: now},
+		{Package: "foo", Vulnerability: "CVE-2023-0005", Status: vex.StatusNotAffected, Justification: vex.VulnerableCodeNotPresent, Impact: alpineNotAffectedImpact, Timestamp: now},
+	}, reqs)
+
+	latest := LatestForPackage(cfgs, "foo", "CVE-2023-0002")
+	require.NotNil(t, latest)
+	assert.Equal(t, "1.2.4-r0", latest.FixedVersion)
+
+	reqs, err = ImportAlpineSecDB(opts)
+	require.NoError(t, err)
+	assert.Empty(t, reqs, "importing again must not change anything")
+}
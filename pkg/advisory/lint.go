@@ -0,0 +1,181 @@
+package advisory
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	apkversion "github.com/knqyf263/go-apk-version"
+	"github.com/openvex/go-vex/pkg/vex"
+	"github.com/samber/lo"
+	"github.com/wolfi-dev/wolfictl/pkg/configs"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+)
+
+// LintSeverity is how serious a lint finding is.
+type LintSeverity string
+
+const (
+	LintError   LintSeverity = "error"
+	LintWarning LintSeverity = "warning"
+)
+
+// LintRule is a check applied to the advisories data set by Lint.
+type LintRule struct {
+	ID          string
+	Severity    LintSeverity
+	Description string
+
+	// check returns a message for each problem found with an advisory's events,
+	// which are sorted by timestamp.
+	check func(entries []advisoryconfigs.Entry) []string
+}
+
+// LintRuleUnknownPackage flags advisory documents for packages that aren't
+// defined in the distro. It's applied only when LintOptions.PackageNames is set.
+var LintRuleUnknownPackage = LintRule{
+	ID:          "unknown-package",
+	Severity:    LintError,
+	Description: "advisories must be for packages that exist in the distro",
+}
+
+// LintRules are the rules that Lint applies to each advisory.
+var LintRules = []LintRule{
+	{
+		ID:          "not-affected-justification",
+		Severity:    LintError,
+		Description: "not_affected events must include a justification",
+		check: func(entries []advisoryconfigs.Entry) []string {
+			var msgs []string
+			for _, e := range entries {
+				if e.Status == vex.StatusNotAffected && e.Justification == "" {
+					msgs = append(msgs, fmt.Sprintf("not_affected event at %s has no justification", e.Timestamp.Format(time.RFC3339)))
+				}
+			}
+			return msgs
+		},
+	},
+	{
+		ID:          "fixed-version-regression",
+		Severity:    LintError,
+		Description: "fixed events must not reference a version older than an earlier fixed event's version",
+		check: func(entries []advisoryconfigs.Entry) []string {
+			var msgs []string
+			var highest *apkversion.Version
+			var highestString string
+			for _, e := range entries {
+				if e.Status != vex.StatusFixed {
+					continue
+				}
+
+				v, err := apkversion.NewVersion(e.FixedVersion)
+				if err != nil {
+					// Unparseable versions are reported by validation.
+					continue
+				}
+
+				if highest != nil && v.LessThan(*highest) {
+					msgs = append(msgs, fmt.Sprintf("fixed event at %s references %s, which is older than the earlier fixed version %s", e.Timestamp.Format(time.RFC3339), e.FixedVersion, highestString))
+					continue
+				}
+
+				highest, highestString = &v, e.FixedVersion
+			}
+			return msgs
+		},
+	},
+	{
+		ID:          "unexplained-redetection",
+		Severity:    LintWarning,
+		Description: "a resolved advisory must only be reopened with an explanation, i.e. by an affected event with an action statement",
+		check: func(entries []advisoryconfigs.Entry) []string {
+			var msgs []string
+			var resolution *advisoryconfigs.Entry
+			for i, e := range entries {
+				switch e.Status {
+				case vex.StatusFixed, vex.StatusNotAffected:
+					resolution = &entries[i]
+
+				case vex.StatusUnderInvestigation:
+					// Unlike affected events, these have no statement to explain why
+					// the resolution no longer holds.
+					if resolution != nil {
+						msgs = append(msgs, fmt.Sprintf("under_investigation event at %s reopens the %s resolution of %s without explanation", e.Timestamp.Format(time.RFC3339), resolution.Status, resolution.Timestamp.Format(time.RFC3339)))
+					}
+					resolution = nil
+
+				case vex.StatusAffected:
+					resolution = nil
+				}
+			}
+			return msgs
+		},
+	},
+}
+
+// LintOptions configures the Lint operation.
+type LintOptions struct {
+	// AdvisoryCfgs is the Index of advisories to lint.
+	AdvisoryCfgs *configs.Index[advisoryconfigs.Document]
+
+	// PackageNames are the names of the packages defined in the distro. If nil,
+	// LintRuleUnknownPackage isn't applied.
+	PackageNames []string
+}
+
+// LintFinding is a problem found by Lint.
+type LintFinding struct {
+	Rule          string       `json:"rule"`
+	Severity      LintSeverity `json:"severity"`
+	Package       string       `json:"package"`
+	Vulnerability string       `json:"vulnerability,omitempty"`
+	Message       string       `json:"message"`
+}
+
+// Lint applies the LintRules to each advisory, and returns the findings sorted
+// by package, vulnerability, and rule.
+func Lint(opts LintOptions) []LintFinding {
+	var findings []LintFinding
+
+	for _, doc := range opts.AdvisoryCfgs.Select().Configurations() {
+		if opts.PackageNames != nil && !lo.Contains(opts.PackageNames, doc.Package.Name) {
+			findings = append(findings, LintFinding{
+				Rule:     LintRuleUnknownPackage.ID,
+				Severity: LintRuleUnknownPackage.Severity,
+				Package:  doc.Package.Name,
+				Message:  fmt.Sprintf("package %q is not defined in the distro", doc.Package.Name),
+			})
+		}
+
+		for vulnID, entries := range doc.Advisories {
+			sorted := append([]advisoryconfigs.Entry{}, entries...)
+			sort.SliceStable(sorted, func(i, j int) bool {
+				return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+			})
+
+			for _, rule := range LintRules {
+				for _, msg := range rule.check(sorted) {
+					findings = append(findings, LintFinding{
+						Rule:          rule.ID,
+						Severity:      rule.Severity,
+						Package:       doc.Package.Name,
+						Vulnerability: vulnID,
+						Message:       msg,
+					})
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Package != findings[j].Package {
+			return findings[i].Package < findings[j].Package
+		}
+		if findings[i].Vulnerability != findings[j].Vulnerability {
+			return findings[i].Vulnerability < findings[j].Vulnerability
+		}
+		return findings[i].Rule < findings[j].Rule
+	})
+
+	return findings
+}
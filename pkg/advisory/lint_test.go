@@ -0,0 +1,85 @@
+package advisory
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
+)
+
+func TestLint(t *testing.T) {
+	const foo = `package:
+  name: foo
+
+advisories:
+  CVE-2023-1:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: not_affected
+  CVE-2023-2:
+    - timestamp: 2023-05-03T00:00:00Z
+      status: fixed
+      fixed-version: 1.2.0-r0
+    - timestamp: 2023-05-01T00:00:00Z
+      status: fixed
+      fixed-version: 1.10.0-r0
+  CVE-2023-3:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: fixed
+      fixed-version: 1.2.0-r0
+    - timestamp: 2023-05-02T00:00:00Z
+      status: under_investigation
+  CVE-2023-4:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: fixed
+      fixed-version: 1.2.0-r0
+    - timestamp: 2023-05-02T00:00:00Z
+      status: affected
+      action: the fix was incomplete, waiting on upstream
+    - timestamp: 2023-05-03T00:00:00Z
+      status: fixed
+      fixed-version: 1.2.0-r1
+`
+	const bar = `package:
+  name: bar
+
+advisories:
+  CVE-2023-1:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: under_investigation
+`
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "foo.advisories.yaml"), []byte(foo), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bar.advisories.yaml"), []byte(bar), 0o644))
+
+	cfgs, err := advisoryconfigs.NewIndex(rwos.DirFS(dir))
+	require.NoError(t, err)
+
+	findings := Lint(LintOptions{AdvisoryCfgs: cfgs, PackageNames: []string{"foo"}})
+
+	assert.Equal(t, []string{
+		"bar: unknown-package",
+		"foo CVE-2023-1: not-affected-justification",
+		"foo CVE-2023-2: fixed-version-regression",
+		"foo CVE-2023-3: unexplained-redetection",
+	}, summarizeLintFindings(findings))
+
+	findings = Lint(LintOptions{AdvisoryCfgs: cfgs})
+	assert.NotContains(t, summarizeLintFindings(findings), "bar: unknown-package", "package existence isn't checked without package names")
+}
+
+func summarizeLintFindings(findings []LintFinding) []string {
+	summaries := make([]string, 0, len(findings))
+	for _, f := range findings {
+		if f.Vulnerability == "" {
+			summaries = append(summaries, f.Package+": "+f.Rule)
+			continue
+		}
+		summaries = append(summaries, f.Package+" "+f.Vulnerability+": "+f.Rule)
+	}
+	return summaries
+}
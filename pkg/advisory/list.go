@@ -0,0 +1,122 @@
+package advisory
+
+import (
+	"sort"
+	"time"
+
+	"github.com/openvex/go-vex/pkg/vex"
+	"github.com/samber/lo"
+	"github.com/wolfi-dev/wolfictl/pkg/configs"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+)
+
+// ListFilter selects advisories by their package, vulnerability, and latest
+// event. Zero-valued fields don't filter anything out.
+type ListFilter struct {
+	Package string
+
+	// Packages are the packages an advisory must be in (any of them), e.g. the
+	// version streams of a project.
+	Packages []string
+
+	// Vulnerabilities are the IDs an advisory must be recorded under (any of
+	// them), e.g. a CVE ID and its aliases.
+	Vulnerabilities []string
+
+	// Statuses are the statuses an advisory's latest event must have (any of
+	// them).
+	Statuses []vex.Status
+
+	// Since and Until bound the timestamp of an advisory's latest event.
+	Since, Until time.Time
+
+	// Unresolved selects advisories whose latest status is affected or
+	// under_investigation.
+	Unresolved bool
+
+	// Version, if set, is an exact package version at which each advisory's
+	// latest event is evaluated (see AtVersion), before the other filters are
+	// applied to it.
+	Version string
+}
+
+// ListedAdvisory is an advisory selected by List.
+type ListedAdvisory struct {
+	Package       string
+	Vulnerability string
+	Entries       []advisoryconfigs.Entry
+	Latest        advisoryconfigs.Entry
+}
+
+// List returns the advisories that match the filter, sorted by package and then
+// by vulnerability. Each advisory's entries are sorted chronologically.
+func List(cfgs *configs.Index[advisoryconfigs.Document], filter ListFilter) []ListedAdvisory {
+	sel := cfgs.Select()
+	if filter.Package != "" {
+		sel = sel.WhereName(filter.Package)
+	}
+
+	var listed []ListedAdvisory
+	for _, doc := range sel.Configurations() {
+		if len(filter.Packages) > 0 && !lo.Contains(filter.Packages, doc.Package.Name) {
+			continue
+		}
+
+		for vuln, entries := range doc.Advisories {
+			if len(entries) == 0 {
+				continue
+			}
+
+			if len(filter.Vulnerabilities) > 0 && !lo.Contains(filter.Vulnerabilities, vuln) {
+				continue
+			}
+
+			latest := AtVersion(*Latest(entries), filter.Version)
+			if !filter.matchesLatest(latest) {
+				continue
+			}
+
+			sorted := make([]advisoryconfigs.Entry, len(entries))
+			copy(sorted, entries)
+			sort.SliceStable(sorted, func(i, j int) bool {
+				return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+			})
+
+			listed = append(listed, ListedAdvisory{
+				Package:       doc.Package.Name,
+				Vulnerability: vuln,
+				Entries:       sorted,
+				Latest:        latest,
+			})
+		}
+	}
+
+	sort.Slice(listed, func(i, j int) bool {
+		if listed[i].Package != listed[j].Package {
+			return listed[i].Package < listed[j].Package
+		}
+		return listed[i].Vulnerability < listed[j].Vulnerability
+	})
+
+	return listed
+}
+
+func (f ListFilter) matchesLatest(latest advisoryconfigs.Entry) bool {
+	if len(f.Statuses) > 0 && !lo.Contains(f.Statuses, latest.Status) {
+		return false
+	}
+
+	if f.Unresolved && latest.Status != vex.StatusAffected && latest.Status != vex.StatusUnderInvestigation {
+		return false
+	}
+
+	if !f.Since.IsZero() && latest.Timestamp.Before(f.Since) {
+		return false
+	}
+
+	if !f.Until.IsZero() && latest.Timestamp.After(f.Until) {
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,73 @@
+package advisory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openvex/go-vex/pkg/vex"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
+)
+
+func TestList(t *testing.T) {
+	cfgs, err := advisoryconfigs.NewIndex(rwos.DirFS("./testdata/export/advisories"))
+	require.NoError(t, err)
+
+	ids := func(listed []ListedAdvisory) []string {
+		return lo.Map(listed, func(a ListedAdvisory, _ int) string {
+			return a.Package + ":" + a.Vulnerability
+		})
+	}
+
+	cases := []struct {
+		name   string
+		filter ListFilter
+		want   []string
+	}{
+		{
+			name:   "by vulnerability and aliases",
+			filter: ListFilter{Vulnerabilities: []string{"CVE-2020-8927", "GHSA-hw7c-3rfg-p46j"}},
+			want:   []string{"brotli:CVE-2020-8927", "ko:GHSA-hw7c-3rfg-p46j"},
+		},
+		{
+			name:   "by status",
+			filter: ListFilter{Package: "openssl", Statuses: []vex.Status{vex.StatusNotAffected}},
+			want:   []string{"openssl:CVE-2023-0466"},
+		},
+		{
+			name: "by time range",
+			filter: ListFilter{
+				Package: "openssl",
+				Since:   time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC),
+				Until:   time.Date(2023, 4, 1, 0, 0, 0, 0, time.UTC),
+			},
+			want: []string{"openssl:CVE-2023-0464", "openssl:CVE-2023-0465"},
+		},
+		{
+			name:   "unresolved",
+			filter: ListFilter{Unresolved: true},
+			want:   []string{},
+		},
+		{
+			name:   "unresolved at a version before the fix",
+			filter: ListFilter{Package: "brotli", Version: "1.0.8-r0", Unresolved: true},
+			want:   []string{"brotli:CVE-2020-8927"},
+		},
+		{
+			name:   "unresolved at the fixed version",
+			filter: ListFilter{Package: "brotli", Version: "1.0.9-r0", Unresolved: true},
+			want:   []string{},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ids(List(cfgs, tt.filter)))
+		})
+	}
+
+	assert.Len(t, List(cfgs, ListFilter{}), 22)
+}
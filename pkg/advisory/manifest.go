@@ -0,0 +1,197 @@
+package advisory
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// ExportManifestSuffix is appended to the path of an exported file to get the
+	// path of its manifest.
+	ExportManifestSuffix = ".manifest.json"
+
+	// ExportBundleSuffix is appended to the path of a manifest to get the path of
+	// the Sigstore bundle holding its signature, signing certificate, and
+	// transparency log entry.
+	ExportBundleSuffix = ".bundle"
+)
+
+// cosignCommand is the cosign executable used to sign and verify manifests, and
+// to attest VEX documents.
+var cosignCommand = "cosign"
+
+// ExportManifest lists the digests of the files of an advisory data export
+// (such as a security database or a VEX document), so that the files can be
+// verified once the manifest is signed.
+type ExportManifest struct {
+	Files []ManifestFile `json:"files"`
+}
+
+// ManifestFile is a file listed in an ExportManifest.
+type ManifestFile struct {
+	// Name is the file's base name.
+	Name string `json:"name"`
+
+	// SHA256 is the hex-encoded SHA-256 digest of the file's contents.
+	SHA256 string `json:"sha256"`
+}
+
+// NewExportManifest returns a manifest of the files at the given paths.
+func NewExportManifest(paths ...string) (*ExportManifest, error) {
+	m := &ExportManifest{}
+	for _, p := range paths {
+		digest, err := sha256File(p)
+		if err != nil {
+			return nil, err
+		}
+
+		m.Files = append(m.Files, ManifestFile{
+			Name:   filepath.Base(p),
+			SHA256: digest,
+		})
+	}
+
+	return m, nil
+}
+
+// ReadExportManifest reads the manifest at the given path.
+func ReadExportManifest(path string) (*ExportManifest, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read export manifest: %w", err)
+	}
+
+	m := &ExportManifest{}
+	if err := json.Unmarshal(b, m); err != nil {
+		return nil, fmt.Errorf("unable to parse export manifest %q: %w", path, err)
+	}
+
+	return m, nil
+}
+
+// Write writes the manifest to the given path.
+func (m *ExportManifest) Write(path string) error {
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to encode export manifest: %w", err)
+	}
+
+	if err := os.WriteFile(path, append(b, '\n'), 0o644); err != nil { //nolint:gosec
+		return fmt.Errorf("unable to write export manifest: %w", err)
+	}
+
+	return nil
+}
+
+// VerifyFile returns an error if the file at the given path isn't listed in the
+// manifest, or its contents don't match the listed digest.
+func (m *ExportManifest) VerifyFile(path string) error {
+	name := filepath.Base(path)
+	for _, f := range m.Files {
+		if f.Name != name {
+			continue
+		}
+
+		digest, err := sha256File(path)
+		if err != nil {
+			return err
+		}
+		if digest != f.SHA256 {
+			return fmt.Errorf("%s has been modified: its digest is sha256:%s, but the manifest lists sha256:%s", name, digest, f.SHA256)
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("%s is not listed in the manifest", name)
+}
+
+// SigningOptions configures how a manifest is signed.
+type SigningOptions struct {
+	// Key is the path (or KMS URI) of a cosign private key to sign with. If it's
+	// empty, keyless signing is used instead.
+	Key string
+}
+
+// SignExportManifest signs the manifest at the given path with cosign. Unless a
+// key is given, keyless signing is used, which records the signature in the
+// Rekor transparency log. The signature, certificate, and log entry are written
+// to a Sigstore bundle next to the manifest, whose path is returned.
+func SignExportManifest(manifestPath string, opts SigningOptions) (string, error) {
+	bundlePath := manifestPath + ExportBundleSuffix
+
+	args := []string{"sign-blob", "--yes", "--bundle", bundlePath}
+	if opts.Key != "" {
+		args = append(args, "--key", opts.Key)
+	}
+	args = append(args, manifestPath)
+
+	cmd := exec.Command(cosignCommand, args...) //nolint:gosec
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("unable to sign export manifest: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+
+	return bundlePath, nil
+}
+
+// VerificationOptions configures how a manifest's signature is verified.
+type VerificationOptions struct {
+	// Key is the path (or KMS URI) of the cosign public key the manifest must have
+	// been signed with. If it's empty, the manifest must have been signed keyless
+	// by the given identity instead.
+	Key string
+
+	// CertificateIdentity is the identity a keyless signature's certificate must
+	// have been issued to.
+	CertificateIdentity string
+
+	// CertificateOIDCIssuer is the OIDC issuer that must have issued the
+	// identity of a keyless signature's certificate.
+	CertificateOIDCIssuer string
+}
+
+// VerifyExportManifest verifies the manifest at the given path against its
+// Sigstore bundle with cosign: the signature must be valid, and made either
+// with the given key or with a certificate issued to the given identity by the
+// given OIDC issuer, and recorded in the Rekor transparency log.
+func VerifyExportManifest(manifestPath, bundlePath string, opts VerificationOptions) error {
+	args := []string{"verify-blob", "--bundle", bundlePath}
+	if opts.Key != "" {
+		args = append(args, "--key", opts.Key)
+	} else {
+		args = append(args,
+			"--certificate-identity", opts.CertificateIdentity,
+			"--certificate-oidc-issuer", opts.CertificateOIDCIssuer,
+		)
+	}
+	args = append(args, manifestPath)
+
+	cmd := exec.Command(cosignCommand, args...) //nolint:gosec
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("unable to verify export manifest signature: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+
+	return nil
+}
+
+func sha256File(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("unable to open %q: %w", path, err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("unable to read %q: %w", path, err)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
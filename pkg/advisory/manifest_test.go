@@ -0,0 +1,36 @@
+package advisory
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportManifest(t *testing.T) {
+	dir := t.TempDir()
+	exportPath := filepath.Join(dir, "security.json")
+	require.NoError(t, os.WriteFile(exportPath, []byte(`{"packages":[]}`), 0o644))
+
+	m, err := NewExportManifest(exportPath)
+	require.NoError(t, err)
+
+	manifestPath := exportPath + ExportManifestSuffix
+	require.NoError(t, m.Write(manifestPath))
+
+	m, err = ReadExportManifest(manifestPath)
+	require.NoError(t, err)
+	assert.Equal(t, []ManifestFile{{
+		Name:   "security.json",
+		SHA256: "454eea274ef715bed0aef364fec823e04318850d28eb04f1c16a03a5127c071c",
+	}}, m.Files)
+
+	assert.NoError(t, m.VerifyFile(exportPath))
+
+	require.NoError(t, os.WriteFile(exportPath, []byte(`{"packages":[{}]}`), 0o644))
+	assert.ErrorContains(t, m.VerifyFile(exportPath), "security.json has been modified")
+
+	assert.EqualError(t, m.VerifyFile(filepath.Join(dir, "other.json")), "other.json is not listed in the manifest")
+}
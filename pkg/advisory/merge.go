@@ -0,0 +1,116 @@
+package advisory
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/samber/lo"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+)
+
+// Merge performs a three-way merge of two edited versions ("ours" and
+// "theirs") of the same advisory document, given the version they were both
+// edited from ("base"). Advisory events are merged as sets: the result has
+// every event added on either side, minus any event from base that either side
+// removed, sorted by timestamp. Aliases are merged the same way. It returns an
+// error for changes that can't be merged automatically, e.g. when the two sides
+// archived the package differently.
+func Merge(base, ours, theirs advisoryconfigs.Document) (advisoryconfigs.Document, error) {
+	if ours.Package.Name != theirs.Package.Name {
+		return advisoryconfigs.Document{}, fmt.Errorf("package names differ: %q and %q", ours.Package.Name, theirs.Package.Name)
+	}
+
+	merged := advisoryconfigs.Document{
+		Package: ours.Package,
+	}
+
+	var err error
+	merged.SchemaVersion, err = mergeValue(base.SchemaVersion, ours.SchemaVersion, theirs.SchemaVersion, "schema version")
+	if err != nil {
+		return advisoryconfigs.Document{}, err
+	}
+
+	merged.Archived, err = mergeValue(base.Archived, ours.Archived, theirs.Archived, "archived status")
+	if err != nil {
+		return advisoryconfigs.Document{}, err
+	}
+
+	vulnIDs := lo.Uniq(append(lo.Keys(ours.Advisories), lo.Keys(theirs.Advisories)...))
+	sort.Strings(vulnIDs)
+	for _, id := range vulnIDs {
+		entries := mergeSets(base.Advisories[id], ours.Advisories[id], theirs.Advisories[id])
+		if len(entries) == 0 {
+			continue
+		}
+
+		sort.SliceStable(entries, func(i, j int) bool {
+			return entries[i].Timestamp.Before(entries[j].Timestamp)
+		})
+
+		if merged.Advisories == nil {
+			merged.Advisories = make(advisoryconfigs.Advisories)
+		}
+		merged.Advisories[id] = entries
+	}
+
+	aliasIDs := lo.Uniq(append(lo.Keys(ours.Aliases), lo.Keys(theirs.Aliases)...))
+	for _, id := range aliasIDs {
+		aliases := mergeSets(base.Aliases[id], ours.Aliases[id], theirs.Aliases[id])
+		if len(aliases) == 0 {
+			continue
+		}
+
+		sort.Strings(aliases)
+
+		if merged.Aliases == nil {
+			merged.Aliases = make(advisoryconfigs.Aliases)
+		}
+		merged.Aliases[id] = aliases
+	}
+
+	return merged, nil
+}
+
+// mergeSets returns the items of ours and theirs (without duplicates), except
+// those items of base that are missing from either ours or theirs.
+func mergeSets[T any](base, ours, theirs []T) []T {
+	contains := func(items []T, item T) bool {
+		return lo.ContainsBy(items, func(other T) bool {
+			return reflect.DeepEqual(item, other)
+		})
+	}
+
+	var merged []T
+	for _, item := range append(append([]T{}, ours...), theirs...) {
+		if contains(merged, item) {
+			continue
+		}
+
+		if contains(base, item) && !(contains(ours, item) && contains(theirs, item)) {
+			// removed on one side
+			continue
+		}
+
+		merged = append(merged, item)
+	}
+
+	return merged
+}
+
+// mergeValue returns the value that changed from base, if only one side changed
+// it, or the common value if both sides agree. It returns an error if both
+// sides changed it differently.
+func mergeValue[T any](base, ours, theirs T, what string) (T, error) {
+	switch {
+	case reflect.DeepEqual(ours, theirs):
+		return ours, nil
+	case reflect.DeepEqual(base, ours):
+		return theirs, nil
+	case reflect.DeepEqual(base, theirs):
+		return ours, nil
+	}
+
+	var zero T
+	return zero, fmt.Errorf("conflicting changes to the %s", what)
+}
@@ -0,0 +1,74 @@
+package advisory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openvex/go-vex/pkg/vex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+)
+
+func TestMerge(t *testing.T) {
+	at := func(day int) time.Time {
+		return time.Date(2023, 5, day, 0, 0, 0, 0, time.UTC)
+	}
+	investigating := advisoryconfigs.Entry{Timestamp: at(1), Status: vex.StatusUnderInvestigation}
+	fixed := advisoryconfigs.Entry{Timestamp: at(3), Status: vex.StatusFixed, FixedVersion: "1.2.3-r1"}
+	notAffected := advisoryconfigs.Entry{Timestamp: at(2), Status: vex.StatusNotAffected, Justification: vex.ComponentNotPresent}
+	typo := advisoryconfigs.Entry{Timestamp: at(1), Status: vex.StatusFixed, FixedVersion: "1.2.3-r"}
+	typoFixed := advisoryconfigs.Entry{Timestamp: at(1), Status: vex.StatusFixed, FixedVersion: "1.2.3-r0"}
+
+	doc := func(advisories advisoryconfigs.Advisories, aliases advisoryconfigs.Aliases) advisoryconfigs.Document {
+		return advisoryconfigs.Document{
+			Package:    advisoryconfigs.Package{Name: "foo"},
+			Advisories: advisories,
+			Aliases:    aliases,
+		}
+	}
+
+	base := doc(advisoryconfigs.Advisories{
+		"CVE-2023-1": {investigating},
+		"CVE-2023-2": {typo},
+		"CVE-2023-3": {investigating},
+	}, nil)
+
+	ours := doc(advisoryconfigs.Advisories{
+		"CVE-2023-1": {investigating, fixed},
+		"CVE-2023-2": {typoFixed},
+		"CVE-2023-3": {investigating},
+	}, advisoryconfigs.Aliases{"CVE-2023-1": {"GHSA-2"}})
+
+	theirs := doc(advisoryconfigs.Advisories{
+		"CVE-2023-1": {investigating, notAffected},
+		"CVE-2023-2": {typo},
+		"CVE-2023-4": {investigating},
+	}, advisoryconfigs.Aliases{"CVE-2023-1": {"GHSA-1"}})
+
+	merged, err := Merge(base, ours, theirs)
+	require.NoError(t, err)
+
+	assert.Equal(t, doc(advisoryconfigs.Advisories{
+		"CVE-2023-1": {investigating, notAffected, fixed},
+		"CVE-2023-2": {typoFixed},
+		"CVE-2023-4": {investigating},
+	}, advisoryconfigs.Aliases{"CVE-2023-1": {"GHSA-1", "GHSA-2"}}), merged)
+}
+
+func TestMerge_conflicts(t *testing.T) {
+	base := advisoryconfigs.Document{Package: advisoryconfigs.Package{Name: "foo"}}
+
+	ours := base
+	ours.Archived = &advisoryconfigs.Archived{Reason: "replaced by foo-2"}
+	theirs := base
+	theirs.Archived = &advisoryconfigs.Archived{Reason: "no longer maintained"}
+
+	_, err := Merge(base, ours, theirs)
+	assert.ErrorContains(t, err, "conflicting changes to the archived status")
+
+	theirs = base
+	theirs.Package.Name = "bar"
+	_, err = Merge(base, ours, theirs)
+	assert.Error(t, err)
+}
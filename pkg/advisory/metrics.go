@@ -0,0 +1,165 @@
+package advisory
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/openvex/go-vex/pkg/vex"
+	"github.com/wolfi-dev/wolfictl/pkg/configs"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	"github.com/wolfi-dev/wolfictl/pkg/scan"
+)
+
+// MetricsOptions configures the ComputeMetrics operation.
+type MetricsOptions struct {
+	// AdvisoryCfgs is the Index of advisories to compute metrics for.
+	AdvisoryCfgs *configs.Index[advisoryconfigs.Document]
+
+	// Severities maps vulnerability IDs to their severities (see
+	// StatsOptions.Severities).
+	Severities map[string]string
+
+	// Policy is the SLA policy to check unresolved advisories against.
+	Policy SLAPolicy
+
+	// Now is the time against which unresolved advisories are measured.
+	Now time.Time
+}
+
+// Metrics are gauges and counters of the health of advisory triage, for
+// exposing to Prometheus.
+type Metrics struct {
+	// CountsByStatus maps each status to the number of advisories whose latest
+	// event has that status.
+	CountsByStatus map[vex.Status]int
+
+	// OpenInvestigations maps each severity (see scan.Severities) to the number
+	// of advisories still under investigation.
+	OpenInvestigations map[string]int
+
+	// Fixed is the number of advisories that are fixed, and FixedLastDay the
+	// number of them first marked fixed in the day before Now. Graphing the rate
+	// of Fixed gives advisories fixed per day over any period.
+	Fixed        int
+	FixedLastDay int
+
+	// SLABreaches maps each severity with an SLA to the number of unresolved
+	// advisories past due.
+	SLABreaches map[string]int
+
+	// Computed is when the metrics were computed.
+	Computed time.Time
+}
+
+// ComputeMetrics computes the triage health metrics of the advisories. Only
+// public advisory data is considered.
+func ComputeMetrics(opts MetricsOptions) Metrics {
+	m := Metrics{
+		CountsByStatus:     make(map[vex.Status]int),
+		OpenInvestigations: make(map[string]int),
+		SLABreaches:        make(map[string]int),
+		Computed:           opts.Now,
+	}
+
+	for _, status := range vex.Statuses() {
+		m.CountsByStatus[vex.Status(status)] = 0
+	}
+	for _, sev := range scan.Severities {
+		m.OpenInvestigations[sev] = 0
+	}
+	for sev := range opts.Policy {
+		m.SLABreaches[sev] = 0
+	}
+
+	for _, a := range List(opts.AdvisoryCfgs, ListFilter{}) {
+		entries := PublicEntries(a.Entries)
+		if len(entries) == 0 {
+			continue
+		}
+		latest := entries[len(entries)-1]
+		m.CountsByStatus[latest.Status]++
+
+		sev := opts.Severities[a.Vulnerability]
+		if _, ok := m.OpenInvestigations[sev]; !ok {
+			sev = "Unknown"
+		}
+
+		switch latest.Status {
+		case vex.StatusFixed:
+			m.Fixed++
+			for _, e := range entries {
+				if e.Status == vex.StatusFixed {
+					if age := opts.Now.Sub(e.Timestamp); age >= 0 && age < 24*time.Hour {
+						m.FixedLastDay++
+					}
+					break
+				}
+			}
+
+		case vex.StatusUnderInvestigation:
+			m.OpenInvestigations[sev]++
+		}
+	}
+
+	queue := SLAQueue(SLAQueueOptions{
+		AdvisoryCfgs: opts.AdvisoryCfgs,
+		Severities:   opts.Severities,
+		Policy:       opts.Policy,
+		Now:          opts.Now,
+	})
+	for _, item := range queue {
+		if item.Breached {
+			m.SLABreaches[item.Severity]++
+		}
+	}
+
+	return m
+}
+
+// WriteTo writes the metrics in the Prometheus text exposition format.
+func (m Metrics) WriteTo(w io.Writer) (int64, error) {
+	var b bytes.Buffer
+
+	writeMetricHeader(&b, "wolfictl_advisories", "gauge", "Number of advisories by the status of their latest event.")
+	for _, status := range sortedKeys(m.CountsByStatus) {
+		fmt.Fprintf(&b, "wolfictl_advisories{status=%q} %d\n", status, m.CountsByStatus[vex.Status(status)])
+	}
+
+	writeMetricHeader(&b, "wolfictl_advisory_open_investigations", "gauge", "Number of advisories under investigation by vulnerability severity.")
+	for _, sev := range sortedKeys(m.OpenInvestigations) {
+		fmt.Fprintf(&b, "wolfictl_advisory_open_investigations{severity=%q} %d\n", sev, m.OpenInvestigations[sev])
+	}
+
+	writeMetricHeader(&b, "wolfictl_advisories_fixed_total", "counter", "Number of advisories that are fixed.")
+	fmt.Fprintf(&b, "wolfictl_advisories_fixed_total %d\n", m.Fixed)
+
+	writeMetricHeader(&b, "wolfictl_advisories_fixed_last_day", "gauge", "Number of advisories first marked fixed in the last 24 hours.")
+	fmt.Fprintf(&b, "wolfictl_advisories_fixed_last_day %d\n", m.FixedLastDay)
+
+	writeMetricHeader(&b, "wolfictl_advisory_sla_breaches", "gauge", "Number of unresolved advisories past their SLA by vulnerability severity.")
+	for _, sev := range sortedKeys(m.SLABreaches) {
+		fmt.Fprintf(&b, "wolfictl_advisory_sla_breaches{severity=%q} %d\n", sev, m.SLABreaches[sev])
+	}
+
+	writeMetricHeader(&b, "wolfictl_advisory_metrics_computed_timestamp_seconds", "gauge", "When the advisory metrics were last computed.")
+	fmt.Fprintf(&b, "wolfictl_advisory_metrics_computed_timestamp_seconds %d\n", m.Computed.Unix())
+
+	return b.WriteTo(w)
+}
+
+func writeMetricHeader(b *bytes.Buffer, name, typ, help string) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+func sortedKeys[K ~string, V any](m map[K]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, string(k))
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,80 @@
+package advisory
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/openvex/go-vex/pkg/vex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
+)
+
+func TestComputeMetrics(t *testing.T) {
+	const doc = `package:
+  name: foo
+
+advisories:
+  CVE-2023-1:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: under_investigation
+  CVE-2023-2:
+    - timestamp: 2023-05-30T00:00:00Z
+      status: under_investigation
+  CVE-2023-3:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: under_investigation
+    - timestamp: 2023-05-31T12:00:00Z
+      status: fixed
+      fixed-version: 1.2.3-r1
+  CVE-2023-4:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: fixed
+      fixed-version: 1.2.3-r1
+  CVE-2023-5:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: affected
+      action: wait for upstream
+`
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "foo.advisories.yaml"), []byte(doc), 0o644))
+
+	cfgs, err := advisoryconfigs.NewIndex(rwos.DirFS(dir))
+	require.NoError(t, err)
+
+	now := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	m := ComputeMetrics(MetricsOptions{
+		AdvisoryCfgs: cfgs,
+		Severities: map[string]string{
+			"CVE-2023-1": "Critical",
+			"CVE-2023-2": "Critical",
+			"CVE-2023-5": "High",
+		},
+		Policy: SLAPolicy{"Critical": 7 * 24 * time.Hour, "High": 14 * 24 * time.Hour},
+		Now:    now,
+	})
+
+	assert.Equal(t, 2, m.CountsByStatus[vex.StatusUnderInvestigation])
+	assert.Equal(t, 2, m.CountsByStatus[vex.StatusFixed])
+	assert.Equal(t, 1, m.CountsByStatus[vex.StatusAffected])
+	assert.Equal(t, 0, m.CountsByStatus[vex.StatusNotAffected])
+	assert.Equal(t, 2, m.OpenInvestigations["Critical"])
+	assert.Equal(t, 0, m.OpenInvestigations["Low"])
+	assert.Equal(t, 2, m.Fixed)
+	assert.Equal(t, 1, m.FixedLastDay)
+	assert.Equal(t, map[string]int{"Critical": 1, "High": 1}, m.SLABreaches)
+
+	var buf bytes.Buffer
+	_, err = m.WriteTo(&buf)
+	require.NoError(t, err)
+	out := buf.String()
+	assert.Contains(t, out, "# TYPE wolfictl_advisories_fixed_total counter\nwolfictl_advisories_fixed_total 2\n")
+	assert.Contains(t, out, `wolfictl_advisory_open_investigations{severity="Critical"} 2`)
+	assert.Contains(t, out, `wolfictl_advisory_sla_breaches{severity="High"} 1`)
+	assert.Contains(t, out, `wolfictl_advisories{status="affected"} 1`)
+}
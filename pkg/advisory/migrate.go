@@ -0,0 +1,143 @@
+package advisory
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	"github.com/wolfi-dev/wolfictl/pkg/yamledit"
+	"gopkg.in/yaml.v3"
+)
+
+// migration upgrades an advisory document from one schema version to the next.
+type migration struct {
+	from, to string
+	migrate  func(doc *yamledit.Document) error
+}
+
+// migrations are the advisory document migrations, in order. Each migration's
+// "to" version must be the next one's "from" version, and the last one's "to"
+// version must be advisoryconfigs.SchemaVersion.
+var migrations = []migration{
+	{
+		// Documents written before schema versioning was introduced are otherwise
+		// identical to version 1.
+		from:    "",
+		to:      "1",
+		migrate: func(*yamledit.Document) error { return nil },
+	},
+}
+
+// MigrateOptions configures the Migrate operation.
+type MigrateOptions struct {
+	// AdvisoriesDir is the advisories repository directory. The advisory
+	// documents at its top level and in its ArchiveDir are migrated.
+	AdvisoriesDir string
+
+	// DryRun reports the documents that need migrating without changing them.
+	DryRun bool
+}
+
+// MigratedDocument is an advisory document that was (or, for a dry run, would
+// be) migrated.
+type MigratedDocument struct {
+	Path string
+	From string
+	To   string
+}
+
+// Migrate upgrades each advisory document whose schema version is older than
+// advisoryconfigs.SchemaVersion to the current version, in place. Documents
+// that are already current are left untouched, so running Migrate again makes
+// no further changes. Documents are edited in place to keep their comments and
+// formatting.
+func Migrate(opts MigrateOptions) ([]MigratedDocument, error) {
+	var paths []string
+	for _, dir := range []string{opts.AdvisoriesDir, filepath.Join(opts.AdvisoriesDir, ArchiveDir)} {
+		matches, err := filepath.Glob(filepath.Join(dir, "*.advisories.yaml"))
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, matches...)
+	}
+	sort.Strings(paths)
+
+	var migrated []MigratedDocument
+	for _, path := range paths {
+		doc, err := yamledit.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		from := ""
+		if node, ok := doc.Get("schema-version"); ok {
+			from = node.Value
+		}
+
+		if from == advisoryconfigs.SchemaVersion {
+			continue
+		}
+
+		if err := migrateDocument(doc, from); err != nil {
+			return nil, fmt.Errorf("unable to migrate %s: %w", path, err)
+		}
+
+		migrated = append(migrated, MigratedDocument{
+			Path: path,
+			From: from,
+			To:   advisoryconfigs.SchemaVersion,
+		})
+
+		if opts.DryRun {
+			continue
+		}
+
+		if err := doc.WriteFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	return migrated, nil
+}
+
+// migrateDocument applies the migrations needed to bring a document from the
+// given schema version up to date, and records the new version.
+func migrateDocument(doc *yamledit.Document, from string) error {
+	start := -1
+	for i, m := range migrations {
+		if m.from == from {
+			start = i
+			break
+		}
+	}
+	if start == -1 {
+		return fmt.Errorf("unsupported schema version %q (the latest supported version is %q)", from, advisoryconfigs.SchemaVersion)
+	}
+
+	for _, m := range migrations[start:] {
+		if err := m.migrate(doc); err != nil {
+			return fmt.Errorf("migrating from schema version %q to %q: %w", m.from, m.to, err)
+		}
+	}
+
+	setSchemaVersion(doc, advisoryconfigs.SchemaVersion)
+	return nil
+}
+
+// setSchemaVersion sets the document's schema version, adding it as the
+// document's first key if it's not there yet.
+func setSchemaVersion(doc *yamledit.Document, version string) {
+	if node, ok := doc.Get("schema-version"); ok {
+		node.Value = version
+		node.Tag = "!!str"
+		node.Style = yaml.DoubleQuotedStyle
+		return
+	}
+
+	root := doc.Root().Content[0]
+	root.Content = append([]*yaml.Node{
+		{Kind: yaml.ScalarNode, Tag: "!!str", Value: "schema-version"},
+		{Kind: yaml.ScalarNode, Tag: "!!str", Value: version, Style: yaml.DoubleQuotedStyle},
+	}, root.Content...)
+}
@@ -0,0 +1,75 @@
+package advisory
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+)
+
+func TestMigrate(t *testing.T) {
+	const unversioned = `# comment to keep
+package:
+  name: foo
+
+advisories:
+  CVE-2023-1:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: under_investigation
+`
+	const current = `schema-version: "1"
+package:
+  name: bar
+`
+
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ArchiveDir), 0o755))
+	fooPath := filepath.Join(dir, "foo.advisories.yaml")
+	barPath := filepath.Join(dir, "bar.advisories.yaml")
+	archivedPath := filepath.Join(dir, ArchiveDir, "baz.advisories.yaml")
+	require.NoError(t, os.WriteFile(fooPath, []byte(unversioned), 0o644))
+	require.NoError(t, os.WriteFile(barPath, []byte(current), 0o644))
+	require.NoError(t, os.WriteFile(archivedPath, []byte(unversioned), 0o644))
+
+	migrated, err := Migrate(MigrateOptions{AdvisoriesDir: dir, DryRun: true})
+	require.NoError(t, err)
+	assert.Equal(t, []MigratedDocument{
+		{Path: archivedPath, From: "", To: advisoryconfigs.SchemaVersion},
+		{Path: fooPath, From: "", To: advisoryconfigs.SchemaVersion},
+	}, migrated)
+
+	b, err := os.ReadFile(fooPath)
+	require.NoError(t, err)
+	assert.Equal(t, unversioned, string(b), "dry run must not change documents")
+
+	migrated, err = Migrate(MigrateOptions{AdvisoriesDir: dir})
+	require.NoError(t, err)
+	assert.Len(t, migrated, 2)
+
+	f, err := os.Open(fooPath)
+	require.NoError(t, err)
+	defer f.Close()
+	doc, err := advisoryconfigs.DecodeDocument(f)
+	require.NoError(t, err)
+	assert.Equal(t, advisoryconfigs.SchemaVersion, doc.SchemaVersion)
+	assert.Len(t, doc.Advisories["CVE-2023-1"], 1)
+
+	b, err = os.ReadFile(fooPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), "# comment to keep")
+
+	migrated, err = Migrate(MigrateOptions{AdvisoriesDir: dir})
+	require.NoError(t, err)
+	assert.Empty(t, migrated, "migrating again must not change anything")
+}
+
+func TestMigrate_unsupportedVersion(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "foo.advisories.yaml"), []byte("schema-version: \"99\"\npackage:\n  name: foo\n"), 0o644))
+
+	_, err := Migrate(MigrateOptions{AdvisoriesDir: dir})
+	assert.ErrorContains(t, err, `unsupported schema version "99"`)
+}
@@ -0,0 +1,178 @@
+package advisory
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openvex/go-vex/pkg/vex"
+	"github.com/wolfi-dev/wolfictl/pkg/configs"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+)
+
+// DigestOptions configures the BuildDigest operation.
+type DigestOptions struct {
+	// AdvisoryCfgs is the Index of advisories to build the digest from.
+	AdvisoryCfgs *configs.Index[advisoryconfigs.Document]
+
+	// Since is the start of the period the digest covers: advisories first
+	// detected at or after it are listed as new.
+	Since time.Time
+
+	// Severities and SLAs are as for StatsOptions, and determine the SLA
+	// breaches listed in the digest.
+	Severities map[string]string
+	SLAs       map[string]time.Duration
+
+	// Now is the time against which unresolved advisories are measured.
+	Now time.Time
+}
+
+// Digest lists the advisories that need attention, for notifying the people
+// who triage them.
+type Digest struct {
+	Since time.Time `json:"since"`
+
+	// New are the advisories first detected since the start of the digest's
+	// period, newest first.
+	New []DigestAdvisory `json:"new"`
+
+	// Untriaged are the advisories detected before the start of the digest's
+	// period that are still under_investigation, longest open first.
+	Untriaged []DigestAdvisory `json:"untriaged"`
+
+	// SLABreaches are the unresolved advisories that have been open longer than
+	// the SLA for their severity, longest open first.
+	SLABreaches []SLABreach `json:"slaBreaches"`
+}
+
+// DigestAdvisory is an advisory listed in a Digest.
+type DigestAdvisory struct {
+	Package       string     `json:"package"`
+	Vulnerability string     `json:"vulnerability"`
+	Status        vex.Status `json:"status"`
+	Detected      time.Time  `json:"detected"`
+}
+
+// BuildDigest returns the digest of the advisories needing attention. Only
+// public advisory data is considered.
+func BuildDigest(opts DigestOptions) Digest {
+	d := Digest{
+		Since:     opts.Since,
+		New:       []DigestAdvisory{},
+		Untriaged: []DigestAdvisory{},
+	}
+
+	for _, a := range List(opts.AdvisoryCfgs, ListFilter{}) {
+		entries := PublicEntries(a.Entries)
+		if len(entries) == 0 {
+			continue
+		}
+		latest := entries[len(entries)-1]
+
+		da := DigestAdvisory{
+			Package:       a.Package,
+			Vulnerability: a.Vulnerability,
+			Status:        latest.Status,
+			Detected:      entries[0].Timestamp,
+		}
+
+		switch {
+		case !da.Detected.Before(opts.Since):
+			d.New = append(d.New, da)
+		case latest.Status == vex.StatusUnderInvestigation:
+			d.Untriaged = append(d.Untriaged, da)
+		}
+	}
+
+	sort.SliceStable(d.New, func(i, j int) bool {
+		return d.New[i].Detected.After(d.New[j].Detected)
+	})
+	sort.SliceStable(d.Untriaged, func(i, j int) bool {
+		return d.Untriaged[i].Detected.Before(d.Untriaged[j].Detected)
+	})
+
+	d.SLABreaches = ComputeStats(StatsOptions{
+		AdvisoryCfgs: opts.AdvisoryCfgs,
+		Severities:   opts.Severities,
+		SLAs:         opts.SLAs,
+		Now:          opts.Now,
+	}).SLABreaches
+
+	return d
+}
+
+// Empty returns true if no advisories need attention.
+func (d Digest) Empty() bool {
+	return len(d.New) == 0 && len(d.Untriaged) == 0 && len(d.SLABreaches) == 0
+}
+
+// Text renders the digest as a message for a chat service such as Slack,
+// listing at most maxItems advisories per section (or all of them, if maxItems
+// is zero).
+func (d Digest) Text(maxItems int) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "*Advisories needing attention* (since %s)\n", d.Since.UTC().Format("2006-01-02 15:04 MST"))
+	if d.Empty() {
+		b.WriteString("\nNothing needs attention.\n")
+		return b.String()
+	}
+
+	writeSection := func(title string, n int, item func(i int) string) {
+		if n == 0 {
+			return
+		}
+
+		fmt.Fprintf(&b, "\n*%s (%d)*\n", title, n)
+		for i := 0; i < n; i++ {
+			if maxItems > 0 && i == maxItems {
+				fmt.Fprintf(&b, "• …and %d more\n", n-maxItems)
+				break
+			}
+			fmt.Fprintf(&b, "• %s\n", item(i))
+		}
+	}
+
+	writeSection("Newly detected", len(d.New), func(i int) string {
+		a := d.New[i]
+		return fmt.Sprintf("%s: %s (%s)", a.Package, a.Vulnerability, a.Status)
+	})
+	writeSection("Still untriaged", len(d.Untriaged), func(i int) string {
+		a := d.Untriaged[i]
+		return fmt.Sprintf("%s: %s (detected %s)", a.Package, a.Vulnerability, a.Detected.UTC().Format("2006-01-02"))
+	})
+	writeSection("SLA breaches", len(d.SLABreaches), func(i int) string {
+		breach := d.SLABreaches[i]
+		return fmt.Sprintf("%s: %s (%s, %s, open %dd, SLA %dd)", breach.Package, breach.Vulnerability, breach.Severity, breach.Status, int(breach.Open.Hours()/24), int(breach.SLA.Hours()/24))
+	})
+
+	return b.String()
+}
+
+// PostWebhook posts the payload, encoded as JSON, to the webhook at the given
+// URL. It returns an error if the webhook doesn't respond with a 2xx status.
+func PostWebhook(client *http.Client, url string, payload any) error {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("unable to encode webhook payload: %w", err)
+	}
+
+	resp, err := client.Post(url, "application/json", bytes.NewReader(b))
+	if err != nil {
+		return fmt.Errorf("unable to post to webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024)) //nolint:errcheck
+		return fmt.Errorf("webhook responded with %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	return nil
+}
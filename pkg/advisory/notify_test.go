@@ -0,0 +1,103 @@
+package advisory
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
+)
+
+func TestBuildDigest(t *testing.T) {
+	const doc = `package:
+  name: foo
+
+advisories:
+  CVE-2023-1:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: under_investigation
+  CVE-2023-2:
+    - timestamp: 2023-05-02T00:00:00Z
+      status: under_investigation
+    - timestamp: 2023-05-03T00:00:00Z
+      status: fixed
+      fixed-version: 1.2.3-r1
+  CVE-2023-3:
+    - timestamp: 2023-05-10T00:00:00Z
+      status: under_investigation
+  CVE-2023-4:
+    - timestamp: 2023-05-11T00:00:00Z
+      status: not_affected
+      justification: component_not_present
+`
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "foo.advisories.yaml"), []byte(doc), 0o644))
+
+	index, err := advisoryconfigs.NewIndex(rwos.DirFS(dir))
+	require.NoError(t, err)
+
+	since := time.Date(2023, 5, 10, 0, 0, 0, 0, time.UTC)
+	d := BuildDigest(DigestOptions{
+		AdvisoryCfgs: index,
+		Since:        since,
+		Severities:   map[string]string{"CVE-2023-1": "High"},
+		SLAs:         map[string]time.Duration{"High": 7 * 24 * time.Hour},
+		Now:          time.Date(2023, 5, 12, 0, 0, 0, 0, time.UTC),
+	})
+
+	assert.Equal(t, []string{"CVE-2023-4", "CVE-2023-3"}, digestVulnerabilities(d.New))
+	assert.Equal(t, []string{"CVE-2023-1"}, digestVulnerabilities(d.Untriaged))
+	require.Len(t, d.SLABreaches, 1)
+	assert.Equal(t, "CVE-2023-1", d.SLABreaches[0].Vulnerability)
+	assert.False(t, d.Empty())
+
+	assert.Equal(t, `*Advisories needing attention* (since 2023-05-10 00:00 UTC)
+
+*Newly detected (2)*
+• foo: CVE-2023-4 (not_affected)
+• …and 1 more
+
+*Still untriaged (1)*
+• foo: CVE-2023-1 (detected 2023-05-01)
+
+*SLA breaches (1)*
+• foo: CVE-2023-1 (High, under_investigation, open 11d, SLA 7d)
+`, d.Text(1))
+
+	assert.Contains(t, Digest{Since: since}.Text(0), "Nothing needs attention.")
+}
+
+func TestPostWebhook(t *testing.T) {
+	var got map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+
+		if got["text"] == "fail" {
+			http.Error(w, "invalid_payload", http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	require.NoError(t, PostWebhook(srv.Client(), srv.URL, map[string]string{"text": "hello"}))
+	assert.Equal(t, map[string]string{"text": "hello"}, got)
+
+	err := PostWebhook(srv.Client(), srv.URL, map[string]string{"text": "fail"})
+	assert.EqualError(t, err, "webhook responded with 400 Bad Request: invalid_payload")
+}
+
+func digestVulnerabilities(advisories []DigestAdvisory) []string {
+	var vulns []string
+	for _, a := range advisories {
+		vulns = append(vulns, a.Vulnerability)
+	}
+	return vulns
+}
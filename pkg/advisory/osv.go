@@ -0,0 +1,326 @@
+package advisory
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openvex/go-vex/pkg/vex"
+	"github.com/samber/lo"
+	"github.com/wolfi-dev/wolfictl/pkg/configs"
+	"github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+)
+
+// OSVSchemaVersion is the version of the OSV schema that exported records
+// conform to.
+const OSVSchemaVersion = "1.5.0"
+
+// OSV is a vulnerability record in the Open Source Vulnerability format (see
+// https://ossf.github.io/osv-schema/).
+type OSV struct {
+	SchemaVersion string         `json:"schema_version"`
+	ID            string         `json:"id"`
+	Modified      time.Time      `json:"modified"`
+	Published     time.Time      `json:"published"`
+	Aliases       []string       `json:"aliases,omitempty"`
+	Affected      []OSVAffected  `json:"affected"`
+	References    []OSVReference `json:"references,omitempty"`
+
+	DatabaseSpecific *OSVDatabaseSpecific `json:"database_specific,omitempty"`
+}
+
+// OSVReference is a link to more information about an OSV record's
+// vulnerability, of one of OSV's reference types (e.g. "FIX").
+type OSVReference struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+// osvReferenceTypes maps advisory reference types to OSV's.
+var osvReferenceTypes = map[advisory.ReferenceType]string{
+	advisory.ReferenceFixCommit:        "FIX",
+	advisory.ReferenceUpstreamAdvisory: "ADVISORY",
+	advisory.ReferenceDistroPR:         "FIX",
+	advisory.ReferenceOther:            "WEB",
+}
+
+// OSVDatabaseSpecific holds the distro's own data about an OSV record's
+// vulnerability, which OSV tooling doesn't interpret.
+type OSVDatabaseSpecific struct {
+	// NotAffected lists the packages that have been determined not to be affected
+	// by the vulnerability.
+	NotAffected []OSVNotAffected `json:"not_affected,omitempty"`
+}
+
+// OSVNotAffected is a package that isn't affected by an OSV record's
+// vulnerability, with the justification for that determination.
+type OSVNotAffected struct {
+	Package       OSVPackage `json:"package"`
+	Justification string     `json:"justification,omitempty"`
+	Impact        string     `json:"impact,omitempty"`
+}
+
+// OSVAffected is a package affected by an OSV record's vulnerability.
+type OSVAffected struct {
+	Package          OSVPackage        `json:"package"`
+	Severity         []OSVSeverity     `json:"severity,omitempty"`
+	Ranges           []OSVRange        `json:"ranges"`
+	DatabaseSpecific map[string]string `json:"database_specific,omitempty"`
+}
+
+// OSVSeverity is a severity score of a vulnerability in an affected package.
+type OSVSeverity struct {
+	Type  string `json:"type"`
+	Score string `json:"score"`
+}
+
+// OSVPackage identifies an affected package.
+type OSVPackage struct {
+	Ecosystem string `json:"ecosystem"`
+	Name      string `json:"name"`
+	Purl      string `json:"purl"`
+}
+
+// OSVRange is a range of affected versions of a package.
+type OSVRange struct {
+	Type   string     `json:"type"`
+	Events []OSVEvent `json:"events"`
+}
+
+// OSVEvent marks where a range of affected versions starts or ends.
+type OSVEvent struct {
+	Introduced string `json:"introduced,omitempty"`
+	Fixed      string `json:"fixed,omitempty"`
+}
+
+// ExportOSVOptions configures ExportOSV.
+type ExportOSVOptions struct {
+	AdvisoryCfgIndices []*configs.Index[advisory.Document]
+
+	// Ecosystem is the OSV ecosystem of the packages, e.g. "Wolfi". Its lowercase
+	// form is used as the namespace of the packages' purls, and its uppercase form
+	// prefixes the IDs of the records.
+	Ecosystem string
+
+	// IncludeNotAffected includes the packages that aren't affected by a
+	// vulnerability in the record's database-specific data, instead of leaving
+	// them out. Vulnerabilities that don't affect any package get a record too.
+	IncludeNotAffected bool
+}
+
+// ExportOSV returns an OSV record for each vulnerability that affects (or
+// affected) at least one package, based on the latest public event of each
+// package's advisory. Packages that aren't affected are left out. Records are
+// sorted by ID.
+func ExportOSV(opts ExportOSVOptions) ([]OSV, error) {
+	if opts.Ecosystem == "" {
+		return nil, fmt.Errorf("no OSV ecosystem specified")
+	}
+
+	records := make(map[string]*OSV)
+
+	for _, index := range opts.AdvisoryCfgIndices {
+		for _, doc := range index.Select().Configurations() {
+			for vulnID, allEntries := range doc.Advisories {
+				entries := PublicEntries(allEntries)
+				latest := Latest(entries)
+				if latest == nil {
+					continue
+				}
+
+				affected, ok := osvAffected(doc.Package.Name, opts.Ecosystem, *latest, SeverityOverrideOf(entries))
+				if !ok && !opts.IncludeNotAffected {
+					continue
+				}
+
+				r, exists := records[vulnID]
+				if !exists {
+					r = &OSV{
+						SchemaVersion: OSVSchemaVersion,
+						ID:            fmt.Sprintf("%s-%s", strings.ToUpper(opts.Ecosystem), vulnID),
+						Aliases:       []string{vulnID},
+						Affected:      []OSVAffected{},
+					}
+					records[vulnID] = r
+				}
+
+				if ok {
+					r.Affected = append(r.Affected, affected)
+				} else {
+					if r.DatabaseSpecific == nil {
+						r.DatabaseSpecific = &OSVDatabaseSpecific{}
+					}
+					r.DatabaseSpecific.NotAffected = append(r.DatabaseSpecific.NotAffected, OSVNotAffected{
+						Package:       osvPackage(doc.Package.Name, opts.Ecosystem),
+						Justification: string(latest.Justification),
+						Impact:        latest.ImpactStatement,
+					})
+				}
+
+				for _, ref := range ReferencesOf(entries) {
+					if !lo.ContainsBy(r.References, func(o OSVReference) bool { return o.URL == ref.URL }) {
+						r.References = append(r.References, OSVReference{Type: osvReferenceTypes[ref.Type], URL: ref.URL})
+					}
+				}
+
+				for _, e := range entries {
+					if r.Published.IsZero() || e.Timestamp.Before(r.Published) {
+						r.Published = e.Timestamp
+					}
+					if e.Timestamp.After(r.Modified) {
+						r.Modified = e.Timestamp
+					}
+				}
+			}
+		}
+	}
+
+	osvs := make([]OSV, 0, len(records))
+	for _, r := range records {
+		sort.Slice(r.Affected, func(i, j int) bool {
+			return r.Affected[i].Package.Name < r.Affected[j].Package.Name
+		})
+		sort.Slice(r.References, func(i, j int) bool {
+			return r.References[i].URL < r.References[j].URL
+		})
+		if r.DatabaseSpecific != nil {
+			notAffected := r.DatabaseSpecific.NotAffected
+			sort.Slice(notAffected, func(i, j int) bool {
+				return notAffected[i].Package.Name < notAffected[j].Package.Name
+			})
+		}
+		r.Published = r.Published.UTC()
+		r.Modified = r.Modified.UTC()
+		osvs = append(osvs, *r)
+	}
+	sort.Slice(osvs, func(i, j int) bool {
+		return osvs[i].ID < osvs[j].ID
+	})
+
+	return osvs, nil
+}
+
+// osvAffected returns the affected package entry for the given latest advisory
+// event, or false if the event says the package isn't affected. The advisory's
+// severity override, if any, is recorded as the package's severity.
+func osvAffected(pkg, ecosystem string, latest advisory.Entry, override *advisory.SeverityOverride) (OSVAffected, bool) {
+	events := []OSVEvent{{Introduced: "0"}}
+
+	switch latest.Status {
+	case vex.StatusNotAffected:
+		return OSVAffected{}, false
+	case vex.StatusFixed:
+		events = append(events, OSVEvent{Fixed: latest.FixedVersion})
+	}
+
+	affected := OSVAffected{
+		Package: osvPackage(pkg, ecosystem),
+		Ranges: []OSVRange{
+			{
+				Type:   "ECOSYSTEM",
+				Events: events,
+			},
+		},
+		DatabaseSpecific: map[string]string{
+			"status": string(latest.Status),
+		},
+	}
+
+	if override != nil {
+		affected.DatabaseSpecific["severity"] = override.Severity
+		affected.DatabaseSpecific["severity_rationale"] = override.Rationale
+		if override.CVSS != "" {
+			affected.Severity = []OSVSeverity{{Type: "CVSS_V3", Score: override.CVSS}}
+		}
+	}
+
+	return affected, true
+}
+
+func osvPackage(pkg, ecosystem string) OSVPackage {
+	return OSVPackage{
+		Ecosystem: ecosystem,
+		Name:      pkg,
+		Purl:      fmt.Sprintf("pkg:apk/%s/%s", strings.ToLower(ecosystem), pkg),
+	}
+}
+
+// WriteOSV writes each record to its own file, named after its ID, in the given
+// directory, which is created if needed. It returns the paths of the written
+// files.
+func WriteOSV(osvs []OSV, dir string) ([]string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("unable to create OSV output directory: %w", err)
+	}
+
+	paths := make([]string, 0, len(osvs))
+	for i := range osvs {
+		b, err := json.MarshalIndent(osvs[i], "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("unable to encode OSV record %s: %w", osvs[i].ID, err)
+		}
+
+		p := filepath.Join(dir, osvs[i].ID+".json")
+		if err := os.WriteFile(p, append(b, '\n'), 0o644); err != nil { //nolint:gosec
+			return nil, fmt.Errorf("unable to write OSV record %s: %w", osvs[i].ID, err)
+		}
+		paths = append(paths, p)
+	}
+
+	return paths, nil
+}
+
+// OSVOfflineDBPath returns the path of the zip file that holds the OSV records
+// of the given ecosystem in an osv-scanner offline database rooted at dir. This
+// is the layout osv-scanner expects in its local database directory (see
+// OSV_SCANNER_LOCAL_DB_CACHE_DIRECTORY).
+func OSVOfflineDBPath(dir, ecosystem string) string {
+	return filepath.Join(dir, "osv-scanner", ecosystem, "all.zip")
+}
+
+// WriteOSVOfflineDB writes the records, each to a JSON file named after its ID,
+// into a zip file laid out as an osv-scanner offline database rooted at the given
+// directory. It returns the path of the written zip file.
+func WriteOSVOfflineDB(osvs []OSV, dir, ecosystem string) (string, error) {
+	p := OSVOfflineDBPath(dir, ecosystem)
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return "", fmt.Errorf("unable to create OSV offline database directory: %w", err)
+	}
+
+	f, err := os.Create(p)
+	if err != nil {
+		return "", fmt.Errorf("unable to create OSV offline database: %w", err)
+	}
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+	for i := range osvs {
+		b, err := json.Marshal(osvs[i])
+		if err != nil {
+			return "", fmt.Errorf("unable to encode OSV record %s: %w", osvs[i].ID, err)
+		}
+
+		w, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     osvs[i].ID + ".json",
+			Method:   zip.Deflate,
+			Modified: osvs[i].Modified,
+		})
+		if err != nil {
+			return "", fmt.Errorf("unable to add OSV record %s to offline database: %w", osvs[i].ID, err)
+		}
+		if _, err := w.Write(b); err != nil {
+			return "", fmt.Errorf("unable to add OSV record %s to offline database: %w", osvs[i].ID, err)
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return "", fmt.Errorf("unable to write OSV offline database: %w", err)
+	}
+
+	return p, nil
+}
@@ -0,0 +1,128 @@
+package advisory
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wolfi-dev/wolfictl/pkg/configs"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
+)
+
+func TestExportOSV(t *testing.T) {
+	cfgs, err := advisoryconfigs.NewIndex(rwos.DirFS("./testdata/export/advisories"))
+	require.NoError(t, err)
+
+	osvs, err := ExportOSV(ExportOSVOptions{
+		AdvisoryCfgIndices: []*configs.Index[advisoryconfigs.Document]{cfgs},
+		Ecosystem:          "Wolfi",
+	})
+	require.NoError(t, err)
+
+	// CVE-2023-0466 is left out, since openssl isn't affected.
+	assert.Len(t, osvs, 21)
+	for _, o := range osvs {
+		assert.NotEqual(t, "WOLFI-CVE-2023-0466", o.ID)
+	}
+
+	ts := time.Date(2022, 9, 15, 2, 40, 18, 0, time.UTC)
+	assert.Equal(t, OSV{
+		SchemaVersion: OSVSchemaVersion,
+		ID:            "WOLFI-CVE-2020-8927",
+		Modified:      ts,
+		Published:     ts,
+		Aliases:       []string{"CVE-2020-8927"},
+		Affected: []OSVAffected{
+			{
+				Package: OSVPackage{Ecosystem: "Wolfi", Name: "brotli", Purl: "pkg:apk/wolfi/brotli"},
+				Ranges: []OSVRange{
+					{Type: "ECOSYSTEM", Events: []OSVEvent{{Introduced: "0"}, {Fixed: "1.0.9-r0"}}},
+				},
+				DatabaseSpecific: map[string]string{"status": "fixed"},
+			},
+		},
+	}, osvs[0])
+
+	_, err = ExportOSV(ExportOSVOptions{AdvisoryCfgIndices: []*configs.Index[advisoryconfigs.Document]{cfgs}})
+	assert.Error(t, err)
+}
+
+func TestExportOSV_includeNotAffected(t *testing.T) {
+	cfgs, err := advisoryconfigs.NewIndex(rwos.DirFS("./testdata/export/advisories"))
+	require.NoError(t, err)
+
+	osvs, err := ExportOSV(ExportOSVOptions{
+		AdvisoryCfgIndices: []*configs.Index[advisoryconfigs.Document]{cfgs},
+		Ecosystem:          "Wolfi",
+		IncludeNotAffected: true,
+	})
+	require.NoError(t, err)
+	require.Len(t, osvs, 22)
+
+	var got *OSV
+	for i := range osvs {
+		if osvs[i].ID == "WOLFI-CVE-2023-0466" {
+			got = &osvs[i]
+		}
+	}
+	require.NotNil(t, got)
+
+	assert.Empty(t, got.Affected)
+	require.NotNil(t, got.DatabaseSpecific)
+	assert.Equal(t, []OSVNotAffected{{
+		Package:       OSVPackage{Ecosystem: "Wolfi", Name: "openssl", Purl: "pkg:apk/wolfi/openssl"},
+		Justification: "vulnerable_code_not_present",
+		Impact:        "This was a case of documentation not matching function behavior. The upstream maintainers decided to update the documentation rather than change the behavior. See https://www.openssl.org/news/secadv/20230328.txt",
+	}}, got.DatabaseSpecific.NotAffected)
+}
+
+func TestWriteOSVOfflineDB(t *testing.T) {
+	dir := t.TempDir()
+	osvs := []OSV{
+		{SchemaVersion: OSVSchemaVersion, ID: "WOLFI-CVE-2023-1", Affected: []OSVAffected{}},
+		{SchemaVersion: OSVSchemaVersion, ID: "WOLFI-CVE-2023-2", Affected: []OSVAffected{}},
+	}
+
+	p, err := WriteOSVOfflineDB(osvs, dir, "Wolfi")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "osv-scanner", "Wolfi", "all.zip"), p)
+
+	zr, err := zip.OpenReader(p)
+	require.NoError(t, err)
+	defer zr.Close()
+
+	require.Len(t, zr.File, 2)
+	for i, f := range zr.File {
+		assert.Equal(t, osvs[i].ID+".json", f.Name)
+
+		rc, err := f.Open()
+		require.NoError(t, err)
+
+		var got OSV
+		require.NoError(t, json.NewDecoder(rc).Decode(&got))
+		rc.Close()
+		assert.Equal(t, osvs[i], got)
+	}
+}
+
+func TestWriteOSV(t *testing.T) {
+	dir := t.TempDir()
+	osvs := []OSV{{SchemaVersion: OSVSchemaVersion, ID: "WOLFI-CVE-2023-1"}}
+
+	paths, err := WriteOSV(osvs, dir)
+	require.NoError(t, err)
+	require.Len(t, paths, 1)
+
+	b, err := os.ReadFile(paths[0])
+	require.NoError(t, err)
+
+	var got OSV
+	require.NoError(t, json.Unmarshal(b, &got))
+	assert.Equal(t, osvs[0], got)
+}
@@ -0,0 +1,79 @@
+package advisory
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/wolfi-dev/wolfictl/pkg/configs"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
+)
+
+// OverlayIndices writes the combined view of the given indices of advisory
+// documents to dir, which should be empty, and returns the Index of the combined
+// documents.
+//
+// Indices given later take precedence over those given earlier (e.g. a private
+// overlay given after the public advisories). Documents for the same package
+// are merged advisory by advisory: where more than one index has an advisory
+// for the same vulnerability ID, the advisory (and its aliases) from the index
+// with the highest precedence is used in its entirety, and the others are
+// dropped. The rest of each combined document comes from the document with the
+// highest precedence.
+func OverlayIndices(dir string, indices ...*configs.Index[advisoryconfigs.Document]) (*configs.Index[advisoryconfigs.Document], error) {
+	docsByPackage := make(map[string][]advisoryconfigs.Document)
+	for _, index := range indices {
+		for _, doc := range index.Select().Configurations() {
+			docsByPackage[doc.Name()] = append(docsByPackage[doc.Name()], doc)
+		}
+	}
+
+	merged, err := advisoryconfigs.NewIndex(rwos.DirFS(dir))
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(docsByPackage))
+	for name := range docsByPackage {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		doc := overlayDocuments(docsByPackage[name])
+		if err := merged.Create(fmt.Sprintf("%s.advisories.yaml", name), doc); err != nil {
+			return nil, fmt.Errorf("unable to write overlaid advisories for %q: %w", name, err)
+		}
+	}
+
+	return merged, nil
+}
+
+// overlayDocuments combines the given documents for the same package, given in
+// order of increasing precedence (see OverlayIndices).
+func overlayDocuments(docs []advisoryconfigs.Document) advisoryconfigs.Document {
+	last := docs[len(docs)-1]
+	merged := advisoryconfigs.Document{
+		SchemaVersion: last.SchemaVersion,
+		Package:       last.Package,
+		Archived:      last.Archived,
+		Advisories:    make(advisoryconfigs.Advisories),
+		Aliases:       make(advisoryconfigs.Aliases),
+	}
+
+	for _, doc := range docs {
+		for id, entries := range doc.Advisories {
+			merged.Advisories[id] = entries
+			delete(merged.Aliases, id)
+			if aliases := doc.Aliases[id]; len(aliases) > 0 {
+				merged.Aliases[id] = aliases
+			}
+		}
+	}
+
+	if len(merged.Aliases) == 0 {
+		merged.Aliases = nil
+	}
+
+	return merged
+}
@@ -0,0 +1,104 @@
+package advisory
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wolfi-dev/wolfictl/pkg/configs"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
+)
+
+func TestOverlayIndices(t *testing.T) {
+	const publicFoo = `package:
+  name: foo
+
+advisories:
+  CVE-2023-1:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: under_investigation
+  CVE-2023-2:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: fixed
+      fixed-version: 1.2.3-r0
+
+aliases:
+  CVE-2023-1:
+    - GHSA-2222-3333-4444
+`
+	const publicBar = `package:
+  name: bar
+
+advisories:
+  CVE-2023-3:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: under_investigation
+`
+	const privateFoo = `package:
+  name: foo
+
+advisories:
+  CVE-2023-1:
+    - timestamp: 2023-05-02T00:00:00Z
+      status: not_affected
+      justification: vulnerable_code_not_present
+`
+	const privateBaz = `package:
+  name: baz
+
+advisories:
+  CVE-2023-4:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: under_investigation
+`
+
+	newIndex := func(t *testing.T, docs map[string]string) *configs.Index[advisoryconfigs.Document] {
+		dir := t.TempDir()
+		for name, doc := range docs {
+			require.NoError(t, os.WriteFile(filepath.Join(dir, name+".advisories.yaml"), []byte(doc), 0o644))
+		}
+
+		index, err := advisoryconfigs.NewIndex(rwos.DirFS(dir))
+		require.NoError(t, err)
+		return index
+	}
+
+	public := newIndex(t, map[string]string{"foo": publicFoo, "bar": publicBar})
+	private := newIndex(t, map[string]string{"foo": privateFoo, "baz": privateBaz})
+
+	merged, err := OverlayIndices(t.TempDir(), public, private)
+	require.NoError(t, err)
+
+	listed := List(merged, ListFilter{})
+	require.Len(t, listed, 4)
+
+	got := make(map[string]string)
+	for _, a := range listed {
+		got[a.Package+"/"+a.Vulnerability] = string(a.Latest.Status)
+	}
+	assert.Equal(t, map[string]string{
+		"bar/CVE-2023-3": "under_investigation",
+		"baz/CVE-2023-4": "under_investigation",
+		"foo/CVE-2023-1": "not_affected",
+		"foo/CVE-2023-2": "fixed",
+	}, got)
+
+	// The private advisory replaced the public one entirely, including its
+	// aliases.
+	foo := merged.Select().WhereName("foo").Configurations()
+	require.Len(t, foo, 1)
+	assert.Len(t, foo[0].Advisories["CVE-2023-1"], 1)
+	assert.Empty(t, foo[0].Aliases)
+
+	// With the precedence reversed, the public advisory wins.
+	merged, err = OverlayIndices(t.TempDir(), private, public)
+	require.NoError(t, err)
+
+	foo = merged.Select().WhereName("foo").Configurations()
+	require.Len(t, foo, 1)
+	assert.Equal(t, "under_investigation", string(Latest(foo[0].Advisories["CVE-2023-1"]).Status))
+	assert.Equal(t, advisoryconfigs.Aliases{"CVE-2023-1": {"GHSA-2222-3333-4444"}}, foo[0].Aliases)
+}
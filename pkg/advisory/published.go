@@ -0,0 +1,100 @@
+package advisory
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/openvex/go-vex/pkg/vex"
+	"github.com/samber/lo"
+	"github.com/wolfi-dev/wolfictl/pkg/configs"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	"gitlab.alpinelinux.org/alpine/go/repository"
+)
+
+// UnpublishedFix is a fixed event whose fixed version never shipped for some
+// architectures, i.e. the advisory claims a fix that users can't install there.
+type UnpublishedFix struct {
+	Package       string
+	Vulnerability string
+	FixedVersion  string
+
+	// Arches are the architectures for which the package is published, but
+	// neither the fixed version nor any later version is.
+	Arches []string
+}
+
+func (f UnpublishedFix) String() string {
+	return fmt.Sprintf("%s: %s: fixed version %s has not been published for %s", f.Package, f.Vulnerability, f.FixedVersion, strings.Join(f.Arches, ", "))
+}
+
+// FindUnpublishedFixes checks each fixed event of the given advisories against
+// the published APKINDEX of each architecture, keyed by architecture. A fix has
+// shipped for an architecture if the fixed version or a later version of the
+// package (or of a subpackage built from it) is in that architecture's
+// APKINDEX. Architectures for which the package isn't published at all are
+// skipped, since the package isn't built for them. Results are sorted by
+// package and vulnerability.
+func FindUnpublishedFixes(cfgs *configs.Index[advisoryconfigs.Document], apkindexes map[string]*repository.ApkIndex) []UnpublishedFix {
+	arches := lo.Keys(apkindexes)
+	sort.Strings(arches)
+
+	// versions maps each architecture to the published versions of each package,
+	// by package name and by origin.
+	versions := make(map[string]map[string][]string, len(apkindexes))
+	for arch, apkindex := range apkindexes {
+		byName := make(map[string][]string)
+		for _, pkg := range apkindex.Packages {
+			byName[pkg.Name] = append(byName[pkg.Name], pkg.Version)
+			if pkg.Origin != "" && pkg.Origin != pkg.Name {
+				byName[pkg.Origin] = append(byName[pkg.Origin], pkg.Version)
+			}
+		}
+		versions[arch] = byName
+	}
+
+	docs := cfgs.Select().Configurations()
+	sort.Slice(docs, func(i, j int) bool {
+		return docs[i].Name() < docs[j].Name()
+	})
+
+	var unpublished []UnpublishedFix
+	for _, doc := range docs {
+		vulns := lo.Keys(doc.Advisories)
+		sort.Strings(vulns)
+
+		for _, vuln := range vulns {
+			for _, e := range doc.Advisories[vuln] {
+				if e.Status != vex.StatusFixed || e.FixedVersion == "" {
+					continue
+				}
+
+				var missing []string
+				for _, arch := range arches {
+					published := versions[arch][doc.Name()]
+					if len(published) == 0 {
+						continue
+					}
+
+					shipped := lo.ContainsBy(published, func(v string) bool {
+						return !versionLess(v, e.FixedVersion)
+					})
+					if !shipped {
+						missing = append(missing, arch)
+					}
+				}
+
+				if len(missing) > 0 {
+					unpublished = append(unpublished, UnpublishedFix{
+						Package:       doc.Name(),
+						Vulnerability: vuln,
+						FixedVersion:  e.FixedVersion,
+						Arches:        missing,
+					})
+				}
+			}
+		}
+	}
+
+	return unpublished
+}
@@ -0,0 +1,74 @@
+package advisory
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
+	"gitlab.alpinelinux.org/alpine/go/repository"
+)
+
+func TestFindUnpublishedFixes(t *testing.T) {
+	const foo = `package:
+  name: foo
+
+advisories:
+  CVE-2023-0001:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: fixed
+      fixed-version: 1.2.3-r1
+  CVE-2023-0002:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: under_investigation
+    - timestamp: 2023-05-02T00:00:00Z
+      status: fixed
+      fixed-version: 1.3.0-r0
+`
+	const bar = `package:
+  name: bar
+
+advisories:
+  CVE-2023-0003:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: fixed
+      fixed-version: 2.0.0-r0
+`
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "foo.advisories.yaml"), []byte(foo), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bar.advisories.yaml"), []byte(bar), 0o644))
+
+	index, err := advisoryconfigs.NewIndex(rwos.DirFS(dir))
+	require.NoError(t, err)
+
+	apkindexes := map[string]*repository.ApkIndex{
+		// A later version than 1.2.3-r1 counts as shipping its fix, and bar's fix
+		// shipped in a subpackage.
+		"x86_64": {Packages: []*repository.Package{
+			{Name: "foo", Version: "1.2.3-r2"},
+			{Name: "bar-libs", Origin: "bar", Version: "2.0.0-r0"},
+		}},
+		// bar isn't built for aarch64 at all.
+		"aarch64": {Packages: []*repository.Package{
+			{Name: "foo", Version: "1.3.0-r0"},
+		}},
+	}
+
+	unpublished := FindUnpublishedFixes(index, apkindexes)
+	assert.Equal(t, []UnpublishedFix{
+		{Package: "foo", Vulnerability: "CVE-2023-0002", FixedVersion: "1.3.0-r0", Arches: []string{"x86_64"}},
+	}, unpublished)
+	assert.Equal(t, "foo: CVE-2023-0002: fixed version 1.3.0-r0 has not been published for x86_64", unpublished[0].String())
+
+	merr := Validate(ValidateOptions{AdvisoryCfgs: index, APKIndexes: apkindexes})
+	require.NotNil(t, merr)
+	require.Len(t, merr.Errors, 1)
+	assert.EqualError(t, merr.Errors[0], unpublished[0].String())
+
+	apkindexes["x86_64"].Packages = append(apkindexes["x86_64"].Packages, &repository.Package{Name: "foo", Version: "1.3.0-r1"})
+	assert.Empty(t, FindUnpublishedFixes(index, apkindexes))
+}
@@ -0,0 +1,84 @@
+package advisory
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/openvex/go-vex/pkg/vex"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+)
+
+// PackageURL is a parsed package URL (purl) of an APK package, as in
+// "pkg:apk/wolfi/foo@1.2.3-r0".
+type PackageURL struct {
+	Namespace string
+	Name      string
+
+	// Version is the package's full version (including its epoch), if given.
+	Version string
+}
+
+// ParsePackageURL parses a purl of type "apk". Qualifiers (e.g. "?arch=x86_64")
+// and subpaths are accepted but ignored, since advisories don't vary by them.
+func ParsePackageURL(s string) (PackageURL, error) {
+	rest, ok := strings.CutPrefix(s, "pkg:")
+	if !ok {
+		return PackageURL{}, fmt.Errorf("purl %q must start with \"pkg:\"", s)
+	}
+
+	if i := strings.IndexAny(rest, "?#"); i >= 0 {
+		rest = rest[:i]
+	}
+
+	typ, rest, _ := strings.Cut(rest, "/")
+	if !strings.EqualFold(typ, "apk") {
+		return PackageURL{}, fmt.Errorf("purl %q must be of type \"apk\"", s)
+	}
+
+	rest, version, _ := strings.Cut(rest, "@")
+	var p PackageURL
+	var err error
+	if p.Version, err = url.PathUnescape(version); err != nil {
+		return PackageURL{}, fmt.Errorf("purl %q has an invalid version: %w", s, err)
+	}
+
+	parts := strings.Split(strings.Trim(rest, "/"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return PackageURL{}, fmt.Errorf("purl %q must have a namespace and name, as in pkg:apk/<namespace>/<name>", s)
+	}
+	if p.Namespace, err = url.PathUnescape(parts[0]); err != nil {
+		return PackageURL{}, fmt.Errorf("purl %q has an invalid namespace: %w", s, err)
+	}
+	if p.Name, err = url.PathUnescape(parts[1]); err != nil {
+		return PackageURL{}, fmt.Errorf("purl %q has an invalid name: %w", s, err)
+	}
+
+	return p, nil
+}
+
+func (p PackageURL) String() string {
+	s := fmt.Sprintf("pkg:apk/%s/%s", p.Namespace, p.Name)
+	if p.Version != "" {
+		s += "@" + p.Version
+	}
+
+	return s
+}
+
+// AtVersion returns the given (latest) entry of an advisory as it applies to an
+// exact version of the package. A fixed entry applies as is only to versions at
+// or after the fixed version; earlier versions are affected, with an action
+// statement to upgrade. Other entries apply to every version. If version is
+// empty, the entry is returned unchanged.
+func AtVersion(entry advisoryconfigs.Entry, version string) advisoryconfigs.Entry {
+	if version == "" || entry.Status != vex.StatusFixed || !versionLess(version, entry.FixedVersion) {
+		return entry
+	}
+
+	return advisoryconfigs.Entry{
+		Timestamp:       entry.Timestamp,
+		Status:          vex.StatusAffected,
+		ActionStatement: fmt.Sprintf("upgrade to %s or later", entry.FixedVersion),
+	}
+}
@@ -0,0 +1,56 @@
+package advisory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openvex/go-vex/pkg/vex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+)
+
+func TestParsePackageURL(t *testing.T) {
+	tests := []struct {
+		purl    string
+		want    PackageURL
+		wantErr bool
+	}{
+		{purl: "pkg:apk/wolfi/foo@1.2.3-r0", want: PackageURL{Namespace: "wolfi", Name: "foo", Version: "1.2.3-r0"}},
+		{purl: "pkg:apk/wolfi/foo@1.2.3-r0?arch=x86_64", want: PackageURL{Namespace: "wolfi", Name: "foo", Version: "1.2.3-r0"}},
+		{purl: "pkg:apk/wolfi/foo", want: PackageURL{Namespace: "wolfi", Name: "foo"}},
+		{purl: "pkg:apk/wolfi/libstdc%2B%2B@13.2.0-r0", want: PackageURL{Namespace: "wolfi", Name: "libstdc++", Version: "13.2.0-r0"}},
+		{purl: "pkg:deb/debian/foo@1.2.3", wantErr: true},
+		{purl: "pkg:apk/foo@1.2.3-r0", wantErr: true},
+		{purl: "apk/wolfi/foo", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.purl, func(t *testing.T) {
+			got, err := ParsePackageURL(tt.purl)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAtVersion(t *testing.T) {
+	ts := time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)
+	fixed := advisoryconfigs.Entry{Timestamp: ts, Status: vex.StatusFixed, FixedVersion: "1.2.3-r1"}
+
+	assert.Equal(t, fixed, AtVersion(fixed, ""))
+	assert.Equal(t, fixed, AtVersion(fixed, "1.2.3-r1"))
+	assert.Equal(t, fixed, AtVersion(fixed, "1.3.0-r0"))
+	assert.Equal(t, advisoryconfigs.Entry{
+		Timestamp:       ts,
+		Status:          vex.StatusAffected,
+		ActionStatement: "upgrade to 1.2.3-r1 or later",
+	}, AtVersion(fixed, "1.2.3-r0"))
+
+	notAffected := advisoryconfigs.Entry{Timestamp: ts, Status: vex.StatusNotAffected, Justification: vex.VulnerableCodeNotPresent}
+	assert.Equal(t, notAffected, AtVersion(notAffected, "0.1.0-r0"))
+}
@@ -0,0 +1,245 @@
+package advisory
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openvex/go-vex/pkg/vex"
+	"github.com/wolfi-dev/wolfictl/pkg/configs"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+)
+
+// MaxQualityScore is the score given to an advisory with no quality issues.
+const MaxQualityScore = 100
+
+// DefaultStaleAfter is how long an advisory can remain under investigation
+// before it's considered stale.
+const DefaultStaleAfter = 30 * 24 * time.Hour
+
+type QualityOptions struct {
+	// AdvisoryCfgs is the Index of advisories on which to operate.
+	AdvisoryCfgs *configs.Index[advisoryconfigs.Document]
+
+	// Now is the time at which advisories are assessed. If set, entries with
+	// timestamps after Now are ignored, which allows assessing the advisory data
+	// as it was at a point in the past. If zero, the current time is used, and
+	// entries timestamped in the future are flagged.
+	Now time.Time
+
+	// StaleAfter is how long an advisory can remain under investigation before
+	// it's flagged. If zero, DefaultStaleAfter is used.
+	StaleAfter time.Duration
+}
+
+// QualityIssue is a single completeness problem found with an advisory.
+type QualityIssue struct {
+	Description string
+
+	// Penalty is the number of points deducted from the advisory's score for this
+	// issue.
+	Penalty int
+}
+
+// QualityReport describes the completeness of a single advisory.
+type QualityReport struct {
+	Package       string
+	Vulnerability string
+	Score         int
+	Issues        []QualityIssue
+}
+
+// QualityTrendPoint is the average advisory quality score as of a point in
+// time.
+type QualityTrendPoint struct {
+	Time         time.Time
+	AverageScore float64
+	Advisories   int
+}
+
+// Quality scores each advisory on its completeness and returns the reports
+// ordered from lowest to highest score, so that the advisories most in need of
+// cleanup come first.
+func Quality(opts QualityOptions) []QualityReport {
+	now := opts.Now
+	asOf := !now.IsZero()
+	if !asOf {
+		now = time.Now()
+	}
+
+	staleAfter := opts.StaleAfter
+	if staleAfter == 0 {
+		staleAfter = DefaultStaleAfter
+	}
+
+	var reports []QualityReport
+	for _, doc := range opts.AdvisoryCfgs.Select().Configurations() {
+		for vulnID, entries := range doc.Advisories {
+			if asOf {
+				entries = entriesAsOf(entries, now)
+			}
+			if len(entries) == 0 {
+				continue
+			}
+
+			issues := assessAdvisory(vulnID, entries, now, staleAfter)
+			reports = append(reports, QualityReport{
+				Package:       doc.Package.Name,
+				Vulnerability: vulnID,
+				Score:         score(issues),
+				Issues:        issues,
+			})
+		}
+	}
+
+	sort.Slice(reports, func(i, j int) bool {
+		if reports[i].Score != reports[j].Score {
+			return reports[i].Score < reports[j].Score
+		}
+		if reports[i].Package != reports[j].Package {
+			return reports[i].Package < reports[j].Package
+		}
+		return reports[i].Vulnerability < reports[j].Vulnerability
+	})
+
+	return reports
+}
+
+// QualityTrend computes the average advisory quality score as of each of the
+// given times, considering only the advisory data recorded by that time.
+func QualityTrend(opts QualityOptions, times []time.Time) []QualityTrendPoint {
+	points := make([]QualityTrendPoint, 0, len(times))
+
+	for _, t := range times {
+		o := opts
+		o.Now = t
+		reports := Quality(o)
+
+		points = append(points, QualityTrendPoint{
+			Time:         t,
+			AverageScore: AverageQualityScore(reports),
+			Advisories:   len(reports),
+		})
+	}
+
+	return points
+}
+
+// AverageQualityScore returns the mean score of the given reports. If there are
+// no reports, it returns MaxQualityScore.
+func AverageQualityScore(reports []QualityReport) float64 {
+	if len(reports) == 0 {
+		return MaxQualityScore
+	}
+
+	total := 0
+	for _, r := range reports {
+		total += r.Score
+	}
+
+	return float64(total) / float64(len(reports))
+}
+
+func entriesAsOf(entries []advisoryconfigs.Entry, t time.Time) []advisoryconfigs.Entry {
+	var result []advisoryconfigs.Entry
+	for _, e := range entries {
+		if e.Timestamp.After(t) {
+			continue
+		}
+		result = append(result, e)
+	}
+	return result
+}
+
+func assessAdvisory(vulnID string, entries []advisoryconfigs.Entry, now time.Time, staleAfter time.Duration) []QualityIssue {
+	var issues []QualityIssue
+
+	if !strings.HasPrefix(vulnID, "CVE-") {
+		issues = append(issues, QualityIssue{
+			Description: fmt.Sprintf("advisory ID %q is not a CVE ID; check whether a CVE alias exists", vulnID),
+			Penalty:     10,
+		})
+	}
+
+	for i, e := range entries {
+		if e.Timestamp.IsZero() {
+			issues = append(issues, QualityIssue{
+				Description: fmt.Sprintf("event %d has no timestamp", i+1),
+				Penalty:     20,
+			})
+			continue
+		}
+
+		if e.Timestamp.After(now) {
+			issues = append(issues, QualityIssue{
+				Description: fmt.Sprintf("event %d is timestamped in the future", i+1),
+				Penalty:     20,
+			})
+		}
+
+		if i > 0 && e.Timestamp.Before(entries[i-1].Timestamp) {
+			issues = append(issues, QualityIssue{
+				Description: fmt.Sprintf("event %d is timestamped before the event preceding it", i+1),
+				Penalty:     10,
+			})
+		}
+	}
+
+	latest := Latest(entries)
+
+	switch latest.Status {
+	case vex.StatusNotAffected:
+		if latest.Justification == "" {
+			issues = append(issues, QualityIssue{
+				Description: "not_affected status has no justification",
+				Penalty:     30,
+			})
+		}
+		if latest.ImpactStatement == "" {
+			issues = append(issues, QualityIssue{
+				Description: "not_affected status has no impact statement explaining why",
+				Penalty:     15,
+			})
+		}
+
+	case vex.StatusAffected:
+		if latest.ActionStatement == "" {
+			issues = append(issues, QualityIssue{
+				Description: "affected status has no action statement",
+				Penalty:     30,
+			})
+		}
+
+	case vex.StatusFixed:
+		if latest.FixedVersion == "" {
+			issues = append(issues, QualityIssue{
+				Description: "fixed status has no fixed version",
+				Penalty:     30,
+			})
+		}
+
+	case vex.StatusUnderInvestigation:
+		if age := now.Sub(latest.Timestamp); age > staleAfter {
+			issues = append(issues, QualityIssue{
+				Description: fmt.Sprintf("under investigation for %d days without an update", int(age.Hours()/24)),
+				Penalty:     20,
+			})
+		}
+	}
+
+	return issues
+}
+
+func score(issues []QualityIssue) int {
+	s := MaxQualityScore
+	for _, issue := range issues {
+		s -= issue.Penalty
+	}
+
+	if s < 0 {
+		return 0
+	}
+
+	return s
+}
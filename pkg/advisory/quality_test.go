@@ -0,0 +1,104 @@
+package advisory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openvex/go-vex/pkg/vex"
+	"github.com/stretchr/testify/assert"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+)
+
+func TestAssessAdvisory(t *testing.T) {
+	now := time.Date(2023, 6, 30, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	cases := []struct {
+		name           string
+		vulnID         string
+		entries        []advisoryconfigs.Entry
+		expectedScore  int
+		expectedIssues int
+	}{
+		{
+			name:   "complete not_affected",
+			vulnID: "CVE-2023-1234",
+			entries: []advisoryconfigs.Entry{
+				{
+					Timestamp:       now.Add(-2 * day),
+					Status:          vex.StatusNotAffected,
+					Justification:   vex.VulnerableCodeNotPresent,
+					ImpactStatement: "the vulnerable code was added in a later version",
+				},
+			},
+			expectedScore:  100,
+			expectedIssues: 0,
+		},
+		{
+			name:   "not_affected missing justification and impact",
+			vulnID: "CVE-2023-1234",
+			entries: []advisoryconfigs.Entry{
+				{
+					Timestamp: now.Add(-2 * day),
+					Status:    vex.StatusNotAffected,
+				},
+			},
+			expectedScore:  55,
+			expectedIssues: 2,
+		},
+		{
+			name:   "stale investigation with GHSA ID",
+			vulnID: "GHSA-xxxx-xxxx-xxxx",
+			entries: []advisoryconfigs.Entry{
+				{
+					Timestamp: now.Add(-60 * day),
+					Status:    vex.StatusUnderInvestigation,
+				},
+			},
+			expectedScore:  70,
+			expectedIssues: 2,
+		},
+		{
+			name:   "out of order and future timestamps",
+			vulnID: "CVE-2023-1234",
+			entries: []advisoryconfigs.Entry{
+				{
+					Timestamp: now.Add(day),
+					Status:    vex.StatusUnderInvestigation,
+				},
+				{
+					Timestamp:    now.Add(-day),
+					Status:       vex.StatusFixed,
+					FixedVersion: "1.2.3-r0",
+				},
+			},
+			expectedScore:  70,
+			expectedIssues: 2,
+		},
+		{
+			name:   "many issues floor at zero",
+			vulnID: "GHSA-xxxx-xxxx-xxxx",
+			entries: []advisoryconfigs.Entry{
+				{Status: vex.StatusAffected},
+				{Status: vex.StatusAffected},
+				{Status: vex.StatusAffected},
+				{Status: vex.StatusAffected},
+			},
+			expectedScore:  0,
+			expectedIssues: 6,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := assessAdvisory(tt.vulnID, tt.entries, now, DefaultStaleAfter)
+			assert.Len(t, issues, tt.expectedIssues)
+			assert.Equal(t, tt.expectedScore, score(issues))
+		})
+	}
+}
+
+func TestAverageQualityScore(t *testing.T) {
+	assert.Equal(t, float64(MaxQualityScore), AverageQualityScore(nil))
+	assert.Equal(t, 75.0, AverageQualityScore([]QualityReport{{Score: 100}, {Score: 50}}))
+}
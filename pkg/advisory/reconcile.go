@@ -0,0 +1,134 @@
+package advisory
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/openvex/go-vex/pkg/vex"
+	"github.com/wolfi-dev/wolfictl/pkg/configs"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	"github.com/wolfi-dev/wolfictl/pkg/scan"
+)
+
+// DefaultReconcileJustification is the justification of the events proposed by
+// Reconcile, unless another is given.
+const DefaultReconcileJustification = vex.VulnerableCodeNotPresent
+
+// ReconcileOptions configures the Reconcile operation.
+type ReconcileOptions struct {
+	// AdvisoryCfgs is the Index of advisories to reconcile.
+	AdvisoryCfgs *configs.Index[advisoryconfigs.Document]
+
+	// ScanResults are fresh scans of the packages' latest APKs, as output by
+	// 'wolfictl scan --output json'. Only packages with a scanned APK are
+	// reconciled. The scans must be unfiltered (see scan.JSONResult.Filters).
+	ScanResults []scan.JSONResult
+
+	// Justification is the justification of the proposed events. If empty,
+	// DefaultReconcileJustification is used.
+	Justification vex.Justification
+
+	// Impact is the impact statement of the proposed events. If empty, a
+	// statement naming the scanned version is used.
+	Impact string
+
+	// Now is the timestamp of the proposed events. If zero, the current time is
+	// used.
+	Now time.Time
+}
+
+// Reconcile finds the advisories still under investigation whose vulnerability
+// the scanner no longer detects in the package, e.g. after a correction to the
+// scanner's vulnerability data, and returns a request for each to resolve it as
+// not_affected. A vulnerability counts as detected if any finding for any APK
+// built from the package (i.e. the package or its subpackages) has the
+// advisory's vulnerability ID or one of its aliases, under any of the finding's
+// IDs. Requests are sorted by package and then vulnerability.
+//
+// An error is returned if any scan result was filtered, since a vulnerability
+// missing from it may still have been detected.
+func Reconcile(opts ReconcileOptions) ([]Request, error) {
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	justification := opts.Justification
+	if justification == "" {
+		justification = DefaultReconcileJustification
+	}
+
+	// detected maps each scanned package to the IDs of the vulnerabilities found
+	// in it, and versions to the latest version of it that was scanned.
+	detected := make(map[string]map[string]struct{})
+	versions := make(map[string]string)
+	for _, result := range opts.ScanResults {
+		if result.APK == nil {
+			continue
+		}
+
+		if len(result.Filters) > 0 {
+			return nil, fmt.Errorf("scan result for %s was filtered (by %s), so it may leave out vulnerabilities that are still detected: reconcile requires unfiltered scan results", result.Target, strings.Join(result.Filters, ", "))
+		}
+
+		pkg := result.APK.Origin
+		if pkg == "" {
+			pkg = result.APK.Name
+		}
+
+		if detected[pkg] == nil {
+			detected[pkg] = make(map[string]struct{})
+		}
+		if v := versions[pkg]; v == "" || versionLess(v, result.APK.Version) {
+			versions[pkg] = result.APK.Version
+		}
+
+		for _, f := range result.Findings {
+			detected[pkg][f.Vulnerability.ID] = struct{}{}
+			for _, alias := range f.Vulnerability.Aliases {
+				detected[pkg][alias] = struct{}{}
+			}
+		}
+	}
+
+	aliases := make(map[string]advisoryconfigs.Aliases)
+	for _, doc := range opts.AdvisoryCfgs.Select().Configurations() {
+		aliases[doc.Name()] = doc.Aliases
+	}
+
+	var reqs []Request
+	for _, a := range List(opts.AdvisoryCfgs, ListFilter{Statuses: []vex.Status{vex.StatusUnderInvestigation}}) {
+		found, scanned := detected[a.Package]
+		if !scanned {
+			continue
+		}
+
+		stillDetected := false
+		for _, id := range append([]string{a.Vulnerability}, aliases[a.Package][a.Vulnerability]...) {
+			if _, ok := found[id]; ok {
+				stillDetected = true
+				break
+			}
+		}
+		if stillDetected {
+			continue
+		}
+
+		impact := opts.Impact
+		if impact == "" {
+			impact = fmt.Sprintf("No longer detected by the vulnerability scanner in version %s, following a correction to the scanner's vulnerability data.", versions[a.Package])
+		}
+
+		reqs = append(reqs, Request{
+			Package:       a.Package,
+			Vulnerability: a.Vulnerability,
+			Status:        vex.StatusNotAffected,
+			Justification: justification,
+			Impact:        impact,
+			Timestamp:     now,
+		})
+	}
+
+	return reqs, nil
+}
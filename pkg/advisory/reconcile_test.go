@@ -0,0 +1,99 @@
+package advisory
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/openvex/go-vex/pkg/vex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
+	"github.com/wolfi-dev/wolfictl/pkg/scan"
+)
+
+func TestReconcile(t *testing.T) {
+	const foo = `package:
+  name: foo
+
+advisories:
+  CVE-2023-1:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: under_investigation
+  CVE-2023-2:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: under_investigation
+  CVE-2023-3:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: under_investigation
+  CVE-2023-4:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: affected
+      action: upgrade to 2.0
+
+aliases:
+  CVE-2023-3:
+    - GHSA-2222-3333-4444
+`
+	const bar = `package:
+  name: bar
+
+advisories:
+  CVE-2023-5:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: under_investigation
+`
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "foo.advisories.yaml"), []byte(foo), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bar.advisories.yaml"), []byte(bar), 0o644))
+
+	index, err := advisoryconfigs.NewIndex(rwos.DirFS(dir))
+	require.NoError(t, err)
+
+	finding := func(id string, aliases ...string) *scan.Finding {
+		return &scan.Finding{Vulnerability: scan.Vulnerability{ID: id, Aliases: aliases}}
+	}
+
+	// CVE-2023-1 is still found in a subpackage, and CVE-2023-3 under its GHSA
+	// alias. bar wasn't scanned, so its advisory is left alone.
+	results := []scan.JSONResult{
+		{Target: "foo-1.2.3-r1.apk", APK: &scan.PackageInfo{Name: "foo", Version: "1.2.3-r1"}, Findings: []*scan.Finding{finding("GHSA-2222-3333-4444")}},
+		{Target: "foo-dev-1.2.3-r1.apk", APK: &scan.PackageInfo{Name: "foo-dev", Origin: "foo", Version: "1.2.3-r1"}, Findings: []*scan.Finding{finding("GHSA-5555-6666-7777", "CVE-2023-1")}},
+	}
+
+	now := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	reqs, err := Reconcile(ReconcileOptions{AdvisoryCfgs: index, ScanResults: results, Now: now})
+	require.NoError(t, err)
+	assert.Equal(t, []Request{
+		{
+			Package:       "foo",
+			Vulnerability: "CVE-2023-2",
+			Status:        vex.StatusNotAffected,
+			Justification: vex.VulnerableCodeNotPresent,
+			Impact:        "No longer detected by the vulnerability scanner in version 1.2.3-r1, following a correction to the scanner's vulnerability data.",
+			Timestamp:     now,
+		},
+	}, reqs)
+	require.NoError(t, reqs[0].Validate())
+
+	reqs, err = Reconcile(ReconcileOptions{
+		AdvisoryCfgs:  index,
+		ScanResults:   results[:1],
+		Justification: vex.ComponentNotPresent,
+		Impact:        "DB correction",
+		Now:           now,
+	})
+	require.NoError(t, err)
+	require.Len(t, reqs, 2)
+	assert.Equal(t, "CVE-2023-1", reqs[0].Vulnerability)
+	assert.Equal(t, vex.ComponentNotPresent, reqs[1].Justification)
+	assert.Equal(t, "DB correction", reqs[1].Impact)
+
+	// A filtered scan may leave out vulnerabilities that are still detected.
+	results[0].Filters = []string{"baseline"}
+	_, err = Reconcile(ReconcileOptions{AdvisoryCfgs: index, ScanResults: results, Now: now})
+	assert.ErrorContains(t, err, "filtered (by baseline)")
+}
@@ -0,0 +1,173 @@
+package advisory
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/samber/lo"
+	"github.com/wolfi-dev/wolfictl/pkg/configs"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+)
+
+const (
+	fieldJustification = "justification"
+	fieldImpact        = "impact"
+	fieldAction        = "action"
+
+	fieldSeverityOverride = "severity-override"
+	fieldReferences       = "references"
+)
+
+// RedactableFields are the fields of an advisory event that can be marked as
+// internal-only.
+var RedactableFields = []string{fieldJustification, fieldImpact, fieldAction, fieldSeverityOverride, fieldReferences}
+
+// PublicEntries returns the given advisory events as they should appear in data
+// exported for public consumption: events marked as internal are dropped, and
+// fields marked as internal are blanked. The given entries aren't modified.
+//
+// Every exporter must use PublicEntries (or PublicDocument) rather than reading
+// advisory events directly.
+func PublicEntries(entries []advisoryconfigs.Entry) []advisoryconfigs.Entry {
+	public := make([]advisoryconfigs.Entry, 0, len(entries))
+	for _, e := range entries {
+		if e.Internal {
+			continue
+		}
+
+		for _, field := range e.InternalFields {
+			switch field {
+			case fieldJustification:
+				e.Justification = ""
+			case fieldImpact:
+				e.ImpactStatement = ""
+			case fieldAction:
+				e.ActionStatement = ""
+			case fieldSeverityOverride:
+				e.SeverityOverride = nil
+			case fieldReferences:
+				e.References = nil
+			}
+		}
+		e.InternalFields = nil
+
+		public = append(public, e)
+	}
+
+	return public
+}
+
+// PublicDocument returns a copy of the given advisory document with
+// PublicEntries applied to each advisory. Advisories left with no events are
+// dropped.
+func PublicDocument(doc advisoryconfigs.Document) advisoryconfigs.Document {
+	advisories := make(advisoryconfigs.Advisories, len(doc.Advisories))
+	for id, entries := range doc.Advisories {
+		public := PublicEntries(entries)
+		if len(public) == 0 {
+			continue
+		}
+		advisories[id] = public
+	}
+
+	return advisoryconfigs.Document{
+		SchemaVersion: doc.SchemaVersion,
+		Package:       doc.Package,
+		Archived:      doc.Archived,
+		Advisories:    advisories,
+		Aliases:       doc.Aliases,
+	}
+}
+
+// RedactionLeak is internal-only advisory data found in an exported artifact.
+type RedactionLeak struct {
+	Package       string
+	Vulnerability string
+	Field         string
+	Value         string
+}
+
+func (l RedactionLeak) String() string {
+	return fmt.Sprintf("%s: %s: internal %s %q", l.Package, l.Vulnerability, l.Field, l.Value)
+}
+
+// VerifyRedaction checks that none of the internal-only free-text values (i.e.
+// impact and action statements, and severity override rationales) recorded in the given advisory data appear in
+// the given exported artifact. It returns each leaked value found.
+//
+// Values that also appear in public advisory data are not considered leaks.
+func VerifyRedaction(artifact []byte, indices []*configs.Index[advisoryconfigs.Document]) []RedactionLeak {
+	var internal []RedactionLeak
+	publicValues := make(map[string]struct{})
+
+	for _, index := range indices {
+		for _, doc := range index.Select().Configurations() {
+			ids := lo.Keys(doc.Advisories)
+			sort.Strings(ids)
+
+			for _, id := range ids {
+				for _, e := range doc.Advisories[id] {
+					var rationale string
+					if e.SeverityOverride != nil {
+						rationale = e.SeverityOverride.Rationale
+					}
+
+					for field, value := range map[string]string{fieldImpact: e.ImpactStatement, fieldAction: e.ActionStatement, fieldSeverityOverride: rationale} {
+						if strings.TrimSpace(value) == "" {
+							continue
+						}
+
+						if e.Internal || lo.Contains(e.InternalFields, field) {
+							internal = append(internal, RedactionLeak{
+								Package:       doc.Package.Name,
+								Vulnerability: id,
+								Field:         field,
+								Value:         value,
+							})
+							continue
+						}
+
+						publicValues[value] = struct{}{}
+					}
+				}
+			}
+		}
+	}
+
+	var leaks []RedactionLeak
+	for _, candidate := range internal {
+		if _, ok := publicValues[candidate.Value]; ok {
+			continue
+		}
+
+		if containsValue(artifact, candidate.Value) {
+			leaks = append(leaks, candidate)
+		}
+	}
+
+	sort.SliceStable(leaks, func(i, j int) bool {
+		return leaks[i].String() < leaks[j].String()
+	})
+
+	return leaks
+}
+
+// containsValue reports whether the value appears in the artifact, either
+// verbatim or in the escaped forms used by JSON and CSV encoding.
+func containsValue(artifact []byte, value string) bool {
+	if bytes.Contains(artifact, []byte(value)) {
+		return true
+	}
+
+	if encoded, err := json.Marshal(value); err == nil {
+		// Strip the surrounding quotes.
+		if bytes.Contains(artifact, encoded[1:len(encoded)-1]) {
+			return true
+		}
+	}
+
+	return bytes.Contains(artifact, []byte(strings.ReplaceAll(value, `"`, `""`)))
+}
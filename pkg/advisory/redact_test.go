@@ -0,0 +1,76 @@
+package advisory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openvex/go-vex/pkg/vex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wolfi-dev/wolfictl/pkg/configs"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
+)
+
+func TestPublicEntries(t *testing.T) {
+	t0 := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	entries := []advisoryconfigs.Entry{
+		{
+			Timestamp: t0,
+			Status:    vex.StatusUnderInvestigation,
+		},
+		{
+			Timestamp:       t0.Add(time.Hour),
+			Status:          vex.StatusAffected,
+			ActionStatement: "waiting on the customer escalation in ticket 1234",
+			Internal:        true,
+		},
+		{
+			Timestamp:       t0.Add(2 * time.Hour),
+			Status:          vex.StatusNotAffected,
+			Justification:   vex.VulnerableCodeNotInExecutePath,
+			ImpactStatement: "internal analysis notes",
+			InternalFields:  []string{"impact"},
+		},
+	}
+
+	public := PublicEntries(entries)
+
+	expected := []advisoryconfigs.Entry{
+		{
+			Timestamp: t0,
+			Status:    vex.StatusUnderInvestigation,
+		},
+		{
+			Timestamp:     t0.Add(2 * time.Hour),
+			Status:        vex.StatusNotAffected,
+			Justification: vex.VulnerableCodeNotInExecutePath,
+		},
+	}
+
+	assert.Equal(t, expected, public)
+
+	// The input must not be modified.
+	assert.Equal(t, "internal analysis notes", entries[2].ImpactStatement)
+}
+
+func TestVerifyRedaction(t *testing.T) {
+	index, err := advisoryconfigs.NewIndex(rwos.DirFS("./testdata/redact/advisories"))
+	require.NoError(t, err)
+
+	indices := []*configs.Index[advisoryconfigs.Document]{index}
+
+	clean := []byte(`package,advisory,status
+foo,CVE-2023-0001,not_affected,shared text
+`)
+	assert.Empty(t, VerifyRedaction(clean, indices))
+
+	leakyCSV := []byte(`foo,CVE-2023-0001,affected,"customer ""acme"" is blocked on this"`)
+	leaks := VerifyRedaction(leakyCSV, indices)
+	require.Len(t, leaks, 1)
+	assert.Equal(t, "action", leaks[0].Field)
+
+	leakyJSON := []byte(`{"action": "customer \"acme\" is blocked on this"}`)
+	assert.Len(t, VerifyRedaction(leakyJSON, indices), 1)
+}
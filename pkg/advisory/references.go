@@ -0,0 +1,126 @@
+package advisory
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/samber/lo"
+	"github.com/wolfi-dev/wolfictl/pkg/configs"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	"golang.org/x/exp/slices"
+)
+
+// ParseReference parses a reference given as "<type>=<url>", e.g.
+// "fix-commit=https://github.com/o/r/commit/abc".
+func ParseReference(s string) (advisoryconfigs.Reference, error) {
+	typ, u, ok := strings.Cut(s, "=")
+	if !ok {
+		return advisoryconfigs.Reference{}, fmt.Errorf("reference %q must be given as <type>=<url>", s)
+	}
+
+	ref := advisoryconfigs.Reference{Type: advisoryconfigs.ReferenceType(typ), URL: u}
+	if err := validateReference(ref); err != nil {
+		return advisoryconfigs.Reference{}, err
+	}
+
+	return ref, nil
+}
+
+// ReferencesOf returns the references recorded by all of the given events of an
+// advisory, oldest first, without duplicates.
+func ReferencesOf(entries []advisoryconfigs.Entry) []advisoryconfigs.Reference {
+	items := make([]advisoryconfigs.Entry, len(entries))
+	copy(items, entries)
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Timestamp.Before(items[j].Timestamp)
+	})
+
+	var refs []advisoryconfigs.Reference
+	for _, e := range items {
+		for _, ref := range e.References {
+			if !slices.Contains(refs, ref) {
+				refs = append(refs, ref)
+			}
+		}
+	}
+
+	return refs
+}
+
+// AddReferenceOptions configures the AddReference operation.
+type AddReferenceOptions struct {
+	// AdvisoryCfgs is the Index of advisories on which to operate.
+	AdvisoryCfgs *configs.Index[advisoryconfigs.Document]
+
+	Package       string
+	Vulnerability string
+	Reference     advisoryconfigs.Reference
+}
+
+// AddReference appends a reference to the latest event of an existing advisory.
+// Since a reference adds context rather than changing the advisory's status, no
+// new event is recorded. Adding a reference that the event already has does
+// nothing.
+func AddReference(opts AddReferenceOptions) error {
+	if err := validateReference(opts.Reference); err != nil {
+		return err
+	}
+
+	advisoryCfgs := opts.AdvisoryCfgs.Select().WhereName(opts.Package)
+	if count := advisoryCfgs.Len(); count != 1 {
+		return fmt.Errorf("cannot add reference: found %d advisory documents for package %q", count, opts.Package)
+	}
+
+	u := advisoryconfigs.NewAdvisoriesSectionUpdater(func(cfg advisoryconfigs.Document) (advisoryconfigs.Advisories, error) {
+		advisories := cfg.Advisories
+		entries := advisories[opts.Vulnerability]
+		if len(entries) == 0 {
+			return advisoryconfigs.Advisories{}, fmt.Errorf("no advisory exists for %s", opts.Vulnerability)
+		}
+
+		latest := 0
+		for i, e := range entries {
+			if !e.Timestamp.Before(entries[latest].Timestamp) {
+				latest = i
+			}
+		}
+
+		if !slices.Contains(entries[latest].References, opts.Reference) {
+			entries[latest].References = append(entries[latest].References, opts.Reference)
+		}
+		advisories[opts.Vulnerability] = entries
+
+		return advisories, nil
+	})
+	if err := advisoryCfgs.Update(u); err != nil {
+		return fmt.Errorf("unable to add reference to advisory %q in %q: %w", opts.Vulnerability, opts.Package, err)
+	}
+
+	return nil
+}
+
+func validateReferences(refs []advisoryconfigs.Reference) error {
+	for _, ref := range refs {
+		if err := validateReference(ref); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateReference(ref advisoryconfigs.Reference) error {
+	if !slices.Contains(advisoryconfigs.ReferenceTypes, ref.Type) {
+		types := lo.Map(advisoryconfigs.ReferenceTypes, func(t advisoryconfigs.ReferenceType, _ int) string { return string(t) })
+		return fmt.Errorf("reference type is %q but must be one of [%s]", ref.Type, strings.Join(types, ", "))
+	}
+
+	u, err := url.Parse(ref.URL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return fmt.Errorf("reference URL %q must be an http(s) URL", ref.URL)
+	}
+
+	return nil
+}
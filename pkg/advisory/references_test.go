@@ -0,0 +1,126 @@
+package advisory
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wolfi-dev/wolfictl/pkg/configs"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
+)
+
+func TestParseReference(t *testing.T) {
+	ref, err := ParseReference("fix-commit=https://github.com/o/r/commit/abc")
+	require.NoError(t, err)
+	assert.Equal(t, advisoryconfigs.Reference{Type: advisoryconfigs.ReferenceFixCommit, URL: "https://github.com/o/r/commit/abc"}, ref)
+
+	_, err = ParseReference("https://github.com/o/r/commit/abc")
+	assert.ErrorContains(t, err, "must be given as <type>=<url>")
+
+	_, err = ParseReference("patch=https://github.com/o/r/commit/abc")
+	assert.ErrorContains(t, err, `reference type is "patch"`)
+
+	_, err = ParseReference("other=github.com/o/r")
+	assert.ErrorContains(t, err, "must be an http(s) URL")
+}
+
+func TestReferencesOf(t *testing.T) {
+	fix := advisoryconfigs.Reference{Type: advisoryconfigs.ReferenceFixCommit, URL: "https://github.com/o/r/commit/abc"}
+	pr := advisoryconfigs.Reference{Type: advisoryconfigs.ReferenceDistroPR, URL: "https://github.com/wolfi-dev/os/pull/1"}
+
+	entries := []advisoryconfigs.Entry{
+		{Timestamp: time.Date(2023, 5, 2, 0, 0, 0, 0, time.UTC), References: []advisoryconfigs.Reference{pr, fix}},
+		{Timestamp: time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC), References: []advisoryconfigs.Reference{fix}},
+	}
+
+	assert.Equal(t, []advisoryconfigs.Reference{fix, pr}, ReferencesOf(entries))
+	assert.Nil(t, ReferencesOf(nil))
+}
+
+func TestAddReference(t *testing.T) {
+	const foo = `package:
+  name: foo
+
+advisories:
+  CVE-2023-0001:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: under_investigation
+    - timestamp: 2023-05-02T00:00:00Z
+      status: fixed
+      fixed-version: 1.2.3-r1
+`
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "foo.advisories.yaml"), []byte(foo), 0o644))
+
+	index, err := advisoryconfigs.NewIndex(rwos.DirFS(dir))
+	require.NoError(t, err)
+
+	ref := advisoryconfigs.Reference{Type: advisoryconfigs.ReferenceDistroPR, URL: "https://github.com/wolfi-dev/os/pull/1"}
+	opts := AddReferenceOptions{AdvisoryCfgs: index, Package: "foo", Vulnerability: "CVE-2023-0001", Reference: ref}
+	require.NoError(t, AddReference(opts))
+	require.NoError(t, AddReference(opts))
+
+	entries := index.Select().WhereName("foo").Configurations()[0].Advisories["CVE-2023-0001"]
+	require.Len(t, entries, 2)
+	assert.Empty(t, entries[0].References)
+	assert.Equal(t, []advisoryconfigs.Reference{ref}, entries[1].References)
+	assert.Nil(t, Validate(ValidateOptions{AdvisoryCfgs: index}))
+
+	opts.Vulnerability = "CVE-2023-0002"
+	assert.ErrorContains(t, AddReference(opts), "no advisory exists for CVE-2023-0002")
+}
+
+func TestExportReferences(t *testing.T) {
+	const foo = `package:
+  name: foo
+
+advisories:
+  CVE-2023-0001:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: fixed
+      fixed-version: 1.2.3-r1
+      references:
+        - type: fix-commit
+          url: https://github.com/o/r/commit/abc
+        - type: distro-pr
+          url: https://github.com/wolfi-dev/os/pull/1
+      internal-fields:
+        - references
+  CVE-2023-0002:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: affected
+      action: wait for upstream fix
+      references:
+        - type: upstream-advisory
+          url: https://example.com/advisories/2
+`
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "foo.advisories.yaml"), []byte(foo), 0o644))
+
+	index, err := advisoryconfigs.NewIndex(rwos.DirFS(dir))
+	require.NoError(t, err)
+	assert.Nil(t, Validate(ValidateOptions{AdvisoryCfgs: index}))
+
+	osvs, err := ExportOSV(ExportOSVOptions{AdvisoryCfgIndices: []*configs.Index[advisoryconfigs.Document]{index}, Ecosystem: "Wolfi"})
+	require.NoError(t, err)
+	require.Len(t, osvs, 2)
+	assert.Empty(t, osvs[0].References)
+	assert.Equal(t, []OSVReference{{Type: "ADVISORY", URL: "https://example.com/advisories/2"}}, osvs[1].References)
+
+	csaf, err := ExportCSAF(ExportCSAFOptions{
+		AdvisoryCfgIndices: []*configs.Index[advisoryconfigs.Document]{index},
+		ProductNamespace:   "wolfi",
+		PublisherName:      "Wolfi",
+		PublisherNamespace: "https://wolfi.dev",
+	})
+	require.NoError(t, err)
+	require.Len(t, csaf.Vulnerabilities, 2)
+	assert.Empty(t, csaf.Vulnerabilities[0].References)
+	assert.Equal(t, []CSAFReference{{Category: "external", Summary: "Upstream advisory", URL: "https://example.com/advisories/2"}}, csaf.Vulnerabilities[1].References)
+}
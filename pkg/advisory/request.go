@@ -2,10 +2,13 @@ package advisory
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/openvex/go-vex/pkg/vex"
 	"github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	"golang.org/x/exp/slices"
 )
 
 // Request specifies the parameters for creating a new advisory or updating an existing advisory.
@@ -14,10 +17,33 @@ type Request struct {
 	Vulnerability string
 	Status        vex.Status
 	Action        string
-	Impact        string
+
+	// Impact is an optional note explaining why the package is not affected. It's
+	// only valid with the not_affected status.
+	Impact string
+
+	// Justification is the reason the package is not affected, as one of the
+	// OpenVEX justifications (see vex.Justifications). It's required with the
+	// not_affected status, and only valid with it.
 	Justification vex.Justification
-	FixedVersion  string
-	Timestamp     time.Time
+
+	FixedVersion string
+	Timestamp    time.Time
+
+	// SeverityOverride is the distro's own assessment of the vulnerability's
+	// severity in the package, if any. It's valid with any status.
+	SeverityOverride *advisory.SeverityOverride
+
+	// Detection is the provenance of the scanner finding that the request was
+	// made for, if any.
+	Detection *advisory.Detection
+
+	// PendingUpstreamFix is the upstream fix that the package is waiting for, if
+	// any. It's only valid with the affected status.
+	PendingUpstreamFix *advisory.PendingUpstreamFix
+
+	// References are links to context for the advisory event, if any.
+	References []advisory.Reference
 }
 
 // Validate returns an error if the Request is invalid.
@@ -47,18 +73,46 @@ func (req Request) Validate() error {
 		if req.Justification == "" {
 			return errors.New("justification cannot be empty if status is 'not affected'")
 		}
+		if !slices.Contains(vex.Justifications(), string(req.Justification)) {
+			return fmt.Errorf("justification %q is not valid, must be one of: %s", req.Justification, strings.Join(vex.Justifications(), ", "))
+		}
+	}
+
+	if req.Status != vex.StatusNotAffected {
+		if req.Justification != "" {
+			return errors.New("justification must be empty if status is not 'not affected'")
+		}
+		if req.Impact != "" {
+			return errors.New("impact must be empty if status is not 'not affected'")
+		}
+	}
+
+	if err := validateSeverityOverride(req.SeverityOverride); err != nil {
+		return err
+	}
+
+	if err := validateDetection(req.Detection); err != nil {
+		return err
+	}
+
+	if err := validatePendingUpstreamFix(req.PendingUpstreamFix, req.Status); err != nil {
+		return err
 	}
 
-	return nil
+	return validateReferences(req.References)
 }
 
 func (req Request) toAdvisoryEntry() advisory.Entry {
 	return advisory.Entry{
-		Timestamp:       req.Timestamp,
-		Status:          req.Status,
-		Justification:   req.Justification,
-		ImpactStatement: req.Impact,
-		ActionStatement: req.Action,
-		FixedVersion:    req.FixedVersion,
+		Timestamp:          req.Timestamp,
+		Status:             req.Status,
+		Justification:      req.Justification,
+		ImpactStatement:    req.Impact,
+		ActionStatement:    req.Action,
+		FixedVersion:       req.FixedVersion,
+		SeverityOverride:   req.SeverityOverride,
+		Detection:          req.Detection,
+		PendingUpstreamFix: req.PendingUpstreamFix,
+		References:         req.References,
 	}
 }
@@ -0,0 +1,38 @@
+package advisory
+
+import (
+	"testing"
+
+	"github.com/openvex/go-vex/pkg/vex"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequest_Validate(t *testing.T) {
+	base := Request{Package: "foo", Vulnerability: "CVE-2023-1"}
+
+	notAffected := base
+	notAffected.Status = vex.StatusNotAffected
+	notAffected.Justification = vex.VulnerableCodeNotInExecutePath
+	assert.NoError(t, notAffected.Validate())
+
+	notAffected.Impact = "The vulnerable function is never called."
+	assert.NoError(t, notAffected.Validate())
+
+	notAffected.Justification = ""
+	assert.EqualError(t, notAffected.Validate(), "justification cannot be empty if status is 'not affected'")
+
+	notAffected.Justification = "false positive, see upstream issue"
+	assert.ErrorContains(t, notAffected.Validate(), `justification "false positive, see upstream issue" is not valid, must be one of: component_not_present`)
+
+	fixed := base
+	fixed.Status = vex.StatusFixed
+	fixed.FixedVersion = "1.2.3-r1"
+	assert.NoError(t, fixed.Validate())
+
+	fixed.Justification = vex.ComponentNotPresent
+	assert.EqualError(t, fixed.Validate(), "justification must be empty if status is not 'not affected'")
+
+	fixed.Justification = ""
+	fixed.Impact = "not affected after all"
+	assert.EqualError(t, fixed.Validate(), "impact must be empty if status is not 'not affected'")
+}
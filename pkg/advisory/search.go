@@ -0,0 +1,161 @@
+package advisory
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/wolfi-dev/wolfictl/pkg/configs"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+)
+
+// Fields of an advisory that Search matches query terms against.
+const (
+	SearchFieldVulnerability = "vulnerability"
+	SearchFieldAlias         = "alias"
+	SearchFieldPackage       = "package"
+	SearchFieldJustification = "justification"
+	SearchFieldImpact        = "impact"
+	SearchFieldAction        = "action"
+)
+
+// searchFieldWeights are the scores of a term matching each field. An exact
+// match scores double.
+var searchFieldWeights = map[string]int{
+	SearchFieldVulnerability: 50,
+	SearchFieldAlias:         40,
+	SearchFieldPackage:       20,
+	SearchFieldJustification: 10,
+	SearchFieldImpact:        5,
+	SearchFieldAction:        5,
+}
+
+// SearchOptions configures the Search operation.
+type SearchOptions struct {
+	// AdvisoryCfgs is the Index of advisories to search.
+	AdvisoryCfgs *configs.Index[advisoryconfigs.Document]
+
+	// Query is split into whitespace-separated terms, all of which must match an
+	// advisory (case-insensitively) for it to be a result.
+	Query string
+
+	// Limit is the most results returned. If zero, all results are returned.
+	Limit int
+}
+
+// SearchResult is an advisory that matches a search query.
+type SearchResult struct {
+	ListedAdvisory
+
+	// Score ranks the result: the higher, the better the advisory matches.
+	Score int
+
+	// Matches are the values of the advisory's fields that matched a query term.
+	Matches []SearchMatch
+}
+
+// SearchMatch is the value of an advisory's field that matched a query term.
+type SearchMatch struct {
+	Field string
+	Value string
+}
+
+// Search returns the advisories that match the query, in the vulnerability IDs
+// they're recorded under, their aliases, their package names, and the
+// justifications, impact statements, and action statements of their events.
+// Results are sorted by descending score, then by package and vulnerability.
+func Search(opts SearchOptions) []SearchResult {
+	terms := strings.Fields(strings.ToLower(opts.Query))
+	if len(terms) == 0 {
+		return nil
+	}
+
+	aliasesByPackage := make(map[string]advisoryconfigs.Aliases)
+	for _, doc := range opts.AdvisoryCfgs.Select().Configurations() {
+		aliasesByPackage[doc.Package.Name] = doc.Aliases
+	}
+
+	var results []SearchResult
+	for _, a := range List(opts.AdvisoryCfgs, ListFilter{}) {
+		fields := searchableFields(a, aliasesByPackage[a.Package][a.Vulnerability])
+
+		r := SearchResult{ListedAdvisory: a}
+		matched := make(map[SearchMatch]bool)
+		for _, term := range terms {
+			termScore := 0
+			for _, f := range fields {
+				value := strings.ToLower(f.Value)
+				if !strings.Contains(value, term) {
+					continue
+				}
+
+				score := searchFieldWeights[f.Field]
+				if value == term {
+					score *= 2
+				}
+				if score > termScore {
+					termScore = score
+				}
+
+				if !matched[f] {
+					matched[f] = true
+					r.Matches = append(r.Matches, f)
+				}
+			}
+
+			if termScore == 0 {
+				r.Score = 0
+				break
+			}
+			r.Score += termScore
+		}
+
+		if r.Score > 0 {
+			results = append(results, r)
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+
+	if opts.Limit > 0 && len(results) > opts.Limit {
+		results = results[:opts.Limit]
+	}
+
+	return results
+}
+
+// searchableFields returns the values of the advisory's fields that Search
+// matches against, without duplicates.
+func searchableFields(a ListedAdvisory, aliases []string) []SearchMatch {
+	fields := []SearchMatch{
+		{SearchFieldVulnerability, a.Vulnerability},
+		{SearchFieldPackage, a.Package},
+	}
+	for _, alias := range aliases {
+		fields = append(fields, SearchMatch{SearchFieldAlias, alias})
+	}
+
+	for _, e := range a.Entries {
+		for _, f := range []SearchMatch{
+			{SearchFieldJustification, string(e.Justification)},
+			{SearchFieldImpact, e.ImpactStatement},
+			{SearchFieldAction, e.ActionStatement},
+		} {
+			if f.Value != "" {
+				fields = append(fields, f)
+			}
+		}
+	}
+
+	seen := make(map[SearchMatch]bool, len(fields))
+	unique := fields[:0]
+	for _, f := range fields {
+		if !seen[f] {
+			seen[f] = true
+			unique = append(unique, f)
+		}
+	}
+
+	return unique
+}
@@ -0,0 +1,73 @@
+package advisory
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
+)
+
+func TestSearch(t *testing.T) {
+	const foo = `package:
+  name: foo
+
+advisories:
+  CVE-2023-1:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: not_affected
+      justification: vulnerable_code_not_in_execute_path
+      impact: The vulnerable parser is only used by the test suite.
+  CVE-2023-2:
+    - timestamp: 2023-05-02T00:00:00Z
+      status: affected
+      action: Waiting for the upstream parser fix.
+
+aliases:
+  CVE-2023-2:
+    - GHSA-abcd-efgh-ijkl
+`
+	const parser = `package:
+  name: parser
+
+advisories:
+  CVE-2023-3:
+    - timestamp: 2023-05-03T00:00:00Z
+      status: under_investigation
+`
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "foo.advisories.yaml"), []byte(foo), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "parser.advisories.yaml"), []byte(parser), 0o644))
+
+	index, err := advisoryconfigs.NewIndex(rwos.DirFS(dir))
+	require.NoError(t, err)
+
+	search := func(query string, limit int) []string {
+		var got []string
+		for _, r := range Search(SearchOptions{AdvisoryCfgs: index, Query: query, Limit: limit}) {
+			got = append(got, r.Vulnerability)
+		}
+		return got
+	}
+
+	// An exact package name match ranks above matches in the notes.
+	assert.Equal(t, []string{"CVE-2023-3", "CVE-2023-1", "CVE-2023-2"}, search("parser", 0))
+	assert.Equal(t, []string{"CVE-2023-3"}, search("parser", 1))
+
+	// Aliases are matched.
+	assert.Equal(t, []string{"CVE-2023-2"}, search("ghsa-abcd", 0))
+
+	// All terms must match.
+	assert.Equal(t, []string{"CVE-2023-1"}, search("parser execute_path", 0))
+	assert.Empty(t, search("parser nonexistent", 0))
+	assert.Empty(t, search("  ", 0))
+
+	results := Search(SearchOptions{AdvisoryCfgs: index, Query: "CVE-2023-1"})
+	require.Len(t, results, 1)
+	assert.Equal(t, 100, results[0].Score)
+	assert.Equal(t, []SearchMatch{{SearchFieldVulnerability, "CVE-2023-1"}}, results[0].Matches)
+}
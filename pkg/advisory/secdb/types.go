@@ -15,6 +15,12 @@ type PackageEntry struct {
 type Package struct {
 	Name     string   `json:"name"`
 	Secfixes Secfixes `json:"secfixes"`
+
+	// Severities maps vulnerability IDs to the distro's own assessment of their
+	// severity in the package, where it overrides the upstream severity. It's an
+	// extension to the Alpine secdb format, which consumers that don't know it
+	// ignore.
+	Severities map[string]string `json:"severities,omitempty"`
 }
 
 type Secfixes map[string][]string
@@ -0,0 +1,81 @@
+package advisory
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/wolfi-dev/wolfictl/pkg/configs"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	"github.com/wolfi-dev/wolfictl/pkg/scan"
+	"golang.org/x/exp/slices"
+)
+
+// cvssV3VectorPattern matches CVSS v3.0 and v3.1 vector strings.
+var cvssV3VectorPattern = regexp.MustCompile(`^CVSS:3\.[01](/[A-Za-z]{1,3}:[A-Za-z])+$`)
+
+// SeverityOverrideOf returns the severity override in effect for an advisory
+// with the given events, i.e. the one recorded by the latest event that records
+// one. If no event does, it returns nil.
+func SeverityOverrideOf(entries []advisoryconfigs.Entry) *advisoryconfigs.SeverityOverride {
+	items := make([]advisoryconfigs.Entry, len(entries))
+	copy(items, entries)
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Timestamp.Before(items[j].Timestamp)
+	})
+
+	for i := len(items) - 1; i >= 0; i-- {
+		if o := items[i].SeverityOverride; o != nil {
+			return o
+		}
+	}
+
+	return nil
+}
+
+// SeverityOverrideForPackage returns the severity override in effect for the
+// named package under any of the given vulnerability IDs (see
+// SeverityOverrideOf), or nil if there isn't one.
+func SeverityOverrideForPackage(cfgs *configs.Index[advisoryconfigs.Document], packageName string, vulnIDs ...string) *advisoryconfigs.SeverityOverride {
+	var entries []advisoryconfigs.Entry
+	for _, doc := range cfgs.Select().WhereName(packageName).Configurations() {
+		for _, id := range vulnIDs {
+			entries = append(entries, doc.Advisories[id]...)
+		}
+	}
+
+	return SeverityOverrideOf(entries)
+}
+
+// describeSeverityOverride describes the severity override for consumers of
+// exported advisory data.
+func describeSeverityOverride(o advisoryconfigs.SeverityOverride) string {
+	severity := o.Severity
+	if o.CVSS != "" {
+		severity = fmt.Sprintf("%s (%s)", o.Severity, o.CVSS)
+	}
+
+	return fmt.Sprintf("Severity assessed as %s for this package: %s", severity, o.Rationale)
+}
+
+func validateSeverityOverride(o *advisoryconfigs.SeverityOverride) error {
+	if o == nil {
+		return nil
+	}
+
+	if !slices.Contains(scan.Severities, o.Severity) {
+		return fmt.Errorf("severity override is %q but must be one of [%s]", o.Severity, strings.Join(scan.Severities, ", "))
+	}
+
+	if o.CVSS != "" && !cvssV3VectorPattern.MatchString(o.CVSS) {
+		return fmt.Errorf("severity override's CVSS %q is not a valid CVSS v3 vector", o.CVSS)
+	}
+
+	if strings.TrimSpace(o.Rationale) == "" {
+		return errors.New("severity override must have a rationale")
+	}
+
+	return nil
+}
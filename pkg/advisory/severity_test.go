@@ -0,0 +1,94 @@
+package advisory
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wolfi-dev/wolfictl/pkg/advisory/secdb"
+	"github.com/wolfi-dev/wolfictl/pkg/configs"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
+)
+
+func TestSeverityOverride(t *testing.T) {
+	// The override recorded with the investigation stays in effect once the
+	// advisory is resolved.
+	const foo = `package:
+  name: foo
+
+advisories:
+  CVE-2023-0001:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: under_investigation
+      severity-override:
+        severity: Low
+        cvss: CVSS:3.1/AV:L/AC:H/PR:H/UI:N/S:U/C:L/I:N/A:N
+        rationale: not network reachable as packaged
+    - timestamp: 2023-05-02T00:00:00Z
+      status: fixed
+      fixed-version: 1.2.3-r1
+  CVE-2023-0002:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: affected
+      action: wait for upstream
+`
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "foo.advisories.yaml"), []byte(foo), 0o644))
+
+	cfgs, err := advisoryconfigs.NewIndex(rwos.DirFS(dir))
+	require.NoError(t, err)
+	indices := []*configs.Index[advisoryconfigs.Document]{cfgs}
+
+	want := &advisoryconfigs.SeverityOverride{
+		Severity:  "Low",
+		CVSS:      "CVSS:3.1/AV:L/AC:H/PR:H/UI:N/S:U/C:L/I:N/A:N",
+		Rationale: "not network reachable as packaged",
+	}
+	assert.Equal(t, want, SeverityOverrideForPackage(cfgs, "foo", "GHSA-2222-3333-4444", "CVE-2023-0001"))
+	assert.Nil(t, SeverityOverrideForPackage(cfgs, "foo", "CVE-2023-0002"))
+	assert.Nil(t, Validate(ValidateOptions{AdvisoryCfgs: cfgs}))
+
+	b, err := BuildDatabase(BuildDatabaseOptions{AdvisoryCfgIndices: indices})
+	require.NoError(t, err)
+	var db secdb.Database
+	require.NoError(t, json.Unmarshal(b, &db))
+	require.Len(t, db.Packages, 1)
+	assert.Equal(t, map[string]string{"CVE-2023-0001": "Low"}, db.Packages[0].Pkg.Severities)
+
+	osvs, err := ExportOSV(ExportOSVOptions{AdvisoryCfgIndices: indices, Ecosystem: "Wolfi"})
+	require.NoError(t, err)
+	require.Len(t, osvs, 2)
+	affected := osvs[0].Affected[0]
+	assert.Equal(t, []OSVSeverity{{Type: "CVSS_V3", Score: want.CVSS}}, affected.Severity)
+	assert.Equal(t, "Low", affected.DatabaseSpecific["severity"])
+	assert.Equal(t, want.Rationale, affected.DatabaseSpecific["severity_rationale"])
+	assert.Empty(t, osvs[1].Affected[0].Severity)
+
+	csaf, err := ExportCSAF(ExportCSAFOptions{
+		AdvisoryCfgIndices: indices,
+		ProductNamespace:   "wolfi",
+		PublisherName:      "Wolfi",
+		PublisherNamespace: "https://wolfi.dev",
+		Now:                time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+	assert.Contains(t, csaf.Vulnerabilities[0].Threats, CSAFThreat{
+		Category:   "impact",
+		Details:    "Severity assessed as Low (CVSS:3.1/AV:L/AC:H/PR:H/UI:N/S:U/C:L/I:N/A:N) for this package: not network reachable as packaged",
+		ProductIDs: []string{"foo-1.2.3-r1"},
+	})
+}
+
+func TestValidateSeverityOverride(t *testing.T) {
+	assert.NoError(t, validateSeverityOverride(nil))
+	assert.NoError(t, validateSeverityOverride(&advisoryconfigs.SeverityOverride{Severity: "Medium", Rationale: "requires local access"}))
+	assert.ErrorContains(t, validateSeverityOverride(&advisoryconfigs.SeverityOverride{Severity: "Moderate", Rationale: "x"}), `severity override is "Moderate"`)
+	assert.ErrorContains(t, validateSeverityOverride(&advisoryconfigs.SeverityOverride{Severity: "Low", CVSS: "AV:N/AC:L", Rationale: "x"}), "not a valid CVSS v3 vector")
+	assert.ErrorContains(t, validateSeverityOverride(&advisoryconfigs.SeverityOverride{Severity: "Low"}), "must have a rationale")
+}
@@ -0,0 +1,173 @@
+package advisory
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/samber/lo"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+)
+
+// AdvisoriesManifestName is the default file name of the manifest of an
+// advisories repository's documents.
+const AdvisoriesManifestName = "advisories" + ExportManifestSuffix
+
+// NewAdvisoriesManifest returns a manifest of the advisory documents in the
+// given advisories repository directory, including archived documents. If
+// packages are given, only the documents of those packages are listed.
+//
+// Each document is listed by its slash-separated path relative to dir, with the
+// digest of its canonical form (the decoded document, encoded as JSON), so that
+// reformatting a document doesn't invalidate a signature of the manifest.
+func NewAdvisoriesManifest(dir string, packages ...string) (*ExportManifest, error) {
+	paths, err := advisoryDocumentPaths(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(packages) > 0 {
+		for _, pkg := range packages {
+			if !lo.ContainsBy(paths, func(p string) bool { return packageNameOfPath(p) == pkg }) {
+				return nil, fmt.Errorf("no advisories found for package %q", pkg)
+			}
+		}
+
+		paths = lo.Filter(paths, func(p string, _ int) bool {
+			return lo.Contains(packages, packageNameOfPath(p))
+		})
+	}
+
+	m := &ExportManifest{}
+	for _, p := range paths {
+		digest, err := canonicalDocumentDigest(filepath.Join(dir, filepath.FromSlash(p)))
+		if err != nil {
+			return nil, err
+		}
+
+		m.Files = append(m.Files, ManifestFile{
+			Name:   p,
+			SHA256: digest,
+		})
+	}
+
+	return m, nil
+}
+
+// VerifyAdvisories returns an error if the advisory documents in the given
+// advisories repository directory don't match the manifest, which must have
+// been created with NewAdvisoriesManifest: listed documents must exist and be
+// unmodified. If complete is true, documents not listed in the manifest are
+// also an error, since the manifest is expected to cover the whole repository.
+func (m *ExportManifest) VerifyAdvisories(dir string, complete bool) error {
+	var merr *multierror.Error
+
+	for _, f := range m.Files {
+		digest, err := canonicalDocumentDigest(filepath.Join(dir, filepath.FromSlash(f.Name)))
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				merr = multierror.Append(merr, fmt.Errorf("%s is listed in the manifest, but doesn't exist", f.Name))
+				continue
+			}
+			merr = multierror.Append(merr, err)
+			continue
+		}
+
+		if digest != f.SHA256 {
+			merr = multierror.Append(merr, fmt.Errorf("%s has been modified: its digest is sha256:%s, but the manifest lists sha256:%s", f.Name, digest, f.SHA256))
+		}
+	}
+
+	if complete {
+		paths, err := advisoryDocumentPaths(dir)
+		if err != nil {
+			return err
+		}
+
+		listed := lo.Map(m.Files, func(f ManifestFile, _ int) string { return f.Name })
+		for _, p := range paths {
+			if !lo.Contains(listed, p) {
+				merr = multierror.Append(merr, fmt.Errorf("%s is not listed in the manifest", p))
+			}
+		}
+	}
+
+	return merr.ErrorOrNil()
+}
+
+// ForPackages returns a manifest of only the advisory documents of the given
+// packages listed in the manifest, which must have been created with
+// NewAdvisoriesManifest. It's an error if a package's document isn't listed.
+func (m *ExportManifest) ForPackages(packages ...string) (*ExportManifest, error) {
+	selected := &ExportManifest{}
+	for _, pkg := range packages {
+		f, ok := lo.Find(m.Files, func(f ManifestFile) bool { return packageNameOfPath(f.Name) == pkg })
+		if !ok {
+			return nil, fmt.Errorf("no advisories of package %q are listed in the manifest", pkg)
+		}
+		selected.Files = append(selected.Files, f)
+	}
+
+	return selected, nil
+}
+
+// advisoryDocumentPaths returns the sorted, slash-separated paths (relative to
+// dir) of the advisory documents in the given advisories repository directory
+// and its archive.
+func advisoryDocumentPaths(dir string) ([]string, error) {
+	var paths []string
+
+	for _, sub := range []string{".", ArchiveDir} {
+		entries, err := os.ReadDir(filepath.Join(dir, sub))
+		if err != nil {
+			if sub == ArchiveDir && errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return nil, fmt.Errorf("unable to list advisory documents: %w", err)
+		}
+
+		for _, e := range entries {
+			if !e.Type().IsRegular() || strings.HasPrefix(e.Name(), ".") || !strings.HasSuffix(e.Name(), ".advisories.yaml") {
+				continue
+			}
+			paths = append(paths, path.Join(sub, e.Name()))
+		}
+	}
+
+	sort.Strings(paths)
+	return paths, nil
+}
+
+func packageNameOfPath(p string) string {
+	return strings.TrimSuffix(path.Base(p), ".advisories.yaml")
+}
+
+func canonicalDocumentDigest(p string) (string, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return "", fmt.Errorf("unable to open %q: %w", p, err)
+	}
+	defer f.Close()
+
+	doc, err := advisoryconfigs.DecodeDocument(f)
+	if err != nil {
+		return "", fmt.Errorf("unable to decode %q: %w", p, err)
+	}
+
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return "", fmt.Errorf("unable to encode %q: %w", p, err)
+	}
+
+	digest := sha256.Sum256(b)
+	return hex.EncodeToString(digest[:]), nil
+}
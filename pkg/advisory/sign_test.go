@@ -0,0 +1,83 @@
+package advisory
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdvisoriesManifest(t *testing.T) {
+	dir := t.TempDir()
+	write := func(p, content string) {
+		p = filepath.Join(dir, p)
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+		require.NoError(t, os.WriteFile(p, []byte(content), 0o644))
+	}
+
+	const foo = `package:
+  name: foo
+
+advisories:
+  CVE-2023-0001:
+    - timestamp: 2023-06-01T00:00:00Z
+      status: fixed
+      fixed-version: 1.2.3-r1
+`
+	write("foo.advisories.yaml", foo)
+	write("archived/bar.advisories.yaml", "package:\n  name: bar\n")
+	write("README.md", "not an advisory document")
+
+	m, err := NewAdvisoriesManifest(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"archived/bar.advisories.yaml", "foo.advisories.yaml"}, manifestFileNames(m))
+	assert.NoError(t, m.VerifyAdvisories(dir, true))
+
+	// Reformatting a document doesn't change its canonical form.
+	write("foo.advisories.yaml", "# a comment\n"+foo)
+	assert.NoError(t, m.VerifyAdvisories(dir, true))
+
+	write("foo.advisories.yaml", foo+`    - timestamp: 2023-06-02T00:00:00Z
+      status: under_investigation
+`)
+	assert.ErrorContains(t, m.VerifyAdvisories(dir, true), "foo.advisories.yaml has been modified")
+
+	write("foo.advisories.yaml", foo)
+	write("baz.advisories.yaml", "package:\n  name: baz\n")
+	assert.ErrorContains(t, m.VerifyAdvisories(dir, true), "baz.advisories.yaml is not listed in the manifest")
+	assert.NoError(t, m.VerifyAdvisories(dir, false))
+
+	require.NoError(t, os.Remove(filepath.Join(dir, "archived", "bar.advisories.yaml")))
+	assert.ErrorContains(t, m.VerifyAdvisories(dir, false), "archived/bar.advisories.yaml is listed in the manifest, but doesn't exist")
+
+	m, err = NewAdvisoriesManifest(dir, "foo")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"foo.advisories.yaml"}, manifestFileNames(m))
+
+	_, err = NewAdvisoriesManifest(dir, "qux")
+	assert.EqualError(t, err, `no advisories found for package "qux"`)
+}
+
+func manifestFileNames(m *ExportManifest) []string {
+	var names []string
+	for _, f := range m.Files {
+		names = append(names, f.Name)
+	}
+	return names
+}
+
+func TestExportManifest_ForPackages(t *testing.T) {
+	m := &ExportManifest{Files: []ManifestFile{
+		{Name: "archived/bar.advisories.yaml", SHA256: "1"},
+		{Name: "foo.advisories.yaml", SHA256: "2"},
+	}}
+
+	selected, err := m.ForPackages("bar")
+	require.NoError(t, err)
+	assert.Equal(t, []ManifestFile{{Name: "archived/bar.advisories.yaml", SHA256: "1"}}, selected.Files)
+
+	_, err = m.ForPackages("foo", "baz")
+	assert.EqualError(t, err, `no advisories of package "baz" are listed in the manifest`)
+}
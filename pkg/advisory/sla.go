@@ -0,0 +1,188 @@
+package advisory
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openvex/go-vex/pkg/vex"
+	"github.com/wolfi-dev/wolfictl/pkg/configs"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	"github.com/wolfi-dev/wolfictl/pkg/scan"
+	"golang.org/x/exp/slices"
+	"gopkg.in/yaml.v3"
+)
+
+// SLAPolicyFile is the default location, within an advisories repository, of
+// the file that defines the SLA policy.
+const SLAPolicyFile = "sla-policy.yaml"
+
+// SLAPolicy maps severities (see scan.Severities) to the longest an advisory of
+// that severity may stay unresolved (i.e. affected or under_investigation).
+// Severities without an SLA aren't tracked.
+type SLAPolicy map[string]time.Duration
+
+// DefaultSLAPolicy is the SLA policy used when none is configured.
+var DefaultSLAPolicy = SLAPolicy{
+	"Critical": 7 * 24 * time.Hour,
+	"High":     30 * 24 * time.Hour,
+	"Medium":   90 * 24 * time.Hour,
+	"Low":      180 * 24 * time.Hour,
+}
+
+type slaPolicyFile struct {
+	SLAs map[string]string `yaml:"slas"`
+}
+
+// DecodeSLAPolicy decodes an SLA policy file, which maps severities to their
+// SLAs under a top-level "slas" key. SLAs are given in days (e.g. "7d") or in
+// Go duration syntax (e.g. "36h"):
+//
+//	slas:
+//	  Critical: 7d
+//	  High: 30d
+func DecodeSLAPolicy(r io.Reader) (SLAPolicy, error) {
+	var f slaPolicyFile
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+	if err := dec.Decode(&f); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("unable to decode SLA policy: %w", err)
+	}
+
+	policy := make(SLAPolicy, len(f.SLAs))
+	for severity, s := range f.SLAs {
+		if !slices.Contains(scan.Severities, severity) {
+			return nil, fmt.Errorf("SLA policy has unknown severity %q (must be one of: %s)", severity, strings.Join(scan.Severities, ", "))
+		}
+
+		d, err := ParseSLADuration(s)
+		if err != nil {
+			return nil, fmt.Errorf("SLA policy for %s: %w", severity, err)
+		}
+
+		policy[severity] = d
+	}
+
+	return policy, nil
+}
+
+// LoadSLAPolicy reads the SLA policy file at path. It returns a nil policy,
+// rather than an error, if the file doesn't exist.
+func LoadSLAPolicy(path string) (SLAPolicy, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	policy, err := DecodeSLAPolicy(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return policy, nil
+}
+
+// ParseSLADuration parses an SLA given in days (e.g. "7d") or in Go duration
+// syntax (e.g. "36h").
+func ParseSLADuration(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("invalid SLA %q", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid SLA %q: must be a number of days (e.g. 7d) or a duration (e.g. 36h)", s)
+	}
+
+	return d, nil
+}
+
+// SLAQueueOptions configures the SLAQueue operation.
+type SLAQueueOptions struct {
+	// AdvisoryCfgs is the Index of advisories to track.
+	AdvisoryCfgs *configs.Index[advisoryconfigs.Document]
+
+	// Severities maps vulnerability IDs to their severities (see
+	// StatsOptions.Severities).
+	Severities map[string]string
+
+	// Policy is the SLA policy to track advisories against.
+	Policy SLAPolicy
+
+	// Now is the time against which deadlines are measured.
+	Now time.Time
+}
+
+// SLAItem is an unresolved advisory with an SLA deadline.
+type SLAItem struct {
+	Package       string     `json:"package"`
+	Vulnerability string     `json:"vulnerability"`
+	Severity      string     `json:"severity"`
+	Status        vex.Status `json:"status"`
+
+	// Detected is the timestamp of the advisory's first event.
+	Detected time.Time `json:"detected"`
+
+	// Due is when the advisory must be resolved by, according to the SLA for its
+	// severity.
+	Due time.Time `json:"due"`
+
+	// Breached is true if the advisory is past due.
+	Breached bool `json:"breached"`
+}
+
+// SLAQueue returns the unresolved advisories whose severity has an SLA in the
+// policy, with their deadlines, measured from their first event (i.e.
+// detection). Only public advisory data is considered. Items are sorted by
+// deadline, most overdue first, and then by package and vulnerability.
+func SLAQueue(opts SLAQueueOptions) []SLAItem {
+	items := []SLAItem{}
+	for _, a := range List(opts.AdvisoryCfgs, ListFilter{}) {
+		entries := PublicEntries(a.Entries)
+		if len(entries) == 0 {
+			continue
+		}
+		latest := entries[len(entries)-1]
+		if latest.Status != vex.StatusAffected && latest.Status != vex.StatusUnderInvestigation {
+			continue
+		}
+
+		sev := opts.Severities[a.Vulnerability]
+		sla, ok := opts.Policy[sev]
+		if !ok {
+			continue
+		}
+
+		detected := entries[0].Timestamp
+		due := detected.Add(sla)
+		items = append(items, SLAItem{
+			Package:       a.Package,
+			Vulnerability: a.Vulnerability,
+			Severity:      sev,
+			Status:        latest.Status,
+			Detected:      detected,
+			Due:           due,
+			Breached:      opts.Now.After(due),
+		})
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Due.Before(items[j].Due)
+	})
+
+	return items
+}
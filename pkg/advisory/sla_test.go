@@ -0,0 +1,97 @@
+package advisory
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openvex/go-vex/pkg/vex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
+)
+
+func TestDecodeSLAPolicy(t *testing.T) {
+	policy, err := DecodeSLAPolicy(strings.NewReader("slas:\n  Critical: 3d\n  High: 36h\n"))
+	require.NoError(t, err)
+	assert.Equal(t, SLAPolicy{"Critical": 3 * 24 * time.Hour, "High": 36 * time.Hour}, policy)
+
+	_, err = DecodeSLAPolicy(strings.NewReader("slas:\n  Urgent: 3d\n"))
+	assert.ErrorContains(t, err, `unknown severity "Urgent"`)
+
+	_, err = DecodeSLAPolicy(strings.NewReader("slas:\n  High: soon\n"))
+	assert.ErrorContains(t, err, `invalid SLA "soon"`)
+
+	policy, err = LoadSLAPolicy(filepath.Join(t.TempDir(), SLAPolicyFile))
+	require.NoError(t, err)
+	assert.Nil(t, policy)
+}
+
+func TestSLAQueue(t *testing.T) {
+	const doc = `package:
+  name: foo
+
+advisories:
+  CVE-2023-1:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: under_investigation
+  CVE-2023-2:
+    - timestamp: 2023-05-20T00:00:00Z
+      status: under_investigation
+    - timestamp: 2023-05-21T00:00:00Z
+      status: affected
+      action: wait for upstream
+  CVE-2023-3:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: under_investigation
+    - timestamp: 2023-05-02T00:00:00Z
+      status: fixed
+      fixed-version: 1.2.3-r1
+  CVE-2023-4:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: under_investigation
+`
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "foo.advisories.yaml"), []byte(doc), 0o644))
+
+	cfgs, err := advisoryconfigs.NewIndex(rwos.DirFS(dir))
+	require.NoError(t, err)
+
+	day := 24 * time.Hour
+	items := SLAQueue(SLAQueueOptions{
+		AdvisoryCfgs: cfgs,
+		Severities: map[string]string{
+			"CVE-2023-1": "Critical",
+			"CVE-2023-2": "High",
+			"CVE-2023-3": "Critical",
+			"CVE-2023-4": "Low",
+		},
+		Policy: SLAPolicy{"Critical": 7 * day, "High": 14 * day},
+		Now:    time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC),
+	})
+
+	assert.Equal(t, []SLAItem{
+		{
+			Package:       "foo",
+			Vulnerability: "CVE-2023-1",
+			Severity:      "Critical",
+			Status:        vex.StatusUnderInvestigation,
+			Detected:      time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC),
+			Due:           time.Date(2023, 5, 8, 0, 0, 0, 0, time.UTC),
+			Breached:      true,
+		},
+		{
+			Package:       "foo",
+			Vulnerability: "CVE-2023-2",
+			Severity:      "High",
+			Status:        vex.StatusAffected,
+			Detected:      time.Date(2023, 5, 20, 0, 0, 0, 0, time.UTC),
+			Due:           time.Date(2023, 6, 3, 0, 0, 0, 0, time.UTC),
+			Breached:      false,
+		},
+	}, items)
+}
@@ -0,0 +1,168 @@
+package advisory
+
+import (
+	"sort"
+	"time"
+
+	"github.com/openvex/go-vex/pkg/vex"
+	"github.com/wolfi-dev/wolfictl/pkg/configs"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	"github.com/wolfi-dev/wolfictl/pkg/scan"
+)
+
+// StatsOptions configures the Stats operation.
+type StatsOptions struct {
+	// AdvisoryCfgs is the Index of advisories to compute statistics for.
+	AdvisoryCfgs *configs.Index[advisoryconfigs.Document]
+
+	// Severities maps vulnerability IDs to their severities (see scan.Severities).
+	// Advisories whose vulnerability isn't in the map, or has an unrecognized
+	// severity, are counted as "Unknown".
+	Severities map[string]string
+
+	// SLAs maps severities to the longest an advisory of that severity may stay
+	// unresolved (i.e. affected or under_investigation). Severities without an
+	// SLA aren't checked.
+	SLAs map[string]time.Duration
+
+	// Now is the time against which unresolved advisories are measured.
+	Now time.Time
+}
+
+// Stats is a roll-up of a set of advisories.
+type Stats struct {
+	// Total is the number of advisories.
+	Total int `json:"total"`
+
+	// CountsByStatus maps each status to the number of advisories whose latest
+	// event has that status.
+	CountsByStatus map[vex.Status]int `json:"countsByStatus"`
+
+	// CountsBySeverity maps each severity (see scan.Severities) to the number of
+	// advisories for a vulnerability of that severity.
+	CountsBySeverity map[string]int `json:"countsBySeverity"`
+
+	// TimeToFix describes how long it took for advisories that are now fixed to
+	// go from their first event (i.e. detection) to being fixed.
+	TimeToFix DurationStats `json:"timeToFix"`
+
+	// SLABreaches are the unresolved advisories that have been open longer than
+	// the SLA for their severity, longest open first.
+	SLABreaches []SLABreach `json:"slaBreaches"`
+}
+
+// DurationStats summarizes a set of durations.
+type DurationStats struct {
+	Count  int           `json:"count"`
+	Mean   time.Duration `json:"mean"`
+	Median time.Duration `json:"median"`
+}
+
+// SLABreach is an unresolved advisory that has been open longer than its SLA.
+type SLABreach struct {
+	Package       string        `json:"package"`
+	Vulnerability string        `json:"vulnerability"`
+	Severity      string        `json:"severity"`
+	Status        vex.Status    `json:"status"`
+	Detected      time.Time     `json:"detected"`
+	Open          time.Duration `json:"open"`
+	SLA           time.Duration `json:"sla"`
+}
+
+// ComputeStats counts the advisories by their latest status and by severity,
+// measures their time to fix, and finds the unresolved advisories that breach
+// their SLA. Only public advisory data is considered.
+func ComputeStats(opts StatsOptions) Stats {
+	s := Stats{
+		CountsByStatus:   make(map[vex.Status]int),
+		CountsBySeverity: make(map[string]int),
+		SLABreaches:      []SLABreach{},
+	}
+
+	for _, sev := range scan.Severities {
+		s.CountsBySeverity[sev] = 0
+	}
+
+	var timesToFix []time.Duration
+	for _, a := range List(opts.AdvisoryCfgs, ListFilter{}) {
+		entries := PublicEntries(a.Entries)
+		if len(entries) == 0 {
+			continue
+		}
+		latest := entries[len(entries)-1]
+		detected := entries[0].Timestamp
+
+		s.Total++
+		s.CountsByStatus[latest.Status]++
+
+		sev := opts.Severities[a.Vulnerability]
+		if _, ok := s.CountsBySeverity[sev]; !ok {
+			sev = "Unknown"
+		}
+		s.CountsBySeverity[sev]++
+
+		switch latest.Status {
+		case vex.StatusFixed:
+			for _, e := range entries {
+				if e.Status == vex.StatusFixed {
+					timesToFix = append(timesToFix, e.Timestamp.Sub(detected))
+					break
+				}
+			}
+
+		case vex.StatusAffected, vex.StatusUnderInvestigation:
+			sla, ok := opts.SLAs[sev]
+			if !ok {
+				continue
+			}
+
+			if open := opts.Now.Sub(detected); open > sla {
+				s.SLABreaches = append(s.SLABreaches, SLABreach{
+					Package:       a.Package,
+					Vulnerability: a.Vulnerability,
+					Severity:      sev,
+					Status:        latest.Status,
+					Detected:      detected,
+					Open:          open,
+					SLA:           sla,
+				})
+			}
+		}
+	}
+
+	s.TimeToFix = summarizeDurations(timesToFix)
+
+	sort.SliceStable(s.SLABreaches, func(i, j int) bool {
+		return s.SLABreaches[i].Open > s.SLABreaches[j].Open
+	})
+
+	return s
+}
+
+func summarizeDurations(durations []time.Duration) DurationStats {
+	if len(durations) == 0 {
+		return DurationStats{}
+	}
+
+	sorted := make([]time.Duration, len(durations))
+	copy(sorted, durations)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+
+	var total time.Duration
+	for _, d := range sorted {
+		total += d
+	}
+
+	median := sorted[len(sorted)/2]
+	if len(sorted)%2 == 0 {
+		median = (sorted[len(sorted)/2-1] + sorted[len(sorted)/2]) / 2
+	}
+
+	return DurationStats{
+		Count:  len(sorted),
+		Mean:   total / time.Duration(len(sorted)),
+		Median: median,
+	}
+}
@@ -0,0 +1,104 @@
+package advisory
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/openvex/go-vex/pkg/vex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
+)
+
+func TestComputeStats(t *testing.T) {
+	const doc = `package:
+  name: foo
+
+advisories:
+  CVE-2023-1:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: under_investigation
+    - timestamp: 2023-05-03T00:00:00Z
+      status: fixed
+      fixed-version: 1.2.3-r1
+  CVE-2023-2:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: under_investigation
+    - timestamp: 2023-05-02T00:00:00Z
+      status: affected
+      action: upgrade to 2.0
+    - timestamp: 2023-05-05T00:00:00Z
+      status: fixed
+      fixed-version: 2.0.0-r0
+  CVE-2023-3:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: under_investigation
+  CVE-2023-4:
+    - timestamp: 2023-05-25T00:00:00Z
+      status: affected
+      action: wait for upstream
+  CVE-2023-5:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: not_affected
+      justification: component_not_present
+  CVE-2023-6:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: under_investigation
+      internal: true
+`
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "foo.advisories.yaml"), []byte(doc), 0o644))
+
+	cfgs, err := advisoryconfigs.NewIndex(rwos.DirFS(dir))
+	require.NoError(t, err)
+
+	day := 24 * time.Hour
+	now := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	s := ComputeStats(StatsOptions{
+		AdvisoryCfgs: cfgs,
+		Severities: map[string]string{
+			"CVE-2023-1": "High",
+			"CVE-2023-2": "High",
+			"CVE-2023-3": "Critical",
+			"CVE-2023-4": "Critical",
+			"CVE-2023-5": "bogus",
+		},
+		SLAs: map[string]time.Duration{
+			"Critical": 14 * day,
+		},
+		Now: now,
+	})
+
+	assert.Equal(t, 5, s.Total)
+	assert.Equal(t, map[vex.Status]int{
+		vex.StatusFixed:              2,
+		vex.StatusUnderInvestigation: 1,
+		vex.StatusAffected:           1,
+		vex.StatusNotAffected:        1,
+	}, s.CountsByStatus)
+	assert.Equal(t, 2, s.CountsBySeverity["Critical"])
+	assert.Equal(t, 2, s.CountsBySeverity["High"])
+	assert.Equal(t, 1, s.CountsBySeverity["Unknown"])
+	assert.Equal(t, DurationStats{Count: 2, Mean: 3 * day, Median: 3 * day}, s.TimeToFix)
+
+	require.Len(t, s.SLABreaches, 1)
+	assert.Equal(t, SLABreach{
+		Package:       "foo",
+		Vulnerability: "CVE-2023-3",
+		Severity:      "Critical",
+		Status:        vex.StatusUnderInvestigation,
+		Detected:      time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC),
+		Open:          31 * day,
+		SLA:           14 * day,
+	}, s.SLABreaches[0])
+}
+
+func Test_summarizeDurations(t *testing.T) {
+	assert.Equal(t, DurationStats{}, summarizeDurations(nil))
+	assert.Equal(t, DurationStats{Count: 3, Mean: 4, Median: 2}, summarizeDurations([]time.Duration{9, 1, 2}))
+}
@@ -0,0 +1,318 @@
+package advisory
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/openvex/go-vex/pkg/vex"
+	"github.com/samber/lo"
+	"github.com/wolfi-dev/wolfictl/pkg/configs"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	"golang.org/x/exp/slices"
+	"gopkg.in/yaml.v3"
+)
+
+// versionStreamPattern matches the name of a package that's one version stream
+// of a project, e.g. "openjdk-17" or "python-3.11", capturing the name shared by
+// all of the project's streams.
+var versionStreamPattern = regexp.MustCompile(`^(.+)-\d+(\.\d+)*$`)
+
+// SiblingStreams returns the names among candidates of the other version
+// streams of the given package, sorted. For example, "openjdk-11" and
+// "openjdk-21" are sibling streams of "openjdk-17". A package whose name doesn't
+// end in a version has no sibling streams.
+func SiblingStreams(packageName string, candidates []string) []string {
+	m := versionStreamPattern.FindStringSubmatch(packageName)
+	if m == nil {
+		return nil
+	}
+	stem := m[1]
+
+	var siblings []string
+	for _, name := range lo.Uniq(candidates) {
+		if name == packageName {
+			continue
+		}
+		if sm := versionStreamPattern.FindStringSubmatch(name); sm != nil && sm[1] == stem {
+			siblings = append(siblings, name)
+		}
+	}
+	sort.Strings(siblings)
+
+	return siblings
+}
+
+// StreamsFile is the default location, within an advisories repository, of the
+// file that maps projects to their version streams.
+const StreamsFile = "streams.yaml"
+
+// StreamMap maps projects to the packages that are their version streams. It's
+// only needed for streams whose names don't follow the usual naming of the
+// project name followed by a version (e.g. "postgresql-16"), which is otherwise
+// how streams are recognized.
+type StreamMap map[string][]string
+
+type streamsFile struct {
+	Projects map[string][]string `yaml:"projects"`
+}
+
+// DecodeStreamMap decodes a streams file, which lists the version streams of
+// each project under a top-level "projects" key:
+//
+//	projects:
+//	  nodejs:
+//	    - nodejs-18
+//	    - nodejs-lts
+func DecodeStreamMap(r io.Reader) (StreamMap, error) {
+	var f streamsFile
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+	if err := dec.Decode(&f); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("unable to decode streams file: %w", err)
+	}
+
+	projectOf := make(map[string]string)
+	for project, pkgs := range f.Projects {
+		for _, pkg := range pkgs {
+			if other, ok := projectOf[pkg]; ok && other != project {
+				return nil, fmt.Errorf("package %q is listed as a stream of both %q and %q", pkg, other, project)
+			}
+			projectOf[pkg] = project
+		}
+	}
+
+	return f.Projects, nil
+}
+
+// LoadStreamMap reads the streams file at path. It returns a nil map, rather
+// than an error, if the file doesn't exist.
+func LoadStreamMap(path string) (StreamMap, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	m, err := DecodeStreamMap(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return m, nil
+}
+
+// Project returns the name of the project that the package is a version stream
+// of, as given by the map or else by the package's name (e.g. "postgresql" for
+// "postgresql-16"). A package that isn't a version stream is its own project.
+func (m StreamMap) Project(packageName string) string {
+	for project, pkgs := range m {
+		if slices.Contains(pkgs, packageName) {
+			return project
+		}
+	}
+
+	if sm := versionStreamPattern.FindStringSubmatch(packageName); sm != nil {
+		return sm[1]
+	}
+
+	return packageName
+}
+
+// Streams returns the names among candidates, and those the map lists for the
+// project, of the project's version streams, sorted.
+func (m StreamMap) Streams(project string, candidates []string) []string {
+	var streams []string
+	for _, name := range lo.Uniq(append(append([]string{}, candidates...), m[project]...)) {
+		if m.Project(name) == project {
+			streams = append(streams, name)
+		}
+	}
+	sort.Strings(streams)
+
+	return streams
+}
+
+// ProjectAdvisory is the consolidated advisory data for a vulnerability across
+// the version streams of a project.
+type ProjectAdvisory struct {
+	Project       string
+	Vulnerability string
+
+	// Streams are the advisories of the project's streams, sorted by package.
+	Streams []ListedAdvisory
+
+	// Missing are the project's streams (among the packages in the index) that
+	// have no advisory for the vulnerability, sorted.
+	Missing []string
+}
+
+// Resolved returns true if every stream of the project has an advisory for the
+// vulnerability, and all of them are fixed or not affected.
+func (a ProjectAdvisory) Resolved() bool {
+	if len(a.Missing) > 0 {
+		return false
+	}
+
+	for _, s := range a.Streams {
+		if s.Latest.Status != vex.StatusFixed && s.Latest.Status != vex.StatusNotAffected {
+			return false
+		}
+	}
+
+	return true
+}
+
+// GroupByProject consolidates the listed advisories (see List) by project and
+// vulnerability, using the given stream map to tell which packages are streams
+// of the same project. Streams of each project in cfgs that have no advisory
+// at all for a vulnerability are noted as missing. The result is sorted by
+// project and then vulnerability.
+func GroupByProject(cfgs *configs.Index[advisoryconfigs.Document], listed []ListedAdvisory, streams StreamMap) []ProjectAdvisory {
+	packages := lo.Map(cfgs.Select().Configurations(), func(doc advisoryconfigs.Document, _ int) string {
+		return doc.Package.Name
+	})
+
+	byKey := make(map[[2]string]*ProjectAdvisory)
+	var keys [][2]string
+	for _, a := range listed {
+		key := [2]string{streams.Project(a.Package), a.Vulnerability}
+		pa, ok := byKey[key]
+		if !ok {
+			pa = &ProjectAdvisory{Project: key[0], Vulnerability: key[1]}
+			byKey[key] = pa
+			keys = append(keys, key)
+		}
+		pa.Streams = append(pa.Streams, a)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i][0] != keys[j][0] {
+			return keys[i][0] < keys[j][0]
+		}
+		return keys[i][1] < keys[j][1]
+	})
+
+	grouped := make([]ProjectAdvisory, 0, len(keys))
+	for _, key := range keys {
+		pa := byKey[key]
+		sort.SliceStable(pa.Streams, func(i, j int) bool {
+			return pa.Streams[i].Package < pa.Streams[j].Package
+		})
+
+		for _, stream := range streams.Streams(pa.Project, packages) {
+			if LatestForPackage(cfgs, stream, pa.Vulnerability) == nil {
+				pa.Missing = append(pa.Missing, stream)
+			}
+		}
+
+		grouped = append(grouped, *pa)
+	}
+
+	return grouped
+}
+
+// CopyToStreamsOptions configures the CopyToStreams operation.
+type CopyToStreamsOptions struct {
+	// AdvisoryCfgs is the Index of advisory configurations on which to operate.
+	AdvisoryCfgs *configs.Index[advisoryconfigs.Document]
+
+	// Package is the package whose advisory is copied.
+	Package string
+
+	// Vulnerability is the ID of the advisory whose latest event is copied.
+	Vulnerability string
+
+	// Streams are the packages to copy the event to.
+	Streams []string
+
+	// FixedVersions maps each stream to its own fixed version. It's required for
+	// every stream when the copied event is a fixed event, since version streams
+	// don't share versions.
+	FixedVersions map[string]string
+
+	// Now is the timestamp of the copied events. If zero, the current time is
+	// used.
+	Now time.Time
+
+	// DryRun returns the requests that would be made without making them.
+	DryRun bool
+}
+
+// CopyToStreams copies the latest event of an advisory to the same advisory of
+// other version streams of the package, creating the advisory where needed. A
+// stream is skipped if its advisory's latest event already matches the copied
+// one. It returns the requests made for each stream it changed.
+func CopyToStreams(opts CopyToStreamsOptions) ([]Request, error) {
+	latest := LatestForPackage(opts.AdvisoryCfgs, opts.Package, opts.Vulnerability)
+	if latest == nil {
+		return nil, fmt.Errorf("no advisory for %s in package %q", opts.Vulnerability, opts.Package)
+	}
+
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	var reqs []Request
+	for _, stream := range opts.Streams {
+		req := Request{
+			Package:       stream,
+			Vulnerability: opts.Vulnerability,
+			Status:        latest.Status,
+			Action:        latest.ActionStatement,
+			Impact:        latest.ImpactStatement,
+			Justification: latest.Justification,
+			Timestamp:     now,
+		}
+		if req.Status == vex.StatusFixed {
+			req.FixedVersion = opts.FixedVersions[stream]
+		}
+		if err := req.Validate(); err != nil {
+			return nil, fmt.Errorf("unable to copy event to %q: %w", stream, err)
+		}
+
+		if current := LatestForPackage(opts.AdvisoryCfgs, stream, opts.Vulnerability); current != nil && matchesRequest(*current, req) {
+			continue
+		}
+
+		reqs = append(reqs, req)
+	}
+
+	if opts.DryRun {
+		return reqs, nil
+	}
+
+	for _, req := range reqs {
+		var err error
+		if LatestForPackage(opts.AdvisoryCfgs, req.Package, req.Vulnerability) == nil {
+			err = Create(req, CreateOptions{AdvisoryCfgs: opts.AdvisoryCfgs})
+		} else {
+			err = Update(req, UpdateOptions{AdvisoryCfgs: opts.AdvisoryCfgs})
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return reqs, nil
+}
+
+// matchesRequest returns true if the entry records the same triage as the
+// request, regardless of when.
+func matchesRequest(entry advisoryconfigs.Entry, req Request) bool {
+	e := req.toAdvisoryEntry()
+	return entry.Status == e.Status &&
+		entry.Justification == e.Justification &&
+		entry.ImpactStatement == e.ImpactStatement &&
+		entry.ActionStatement == e.ActionStatement &&
+		entry.FixedVersion == e.FixedVersion
+}
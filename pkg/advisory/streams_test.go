@@ -0,0 +1,182 @@
+package advisory
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openvex/go-vex/pkg/vex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
+)
+
+func TestSiblingStreams(t *testing.T) {
+	candidates := []string{"openjdk-11", "openjdk-17", "openjdk-21", "openjdk-21", "openjdk", "openjdk-jre-17", "python-3.11", "python-3.12"}
+
+	assert.Equal(t, []string{"openjdk-11", "openjdk-21"}, SiblingStreams("openjdk-17", candidates))
+	assert.Equal(t, []string{"python-3.11"}, SiblingStreams("python-3.12", candidates))
+	assert.Empty(t, SiblingStreams("openjdk", candidates))
+}
+
+func TestCopyToStreams(t *testing.T) {
+	const source = `package:
+  name: openjdk-17
+
+advisories:
+  CVE-2023-1:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: under_investigation
+    - timestamp: 2023-05-02T00:00:00Z
+      status: fixed
+      fixed-version: 17.0.8-r0
+`
+	const alreadyFixed = `package:
+  name: openjdk-21
+
+advisories:
+  CVE-2023-1:
+    - timestamp: 2023-05-02T00:00:00Z
+      status: fixed
+      fixed-version: 21.0.1-r0
+`
+	const investigating = `package:
+  name: openjdk-11
+
+advisories:
+  CVE-2023-1:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: under_investigation
+`
+
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"openjdk-17.advisories.yaml": source,
+		"openjdk-21.advisories.yaml": alreadyFixed,
+		"openjdk-11.advisories.yaml": investigating,
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+
+	cfgs, err := advisoryconfigs.NewIndex(rwos.DirFS(dir))
+	require.NoError(t, err)
+
+	now := time.Date(2023, 5, 3, 0, 0, 0, 0, time.UTC)
+	opts := CopyToStreamsOptions{
+		AdvisoryCfgs:  cfgs,
+		Package:       "openjdk-17",
+		Vulnerability: "CVE-2023-1",
+		Streams:       []string{"openjdk-11", "openjdk-21", "openjdk-22"},
+		FixedVersions: map[string]string{"openjdk-11": "11.0.20-r0", "openjdk-21": "21.0.1-r0"},
+		Now:           now,
+	}
+
+	_, err = CopyToStreams(opts)
+	assert.ErrorContains(t, err, `unable to copy event to "openjdk-22"`, "fixed events need a fixed version for every stream")
+
+	opts.FixedVersions["openjdk-22"] = "22.0.0-r0"
+	reqs, err := CopyToStreams(opts)
+	require.NoError(t, err)
+	assert.Equal(t, []Request{
+		{Package: "openjdk-11", Vulnerability: "CVE-2023-1", Status: vex.StatusFixed, FixedVersion: "11.0.20-r0", Timestamp: now},
+		{Package: "openjdk-22", Vulnerability: "CVE-2023-1", Status: vex.StatusFixed, FixedVersion: "22.0.0-r0", Timestamp: now},
+	}, reqs)
+
+	latest := LatestForPackage(cfgs, "openjdk-11", "CVE-2023-1")
+	require.NotNil(t, latest)
+	assert.Equal(t, "11.0.20-r0", latest.FixedVersion)
+
+	latest = LatestForPackage(cfgs, "openjdk-22", "CVE-2023-1")
+	require.NotNil(t, latest)
+	assert.Equal(t, vex.StatusFixed, latest.Status)
+
+	reqs, err = CopyToStreams(opts)
+	require.NoError(t, err)
+	assert.Empty(t, reqs, "copying again must not change anything")
+}
+
+func TestStreamMap(t *testing.T) {
+	streams, err := DecodeStreamMap(strings.NewReader(`projects:
+  nodejs:
+    - nodejs-18
+    - nodejs-lts
+`))
+	require.NoError(t, err)
+
+	assert.Equal(t, "nodejs", streams.Project("nodejs-lts"))
+	assert.Equal(t, "postgresql", streams.Project("postgresql-16"))
+	assert.Equal(t, "glibc", streams.Project("glibc"))
+
+	candidates := []string{"nodejs-18", "nodejs-20", "postgresql-15", "postgresql-16", "postgresql-16"}
+	assert.Equal(t, []string{"nodejs-18", "nodejs-20", "nodejs-lts"}, streams.Streams("nodejs", candidates))
+	assert.Equal(t, []string{"postgresql-15", "postgresql-16"}, streams.Streams("postgresql", candidates))
+
+	_, err = DecodeStreamMap(strings.NewReader(`projects:
+  nodejs: [nodejs-lts]
+  node: [nodejs-lts]
+`))
+	assert.ErrorContains(t, err, "listed as a stream of both")
+}
+
+func TestGroupByProject(t *testing.T) {
+	docs := map[string]string{
+		"postgresql-15": `package:
+  name: postgresql-15
+
+advisories:
+  CVE-2023-1:
+    - timestamp: 2023-05-02T00:00:00Z
+      status: fixed
+      fixed-version: 15.4-r0
+`,
+		"postgresql-16": `package:
+  name: postgresql-16
+
+advisories:
+  CVE-2023-1:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: affected
+      action: wait for upstream fix
+  CVE-2023-2:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: not_affected
+      justification: vulnerable_code_not_present
+`,
+		"glibc": `package:
+  name: glibc
+
+advisories:
+  CVE-2023-1:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: fixed
+      fixed-version: 2.38-r0
+`,
+	}
+
+	dir := t.TempDir()
+	for name, doc := range docs {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name+".advisories.yaml"), []byte(doc), 0o644))
+	}
+
+	cfgs, err := advisoryconfigs.NewIndex(rwos.DirFS(dir))
+	require.NoError(t, err)
+
+	listed := List(cfgs, ListFilter{Packages: []string{"postgresql-15", "postgresql-16"}})
+	require.Len(t, listed, 3)
+
+	grouped := GroupByProject(cfgs, listed, nil)
+	require.Len(t, grouped, 2)
+
+	assert.Equal(t, "postgresql", grouped[0].Project)
+	assert.Equal(t, "CVE-2023-1", grouped[0].Vulnerability)
+	assert.Equal(t, []string{"postgresql-15", "postgresql-16"}, []string{grouped[0].Streams[0].Package, grouped[0].Streams[1].Package})
+	assert.Empty(t, grouped[0].Missing)
+	assert.False(t, grouped[0].Resolved())
+
+	assert.Equal(t, "CVE-2023-2", grouped[1].Vulnerability)
+	assert.Equal(t, []string{"postgresql-15"}, grouped[1].Missing)
+	assert.False(t, grouped[1].Resolved())
+}
@@ -0,0 +1,139 @@
+package advisory
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"sort"
+
+	"github.com/openvex/go-vex/pkg/vex"
+	"gopkg.in/yaml.v3"
+)
+
+// TemplatesFile is the default location, within an advisories repository, of
+// the file that defines advisory templates.
+const TemplatesFile = "advisory-templates.yaml"
+
+// Template pre-fills the fields of an advisory event for a common triage
+// outcome, e.g. "the vulnerable code is only used at build time".
+type Template struct {
+	Name          string            `yaml:"-"`
+	Description   string            `yaml:"description,omitempty"`
+	Status        vex.Status        `yaml:"status"`
+	Justification vex.Justification `yaml:"justification,omitempty"`
+	Impact        string            `yaml:"impact,omitempty"`
+	Action        string            `yaml:"action,omitempty"`
+}
+
+// Templates are the advisory templates defined in a templates file, keyed by
+// name.
+type Templates map[string]Template
+
+type templatesFile struct {
+	Templates Templates `yaml:"templates"`
+}
+
+// DecodeTemplates decodes a templates file, which maps template names to their
+// fields under a top-level "templates" key:
+//
+//	templates:
+//	  build-dependency-only:
+//	    description: the vulnerable component is only used to build the package
+//	    status: not_affected
+//	    justification: vulnerable_code_not_in_execute_path
+//	    impact: The vulnerable component is only used at build time.
+func DecodeTemplates(r io.Reader) (Templates, error) {
+	var f templatesFile
+	if err := yaml.NewDecoder(r).Decode(&f); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("unable to decode advisory templates: %w", err)
+	}
+
+	for name, t := range f.Templates {
+		t.Name = name
+
+		// A template must make a valid request once the package and
+		// vulnerability are known, except for the fixed version, which differs
+		// every time.
+		req, err := t.Apply(Request{Package: "package", Vulnerability: "vulnerability", FixedVersion: "version"})
+		if err == nil {
+			err = req.Validate()
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid advisory template %q: %w", name, err)
+		}
+
+		f.Templates[name] = t
+	}
+
+	return f.Templates, nil
+}
+
+// LoadTemplates reads the templates file at path. It returns no templates,
+// rather than an error, if the file doesn't exist.
+func LoadTemplates(path string) (Templates, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	templates, err := DecodeTemplates(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return templates, nil
+}
+
+// Names returns the names of the templates, sorted.
+func (ts Templates) Names() []string {
+	names := make([]string, 0, len(ts))
+	for name := range ts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Get returns the named template, or an error listing the available templates
+// if there's no such template.
+func (ts Templates) Get(name string) (Template, error) {
+	t, ok := ts[name]
+	if !ok {
+		if len(ts) == 0 {
+			return Template{}, fmt.Errorf("unknown advisory template %q (no templates are defined)", name)
+		}
+		return Template{}, fmt.Errorf("unknown advisory template %q (must be one of: %v)", name, ts.Names())
+	}
+
+	return t, nil
+}
+
+// Apply returns the request with its status, justification, impact, and action
+// taken from the template. Fields that are already set on the request are kept,
+// so that they can override the template. It returns an error if the request
+// already has a different status from the template.
+func (t Template) Apply(req Request) (Request, error) {
+	if req.Status != "" && req.Status != t.Status {
+		return Request{}, fmt.Errorf("advisory template %q is for status %q, not %q", t.Name, t.Status, req.Status)
+	}
+
+	req.Status = t.Status
+	if req.Justification == "" {
+		req.Justification = t.Justification
+	}
+	if req.Impact == "" {
+		req.Impact = t.Impact
+	}
+	if req.Action == "" {
+		req.Action = t.Action
+	}
+
+	return req, nil
+}
@@ -0,0 +1,55 @@
+package advisory
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/openvex/go-vex/pkg/vex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeTemplates(t *testing.T) {
+	const file = `templates:
+  build-dependency-only:
+    description: the vulnerable component is only used to build the package
+    status: not_affected
+    justification: vulnerable_code_not_in_execute_path
+    impact: The vulnerable component is only used at build time.
+  upstream-fix:
+    status: fixed
+`
+
+	templates, err := DecodeTemplates(strings.NewReader(file))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"build-dependency-only", "upstream-fix"}, templates.Names())
+
+	tmpl, err := templates.Get("build-dependency-only")
+	require.NoError(t, err)
+
+	req, err := tmpl.Apply(Request{Package: "foo", Vulnerability: "CVE-2023-1", Impact: "Only used by the test suite."})
+	require.NoError(t, err)
+	assert.Equal(t, Request{
+		Package:       "foo",
+		Vulnerability: "CVE-2023-1",
+		Status:        vex.StatusNotAffected,
+		Justification: vex.VulnerableCodeNotInExecutePath,
+		Impact:        "Only used by the test suite.",
+	}, req, "fields already set must override the template")
+
+	_, err = tmpl.Apply(Request{Status: vex.StatusFixed})
+	assert.ErrorContains(t, err, `is for status "not_affected", not "fixed"`)
+
+	_, err = templates.Get("nope")
+	assert.ErrorContains(t, err, "must be one of: [build-dependency-only upstream-fix]")
+
+	_, err = DecodeTemplates(strings.NewReader("templates:\n  bad:\n    status: not_affected\n"))
+	assert.ErrorContains(t, err, `invalid advisory template "bad"`)
+}
+
+func TestLoadTemplates_missingFile(t *testing.T) {
+	templates, err := LoadTemplates(filepath.Join(t.TempDir(), TemplatesFile))
+	require.NoError(t, err)
+	assert.Empty(t, templates)
+}
@@ -0,0 +1,111 @@
+package advisory
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	"github.com/wolfi-dev/wolfictl/pkg/git"
+	"github.com/wolfi-dev/wolfictl/pkg/yamledit"
+	"gopkg.in/yaml.v3"
+)
+
+// TimelineOptions configures the Timeline operation.
+type TimelineOptions struct {
+	// AdvisoriesDir is the advisories repository directory. The package's
+	// advisory document is looked up at its top level, then in its ArchiveDir.
+	AdvisoriesDir string
+
+	// Package is the name of the package.
+	Package string
+
+	// Vulnerability is the ID of the advisory, as recorded in the document.
+	Vulnerability string
+
+	// WithActors looks up who recorded each event, using git blame. This requires
+	// AdvisoriesDir to be in a git repository.
+	WithActors bool
+}
+
+// TimelineEvent is an event of an advisory, along with who recorded it.
+type TimelineEvent struct {
+	advisoryconfigs.Entry
+
+	// Actor is the author of the commit that last changed the event. It's empty
+	// if it wasn't looked up or the event hasn't been committed yet.
+	Actor string
+}
+
+// Timeline returns the full event history of the given advisory, sorted by
+// timestamp.
+func Timeline(opts TimelineOptions) ([]TimelineEvent, error) {
+	path, err := findAdvisoryDocument(opts.AdvisoriesDir, opts.Package)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := yamledit.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	node, ok := doc.Get("advisories", opts.Vulnerability)
+	if !ok || node.Kind != yaml.SequenceNode {
+		return nil, fmt.Errorf("no advisory for %s in package %q", opts.Vulnerability, opts.Package)
+	}
+
+	var authors []string
+	if opts.WithActors {
+		rel, err := filepath.Rel(opts.AdvisoriesDir, path)
+		if err != nil {
+			return nil, err
+		}
+		authors, err = git.LineAuthors(opts.AdvisoriesDir, rel)
+		if err != nil {
+			return nil, fmt.Errorf("unable to look up who recorded the events: %w", err)
+		}
+	}
+
+	events := make([]TimelineEvent, 0, len(node.Content))
+	for _, item := range node.Content {
+		var e TimelineEvent
+		if err := item.Decode(&e.Entry); err != nil {
+			return nil, fmt.Errorf("unable to decode event at %s:%d: %w", path, item.Line, err)
+		}
+
+		// The event's first line is its first key, which is nearly always its
+		// timestamp and so is only changed when the event is recorded.
+		if i := item.Line - 1; i >= 0 && i < len(authors) {
+			e.Actor = authors[i]
+		}
+
+		events = append(events, e)
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Timestamp.Before(events[j].Timestamp)
+	})
+
+	return events, nil
+}
+
+// findAdvisoryDocument returns the path of the package's advisory document,
+// whether it's active or archived.
+func findAdvisoryDocument(advisoriesDir, packageName string) (string, error) {
+	name := fmt.Sprintf("%s.advisories.yaml", packageName)
+
+	for _, dir := range []string{advisoriesDir, filepath.Join(advisoriesDir, ArchiveDir)} {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return "", err
+		}
+	}
+
+	return "", fmt.Errorf("no advisories found for package %q", packageName)
+}
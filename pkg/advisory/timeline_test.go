@@ -0,0 +1,71 @@
+package advisory
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/openvex/go-vex/pkg/vex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeline(t *testing.T) {
+	const committed = `package:
+  name: foo
+
+advisories:
+  CVE-2023-1:
+    - timestamp: 2023-05-03T00:00:00Z
+      status: fixed
+      fixed-version: 1.2.3-r1
+    - timestamp: 2023-05-01T00:00:00Z
+      status: under_investigation
+`
+	const uncommitted = `    - timestamp: 2023-05-04T00:00:00Z
+      status: fixed
+      fixed-version: 1.2.3-r2
+`
+
+	dir := t.TempDir()
+	run := func(args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Alice", "GIT_AUTHOR_EMAIL=alice@example.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com",
+		)
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	path := filepath.Join(dir, "foo.advisories.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(committed), 0o600))
+	run("init", "-q", "-b", "main")
+	run("add", "-A")
+	run("commit", "-q", "-m", "add advisory")
+	require.NoError(t, os.WriteFile(path, []byte(committed+uncommitted), 0o600))
+
+	events, err := Timeline(TimelineOptions{
+		AdvisoriesDir: dir,
+		Package:       "foo",
+		Vulnerability: "CVE-2023-1",
+		WithActors:    true,
+	})
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+
+	assert.Equal(t, vex.StatusUnderInvestigation, events[0].Status)
+	assert.Equal(t, "Alice", events[0].Actor)
+	assert.Equal(t, "1.2.3-r1", events[1].FixedVersion)
+	assert.Equal(t, "Alice", events[1].Actor)
+	assert.Equal(t, "1.2.3-r2", events[2].FixedVersion)
+	assert.Empty(t, events[2].Actor)
+
+	_, err = Timeline(TimelineOptions{AdvisoriesDir: dir, Package: "foo", Vulnerability: "CVE-2023-2"})
+	assert.ErrorContains(t, err, "no advisory for CVE-2023-2")
+
+	_, err = Timeline(TimelineOptions{AdvisoriesDir: dir, Package: "bar", Vulnerability: "CVE-2023-1"})
+	assert.ErrorContains(t, err, `no advisories found for package "bar"`)
+}
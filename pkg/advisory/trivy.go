@@ -0,0 +1,157 @@
+package advisory
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/openvex/go-vex/pkg/vex"
+	"github.com/samber/lo"
+	"github.com/wolfi-dev/wolfictl/pkg/advisory/secdb"
+	"github.com/wolfi-dev/wolfictl/pkg/configs"
+	"github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+)
+
+// TrivyDB is advisory data in the bucket layout of Trivy's vulnerability
+// database (see https://github.com/aquasecurity/trivy-db), as JSON. Each
+// top-level key is a bbolt bucket, and the values within them are the JSON
+// values Trivy stores, so the data can be loaded bucket by bucket into a
+// database for Trivy's custom vulnerability sources.
+type TrivyDB struct {
+	// Platform is the name of the bucket of advisories, which is also the ID of
+	// the data source, e.g. "wolfi".
+	Platform string
+
+	// DataSource describes where the advisories come from.
+	DataSource TrivyDataSource
+
+	// Advisories maps each package name to its advisories, by vulnerability ID.
+	Advisories map[string]map[string]TrivyAdvisory
+
+	// Vulnerabilities maps vulnerability IDs to the distro's own details of the
+	// vulnerability, for those with a severity override. Since the details
+	// aren't per package, the highest severity among the overrides is used.
+	Vulnerabilities map[string]TrivyVulnerability
+}
+
+type TrivyDataSource struct {
+	ID   string
+	Name string
+	URL  string
+}
+
+// TrivyAdvisory is a package's advisory for a vulnerability. As in Alpine's
+// secdb, packages that aren't affected have a fixed version of "0", so that
+// every version is considered fixed.
+type TrivyAdvisory struct {
+	FixedVersion string `json:",omitempty"`
+	Status       string `json:",omitempty"`
+}
+
+type TrivyVulnerability struct {
+	// VendorSeverity maps data source IDs to Trivy's severities (1 for low up
+	// to 4 for critical).
+	VendorSeverity map[string]int `json:",omitempty"`
+}
+
+// trivySeverities maps severities (see scan.Severities) to Trivy's.
+var trivySeverities = map[string]int{
+	"Unknown":    0,
+	"Negligible": 1,
+	"Low":        1,
+	"Medium":     2,
+	"High":       3,
+	"Critical":   4,
+}
+
+// MarshalJSON encodes the database as an object of its buckets: "data-source",
+// the platform's bucket, and "vulnerability".
+func (db TrivyDB) MarshalJSON() ([]byte, error) {
+	buckets := map[string]any{
+		"data-source": map[string]TrivyDataSource{db.Platform: db.DataSource},
+		db.Platform:   db.Advisories,
+	}
+	if len(db.Vulnerabilities) > 0 {
+		buckets["vulnerability"] = db.Vulnerabilities
+	}
+
+	return json.Marshal(buckets)
+}
+
+type ExportTrivyOptions struct {
+	AdvisoryCfgIndices []*configs.Index[advisory.Document]
+
+	// Platform is the name of the bucket of advisories and the ID of the data
+	// source, e.g. "wolfi".
+	Platform string
+
+	// DataSourceName and DataSourceURL describe the data source, e.g. "Wolfi
+	// Security Data" and "https://wolfi.dev".
+	DataSourceName string
+	DataSourceURL  string
+}
+
+// ExportTrivy returns the advisory data as a TrivyDB, based on the latest public
+// event of each advisory. Fixed and not affected packages are listed with
+// their fixed version, and affected packages without one. Advisories still
+// under investigation are left out.
+func ExportTrivy(opts ExportTrivyOptions) (*TrivyDB, error) {
+	if opts.Platform == "" {
+		return nil, fmt.Errorf("platform is required")
+	}
+
+	db := &TrivyDB{
+		Platform: opts.Platform,
+		DataSource: TrivyDataSource{
+			ID:   opts.Platform,
+			Name: opts.DataSourceName,
+			URL:  opts.DataSourceURL,
+		},
+		Advisories:      make(map[string]map[string]TrivyAdvisory),
+		Vulnerabilities: make(map[string]TrivyVulnerability),
+	}
+
+	for _, index := range opts.AdvisoryCfgIndices {
+		for _, cfg := range index.Select().Configurations() {
+			vulns := lo.Keys(cfg.Advisories)
+			sort.Strings(vulns)
+
+			for _, vuln := range vulns {
+				entries := PublicEntries(cfg.Advisories[vuln])
+				if len(entries) == 0 {
+					continue
+				}
+
+				var adv TrivyAdvisory
+				latest := Latest(entries)
+				switch latest.Status {
+				case vex.StatusFixed:
+					adv = TrivyAdvisory{FixedVersion: latest.FixedVersion, Status: string(vex.StatusFixed)}
+				case vex.StatusNotAffected:
+					adv = TrivyAdvisory{FixedVersion: secdb.NAK, Status: string(vex.StatusNotAffected)}
+				case vex.StatusAffected:
+					adv = TrivyAdvisory{Status: string(vex.StatusAffected)}
+				default:
+					continue
+				}
+
+				if db.Advisories[cfg.Package.Name] == nil {
+					db.Advisories[cfg.Package.Name] = make(map[string]TrivyAdvisory)
+				}
+				db.Advisories[cfg.Package.Name][vuln] = adv
+
+				if o := SeverityOverrideOf(entries); o != nil {
+					severity := trivySeverities[o.Severity]
+					if existing, ok := db.Vulnerabilities[vuln]; ok && existing.VendorSeverity[opts.Platform] > severity {
+						continue
+					}
+					db.Vulnerabilities[vuln] = TrivyVulnerability{
+						VendorSeverity: map[string]int{opts.Platform: severity},
+					}
+				}
+			}
+		}
+	}
+
+	return db, nil
+}
@@ -0,0 +1,77 @@
+package advisory
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wolfi-dev/wolfictl/pkg/configs"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
+)
+
+func TestExportTrivy(t *testing.T) {
+	const foo = `package:
+  name: foo
+
+advisories:
+  CVE-2023-1:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: under_investigation
+      severity-override:
+        severity: Low
+        rationale: not network reachable as packaged
+    - timestamp: 2023-05-02T00:00:00Z
+      status: fixed
+      fixed-version: 1.2.3-r1
+  CVE-2023-2:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: not_affected
+      justification: vulnerable_code_not_present
+  CVE-2023-3:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: affected
+      action: wait for upstream fix
+  CVE-2023-4:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: under_investigation
+`
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "foo.advisories.yaml"), []byte(foo), 0o644))
+
+	cfgs, err := advisoryconfigs.NewIndex(rwos.DirFS(dir))
+	require.NoError(t, err)
+
+	db, err := ExportTrivy(ExportTrivyOptions{
+		AdvisoryCfgIndices: []*configs.Index[advisoryconfigs.Document]{cfgs},
+		Platform:           "wolfi",
+		DataSourceName:     "Wolfi Security Data",
+		DataSourceURL:      "https://wolfi.dev",
+	})
+	require.NoError(t, err)
+
+	b, err := json.Marshal(db)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+  "data-source": {
+    "wolfi": {"ID": "wolfi", "Name": "Wolfi Security Data", "URL": "https://wolfi.dev"}
+  },
+  "wolfi": {
+    "foo": {
+      "CVE-2023-1": {"FixedVersion": "1.2.3-r1", "Status": "fixed"},
+      "CVE-2023-2": {"FixedVersion": "0", "Status": "not_affected"},
+      "CVE-2023-3": {"Status": "affected"}
+    }
+  },
+  "vulnerability": {
+    "CVE-2023-1": {"VendorSeverity": {"wolfi": 1}}
+  }
+}`, string(b))
+
+	_, err = ExportTrivy(ExportTrivyOptions{AdvisoryCfgIndices: []*configs.Index[advisoryconfigs.Document]{cfgs}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,272 @@
+package advisory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v50/github"
+	"github.com/openvex/go-vex/pkg/vex"
+	"github.com/wolfi-dev/wolfictl/pkg/configs"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	"golang.org/x/exp/slices"
+)
+
+// UpstreamRefKind is the kind of thing an upstream fix reference points to.
+type UpstreamRefKind string
+
+const (
+	UpstreamIssue       UpstreamRefKind = "issue"
+	UpstreamPullRequest UpstreamRefKind = "pull request"
+	UpstreamCommit      UpstreamRefKind = "commit"
+)
+
+// UpstreamRef is a parsed reference to an upstream issue, pull request, or
+// commit on GitHub.
+type UpstreamRef struct {
+	Kind  UpstreamRefKind
+	Owner string
+	Repo  string
+
+	// Number is the issue or pull request number.
+	Number int
+
+	// SHA is the commit hash.
+	SHA string
+}
+
+// ParseUpstreamURL parses the URL of a pending upstream fix, which must be a
+// GitHub issue, pull request, or commit URL.
+func ParseUpstreamURL(rawURL string) (UpstreamRef, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return UpstreamRef{}, fmt.Errorf("upstream fix URL %q is invalid: %w", rawURL, err)
+	}
+
+	if u.Scheme != "https" || u.Host != "github.com" {
+		return UpstreamRef{}, fmt.Errorf("upstream fix URL %q must be a https://github.com URL", rawURL)
+	}
+
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) < 4 {
+		return UpstreamRef{}, fmt.Errorf("upstream fix URL %q must be a GitHub issue, pull request, or commit URL", rawURL)
+	}
+
+	ref := UpstreamRef{Owner: parts[0], Repo: parts[1]}
+	switch parts[2] {
+	case "issues", "pull":
+		ref.Kind = UpstreamIssue
+		if parts[2] == "pull" {
+			ref.Kind = UpstreamPullRequest
+		}
+		ref.Number, err = strconv.Atoi(parts[3])
+		if err != nil || ref.Number <= 0 {
+			return UpstreamRef{}, fmt.Errorf("upstream fix URL %q has an invalid %s number", rawURL, ref.Kind)
+		}
+
+	case "commit":
+		ref.Kind = UpstreamCommit
+		ref.SHA = parts[3]
+
+	default:
+		return UpstreamRef{}, fmt.Errorf("upstream fix URL %q must be a GitHub issue, pull request, or commit URL", rawURL)
+	}
+
+	return ref, nil
+}
+
+func (r UpstreamRef) String() string {
+	if r.Kind == UpstreamCommit {
+		return fmt.Sprintf("%s/%s@%s", r.Owner, r.Repo, r.SHA)
+	}
+
+	return fmt.Sprintf("%s/%s#%d", r.Owner, r.Repo, r.Number)
+}
+
+// UpstreamStatus is the progress of an upstream fix.
+type UpstreamStatus struct {
+	// Ready is true once the fix is done upstream, and the advisory can
+	// progress (e.g. to a fixed event, once the package is updated).
+	Ready bool
+
+	// Detail describes the status, e.g. "pull request merged".
+	Detail string
+}
+
+// UpstreamChecker checks the progress of upstream fixes.
+type UpstreamChecker interface {
+	CheckUpstream(ctx context.Context, ref UpstreamRef) (UpstreamStatus, error)
+}
+
+// GitHubUpstreamChecker is an UpstreamChecker that uses the GitHub API. Issues
+// are ready once closed as completed, pull requests once merged, and commits
+// once they're in the latest tagged release.
+type GitHubUpstreamChecker struct {
+	Client *github.Client
+}
+
+func (c GitHubUpstreamChecker) CheckUpstream(ctx context.Context, ref UpstreamRef) (UpstreamStatus, error) {
+	switch ref.Kind {
+	case UpstreamIssue:
+		issue, _, err := c.Client.Issues.Get(ctx, ref.Owner, ref.Repo, ref.Number)
+		if err != nil {
+			return UpstreamStatus{}, fmt.Errorf("unable to get issue %s: %w", ref, err)
+		}
+		if issue.GetState() != "closed" {
+			return UpstreamStatus{Detail: "issue open"}, nil
+		}
+		if issue.GetStateReason() == "not_planned" {
+			return UpstreamStatus{Detail: "issue closed as not planned"}, nil
+		}
+		return UpstreamStatus{Ready: true, Detail: "issue closed"}, nil
+
+	case UpstreamPullRequest:
+		pr, _, err := c.Client.PullRequests.Get(ctx, ref.Owner, ref.Repo, ref.Number)
+		if err != nil {
+			return UpstreamStatus{}, fmt.Errorf("unable to get pull request %s: %w", ref, err)
+		}
+		if pr.GetMerged() {
+			return UpstreamStatus{Ready: true, Detail: "pull request merged"}, nil
+		}
+		if pr.GetState() == "closed" {
+			return UpstreamStatus{Detail: "pull request closed without merging"}, nil
+		}
+		return UpstreamStatus{Detail: "pull request open"}, nil
+
+	case UpstreamCommit:
+		tag, err := c.latestTag(ctx, ref)
+		if err != nil {
+			return UpstreamStatus{}, err
+		}
+		if tag == "" {
+			return UpstreamStatus{Detail: "no tagged releases"}, nil
+		}
+
+		// The tag contains the commit if it's identical to or ahead of it.
+		cmp, _, err := c.Client.Repositories.CompareCommits(ctx, ref.Owner, ref.Repo, ref.SHA, tag, nil)
+		if err != nil {
+			return UpstreamStatus{}, fmt.Errorf("unable to compare commit %s with %s: %w", ref, tag, err)
+		}
+		if s := cmp.GetStatus(); s == "ahead" || s == "identical" {
+			return UpstreamStatus{Ready: true, Detail: fmt.Sprintf("commit released in %s", tag)}, nil
+		}
+		return UpstreamStatus{Detail: fmt.Sprintf("commit not in latest release %s", tag)}, nil
+	}
+
+	return UpstreamStatus{}, fmt.Errorf("unsupported upstream fix reference %s", ref)
+}
+
+// latestTag returns the tag of the repo's latest release, or if it has no
+// releases, its most recent tag. It returns "" if the repo has neither.
+func (c GitHubUpstreamChecker) latestTag(ctx context.Context, ref UpstreamRef) (string, error) {
+	release, resp, err := c.Client.Repositories.GetLatestRelease(ctx, ref.Owner, ref.Repo)
+	if err == nil {
+		return release.GetTagName(), nil
+	}
+	if resp == nil || resp.StatusCode != http.StatusNotFound {
+		return "", fmt.Errorf("unable to get latest release of %s/%s: %w", ref.Owner, ref.Repo, err)
+	}
+
+	tags, _, err := c.Client.Repositories.ListTags(ctx, ref.Owner, ref.Repo, &github.ListOptions{PerPage: 1})
+	if err != nil {
+		return "", fmt.Errorf("unable to list tags of %s/%s: %w", ref.Owner, ref.Repo, err)
+	}
+	if len(tags) == 0 {
+		return "", nil
+	}
+
+	return tags[0].GetName(), nil
+}
+
+type PollUpstreamOptions struct {
+	// AdvisoryCfgs is the Index of advisories on which to operate.
+	AdvisoryCfgs *configs.Index[advisoryconfigs.Document]
+
+	// SelectedPackages limits polling to these packages. If empty, all packages
+	// are polled.
+	SelectedPackages []string
+
+	// Checker checks the progress of each upstream fix.
+	Checker UpstreamChecker
+}
+
+// PendingUpstreamFixResult is the progress of the upstream fix that an advisory
+// is waiting for.
+type PendingUpstreamFixResult struct {
+	Package       string
+	Vulnerability string
+	URL           string
+
+	Status UpstreamStatus
+
+	// Err is set if the progress couldn't be checked.
+	Err error
+}
+
+// PollUpstream checks the upstream fix of each advisory whose latest event is
+// pending one, and returns the results, sorted by package and then
+// vulnerability. Fixes that can't be checked are reported in the results
+// rather than stopping the poll, unless the context is done.
+func PollUpstream(ctx context.Context, opts PollUpstreamOptions) ([]PendingUpstreamFixResult, error) {
+	var results []PendingUpstreamFixResult
+
+	for _, doc := range opts.AdvisoryCfgs.Select().Configurations() {
+		if len(opts.SelectedPackages) > 0 && !slices.Contains(opts.SelectedPackages, doc.Package.Name) {
+			continue
+		}
+
+		for vulnID, entries := range doc.Advisories {
+			if len(entries) == 0 {
+				continue
+			}
+			latest := latestEntry(entries)
+			if latest.PendingUpstreamFix == nil {
+				continue
+			}
+
+			result := PendingUpstreamFixResult{
+				Package:       doc.Package.Name,
+				Vulnerability: vulnID,
+				URL:           latest.PendingUpstreamFix.URL,
+			}
+
+			ref, err := ParseUpstreamURL(result.URL)
+			if err == nil {
+				result.Status, err = opts.Checker.CheckUpstream(ctx, ref)
+			}
+			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+				return nil, err
+			}
+			result.Err = err
+
+			results = append(results, result)
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Package != results[j].Package {
+			return results[i].Package < results[j].Package
+		}
+		return results[i].Vulnerability < results[j].Vulnerability
+	})
+
+	return results, nil
+}
+
+func validatePendingUpstreamFix(fix *advisoryconfigs.PendingUpstreamFix, status vex.Status) error {
+	if fix == nil {
+		return nil
+	}
+
+	if status != vex.StatusAffected {
+		return fmt.Errorf("pending upstream fix must only be set if status is %q", vex.StatusAffected)
+	}
+
+	_, err := ParseUpstreamURL(fix.URL)
+	return err
+}
@@ -0,0 +1,162 @@
+package advisory
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-github/v50/github"
+	"github.com/openvex/go-vex/pkg/vex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
+)
+
+func TestParseUpstreamURL(t *testing.T) {
+	tests := []struct {
+		url     string
+		want    UpstreamRef
+		wantErr bool
+	}{
+		{url: "https://github.com/golang/go/issues/61234", want: UpstreamRef{Kind: UpstreamIssue, Owner: "golang", Repo: "go", Number: 61234}},
+		{url: "https://github.com/golang/go/pull/61235/files", want: UpstreamRef{Kind: UpstreamPullRequest, Owner: "golang", Repo: "go", Number: 61235}},
+		{url: "https://github.com/golang/go/commit/0123abc", want: UpstreamRef{Kind: UpstreamCommit, Owner: "golang", Repo: "go", SHA: "0123abc"}},
+		{url: "https://gitlab.com/golang/go/issues/1", wantErr: true},
+		{url: "https://github.com/golang/go/issues/abc", wantErr: true},
+		{url: "https://github.com/golang/go/releases/tag/v1", wantErr: true},
+		{url: "https://github.com/golang/go", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			got, err := ParseUpstreamURL(tt.url)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGitHubUpstreamChecker(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/repos/o/r/issues/1":
+			fmt.Fprint(w, `{"number": 1, "state": "closed", "state_reason": "completed"}`)
+		case "/repos/o/r/issues/2":
+			fmt.Fprint(w, `{"number": 2, "state": "closed", "state_reason": "not_planned"}`)
+		case "/repos/o/r/pulls/3":
+			fmt.Fprint(w, `{"number": 3, "state": "closed", "merged": true}`)
+		case "/repos/o/r/releases/latest":
+			fmt.Fprint(w, `{"tag_name": "v1.2.3"}`)
+		case "/repos/o/r/compare/abc...v1.2.3":
+			fmt.Fprint(w, `{"status": "ahead"}`)
+		case "/repos/o/r/compare/def...v1.2.3":
+			fmt.Fprint(w, `{"status": "diverged"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := github.NewClient(server.Client())
+	var err error
+	client.BaseURL, err = url.Parse(server.URL + "/")
+	require.NoError(t, err)
+	checker := GitHubUpstreamChecker{Client: client}
+
+	tests := []struct {
+		ref  UpstreamRef
+		want UpstreamStatus
+	}{
+		{UpstreamRef{Kind: UpstreamIssue, Owner: "o", Repo: "r", Number: 1}, UpstreamStatus{Ready: true, Detail: "issue closed"}},
+		{UpstreamRef{Kind: UpstreamIssue, Owner: "o", Repo: "r", Number: 2}, UpstreamStatus{Detail: "issue closed as not planned"}},
+		{UpstreamRef{Kind: UpstreamPullRequest, Owner: "o", Repo: "r", Number: 3}, UpstreamStatus{Ready: true, Detail: "pull request merged"}},
+		{UpstreamRef{Kind: UpstreamCommit, Owner: "o", Repo: "r", SHA: "abc"}, UpstreamStatus{Ready: true, Detail: "commit released in v1.2.3"}},
+		{UpstreamRef{Kind: UpstreamCommit, Owner: "o", Repo: "r", SHA: "def"}, UpstreamStatus{Detail: "commit not in latest release v1.2.3"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref.String(), func(t *testing.T) {
+			got, err := checker.CheckUpstream(context.Background(), tt.ref)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+type fakeUpstreamChecker map[string]UpstreamStatus
+
+func (c fakeUpstreamChecker) CheckUpstream(_ context.Context, ref UpstreamRef) (UpstreamStatus, error) {
+	s, ok := c[ref.String()]
+	if !ok {
+		return UpstreamStatus{}, fmt.Errorf("unknown %s", ref)
+	}
+	return s, nil
+}
+
+func TestPollUpstream(t *testing.T) {
+	const foo = `package:
+  name: foo
+
+advisories:
+  CVE-2023-0001:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: affected
+      action: wait for upstream fix
+      pending-upstream-fix:
+        url: https://github.com/o/r/pull/3
+  CVE-2023-0002:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: affected
+      action: wait for upstream fix
+      pending-upstream-fix:
+        url: https://github.com/o/r/issues/9
+  CVE-2023-0003:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: affected
+      action: wait for upstream fix
+      pending-upstream-fix:
+        url: https://github.com/o/r/issues/4
+    - timestamp: 2023-05-02T00:00:00Z
+      status: fixed
+      fixed-version: 1.2.3-r1
+`
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "foo.advisories.yaml"), []byte(foo), 0o644))
+
+	cfgs, err := advisoryconfigs.NewIndex(rwos.DirFS(dir))
+	require.NoError(t, err)
+	assert.Nil(t, Validate(ValidateOptions{AdvisoryCfgs: cfgs}))
+
+	checker := fakeUpstreamChecker{"o/r#3": {Ready: true, Detail: "pull request merged"}}
+	results, err := PollUpstream(context.Background(), PollUpstreamOptions{AdvisoryCfgs: cfgs, Checker: checker})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	assert.Equal(t, "CVE-2023-0001", results[0].Vulnerability)
+	assert.Equal(t, UpstreamStatus{Ready: true, Detail: "pull request merged"}, results[0].Status)
+	assert.NoError(t, results[0].Err)
+
+	assert.Equal(t, "CVE-2023-0002", results[1].Vulnerability)
+	assert.Error(t, results[1].Err)
+}
+
+func TestValidatePendingUpstreamFix(t *testing.T) {
+	fix := &advisoryconfigs.PendingUpstreamFix{URL: "https://github.com/o/r/issues/1"}
+
+	assert.NoError(t, validatePendingUpstreamFix(nil, vex.StatusFixed))
+	assert.NoError(t, validatePendingUpstreamFix(fix, vex.StatusAffected))
+	assert.ErrorContains(t, validatePendingUpstreamFix(fix, vex.StatusUnderInvestigation), "only be set if status is")
+	assert.Error(t, validatePendingUpstreamFix(&advisoryconfigs.PendingUpstreamFix{URL: "https://example.com/1"}, vex.StatusAffected))
+}
@@ -1,14 +1,18 @@
 package advisory
 
 import (
+	"errors"
 	"fmt"
+	"regexp"
 	"strings"
 
 	"chainguard.dev/melange/pkg/build"
 	"github.com/hashicorp/go-multierror"
+	apkversion "github.com/knqyf263/go-apk-version"
 	"github.com/openvex/go-vex/pkg/vex"
 	"github.com/wolfi-dev/wolfictl/pkg/configs"
 	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	"gitlab.alpinelinux.org/alpine/go/repository"
 	"golang.org/x/exp/slices"
 )
 
@@ -19,11 +23,20 @@ type ValidateOptions struct {
 	// AdvisoryCfgs is the Index of advisories on which to operate.
 	AdvisoryCfgs *configs.Index[advisoryconfigs.Document]
 
+	// ArchivedAdvisoryCfgs is the Index of archived advisories (see ArchiveDir),
+	// if any. Archived advisories are validated separately from active ones.
+	ArchivedAdvisoryCfgs *configs.Index[advisoryconfigs.Document]
+
 	// PackageRepositoryURL is the URL to the distro's package repository (e.g. "https://packages.wolfi.dev/os") (not used yet).
 	PackageRepositoryURL string
 
 	// The Arches to consider during validation (e.g. "x86_64") (not used yet).
 	Arches []string
+
+	// APKIndexes are the published APKINDEXes, keyed by architecture. If set,
+	// each fixed event's version must have been published for each architecture
+	// (see FindUnpublishedFixes).
+	APKIndexes map[string]*repository.ApkIndex
 }
 
 func Validate(opts ValidateOptions) *multierror.Error {
@@ -33,6 +46,12 @@ func Validate(opts ValidateOptions) *multierror.Error {
 
 	for _, cfg := range advCfgs {
 		err := validateAdvisoryDocument(cfg)
+		if cfg.Archived != nil {
+			if err == nil {
+				err = newMultierror()
+			}
+			err = multierror.Append(err, fmt.Errorf("archived advisories must be moved to the %q directory", ArchiveDir))
+		}
 		if err != nil {
 			merr = multierror.Append(merr, fmt.Errorf(
 				"issue(s) found with advisories file for package %q: %w",
@@ -42,6 +61,72 @@ func Validate(opts ValidateOptions) *multierror.Error {
 		}
 	}
 
+	allCfgs := advCfgs
+	if opts.ArchivedAdvisoryCfgs != nil {
+		allCfgs = append(append([]advisoryconfigs.Document{}, advCfgs...), opts.ArchivedAdvisoryCfgs.Select().Configurations()...)
+	}
+	for _, err := range duplicateCGAIDErrors(allCfgs) {
+		merr = multierror.Append(merr, err)
+	}
+
+	if opts.APKIndexes != nil {
+		for _, f := range FindUnpublishedFixes(opts.AdvisoryCfgs, opts.APKIndexes) {
+			merr = multierror.Append(merr, errors.New(f.String()))
+		}
+	}
+
+	if opts.ArchivedAdvisoryCfgs != nil {
+		for _, cfg := range opts.ArchivedAdvisoryCfgs.Select().Configurations() {
+			err := validateArchivedDocument(cfg)
+			if opts.AdvisoryCfgs.Select().WhereName(cfg.Package.Name).Len() > 0 {
+				if err == nil {
+					err = newMultierror()
+				}
+				err = multierror.Append(err, fmt.Errorf("package also has active advisories"))
+			}
+			if err != nil {
+				merr = multierror.Append(merr, fmt.Errorf(
+					"issue(s) found with archived advisories file for package %q: %w",
+					cfg.Package.Name,
+					err,
+				))
+			}
+		}
+	}
+
+	if merr.Len() > 0 {
+		return merr
+	}
+
+	return nil
+}
+
+// validateArchivedDocument validates an archived advisory document, which must
+// be marked as archived and mustn't have changed since it was archived.
+func validateArchivedDocument(cfg advisoryconfigs.Document) *multierror.Error {
+	merr := validateAdvisoryDocument(cfg)
+	if merr == nil {
+		merr = newMultierror()
+	}
+
+	if cfg.Archived == nil {
+		merr = multierror.Append(merr, fmt.Errorf("archived advisories must have an 'archived' section"))
+		return merr
+	}
+
+	if cfg.Archived.Timestamp.IsZero() {
+		merr = multierror.Append(merr, fmt.Errorf("archived timestamp must not be zero"))
+	}
+
+	for advID, entries := range cfg.Advisories {
+		for _, e := range entries {
+			if e.Timestamp.After(cfg.Archived.Timestamp) {
+				merr = multierror.Append(merr, fmt.Errorf("advisory %q has an event recorded after the package was archived", advID))
+				break
+			}
+		}
+	}
+
 	if merr.Len() > 0 {
 		return merr
 	}
@@ -68,6 +153,18 @@ func validateAdvisoryDocument(cfg advisoryconfigs.Document) *multierror.Error {
 
 	for advID, advEntries := range cfg.Advisories {
 		err := validateAdvisory(advEntries)
+		if !isKnownVulnerabilityID(advID) {
+			if err == nil {
+				err = newMultierror()
+			}
+			err = multierror.Append(err, fmt.Errorf("advisory ID must be a CVE, GHSA, or Go vulnerability ID"))
+		}
+		for _, cgaErr := range validateCGAIDAliases(cfg.Aliases[advID]) {
+			if err == nil {
+				err = newMultierror()
+			}
+			err = multierror.Append(err, cgaErr)
+		}
 		if err != nil {
 			merr = multierror.Append(merr, fmt.Errorf(
 				"issue(s) found with advisory %q: %w",
@@ -104,6 +201,14 @@ func validateAdvisory(advEntries []advisoryconfigs.Entry) *multierror.Error {
 				err,
 			))
 		}
+
+		if i > 0 && advEntry.Timestamp.Before(advEntries[i-1].Timestamp) {
+			merr = multierror.Append(merr, fmt.Errorf(
+				"event %d (of %d) has a timestamp earlier than the event before it, but events must be in chronological order",
+				i+1,
+				len(advEntries),
+			))
+		}
 	}
 
 	if merr.Len() > 0 {
@@ -113,6 +218,24 @@ func validateAdvisory(advEntries []advisoryconfigs.Entry) *multierror.Error {
 	return nil
 }
 
+// vulnerabilityIDPatterns match the formats of vulnerability IDs that
+// advisories can be recorded under.
+var vulnerabilityIDPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`^CVE-\d{4}-\d{4,}$`),
+	regexp.MustCompile(`^GHSA(-[23456789cfghjmpqrvwx]{4}){3}$`),
+	regexp.MustCompile(`^GO-\d{4}-\d{4,}$`),
+}
+
+func isKnownVulnerabilityID(id string) bool {
+	for _, p := range vulnerabilityIDPatterns {
+		if p.MatchString(id) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func validateAdvisoryEntry(entry advisoryconfigs.Entry) *multierror.Error {
 	merr := newMultierror()
 
@@ -145,6 +268,29 @@ func validateAdvisoryEntry(entry advisoryconfigs.Entry) *multierror.Error {
 		merr = multierror.Append(merr, err)
 	}
 
+	if err := validateSeverityOverride(entry.SeverityOverride); err != nil {
+		merr = multierror.Append(merr, err)
+	}
+
+	if err := validateDetection(entry.Detection); err != nil {
+		merr = multierror.Append(merr, err)
+	}
+
+	if err := validatePendingUpstreamFix(entry.PendingUpstreamFix, entry.Status); err != nil {
+		merr = multierror.Append(merr, err)
+	}
+
+	if err := validateReferences(entry.References); err != nil {
+		merr = multierror.Append(merr, err)
+	}
+
+	for _, field := range entry.InternalFields {
+		if !slices.Contains(RedactableFields, field) {
+			err := fmt.Errorf("internal field is %q but must be one of [%v]", field, strings.Join(RedactableFields, ", "))
+			merr = multierror.Append(merr, err)
+		}
+	}
+
 	if merr.Len() > 0 {
 		return merr
 	}
@@ -161,6 +307,11 @@ func validateFixedVersion(fixedVersion string, status vex.Status) *multierror.Er
 				merr,
 				fmt.Errorf("fixed version must not be empty if status is %q", vex.StatusFixed),
 			)
+		} else if _, err := apkversion.NewVersion(fixedVersion); err != nil {
+			merr = multierror.Append(
+				merr,
+				fmt.Errorf("fixed version %q is not a valid APK version: %w", fixedVersion, err),
+			)
 		}
 	} else {
 		if fixedVersion != "" {
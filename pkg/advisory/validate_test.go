@@ -0,0 +1,96 @@
+package advisory
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/openvex/go-vex/pkg/vex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
+)
+
+func TestValidateAdvisoryDocument(t *testing.T) {
+	t1 := time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)
+	t2 := t1.Add(24 * time.Hour)
+
+	cases := []struct {
+		name       string
+		advisories advisoryconfigs.Advisories
+		wantErr    string
+	}{
+		{
+			name: "valid",
+			advisories: advisoryconfigs.Advisories{
+				"CVE-2023-1234": {
+					{Timestamp: t1, Status: vex.StatusUnderInvestigation},
+					{Timestamp: t2, Status: vex.StatusFixed, FixedVersion: "1.2.3-r1"},
+				},
+				"GHSA-2h5h-59f5-c5x9": {{Timestamp: t1, Status: vex.StatusUnderInvestigation}},
+				"GO-2023-1571":        {{Timestamp: t1, Status: vex.StatusUnderInvestigation}},
+			},
+		},
+		{
+			name: "events out of order",
+			advisories: advisoryconfigs.Advisories{
+				"CVE-2023-1234": {
+					{Timestamp: t2, Status: vex.StatusUnderInvestigation},
+					{Timestamp: t1, Status: vex.StatusFixed, FixedVersion: "1.2.3-r1"},
+				},
+			},
+			wantErr: "chronological order",
+		},
+		{
+			name: "invalid fixed version",
+			advisories: advisoryconfigs.Advisories{
+				"CVE-2023-1234": {{Timestamp: t1, Status: vex.StatusFixed, FixedVersion: "not a version"}},
+			},
+			wantErr: "not a valid APK version",
+		},
+		{
+			name: "unknown ID format",
+			advisories: advisoryconfigs.Advisories{
+				"CVE-23-1": {{Timestamp: t1, Status: vex.StatusUnderInvestigation}},
+			},
+			wantErr: "advisory ID must be",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateAdvisoryDocument(advisoryconfigs.Document{
+				Package:    advisoryconfigs.Package{Name: "foo"},
+				Advisories: tt.advisories,
+			})
+
+			if tt.wantErr == "" {
+				assert.Nil(t, err)
+				return
+			}
+			require.NotNil(t, err)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestValidate_duplicatePackage(t *testing.T) {
+	dir := t.TempDir()
+	doc := []byte(`package:
+  name: foo
+
+advisories:
+  CVE-2023-1234:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: under_investigation
+`)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "foo.advisories.yaml"), doc, 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "foo-copy.advisories.yaml"), doc, 0o644))
+
+	// The index refuses to load a second advisories file for the same package,
+	// so the duplicate is reported before any other validation.
+	_, err := advisoryconfigs.NewIndex(rwos.DirFS(dir))
+	assert.ErrorContains(t, err, `item named "foo"`)
+}
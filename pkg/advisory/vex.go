@@ -0,0 +1,255 @@
+package advisory
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/openvex/go-vex/pkg/vex"
+	"github.com/samber/lo"
+	"github.com/wolfi-dev/wolfictl/pkg/configs"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+)
+
+// GenerateVEXOptions configures the GenerateVEX operation.
+type GenerateVEXOptions struct {
+	// AdvisoryCfgs is the Index of advisories to generate statements from.
+	AdvisoryCfgs *configs.Index[advisoryconfigs.Document]
+
+	// Packages limits the statements to the advisories of these packages. If
+	// empty, statements are generated for all packages.
+	Packages []string
+
+	// ProductNamespace is used to build the products' purls, as in
+	// "pkg:apk/<namespace>/<package>".
+	ProductNamespace string
+
+	// Author and AuthorRole identify who issues the document. If empty, OpenVEX's
+	// defaults are used.
+	Author, AuthorRole string
+
+	// Now is the time the document is issued. If zero, the time given by
+	// SOURCE_DATE_EPOCH is used, if set, and otherwise the latest timestamp of the
+	// document's statements.
+	Now time.Time
+}
+
+// GenerateVEX returns an OpenVEX document with a statement for each advisory of
+// the selected packages, based on the advisory's latest public event:
+//
+//   - fixed advisories state that the package's fixed version is fixed
+//   - not_affected advisories state that every version of the package is not
+//     affected, with the justification and impact statement
+//   - affected advisories state that every version of the package is affected,
+//     with the action statement
+//   - under_investigation advisories are stated as such
+//
+// Products are identified by purls. The document's ID is derived from its
+// content, including its timestamp, so regenerating unchanged advisory data
+// yields the same ID unless Now is set to a different time.
+func GenerateVEX(opts GenerateVEXOptions) (*vex.VEX, error) {
+	if opts.ProductNamespace == "" {
+		return nil, fmt.Errorf("product namespace is required")
+	}
+
+	for _, pkg := range opts.Packages {
+		if opts.AdvisoryCfgs.Select().WhereName(pkg).Len() == 0 {
+			return nil, fmt.Errorf("no advisories found for package %q", pkg)
+		}
+	}
+
+	v := vex.New()
+	v.Tooling = "wolfictl"
+	if opts.Author != "" {
+		v.Author = opts.Author
+	}
+	if opts.AuthorRole != "" {
+		v.AuthorRole = opts.AuthorRole
+	}
+	for _, a := range List(opts.AdvisoryCfgs, ListFilter{Packages: opts.Packages}) {
+		entries := PublicEntries(a.Entries)
+		latest := Latest(entries)
+		if latest == nil {
+			continue
+		}
+
+		v.Statements = append(v.Statements, vexStatement(opts.ProductNamespace, a.Package, a.Vulnerability, *latest, SeverityOverrideOf(entries)))
+	}
+
+	switch {
+	case !opts.Now.IsZero():
+		now := opts.Now
+		v.Timestamp = &now
+	case os.Getenv("SOURCE_DATE_EPOCH") == "" && len(v.Statements) > 0:
+		// vex.New dated the document with the current time.
+		v.Timestamp = latestStatementTimestamp(v.Statements)
+	}
+
+	if err := setCanonicalID(&v); err != nil {
+		return nil, err
+	}
+
+	return &v, nil
+}
+
+// latestStatementTimestamp returns the latest timestamp of the given statements.
+func latestStatementTimestamp(statements []vex.Statement) *time.Time {
+	var latest time.Time
+	for _, stmt := range statements {
+		if stmt.Timestamp != nil && stmt.Timestamp.After(latest) {
+			latest = *stmt.Timestamp
+		}
+	}
+
+	return &latest
+}
+
+// vexStatement returns the statement of the package's status given by the
+// latest public event of its advisory. As OpenVEX requires, not_affected
+// statements always have a justification or impact statement, and affected
+// statements an action statement, even if the event's were internal.
+func vexStatement(namespace, pkg, vulnID string, latest advisoryconfigs.Entry, override *advisoryconfigs.SeverityOverride) vex.Statement {
+	ts := latest.Timestamp
+	stmt := vex.Statement{
+		Vulnerability: vulnID,
+		Timestamp:     &ts,
+		Products:      []string{PackageURL{Namespace: namespace, Name: pkg}.String()},
+		Status:        latest.Status,
+	}
+
+	switch latest.Status {
+	case vex.StatusFixed:
+		stmt.Products = []string{PackageURL{Namespace: namespace, Name: pkg, Version: latest.FixedVersion}.String()}
+		stmt.StatusNotes = fmt.Sprintf("fixed in version %s", latest.FixedVersion)
+
+	case vex.StatusNotAffected:
+		stmt.Justification = latest.Justification
+		stmt.ImpactStatement = latest.ImpactStatement
+		if stmt.Justification == "" && stmt.ImpactStatement == "" {
+			stmt.ImpactStatement = "Not affected."
+		}
+
+	case vex.StatusAffected:
+		stmt.ActionStatement = latest.ActionStatement
+		if stmt.ActionStatement == "" {
+			stmt.ActionStatement = "No fix is available yet."
+		}
+	}
+
+	if override != nil {
+		notes := describeSeverityOverride(*override)
+		if stmt.StatusNotes != "" {
+			notes = stmt.StatusNotes + ". " + notes
+		}
+		stmt.StatusNotes = notes
+	}
+
+	return stmt
+}
+
+// MergeVEXOptions configures the MergeVEX operation.
+type MergeVEXOptions struct {
+	// Author and AuthorRole identify who issues the merged document. If empty,
+	// OpenVEX's defaults are used.
+	Author, AuthorRole string
+
+	// Now is the time the merged document is issued. If zero, the current time is
+	// used.
+	Now time.Time
+}
+
+// MergeVEX merges the statements of the given OpenVEX documents into one
+// document, e.g. for an image composed of many packages. For each product and
+// vulnerability, only the latest statement is kept, where statements without a
+// timestamp take their document's. Statements about several products are split
+// into one statement per product, so that a later statement about one of them
+// doesn't hide the earlier one about the others. Where two statements have the
+// same timestamp, the one from the document given later wins.
+//
+// Statements are sorted by vulnerability and then by product, and the merged
+// document's ID is derived from its content.
+func MergeVEX(docs []*vex.VEX, opts MergeVEXOptions) (*vex.VEX, error) {
+	type key struct{ vuln, product string }
+	latest := make(map[key]vex.Statement)
+
+	for _, doc := range docs {
+		for _, stmt := range doc.Statements {
+			if stmt.Timestamp == nil || stmt.Timestamp.IsZero() {
+				stmt.Timestamp = doc.Timestamp
+			}
+
+			products := stmt.Products
+			if len(products) == 0 {
+				products = []string{""}
+			}
+
+			for _, product := range products {
+				s := stmt
+				if product != "" {
+					s.Products = []string{product}
+				}
+
+				k := key{vuln: stmt.Vulnerability, product: product}
+				if existing, ok := latest[k]; ok && statementTime(existing).After(statementTime(s)) {
+					continue
+				}
+				latest[k] = s
+			}
+		}
+	}
+
+	keys := lo.Keys(latest)
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].vuln != keys[j].vuln {
+			return keys[i].vuln < keys[j].vuln
+		}
+		return keys[i].product < keys[j].product
+	})
+
+	v := vex.New()
+	v.Tooling = "wolfictl"
+	if opts.Author != "" {
+		v.Author = opts.Author
+	}
+	if opts.AuthorRole != "" {
+		v.AuthorRole = opts.AuthorRole
+	}
+	if !opts.Now.IsZero() {
+		now := opts.Now
+		v.Timestamp = &now
+	}
+
+	for _, k := range keys {
+		v.Statements = append(v.Statements, latest[k])
+	}
+
+	if err := setCanonicalID(&v); err != nil {
+		return nil, err
+	}
+
+	return &v, nil
+}
+
+// setCanonicalID sets the document's ID, derived from its content. Computing
+// the ID sorts the statements it's given, so it's computed from a copy to keep
+// the document's statements in their order.
+func setCanonicalID(v *vex.VEX) error {
+	cp := *v
+	cp.Statements = append([]vex.Statement(nil), v.Statements...)
+
+	id, err := cp.GenerateCanonicalID()
+	if err != nil {
+		return fmt.Errorf("unable to generate VEX document ID: %w", err)
+	}
+
+	v.ID = id
+	return nil
+}
+
+func statementTime(stmt vex.Statement) time.Time {
+	if stmt.Timestamp == nil {
+		return time.Time{}
+	}
+	return *stmt.Timestamp
+}
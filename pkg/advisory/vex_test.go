@@ -0,0 +1,150 @@
+package advisory
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/openvex/go-vex/pkg/vex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
+)
+
+func TestGenerateVEX(t *testing.T) {
+	const foo = `package:
+  name: foo
+
+advisories:
+  CVE-2023-1:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: under_investigation
+    - timestamp: 2023-05-02T00:00:00Z
+      status: fixed
+      fixed-version: 1.2.3-r1
+  CVE-2023-2:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: not_affected
+      justification: vulnerable_code_not_present
+      impact: the vulnerable code was added in 2.0
+  CVE-2023-3:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: affected
+      action: secret plan
+      internal-fields:
+        - action
+`
+	const bar = `package:
+  name: bar
+
+advisories:
+  CVE-2023-4:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: under_investigation
+`
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "foo.advisories.yaml"), []byte(foo), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bar.advisories.yaml"), []byte(bar), 0o644))
+
+	cfgs, err := advisoryconfigs.NewIndex(rwos.DirFS(dir))
+	require.NoError(t, err)
+
+	now := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	opts := GenerateVEXOptions{AdvisoryCfgs: cfgs, Packages: []string{"foo"}, ProductNamespace: "wolfi", Author: "Wolfi", Now: now}
+
+	v, err := GenerateVEX(opts)
+	require.NoError(t, err)
+	assert.Equal(t, "Wolfi", v.Author)
+	assert.Equal(t, &now, v.Timestamp)
+	assert.NotEmpty(t, v.ID)
+
+	ts1, ts2 := time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC), time.Date(2023, 5, 2, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, []vex.Statement{
+		{
+			Vulnerability: "CVE-2023-1",
+			Timestamp:     &ts2,
+			Products:      []string{"pkg:apk/wolfi/foo@1.2.3-r1"},
+			Status:        vex.StatusFixed,
+			StatusNotes:   "fixed in version 1.2.3-r1",
+		},
+		{
+			Vulnerability:   "CVE-2023-2",
+			Timestamp:       &ts1,
+			Products:        []string{"pkg:apk/wolfi/foo"},
+			Status:          vex.StatusNotAffected,
+			Justification:   vex.VulnerableCodeNotPresent,
+			ImpactStatement: "the vulnerable code was added in 2.0",
+		},
+		{
+			Vulnerability:   "CVE-2023-3",
+			Timestamp:       &ts1,
+			Products:        []string{"pkg:apk/wolfi/foo"},
+			Status:          vex.StatusAffected,
+			ActionStatement: "No fix is available yet.",
+		},
+	}, v.Statements)
+	for _, stmt := range v.Statements {
+		assert.NoError(t, stmt.Validate())
+	}
+
+	again, err := GenerateVEX(opts)
+	require.NoError(t, err)
+	assert.Equal(t, v.ID, again.ID)
+
+	// Without Now, the document is dated by its latest statement, so its ID is
+	// still stable.
+	opts.Now = time.Time{}
+	v, err = GenerateVEX(opts)
+	require.NoError(t, err)
+	assert.Equal(t, &ts2, v.Timestamp)
+	again, err = GenerateVEX(opts)
+	require.NoError(t, err)
+	assert.Equal(t, v.ID, again.ID)
+
+	all, err := GenerateVEX(GenerateVEXOptions{AdvisoryCfgs: cfgs, ProductNamespace: "wolfi"})
+	require.NoError(t, err)
+	assert.Len(t, all.Statements, 4)
+
+	_, err = GenerateVEX(GenerateVEXOptions{AdvisoryCfgs: cfgs, Packages: []string{"baz"}, ProductNamespace: "wolfi"})
+	assert.ErrorContains(t, err, `no advisories found for package "baz"`)
+}
+
+func TestMergeVEX(t *testing.T) {
+	ts1, ts2, ts3 := time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC), time.Date(2023, 5, 2, 0, 0, 0, 0, time.UTC), time.Date(2023, 5, 3, 0, 0, 0, 0, time.UTC)
+
+	a := vex.New()
+	a.Timestamp = &ts1
+	a.Statements = []vex.Statement{
+		{Vulnerability: "CVE-2023-1", Products: []string{"pkg:apk/wolfi/foo", "pkg:apk/wolfi/bar"}, Status: vex.StatusUnderInvestigation},
+		{Vulnerability: "CVE-2023-2", Timestamp: &ts3, Products: []string{"pkg:apk/wolfi/foo"}, Status: vex.StatusAffected, ActionStatement: "wait"},
+	}
+
+	b := vex.New()
+	b.Timestamp = &ts2
+	b.Statements = []vex.Statement{
+		{Vulnerability: "CVE-2023-1", Products: []string{"pkg:apk/wolfi/foo@1.2.3-r1"}, Status: vex.StatusFixed},
+		{Vulnerability: "CVE-2023-1", Products: []string{"pkg:apk/wolfi/bar"}, Status: vex.StatusNotAffected, ImpactStatement: "not built"},
+		{Vulnerability: "CVE-2023-2", Products: []string{"pkg:apk/wolfi/foo"}, Status: vex.StatusUnderInvestigation},
+	}
+
+	now := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	merged, err := MergeVEX([]*vex.VEX{&a, &b}, MergeVEXOptions{Author: "Wolfi", Now: now})
+	require.NoError(t, err)
+	assert.Equal(t, "Wolfi", merged.Author)
+	assert.Equal(t, &now, merged.Timestamp)
+	assert.NotEmpty(t, merged.ID)
+
+	assert.Equal(t, []vex.Statement{
+		{Vulnerability: "CVE-2023-1", Timestamp: &ts2, Products: []string{"pkg:apk/wolfi/bar"}, Status: vex.StatusNotAffected, ImpactStatement: "not built"},
+		{Vulnerability: "CVE-2023-1", Timestamp: &ts1, Products: []string{"pkg:apk/wolfi/foo"}, Status: vex.StatusUnderInvestigation},
+		{Vulnerability: "CVE-2023-1", Timestamp: &ts2, Products: []string{"pkg:apk/wolfi/foo@1.2.3-r1"}, Status: vex.StatusFixed},
+		{Vulnerability: "CVE-2023-2", Timestamp: &ts3, Products: []string{"pkg:apk/wolfi/foo"}, Status: vex.StatusAffected, ActionStatement: "wait"},
+	}, merged.Statements)
+
+	again, err := MergeVEX([]*vex.VEX{&a, &b}, MergeVEXOptions{Author: "Wolfi", Now: now})
+	require.NoError(t, err)
+	assert.Equal(t, merged.ID, again.ID)
+}
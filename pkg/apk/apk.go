@@ -1,11 +1,13 @@
 package apk
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
 
+	"github.com/wolfi-dev/wolfictl/pkg/trust"
 	"github.com/wolfi-dev/wolfictl/pkg/versions"
 
 	"github.com/pkg/errors"
@@ -39,7 +41,15 @@ func (c Context) GetApkPackages() (map[string]*repository.Package, error) {
 		return nil, fmt.Errorf("non ok http response for URI %s code: %v", c.indexURL, resp.StatusCode)
 	}
 
-	return ParseApkIndex(resp.Body)
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed reading URI %s", c.indexURL)
+	}
+	if err := trust.EnforceIndex(c.indexURL, b); err != nil {
+		return nil, err
+	}
+
+	return ParseApkIndex(io.NopCloser(bytes.NewReader(b)))
 }
 
 func ParseUnpackedApkIndex(indexData io.ReadCloser) (map[string]*repository.Package, error) {
@@ -0,0 +1,307 @@
+package apk
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	apkversion "github.com/knqyf263/go-apk-version"
+	"gitlab.alpinelinux.org/alpine/go/repository"
+	"golang.org/x/exp/slices"
+)
+
+// RepositoryPackage is a package listed in a repository's APKINDEX.
+type RepositoryPackage struct {
+	*repository.Package
+
+	// Repository is the URL of the repository the package came from.
+	Repository string
+}
+
+// Resolver resolves package dependencies (including "so:" and "cmd:" style
+// dependencies satisfied via provides) using APKINDEX data.
+type Resolver struct {
+	// latest maps package names to the latest version of the package.
+	latest map[string]RepositoryPackage
+
+	// versions maps package names to every version of the package, for
+	// resolving dependencies pinned to a version.
+	versions map[string][]RepositoryPackage
+
+	// providers maps provided names to the package names that provide them.
+	providers map[string][]string
+}
+
+func NewResolver() *Resolver {
+	return &Resolver{
+		latest:    make(map[string]RepositoryPackage),
+		versions:  make(map[string][]RepositoryPackage),
+		providers: make(map[string][]string),
+	}
+}
+
+// AddIndex adds the packages from the given repository's APKINDEX to the set of
+// packages available for resolution.
+func (r *Resolver) AddIndex(repo string, idx *repository.ApkIndex) {
+	for _, pkg := range idx.Packages {
+		p := RepositoryPackage{Package: pkg, Repository: repo}
+		if existing, ok := r.latest[pkg.Name]; !ok || isNewer(p, existing) {
+			r.latest[pkg.Name] = p
+		}
+		r.versions[pkg.Name] = append(r.versions[pkg.Name], p)
+
+		for _, provided := range pkg.Provides {
+			name := StripConstraint(provided)
+			if !slices.Contains(r.providers[name], pkg.Name) {
+				r.providers[name] = append(r.providers[name], pkg.Name)
+			}
+		}
+	}
+}
+
+// Resolve returns the latest package that satisfies the given dependency (e.g.
+// "busybox", "busybox=1.36.1-r0", "busybox>=1.36", or "so:libc.so.6"),
+// preferring a package with a matching name over one that provides it. If the
+// dependency has a version constraint, only packages whose version (or
+// provided version) satisfies it are considered.
+func (r *Resolver) Resolve(dep string) (RepositoryPackage, bool) {
+	name := StripConstraint(dep)
+
+	if name != dep {
+		return r.resolveConstrained(dep)
+	}
+
+	if p, ok := r.latest[name]; ok {
+		return p, true
+	}
+
+	satisfiers := r.Satisfiers(dep)
+	if len(satisfiers) == 0 {
+		return RepositoryPackage{}, false
+	}
+
+	best := satisfiers[0]
+	for _, p := range satisfiers[1:] {
+		if isNewer(p, best) {
+			best = p
+		}
+	}
+
+	return best, true
+}
+
+// Satisfiers returns the latest version of every package that could satisfy
+// the given dependency, either by name or via provides, sorted by name.
+func (r *Resolver) Satisfiers(dep string) []RepositoryPackage {
+	name := StripConstraint(dep)
+
+	names := append([]string{}, r.providers[name]...)
+	if _, ok := r.latest[name]; ok && !slices.Contains(names, name) {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	satisfiers := make([]RepositoryPackage, 0, len(names))
+	for _, n := range names {
+		satisfiers = append(satisfiers, r.latest[n])
+	}
+
+	return satisfiers
+}
+
+// resolveConstrained returns the latest package that satisfies the given
+// dependency's version constraint, looking first at packages with a matching
+// name and then at packages that provide it with a version.
+func (r *Resolver) resolveConstrained(dep string) (RepositoryPackage, bool) {
+	name, op, version := parseConstraint(dep)
+
+	var best RepositoryPackage
+	var found bool
+	consider := func(p RepositoryPackage, v string) {
+		if !satisfiesConstraint(v, op, version) {
+			return
+		}
+		if !found || isNewer(p, best) {
+			best, found = p, true
+		}
+	}
+
+	for _, p := range r.versions[name] {
+		consider(p, p.Version)
+	}
+	if found {
+		return best, true
+	}
+
+	for _, providerName := range r.providers[name] {
+		for _, p := range r.versions[providerName] {
+			for _, provided := range p.Provides {
+				if providedName, _, providedVersion := parseConstraint(provided); providedName == name && providedVersion != "" {
+					consider(p, providedVersion)
+				}
+			}
+		}
+	}
+
+	return best, found
+}
+
+// DependencyNode is a node in a resolved dependency tree.
+type DependencyNode struct {
+	// Dependency is the dependency string that this node satisfies, as declared by
+	// its parent (e.g. "so:libc.so.6"). It's empty for the root node.
+	Dependency string `json:"dependency,omitempty"`
+
+	Package string `json:"package,omitempty"`
+	Version string `json:"version,omitempty"`
+
+	// Satisfiers lists every package that could satisfy Dependency, when there's
+	// more than one.
+	Satisfiers []string `json:"satisfiers,omitempty"`
+
+	// Unresolved is true if no package satisfies Dependency.
+	Unresolved bool `json:"unresolved,omitempty"`
+
+	// Cycle is true if this package is already an ancestor of this node, in which
+	// case its dependencies aren't listed again.
+	Cycle bool `json:"cycle,omitempty"`
+
+	// Repeated is true if this package's dependencies are already listed
+	// elsewhere in the tree, in which case they aren't listed again.
+	Repeated bool `json:"repeated,omitempty"`
+
+	Dependencies []*DependencyNode `json:"dependencies,omitempty"`
+}
+
+// Tree resolves the full dependency tree of the given package.
+func (r *Resolver) Tree(pkg string) (*DependencyNode, error) {
+	root, ok := r.Resolve(pkg)
+	if !ok {
+		return nil, fmt.Errorf("unable to resolve package %q", pkg)
+	}
+
+	return r.TreeOf(root), nil
+}
+
+// TreeOf resolves the full dependency tree of the given package, which needn't
+// be in any of the resolver's repositories (e.g. a locally built APK file).
+func (r *Resolver) TreeOf(pkg RepositoryPackage) *DependencyNode {
+	node := &DependencyNode{
+		Package: pkg.Name,
+		Version: pkg.Version,
+	}
+
+	r.expand(node, pkg, map[string]bool{pkg.Name: true}, map[string]bool{})
+
+	return node
+}
+
+func (r *Resolver) expand(node *DependencyNode, pkg RepositoryPackage, ancestors, expanded map[string]bool) {
+	expanded[pkg.Name] = true
+
+	for _, dep := range pkg.Dependencies {
+		if strings.HasPrefix(dep, "!") {
+			// conflicts aren't dependencies
+			continue
+		}
+
+		child := &DependencyNode{Dependency: dep}
+		node.Dependencies = append(node.Dependencies, child)
+
+		resolved, ok := r.Resolve(dep)
+		if !ok {
+			child.Unresolved = true
+			continue
+		}
+
+		child.Package = resolved.Name
+		child.Version = resolved.Version
+
+		if satisfiers := r.Satisfiers(dep); len(satisfiers) > 1 {
+			for _, s := range satisfiers {
+				child.Satisfiers = append(child.Satisfiers, s.Name)
+			}
+		}
+
+		switch {
+		case ancestors[resolved.Name]:
+			child.Cycle = true
+		case expanded[resolved.Name]:
+			child.Repeated = true
+		default:
+			ancestors[resolved.Name] = true
+			r.expand(child, resolved, ancestors, expanded)
+			delete(ancestors, resolved.Name)
+		}
+	}
+}
+
+var constraintPattern = regexp.MustCompile(`[<>=~].*$`)
+
+var constraintPartsPattern = regexp.MustCompile(`^([^<>=~]+)([<>=~]+)(.*)$`)
+
+// parseConstraint splits a dependency or provides string into its name,
+// operator, and version, e.g. "busybox>=1.36" becomes "busybox", ">=", and
+// "1.36". The operator and version are empty if there's no constraint.
+func parseConstraint(s string) (name, op, version string) {
+	m := constraintPartsPattern.FindStringSubmatch(s)
+	if m == nil {
+		return s, "", ""
+	}
+
+	return m[1], m[2], m[3]
+}
+
+// satisfiesConstraint reports whether the version v satisfies the constraint
+// given by op and version. A "~" constraint matches versions that begin with
+// the given version, as in apk.
+func satisfiesConstraint(v, op, version string) bool {
+	if op == "~" || op == "=~" || op == "~=" {
+		return v == version || strings.HasPrefix(v, version+".") || strings.HasPrefix(v, version+"-")
+	}
+
+	have, err := apkversion.NewVersion(v)
+	if err != nil {
+		return false
+	}
+	want, err := apkversion.NewVersion(version)
+	if err != nil {
+		return false
+	}
+
+	cmp := have.Compare(want)
+	switch op {
+	case "=":
+		return cmp == 0
+	case ">=":
+		return cmp >= 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case "<":
+		return cmp < 0
+	default:
+		return false
+	}
+}
+
+// StripConstraint removes any version constraint from a dependency or provides
+// string, e.g. "busybox>=1.36" becomes "busybox".
+func StripConstraint(s string) string {
+	return constraintPattern.ReplaceAllString(s, "")
+}
+
+func isNewer(p, than RepositoryPackage) bool {
+	v, err := apkversion.NewVersion(p.Version)
+	if err != nil {
+		return false
+	}
+	thanVersion, err := apkversion.NewVersion(than.Version)
+	if err != nil {
+		return true
+	}
+
+	return v.GreaterThan(thanVersion)
+}
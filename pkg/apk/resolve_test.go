@@ -0,0 +1,98 @@
+package apk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gitlab.alpinelinux.org/alpine/go/repository"
+)
+
+func TestResolverResolve(t *testing.T) {
+	r := NewResolver()
+	r.AddIndex("https://packages.wolfi.dev/os", &repository.ApkIndex{
+		Packages: []*repository.Package{
+			{Name: "glibc", Version: "2.37-r1", Provides: []string{"so:libc.so.6=6"}},
+			{Name: "glibc", Version: "2.37-r3", Provides: []string{"so:libc.so.6=6"}},
+			{Name: "busybox", Version: "1.36.0-r2", Dependencies: []string{"so:libc.so.6"}},
+			{Name: "busybox", Version: "1.36.1-r0", Dependencies: []string{"so:libc.so.6"}},
+		},
+	})
+
+	cases := []struct {
+		dep             string
+		expectedName    string
+		expectedVersion string
+	}{
+		{dep: "busybox", expectedName: "busybox", expectedVersion: "1.36.1-r0"},
+		{dep: "busybox>=1.36", expectedName: "busybox", expectedVersion: "1.36.1-r0"},
+		{dep: "busybox=1.36.0-r2", expectedName: "busybox", expectedVersion: "1.36.0-r2"},
+		{dep: "busybox<1.36.1", expectedName: "busybox", expectedVersion: "1.36.0-r2"},
+		{dep: "busybox~1.36.0", expectedName: "busybox", expectedVersion: "1.36.0-r2"},
+		{dep: "glibc", expectedName: "glibc", expectedVersion: "2.37-r3"},
+		{dep: "glibc=2.37-r1", expectedName: "glibc", expectedVersion: "2.37-r1"},
+		{dep: "so:libc.so.6=6", expectedName: "glibc", expectedVersion: "2.37-r3"},
+		{dep: "so:libc.so.6", expectedName: "glibc", expectedVersion: "2.37-r3"},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.dep, func(t *testing.T) {
+			p, ok := r.Resolve(tt.dep)
+			assert.True(t, ok)
+			assert.Equal(t, tt.expectedName, p.Name)
+			assert.Equal(t, tt.expectedVersion, p.Version)
+			assert.Equal(t, "https://packages.wolfi.dev/os", p.Repository)
+		})
+	}
+
+	_, ok := r.Resolve("does-not-exist")
+	assert.False(t, ok)
+
+	// a pinned version that isn't in the index doesn't fall back to the latest
+	_, ok = r.Resolve("busybox=1.35.0-r0")
+	assert.False(t, ok)
+}
+
+func TestResolverTree(t *testing.T) {
+	r := NewResolver()
+	r.AddIndex("https://packages.wolfi.dev/os", &repository.ApkIndex{
+		Packages: []*repository.Package{
+			{Name: "a", Version: "1-r0", Dependencies: []string{"b", "cmd:c", "missing", "!conflict"}},
+			{Name: "b", Version: "1-r0", Dependencies: []string{"a", "so:libc.so.6"}},
+			{Name: "c", Version: "1-r0", Provides: []string{"cmd:c=1-r0"}, Dependencies: []string{"so:libc.so.6"}},
+			{Name: "c-alt", Version: "2-r0", Provides: []string{"cmd:c=2-r0"}},
+			{Name: "glibc", Version: "2.37-r3", Provides: []string{"so:libc.so.6=6"}},
+		},
+	})
+
+	tree, err := r.Tree("a")
+	require.NoError(t, err)
+
+	expected := &DependencyNode{
+		Package: "a",
+		Version: "1-r0",
+		Dependencies: []*DependencyNode{
+			{
+				Dependency: "b",
+				Package:    "b",
+				Version:    "1-r0",
+				Dependencies: []*DependencyNode{
+					{Dependency: "a", Package: "a", Version: "1-r0", Cycle: true},
+					{Dependency: "so:libc.so.6", Package: "glibc", Version: "2.37-r3"},
+				},
+			},
+			{
+				Dependency: "cmd:c",
+				Package:    "c-alt",
+				Version:    "2-r0",
+				Satisfiers: []string{"c", "c-alt"},
+			},
+			{Dependency: "missing", Unresolved: true},
+		},
+	}
+
+	assert.Equal(t, expected, tree)
+
+	_, err = r.Tree("missing")
+	assert.Error(t, err)
+}
@@ -0,0 +1,97 @@
+package bot
+
+import (
+	"context"
+	"io"
+
+	"github.com/google/go-github/v50/github"
+	"github.com/wolfi-dev/wolfictl/pkg/advisory"
+	"github.com/wolfi-dev/wolfictl/pkg/configs"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	"github.com/wolfi-dev/wolfictl/pkg/gh"
+	"github.com/wolfi-dev/wolfictl/pkg/scan"
+	"github.com/wolfi-dev/wolfictl/pkg/update"
+)
+
+// UpdateDetector finds new upstream versions of packages.
+type UpdateDetector interface {
+	// LatestVersions returns the latest upstream version of each of the named
+	// packages whose melange configs are in dir, keyed by package name. If no
+	// names are given, all packages in dir are checked.
+	LatestVersions(ctx context.Context, dir string, packageNames []string) (map[string]update.NewVersionResults, error)
+}
+
+// PullRequestOpener opens pull requests.
+type PullRequestOpener interface {
+	OpenPullRequest(ctx context.Context, pr *gh.NewPullRequest) (*github.PullRequest, error)
+}
+
+// AdvisoryWriter records advisory data.
+type AdvisoryWriter interface {
+	CreateAdvisory(ctx context.Context, req advisory.Request) error
+}
+
+// Scanner scans APK files for vulnerabilities.
+type Scanner interface {
+	ScanAPK(ctx context.Context, r io.Reader) (*scan.Result, error)
+}
+
+// NewUpdateDetector returns an UpdateDetector backed by the same release
+// monitoring and GitHub queries used by 'wolfictl update'.
+func NewUpdateDetector(opts update.Options) UpdateDetector {
+	return &updateDetector{opts: opts}
+}
+
+type updateDetector struct {
+	opts update.Options
+}
+
+func (d *updateDetector) LatestVersions(_ context.Context, dir string, packageNames []string) (map[string]update.NewVersionResults, error) {
+	return d.opts.GetLatestVersions(dir, packageNames)
+}
+
+// NewPullRequestOpener returns a PullRequestOpener that uses the GitHub API,
+// retrying when rate limited.
+func NewPullRequestOpener(opts gh.GitOptions) PullRequestOpener {
+	return &pullRequestOpener{opts: opts}
+}
+
+type pullRequestOpener struct {
+	opts gh.GitOptions
+}
+
+func (o *pullRequestOpener) OpenPullRequest(_ context.Context, pr *gh.NewPullRequest) (*github.PullRequest, error) {
+	return o.opts.OpenPullRequest(pr)
+}
+
+// NewAdvisoryWriter returns an AdvisoryWriter that creates advisories in the
+// given index of advisory documents, as 'wolfictl advisory create' does.
+func NewAdvisoryWriter(advisoryCfgs *configs.Index[advisoryconfigs.Document]) AdvisoryWriter {
+	return &advisoryWriter{opts: advisory.CreateOptions{AdvisoryCfgs: advisoryCfgs}}
+}
+
+type advisoryWriter struct {
+	opts advisory.CreateOptions
+}
+
+func (w *advisoryWriter) CreateAdvisory(_ context.Context, req advisory.Request) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	return advisory.Create(req, w.opts)
+}
+
+// NewScanner returns a Scanner that scans APK files as 'wolfictl scan' does,
+// using the given options.
+func NewScanner(opts scan.Options) Scanner {
+	return &scanner{opts: opts}
+}
+
+type scanner struct {
+	opts scan.Options
+}
+
+func (s *scanner) ScanAPK(_ context.Context, r io.Reader) (*scan.Result, error) {
+	return scan.APK(r, s.opts)
+}
@@ -0,0 +1,88 @@
+// Package bot provides the building blocks used by wolfictl's own automation
+// (update detection, pull requests, advisories, and scanning) for composing
+// custom bots in Go, rather than shelling out to the wolfictl CLI and parsing
+// its output.
+//
+// A Bot holds an implementation of each building block. The New* functions in
+// this package adapt wolfictl's existing implementations to these interfaces,
+// and any of them can be replaced, e.g. with a fake for testing. Automation is
+// written as Tasks, which a Bot runs once (Run) or periodically (RunEvery).
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// Bot assembles the building blocks available to Tasks. Any of them may be nil
+// if the bot's Tasks don't need them.
+type Bot struct {
+	Updates      UpdateDetector
+	PullRequests PullRequestOpener
+	Advisories   AdvisoryWriter
+	Scanner      Scanner
+
+	// Logger is used to report the outcome of each Task. If nil, log.Default() is
+	// used.
+	Logger *log.Logger
+}
+
+// Task is a unit of automation performed by a Bot.
+type Task struct {
+	// Name identifies the task in logs and errors.
+	Name string
+
+	// Do performs the task using the building blocks of the given Bot.
+	Do func(ctx context.Context, b *Bot) error
+}
+
+// Run runs each of the given tasks once, in order. A failing task doesn't stop
+// later tasks from running; the errors of all failed tasks are returned
+// together.
+func (b *Bot) Run(ctx context.Context, tasks ...Task) error {
+	var errs []error
+	for _, t := range tasks {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := t.Do(ctx, b); err != nil {
+			b.logger().Printf("task %s failed: %v", t.Name, err)
+			errs = append(errs, fmt.Errorf("task %s: %w", t.Name, err))
+			continue
+		}
+
+		b.logger().Printf("task %s succeeded", t.Name)
+	}
+
+	return errors.Join(errs...)
+}
+
+// RunEvery runs the given tasks immediately and then once per interval, until
+// the context is canceled. Task failures are logged but don't stop the loop. It
+// returns the context's error.
+func (b *Bot) RunEvery(ctx context.Context, interval time.Duration, tasks ...Task) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		// Failures are logged by Run.
+		_ = b.Run(ctx, tasks...)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (b *Bot) logger() *log.Logger {
+	if b.Logger == nil {
+		return log.Default()
+	}
+	return b.Logger
+}
@@ -0,0 +1,60 @@
+package bot
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wolfi-dev/wolfictl/pkg/advisory"
+)
+
+type fakeAdvisoryWriter struct {
+	created []advisory.Request
+}
+
+func (w *fakeAdvisoryWriter) CreateAdvisory(_ context.Context, req advisory.Request) error {
+	w.created = append(w.created, req)
+	return nil
+}
+
+func TestBot_Run(t *testing.T) {
+	advisories := &fakeAdvisoryWriter{}
+	logs := new(bytes.Buffer)
+	b := &Bot{
+		Advisories: advisories,
+		Logger:     log.New(logs, "", 0),
+	}
+
+	var ran []string
+	err := b.Run(context.Background(),
+		Task{Name: "fails", Do: func(context.Context, *Bot) error {
+			ran = append(ran, "fails")
+			return errors.New("boom")
+		}},
+		Task{Name: "advise", Do: func(ctx context.Context, b *Bot) error {
+			ran = append(ran, "advise")
+			return b.Advisories.CreateAdvisory(ctx, advisory.Request{Package: "foo", Vulnerability: "CVE-2023-1234"})
+		}},
+	)
+
+	assert.EqualError(t, err, "task fails: boom")
+	assert.Equal(t, []string{"fails", "advise"}, ran)
+	assert.Len(t, advisories.created, 1)
+	assert.Equal(t, "task fails failed: boom\ntask advise succeeded\n", logs.String())
+}
+
+func TestBot_Run_canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	b := &Bot{}
+	err := b.Run(ctx, Task{Name: "never", Do: func(context.Context, *Bot) error {
+		t.Fatal("task should not run")
+		return nil
+	}})
+
+	assert.ErrorIs(t, err, context.Canceled)
+}
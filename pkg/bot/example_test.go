@@ -0,0 +1,65 @@
+package bot_test
+
+import (
+	"context"
+	"log"
+	"os"
+	"time"
+
+	"github.com/openvex/go-vex/pkg/vex"
+	"github.com/wolfi-dev/wolfictl/pkg/advisory"
+	"github.com/wolfi-dev/wolfictl/pkg/bot"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
+	"github.com/wolfi-dev/wolfictl/pkg/scan"
+)
+
+// This example scans a package every hour and records an advisory for each
+// critical vulnerability found, for a human to investigate.
+func Example() {
+	advisoryCfgs, err := advisoryconfigs.NewIndex(rwos.DirFS("advisories"))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	b := &bot.Bot{
+		Scanner:    bot.NewScanner(scan.Options{}),
+		Advisories: bot.NewAdvisoryWriter(advisoryCfgs),
+	}
+
+	triage := bot.Task{
+		Name: "triage-critical",
+		Do: func(ctx context.Context, b *bot.Bot) error {
+			f, err := os.Open("packages/x86_64/crane-0.15.2-r0.apk")
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+
+			result, err := b.Scanner.ScanAPK(ctx, f)
+			if err != nil {
+				return err
+			}
+
+			for _, finding := range result.Findings {
+				if finding.Vulnerability.Severity != "Critical" {
+					continue
+				}
+
+				err := b.Advisories.CreateAdvisory(ctx, advisory.Request{
+					Package:       result.APK.Origin,
+					Vulnerability: finding.Vulnerability.ID,
+					Status:        vex.StatusUnderInvestigation,
+					Timestamp:     time.Now(),
+				})
+				if err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+	}
+
+	_ = b.RunEvery(context.Background(), time.Hour, triage)
+}
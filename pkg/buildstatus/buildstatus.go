@@ -0,0 +1,243 @@
+// Package buildstatus aggregates whether a revision of a package has been
+// built and published for each architecture, from the CI check runs for the
+// revision and the package repository's APKINDEX for each architecture.
+package buildstatus
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"gitlab.alpinelinux.org/alpine/go/repository"
+	"gopkg.in/yaml.v3"
+)
+
+// State is the state of a package's build for an architecture.
+type State string
+
+const (
+	StateSucceeded State = "succeeded"
+	StateFailed    State = "failed"
+	StateRunning   State = "running"
+
+	// StateMissing means no build was found for the architecture.
+	StateMissing State = "missing"
+)
+
+// CheckRun is a CI check run for a revision, such as a GitHub Actions job.
+type CheckRun struct {
+	Name string
+
+	// Status is "queued", "in_progress", or "completed".
+	Status string
+
+	// Conclusion is the outcome of a completed run, e.g. "success" or "failure".
+	Conclusion string
+
+	URL string
+
+	// Started is when the run was created. GitHub check runs have no separate
+	// creation time; their start time is set when they're created.
+	Started time.Time
+}
+
+// ArchStatus is the build and publish status of a package for one
+// architecture.
+type ArchStatus struct {
+	Arch string `json:"arch"`
+
+	Build State `json:"build"`
+
+	// BuildURL links to the build, if one was found.
+	BuildURL string `json:"buildURL,omitempty"`
+
+	// Published is whether the package version is in the architecture's APKINDEX.
+	Published bool `json:"published"`
+}
+
+// Status is the build and publish status of a revision of a package, across
+// architectures.
+type Status struct {
+	Ref     string       `json:"ref"`
+	Package string       `json:"package"`
+	Version string       `json:"version"`
+	Archs   []ArchStatus `json:"archs"`
+}
+
+// Live reports whether the package version has been built and published for
+// every architecture.
+func (s Status) Live() bool {
+	for _, a := range s.Archs {
+		if a.Build != StateSucceeded || !a.Published {
+			return false
+		}
+	}
+
+	return len(s.Archs) > 0
+}
+
+// Aggregate returns the status of the given version of a package, built at the
+// given ref, for each of the given architectures. A check run belongs to an
+// architecture if its name mentions the architecture as a word (e.g. "build
+// (aarch64)"). If several do, the most recently started one counts. Runs that
+// were skipped, or that ended neutral, didn't build anything and are ignored.
+// published maps each architecture to whether the version is in its APKINDEX.
+func Aggregate(ref, pkg, version string, archs []string, runs []CheckRun, published map[string]bool) Status {
+	s := Status{
+		Ref:     ref,
+		Package: pkg,
+		Version: version,
+	}
+
+	runs = append([]CheckRun(nil), runs...)
+	sort.SliceStable(runs, func(i, j int) bool {
+		return runs[i].Started.Before(runs[j].Started)
+	})
+
+	for _, arch := range archs {
+		as := ArchStatus{
+			Arch:      arch,
+			Build:     StateMissing,
+			Published: published[arch],
+		}
+
+		for _, run := range runs {
+			if !mentionsArch(run.Name, arch) || !built(run) {
+				continue
+			}
+
+			as.Build = runState(run)
+			as.BuildURL = run.URL
+		}
+
+		s.Archs = append(s.Archs, as)
+	}
+
+	return s
+}
+
+// archAliases are other names used for architectures, e.g. in CI job names.
+var archAliases = map[string][]string{
+	"x86_64":  {"amd64"},
+	"aarch64": {"arm64"},
+}
+
+// mentionsArch reports whether the given check run name has the architecture
+// (or one of its aliases) as a whole word, so that e.g. "build (x86_64)"
+// mentions x86_64 but "build-x86_64_v3" doesn't.
+func mentionsArch(name, arch string) bool {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	})
+
+	for _, a := range append([]string{arch}, archAliases[arch]...) {
+		for _, w := range words {
+			if strings.EqualFold(w, a) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// built reports whether the check run attempted a build, i.e. it wasn't skipped
+// and didn't finish with a neutral conclusion.
+func built(run CheckRun) bool {
+	return run.Conclusion != "skipped" && run.Conclusion != "neutral"
+}
+
+func runState(run CheckRun) State {
+	if run.Status != "completed" {
+		return StateRunning
+	}
+
+	switch run.Conclusion {
+	case "success":
+		return StateSucceeded
+	default:
+		return StateFailed
+	}
+}
+
+// IsPublished reports whether the given version (e.g. "1.2.3-r0") of the named
+// package is in the APKINDEX.
+func IsPublished(index *repository.ApkIndex, pkg, version string) bool {
+	for _, p := range index.Packages {
+		if p.Name == pkg && p.Version == version {
+			return true
+		}
+	}
+
+	return false
+}
+
+// FetchVersion returns the full version (e.g. "1.2.3-r0") of the named package
+// as declared in its melange config at the given ref of the GitHub repository
+// at repoURL (e.g. "https://github.com/wolfi-dev/os"), using the given HTTP
+// client.
+func FetchVersion(ctx context.Context, client *http.Client, repoURL, ref, pkg string) (string, error) {
+	const githubPrefix = "https://github.com/"
+	if !strings.HasPrefix(repoURL, githubPrefix) {
+		return "", fmt.Errorf("%s is not a GitHub repository", repoURL)
+	}
+
+	url := fmt.Sprintf("https://raw.githubusercontent.com/%s/%s/%s.yaml", strings.TrimSuffix(strings.TrimPrefix(repoURL, githubPrefix), "/"), ref, pkg)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("unable to fetch melange config for %s: %w", pkg, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unable to fetch melange config for %s: GET %s: %d", pkg, url, resp.StatusCode)
+	}
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("unable to fetch melange config for %s: %w", pkg, err)
+	}
+
+	return parseVersion(b)
+}
+
+// parseVersion returns the full version declared in the given melange config,
+// with any ${{vars.*}} references in the version substituted.
+func parseVersion(cfg []byte) (string, error) {
+	var c struct {
+		Package struct {
+			Version string `yaml:"version"`
+			Epoch   uint64 `yaml:"epoch"`
+		} `yaml:"package"`
+		Vars map[string]string `yaml:"vars"`
+	}
+	if err := yaml.Unmarshal(cfg, &c); err != nil {
+		return "", fmt.Errorf("unable to parse melange config: %w", err)
+	}
+
+	if c.Package.Version == "" {
+		return "", fmt.Errorf("melange config has no package version")
+	}
+
+	oldnew := make([]string, 0, 2*len(c.Vars))
+	for k, v := range c.Vars {
+		oldnew = append(oldnew, "${{vars."+k+"}}", v)
+	}
+	version := strings.NewReplacer(oldnew...).Replace(c.Package.Version)
+
+	if strings.Contains(version, "${{") {
+		return "", fmt.Errorf("unable to substitute variables in package version %q", c.Package.Version)
+	}
+
+	return fmt.Sprintf("%s-r%d", version, c.Package.Epoch), nil
+}
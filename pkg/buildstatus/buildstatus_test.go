@@ -0,0 +1,62 @@
+package buildstatus
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAggregate(t *testing.T) {
+	started := func(minute int) time.Time {
+		return time.Date(2023, time.June, 1, 12, minute, 0, 0, time.UTC)
+	}
+
+	// Not in the order the runs were started, which the API doesn't promise.
+	runs := []CheckRun{
+		{Name: "lint", Status: "completed", Conclusion: "success", Started: started(0)},
+		{Name: "build (x86_64)", Status: "completed", Conclusion: "success", URL: "https://ci.example.com/2", Started: started(2)},
+		{Name: "build (x86_64)", Status: "completed", Conclusion: "failure", URL: "https://ci.example.com/1", Started: started(1)},
+		{Name: "build (x86_64)", Status: "completed", Conclusion: "skipped", URL: "https://ci.example.com/4", Started: started(4)},
+		{Name: "build (arm64)", Status: "in_progress", URL: "https://ci.example.com/3", Started: started(3)},
+		{Name: "build (x86_64_v3)", Status: "completed", Conclusion: "failure", URL: "https://ci.example.com/5", Started: started(5)},
+		{Name: "build (riscv64)", Status: "completed", Conclusion: "neutral", URL: "https://ci.example.com/6", Started: started(6)},
+	}
+
+	s := Aggregate("abc123", "crane", "0.15.2-r0", []string{"x86_64", "aarch64", "riscv64"}, runs, map[string]bool{"x86_64": true, "riscv64": true})
+
+	assert.Equal(t, []ArchStatus{
+		{Arch: "x86_64", Build: StateSucceeded, BuildURL: "https://ci.example.com/2", Published: true},
+		{Arch: "aarch64", Build: StateRunning, BuildURL: "https://ci.example.com/3"},
+		{Arch: "riscv64", Build: StateMissing, Published: true},
+	}, s.Archs)
+	assert.False(t, s.Live())
+
+	s = Aggregate("abc123", "crane", "0.15.2-r0", []string{"x86_64"}, runs, map[string]bool{"x86_64": true})
+	assert.True(t, s.Live())
+}
+
+func Test_mentionsArch(t *testing.T) {
+	assert.True(t, mentionsArch("build (x86_64)", "x86_64"))
+	assert.True(t, mentionsArch("build-amd64", "x86_64"))
+	assert.True(t, mentionsArch("Build AArch64", "aarch64"))
+	assert.False(t, mentionsArch("build (x86_64_v3)", "x86_64"))
+	assert.False(t, mentionsArch("build (xarm64)", "aarch64"))
+}
+
+func Test_parseVersion(t *testing.T) {
+	v, err := parseVersion([]byte("package:\n  name: crane\n  version: 0.15.2\n  epoch: 1\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "0.15.2-r1", v)
+
+	_, err = parseVersion([]byte("package:\n  name: crane\n"))
+	assert.Error(t, err)
+
+	v, err = parseVersion([]byte("package:\n  name: openjdk-17\n  version: ${{vars.major}}.0.7\n  epoch: 2\nvars:\n  major: \"17\"\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "17.0.7-r2", v)
+
+	_, err = parseVersion([]byte("package:\n  name: openjdk-17\n  version: ${{vars.major}}.0.7\n"))
+	assert.Error(t, err)
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/wolfi-dev/wolfictl/pkg/lint"
 	"github.com/wolfi-dev/wolfictl/pkg/melange"
 	"github.com/wolfi-dev/wolfictl/pkg/update"
+	"github.com/wolfi-dev/wolfictl/pkg/yamledit"
 	"gopkg.in/yaml.v3"
 )
 
@@ -193,19 +194,17 @@ func (o CheckUpdateOptions) processUpdates(latestVersions map[string]update.NewV
 	for packageName, newVersion := range latestVersions {
 		srcConfigFile := filepath.Join(o.Dir, packageName+".yaml")
 
-		dryRunConfig, err := build.ParseConfiguration(srcConfigFile)
+		dryRunConfig, err := yamledit.ReadFile(srcConfigFile)
 		if err != nil {
 			return err
 		}
-		applyOverrides(&o, dryRunConfig)
-
-		data, err := yaml.Marshal(dryRunConfig)
+		err = applyOverrides(&o, dryRunConfig)
 		if err != nil {
 			return err
 		}
 
 		tmpConfigFile := filepath.Join(tempDir, packageName+".yaml")
-		err = os.WriteFile(tmpConfigFile, data, os.FileMode(0o644))
+		err = dryRunConfig.WriteFile(tmpConfigFile)
 		if err != nil {
 			return err
 		}
@@ -247,10 +246,11 @@ func (o CheckUpdateOptions) processUpdates(latestVersions map[string]update.NewV
 	return nil
 }
 
-func applyOverrides(options *CheckUpdateOptions, dryRunConfig *build.Configuration) {
+func applyOverrides(options *CheckUpdateOptions, dryRunConfig *yamledit.Document) error {
 	if options.OverrideVersion != "" {
-		dryRunConfig.Package.Version = options.OverrideVersion
+		return dryRunConfig.Set(options.OverrideVersion, "package", "version")
 	}
+	return nil
 }
 
 func verifyPipelines(o CheckUpdateOptions, updated *build.Configuration, mutations map[string]string, checkErrors *lint.EvalRuleErrors) {
@@ -2,12 +2,15 @@ package checks
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"strings"
 
 	"github.com/wolfi-dev/wolfictl/pkg/tar"
+	"github.com/wolfi-dev/wolfictl/pkg/trust"
 
 	"github.com/pkg/errors"
 )
@@ -70,7 +73,15 @@ func downloadCurrentAPK(client *http.Client, apkIndexURL, newPackageName, dirCur
 		return fmt.Errorf("download failed for %s, status code: %d", apkURL, resp.StatusCode)
 	}
 
-	err = tar.Untar(resp.Body, dirCurrentApk)
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read %s", apkURL)
+	}
+	if err := trust.EnforceAPK(apkURL, b); err != nil {
+		return err
+	}
+
+	err = tar.Untar(bytes.NewReader(b), dirCurrentApk)
 	if err != nil {
 		return errors.Wrap(err, "failed to untar new apk")
 	}
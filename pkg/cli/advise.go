@@ -0,0 +1,193 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/wolfi-dev/wolfictl/pkg/advise"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
+	"github.com/wolfi-dev/wolfictl/pkg/dag"
+	"github.com/wolfi-dev/wolfictl/pkg/distro"
+	"github.com/wolfi-dev/wolfictl/pkg/git"
+	"github.com/wolfi-dev/wolfictl/pkg/melange"
+	"gopkg.in/yaml.v3"
+)
+
+func Advise() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "advise",
+		SilenceErrors: true,
+		Short:         "Recommend maintenance work for the distro",
+	}
+
+	cmd.AddCommand(AdviseRebuilds())
+
+	return cmd
+}
+
+func AdviseRebuilds() *cobra.Command {
+	p := &adviseRebuildsParams{}
+	cmd := &cobra.Command{
+		Use:   "rebuilds",
+		Short: "Recommend packages that need an epoch-bump rebuild",
+		Long: `Recommend packages that need an epoch-bump rebuild.
+
+A package is recommended for a rebuild when one of its dependencies has, since
+the given date:
+
+  - had a vulnerability marked as fixed in the advisory data,
+  - had its version updated in the distro repository, or
+  - changed the so-name of a shared library (see --so-name-change).
+
+Recommendations are listed in build order.`,
+		Example:       "wolfictl advise rebuilds --since 2023-07-01",
+		SilenceErrors: true,
+		Args:          cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			since, err := time.Parse("2006-01-02", p.since)
+			if err != nil {
+				return fmt.Errorf("unable to parse --since date (expected YYYY-MM-DD): %w", err)
+			}
+
+			distroRepoDir := resolveDistroDir(p.distroRepoDir)
+			advisoriesRepoDir := resolveAdvisoriesDir(p.advisoriesRepoDir)
+			if distroRepoDir == "" || advisoriesRepoDir == "" {
+				if p.doNotDetectDistro {
+					return fmt.Errorf("distro repo dir and/or advisories repo dir was left unspecified")
+				}
+
+				d, err := distro.Detect()
+				if err != nil {
+					return fmt.Errorf("distro repo dir and/or advisories repo dir was left unspecified, and distro auto-detection failed: %w", err)
+				}
+
+				distroRepoDir = d.DistroRepoDir
+				advisoriesRepoDir = d.AdvisoriesRepoDir
+				_, _ = fmt.Fprint(os.Stderr, renderDetectedDistro(d))
+			}
+
+			pkgs, err := dag.NewPackages(os.DirFS(distroRepoDir), distroRepoDir, p.pipelineDir)
+			if err != nil {
+				return err
+			}
+			g, err := dag.NewGraph(pkgs)
+			if err != nil {
+				return err
+			}
+
+			advisoryCfgs, err := advisoryconfigs.NewIndex(rwos.DirFS(advisoriesRepoDir))
+			if err != nil {
+				return err
+			}
+
+			updated, err := updatedPackagesSince(distroRepoDir, since, pkgs)
+			if err != nil {
+				return err
+			}
+
+			recommendations, err := advise.Rebuilds(advise.RebuildsOptions{
+				Graph:           g,
+				AdvisoryCfgs:    advisoryCfgs,
+				Since:           since,
+				UpdatedPackages: updated,
+				SoNameChanges:   p.soNameChanges,
+			})
+			if err != nil {
+				return err
+			}
+
+			if len(recommendations) == 0 {
+				fmt.Println("✅ No rebuilds needed")
+				return nil
+			}
+
+			for _, r := range recommendations {
+				fmt.Printf("%s\n", r.Package)
+				for _, reason := range r.Reasons {
+					fmt.Printf("  - %s\n", reason)
+				}
+			}
+
+			return nil
+		},
+	}
+
+	p.addFlagsTo(cmd)
+	return cmd
+}
+
+type adviseRebuildsParams struct {
+	doNotDetectDistro bool
+
+	distroRepoDir, advisoriesRepoDir string
+	pipelineDir                      string
+
+	since         string
+	soNameChanges []string
+}
+
+func (p *adviseRebuildsParams) addFlagsTo(cmd *cobra.Command) {
+	addNoDistroDetectionFlag(&p.doNotDetectDistro, cmd)
+
+	addDistroDirFlag(&p.distroRepoDir, cmd)
+	addAdvisoriesDirFlag(&p.advisoriesRepoDir, cmd)
+	cmd.Flags().StringVar(&p.pipelineDir, "pipeline-dir", "", "directory used to extend defined built-in pipelines")
+
+	cmd.Flags().StringVar(&p.since, "since", time.Now().AddDate(0, 0, -7).Format("2006-01-02"), "only consider changes after this date (YYYY-MM-DD)")
+	cmd.Flags().StringSliceVar(&p.soNameChanges, "so-name-change", nil, "name of a package whose so-name changed (e.g. as reported by 'wolfictl check so-name'); can be repeated")
+}
+
+// updatedPackagesSince returns the origin packages whose version in the distro
+// repo has changed since the given time, mapped to a description of the change.
+func updatedPackagesSince(distroRepoDir string, since time.Time, pkgs *dag.Packages) (map[string]string, error) {
+	rev, err := git.RevisionBefore(distroRepoDir, since)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := git.ChangedFiles(distroRepoDir, rev)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := make(map[string]string)
+	for _, f := range files {
+		if strings.Contains(f, "/") || filepath.Ext(f) != ".yaml" {
+			continue
+		}
+
+		name := strings.TrimSuffix(f, ".yaml")
+		current, err := pkgs.PkgInfo(name)
+		if err != nil {
+			return nil, err
+		}
+		if current == nil {
+			// not a package config, or the package was removed
+			continue
+		}
+
+		data, err := git.FileAtRevision(distroRepoDir, rev, f)
+		if err != nil {
+			// i.e. a new package, which nothing could have depended on before
+			continue
+		}
+
+		previous := melange.ConfigCheck{}
+		if err := yaml.Unmarshal(data, &previous); err != nil {
+			continue
+		}
+
+		if previous.Package.Version == current.Version {
+			continue
+		}
+
+		updated[name] = fmt.Sprintf("%s -> %s", previous.Package.Version, current.Version)
+	}
+
+	return updated, nil
+}
@@ -1,26 +1,38 @@
 package cli
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"os"
+	"path/filepath"
 	"sort"
+	"strings"
 	"time"
 
 	"chainguard.dev/melange/pkg/build"
+	"github.com/adrg/xdg"
 	"github.com/openvex/go-vex/pkg/vex"
 	"github.com/samber/lo"
 	"github.com/spf13/cobra"
 	"github.com/wolfi-dev/wolfictl/pkg/advisory"
 	"github.com/wolfi-dev/wolfictl/pkg/cli/styles"
 	"github.com/wolfi-dev/wolfictl/pkg/configs"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	rwhttp "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/http"
+	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
 	"github.com/wolfi-dev/wolfictl/pkg/distro"
+	"github.com/wolfi-dev/wolfictl/pkg/git"
+	"github.com/wolfi-dev/wolfictl/pkg/scan"
 	"github.com/wolfi-dev/wolfictl/pkg/versions"
 	"gitlab.alpinelinux.org/alpine/go/repository"
+	"golang.org/x/term"
 )
 
 const (
 	envVarNameForDistroDir     = "WOLFICTL_DISTRO_REPO_DIR"
 	envVarNameForAdvisoriesDir = "WOLFICTL_ADVISORIES_REPO_DIR"
+	envVarNameForAdvisoriesURL = "WOLFICTL_ADVISORIES_URL"
 )
 
 func Advisory() *cobra.Command {
@@ -37,8 +49,37 @@ func Advisory() *cobra.Command {
 	cmd.AddCommand(AdvisorySyncSecfixes())
 	cmd.AddCommand(AdvisoryDiscover())
 	cmd.AddCommand(AdvisoryDB())
+	cmd.AddCommand(AdvisorySecDB())
 	cmd.AddCommand(AdvisoryValidate())
+	cmd.AddCommand(AdvisoryQuality())
 	cmd.AddCommand(AdvisoryExport())
+	cmd.AddCommand(AdvisoryVerifyRedaction())
+	cmd.AddCommand(AdvisoryArchive())
+	cmd.AddCommand(AdvisoryVerifyExport())
+	cmd.AddCommand(AdvisoryDiff())
+	cmd.AddCommand(AdvisorySuggest())
+	cmd.AddCommand(AdvisorySyncAliases())
+	cmd.AddCommand(AdvisoryStats())
+	cmd.AddCommand(AdvisoryMigrate())
+	cmd.AddCommand(AdvisoryMerge())
+	cmd.AddCommand(AdvisoryShow())
+	cmd.AddCommand(AdvisoryCopy())
+	cmd.AddCommand(AdvisoryLint())
+	cmd.AddCommand(AdvisoryGC())
+	cmd.AddCommand(AdvisoryImport())
+	cmd.AddCommand(AdvisorySign())
+	cmd.AddCommand(AdvisoryVerify())
+	cmd.AddCommand(AdvisoryDashboard())
+	cmd.AddCommand(AdvisoryNotify())
+	cmd.AddCommand(AdvisorySearch())
+	cmd.AddCommand(AdvisoryCGA())
+	cmd.AddCommand(AdvisoryVerifyFixedVersions())
+	cmd.AddCommand(AdvisoryReconcile())
+	cmd.AddCommand(AdvisorySLA())
+	cmd.AddCommand(AdvisoryPublishIndex())
+	cmd.AddCommand(AdvisoryPollUpstream())
+	cmd.AddCommand(AdvisoryMetrics())
+	cmd.AddCommand(AdvisoryReference())
 
 	return cmd
 }
@@ -63,6 +104,20 @@ func resolveAdvisoriesDir(cliFlagValue string) string {
 	return ""
 }
 
+// resolveAdvisoriesDirs is like resolveAdvisoriesDir, for commands that accept
+// more than one advisories repo dir.
+func resolveAdvisoriesDirs(cliFlagValues []string) []string {
+	if len(cliFlagValues) > 0 {
+		return cliFlagValues
+	}
+
+	if v := os.Getenv(envVarNameForAdvisoriesDir); v != "" {
+		return []string{v}
+	}
+
+	return nil
+}
+
 func renderDetectedDistro(d distro.Distro) string {
 	return styles.Secondary().Render("Auto-detected distro: ") + d.Name + "\n\n"
 }
@@ -80,9 +135,168 @@ func resolveTimestamp(ts string) (time.Time, error) {
 	return t, nil
 }
 
+func addAsOfFlag(val *string, cmd *cobra.Command) {
+	cmd.Flags().StringVar(val, "as-of", "", "use the advisory data as it was at this date (YYYY-MM-DD, taken as the start of the day in UTC) or RFC3339 timestamp, according to the advisories repo's git history")
+}
+
+// resolveAsOf parses the value of the --as-of flag.
+func resolveAsOf(asOf string) (time.Time, error) {
+	return parseDateFlag("--as-of", asOf)
+}
+
+// parseDateFlag parses the value of the named flag as a date (taken as the
+// start of the day in UTC) or an RFC3339 timestamp.
+func parseDateFlag(flag, value string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unable to parse %s value %q: must be a date (YYYY-MM-DD) or RFC3339 timestamp", flag, value)
+	}
+
+	return t, nil
+}
+
+// advisoriesDirAsOf returns a directory containing the advisories repo's files
+// as they were at the given time, according to its git history. If asOf is
+// empty, the advisories repo dir itself is returned. The returned function
+// cleans up any temporary files, and must be called.
+func advisoriesDirAsOf(advisoriesRepoDir, asOf string) (string, func(), error) {
+	if asOf == "" {
+		return advisoriesRepoDir, func() {}, nil
+	}
+
+	t, err := resolveAsOf(asOf)
+	if err != nil {
+		return "", nil, err
+	}
+
+	dir, c, err := git.CheckoutAsOf(advisoriesRepoDir, t)
+	if err != nil {
+		return "", nil, fmt.Errorf("unable to get advisory data as of %s: %w", asOf, err)
+	}
+
+	_, _ = fmt.Fprint(os.Stderr, styles.Secondary().Render(fmt.Sprintf("Using %s as of commit %s (%s)", advisoriesRepoDir, c.Hash.String()[:12], c.Committer.When.UTC().Format(time.RFC3339)))+"\n\n")
+
+	return dir, func() { _ = os.RemoveAll(dir) }, nil
+}
+
+// advisoriesIndexAsOf returns the Index of the overlaid view of the advisory data
+// published at advisoriesURL, if it's not empty, and in the given advisories
+// repo dirs (see advisory.OverlayIndices), as it was at the given time (see
+// advisoriesDirAsOf). The repo dirs take precedence over the published data. The
+// returned function cleans up any temporary files, and must be called.
+func advisoriesIndexAsOf(advisoriesURL string, advisoriesRepoDirs []string, asOf string) (*configs.Index[advisoryconfigs.Document], func(), error) {
+	var cleanups []func()
+	cleanup := func() {
+		for _, c := range cleanups {
+			c()
+		}
+	}
+
+	indices := make([]*configs.Index[advisoryconfigs.Document], 0, len(advisoriesRepoDirs)+1)
+
+	if advisoriesURL != "" {
+		if asOf != "" {
+			return nil, nil, fmt.Errorf("--as-of can't be used with published advisory data (%s), which has no history", advisoriesURL)
+		}
+
+		index, err := advisoriesIndexFromURL(advisoriesURL)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		indices = append(indices, index)
+	}
+
+	for _, dir := range advisoriesRepoDirs {
+		dir, c, err := advisoriesDirAsOf(dir, asOf)
+		if err != nil {
+			cleanup()
+			return nil, nil, err
+		}
+		cleanups = append(cleanups, c)
+
+		index, err := advisoryconfigs.NewIndex(rwos.DirFS(dir))
+		if err != nil {
+			cleanup()
+			return nil, nil, fmt.Errorf("unable to index advisory configs for directory %q: %w", dir, err)
+		}
+
+		indices = append(indices, index)
+	}
+
+	if len(indices) == 1 {
+		return indices[0], cleanup, nil
+	}
+
+	mergedDir, err := os.MkdirTemp("", "wolfictl-advisories-merged-")
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	cleanups = append(cleanups, func() { _ = os.RemoveAll(mergedDir) })
+
+	index, err := advisory.OverlayIndices(mergedDir, indices...)
+	if err != nil {
+		cleanup()
+		return nil, nil, fmt.Errorf("unable to overlay advisory data: %w", err)
+	}
+
+	return index, cleanup, nil
+}
+
+// loadStreamMap loads the streams file (see advisory.StreamsFile) of each of the
+// given advisories repo dirs that has one. Where more than one lists the same
+// project, the one from the dir given last is used.
+func loadStreamMap(advisoriesRepoDirs []string) (advisory.StreamMap, error) {
+	streams := make(advisory.StreamMap)
+	for _, dir := range advisoriesRepoDirs {
+		m, err := advisory.LoadStreamMap(filepath.Join(dir, advisory.StreamsFile))
+		if err != nil {
+			return nil, err
+		}
+		for project, pkgs := range m {
+			streams[project] = pkgs
+		}
+	}
+
+	return streams, nil
+}
+
+// projectStreams returns the packages in the index that are version streams of
+// the given project.
+func projectStreams(cfgs *configs.Index[advisoryconfigs.Document], streams advisory.StreamMap, project string) ([]string, error) {
+	packages := lo.Map(cfgs.Select().Configurations(), func(doc advisoryconfigs.Document, _ int) string {
+		return doc.Package.Name
+	})
+
+	found := lo.Intersect(streams.Streams(project, packages), packages)
+	if len(found) == 0 {
+		return nil, fmt.Errorf("no version streams of project %q have advisories", project)
+	}
+	sort.Strings(found)
+
+	return found, nil
+}
+
 type advisoryRequestParams struct {
 	packageName, vuln, status, action, impact, justification, timestamp, fixedVersion string
 
+	// eventType and note are alternatives to status, and to impact or action
+	// (depending on the status), named for the guided advisory flow's prompts.
+	eventType, note string
+
+	severity, cvss, severityRationale string
+
+	pendingUpstreamFix string
+
+	references []string
+
+	template, templatesFile string
+
 	// Deprecated: This flag is no longer used, and so this field is ignored.
 	sync bool
 }
@@ -92,32 +306,191 @@ func (p *advisoryRequestParams) addFlags(cmd *cobra.Command) {
 	addVulnFlag(&p.vuln, cmd)
 
 	cmd.Flags().StringVarP(&p.status, "status", "s", "", "status for VEX statement")
+	cmd.Flags().StringVar(&p.eventType, "type", "", fmt.Sprintf("type of the advisory event, i.e. its status (one of: %s; hyphens may be used instead of underscores)", strings.Join(vex.Statuses(), ", ")))
+	cmd.Flags().StringVar(&p.note, "note", "", "note recorded with the event: the impact statement for the not_affected type, or the action statement for the affected type")
 	cmd.Flags().StringVar(&p.action, "action", "", "action statement for VEX statement (used only for affected status)")
-	cmd.Flags().StringVar(&p.impact, "impact", "", "impact statement for VEX statement (used only for not_affected status)")
-	cmd.Flags().StringVar(&p.justification, "justification", "", "justification for VEX statement (used only for not_affected status)")
+	cmd.Flags().StringVar(&p.impact, "impact", "", "optional note explaining why the package is not affected, recorded as the VEX impact statement (used only for not_affected status)")
+	cmd.Flags().StringVar(&p.justification, "justification", "", fmt.Sprintf("justification for VEX statement, one of: %s (used only for not_affected status)", strings.Join(vex.Justifications(), ", ")))
 	cmd.Flags().StringVar(&p.timestamp, "timestamp", "now", "timestamp for VEX statement")
 	cmd.Flags().StringVar(&p.fixedVersion, "fixed-version", "", "package version where fix was applied (used only for fixed status)")
+	cmd.Flags().StringVar(&p.severity, "severity", "", fmt.Sprintf("the distro's own assessment of the vulnerability's severity in the package, overriding the upstream severity in exports and scans (one of: %s; requires --severity-rationale)", strings.Join(scan.Severities, ", ")))
+	cmd.Flags().StringVar(&p.cvss, "cvss", "", "CVSS v3 vector that the --severity assessment is based on")
+	cmd.Flags().StringVar(&p.severityRationale, "severity-rationale", "", "why the --severity assessment differs from the upstream one, e.g. \"not network reachable as packaged\"")
+	cmd.Flags().StringVar(&p.pendingUpstreamFix, "pending-upstream-fix", "", "URL of the upstream GitHub issue, pull request, or commit whose fix the package is waiting for, to be checked by 'wolfictl advisory poll-upstream' (implies affected status)")
+	cmd.Flags().StringArrayVar(&p.references, "reference", nil, fmt.Sprintf("link to context for the event, as <type>=<url>, where type is one of: %s (may be given more than once)", strings.Join(referenceTypes(), ", ")))
+	cmd.Flags().StringVar(&p.template, "template", "", "name of an advisory template whose status, justification, impact, and action fill in any of these not given as flags")
+	cmd.Flags().StringVar(&p.templatesFile, "templates-file", "", fmt.Sprintf("path to the advisory templates file (default: %s in the advisories repo)", advisory.TemplatesFile))
 	cmd.Flags().BoolVar(&p.sync, "sync", false, "synchronize secfixes data immediately after updating advisory")
 
 	_ = cmd.Flags().MarkDeprecated("sync", "because 'secfixes' data is no longer used. This flag now has no effect, and it will be removed in an upcoming version.") //nolint:errcheck
 }
 
-func (p *advisoryRequestParams) advisoryRequest() (advisory.Request, error) {
+// templates loads the advisory templates from --templates-file, or else from the
+// advisories repo's templates file, if it has one.
+func (p *advisoryRequestParams) templates(advisoriesRepoDir string) (advisory.Templates, error) {
+	if p.templatesFile == "" {
+		return advisory.LoadTemplates(filepath.Join(advisoriesRepoDir, advisory.TemplatesFile))
+	}
+
+	f, err := os.Open(p.templatesFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open advisory templates: %w", err)
+	}
+	defer f.Close()
+
+	return advisory.DecodeTemplates(f)
+}
+
+// advisoryRequest returns the request given by the flags, with the fields left
+// unset filled in from the --template, if any, out of the given templates.
+func (p *advisoryRequestParams) advisoryRequest(templates advisory.Templates) (advisory.Request, error) {
 	timestamp, err := resolveTimestamp(p.timestamp)
 	if err != nil {
 		return advisory.Request{}, fmt.Errorf("unable to process timestamp: %w", err)
 	}
 
-	return advisory.Request{
+	status, err := p.resolveStatus()
+	if err != nil {
+		return advisory.Request{}, err
+	}
+
+	req := advisory.Request{
 		Package:       p.packageName,
 		Vulnerability: p.vuln,
-		Status:        vex.Status(p.status),
+		Status:        status,
 		Action:        p.action,
 		Impact:        p.impact,
 		Justification: vex.Justification(p.justification),
 		Timestamp:     timestamp,
 		FixedVersion:  p.fixedVersion,
-	}, nil
+	}
+
+	if p.severity != "" || p.cvss != "" || p.severityRationale != "" {
+		req.SeverityOverride = &advisoryconfigs.SeverityOverride{
+			Severity:  p.severity,
+			CVSS:      p.cvss,
+			Rationale: p.severityRationale,
+		}
+	}
+
+	for _, r := range p.references {
+		ref, err := advisory.ParseReference(r)
+		if err != nil {
+			return advisory.Request{}, err
+		}
+		req.References = append(req.References, ref)
+	}
+
+	if p.pendingUpstreamFix != "" {
+		req.PendingUpstreamFix = &advisoryconfigs.PendingUpstreamFix{URL: p.pendingUpstreamFix}
+		if req.Status == "" {
+			req.Status = vex.StatusAffected
+		}
+	}
+
+	if p.template != "" {
+		t, err := templates.Get(p.template)
+		if err != nil {
+			return advisory.Request{}, err
+		}
+
+		req, err = t.Apply(req)
+		if err != nil {
+			return advisory.Request{}, err
+		}
+	}
+
+	if p.note != "" {
+		switch req.Status {
+		case vex.StatusNotAffected:
+			if req.Impact != "" {
+				return advisory.Request{}, fmt.Errorf("--note and --impact cannot be used together")
+			}
+			req.Impact = p.note
+		case vex.StatusAffected:
+			if req.Action != "" && p.action != "" {
+				return advisory.Request{}, fmt.Errorf("--note and --action cannot be used together")
+			}
+			req.Action = p.note
+		default:
+			return advisory.Request{}, fmt.Errorf("--note can only be used with the %s or %s type", vex.StatusAffected, vex.StatusNotAffected)
+		}
+	}
+
+	if req.PendingUpstreamFix != nil && req.Status == vex.StatusAffected && req.Action == "" {
+		req.Action = "wait for upstream fix"
+	}
+
+	return req, nil
+}
+
+// resolveStatus returns the status given by --type or --status, which may be
+// written with hyphens (e.g. "not-affected").
+func (p *advisoryRequestParams) resolveStatus() (vex.Status, error) {
+	status := vex.Status(strings.ReplaceAll(p.status, "-", "_"))
+	eventType := vex.Status(strings.ReplaceAll(p.eventType, "-", "_"))
+
+	if eventType != "" {
+		if status != "" && status != eventType {
+			return "", fmt.Errorf("--type %q and --status %q disagree", p.eventType, p.status)
+		}
+		status = eventType
+	}
+
+	return status, nil
+}
+
+// missingFlags returns the flags that would have to be given for the request to
+// be complete, for reporting when there's no way to prompt for them.
+func missingFlags(req advisory.Request) []string {
+	var missing []string
+	if req.Package == "" {
+		missing = append(missing, "--package")
+	}
+	if req.Vulnerability == "" {
+		missing = append(missing, "--vuln")
+	}
+
+	switch req.Status {
+	case "":
+		missing = append(missing, "--type")
+	case vex.StatusFixed:
+		if req.FixedVersion == "" {
+			missing = append(missing, "--fixed-version")
+		}
+	case vex.StatusNotAffected:
+		if req.Justification == "" {
+			missing = append(missing, "--justification")
+		}
+	case vex.StatusAffected:
+		if req.Action == "" {
+			missing = append(missing, "--note")
+		}
+	}
+
+	return missing
+}
+
+// incompleteRequestError explains why the request can't be made without
+// prompting, naming the missing flags where that's the reason.
+func incompleteRequestError(req advisory.Request, err error) error {
+	if missing := missingFlags(req); len(missing) > 0 {
+		return fmt.Errorf("not enough information for the advisory without prompting (missing %s): %w", strings.Join(missing, ", "), err)
+	}
+
+	return fmt.Errorf("not enough information for the advisory: %w", err)
+}
+
+// canPrompt returns true if the user can be prompted for input, i.e. unless
+// --no-prompt was given or stdin isn't a terminal.
+func canPrompt(doNotPrompt bool) bool {
+	return !doNotPrompt && term.IsTerminal(int(os.Stdin.Fd()))
+}
+
+func referenceTypes() []string {
+	return lo.Map(advisoryconfigs.ReferenceTypes, func(t advisoryconfigs.ReferenceType, _ int) string {
+		return string(t)
+	})
 }
 
 func addPackageFlag(val *string, cmd *cobra.Command) {
@@ -136,8 +509,45 @@ func addAdvisoriesDirFlag(val *string, cmd *cobra.Command) {
 	cmd.Flags().StringVarP(val, "advisories-repo-dir", "a", "", fmt.Sprintf("directory containing the advisories repository (can also be set with environment variable `%s`)", envVarNameForAdvisoriesDir))
 }
 
+// advisoriesIndexFromURL returns the Index of the advisory data published at the
+// given URL (see rwhttp.FS), cached under the user's cache directory.
+func advisoriesIndexFromURL(advisoriesURL string) (*configs.Index[advisoryconfigs.Document], error) {
+	sum := sha256.Sum256([]byte(advisoriesURL))
+	cacheDir := filepath.Join(xdg.CacheHome, "wolfictl", "advisories", hex.EncodeToString(sum[:])[:16])
+
+	fsys, err := rwhttp.New(advisoriesURL, cacheDir)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load published advisory data: %w", err)
+	}
+
+	index, err := advisoryconfigs.NewIndex(fsys)
+	if err != nil {
+		return nil, fmt.Errorf("unable to index advisory data published at %s: %w", advisoriesURL, err)
+	}
+
+	return index, nil
+}
+
+// resolveAdvisoriesURL returns the URL of the published advisory data given by
+// the --advisories-url flag, or else by the environment, if any.
+func resolveAdvisoriesURL(cliFlagValue string) string {
+	if cliFlagValue != "" {
+		return cliFlagValue
+	}
+
+	return os.Getenv(envVarNameForAdvisoriesURL)
+}
+
+func addAdvisoriesURLFlag(val *string, cmd *cobra.Command) {
+	cmd.Flags().StringVar(val, "advisories-url", "", fmt.Sprintf("base URL of published advisory data (see 'wolfictl advisory publish-index') to use instead of, or beneath, a local advisories repository (can also be set with environment variable `%s`)", envVarNameForAdvisoriesURL))
+}
+
+func addAdvisoriesDirsFlag(val *[]string, cmd *cobra.Command) {
+	cmd.Flags().StringSliceVarP(val, "advisories-repo-dir", "a", nil, fmt.Sprintf("directory containing an advisories repository; can be given more than once, in which case the advisory data is merged, with later directories taking precedence (can also be set with environment variable `%s`)", envVarNameForAdvisoriesDir))
+}
+
 func addNoPromptFlag(val *bool, cmd *cobra.Command) {
-	cmd.Flags().BoolVar(val, "no-prompt", false, "do not prompt the user for input")
+	cmd.Flags().BoolVar(val, "no-prompt", false, "do not prompt the user for input, and fail if any is missing (implied when stdin isn't a terminal)")
 }
 
 func addNoDistroDetectionFlag(val *bool, cmd *cobra.Command) {
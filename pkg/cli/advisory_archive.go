@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/wolfi-dev/wolfictl/pkg/advisory"
+	"github.com/wolfi-dev/wolfictl/pkg/distro"
+)
+
+func AdvisoryArchive() *cobra.Command {
+	p := &archiveParams{}
+	cmd := &cobra.Command{
+		Use:   "archive <package>",
+		Short: "Archive the advisory data of a package removed from the distro",
+		Long: fmt.Sprintf(`Archive the advisory data of a package removed from the distro.
+
+The package's advisories file is marked as archived and moved into the %q
+directory of the advisories repository, where it's no longer part of the active
+advisory data. Archived advisories are frozen: "advisory validate" checks them
+separately, and rejects any event recorded after the package was archived.
+
+A final VEX document for the package is printed (or written to --vex-output).
+It carries over the resolution of each advisory, and marks advisories that were
+still unresolved as affected, with no fix forthcoming, since the package is no
+longer supported.`, advisory.ArchiveDir),
+		Example:       `  wolfictl advisory archive py3-foo --reason "replaced by py3.11-foo" --vex-output py3-foo.openvex.json`,
+		SilenceErrors: true,
+		Args:          cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			advisoriesRepoDir := resolveAdvisoriesDir(p.advisoriesRepoDir)
+			if advisoriesRepoDir == "" {
+				if p.doNotDetectDistro {
+					return fmt.Errorf("advisories repo dir was left unspecified")
+				}
+
+				d, err := distro.Detect()
+				if err != nil {
+					return fmt.Errorf("advisories repo dir was left unspecified, and distro auto-detection failed: %w", err)
+				}
+
+				advisoriesRepoDir = d.AdvisoriesRepoDir
+				_, _ = fmt.Fprint(os.Stderr, renderDetectedDistro(d))
+			}
+
+			v, err := advisory.Archive(advisory.ArchiveOptions{
+				AdvisoriesDir:    advisoriesRepoDir,
+				Package:          args[0],
+				Reason:           p.reason,
+				ProductNamespace: p.productNamespace,
+			})
+			if err != nil {
+				return err
+			}
+
+			out := os.Stdout
+			if p.vexOutput != "" {
+				f, err := os.Create(p.vexOutput)
+				if err != nil {
+					return fmt.Errorf("unable to create VEX output file: %w", err)
+				}
+				defer f.Close()
+				out = f
+			}
+
+			if err := v.ToJSON(out); err != nil {
+				return fmt.Errorf("unable to write VEX document: %w", err)
+			}
+
+			fmt.Fprintf(os.Stderr, "archived advisories for %s (%d advisories)\n", args[0], len(v.Statements))
+			return nil
+		},
+	}
+
+	p.addFlagsTo(cmd)
+	return cmd
+}
+
+type archiveParams struct {
+	doNotDetectDistro bool
+	advisoriesRepoDir string
+	reason            string
+	productNamespace  string
+	vexOutput         string
+}
+
+func (p *archiveParams) addFlagsTo(cmd *cobra.Command) {
+	addNoDistroDetectionFlag(&p.doNotDetectDistro, cmd)
+	addAdvisoriesDirFlag(&p.advisoriesRepoDir, cmd)
+	cmd.Flags().StringVar(&p.reason, "reason", "", "why the package was removed (e.g. the name of its replacement)")
+	cmd.Flags().StringVar(&p.productNamespace, "product-namespace", "wolfi", "namespace used in the package URLs that identify the product in the VEX document")
+	cmd.Flags().StringVar(&p.vexOutput, "vex-output", "", "write the final VEX document to this file instead of stdout")
+}
@@ -0,0 +1,177 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wolfi-dev/wolfictl/pkg/advisory"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
+)
+
+func AdvisoryCGA() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cga",
+		Short: "Commands for managing the distro's own advisory IDs (CGA IDs)",
+	}
+
+	cmd.AddCommand(AdvisoryCGAAllocate())
+	cmd.AddCommand(AdvisoryCGAResolve())
+
+	return cmd
+}
+
+func AdvisoryCGAAllocate() *cobra.Command {
+	p := &cgaAllocateParams{}
+	cmd := &cobra.Command{
+		Use:   "allocate",
+		Short: "allocate CGA IDs to advisories that don't have one yet",
+		Long: `allocate CGA IDs to advisories that don't have one yet
+
+Each advisory (i.e. each package's advisory for a vulnerability) without a CGA
+ID is allocated a new, random one of the form CGA-xxxx-xxxx-xxxx, which is
+recorded among the advisory's aliases.
+
+New IDs never collide with an ID already in the advisory data. They have about
+52 bits of randomness, so IDs allocated concurrently, e.g. on different branches
+of the advisories repository, won't collide in practice either; 'wolfictl
+advisory validate' reports any CGA ID allocated to more than one advisory.`,
+		Example: `  wolfictl advisory cga allocate
+
+  wolfictl advisory cga allocate -p openssl -V CVE-2023-0464 --dry-run`,
+		SilenceErrors: true,
+		Args:          cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			advisoriesRepoDir, err := p.resolveAdvisoriesDir()
+			if err != nil {
+				return err
+			}
+
+			advisoryCfgs, err := advisoryconfigs.NewIndex(rwos.DirFS(advisoriesRepoDir))
+			if err != nil {
+				return err
+			}
+
+			allocations, err := advisory.AllocateCGAIDs(advisory.AllocateCGAIDsOptions{
+				AdvisoryCfgs:  advisoryCfgs,
+				Package:       p.packageName,
+				Vulnerability: p.vuln,
+				DryRun:        p.dryRun,
+			})
+			if err != nil {
+				return err
+			}
+
+			for _, a := range allocations {
+				fmt.Printf("%s: %s: %s\n", a.Package, a.Vulnerability, a.CGAID)
+			}
+
+			verb := "Allocated"
+			if p.dryRun {
+				verb = "Would allocate"
+			}
+			_, _ = fmt.Fprintf(os.Stderr, "%s %d CGA IDs\n", verb, len(allocations))
+
+			return nil
+		},
+	}
+
+	p.addFlagsTo(cmd)
+	return cmd
+}
+
+type cgaAllocateParams struct {
+	advisoriesDirParams
+
+	packageName, vuln string
+	dryRun            bool
+}
+
+func (p *cgaAllocateParams) addFlagsTo(cmd *cobra.Command) {
+	p.advisoriesDirParams.addFlagsTo(cmd)
+
+	addPackageFlag(&p.packageName, cmd)
+	addVulnFlag(&p.vuln, cmd)
+	cmd.Flags().BoolVar(&p.dryRun, "dry-run", false, "only list the IDs that would be allocated, without recording them")
+}
+
+func AdvisoryCGAResolve() *cobra.Command {
+	p := &cgaResolveParams{}
+	cmd := &cobra.Command{
+		Use:   "resolve <id>",
+		Short: "resolve a CGA ID to its vulnerability and aliases, or a vulnerability ID to its CGA IDs",
+		Long: `resolve a CGA ID to its vulnerability and aliases, or a vulnerability ID to its CGA IDs
+
+Given a CGA ID, the advisory it was allocated to is shown, along with the
+vulnerability's other IDs. Given any other ID (e.g. a CVE or GHSA ID), every
+advisory for that vulnerability is shown, one per package, with its CGA ID.`,
+		Example: `  wolfictl advisory cga resolve CGA-2fm3-x7g4-q9wh
+
+  wolfictl advisory cga resolve CVE-2023-0464 --json`,
+		SilenceErrors: true,
+		Args:          cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			advisoriesRepoDir, err := p.resolveAdvisoriesDir()
+			if err != nil {
+				return err
+			}
+
+			advisoryCfgs, err := advisoryconfigs.NewIndex(rwos.DirFS(advisoriesRepoDir))
+			if err != nil {
+				return err
+			}
+
+			resolutions := advisory.ResolveCGAID(advisoryCfgs, args[0])
+			if len(resolutions) == 0 {
+				return fmt.Errorf("no advisories found for %s", args[0])
+			}
+
+			if p.outputJSON {
+				out := make([]cgaResolutionJSON, 0, len(resolutions))
+				for _, r := range resolutions {
+					out = append(out, cgaResolutionJSON(r))
+				}
+
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(out)
+			}
+
+			for _, r := range resolutions {
+				cgaID := r.CGAID
+				if cgaID == "" {
+					cgaID = "(no CGA ID)"
+				}
+				ids := append([]string{r.Vulnerability}, r.Aliases...)
+				fmt.Printf("%s: %s: %s\n", r.Package, cgaID, strings.Join(ids, ", "))
+			}
+
+			return nil
+		},
+	}
+
+	p.addFlagsTo(cmd)
+	return cmd
+}
+
+type cgaResolveParams struct {
+	advisoriesDirParams
+
+	outputJSON bool
+}
+
+func (p *cgaResolveParams) addFlagsTo(cmd *cobra.Command) {
+	p.advisoriesDirParams.addFlagsTo(cmd)
+
+	cmd.Flags().BoolVar(&p.outputJSON, "json", false, "print the resolved advisories as JSON")
+}
+
+type cgaResolutionJSON struct {
+	Package       string   `json:"package"`
+	Vulnerability string   `json:"vulnerability"`
+	CGAID         string   `json:"cgaID,omitempty"`
+	Aliases       []string `json:"aliases,omitempty"`
+}
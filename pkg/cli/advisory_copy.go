@@ -0,0 +1,152 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"chainguard.dev/melange/pkg/build"
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+	"github.com/wolfi-dev/wolfictl/pkg/advisory"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	buildconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/build"
+	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
+	"github.com/wolfi-dev/wolfictl/pkg/distro"
+)
+
+func AdvisoryCopy() *cobra.Command {
+	p := &copyParams{}
+	cmd := &cobra.Command{
+		Use:   "copy <package> <vulnerability-id>",
+		Short: "copy an advisory's latest event to the package's other version streams",
+		Long: `copy an advisory's latest event to the package's other version streams
+
+Packages like openjdk-17 or postgresql-16 are one version stream of a project
+that has several. This command records the latest event of the given advisory
+for the project's other streams too, creating their advisories where needed.
+
+By default, the event is copied to every sibling stream, as found among the
+packages of the distro and advisories repositories. Use --to to choose streams.
+
+Since streams don't share versions, copying a fixed event requires each
+stream's own fixed version, given with --fixed-version <stream>=<version>.
+Streams whose latest event already matches the copied one are left untouched.`,
+		Example: `  # Mark a CVE fixed in each supported OpenJDK stream
+  wolfictl advisory copy openjdk-17 CVE-2023-22081 \
+    --fixed-version openjdk-11=11.0.21-r0,openjdk-21=21.0.1-r0
+
+  # Copy a not_affected triage to one other stream only
+  wolfictl advisory copy postgresql-16 CVE-2023-5868 --to postgresql-15`,
+		SilenceErrors: true,
+		Args:          cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			distroRepoDir := resolveDistroDir(p.distroRepoDir)
+			advisoriesRepoDir := resolveAdvisoriesDir(p.advisoriesRepoDir)
+			if advisoriesRepoDir == "" {
+				if p.doNotDetectDistro {
+					return fmt.Errorf("no advisories repo dir specified")
+				}
+
+				d, err := distro.Detect()
+				if err != nil {
+					return fmt.Errorf("no advisories repo dir specified, and distro auto-detection failed: %w", err)
+				}
+
+				advisoriesRepoDir = d.AdvisoriesRepoDir
+				if distroRepoDir == "" {
+					distroRepoDir = d.DistroRepoDir
+				}
+				_, _ = fmt.Fprint(os.Stderr, renderDetectedDistro(d))
+			}
+
+			advisoryCfgs, err := advisoryconfigs.NewIndex(rwos.DirFS(advisoriesRepoDir))
+			if err != nil {
+				return err
+			}
+
+			packageName, vulnID := args[0], args[1]
+
+			streams := p.streams
+			if len(streams) == 0 {
+				candidates := lo.Map(advisoryCfgs.Select().Configurations(), func(cfg advisoryconfigs.Document, _ int) string {
+					return cfg.Package.Name
+				})
+
+				if distroRepoDir != "" {
+					buildCfgs, err := buildconfigs.NewIndex(rwos.DirFS(distroRepoDir))
+					if err != nil {
+						return err
+					}
+					candidates = append(candidates, lo.Map(buildCfgs.Select().Configurations(), func(cfg build.Configuration, _ int) string {
+						return cfg.Package.Name
+					})...)
+				}
+
+				streams = advisory.SiblingStreams(packageName, candidates)
+				if len(streams) == 0 {
+					return fmt.Errorf("no other version streams found for %q (use --to to name them)", packageName)
+				}
+			}
+
+			for stream := range p.fixedVersions {
+				if !lo.Contains(streams, stream) {
+					return fmt.Errorf("--fixed-version given for %q, which isn't one of the streams to copy to (%s)", stream, strings.Join(streams, ", "))
+				}
+			}
+
+			reqs, err := advisory.CopyToStreams(advisory.CopyToStreamsOptions{
+				AdvisoryCfgs:  advisoryCfgs,
+				Package:       packageName,
+				Vulnerability: vulnID,
+				Streams:       streams,
+				FixedVersions: p.fixedVersions,
+				DryRun:        p.dryRun,
+			})
+			if err != nil {
+				return err
+			}
+
+			verb := "Copied"
+			if p.dryRun {
+				verb = "Would copy"
+			}
+			for _, req := range reqs {
+				entry := advisoryconfigs.Entry{
+					Status:          req.Status,
+					Justification:   req.Justification,
+					ActionStatement: req.Action,
+					FixedVersion:    req.FixedVersion,
+				}
+				fmt.Printf("%s: %s: %s\n", req.Package, req.Vulnerability, renderListItem(entry))
+			}
+			_, _ = fmt.Fprintf(os.Stderr, "%s the event to %d of %d streams\n", verb, len(reqs), len(streams))
+
+			return nil
+		},
+	}
+
+	p.addFlagsTo(cmd)
+	return cmd
+}
+
+type copyParams struct {
+	doNotDetectDistro bool
+
+	distroRepoDir, advisoriesRepoDir string
+
+	streams       []string
+	fixedVersions map[string]string
+	dryRun        bool
+}
+
+func (p *copyParams) addFlagsTo(cmd *cobra.Command) {
+	addNoDistroDetectionFlag(&p.doNotDetectDistro, cmd)
+
+	addDistroDirFlag(&p.distroRepoDir, cmd)
+	addAdvisoriesDirFlag(&p.advisoriesRepoDir, cmd)
+
+	cmd.Flags().StringSliceVar(&p.streams, "to", nil, "packages to copy the event to (default: all other version streams of the package)")
+	cmd.Flags().StringToStringVar(&p.fixedVersions, "fixed-version", nil, "fixed version of each stream, as <stream>=<version> (required to copy a fixed event)")
+	cmd.Flags().BoolVar(&p.dryRun, "dry-run", false, "only show the events that would be copied, without recording them")
+}
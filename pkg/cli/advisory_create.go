@@ -3,6 +3,8 @@ package cli
 import (
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"chainguard.dev/melange/pkg/build"
 	tea "github.com/charmbracelet/bubbletea"
@@ -10,19 +12,57 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/wolfi-dev/wolfictl/pkg/advisory"
 	"github.com/wolfi-dev/wolfictl/pkg/cli/components/advisory/prompt"
+	"github.com/wolfi-dev/wolfictl/pkg/configs"
 	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
 	buildconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/build"
 	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
 	"github.com/wolfi-dev/wolfictl/pkg/distro"
 	"github.com/wolfi-dev/wolfictl/pkg/index"
+	"github.com/wolfi-dev/wolfictl/pkg/scan"
 	"gitlab.alpinelinux.org/alpine/go/repository"
 )
 
 func AdvisoryCreate() *cobra.Command {
 	p := &createParams{}
 	cmd := &cobra.Command{
-		Use:           "create",
-		Short:         "create a new advisory for a package",
+		Use:   "create",
+		Short: "create a new advisory for a package",
+		Long: `create a new advisory for a package
+
+With --from-scan, an advisory is created for each finding in a saved scan
+result (the output of 'wolfictl scan --output json'), or an entry is added if
+the package already has an advisory for the vulnerability. The status and
+other advisory fields given as flags apply to every finding. Any that are
+missing are prompted for once per package, and the answers apply to all of
+that package's findings. Findings whose advisory already has the same status
+are left alone.
+
+Common triage outcomes can be defined as templates in the advisories repo's
+advisory-templates.yaml file (or the file given by --templates-file), e.g.:
+
+  templates:
+    build-dependency-only:
+      status: not_affected
+      justification: vulnerable_code_not_in_execute_path
+      impact: The vulnerable component is only used at build time.
+
+With --template, the named template fills in the status, justification,
+impact, and action not given as flags. Otherwise, the templates are offered when
+prompting for the status.
+
+Every prompt has a corresponding flag (--package, --vuln, --type,
+--fixed-version, --justification, --note, --timestamp), so advisories can be
+created by automation. Without a terminal on stdin (or with --no-prompt), any
+missing input is an error that names the flags to give instead.`,
+		Example: `  wolfictl advisory create -p crane -V CVE-2023-1234 -s under_investigation
+
+  wolfictl scan crane-0.15.2-r0.apk -o json > scan.json
+  wolfictl advisory create --from-scan scan.json -s under_investigation
+
+  wolfictl advisory create -p crane -V CVE-2023-1234 --template build-dependency-only
+
+  # Non-interactively, e.g. from a bot that fixed the CVE with a version bump
+  wolfictl advisory create --no-prompt -p crane -V CVE-2023-1234 --type fixed --fixed-version 0.15.3-r0`,
 		SilenceErrors: true,
 		Args:          cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -65,11 +105,20 @@ func AdvisoryCreate() *cobra.Command {
 				return fmt.Errorf("unable to select packages: %w", err)
 			}
 
-			req, err := p.requestParams.advisoryRequest()
+			templates, err := p.requestParams.templates(advisoriesRepoDir)
+			if err != nil {
+				return err
+			}
+
+			req, err := p.requestParams.advisoryRequest(templates)
 			if err != nil {
 				return err
 			}
 
+			if p.fromScan != "" && req.Vulnerability != "" {
+				return fmt.Errorf("--vuln can't be used with --from-scan")
+			}
+
 			var apkindexes []*repository.ApkIndex
 			for _, arch := range archs {
 				idx, err := index.Index(arch, packageRepositoryURL)
@@ -79,9 +128,23 @@ func AdvisoryCreate() *cobra.Command {
 				apkindexes = append(apkindexes, idx)
 			}
 
+			if p.fromScan != "" {
+				results, err := scan.ReadJSONResultsFile(p.fromScan)
+				if err != nil {
+					return err
+				}
+
+				reqs, err := advisory.RequestsFromScan(results, req.Package, req)
+				if err != nil {
+					return fmt.Errorf("%w (use --package to name the package)", err)
+				}
+
+				return createFromScan(reqs, advisoryCfgs, canPrompt(p.doNotPrompt), newAllowedFixedVersionsFunc(apkindexes, buildCfgs), templates)
+			}
+
 			if err := req.Validate(); err != nil {
-				if p.doNotPrompt {
-					return fmt.Errorf("not enough information to create advisory: %w", err)
+				if !canPrompt(p.doNotPrompt) {
+					return incompleteRequestError(req, err)
 				}
 
 				// prompt for missing fields
@@ -92,8 +155,14 @@ func AdvisoryCreate() *cobra.Command {
 					})
 				}
 
+				// Suggest CVEs from the last couple of years, as found in the local copy of
+				// the scan vulnerability DB. Without one, any CVE ID can still be entered.
 				allowedVulnerabilities := func(packageName string) []string {
-					return nil
+					ids, err := scan.RecentCVEIDs(time.Now().Year() - 1)
+					if err != nil {
+						return nil
+					}
+					return ids
 				}
 
 				allowedFixedVersions := newAllowedFixedVersionsFunc(apkindexes, buildCfgs)
@@ -103,6 +172,9 @@ func AdvisoryCreate() *cobra.Command {
 					AllowedPackagesFunc:        allowedPackages,
 					AllowedVulnerabilitiesFunc: allowedVulnerabilities,
 					AllowedFixedVersionsFunc:   allowedFixedVersions,
+					Templates:                  templates,
+
+					AllowUnlistedVulnerabilities: true,
 				})
 				var returnedModel tea.Model
 				program := tea.NewProgram(m)
@@ -147,6 +219,7 @@ type createParams struct {
 	distroRepoDir, advisoriesRepoDir string
 	archs                            []string
 	packageRepositoryURL             string
+	fromScan                         string
 }
 
 func (p *createParams) addFlagsTo(cmd *cobra.Command) {
@@ -158,4 +231,77 @@ func (p *createParams) addFlagsTo(cmd *cobra.Command) {
 	addAdvisoriesDirFlag(&p.advisoriesRepoDir, cmd)
 	cmd.Flags().StringSliceVar(&p.archs, "arch", []string{"x86_64", "aarch64"}, "package architectures to find published versions for")
 	cmd.Flags().StringVarP(&p.packageRepositoryURL, "package-repo-url", "r", "", "URL of the APK package repository")
+	cmd.Flags().StringVar(&p.fromScan, "from-scan", "", "path to a saved scan result (from 'wolfictl scan --output json') to create advisories for all of its findings")
+}
+
+// createFromScan creates or updates the advisory for each request. Requests
+// are grouped by package, and if the flags didn't provide enough information,
+// the user is prompted once per package for the missing fields, if interactive.
+func createFromScan(reqs []advisory.Request, advisoryCfgs *configs.Index[advisoryconfigs.Document], interactive bool, allowedFixedVersions func(string) []string, templates advisory.Templates) error {
+	if len(reqs) == 0 {
+		_, _ = fmt.Fprintln(os.Stderr, "No findings in scan result")
+		return nil
+	}
+
+	byPackage := lo.GroupBy(reqs, func(req advisory.Request) string {
+		return req.Package
+	})
+	packages := lo.Uniq(lo.Map(reqs, func(req advisory.Request, _ int) string {
+		return req.Package
+	}))
+
+	var created, unchanged int
+	for _, pkg := range packages {
+		pkgReqs := byPackage[pkg]
+
+		if err := pkgReqs[0].Validate(); err != nil {
+			if !interactive {
+				return incompleteRequestError(pkgReqs[0], err)
+			}
+
+			vulns := lo.Map(pkgReqs, func(req advisory.Request, _ int) string {
+				return req.Vulnerability
+			})
+			_, _ = fmt.Fprintf(os.Stderr, "%s: %s\n", pkg, strings.Join(vulns, ", "))
+
+			m := prompt.New(prompt.Configuration{
+				Request:                  pkgReqs[0],
+				AllowedFixedVersionsFunc: allowedFixedVersions,
+				Templates:                templates,
+			})
+			returnedModel, err := tea.NewProgram(m).Run()
+			if err != nil {
+				return err
+			}
+
+			m, ok := returnedModel.(prompt.Model)
+			if !ok {
+				return fmt.Errorf("unexpected model type: %T", returnedModel)
+			}
+			if m.EarlyExit {
+				return nil
+			}
+
+			for i := range pkgReqs {
+				vuln := pkgReqs[i].Vulnerability
+				pkgReqs[i] = m.Request
+				pkgReqs[i].Vulnerability = vuln
+			}
+		}
+
+		for _, req := range pkgReqs {
+			changed, err := advisory.Import(req, advisoryCfgs)
+			if err != nil {
+				return err
+			}
+			if changed {
+				created++
+			} else {
+				unchanged++
+			}
+		}
+	}
+
+	_, _ = fmt.Fprintf(os.Stderr, "Created or updated %d advisories (%d already up to date)\n", created, unchanged)
+	return nil
 }
@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/wolfi-dev/wolfictl/pkg/advisory"
+	"github.com/wolfi-dev/wolfictl/pkg/configs"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
+)
+
+func AdvisoryDashboard() *cobra.Command {
+	p := &dashboardParams{}
+	cmd := &cobra.Command{
+		Use:   "dashboard",
+		Short: "generate a static HTML dashboard of the advisory data",
+		Long: `generate a static HTML dashboard of the advisory data
+
+The dashboard is a browsable static site, written to --output-dir, with:
+
+  - a page for each package, listing its advisories and their events
+  - a page for each vulnerability, listing the status of each package
+  - the queue of open investigations (advisories whose latest event is
+    under_investigation), longest open first
+  - a feed of the most recent advisory events
+
+Archived packages are included. Only public advisory data is rendered.`,
+		Example:       `  wolfictl advisory dashboard --output-dir site/`,
+		SilenceErrors: true,
+		Args:          cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if p.outputDir == "" {
+				return fmt.Errorf("--output-dir is required")
+			}
+
+			advisoriesRepoDir, err := p.resolveAdvisoriesDir()
+			if err != nil {
+				return err
+			}
+
+			indices := make([]*configs.Index[advisoryconfigs.Document], 0, 2)
+			for _, dir := range []string{advisoriesRepoDir, filepath.Join(advisoriesRepoDir, advisory.ArchiveDir)} {
+				if _, err := os.Stat(dir); errors.Is(err, fs.ErrNotExist) {
+					continue
+				}
+
+				index, err := advisoryconfigs.NewIndex(rwos.DirFS(dir))
+				if err != nil {
+					return fmt.Errorf("unable to index advisory configs for directory %q: %w", dir, err)
+				}
+				indices = append(indices, index)
+			}
+
+			d := advisory.BuildDashboard(advisory.DashboardOptions{
+				AdvisoryCfgIndices: indices,
+				Title:              p.title,
+				ActivityLimit:      p.activityLimit,
+			})
+
+			paths, err := advisory.WriteDashboard(d, p.outputDir)
+			if err != nil {
+				return err
+			}
+
+			_, _ = fmt.Fprintf(os.Stderr, "Wrote %d dashboard pages to %s\n", len(paths), p.outputDir)
+			return nil
+		},
+	}
+
+	p.addFlagsTo(cmd)
+	return cmd
+}
+
+type dashboardParams struct {
+	advisoriesDirParams
+
+	outputDir     string
+	title         string
+	activityLimit int
+}
+
+func (p *dashboardParams) addFlagsTo(cmd *cobra.Command) {
+	p.advisoriesDirParams.addFlagsTo(cmd)
+
+	cmd.Flags().StringVar(&p.outputDir, "output-dir", "", "directory to write the dashboard's pages to")
+	cmd.Flags().StringVar(&p.title, "title", "Security advisories", "title of the dashboard")
+	cmd.Flags().IntVar(&p.activityLimit, "activity-limit", advisory.DefaultDashboardActivityLimit, "number of events to list in the recent activity feed")
+}
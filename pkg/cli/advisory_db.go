@@ -10,16 +10,58 @@ import (
 	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
 	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
 	"github.com/wolfi-dev/wolfictl/pkg/distro"
+	"github.com/wolfi-dev/wolfictl/pkg/index"
+	"gitlab.alpinelinux.org/alpine/go/repository"
 )
 
 func AdvisoryDB() *cobra.Command {
+	cmd := newSecDBBuildCommand(false)
+	cmd.Use = "db"
+	cmd.Deprecated = "use 'wolfictl advisory secdb build' instead."
+	return cmd
+}
+
+func AdvisorySecDB() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "secdb",
+		Short: "Commands for the Alpine-format security database (secdb) built from advisory data",
+	}
+
+	cmd.AddCommand(AdvisorySecDBBuild())
+
+	return cmd
+}
+
+func AdvisorySecDBBuild() *cobra.Command {
+	cmd := newSecDBBuildCommand(true)
+	cmd.Long = `Build the security database (secdb) consumed by apk-tools and scanners from advisory data
+
+Each package's fixed advisories are listed under their fixed versions, and its
+not_affected advisories under version "0". Internal-only advisory data is left
+out.
+
+Unless --verify-fixed-versions=false is given, each fixed version is first
+checked against the package repository's APKINDEX for each --arch, and the
+build fails if any fixed version hasn't been published.`
+	cmd.Example = `  wolfictl advisory secdb build --arch x86_64 --output security.json`
+	return cmd
+}
+
+// newSecDBBuildCommand returns a command that builds a secdb from advisory data,
+// verifying fixed versions against the package repository by default if
+// verifyByDefault is true.
+func newSecDBBuildCommand(verifyByDefault bool) *cobra.Command {
 	p := &dbParams{}
 	cmd := &cobra.Command{
-		Use:           "db",
-		Short:         "Build a security database from advisory data (NOTE: For now, this command uses secfixes data, but will soon use advisory data instead.)",
+		Use:           "build",
+		Short:         "Build a security database from advisory data",
 		SilenceErrors: true,
 		Args:          cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if p.sign && p.outputLocation == "" {
+				return fmt.Errorf("--sign requires --output")
+			}
+
 			if len(p.advisoriesRepoDirs) == 0 {
 				if p.doNotDetectDistro {
 					return fmt.Errorf("no advisories repo dir specified")
@@ -31,6 +73,9 @@ func AdvisoryDB() *cobra.Command {
 				}
 
 				p.advisoriesRepoDirs = append(p.advisoriesRepoDirs, d.AdvisoriesRepoDir)
+				if p.packageRepositoryURL == "" {
+					p.packageRepositoryURL = d.APKRepositoryURL
+				}
 				_, _ = fmt.Fprint(os.Stderr, renderDetectedDistro(d))
 			}
 
@@ -45,6 +90,25 @@ func AdvisoryDB() *cobra.Command {
 				indices = append(indices, index)
 			}
 
+			if p.verifyFixedVersions {
+				if p.packageRepositoryURL == "" {
+					return fmt.Errorf("no package repository URL specified, which is needed to verify fixed versions")
+				}
+
+				var apkindexes []*repository.ApkIndex
+				for _, arch := range p.archs {
+					idx, err := index.Index(arch, p.packageRepositoryURL)
+					if err != nil {
+						return fmt.Errorf("unable to load APKINDEX for %s: %w", arch, err)
+					}
+					apkindexes = append(apkindexes, idx)
+				}
+
+				if err := advisory.VerifyFixedVersions(indices, apkindexes); err != nil {
+					return fmt.Errorf("unable to build the security database: %w", err)
+				}
+			}
+
 			opts := advisory.BuildDatabaseOptions{
 				AdvisoryCfgIndices: indices,
 				URLPrefix:          p.urlPrefix,
@@ -73,11 +137,15 @@ func AdvisoryDB() *cobra.Command {
 				return fmt.Errorf("unable to write the security database to specified location: %w", err)
 			}
 
+			if p.sign {
+				return signExport(p.outputLocation)
+			}
+
 			return nil
 		},
 	}
 
-	p.addFlagsTo(cmd)
+	p.addFlagsTo(cmd, verifyByDefault)
 	return cmd
 }
 
@@ -91,9 +159,14 @@ type dbParams struct {
 	urlPrefix string
 	archs     []string
 	repo      string
+
+	packageRepositoryURL string
+	verifyFixedVersions  bool
+
+	sign bool
 }
 
-func (p *dbParams) addFlagsTo(cmd *cobra.Command) {
+func (p *dbParams) addFlagsTo(cmd *cobra.Command, verifyByDefault bool) {
 	addNoDistroDetectionFlag(&p.doNotDetectDistro, cmd)
 
 	cmd.Flags().StringSliceVarP(&p.advisoriesRepoDirs, "advisories-repo-dir", "a", nil, "directory containing an advisories repository")
@@ -103,4 +176,9 @@ func (p *dbParams) addFlagsTo(cmd *cobra.Command) {
 	cmd.Flags().StringVar(&p.urlPrefix, "url-prefix", "https://packages.wolfi.dev", "URL scheme and hostname for the package repository")
 	cmd.Flags().StringSliceVar(&p.archs, "arch", []string{"x86_64"}, "the package architectures the security database is for")
 	cmd.Flags().StringVar(&p.repo, "repo", "os", "the name of the package repository")
+
+	cmd.Flags().StringVarP(&p.packageRepositoryURL, "package-repo-url", "r", "", "URL of the APK package repository, used to verify fixed versions (default: the detected distro's repository)")
+	cmd.Flags().BoolVar(&p.verifyFixedVersions, "verify-fixed-versions", verifyByDefault, "fail if any fixed version hasn't been published to the package repository")
+
+	addSignExportFlag(&p.sign, cmd)
 }
@@ -0,0 +1,224 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wolfi-dev/wolfictl/pkg/advisory"
+	"github.com/wolfi-dev/wolfictl/pkg/configs"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
+	"github.com/wolfi-dev/wolfictl/pkg/distro"
+	"github.com/wolfi-dev/wolfictl/pkg/git"
+)
+
+func AdvisoryDiff() *cobra.Command {
+	p := &diffParams{}
+	cmd := &cobra.Command{
+		Use:         "diff <from-ref> [<to-ref>]",
+		Annotations: pagerAnnotations,
+		Short:       "Summarize the changes to advisory data between two git refs, as Markdown",
+		Long: `Summarize the changes to advisory data between two git refs, as Markdown
+
+The advisories repository is compared as it was at each ref (a branch, tag, or
+commit). If no second ref is given, the repository's current files are used,
+including uncommitted changes. The refs can also be given as a single git range:
+"A..B" compares A to B, and "A...B" compares the merge base of A and B to B, as
+for a pull request from B into A.
+
+By default, the summary lists the advisories that were added, removed, or
+modified, along with the events that were added or removed. With --summary, each
+change is instead described in a short sentence (e.g. "marked CVE-2023-1234
+fixed in foo 1.2.3-r1"), which is suitable for a bot to post as a comment on an
+advisories pull request.`,
+		Example: `  wolfictl advisory diff origin/main
+
+  wolfictl advisory diff main my-branch > summary.md
+
+  wolfictl advisory diff --summary origin/main...HEAD`,
+		SilenceErrors: true,
+		Args:          cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			advisoriesRepoDir := resolveAdvisoriesDir(p.advisoriesRepoDir)
+			if advisoriesRepoDir == "" {
+				if p.doNotDetectDistro {
+					return fmt.Errorf("no advisories repo dir specified")
+				}
+
+				d, err := distro.Detect()
+				if err != nil {
+					return fmt.Errorf("no advisories repo dir specified, and distro auto-detection failed: %w", err)
+				}
+
+				advisoriesRepoDir = d.AdvisoriesRepoDir
+				_, _ = fmt.Fprint(os.Stderr, renderDetectedDistro(d))
+			}
+
+			refs, err := resolveDiffRefs(advisoriesRepoDir, args)
+			if err != nil {
+				return err
+			}
+
+			from, err := advisoryIndexAtRef(advisoriesRepoDir, refs.from)
+			if err != nil {
+				return err
+			}
+
+			var to *configs.Index[advisoryconfigs.Document]
+			if refs.to != "" {
+				to, err = advisoryIndexAtRef(advisoriesRepoDir, refs.to)
+			} else {
+				to, err = advisoryconfigs.NewIndex(rwos.DirFS(advisoriesRepoDir))
+			}
+			if err != nil {
+				return err
+			}
+
+			d := advisory.Diff(from, to)
+			if p.summary {
+				fmt.Print(renderAdvisoryDiffSummary(refs.fromLabel, refs.toLabel, d))
+				return nil
+			}
+
+			fmt.Print(renderAdvisoryDiff(refs.fromLabel, refs.toLabel, d))
+			return nil
+		},
+	}
+
+	p.addFlagsTo(cmd)
+	return cmd
+}
+
+type diffParams struct {
+	doNotDetectDistro bool
+	advisoriesRepoDir string
+	summary           bool
+}
+
+func (p *diffParams) addFlagsTo(cmd *cobra.Command) {
+	addNoDistroDetectionFlag(&p.doNotDetectDistro, cmd)
+	addAdvisoriesDirFlag(&p.advisoriesRepoDir, cmd)
+	cmd.Flags().BoolVar(&p.summary, "summary", false, "describe each change in a short sentence, for posting on a pull request")
+}
+
+// diffRefs are the git refs to compare, and how to refer to them in output. An
+// empty to ref means the working tree.
+type diffRefs struct {
+	from, to           string
+	fromLabel, toLabel string
+}
+
+// resolveDiffRefs resolves the arguments to 'advisory diff', which are either
+// one or two refs, or a single git range ("A..B" or "A...B"), into the refs to
+// compare.
+func resolveDiffRefs(dir string, args []string) (diffRefs, error) {
+	if len(args) == 2 {
+		return diffRefs{from: args[0], to: args[1], fromLabel: args[0], toLabel: args[1]}, nil
+	}
+
+	rng := args[0]
+	if a, b, ok := strings.Cut(rng, "..."); ok {
+		a, b = refOrHEAD(a), refOrHEAD(b)
+		base, err := git.MergeBase(dir, a, b)
+		if err != nil {
+			return diffRefs{}, err
+		}
+
+		// Label the merge base by the ref it's reached from, as a pull request
+		// from b into a would.
+		return diffRefs{from: base, to: b, fromLabel: a, toLabel: b}, nil
+	}
+	if a, b, ok := strings.Cut(rng, ".."); ok {
+		a, b = refOrHEAD(a), refOrHEAD(b)
+		return diffRefs{from: a, to: b, fromLabel: a, toLabel: b}, nil
+	}
+
+	return diffRefs{from: rng, fromLabel: rng, toLabel: "working tree"}, nil
+}
+
+// refOrHEAD returns ref, or "HEAD" if ref is empty, as git does for an omitted
+// end of a range.
+func refOrHEAD(ref string) string {
+	if ref == "" {
+		return "HEAD"
+	}
+	return ref
+}
+
+// advisoryIndexAtRef indexes the advisory data in the repository at dir as of
+// the given git ref.
+func advisoryIndexAtRef(dir, ref string) (*configs.Index[advisoryconfigs.Document], error) {
+	checkoutDir, _, err := git.CheckoutRef(dir, ref)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get advisory data at %s: %w", ref, err)
+	}
+	defer os.RemoveAll(checkoutDir)
+
+	index, err := advisoryconfigs.NewIndex(rwos.DirFS(checkoutDir))
+	if err != nil {
+		return nil, fmt.Errorf("unable to index advisory data at %s: %w", ref, err)
+	}
+
+	return index, nil
+}
+
+func renderAdvisoryDiff(from, to string, d advisory.DiffResult) string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "## Advisory changes: `%s` → `%s`\n\n", from, to)
+
+	if d.IsEmpty() {
+		sb.WriteString("No changes.\n")
+		return sb.String()
+	}
+
+	fmt.Fprintf(&sb, "%d added, %d modified, %d removed\n", len(d.Added), len(d.Modified), len(d.Removed))
+
+	sections := []struct {
+		title   string
+		changes []advisory.AdvisoryChange
+	}{
+		{"Added", d.Added},
+		{"Modified", d.Modified},
+		{"Removed", d.Removed},
+	}
+
+	for _, s := range sections {
+		if len(s.changes) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(&sb, "\n### %s\n\n", s.title)
+		for _, c := range s.changes {
+			fmt.Fprintf(&sb, "- **%s**: %s\n", c.Package, c.Vulnerability)
+			for _, e := range c.RemovedEvents {
+				fmt.Fprintf(&sb, "  - ➖ %s: %s\n", e.Timestamp.UTC().Format("2006-01-02"), renderListItem(e))
+			}
+			for _, e := range c.AddedEvents {
+				fmt.Fprintf(&sb, "  - ➕ %s: %s\n", e.Timestamp.UTC().Format("2006-01-02"), renderListItem(e))
+			}
+		}
+	}
+
+	return sb.String()
+}
+
+func renderAdvisoryDiffSummary(from, to string, d advisory.DiffResult) string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "## Advisory changes: `%s` → `%s`\n\n", from, to)
+
+	summary := d.Summary()
+	if len(summary) == 0 {
+		sb.WriteString("No changes.\n")
+		return sb.String()
+	}
+
+	for _, s := range summary {
+		fmt.Fprintf(&sb, "- %s\n", s)
+	}
+
+	return sb.String()
+}
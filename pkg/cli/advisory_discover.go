@@ -24,13 +24,35 @@ const envVarNameForNVDAPIKey = "WOLFICTL_NVD_API_KEY"
 func AdvisoryDiscover() *cobra.Command {
 	p := &discoverParams{}
 	cmd := &cobra.Command{
-		Use:           "discover",
-		Short:         "search for new potential vulnerabilities and create advisories for them",
+		Use:   "discover",
+		Short: "search for new potential vulnerabilities and create advisories for them",
+		Long: `search for new potential vulnerabilities and create advisories for them
+
+By default, NVD is searched for each package in turn, using CPEs derived from
+the package names (with a few built-in overrides for packages whose NVD product
+names differ).
+
+With --since, only CVEs published within the given time window (e.g. "7d") are
+fetched, in bulk, and matched against all packages. This takes a fraction of
+the API requests of a full search, so it can run frequently to respond to new
+disclosures before scanner databases catch up. Matches are recorded as
+under_investigation advisories.`,
+		Example: `  # Candidate advisories for everything disclosed in the last day
+  wolfictl advisory discover --since 1d`,
 		SilenceErrors: true,
 		Args:          cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			start := time.Now()
 
+			var since time.Time
+			if p.since != "" {
+				window, err := parseAge(p.since)
+				if err != nil {
+					return fmt.Errorf("invalid --since: %w", err)
+				}
+				since = start.Add(-window)
+			}
+
 			packageRepositoryURL := p.packageRepositoryURL
 
 			distroRepoDir := resolveDistroDir(p.distroRepoDir)
@@ -69,7 +91,7 @@ func AdvisoryDiscover() *cobra.Command {
 
 			selectedPackages := getSelectedOrDistroPackages(p.packageName, buildCfgs)
 
-			apiKey := p.resolveNVDAPIKey()
+			apiKey := resolveNVDAPIKey(p.nvdAPIKey)
 
 			err = advisory.Discover(advisory.DiscoverOptions{
 				SelectedPackages:      selectedPackages,
@@ -78,6 +100,7 @@ func AdvisoryDiscover() *cobra.Command {
 				PackageRepositoryURL:  packageRepositoryURL,
 				Arches:                []string{"x86_64", "aarch64"},
 				VulnerabilityDetector: nvdapi.NewDetector(http.DefaultClient, nvdapi.DefaultHost, apiKey),
+				Since:                 since,
 			})
 			if err != nil {
 				return err
@@ -104,6 +127,8 @@ type discoverParams struct {
 	packageRepositoryURL string
 
 	nvdAPIKey string
+
+	since string
 }
 
 func (p *discoverParams) addFlagsTo(cmd *cobra.Command) {
@@ -116,14 +141,20 @@ func (p *discoverParams) addFlagsTo(cmd *cobra.Command) {
 
 	cmd.Flags().StringVarP(&p.packageRepositoryURL, "package-repo-url", "r", "", "URL of the APK package repository")
 
-	cmd.Flags().StringVar(&p.nvdAPIKey, "nvd-api-key", "", fmt.Sprintf("NVD API key (Can also be set via the environment variable '%s'. Using an API key significantly increases the rate limit for API requests. If you need an NVD API key, go to https://nvd.nist.gov/developers/request-an-api-key .)", envVarNameForNVDAPIKey))
+	addNVDAPIKeyFlag(&p.nvdAPIKey, cmd)
+
+	cmd.Flags().StringVar(&p.since, "since", "", "only match CVEs published within this long ago, e.g. 7d or 12h")
+}
+
+func addNVDAPIKeyFlag(val *string, cmd *cobra.Command) {
+	cmd.Flags().StringVar(val, "nvd-api-key", "", fmt.Sprintf("NVD API key (Can also be set via the environment variable '%s'. Using an API key significantly increases the rate limit for API requests. If you need an NVD API key, go to https://nvd.nist.gov/developers/request-an-api-key .)", envVarNameForNVDAPIKey))
 }
 
-func (p *discoverParams) resolveNVDAPIKey() string {
+func resolveNVDAPIKey(cliFlagValue string) string {
 	// TODO: use Viper for this!
 
-	if p.nvdAPIKey != "" {
-		return p.nvdAPIKey
+	if v := cliFlagValue; v != "" {
+		return v
 	}
 
 	keyFromEnv := os.Getenv(envVarNameForNVDAPIKey)
@@ -1,27 +1,133 @@
 package cli
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/wolfi-dev/wolfictl/pkg/advisory"
 	"github.com/wolfi-dev/wolfictl/pkg/configs"
 	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
-	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
 	"github.com/wolfi-dev/wolfictl/pkg/distro"
 )
 
+const (
+	exportFormatCSV        = "csv"
+	exportFormatOSV        = "osv"
+	exportFormatOSVOffline = "osv-offline"
+	exportFormatCSAF       = "csaf"
+	exportFormatTrivy      = "trivy"
+
+	exportFormatMarkdown = advisory.ChangelogFormatMarkdown
+	exportFormatHTML     = advisory.ChangelogFormatHTML
+
+	defaultExportEcosystem = "Wolfi"
+)
+
+var exportFormats = []string{exportFormatCSV, exportFormatOSV, exportFormatOSVOffline, exportFormatCSAF, exportFormatTrivy, exportFormatMarkdown, exportFormatHTML}
+
 func AdvisoryExport() *cobra.Command {
 	p := &exportParams{}
 	cmd := &cobra.Command{
-		Use:           "export",
-		Short:         "Export advisory data (experimental)",
+		Use:   "export",
+		Short: "Export advisory data (experimental)",
+		Long: `Export advisory data (experimental)
+
+By default, the latest event of each advisory is exported as a CSV row. With
+--project, only the advisories of the version streams of that project (e.g.
+postgresql-15 and postgresql-16 for "postgresql") are exported, as recognized
+by their names or listed in the advisories repository's ` + advisory.StreamsFile + ` file.
+
+With more than one --advisories-repo-dir (e.g. the public advisories and a
+private overlay), the merged advisory data is exported. Where more than one
+repository has an advisory for the same package and vulnerability, the one from
+the repository given last is used.
+
+With --format osv, an OSV record (https://ossf.github.io/osv-schema/) is written
+for each vulnerability to its own JSON file in --output-dir, listing the
+packages it affects by purl, with the fixed version as the end of the affected
+range. Packages that aren't affected are left out.
+
+With --format osv-offline, the OSV records are written instead as an offline
+database for osv-scanner, i.e. zipped into osv-scanner/<ecosystem>/all.zip in
+--output-dir. Packages that aren't affected are included in each record's
+database_specific data, along with the justification. To scan with it, point
+osv-scanner's OSV_SCANNER_LOCAL_DB_CACHE_DIRECTORY at --output-dir and use
+its offline mode.
+
+With --format csaf, a single CSAF 2.0 VEX document is written, stating each
+package's status (fixed, known_not_affected, known_affected, or
+under_investigation) for each vulnerability it has an advisory for. Fixed
+packages are identified by their fixed version.
+
+With --format trivy, the advisory data is written as JSON in the bucket layout
+of Trivy's vulnerability database, for loading as a custom vulnerability source,
+so that Trivy picks up fixed versions and not affected packages. The advisories
+bucket and data source are named after the lowercased --ecosystem.
+
+With --format markdown or --format html, a security changelog is written,
+listing the vulnerabilities fixed in each package (and the version with the
+fix), as recorded by the fixed events between --since and --until. It's meant
+for publishing as release notes.`,
+		Example: `  wolfictl advisory export -o advisories.csv
+
+  wolfictl advisory export --format osv --output-dir osv/
+
+  wolfictl advisory export --format osv-offline --output-dir osv-db/
+
+  wolfictl advisory export --format csaf -o vex.json
+
+  wolfictl advisory export --format trivy -o trivy-wolfi.json
+
+  wolfictl advisory export --format markdown --since 2023-05-01 --until 2023-05-08 -o changelog.md`,
 		SilenceErrors: true,
 		Args:          cobra.NoArgs,
 		Hidden:        true,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			switch p.format {
+			case exportFormatCSV, exportFormatCSAF, exportFormatTrivy, exportFormatMarkdown, exportFormatHTML:
+				if p.sign && p.outputLocation == "" {
+					return fmt.Errorf("--sign requires --output")
+				}
+			case exportFormatOSV, exportFormatOSVOffline:
+				if p.outputDir == "" {
+					return fmt.Errorf("--format %s requires --output-dir", p.format)
+				}
+			default:
+				return fmt.Errorf("unsupported export format %q (must be one of: %s)", p.format, strings.Join(exportFormats, ", "))
+			}
+
+			if p.project != "" && p.format != exportFormatCSV {
+				return fmt.Errorf("--project can only be used with --format %s", exportFormatCSV)
+			}
+
+			isChangelog := p.format == exportFormatMarkdown || p.format == exportFormatHTML
+			if !isChangelog && (p.since != "" || p.until != "") {
+				return fmt.Errorf("--since and --until can only be used with --format %s or %s", exportFormatMarkdown, exportFormatHTML)
+			}
+
+			var since, until time.Time
+			var err error
+			if p.since != "" {
+				if since, err = parseDateFlag("--since", p.since); err != nil {
+					return err
+				}
+			}
+			if p.until != "" {
+				if until, err = parseDateFlag("--until", p.until); err != nil {
+					return err
+				}
+			}
+
+			ecosystem := p.ecosystem
+
+			p.advisoriesRepoDirs = resolveAdvisoriesDirs(p.advisoriesRepoDirs)
 			if len(p.advisoriesRepoDirs) == 0 {
 				if p.doNotDetectDistro {
 					return fmt.Errorf("no advisories repo dir specified")
@@ -33,25 +139,63 @@ func AdvisoryExport() *cobra.Command {
 				}
 
 				p.advisoriesRepoDirs = append(p.advisoriesRepoDirs, d.AdvisoriesRepoDir)
+				if ecosystem == "" {
+					ecosystem = d.Name
+				}
 				_, _ = fmt.Fprint(os.Stderr, renderDetectedDistro(d))
 			}
 
-			indices := make([]*configs.Index[advisoryconfigs.Document], 0, len(p.advisoriesRepoDirs))
-			for _, dir := range p.advisoriesRepoDirs {
-				advisoryFsys := rwos.DirFS(dir)
-				index, err := advisoryconfigs.NewIndex(advisoryFsys)
-				if err != nil {
-					return fmt.Errorf("unable to index advisory configs for directory %q: %w", dir, err)
-				}
-
-				indices = append(indices, index)
+			advisoryCfgs, cleanup, err := advisoriesIndexAsOf("", p.advisoriesRepoDirs, p.asOf)
+			if err != nil {
+				return err
 			}
+			defer cleanup()
+
+			indices := []*configs.Index[advisoryconfigs.Document]{advisoryCfgs}
 
-			opts := advisory.ExportOptions{
-				AdvisoryCfgIndices: indices,
+			if ecosystem == "" {
+				ecosystem = defaultExportEcosystem
 			}
 
-			export, err := advisory.Export(opts)
+			var export io.Reader
+			switch p.format {
+			case exportFormatOSV:
+				return exportOSV(indices, ecosystem, p.outputDir, p.sign)
+
+			case exportFormatOSVOffline:
+				return exportOSVOfflineDB(indices, ecosystem, p.outputDir, p.sign)
+
+			case exportFormatCSAF:
+				export, err = exportCSAF(indices, ecosystem, p.publisherNamespace)
+
+			case exportFormatTrivy:
+				export, err = exportTrivy(indices, ecosystem, p.publisherNamespace)
+
+			case exportFormatMarkdown, exportFormatHTML:
+				export, err = advisory.ExportChangelog(advisory.ChangelogOptions{
+					AdvisoryCfgIndices: indices,
+					Since:              since,
+					Until:              until,
+					Format:             p.format,
+				})
+
+			default:
+				opts := advisory.ExportOptions{
+					AdvisoryCfgIndices: indices,
+				}
+				if p.project != "" {
+					streams, err := loadStreamMap(p.advisoriesRepoDirs)
+					if err != nil {
+						return err
+					}
+					opts.Packages, err = projectStreams(advisoryCfgs, streams, p.project)
+					if err != nil {
+						return err
+					}
+				}
+
+				export, err = advisory.Export(opts)
+			}
 			if err != nil {
 				return fmt.Errorf("unable to export advisory data: %w", err)
 			}
@@ -72,6 +216,10 @@ func AdvisoryExport() *cobra.Command {
 				return fmt.Errorf("unable to export data to specified location: %w", err)
 			}
 
+			if p.sign {
+				return signExport(p.outputLocation)
+			}
+
 			return nil
 		},
 	}
@@ -86,12 +234,130 @@ type exportParams struct {
 	advisoriesRepoDirs []string
 
 	outputLocation string
+
+	format             string
+	outputDir          string
+	ecosystem          string
+	publisherNamespace string
+
+	since, until string
+
+	project string
+
+	asOf string
+
+	sign bool
 }
 
 func (p *exportParams) addFlagsTo(cmd *cobra.Command) {
 	addNoDistroDetectionFlag(&p.doNotDetectDistro, cmd)
 
-	cmd.Flags().StringSliceVarP(&p.advisoriesRepoDirs, "advisories-repo-dir", "a", nil, "directory containing an advisories repository")
+	addAdvisoriesDirsFlag(&p.advisoriesRepoDirs, cmd)
 
 	cmd.Flags().StringVarP(&p.outputLocation, "output", "o", "", "output location (default: stdout)")
+	cmd.Flags().StringVar(&p.format, "format", exportFormatCSV, fmt.Sprintf("export format (%s)", strings.Join(exportFormats, ", ")))
+	cmd.Flags().StringVar(&p.outputDir, "output-dir", "", fmt.Sprintf("directory to write OSV records to (used with --format %s or %s)", exportFormatOSV, exportFormatOSVOffline))
+	cmd.Flags().StringVar(&p.ecosystem, "ecosystem", "", fmt.Sprintf("OSV ecosystem of the packages, also used as the CSAF publisher's name and the Trivy data source (default: the detected distro's name, or %s)", defaultExportEcosystem))
+	cmd.Flags().StringVar(&p.publisherNamespace, "publisher-namespace", "https://wolfi.dev", fmt.Sprintf("URL identifying the publisher of the document (used with --format %s or %s)", exportFormatCSAF, exportFormatTrivy))
+
+	cmd.Flags().StringVar(&p.project, "project", "", fmt.Sprintf("only export advisories of the version streams of this project (used with --format %s)", exportFormatCSV))
+	cmd.Flags().StringVar(&p.since, "since", "", fmt.Sprintf("only include fixes recorded at or after this date (YYYY-MM-DD) or RFC3339 timestamp (used with --format %s or %s)", exportFormatMarkdown, exportFormatHTML))
+	cmd.Flags().StringVar(&p.until, "until", "", fmt.Sprintf("only include fixes recorded at or before this date (YYYY-MM-DD) or RFC3339 timestamp (used with --format %s or %s)", exportFormatMarkdown, exportFormatHTML))
+
+	addAsOfFlag(&p.asOf, cmd)
+
+	addSignExportFlag(&p.sign, cmd)
+}
+
+// exportOSV writes an OSV record for each vulnerability to the output
+// directory. If signing, the manifest of all the records is written next to the
+// directory.
+func exportOSV(indices []*configs.Index[advisoryconfigs.Document], ecosystem, outputDir string, sign bool) error {
+	osvs, err := advisory.ExportOSV(advisory.ExportOSVOptions{
+		AdvisoryCfgIndices: indices,
+		Ecosystem:          ecosystem,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to export advisory data: %w", err)
+	}
+
+	paths, err := advisory.WriteOSV(osvs, outputDir)
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(os.Stderr, "Wrote %d OSV records to %s\n", len(paths), outputDir)
+
+	if sign {
+		return signExportFiles(filepath.Clean(outputDir)+advisory.ExportManifestSuffix, paths...)
+	}
+
+	return nil
+}
+
+// exportOSVOfflineDB writes an osv-scanner offline database of the OSV records
+// to the output directory. If signing, the manifest of the database's zip file
+// is written next to it.
+func exportOSVOfflineDB(indices []*configs.Index[advisoryconfigs.Document], ecosystem, outputDir string, sign bool) error {
+	osvs, err := advisory.ExportOSV(advisory.ExportOSVOptions{
+		AdvisoryCfgIndices: indices,
+		Ecosystem:          ecosystem,
+		IncludeNotAffected: true,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to export advisory data: %w", err)
+	}
+
+	p, err := advisory.WriteOSVOfflineDB(osvs, outputDir, ecosystem)
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(os.Stderr, "Wrote %d OSV records to %s\n", len(osvs), p)
+
+	if sign {
+		return signExport(p)
+	}
+
+	return nil
+}
+
+// exportCSAF returns the CSAF VEX document for the advisory data, encoded as
+// JSON.
+func exportTrivy(indices []*configs.Index[advisoryconfigs.Document], ecosystem, publisherNamespace string) (io.Reader, error) {
+	db, err := advisory.ExportTrivy(advisory.ExportTrivyOptions{
+		AdvisoryCfgIndices: indices,
+		Platform:           strings.ToLower(ecosystem),
+		DataSourceName:     ecosystem + " Security Data",
+		DataSourceURL:      publisherNamespace,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := json.Marshal(db)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.NewReader(b), nil
+}
+
+func exportCSAF(indices []*configs.Index[advisoryconfigs.Document], ecosystem, publisherNamespace string) (io.Reader, error) {
+	csaf, err := advisory.ExportCSAF(advisory.ExportCSAFOptions{
+		AdvisoryCfgIndices: indices,
+		ProductNamespace:   strings.ToLower(ecosystem),
+		PublisherName:      ecosystem,
+		PublisherNamespace: publisherNamespace,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	buf := new(bytes.Buffer)
+	enc := json.NewEncoder(buf)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(csaf); err != nil {
+		return nil, err
+	}
+
+	return buf, nil
 }
@@ -0,0 +1,202 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/openvex/go-vex/pkg/vex"
+	"github.com/spf13/cobra"
+	"github.com/wolfi-dev/wolfictl/pkg/advisory"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
+	"github.com/wolfi-dev/wolfictl/pkg/distro"
+	"github.com/wolfi-dev/wolfictl/pkg/index"
+	"gitlab.alpinelinux.org/alpine/go/repository"
+)
+
+func AdvisoryGC() *cobra.Command {
+	p := &gcParams{}
+	cmd := &cobra.Command{
+		Use:   "gc",
+		Short: "archive the advisory data of packages removed from the package repository",
+		Long: fmt.Sprintf(`archive the advisory data of packages removed from the package repository
+
+A package is considered removed if neither it nor any of its subpackages
+appears in the package repository's APKINDEXes. These are the APKINDEX files
+given with --index, or else the APKINDEXes of the distro's package repository,
+for each architecture.
+
+Each removed package's advisory data is archived as by 'wolfictl advisory
+archive', i.e. moved into the %q directory of the advisories repository. Use
+--dry-run to only list the packages that would be archived.`, advisory.ArchiveDir),
+		Example: `  # List packages whose advisories would be archived
+  wolfictl advisory gc --dry-run
+
+  # Archive them, using local copies of the APKINDEXes, and keep their final VEX documents
+  wolfictl advisory gc --index x86_64/APKINDEX.tar.gz --index aarch64/APKINDEX.tar.gz --vex-dir ./vex`,
+		SilenceErrors: true,
+		Args:          cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			archs := p.archs
+			packageRepositoryURL := p.packageRepositoryURL
+			advisoriesRepoDir := resolveAdvisoriesDir(p.advisoriesRepoDir)
+			if advisoriesRepoDir == "" || (len(p.indexes) == 0 && packageRepositoryURL == "") {
+				if p.doNotDetectDistro {
+					return fmt.Errorf("advisories repo dir and/or APKINDEXes were left unspecified")
+				}
+
+				d, err := distro.Detect()
+				if err != nil {
+					return fmt.Errorf("advisories repo dir and/or APKINDEXes were left unspecified, and distro auto-detection failed: %w", err)
+				}
+
+				if advisoriesRepoDir == "" {
+					advisoriesRepoDir = d.AdvisoriesRepoDir
+				}
+				if packageRepositoryURL == "" {
+					packageRepositoryURL = d.APKRepositoryURL
+				}
+				if len(archs) == 0 {
+					archs = d.SupportedArchitectures
+				}
+
+				_, _ = fmt.Fprint(os.Stderr, renderDetectedDistro(d))
+			}
+
+			apkindexes, err := p.loadAPKINDEXes(archs, packageRepositoryURL)
+			if err != nil {
+				return err
+			}
+
+			advisoryCfgs, err := advisoryconfigs.NewIndex(rwos.DirFS(advisoriesRepoDir))
+			if err != nil {
+				return err
+			}
+
+			removed := advisory.RemovedPackages(advisoryCfgs, apkindexes)
+
+			if p.dryRun {
+				for _, name := range removed {
+					fmt.Println(name)
+				}
+				_, _ = fmt.Fprintf(os.Stderr, "Would archive advisories for %d removed packages\n", len(removed))
+				return nil
+			}
+
+			if p.vexDir != "" {
+				if err := os.MkdirAll(p.vexDir, 0o755); err != nil {
+					return err
+				}
+			}
+
+			for _, name := range removed {
+				v, err := advisory.Archive(advisory.ArchiveOptions{
+					AdvisoriesDir:    advisoriesRepoDir,
+					Package:          name,
+					Reason:           p.reason,
+					ProductNamespace: p.productNamespace,
+				})
+				if err != nil {
+					return fmt.Errorf("unable to archive advisories for %s: %w", name, err)
+				}
+
+				if p.vexDir != "" {
+					if err := writeVEXFile(filepath.Join(p.vexDir, name+".openvex.json"), v); err != nil {
+						return err
+					}
+				}
+
+				fmt.Println(name)
+			}
+
+			_, _ = fmt.Fprintf(os.Stderr, "Archived advisories for %d removed packages\n", len(removed))
+			return nil
+		},
+	}
+
+	p.addFlagsTo(cmd)
+	return cmd
+}
+
+type gcParams struct {
+	doNotDetectDistro bool
+
+	advisoriesRepoDir string
+
+	indexes              []string
+	archs                []string
+	packageRepositoryURL string
+
+	reason           string
+	productNamespace string
+	vexDir           string
+	dryRun           bool
+}
+
+func (p *gcParams) addFlagsTo(cmd *cobra.Command) {
+	addNoDistroDetectionFlag(&p.doNotDetectDistro, cmd)
+
+	addAdvisoriesDirFlag(&p.advisoriesRepoDir, cmd)
+
+	cmd.Flags().StringSliceVar(&p.indexes, "index", nil, "path to an APKINDEX.tar.gz file listing the packages that still exist (can be given more than once)")
+	cmd.Flags().StringSliceVar(&p.archs, "arch", nil, "package architectures whose APKINDEXes to fetch, if --index isn't given (default: the distro's supported architectures)")
+	cmd.Flags().StringVarP(&p.packageRepositoryURL, "package-repo-url", "r", "", "URL of the APK package repository, if --index isn't given")
+	cmd.Flags().StringVar(&p.reason, "reason", "removed from the package repository", "reason recorded in the archived advisory documents")
+	cmd.Flags().StringVar(&p.productNamespace, "product-namespace", "wolfi", "namespace used in the package URLs that identify the product in the VEX documents")
+	cmd.Flags().StringVar(&p.vexDir, "vex-dir", "", "directory to write each archived package's final VEX document to")
+	cmd.Flags().BoolVar(&p.dryRun, "dry-run", false, "only list the packages whose advisories would be archived")
+}
+
+// loadAPKINDEXes loads the --index files, or else the package repository's
+// APKINDEX for each architecture. It refuses APKINDEXes without any packages,
+// which would make every package look removed.
+func (p *gcParams) loadAPKINDEXes(archs []string, packageRepositoryURL string) ([]*repository.ApkIndex, error) {
+	var apkindexes []*repository.ApkIndex
+
+	if len(p.indexes) > 0 {
+		for _, path := range p.indexes {
+			idx, err := index.Index("", path)
+			if err != nil {
+				return nil, fmt.Errorf("unable to load APKINDEX %s: %w", path, err)
+			}
+			if len(idx.Packages) == 0 {
+				return nil, fmt.Errorf("APKINDEX %s has no packages", path)
+			}
+			apkindexes = append(apkindexes, idx)
+		}
+
+		return apkindexes, nil
+	}
+
+	if len(archs) == 0 {
+		return nil, fmt.Errorf("no architectures specified (use --arch)")
+	}
+
+	for _, arch := range archs {
+		idx, err := index.Index(arch, packageRepositoryURL)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load APKINDEX for %s: %w", arch, err)
+		}
+		if len(idx.Packages) == 0 {
+			return nil, fmt.Errorf("APKINDEX for %s has no packages", arch)
+		}
+		apkindexes = append(apkindexes, idx)
+	}
+
+	return apkindexes, nil
+}
+
+func writeVEXFile(path string, v *vex.VEX) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("unable to create VEX output file: %w", err)
+	}
+	defer f.Close()
+
+	if err := v.ToJSON(f); err != nil {
+		return fmt.Errorf("unable to write VEX document: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,216 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+	"github.com/wolfi-dev/wolfictl/pkg/advisory"
+	"github.com/wolfi-dev/wolfictl/pkg/advisory/secdb"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	buildconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/build"
+	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
+	"github.com/wolfi-dev/wolfictl/pkg/distro"
+	"github.com/wolfi-dev/wolfictl/pkg/index"
+	"gitlab.alpinelinux.org/alpine/go/repository"
+)
+
+func AdvisoryImport() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "Commands for importing advisory data from other sources",
+	}
+
+	cmd.AddCommand(AdvisoryImportAlpineSecDB())
+
+	return cmd
+}
+
+func AdvisoryImportAlpineSecDB() *cobra.Command {
+	p := &importAlpineSecDBParams{}
+	cmd := &cobra.Command{
+		Use:   "alpine-secdb <url-or-path>",
+		Short: "create advisories from Alpine's security database (secdb)",
+		Long: `create advisories from Alpine's security database (secdb)
+
+The secfixes of each package that's in both Alpine's secdb and the distro are
+recorded as advisories, except for vulnerabilities the package already has an
+advisory for (under the same ID or as an alias).
+
+Alpine's release numbers (the "-rN" of a version) are its own, so a
+vulnerability that Alpine fixed in some version is recorded as fixed in the
+earliest version of the package published in the distro's package repository
+that's no older than Alpine's version. If there's no such version yet, the
+vulnerability is recorded as under_investigation. Vulnerabilities that Alpine
+lists as not affecting the package are recorded as not_affected.
+
+Use --dry-run to review the advisories before creating them.`,
+		Example: `  wolfictl advisory import alpine-secdb https://secdb.alpinelinux.org/edge/main.json --dry-run
+
+  wolfictl advisory import alpine-secdb ./community.json -p py3-foo`,
+		SilenceErrors: true,
+		Args:          cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			archs := p.archs
+			packageRepositoryURL := p.packageRepositoryURL
+			distroRepoDir := resolveDistroDir(p.distroRepoDir)
+			advisoriesRepoDir := resolveAdvisoriesDir(p.advisoriesRepoDir)
+			if distroRepoDir == "" || advisoriesRepoDir == "" {
+				if p.doNotDetectDistro {
+					return fmt.Errorf("distro repo dir and/or advisories repo dir was left unspecified")
+				}
+
+				d, err := distro.Detect()
+				if err != nil {
+					return fmt.Errorf("distro repo dir and/or advisories repo dir was left unspecified, and distro auto-detection failed: %w", err)
+				}
+
+				if len(archs) == 0 {
+					archs = d.SupportedArchitectures
+				}
+
+				if packageRepositoryURL == "" {
+					packageRepositoryURL = d.APKRepositoryURL
+				}
+
+				distroRepoDir = d.DistroRepoDir
+				advisoriesRepoDir = d.AdvisoriesRepoDir
+				_, _ = fmt.Fprint(os.Stderr, renderDetectedDistro(d))
+			}
+
+			db, err := readAlpineSecDB(args[0])
+			if err != nil {
+				return err
+			}
+
+			advisoryCfgs, err := advisoryconfigs.NewIndex(rwos.DirFS(advisoriesRepoDir))
+			if err != nil {
+				return err
+			}
+
+			buildCfgs, err := buildconfigs.NewIndex(rwos.DirFS(distroRepoDir))
+			if err != nil {
+				return fmt.Errorf("unable to select packages: %w", err)
+			}
+
+			packages := getSelectedOrDistroPackages(p.packageName, buildCfgs)
+
+			var apkindexes []*repository.ApkIndex
+			for _, arch := range archs {
+				idx, err := index.Index(arch, packageRepositoryURL)
+				if err != nil {
+					return fmt.Errorf("unable to load APKINDEX for %s: %w", arch, err)
+				}
+				apkindexes = append(apkindexes, idx)
+			}
+
+			reqs, err := advisory.ImportAlpineSecDB(advisory.ImportAlpineSecDBOptions{
+				AdvisoryCfgs:      advisoryCfgs,
+				DB:                db,
+				Packages:          packages,
+				PublishedVersions: publishedVersionsFunc(apkindexes),
+				DryRun:            p.dryRun,
+			})
+			if err != nil {
+				return err
+			}
+
+			for _, req := range reqs {
+				fmt.Printf("%s: %s: %s\n", req.Package, req.Vulnerability, renderListItem(advisoryconfigs.Entry{
+					Status:        req.Status,
+					Justification: req.Justification,
+					FixedVersion:  req.FixedVersion,
+				}))
+			}
+
+			verb := "Created"
+			if p.dryRun {
+				verb = "Would create"
+			}
+			_, _ = fmt.Fprintf(os.Stderr, "%s %d advisories from %s\n", verb, len(reqs), args[0])
+
+			return nil
+		},
+	}
+
+	p.addFlagsTo(cmd)
+	return cmd
+}
+
+type importAlpineSecDBParams struct {
+	doNotDetectDistro bool
+
+	packageName string
+
+	distroRepoDir, advisoriesRepoDir string
+	archs                            []string
+	packageRepositoryURL             string
+
+	dryRun bool
+}
+
+func (p *importAlpineSecDBParams) addFlagsTo(cmd *cobra.Command) {
+	addNoDistroDetectionFlag(&p.doNotDetectDistro, cmd)
+
+	addPackageFlag(&p.packageName, cmd)
+
+	addDistroDirFlag(&p.distroRepoDir, cmd)
+	addAdvisoriesDirFlag(&p.advisoriesRepoDir, cmd)
+	cmd.Flags().StringSliceVar(&p.archs, "arch", []string{"x86_64", "aarch64"}, "package architectures to find published versions for")
+	cmd.Flags().StringVarP(&p.packageRepositoryURL, "package-repo-url", "r", "", "URL of the APK package repository")
+
+	cmd.Flags().BoolVar(&p.dryRun, "dry-run", false, "only list the advisories that would be created, without creating them")
+}
+
+// readAlpineSecDB reads a secdb from a URL or a local file.
+func readAlpineSecDB(location string) (*secdb.Database, error) {
+	var r io.Reader
+	if strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://") {
+		resp, err := http.Get(location) //nolint:gosec
+		if err != nil {
+			return nil, fmt.Errorf("unable to download secdb: %w", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("unable to download secdb: GET %s: %d", location, resp.StatusCode)
+		}
+		r = resp.Body
+	} else {
+		f, err := os.Open(location)
+		if err != nil {
+			return nil, fmt.Errorf("unable to open secdb: %w", err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	db := &secdb.Database{}
+	if err := json.NewDecoder(r).Decode(db); err != nil {
+		return nil, fmt.Errorf("unable to decode secdb %s: %w", location, err)
+	}
+
+	return db, nil
+}
+
+// publishedVersionsFunc returns a function that lists the versions of a
+// package (or of its subpackages) found in the given APKINDEXes.
+func publishedVersionsFunc(apkindexes []*repository.ApkIndex) func(packageName string) []string {
+	return func(packageName string) []string {
+		var versions []string
+		for _, apkindex := range apkindexes {
+			for _, pkg := range apkindex.Packages {
+				if pkg.Name == packageName || pkg.Origin == packageName {
+					versions = append(versions, pkg.Version)
+				}
+			}
+		}
+
+		return lo.Uniq(versions)
+	}
+}
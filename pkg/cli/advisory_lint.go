@@ -0,0 +1,195 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"chainguard.dev/melange/pkg/build"
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+	"github.com/wolfi-dev/wolfictl/pkg/advisory"
+	"github.com/wolfi-dev/wolfictl/pkg/configs"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	buildconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/build"
+	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
+	"github.com/wolfi-dev/wolfictl/pkg/distro"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	lintOutputText   = "text"
+	lintOutputJSON   = "json"
+	lintOutputGitHub = "github"
+)
+
+var lintOutputFormats = []string{lintOutputText, lintOutputJSON, lintOutputGitHub}
+
+func AdvisoryLint() *cobra.Command {
+	p := &lintParams{}
+	cmd := &cobra.Command{
+		Use:   "lint",
+		Short: "check the advisories data set for questionable triage",
+		Long: fmt.Sprintf(`check the advisories data set for questionable triage
+
+Unlike 'wolfictl advisory validate', which checks that advisory documents are
+well-formed, this command checks that the recorded triage makes sense. The
+rules are:
+
+%s
+The command fails if any finding has "error" severity. With --output github,
+findings are printed as GitHub Actions workflow commands, so that they show up
+as annotations on the workflow run.`, renderLintRules()),
+		Example: `  # Lint a single package's advisories
+  wolfictl advisory lint -p openssl
+
+  # Annotate a pull request in a GitHub Actions workflow
+  wolfictl advisory lint --output github`,
+		SilenceErrors: true,
+		Args:          cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !slices.Contains(lintOutputFormats, p.outputFormat) {
+				return fmt.Errorf("invalid output format %q, must be one of [%s]", p.outputFormat, strings.Join(lintOutputFormats, ", "))
+			}
+
+			distroRepoDir := resolveDistroDir(p.distroRepoDir)
+			advisoriesRepoDir := resolveAdvisoriesDir(p.advisoriesRepoDir)
+			if distroRepoDir == "" || advisoriesRepoDir == "" {
+				if p.doNotDetectDistro {
+					return fmt.Errorf("distro repo dir and/or advisories repo dir was left unspecified")
+				}
+
+				d, err := distro.Detect()
+				if err != nil {
+					return fmt.Errorf("distro repo dir and/or advisories repo dir was left unspecified, and distro auto-detection failed: %w", err)
+				}
+
+				distroRepoDir = d.DistroRepoDir
+				advisoriesRepoDir = d.AdvisoriesRepoDir
+				_, _ = fmt.Fprint(os.Stderr, renderDetectedDistro(d))
+			}
+
+			advisoryFsys := rwos.DirFS(advisoriesRepoDir)
+			var advisoryCfgs *configs.Index[advisoryconfigs.Document]
+			var err error
+			if p.packageName != "" {
+				advisoryCfgs, err = advisoryconfigs.NewIndexFromPaths(advisoryFsys, fmt.Sprintf("%s.advisories.yaml", p.packageName))
+			} else {
+				advisoryCfgs, err = advisoryconfigs.NewIndex(advisoryFsys)
+			}
+			if err != nil {
+				return err
+			}
+
+			buildCfgs, err := buildconfigs.NewIndex(rwos.DirFS(distroRepoDir))
+			if err != nil {
+				return fmt.Errorf("unable to load package definitions: %w", err)
+			}
+			packageNames := lo.Map(buildCfgs.Select().Configurations(), func(cfg build.Configuration, _ int) string {
+				return cfg.Package.Name
+			})
+
+			findings := advisory.Lint(advisory.LintOptions{
+				AdvisoryCfgs: advisoryCfgs,
+				PackageNames: packageNames,
+			})
+
+			switch p.outputFormat {
+			case lintOutputJSON:
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				if findings == nil {
+					findings = []advisory.LintFinding{}
+				}
+				if err := enc.Encode(findings); err != nil {
+					return err
+				}
+
+			case lintOutputGitHub:
+				for _, f := range findings {
+					fmt.Println(renderLintFindingGitHub(advisoriesRepoDir, f))
+				}
+
+			default:
+				for _, f := range findings {
+					fmt.Println(renderLintFinding(f))
+				}
+			}
+
+			errs := lo.CountBy(findings, func(f advisory.LintFinding) bool {
+				return f.Severity == advisory.LintError
+			})
+			if errs > 0 {
+				return fmt.Errorf("found %d lint errors (and %d warnings)", errs, len(findings)-errs)
+			}
+
+			if p.outputFormat == lintOutputText {
+				_, _ = fmt.Fprintf(os.Stderr, "Found no lint errors (%d warnings)\n", len(findings))
+			}
+
+			return nil
+		},
+	}
+
+	p.addFlagsTo(cmd)
+	return cmd
+}
+
+type lintParams struct {
+	doNotDetectDistro bool
+
+	distroRepoDir, advisoriesRepoDir string
+
+	packageName  string
+	outputFormat string
+}
+
+func (p *lintParams) addFlagsTo(cmd *cobra.Command) {
+	addNoDistroDetectionFlag(&p.doNotDetectDistro, cmd)
+
+	addDistroDirFlag(&p.distroRepoDir, cmd)
+	addAdvisoriesDirFlag(&p.advisoriesRepoDir, cmd)
+
+	addPackageFlag(&p.packageName, cmd)
+	cmd.Flags().StringVarP(&p.outputFormat, "output", "o", lintOutputText, fmt.Sprintf("output format (%s)", strings.Join(lintOutputFormats, ", ")))
+}
+
+func renderLintRules() string {
+	var b strings.Builder
+	for _, rule := range append([]advisory.LintRule{advisory.LintRuleUnknownPackage}, advisory.LintRules...) {
+		fmt.Fprintf(&b, "  %s (%s): %s\n", rule.ID, rule.Severity, rule.Description)
+	}
+	return b.String()
+}
+
+func renderLintFinding(f advisory.LintFinding) string {
+	subject := f.Package
+	if f.Vulnerability != "" {
+		subject += ": " + f.Vulnerability
+	}
+
+	return fmt.Sprintf("%s: %s [%s %s]", subject, f.Message, f.Severity, f.Rule)
+}
+
+// renderLintFindingGitHub renders the finding as a GitHub Actions workflow
+// command that annotates the package's advisory document.
+//
+// See https://docs.github.com/en/actions/using-workflows/workflow-commands-for-github-actions.
+func renderLintFindingGitHub(advisoriesRepoDir string, f advisory.LintFinding) string {
+	file := filepath.Join(advisoriesRepoDir, fmt.Sprintf("%s.advisories.yaml", f.Package))
+
+	title := f.Rule
+	if f.Vulnerability != "" {
+		title = fmt.Sprintf("%s: %s", f.Vulnerability, f.Rule)
+	}
+
+	return fmt.Sprintf(
+		"::%s file=%s,title=%s::%s",
+		f.Severity,
+		escapeGitHubProperty(file),
+		escapeGitHubProperty(title),
+		escapeGitHubData(f.Message),
+	)
+}
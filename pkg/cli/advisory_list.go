@@ -1,28 +1,80 @@
 package cli
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
 	"os"
-	"sort"
+	"strings"
+	"time"
 
 	"github.com/openvex/go-vex/pkg/vex"
+	"github.com/samber/lo"
 	"github.com/spf13/cobra"
 	"github.com/wolfi-dev/wolfictl/pkg/advisory"
 	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
-	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
 	"github.com/wolfi-dev/wolfictl/pkg/distro"
+	"github.com/wolfi-dev/wolfictl/pkg/vuln"
+	"golang.org/x/exp/slices"
 )
 
 func AdvisoryList() *cobra.Command {
 	p := &listParams{}
 	cmd := &cobra.Command{
-		Use:           "list",
-		Short:         "list advisories for specific packages or across all of Wolfi",
+		Use:         "list",
+		Annotations: pagerAnnotations,
+		Short:       "list advisories for specific packages or across all of Wolfi",
+		Long: `list advisories for specific packages or across all of Wolfi
+
+Advisories can be filtered by package, vulnerability, and their latest event's
+status and timestamp. With --aliases, advisories recorded under any alias of
+the given vulnerability (as known to OSV) are listed too.
+
+With more than one --advisories-repo-dir (e.g. the public advisories and a
+private overlay), the merged advisory data is listed. Where more than one
+repository has an advisory for the same package and vulnerability, the one from
+the repository given last is used.
+
+With --advisories-url, the advisory data published at that URL (see 'wolfictl
+advisory publish-index') is listed instead of needing a local checkout, or
+beneath any given advisories repositories. Documents are fetched only when
+they've changed since they were last cached.
+
+Packages that are version streams of one project (e.g. postgresql-15 and
+postgresql-16) can be queried together with --project, and with --by-project,
+each vulnerability's advisories across a project's streams are consolidated
+into one answer, noting any streams without an advisory. Streams are recognized
+by their names ending in a version, or are listed per project in the
+advisories repository's ` + advisory.StreamsFile + ` file.
+
+With --purl (e.g. pkg:apk/wolfi/foo@1.2.3-r0), the package's advisories are
+listed as they apply to that exact version: a vulnerability fixed in a later
+version is listed as affected, with the version to upgrade to. The purl's
+namespace isn't checked against the advisories repository.`,
+		Example: `  # All packages still under investigation for a vulnerability, under any of its IDs
+  wolfictl advisory list -V GHSA-2h5h-59f5-c5x9 --aliases --status under_investigation
+
+  # Advisories resolved as fixed in May 2023, as JSON
+  wolfictl advisory list --status fixed --since 2023-05-01 --until 2023-06-01 --json
+
+  # The status of a vulnerability across all postgresql streams
+  wolfictl advisory list --project postgresql -V CVE-2023-5869 --by-project
+
+  # Public advisories merged with a private overlay
+  wolfictl advisory list -a ../advisories -a ../private-advisories
+
+  # What's known about an exact package version, as from an SBOM
+  wolfictl advisory list --purl pkg:apk/wolfi/glibc@2.38-r1 --unresolved
+
+  # Published advisory data, without a local checkout
+  wolfictl advisory list -p glibc --advisories-url https://example.com/advisories`,
 		SilenceErrors: true,
 		Args:          cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			advisoriesRepoDir := resolveAdvisoriesDir(p.advisoriesRepoDir)
-			if advisoriesRepoDir == "" {
+			advisoriesURL := resolveAdvisoriesURL(p.advisoriesURL)
+			advisoriesRepoDirs := resolveAdvisoriesDirs(p.advisoriesRepoDirs)
+			if len(advisoriesRepoDirs) == 0 && advisoriesURL == "" {
 				if p.doNotDetectDistro {
 					return fmt.Errorf("no advisories repo dir specified")
 				}
@@ -32,61 +84,73 @@ func AdvisoryList() *cobra.Command {
 					return fmt.Errorf("no advisories repo dir specified, and distro auto-detection failed: %w", err)
 				}
 
-				advisoriesRepoDir = d.AdvisoriesRepoDir
+				advisoriesRepoDirs = []string{d.AdvisoriesRepoDir}
 				_, _ = fmt.Fprint(os.Stderr, renderDetectedDistro(d))
 			}
 
-			advisoriesFsys := rwos.DirFS(advisoriesRepoDir)
-			advisoryCfgs, err := advisoryconfigs.NewIndex(advisoriesFsys)
+			advisoryCfgs, cleanup, err := advisoriesIndexAsOf(advisoriesURL, advisoriesRepoDirs, p.asOf)
 			if err != nil {
 				return err
 			}
+			defer cleanup()
 
-			var cfgs []advisoryconfigs.Document
-			if pkg := p.packageName; pkg != "" {
-				cfgs = advisoryCfgs.Select().WhereName(pkg).Configurations()
-			} else {
-				cfgs = advisoryCfgs.Select().Configurations()
+			filter, err := p.filter(cmd.Context())
+			if err != nil {
+				return err
 			}
 
-			var output string
+			streams, err := loadStreamMap(advisoriesRepoDirs)
+			if err != nil {
+				return err
+			}
 
-			for _, cfg := range cfgs {
-				for vuln, entries := range cfg.Advisories {
-					if len(entries) == 0 {
-						// nothing to show
-						continue
-					}
+			if p.project != "" {
+				if p.packageName != "" || p.purl != "" {
+					return fmt.Errorf("--project cannot be used with --package or --purl")
+				}
+				filter.Packages, err = projectStreams(advisoryCfgs, streams, p.project)
+				if err != nil {
+					return err
+				}
+			}
 
-					if p.vuln != "" && p.vuln != vuln {
-						// user specified a particular different vulnerability
-						continue
-					}
+			listed := advisory.List(advisoryCfgs, filter)
 
-					latest := advisory.Latest(entries)
+			if p.byProject {
+				if p.history {
+					return fmt.Errorf("--by-project cannot be used with --history")
+				}
 
-					if p.unresolved && latest.Status != vex.StatusAffected && latest.Status != vex.StatusUnderInvestigation {
-						// user only wants to see unresolved advisories
-						continue
-					}
+				grouped := advisory.GroupByProject(advisoryCfgs, listed, streams)
+				if p.outputJSON {
+					enc := json.NewEncoder(os.Stdout)
+					enc.SetIndent("", "  ")
+					return enc.Encode(projectListJSON(grouped))
+				}
 
-					if p.history {
-						sort.SliceStable(entries, func(i, j int) bool {
-							return entries[i].Timestamp.Before(entries[j].Timestamp)
-						})
+				for _, a := range grouped {
+					fmt.Printf("%s: %s: %s\n", a.Project, a.Vulnerability, renderProjectAdvisory(a))
+				}
+				return nil
+			}
 
-						for _, item := range entries {
-							timestamp := item.Timestamp
-							statusDescription := renderListItem(item)
-							output += fmt.Sprintf("%s: %s: %s @ %s\n", cfg.Package.Name, vuln, statusDescription, timestamp)
-						}
+			if p.outputJSON {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(listJSON(listed, p.history))
+			}
 
-						continue
+			var output string
+			for _, a := range listed {
+				if p.history {
+					for _, item := range a.Entries {
+						output += fmt.Sprintf("%s: %s: %s @ %s\n", a.Package, a.Vulnerability, renderListItem(item), item.Timestamp)
 					}
 
-					statusDescription := renderListItem(*latest)
-					output += fmt.Sprintf("%s: %s: %s\n", cfg.Package.Name, vuln, statusDescription)
+					continue
 				}
+
+				output += fmt.Sprintf("%s: %s: %s\n", a.Package, a.Vulnerability, renderListItem(a.Latest))
 			}
 
 			fmt.Print(output)
@@ -101,24 +165,196 @@ func AdvisoryList() *cobra.Command {
 type listParams struct {
 	doNotDetectDistro bool
 
-	advisoriesRepoDir string
+	advisoriesRepoDirs []string
+	advisoriesURL      string
 
 	packageName string
+	purl        string
+	project     string
+	byProject   bool
 	vuln        string
+	aliases     bool
+	statuses    []string
+	since       string
+	until       string
 	history     bool
 	unresolved  bool
+	asOf        string
+	outputJSON  bool
 }
 
 func (p *listParams) addFlagsTo(cmd *cobra.Command) {
 	addNoDistroDetectionFlag(&p.doNotDetectDistro, cmd)
 
-	addAdvisoriesDirFlag(&p.advisoriesRepoDir, cmd)
+	addAdvisoriesDirsFlag(&p.advisoriesRepoDirs, cmd)
+	addAdvisoriesURLFlag(&p.advisoriesURL, cmd)
 
 	addPackageFlag(&p.packageName, cmd)
 	addVulnFlag(&p.vuln, cmd)
 
+	cmd.Flags().StringVar(&p.purl, "purl", "", "only show advisories of the package in this purl (e.g. pkg:apk/wolfi/foo@1.2.3-r0), as they apply to its version")
+	cmd.Flags().StringVar(&p.project, "project", "", "only show advisories of the version streams of this project (e.g. \"postgresql\" for postgresql-15, postgresql-16, etc.)")
+	cmd.Flags().BoolVar(&p.byProject, "by-project", false, "consolidate each vulnerability's advisories across the version streams of each project")
+	cmd.Flags().BoolVar(&p.aliases, "aliases", false, "also list advisories recorded under aliases of the --vuln ID, as looked up in OSV")
+	cmd.Flags().StringSliceVar(&p.statuses, "status", nil, fmt.Sprintf("only show advisories whose latest status is one of these (%s)", strings.Join(vex.Statuses(), ", ")))
+	cmd.Flags().StringVar(&p.since, "since", "", "only show advisories whose latest event is at or after this date (YYYY-MM-DD) or RFC3339 timestamp")
+	cmd.Flags().StringVar(&p.until, "until", "", "only show advisories whose latest event is at or before this date (YYYY-MM-DD) or RFC3339 timestamp")
 	cmd.Flags().BoolVar(&p.history, "history", false, "show full history for advisories")
 	cmd.Flags().BoolVar(&p.unresolved, "unresolved", false, fmt.Sprintf("only show advisories whose latest status is %s or %s", vex.StatusAffected, vex.StatusUnderInvestigation))
+	addAsOfFlag(&p.asOf, cmd)
+	cmd.Flags().BoolVar(&p.outputJSON, "json", false, "print the advisories as JSON")
+}
+
+func (p *listParams) filter(ctx context.Context) (advisory.ListFilter, error) {
+	filter := advisory.ListFilter{
+		Package:    p.packageName,
+		Unresolved: p.unresolved,
+	}
+
+	if p.purl != "" {
+		if p.packageName != "" {
+			return advisory.ListFilter{}, fmt.Errorf("--purl and --package cannot be used together")
+		}
+
+		purl, err := advisory.ParsePackageURL(p.purl)
+		if err != nil {
+			return advisory.ListFilter{}, err
+		}
+		filter.Package = purl.Name
+		filter.Version = purl.Version
+	}
+
+	for _, status := range p.statuses {
+		if !slices.Contains(vex.Statuses(), status) {
+			return advisory.ListFilter{}, fmt.Errorf("status is %q but must be one of [%s]", status, strings.Join(vex.Statuses(), ", "))
+		}
+		filter.Statuses = append(filter.Statuses, vex.Status(status))
+	}
+
+	var err error
+	if p.since != "" {
+		if filter.Since, err = parseDateFlag("--since", p.since); err != nil {
+			return advisory.ListFilter{}, err
+		}
+	}
+	if p.until != "" {
+		if filter.Until, err = parseDateFlag("--until", p.until); err != nil {
+			return advisory.ListFilter{}, err
+		}
+	}
+
+	if p.vuln != "" {
+		filter.Vulnerabilities = []string{p.vuln}
+
+		if p.aliases {
+			aliases, err := vuln.NewAliasFinder(http.DefaultClient).Aliases(ctx, p.vuln)
+			if err != nil {
+				return advisory.ListFilter{}, err
+			}
+			filter.Vulnerabilities = append(filter.Vulnerabilities, aliases...)
+		}
+	} else if p.aliases {
+		return advisory.ListFilter{}, fmt.Errorf("--aliases requires --vuln")
+	}
+
+	return filter, nil
+}
+
+// listedAdvisoryJSON is the JSON output for an advisory listed by 'advisory
+// list'.
+type listedAdvisoryJSON struct {
+	Package       string            `json:"package"`
+	Vulnerability string            `json:"vulnerability"`
+	Latest        listedEventJSON   `json:"latest"`
+	History       []listedEventJSON `json:"history,omitempty"`
+}
+
+type listedEventJSON struct {
+	Timestamp     time.Time `json:"timestamp"`
+	Status        string    `json:"status"`
+	FixedVersion  string    `json:"fixedVersion,omitempty"`
+	Justification string    `json:"justification,omitempty"`
+	Impact        string    `json:"impact,omitempty"`
+	Action        string    `json:"action,omitempty"`
+
+	References []referenceJSON `json:"references,omitempty"`
+}
+
+type referenceJSON struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+func listedEvent(e advisoryconfigs.Entry) listedEventJSON {
+	return listedEventJSON{
+		Timestamp:     e.Timestamp,
+		Status:        string(e.Status),
+		FixedVersion:  e.FixedVersion,
+		Justification: string(e.Justification),
+		Impact:        e.ImpactStatement,
+		Action:        e.ActionStatement,
+		References: lo.Map(e.References, func(r advisoryconfigs.Reference, _ int) referenceJSON {
+			return referenceJSON{Type: string(r.Type), URL: r.URL}
+		}),
+	}
+}
+
+func listJSON(listed []advisory.ListedAdvisory, history bool) []listedAdvisoryJSON {
+	out := make([]listedAdvisoryJSON, 0, len(listed))
+	for _, a := range listed {
+		item := listedAdvisoryJSON{
+			Package:       a.Package,
+			Vulnerability: a.Vulnerability,
+			Latest:        listedEvent(a.Latest),
+		}
+		if history {
+			item.History = lo.Map(a.Entries, func(e advisoryconfigs.Entry, _ int) listedEventJSON {
+				return listedEvent(e)
+			})
+		}
+		out = append(out, item)
+	}
+
+	return out
+}
+
+// projectAdvisoryJSON is the JSON output for a vulnerability's consolidated
+// advisories across a project's streams, listed by 'advisory list --by-project'.
+type projectAdvisoryJSON struct {
+	Project       string               `json:"project"`
+	Vulnerability string               `json:"vulnerability"`
+	Resolved      bool                 `json:"resolved"`
+	Streams       []listedAdvisoryJSON `json:"streams"`
+	Missing       []string             `json:"missing,omitempty"`
+}
+
+func projectListJSON(grouped []advisory.ProjectAdvisory) []projectAdvisoryJSON {
+	out := make([]projectAdvisoryJSON, 0, len(grouped))
+	for _, a := range grouped {
+		out = append(out, projectAdvisoryJSON{
+			Project:       a.Project,
+			Vulnerability: a.Vulnerability,
+			Resolved:      a.Resolved(),
+			Streams:       listJSON(a.Streams, false),
+			Missing:       a.Missing,
+		})
+	}
+
+	return out
+}
+
+// renderProjectAdvisory renders the latest status of each of a project's
+// streams, e.g. "postgresql-15: fixed (15.5-r0); postgresql-16: no advisory".
+func renderProjectAdvisory(a advisory.ProjectAdvisory) string {
+	var parts []string
+	for _, s := range a.Streams {
+		parts = append(parts, fmt.Sprintf("%s: %s", s.Package, renderListItem(s.Latest)))
+	}
+	for _, m := range a.Missing {
+		parts = append(parts, fmt.Sprintf("%s: no advisory", m))
+	}
+
+	return strings.Join(parts, "; ")
 }
 
 func renderListItem(entry advisoryconfigs.Entry) string {
@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/wolfi-dev/wolfictl/pkg/advisory"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	"github.com/wolfi-dev/wolfictl/pkg/yamledit"
+	"gopkg.in/yaml.v3"
+)
+
+func AdvisoryMerge() *cobra.Command {
+	p := &mergeParams{}
+	cmd := &cobra.Command{
+		Use:   "merge <base> <ours> <theirs>",
+		Short: "merge two edited versions of an advisory document",
+		Long: `merge two edited versions of an advisory document
+
+Given the common ancestor (base) of an advisory document and two versions of it
+edited independently (ours and theirs), this command merges the advisory events
+of both versions: events added on either side are kept, events removed on
+either side are dropped, and each advisory's events are sorted by timestamp.
+Aliases are merged the same way.
+
+The result is written to <ours> (or to --output), so this command can be used
+as a git merge driver, which avoids conflicts when two branches add events to
+the same advisory document:
+
+  git config merge.wolfictl-advisory.driver "wolfictl advisory merge %O %A %B"
+  echo '*.advisories.yaml merge=wolfictl-advisory' >> .gitattributes
+
+If the versions can't be merged automatically (e.g. both sides archived the
+package differently), the command fails and leaves <ours> untouched, and git
+reports a conflict as usual.`,
+		Example:       `  wolfictl advisory merge base.advisories.yaml foo.advisories.yaml other.advisories.yaml`,
+		SilenceErrors: true,
+		Args:          cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			docs := make([]advisoryconfigs.Document, 0, len(args))
+			for _, path := range args {
+				doc, err := decodeAdvisoryDocumentFile(path)
+				if err != nil {
+					return err
+				}
+				docs = append(docs, *doc)
+			}
+
+			merged, err := advisory.Merge(docs[0], docs[1], docs[2])
+			if err != nil {
+				return fmt.Errorf("unable to merge %s: %w", args[1], err)
+			}
+
+			node := &yaml.Node{}
+			err = node.Encode(merged)
+			if err != nil {
+				return fmt.Errorf("unable to encode merged document: %w", err)
+			}
+
+			buf := new(bytes.Buffer)
+			err = yamledit.Encode(buf, node)
+			if err != nil {
+				return err
+			}
+
+			output := p.outputPath
+			if output == "" {
+				output = args[1]
+			}
+
+			return os.WriteFile(output, buf.Bytes(), 0o644) //nolint:gosec
+		},
+	}
+
+	p.addFlagsTo(cmd)
+	return cmd
+}
+
+type mergeParams struct {
+	outputPath string
+}
+
+func (p *mergeParams) addFlagsTo(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&p.outputPath, "output", "o", "", "path to write the merged document to (default: the <ours> path)")
+}
+
+func decodeAdvisoryDocumentFile(path string) (*advisoryconfigs.Document, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	doc, err := advisoryconfigs.DecodeDocument(f)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode %s: %w", path, err)
+	}
+
+	return doc, nil
+}
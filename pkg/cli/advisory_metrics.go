@@ -0,0 +1,223 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/wolfi-dev/wolfictl/pkg/advisory"
+	"github.com/wolfi-dev/wolfictl/pkg/scan"
+)
+
+func AdvisoryMetrics() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "metrics",
+		Short: "Commands for exposing metrics of advisory triage health",
+	}
+
+	cmd.AddCommand(AdvisoryMetricsServe())
+	return cmd
+}
+
+func AdvisoryMetricsServe() *cobra.Command {
+	p := &metricsServeParams{}
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "serve Prometheus metrics of advisory triage health",
+		Long: fmt.Sprintf(`serve Prometheus metrics of advisory triage health
+
+Metrics are served over HTTP at /metrics, in the Prometheus text format, so
+triage health can be graphed (e.g. in Grafana):
+
+  wolfictl_advisories{status}                  advisories by latest status
+  wolfictl_advisory_open_investigations{severity}
+                                               advisories under investigation
+  wolfictl_advisories_fixed_total              advisories that are fixed
+  wolfictl_advisories_fixed_last_day           advisories fixed in the last 24h
+  wolfictl_advisory_sla_breaches{severity}     unresolved advisories past due
+
+Advisories fixed per day over any period can be graphed with e.g.
+increase(wolfictl_advisories_fixed_total[1d]).
+
+The advisory data is read from the advisories repo dirs (-a, which may be
+given more than once) and/or the published data at --advisories-url, and is
+re-read every --refresh interval. If a refresh fails, the last metrics are
+still served.
+
+SLAs are read from the %s file in the last advisories repo dir (or
+the file given with --policy), as for 'wolfictl advisory sla'. Severities are
+taken from the vulnerability database used by 'wolfictl scan'. Only public
+advisory data is considered.`, advisory.SLAPolicyFile),
+		Example: `  wolfictl advisory metrics serve --listen :9090
+
+  wolfictl advisory metrics serve --advisories-url https://example.com/advisories --refresh 15m`,
+		SilenceErrors: true,
+		Args:          cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if p.refresh <= 0 {
+				return fmt.Errorf("--refresh must be positive")
+			}
+
+			advisoriesURL := resolveAdvisoriesURL(p.advisoriesURL)
+			advisoriesRepoDirs := resolveAdvisoriesDirs(p.advisoriesRepoDirs)
+			if len(advisoriesRepoDirs) == 0 && advisoriesURL == "" {
+				return fmt.Errorf("no advisories repo dir or advisories URL specified")
+			}
+
+			policy, err := p.policy(advisoriesRepoDirs)
+			if err != nil {
+				return err
+			}
+
+			s := &metricsServer{
+				compute: func() (advisory.Metrics, error) {
+					return computeAdvisoryMetrics(advisoriesURL, advisoriesRepoDirs, policy)
+				},
+			}
+			if err := s.refresh(); err != nil {
+				return err
+			}
+
+			ctx, cancel := context.WithCancel(cmd.Context())
+			defer cancel()
+			go s.refreshEvery(ctx, p.refresh)
+
+			mux := http.NewServeMux()
+			mux.Handle("/metrics", s)
+			server := &http.Server{
+				Addr:              p.listen,
+				Handler:           mux,
+				ReadHeaderTimeout: 10 * time.Second,
+			}
+
+			_, _ = fmt.Fprintf(os.Stderr, "Serving advisory metrics at http://%s/metrics\n", p.listen)
+			if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	p.addFlagsTo(cmd)
+	return cmd
+}
+
+type metricsServeParams struct {
+	advisoriesRepoDirs []string
+	advisoriesURL      string
+	policyFile         string
+	listen             string
+	refresh            time.Duration
+}
+
+func (p *metricsServeParams) addFlagsTo(cmd *cobra.Command) {
+	addAdvisoriesDirsFlag(&p.advisoriesRepoDirs, cmd)
+	addAdvisoriesURLFlag(&p.advisoriesURL, cmd)
+
+	cmd.Flags().StringVar(&p.policyFile, "policy", "", fmt.Sprintf("path to the SLA policy file (default: %s in the last advisories repo dir)", advisory.SLAPolicyFile))
+	cmd.Flags().StringVar(&p.listen, "listen", ":9090", "address to serve metrics on")
+	cmd.Flags().DurationVar(&p.refresh, "refresh", 5*time.Minute, "how often to re-read the advisory data")
+}
+
+// policy returns the SLA policy in the policy file, or else the default policy.
+func (p *metricsServeParams) policy(advisoriesRepoDirs []string) (advisory.SLAPolicy, error) {
+	path := p.policyFile
+	if path == "" {
+		if len(advisoriesRepoDirs) == 0 {
+			return advisory.DefaultSLAPolicy, nil
+		}
+		path = filepath.Join(advisoriesRepoDirs[len(advisoriesRepoDirs)-1], advisory.SLAPolicyFile)
+	}
+
+	policy, err := advisory.LoadSLAPolicy(path)
+	if err != nil {
+		return nil, err
+	}
+	if policy == nil {
+		if p.policyFile != "" {
+			return nil, fmt.Errorf("SLA policy file %s not found", p.policyFile)
+		}
+		return advisory.DefaultSLAPolicy, nil
+	}
+
+	return policy, nil
+}
+
+func computeAdvisoryMetrics(advisoriesURL string, advisoriesRepoDirs []string, policy advisory.SLAPolicy) (advisory.Metrics, error) {
+	advisoryCfgs, cleanup, err := advisoriesIndexAsOf(advisoriesURL, advisoriesRepoDirs, "")
+	if err != nil {
+		return advisory.Metrics{}, err
+	}
+	defer cleanup()
+
+	severities, err := scan.VulnerabilitySeverities()
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "warning: severities are unknown, so advisories are counted as Unknown: %v\n", err)
+	}
+
+	return advisory.ComputeMetrics(advisory.MetricsOptions{
+		AdvisoryCfgs: advisoryCfgs,
+		Severities:   severities,
+		Policy:       policy,
+		Now:          time.Now(),
+	}), nil
+}
+
+// metricsServer serves the most recently computed metrics.
+type metricsServer struct {
+	compute func() (advisory.Metrics, error)
+
+	mu   sync.RWMutex
+	body []byte
+}
+
+func (s *metricsServer) refresh() error {
+	m, err := s.compute()
+	if err != nil {
+		return fmt.Errorf("unable to compute advisory metrics: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if _, err := m.WriteTo(&buf); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.body = buf.Bytes()
+	s.mu.Unlock()
+
+	return nil
+}
+
+func (s *metricsServer) refreshEvery(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.refresh(); err != nil {
+				_, _ = fmt.Fprintf(os.Stderr, "warning: %v (serving the last metrics)\n", err)
+			}
+		}
+	}
+}
+
+func (s *metricsServer) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	s.mu.RLock()
+	body := s.body
+	s.mu.RUnlock()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = w.Write(body)
+}
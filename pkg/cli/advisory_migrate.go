@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/wolfi-dev/wolfictl/pkg/advisory"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	"github.com/wolfi-dev/wolfictl/pkg/distro"
+)
+
+func AdvisoryMigrate() *cobra.Command {
+	p := &migrateParams{}
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "upgrade advisory documents to the current schema version",
+		Long: fmt.Sprintf(`upgrade advisory documents to the current schema version
+
+Each advisory document (including archived ones) that conforms to an older
+schema version is upgraded in place to the current version (%q), keeping its
+comments and formatting. Documents already at the current version are left
+untouched, so it's safe to run this repeatedly.`, advisoryconfigs.SchemaVersion),
+		SilenceErrors: true,
+		Args:          cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			advisoriesRepoDir := resolveAdvisoriesDir(p.advisoriesRepoDir)
+			if advisoriesRepoDir == "" {
+				if p.doNotDetectDistro {
+					return fmt.Errorf("no advisories repo dir specified")
+				}
+
+				d, err := distro.Detect()
+				if err != nil {
+					return fmt.Errorf("no advisories repo dir specified, and distro auto-detection failed: %w", err)
+				}
+
+				advisoriesRepoDir = d.AdvisoriesRepoDir
+				_, _ = fmt.Fprint(os.Stderr, renderDetectedDistro(d))
+			}
+
+			migrated, err := advisory.Migrate(advisory.MigrateOptions{
+				AdvisoriesDir: advisoriesRepoDir,
+				DryRun:        p.dryRun,
+			})
+			if err != nil {
+				return err
+			}
+
+			for _, m := range migrated {
+				from := m.From
+				if from == "" {
+					from = "unversioned"
+				}
+				fmt.Printf("%s: %s -> %s\n", m.Path, from, m.To)
+			}
+
+			verb := "Migrated"
+			if p.dryRun {
+				verb = "Would migrate"
+			}
+			_, _ = fmt.Fprintf(os.Stderr, "%s %d advisory documents\n", verb, len(migrated))
+
+			return nil
+		},
+	}
+
+	p.addFlagsTo(cmd)
+	return cmd
+}
+
+type migrateParams struct {
+	doNotDetectDistro bool
+
+	advisoriesRepoDir string
+
+	dryRun bool
+}
+
+func (p *migrateParams) addFlagsTo(cmd *cobra.Command) {
+	addNoDistroDetectionFlag(&p.doNotDetectDistro, cmd)
+
+	addAdvisoriesDirFlag(&p.advisoriesRepoDir, cmd)
+
+	cmd.Flags().BoolVar(&p.dryRun, "dry-run", false, "only list the documents that need migrating, without changing them")
+}
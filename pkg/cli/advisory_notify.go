@@ -0,0 +1,150 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/wolfi-dev/wolfictl/pkg/advisory"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
+	"github.com/wolfi-dev/wolfictl/pkg/scan"
+)
+
+const (
+	envVarNameForNotifyWebhookURL = "WOLFICTL_NOTIFY_WEBHOOK_URL"
+
+	notifyFormatSlack = "slack"
+	notifyFormatJSON  = "json"
+)
+
+func AdvisoryNotify() *cobra.Command {
+	p := &notifyParams{}
+	cmd := &cobra.Command{
+		Use:   "notify",
+		Short: "post a digest of advisories needing attention to a webhook",
+		Long: fmt.Sprintf(`post a digest of advisories needing attention to a webhook
+
+The digest lists:
+
+  - advisories first detected within the --since period
+  - advisories detected before then that are still under_investigation
+  - unresolved advisories that have been open longer than the SLA for their
+    severity (see 'wolfictl advisory stats')
+
+It's meant to be run on a schedule (e.g. daily, with --since 1d), so that
+triage doesn't depend on someone remembering to look.
+
+With --format slack (the default), the digest is posted as a message to a Slack
+incoming webhook. With --format json, the digest itself is posted as JSON, for
+other webhooks.
+
+The webhook URL can also be set with the environment variable %s,
+which keeps it out of the command line. If nothing needs attention, nothing is
+posted. Use --dry-run to print the digest instead of posting it.`, envVarNameForNotifyWebhookURL),
+		Example: `  wolfictl advisory notify --since 1d --webhook-url https://hooks.slack.com/services/...
+
+  wolfictl advisory notify --since 7d --format json --dry-run`,
+		SilenceErrors: true,
+		Args:          cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if p.format != notifyFormatSlack && p.format != notifyFormatJSON {
+				return fmt.Errorf("unsupported format %q (must be %s or %s)", p.format, notifyFormatSlack, notifyFormatJSON)
+			}
+
+			webhookURL := p.webhookURL
+			if webhookURL == "" {
+				webhookURL = os.Getenv(envVarNameForNotifyWebhookURL)
+			}
+			if webhookURL == "" && !p.dryRun {
+				return fmt.Errorf("no webhook URL specified (use --webhook-url or %s)", envVarNameForNotifyWebhookURL)
+			}
+
+			since, err := parseAge(p.since)
+			if err != nil {
+				return fmt.Errorf("invalid --since: %w", err)
+			}
+
+			slas, err := parseSLAs(p.slas)
+			if err != nil {
+				return err
+			}
+
+			advisoriesRepoDir, err := p.resolveAdvisoriesDir()
+			if err != nil {
+				return err
+			}
+
+			advisoryCfgs, err := advisoryconfigs.NewIndex(rwos.DirFS(advisoriesRepoDir))
+			if err != nil {
+				return err
+			}
+
+			severities, err := scan.VulnerabilitySeverities()
+			if err != nil {
+				_, _ = fmt.Fprintf(os.Stderr, "warning: severities are unknown, so SLA breaches can't be found: %v\n", err)
+			}
+
+			now := time.Now()
+			digest := advisory.BuildDigest(advisory.DigestOptions{
+				AdvisoryCfgs: advisoryCfgs,
+				Since:        now.Add(-since),
+				Severities:   severities,
+				SLAs:         slas,
+				Now:          now,
+			})
+
+			var payload any = digest
+			if p.format == notifyFormatSlack {
+				payload = map[string]string{"text": digest.Text(p.maxItems)}
+			}
+
+			if p.dryRun {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(payload)
+			}
+
+			if digest.Empty() {
+				_, _ = fmt.Fprintln(os.Stderr, "No advisories need attention, so nothing was posted.")
+				return nil
+			}
+
+			if err := advisory.PostWebhook(http.DefaultClient, webhookURL, payload); err != nil {
+				return err
+			}
+
+			_, _ = fmt.Fprintf(os.Stderr, "Posted digest of %d new, %d untriaged, and %d SLA-breaching advisories\n", len(digest.New), len(digest.Untriaged), len(digest.SLABreaches))
+			return nil
+		},
+	}
+
+	p.addFlagsTo(cmd)
+	return cmd
+}
+
+type notifyParams struct {
+	advisoriesDirParams
+
+	webhookURL string
+	format     string
+	since      string
+	slas       []string
+	maxItems   int
+	dryRun     bool
+}
+
+func (p *notifyParams) addFlagsTo(cmd *cobra.Command) {
+	p.advisoriesDirParams.addFlagsTo(cmd)
+
+	cmd.Flags().StringVar(&p.webhookURL, "webhook-url", "", fmt.Sprintf("URL of the webhook to post the digest to (can also be set with environment variable `%s`)", envVarNameForNotifyWebhookURL))
+	cmd.Flags().StringVar(&p.format, "format", notifyFormatSlack, fmt.Sprintf("payload format (%s)", strings.Join([]string{notifyFormatSlack, notifyFormatJSON}, ", ")))
+	cmd.Flags().StringVar(&p.since, "since", "1d", "period in which advisories count as newly detected, as a number of days (e.g. 7d) or a duration (e.g. 12h)")
+	addSLAFlag(&p.slas, cmd)
+	cmd.Flags().IntVar(&p.maxItems, "max-items", 20, "most advisories to list in each section of a Slack message (0 for no limit)")
+	cmd.Flags().BoolVar(&p.dryRun, "dry-run", false, "print the payload instead of posting it")
+}
@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/google/go-github/v50/github"
+	"github.com/spf13/cobra"
+	"github.com/wolfi-dev/wolfictl/pkg/advisory"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
+	"golang.org/x/oauth2"
+)
+
+func AdvisoryPollUpstream() *cobra.Command {
+	p := &pollUpstreamParams{}
+	cmd := &cobra.Command{
+		Use:   "poll-upstream",
+		Short: "check the upstream fixes that affected packages are waiting for",
+		Long: `check the upstream fixes that affected packages are waiting for
+
+Advisories whose latest event is affected and records a pending upstream fix
+(see --pending-upstream-fix for 'wolfictl advisory create' and 'wolfictl
+advisory update') are checked against GitHub: an issue is done once it's
+closed as completed, a pull request once it's merged, and a commit once it's
+in the repository's latest tagged release.
+
+Advisories whose upstream fix is done are listed as ready to progress (e.g. to
+be updated to a newer upstream version and marked fixed), followed by those
+still pending. With --ready-only, the exit code is 1 if any are ready, so this
+can be used in a scheduled job.
+
+Set GITHUB_TOKEN to avoid GitHub's low rate limit for unauthenticated requests.`,
+		Example: `  wolfictl advisory poll-upstream
+
+  wolfictl advisory poll-upstream -p go --ready-only`,
+		SilenceErrors: true,
+		Args:          cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			advisoriesRepoDir, err := p.resolveAdvisoriesDir()
+			if err != nil {
+				return err
+			}
+
+			advisoryCfgs, err := advisoryconfigs.NewIndex(rwos.DirFS(advisoriesRepoDir))
+			if err != nil {
+				return err
+			}
+
+			httpClient := http.DefaultClient
+			if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+				httpClient = oauth2.NewClient(cmd.Context(), oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token}))
+			}
+
+			results, err := advisory.PollUpstream(cmd.Context(), advisory.PollUpstreamOptions{
+				AdvisoryCfgs:     advisoryCfgs,
+				SelectedPackages: p.packageNames,
+				Checker:          advisory.GitHubUpstreamChecker{Client: github.NewClient(httpClient)},
+			})
+			if err != nil {
+				return err
+			}
+
+			var ready, pending, failed []advisory.PendingUpstreamFixResult
+			for _, r := range results {
+				switch {
+				case r.Err != nil:
+					failed = append(failed, r)
+				case r.Status.Ready:
+					ready = append(ready, r)
+				default:
+					pending = append(pending, r)
+				}
+			}
+
+			fmt.Printf("Ready to progress (%d):\n", len(ready))
+			for _, r := range ready {
+				fmt.Printf("  %s: %s: %s (%s)\n", r.Package, r.Vulnerability, r.Status.Detail, r.URL)
+			}
+
+			if !p.readyOnly {
+				fmt.Printf("\nStill pending (%d):\n", len(pending))
+				for _, r := range pending {
+					fmt.Printf("  %s: %s: %s (%s)\n", r.Package, r.Vulnerability, r.Status.Detail, r.URL)
+				}
+			}
+
+			for _, r := range failed {
+				fmt.Fprintf(os.Stderr, "warning: unable to check %s: %s: %v\n", r.Package, r.Vulnerability, r.Err)
+			}
+
+			if p.readyOnly && len(ready) > 0 {
+				return &ExitCodeError{Code: 1}
+			}
+
+			return nil
+		},
+	}
+
+	p.addFlagsTo(cmd)
+	return cmd
+}
+
+type pollUpstreamParams struct {
+	advisoriesDirParams
+
+	packageNames []string
+	readyOnly    bool
+}
+
+func (p *pollUpstreamParams) addFlagsTo(cmd *cobra.Command) {
+	p.advisoriesDirParams.addFlagsTo(cmd)
+
+	cmd.Flags().StringSliceVarP(&p.packageNames, "package", "p", nil, "package names whose advisories to check (default: all)")
+	cmd.Flags().BoolVar(&p.readyOnly, "ready-only", false, "only list advisories that are ready to progress, and exit 1 if there are any")
+}
@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	rwhttp "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/http"
+)
+
+func AdvisoryPublishIndex() *cobra.Command {
+	p := &publishIndexParams{}
+	cmd := &cobra.Command{
+		Use:   "publish-index",
+		Short: "write the index used to read the advisory data over HTTP",
+		Long: `write the index used to read the advisory data over HTTP
+
+The index lists each advisory document with the digest of its contents, as
+compressed JSON. When it's published alongside the advisory documents (e.g. by
+uploading the advisories repository's YAML files and the index to a bucket or
+static site), commands that accept --advisories-url can read the advisory data
+from there instead of from a local checkout of the advisories repository. Only
+the documents that changed since they were last fetched are downloaded again.
+
+By default, the index is written to the advisories repository, as ` + rwhttp.IndexFile + `.`,
+		Example: `  wolfictl advisory publish-index
+
+  wolfictl advisory publish-index -o site/advisories/` + rwhttp.IndexFile,
+		SilenceErrors: true,
+		Args:          cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			advisoriesRepoDir, err := p.resolveAdvisoriesDir()
+			if err != nil {
+				return err
+			}
+
+			outputLocation := p.outputLocation
+			if outputLocation == "" {
+				outputLocation = filepath.Join(advisoriesRepoDir, rwhttp.IndexFile)
+			}
+
+			f, err := os.Create(outputLocation)
+			if err != nil {
+				return fmt.Errorf("unable to create index file: %w", err)
+			}
+			defer f.Close()
+
+			if err := rwhttp.WriteIndex(f, os.DirFS(advisoriesRepoDir)); err != nil {
+				return fmt.Errorf("unable to write index of %s: %w", advisoriesRepoDir, err)
+			}
+
+			if err := f.Close(); err != nil {
+				return fmt.Errorf("unable to write index file: %w", err)
+			}
+
+			fmt.Fprintf(os.Stderr, "Index written to %s\n", outputLocation)
+			return nil
+		},
+	}
+
+	p.addFlagsTo(cmd)
+	return cmd
+}
+
+type publishIndexParams struct {
+	advisoriesDirParams
+
+	outputLocation string
+}
+
+func (p *publishIndexParams) addFlagsTo(cmd *cobra.Command) {
+	p.advisoriesDirParams.addFlagsTo(cmd)
+
+	cmd.Flags().StringVarP(&p.outputLocation, "output", "o", "", fmt.Sprintf("path to write the index to (default: %s in the advisories repo dir)", rwhttp.IndexFile))
+}
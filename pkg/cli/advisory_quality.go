@@ -0,0 +1,158 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/wolfi-dev/wolfictl/pkg/advisory"
+	"github.com/wolfi-dev/wolfictl/pkg/configs"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
+	"github.com/wolfi-dev/wolfictl/pkg/distro"
+)
+
+func AdvisoryQuality() *cobra.Command {
+	p := &qualityParams{}
+	cmd := &cobra.Command{
+		Use:   "quality",
+		Short: "Score advisories on completeness and list the ones most in need of cleanup",
+		Long: `Score advisories on completeness and list the ones most in need of cleanup.
+
+Each advisory starts with a score of 100, and points are deducted for issues
+such as missing justifications, impact or action statements, implausible
+timestamps, stale investigations, and non-CVE identifiers. Advisories are listed
+from lowest to highest score, followed by the average score over recent months.`,
+		SilenceErrors: true,
+		Args:          cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			advisoriesRepoDir := resolveAdvisoriesDir(p.advisoriesRepoDir)
+			if advisoriesRepoDir == "" {
+				if p.doNotDetectDistro {
+					return fmt.Errorf("no advisories repo dir specified")
+				}
+
+				d, err := distro.Detect()
+				if err != nil {
+					return fmt.Errorf("no advisories repo dir specified, and distro auto-detection failed: %w", err)
+				}
+
+				advisoriesRepoDir = d.AdvisoriesRepoDir
+				_, _ = fmt.Fprint(os.Stderr, renderDetectedDistro(d))
+			}
+
+			advisoryFsys := rwos.DirFS(advisoriesRepoDir)
+			var advisoryCfgs *configs.Index[advisoryconfigs.Document]
+			var err error
+			if p.packageName != "" {
+				advisoryCfgs, err = advisoryconfigs.NewIndexFromPaths(advisoryFsys, fmt.Sprintf("%s.advisories.yaml", p.packageName))
+			} else {
+				advisoryCfgs, err = advisoryconfigs.NewIndex(advisoryFsys)
+			}
+			if err != nil {
+				return err
+			}
+
+			opts := advisory.QualityOptions{
+				AdvisoryCfgs: advisoryCfgs,
+				StaleAfter:   time.Duration(p.staleAfterDays) * 24 * time.Hour,
+			}
+
+			reports := advisory.Quality(opts)
+
+			var cleanup []advisory.QualityReport
+			for _, r := range reports {
+				if r.Score < advisory.MaxQualityScore {
+					cleanup = append(cleanup, r)
+				}
+			}
+
+			if len(cleanup) == 0 {
+				fmt.Println("✅ No advisory quality issues found")
+			} else {
+				shown := cleanup
+				if p.limit > 0 && len(shown) > p.limit {
+					shown = shown[:p.limit]
+				}
+
+				fmt.Println(renderQualityReports(shown))
+
+				if len(shown) < len(cleanup) {
+					fmt.Printf("... and %d more (use --limit 0 to show all)\n", len(cleanup)-len(shown))
+				}
+			}
+
+			fmt.Printf(
+				"\n%d of %d advisories need cleanup; average score: %.1f\n",
+				len(cleanup),
+				len(reports),
+				advisory.AverageQualityScore(reports),
+			)
+
+			if p.trendMonths > 0 {
+				trend := advisory.QualityTrend(opts, monthEnds(time.Now(), p.trendMonths))
+				fmt.Printf("\n%s\n", renderQualityTrend(trend))
+			}
+
+			return nil
+		},
+	}
+
+	p.addFlagsTo(cmd)
+	return cmd
+}
+
+type qualityParams struct {
+	doNotDetectDistro bool
+	advisoriesRepoDir string
+	packageName       string
+	limit             int
+	staleAfterDays    int
+	trendMonths       int
+}
+
+func (p *qualityParams) addFlagsTo(cmd *cobra.Command) {
+	addNoDistroDetectionFlag(&p.doNotDetectDistro, cmd)
+	addAdvisoriesDirFlag(&p.advisoriesRepoDir, cmd)
+	addPackageFlag(&p.packageName, cmd)
+
+	cmd.Flags().IntVar(&p.limit, "limit", 20, "maximum number of advisories to list for cleanup (0 for no limit)")
+	cmd.Flags().IntVar(&p.staleAfterDays, "stale-after", 30, "number of days an advisory can remain under investigation before it's flagged")
+	cmd.Flags().IntVar(&p.trendMonths, "trend-months", 6, "number of months to include in the quality trend (0 to skip)")
+}
+
+// monthEnds returns the end of each of the n months preceding the current one,
+// oldest first, followed by now.
+func monthEnds(now time.Time, n int) []time.Time {
+	startOfMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+
+	times := make([]time.Time, 0, n+1)
+	for i := n; i > 0; i-- {
+		times = append(times, startOfMonth.AddDate(0, -i+1, 0).Add(-time.Nanosecond))
+	}
+
+	return append(times, now)
+}
+
+func renderQualityReports(reports []advisory.QualityReport) string {
+	var lines []string
+	for _, r := range reports {
+		lines = append(lines, fmt.Sprintf("%3d  %s: %s", r.Score, r.Package, r.Vulnerability))
+		for _, issue := range r.Issues {
+			lines = append(lines, fmt.Sprintf("       - %s (-%d)", issue.Description, issue.Penalty))
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func renderQualityTrend(points []advisory.QualityTrendPoint) string {
+	lines := []string{"Quality trend:"}
+	for _, pt := range points {
+		lines = append(lines, fmt.Sprintf("  %s  %5.1f  (%d advisories)", pt.Time.Format("2006-01-02"), pt.AverageScore, pt.Advisories))
+	}
+
+	return strings.Join(lines, "\n")
+}
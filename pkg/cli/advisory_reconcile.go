@@ -0,0 +1,145 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/openvex/go-vex/pkg/vex"
+	"github.com/spf13/cobra"
+	"github.com/wolfi-dev/wolfictl/pkg/advisory"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
+	"github.com/wolfi-dev/wolfictl/pkg/scan"
+)
+
+func AdvisoryReconcile() *cobra.Command {
+	p := &reconcileParams{}
+	cmd := &cobra.Command{
+		Use:   "reconcile",
+		Short: "resolve advisories under investigation that the scanner no longer detects",
+		Long: fmt.Sprintf(`resolve advisories under investigation that the scanner no longer detects
+
+Given fresh scans of the latest APKs (the output of 'wolfictl scan --output
+json', as a file or a directory of .json files), each advisory that's still
+under_investigation for a scanned package is checked against the scan findings
+for the package and its subpackages. If its vulnerability isn't found under any
+of its IDs or aliases, e.g. because the scanner's vulnerability data was
+corrected, a not_affected event is recorded for it (with justification %s,
+unless --justification is given).
+
+Packages that weren't scanned are left alone. Scan results must not be
+filtered, e.g. with --baseline or --vex, since a vulnerability missing from
+them may still be detected. Use --dry-run to only list the
+events that would be recorded.`, advisory.DefaultReconcileJustification),
+		Example: `  wolfictl scan --package foo --package bar --output json > scans/latest.json
+  wolfictl advisory reconcile --scan-results scans/ --dry-run`,
+		SilenceErrors: true,
+		Args:          cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if p.scanResults == "" {
+				return fmt.Errorf("no scan results specified (use --scan-results)")
+			}
+
+			advisoriesRepoDir, err := p.resolveAdvisoriesDir()
+			if err != nil {
+				return err
+			}
+
+			advisoryCfgs, err := advisoryconfigs.NewIndex(rwos.DirFS(advisoriesRepoDir))
+			if err != nil {
+				return err
+			}
+
+			results, err := readScanResults(p.scanResults)
+			if err != nil {
+				return err
+			}
+
+			reqs, err := advisory.Reconcile(advisory.ReconcileOptions{
+				AdvisoryCfgs:  advisoryCfgs,
+				ScanResults:   results,
+				Justification: vex.Justification(p.justification),
+				Impact:        p.impact,
+			})
+			if err != nil {
+				return err
+			}
+
+			for _, req := range reqs {
+				if err := req.Validate(); err != nil {
+					return err
+				}
+			}
+
+			for _, req := range reqs {
+				fmt.Printf("%s: %s: %s (%s)\n", req.Package, req.Vulnerability, req.Status, req.Justification)
+				if !p.dryRun {
+					if _, err := advisory.Import(req, advisoryCfgs); err != nil {
+						return err
+					}
+				}
+			}
+
+			verb := "Resolved"
+			if p.dryRun {
+				verb = "Would resolve"
+			}
+			_, _ = fmt.Fprintf(os.Stderr, "%s %d advisories no longer detected by the scanner\n", verb, len(reqs))
+
+			return nil
+		},
+	}
+
+	p.addFlagsTo(cmd)
+	return cmd
+}
+
+type reconcileParams struct {
+	advisoriesDirParams
+
+	scanResults   string
+	justification string
+	impact        string
+	dryRun        bool
+}
+
+func (p *reconcileParams) addFlagsTo(cmd *cobra.Command) {
+	p.advisoriesDirParams.addFlagsTo(cmd)
+
+	cmd.Flags().StringVar(&p.scanResults, "scan-results", "", "saved scan result (from 'wolfictl scan --output json'), or a directory of them")
+	cmd.Flags().StringVar(&p.justification, "justification", "", fmt.Sprintf("justification of the recorded events (default %s)", advisory.DefaultReconcileJustification))
+	cmd.Flags().StringVar(&p.impact, "impact", "", "impact statement of the recorded events (default: a statement naming the scanned version)")
+	cmd.Flags().BoolVar(&p.dryRun, "dry-run", false, "only list the events that would be recorded")
+}
+
+// readScanResults reads the saved scan result at the given path, or each saved
+// scan result (i.e. .json file) in the given directory.
+func readScanResults(path string) ([]scan.JSONResult, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read scan results: %w", err)
+	}
+	if !info.IsDir() {
+		return scan.ReadJSONResultsFile(path)
+	}
+
+	files, err := filepath.Glob(filepath.Join(path, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no scan results (.json files) found in %s", path)
+	}
+
+	var results []scan.JSONResult
+	for _, f := range files {
+		r, err := scan.ReadJSONResultsFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f, err)
+		}
+		results = append(results, r...)
+	}
+
+	return results, nil
+}
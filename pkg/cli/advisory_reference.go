@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wolfi-dev/wolfictl/pkg/advisory"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
+)
+
+func AdvisoryReference() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "reference",
+		Short: "Commands for managing the reference links of advisories",
+	}
+
+	cmd.AddCommand(AdvisoryReferenceAdd())
+
+	return cmd
+}
+
+func AdvisoryReferenceAdd() *cobra.Command {
+	p := &referenceAddParams{}
+	cmd := &cobra.Command{
+		Use:   "add <package> <vulnerability-id> <type>=<url>...",
+		Short: "add reference links to an advisory",
+		Long: fmt.Sprintf(`add reference links to an advisory
+
+Each reference is given as <type>=<url>, where type is one of: %s.
+
+References are added to the advisory's latest event, since they add context
+rather than changing the advisory's status, so no new event is recorded. To
+record references with a new event, use --reference with 'wolfictl advisory
+create' or 'wolfictl advisory update'.
+
+References are shown by 'wolfictl advisory show' and 'wolfictl advisory list
+--json', and are included in OSV and CSAF exports.`, strings.Join(referenceTypes(), ", ")),
+		Example: `  wolfictl advisory reference add openssl CVE-2023-0464 \
+    upstream-advisory=https://www.openssl.org/news/secadv/20230322.txt \
+    distro-pr=https://github.com/wolfi-dev/os/pull/1234`,
+		SilenceErrors: true,
+		Args:          cobra.MinimumNArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			packageName, vulnID := args[0], args[1]
+
+			var refs []advisoryconfigs.Reference
+			for _, arg := range args[2:] {
+				ref, err := advisory.ParseReference(arg)
+				if err != nil {
+					return err
+				}
+				refs = append(refs, ref)
+			}
+
+			advisoriesRepoDir, err := p.resolveAdvisoriesDir()
+			if err != nil {
+				return err
+			}
+
+			advisoryCfgs, err := advisoryconfigs.NewIndex(rwos.DirFS(advisoriesRepoDir))
+			if err != nil {
+				return err
+			}
+
+			for _, ref := range refs {
+				err := advisory.AddReference(advisory.AddReferenceOptions{
+					AdvisoryCfgs:  advisoryCfgs,
+					Package:       packageName,
+					Vulnerability: vulnID,
+					Reference:     ref,
+				})
+				if err != nil {
+					return err
+				}
+			}
+
+			_, _ = fmt.Fprintf(os.Stderr, "Added %d references to %s: %s\n", len(refs), packageName, vulnID)
+			return nil
+		},
+	}
+
+	p.addFlagsTo(cmd)
+	return cmd
+}
+
+type referenceAddParams struct {
+	advisoriesDirParams
+}
@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+	"github.com/wolfi-dev/wolfictl/pkg/advisory"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
+)
+
+func AdvisorySearch() *cobra.Command {
+	p := &searchParams{}
+	cmd := &cobra.Command{
+		Use:         "search <query>",
+		Annotations: pagerAnnotations,
+		Short:       "search the advisories by vulnerability ID, alias, package, or notes",
+		Long: `search the advisories by vulnerability ID, alias, package, or notes
+
+Each whitespace-separated term of the query must match (case-insensitively)
+part of the advisory's vulnerability ID, one of its aliases, its package name,
+or the justification, impact statement, or action statement of any of its
+events.
+
+Results are ranked by how well they match: matches in vulnerability IDs and
+aliases rank highest, then package names, then justifications, then impact and
+action statements. An exact match of a whole value ranks higher than a partial
+match.`,
+		Example: `  wolfictl advisory search GHSA-jfhm-5ghh-2f97
+
+  wolfictl advisory search "test suite" --json`,
+		SilenceErrors: true,
+		Args:          cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			advisoriesRepoDir, err := p.resolveAdvisoriesDir()
+			if err != nil {
+				return err
+			}
+
+			advisoryCfgs, err := advisoryconfigs.NewIndex(rwos.DirFS(advisoriesRepoDir))
+			if err != nil {
+				return err
+			}
+
+			results := advisory.Search(advisory.SearchOptions{
+				AdvisoryCfgs: advisoryCfgs,
+				Query:        strings.Join(args, " "),
+				Limit:        p.limit,
+			})
+
+			if p.outputJSON {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(searchJSON(results))
+			}
+
+			if len(results) == 0 {
+				_, _ = fmt.Fprintln(os.Stderr, "No advisories found.")
+				return nil
+			}
+
+			for _, r := range results {
+				fmt.Printf("%s: %s: %s\n", r.Package, r.Vulnerability, renderListItem(r.Latest))
+				for _, m := range r.Matches {
+					fmt.Printf("    %s\n", styleSubtle.Render(fmt.Sprintf("%s: %s", m.Field, m.Value)))
+				}
+			}
+
+			return nil
+		},
+	}
+
+	p.addFlagsTo(cmd)
+	return cmd
+}
+
+type searchParams struct {
+	advisoriesDirParams
+
+	limit      int
+	outputJSON bool
+}
+
+func (p *searchParams) addFlagsTo(cmd *cobra.Command) {
+	p.advisoriesDirParams.addFlagsTo(cmd)
+
+	cmd.Flags().IntVar(&p.limit, "limit", 50, "most results to show (0 for no limit)")
+	cmd.Flags().BoolVar(&p.outputJSON, "json", false, "print the results as JSON")
+}
+
+type searchResultJSON struct {
+	Package       string            `json:"package"`
+	Vulnerability string            `json:"vulnerability"`
+	Score         int               `json:"score"`
+	Latest        listedEventJSON   `json:"latest"`
+	Matches       []searchMatchJSON `json:"matches"`
+}
+
+type searchMatchJSON struct {
+	Field string `json:"field"`
+	Value string `json:"value"`
+}
+
+func searchJSON(results []advisory.SearchResult) []searchResultJSON {
+	out := make([]searchResultJSON, 0, len(results))
+	for _, r := range results {
+		out = append(out, searchResultJSON{
+			Package:       r.Package,
+			Vulnerability: r.Vulnerability,
+			Score:         r.Score,
+			Latest: listedEventJSON{
+				Timestamp:     r.Latest.Timestamp,
+				Status:        string(r.Latest.Status),
+				FixedVersion:  r.Latest.FixedVersion,
+				Justification: string(r.Latest.Justification),
+				Impact:        r.Latest.ImpactStatement,
+				Action:        r.Latest.ActionStatement,
+			},
+			Matches: lo.Map(r.Matches, func(m advisory.SearchMatch, _ int) searchMatchJSON {
+				return searchMatchJSON{Field: m.Field, Value: m.Value}
+			}),
+		})
+	}
+
+	return out
+}
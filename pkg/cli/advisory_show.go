@@ -0,0 +1,330 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/openvex/go-vex/pkg/vex"
+	"github.com/spf13/cobra"
+	"github.com/wolfi-dev/wolfictl/pkg/advisory"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
+	"github.com/wolfi-dev/wolfictl/pkg/distro"
+)
+
+func AdvisoryShow() *cobra.Command {
+	p := &showParams{}
+	cmd := &cobra.Command{
+		Use:         "show <package> <vulnerability-id>",
+		Annotations: pagerAnnotations,
+		Short:       "show the event timeline of an advisory",
+		Long: `show the event timeline of an advisory
+
+All of the advisory's events are shown in order, with how long ago each one
+happened, who recorded it, its notes (impact and action statements), and its
+reference links.
+
+The person who recorded an event is the author of the commit that last changed
+the event, as found by git blame on the advisory document. Use --actors=false
+if the advisories directory isn't a git repository.
+
+With --all-streams, the timelines of the advisories for the vulnerability in
+every version stream of the package's project (e.g. postgresql-15 and
+postgresql-16) are shown, one after another. Streams are recognized by their
+names ending in a version, or are listed per project in the advisories
+repository's ` + advisory.StreamsFile + ` file.
+
+With --purl (e.g. pkg:apk/wolfi/foo@1.2.3-r0) in place of the package name,
+the package's timeline is shown followed by the advisory's status at that
+exact version: if the vulnerability is fixed in a later version, it's affected.`,
+		Example: `  wolfictl advisory show glibc CVE-2023-4911
+
+  # The same timeline as JSON
+  wolfictl advisory show glibc CVE-2023-4911 --json
+
+  # The timeline, and the status at an exact version
+  wolfictl advisory show --purl pkg:apk/wolfi/glibc@2.38-r1 CVE-2023-4911
+
+  # The timelines across all postgresql streams
+  wolfictl advisory show postgresql-16 CVE-2023-5869 --all-streams`,
+		SilenceErrors: true,
+		Args:          cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			advisoriesRepoDir := resolveAdvisoriesDir(p.advisoriesRepoDir)
+			if advisoriesRepoDir == "" {
+				if p.doNotDetectDistro {
+					return fmt.Errorf("no advisories repo dir specified")
+				}
+
+				d, err := distro.Detect()
+				if err != nil {
+					return fmt.Errorf("no advisories repo dir specified, and distro auto-detection failed: %w", err)
+				}
+
+				advisoriesRepoDir = d.AdvisoriesRepoDir
+				_, _ = fmt.Fprint(os.Stderr, renderDetectedDistro(d))
+			}
+
+			var packageName, vulnID, version string
+			if p.purl != "" {
+				if len(args) != 1 {
+					return fmt.Errorf("with --purl, only the vulnerability ID must be given")
+				}
+				if p.allStreams {
+					return fmt.Errorf("--purl cannot be used with --all-streams")
+				}
+
+				purl, err := advisory.ParsePackageURL(p.purl)
+				if err != nil {
+					return err
+				}
+				packageName, vulnID, version = purl.Name, args[0], purl.Version
+			} else {
+				if len(args) != 2 {
+					return fmt.Errorf("a package name and a vulnerability ID must be given")
+				}
+				packageName, vulnID = args[0], args[1]
+			}
+
+			if p.allStreams {
+				return showAllStreams(advisoriesRepoDir, packageName, vulnID, p.actors, p.outputJSON)
+			}
+
+			events, err := advisory.Timeline(advisory.TimelineOptions{
+				AdvisoriesDir: advisoriesRepoDir,
+				Package:       packageName,
+				Vulnerability: vulnID,
+				WithActors:    p.actors,
+			})
+			if err != nil {
+				return err
+			}
+
+			var atVersion *advisoryconfigs.Entry
+			if version != "" {
+				atVersion = entryAtVersion(events, version)
+			}
+
+			if p.outputJSON {
+				out := timelineJSON(packageName, vulnID, events)
+				if atVersion != nil {
+					out.Version = version
+					e := listedEvent(*atVersion)
+					out.AtVersion = &e
+				}
+
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(out)
+			}
+
+			fmt.Print(renderTimeline(packageName, vulnID, events, time.Now()))
+			if atVersion != nil {
+				fmt.Printf("\nAt version %s: %s\n", version, renderListItem(*atVersion))
+			}
+			return nil
+		},
+	}
+
+	p.addFlagsTo(cmd)
+	return cmd
+}
+
+type showParams struct {
+	doNotDetectDistro bool
+
+	advisoriesRepoDir string
+
+	purl       string
+	actors     bool
+	allStreams bool
+	outputJSON bool
+}
+
+func (p *showParams) addFlagsTo(cmd *cobra.Command) {
+	addNoDistroDetectionFlag(&p.doNotDetectDistro, cmd)
+
+	addAdvisoriesDirFlag(&p.advisoriesRepoDir, cmd)
+
+	cmd.Flags().StringVar(&p.purl, "purl", "", "purl of the package (e.g. pkg:apk/wolfi/foo@1.2.3-r0), in place of its name, to also show the advisory's status at its version")
+	cmd.Flags().BoolVar(&p.actors, "actors", true, "show who recorded each event (looked up via git blame)")
+	cmd.Flags().BoolVar(&p.allStreams, "all-streams", false, "show the timelines for every version stream of the package's project that has an advisory for the vulnerability")
+	cmd.Flags().BoolVar(&p.outputJSON, "json", false, "print the timeline as JSON")
+}
+
+// showAllStreams shows the timelines of the advisories for the vulnerability in
+// each version stream of the package's project that has one.
+func showAllStreams(advisoriesRepoDir, packageName, vulnID string, actors, outputJSON bool) error {
+	advisoryCfgs, err := advisoryconfigs.NewIndex(rwos.DirFS(advisoriesRepoDir))
+	if err != nil {
+		return err
+	}
+
+	streams, err := loadStreamMap([]string{advisoriesRepoDir})
+	if err != nil {
+		return err
+	}
+
+	project := streams.Project(packageName)
+	packages, err := projectStreams(advisoryCfgs, streams, project)
+	if err != nil {
+		return err
+	}
+
+	var timelines []timelineJSONOutput
+	for _, pkg := range packages {
+		if advisory.LatestForPackage(advisoryCfgs, pkg, vulnID) == nil {
+			continue
+		}
+
+		events, err := advisory.Timeline(advisory.TimelineOptions{
+			AdvisoriesDir: advisoriesRepoDir,
+			Package:       pkg,
+			Vulnerability: vulnID,
+			WithActors:    actors,
+		})
+		if err != nil {
+			return err
+		}
+
+		if !outputJSON {
+			if len(timelines) > 0 {
+				fmt.Println()
+			}
+			fmt.Print(renderTimeline(pkg, vulnID, events, time.Now()))
+		}
+		timelines = append(timelines, timelineJSON(pkg, vulnID, events))
+	}
+
+	if len(timelines) == 0 {
+		return fmt.Errorf("no advisory for %s in any version stream of project %q (%s)", vulnID, project, strings.Join(packages, ", "))
+	}
+
+	if outputJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(timelines)
+	}
+
+	return nil
+}
+
+// timelineJSONOutput is the JSON output of 'advisory show'.
+type timelineJSONOutput struct {
+	Package       string              `json:"package"`
+	Vulnerability string              `json:"vulnerability"`
+	Events        []timelineEventJSON `json:"events"`
+
+	// Version and AtVersion are the package version given with --purl, and the
+	// advisory's status at that version.
+	Version   string           `json:"version,omitempty"`
+	AtVersion *listedEventJSON `json:"atVersion,omitempty"`
+}
+
+// entryAtVersion returns the latest public event of the timeline as it applies
+// to the given package version (see advisory.AtVersion), or nil if there are no
+// public events.
+func entryAtVersion(events []advisory.TimelineEvent, version string) *advisoryconfigs.Entry {
+	entries := make([]advisoryconfigs.Entry, 0, len(events))
+	for _, e := range events {
+		entries = append(entries, e.Entry)
+	}
+
+	latest := advisory.Latest(advisory.PublicEntries(entries))
+	if latest == nil {
+		return nil
+	}
+
+	e := advisory.AtVersion(*latest, version)
+	return &e
+}
+
+type timelineEventJSON struct {
+	listedEventJSON
+	Actor    string `json:"actor,omitempty"`
+	Internal bool   `json:"internal,omitempty"`
+}
+
+func timelineJSON(packageName, vulnID string, events []advisory.TimelineEvent) timelineJSONOutput {
+	out := timelineJSONOutput{
+		Package:       packageName,
+		Vulnerability: vulnID,
+		Events:        make([]timelineEventJSON, 0, len(events)),
+	}
+
+	for _, e := range events {
+		out.Events = append(out.Events, timelineEventJSON{
+			listedEventJSON: listedEvent(e.Entry),
+			Actor:           e.Actor,
+			Internal:        e.Internal,
+		})
+	}
+
+	return out
+}
+
+func renderTimeline(packageName, vulnID string, events []advisory.TimelineEvent, now time.Time) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s: %s\n", packageName, vulnID)
+
+	for _, e := range events {
+		fmt.Fprintf(&b, "\n%s %s\n", e.Timestamp.UTC().Format("2006-01-02 15:04 MST"), styleSubtle.Render("("+renderRelativeTime(e.Timestamp, now)+")"))
+
+		line := "  " + renderListItem(e.Entry)
+		if e.Actor != "" {
+			line += " by " + e.Actor
+		}
+		if e.Internal {
+			line += styleSubtle.Render(" [internal]")
+		}
+		b.WriteString(line + "\n")
+
+		if e.ImpactStatement != "" {
+			fmt.Fprintf(&b, "  impact: %s\n", e.ImpactStatement)
+		}
+		if e.ActionStatement != "" && e.Status != vex.StatusAffected {
+			fmt.Fprintf(&b, "  action: %s\n", e.ActionStatement)
+		}
+		for _, ref := range e.References {
+			fmt.Fprintf(&b, "  %s: %s\n", ref.Type, ref.URL)
+		}
+	}
+
+	return b.String()
+}
+
+// renderRelativeTime describes how long before now t was, in the largest
+// whole unit, e.g. "3 days ago".
+func renderRelativeTime(t, now time.Time) string {
+	d := now.Sub(t)
+	if d < 0 {
+		return "in the future"
+	}
+
+	day := 24 * time.Hour
+	units := []struct {
+		name string
+		size time.Duration
+	}{
+		{"year", 365 * day},
+		{"month", 30 * day},
+		{"day", day},
+		{"hour", time.Hour},
+		{"minute", time.Minute},
+	}
+
+	for _, u := range units {
+		if n := int(d / u.size); n >= 1 {
+			if n == 1 {
+				return fmt.Sprintf("1 %s ago", u.name)
+			}
+			return fmt.Sprintf("%d %ss ago", n, u.name)
+		}
+	}
+
+	return "just now"
+}
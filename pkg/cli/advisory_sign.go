@@ -0,0 +1,200 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/wolfi-dev/wolfictl/pkg/advisory"
+	"github.com/wolfi-dev/wolfictl/pkg/distro"
+)
+
+func AdvisorySign() *cobra.Command {
+	p := &signParams{}
+	cmd := &cobra.Command{
+		Use:   "sign",
+		Short: "sign the advisory data with Sigstore",
+		Long: `sign the advisory data with Sigstore
+
+A manifest listing the digest of each advisory document (including archived
+documents) is written and signed with cosign, so that consumers of the advisory
+data, and of the security databases and other exports built from it, can verify
+that it came from the advisories repository unmodified. Use 'wolfictl advisory
+verify' to verify it.
+
+Each document's digest is computed over its canonical form (the decoded
+document, encoded as JSON), so reformatting a document doesn't invalidate the
+signature.
+
+By default, the whole advisories repository is signed. Use -p to sign only the
+documents of the given packages.
+
+Unless --key is given, keyless signing is used, which records the signature in
+the Rekor transparency log. The signature is written to a Sigstore bundle next
+to the manifest.
+
+This requires cosign to be installed.`,
+		Example: `  # Sign the advisories repository keyless, e.g. in a GitHub Actions workflow
+  wolfictl advisory sign
+
+  # Sign a single package's advisories with a cosign key
+  wolfictl advisory sign -p openssl --key cosign.key --output openssl.manifest.json`,
+		SilenceErrors: true,
+		Args:          cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			advisoriesRepoDir, err := p.resolveAdvisoriesDir()
+			if err != nil {
+				return err
+			}
+
+			manifest, err := advisory.NewAdvisoriesManifest(advisoriesRepoDir, p.packageNames...)
+			if err != nil {
+				return err
+			}
+
+			if err := manifest.Write(p.outputLocation); err != nil {
+				return err
+			}
+
+			bundlePath, err := advisory.SignExportManifest(p.outputLocation, advisory.SigningOptions{Key: p.key})
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(os.Stderr, "Signed manifest of %d advisory documents written to %s (signature bundle: %s)\n", len(manifest.Files), p.outputLocation, bundlePath)
+			return nil
+		},
+	}
+
+	p.addFlagsTo(cmd)
+	return cmd
+}
+
+type signParams struct {
+	advisoriesDirParams
+
+	packageNames   []string
+	outputLocation string
+	key            string
+}
+
+func (p *signParams) addFlagsTo(cmd *cobra.Command) {
+	p.advisoriesDirParams.addFlagsTo(cmd)
+
+	cmd.Flags().StringSliceVarP(&p.packageNames, "package", "p", nil, "package names whose advisories to sign (default: all)")
+	cmd.Flags().StringVarP(&p.outputLocation, "output", "o", advisory.AdvisoriesManifestName, "path to write the manifest to")
+	cmd.Flags().StringVar(&p.key, "key", "", "path (or KMS URI) of a cosign private key to sign with, instead of keyless signing")
+}
+
+func AdvisoryVerify() *cobra.Command {
+	p := &verifyParams{}
+	cmd := &cobra.Command{
+		Use:   "verify",
+		Short: "verify the advisory data against its Sigstore signature",
+		Long: `verify the advisory data against its Sigstore signature
+
+The manifest written by 'wolfictl advisory sign' must have been signed by the
+given identity, as recorded in the Rekor transparency log (or with the given
+key), and must list the current digest of each advisory document.
+
+By default, the manifest must cover the whole advisories repository, so
+documents it doesn't list are also reported. Use -p to verify only the
+documents of the given packages.
+
+This requires cosign to be installed.`,
+		Example: `  wolfictl advisory verify \
+    --certificate-identity https://github.com/wolfi-dev/advisories/.github/workflows/release.yaml@refs/heads/main
+
+  wolfictl advisory verify -p openssl --key cosign.pub --manifest openssl.manifest.json`,
+		SilenceErrors: true,
+		Args:          cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if p.certificateIdentity == "" && p.key == "" {
+				return fmt.Errorf("--certificate-identity or --key is required")
+			}
+
+			advisoriesRepoDir, err := p.resolveAdvisoriesDir()
+			if err != nil {
+				return err
+			}
+
+			bundlePath := p.bundle
+			if bundlePath == "" {
+				bundlePath = p.manifest + advisory.ExportBundleSuffix
+			}
+
+			if err := advisory.VerifyExportManifest(p.manifest, bundlePath, p.verificationOptions()); err != nil {
+				return err
+			}
+
+			manifest, err := advisory.ReadExportManifest(p.manifest)
+			if err != nil {
+				return err
+			}
+
+			if len(p.packageNames) > 0 {
+				manifest, err = manifest.ForPackages(p.packageNames...)
+				if err != nil {
+					return err
+				}
+			}
+
+			if err := manifest.VerifyAdvisories(advisoriesRepoDir, len(p.packageNames) == 0); err != nil {
+				return err
+			}
+
+			fmt.Printf("✅ %d advisory documents are verified\n", len(manifest.Files))
+			return nil
+		},
+	}
+
+	p.addFlagsTo(cmd)
+	return cmd
+}
+
+type verifyParams struct {
+	advisoriesDirParams
+	verificationParams
+
+	packageNames []string
+	manifest     string
+	bundle       string
+}
+
+func (p *verifyParams) addFlagsTo(cmd *cobra.Command) {
+	p.advisoriesDirParams.addFlagsTo(cmd)
+	p.verificationParams.addFlagsTo(cmd)
+
+	cmd.Flags().StringSliceVarP(&p.packageNames, "package", "p", nil, "package names whose advisories to verify (default: all)")
+	cmd.Flags().StringVar(&p.manifest, "manifest", advisory.AdvisoriesManifestName, "path to the manifest of the advisory documents")
+	cmd.Flags().StringVar(&p.bundle, "bundle", "", fmt.Sprintf("path to the Sigstore bundle of the manifest's signature (default: the manifest's path with %q appended)", advisory.ExportBundleSuffix))
+}
+
+type advisoriesDirParams struct {
+	doNotDetectDistro bool
+	advisoriesRepoDir string
+}
+
+func (p *advisoriesDirParams) addFlagsTo(cmd *cobra.Command) {
+	addNoDistroDetectionFlag(&p.doNotDetectDistro, cmd)
+	addAdvisoriesDirFlag(&p.advisoriesRepoDir, cmd)
+}
+
+func (p *advisoriesDirParams) resolveAdvisoriesDir() (string, error) {
+	advisoriesRepoDir := resolveAdvisoriesDir(p.advisoriesRepoDir)
+	if advisoriesRepoDir != "" {
+		return advisoriesRepoDir, nil
+	}
+
+	if p.doNotDetectDistro {
+		return "", fmt.Errorf("advisories repo dir was left unspecified")
+	}
+
+	d, err := distro.Detect()
+	if err != nil {
+		return "", fmt.Errorf("advisories repo dir was left unspecified, and distro auto-detection failed: %w", err)
+	}
+
+	_, _ = fmt.Fprint(os.Stderr, renderDetectedDistro(d))
+	return d.AdvisoriesRepoDir, nil
+}
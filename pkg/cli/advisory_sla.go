@@ -0,0 +1,162 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/wolfi-dev/wolfictl/pkg/advisory"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
+	"github.com/wolfi-dev/wolfictl/pkg/scan"
+)
+
+func AdvisorySLA() *cobra.Command {
+	p := &slaParams{}
+	cmd := &cobra.Command{
+		Use:   "sla",
+		Short: "list SLA breaches and upcoming SLA deadlines for unresolved advisories",
+		Long: fmt.Sprintf(`list SLA breaches and upcoming SLA deadlines for unresolved advisories
+
+Each unresolved advisory (affected or under_investigation) is due to be
+resolved within the SLA for its vulnerability's severity, counting from the
+advisory's first event (i.e. detection). Advisories past due are listed as
+breaches, followed by those due within the --within period.
+
+The SLA policy is read from the %s file in the advisories repository (or
+the file given with --policy), which maps severities to SLAs under a "slas"
+key, e.g.:
+
+  slas:
+    Critical: 7d
+    High: 30d
+
+If there's no policy file, the SLAs are Critical=7d, High=30d, Medium=90d, and
+Low=180d. SLAs given with --sla override the policy entirely.
+
+Severities are taken from the vulnerability database used by 'wolfictl scan',
+as for 'wolfictl advisory stats'. With --json, the whole queue of advisories
+with an SLA is printed, soonest due first, for use by dashboards.`, advisory.SLAPolicyFile),
+		Example: `  wolfictl advisory sla --within 14d
+
+  wolfictl advisory sla --sla Critical=3d,High=14d --json`,
+		SilenceErrors: true,
+		Args:          cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			within, err := parseAge(p.within)
+			if err != nil {
+				return fmt.Errorf("invalid --within: %w", err)
+			}
+
+			advisoriesRepoDir, err := p.resolveAdvisoriesDir()
+			if err != nil {
+				return err
+			}
+
+			policy, err := p.policy(advisoriesRepoDir)
+			if err != nil {
+				return err
+			}
+
+			advisoryCfgs, err := advisoryconfigs.NewIndex(rwos.DirFS(advisoriesRepoDir))
+			if err != nil {
+				return err
+			}
+
+			severities, err := scan.VulnerabilitySeverities()
+			if err != nil {
+				_, _ = fmt.Fprintf(os.Stderr, "warning: severities are unknown, so no deadlines can be computed: %v\n", err)
+			}
+
+			now := time.Now()
+			queue := advisory.SLAQueue(advisory.SLAQueueOptions{
+				AdvisoryCfgs: advisoryCfgs,
+				Severities:   severities,
+				Policy:       policy,
+				Now:          now,
+			})
+
+			if p.outputJSON {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(queue)
+			}
+
+			var breached, upcoming []advisory.SLAItem
+			for _, item := range queue {
+				switch {
+				case item.Breached:
+					breached = append(breached, item)
+				case item.Due.Sub(now) <= within:
+					upcoming = append(upcoming, item)
+				}
+			}
+
+			fmt.Printf("Breached (%d):\n", len(breached))
+			for _, item := range breached {
+				fmt.Printf("  %s: %s: %s %s, due %s (%s overdue)\n", item.Package, item.Vulnerability, item.Severity, item.Status, item.Due.Format("2006-01-02"), renderDays(now.Sub(item.Due)))
+			}
+
+			fmt.Printf("\nDue within %s (%d):\n", p.within, len(upcoming))
+			for _, item := range upcoming {
+				fmt.Printf("  %s: %s: %s %s, due %s (in %s)\n", item.Package, item.Vulnerability, item.Severity, item.Status, item.Due.Format("2006-01-02"), renderDays(item.Due.Sub(now)))
+			}
+
+			return nil
+		},
+	}
+
+	p.addFlagsTo(cmd)
+	return cmd
+}
+
+type slaParams struct {
+	advisoriesDirParams
+
+	slas       []string
+	policyFile string
+	within     string
+	outputJSON bool
+}
+
+func (p *slaParams) addFlagsTo(cmd *cobra.Command) {
+	p.advisoriesDirParams.addFlagsTo(cmd)
+
+	cmd.Flags().StringSliceVar(&p.slas, "sla", nil, "longest time an advisory of a given severity may stay unresolved, as <severity>=<duration> (overrides the SLA policy)")
+	cmd.Flags().StringVar(&p.policyFile, "policy", "", fmt.Sprintf("path to the SLA policy file (default: %s in the advisories repository)", advisory.SLAPolicyFile))
+	cmd.Flags().StringVar(&p.within, "within", "7d", "list advisories due within this period, as a number of days (e.g. 7d) or a duration (e.g. 12h)")
+	cmd.Flags().BoolVar(&p.outputJSON, "json", false, "print the queue of advisories with an SLA as JSON")
+}
+
+// policy returns the SLA policy given by --sla, or else the one in the policy
+// file, or else the default policy.
+func (p *slaParams) policy(advisoriesRepoDir string) (advisory.SLAPolicy, error) {
+	if len(p.slas) > 0 {
+		slas, err := parseSLAs(p.slas)
+		if err != nil {
+			return nil, err
+		}
+		return advisory.SLAPolicy(slas), nil
+	}
+
+	path := p.policyFile
+	if path == "" {
+		path = filepath.Join(advisoriesRepoDir, advisory.SLAPolicyFile)
+	}
+
+	policy, err := advisory.LoadSLAPolicy(path)
+	if err != nil {
+		return nil, err
+	}
+	if policy == nil {
+		if p.policyFile != "" {
+			return nil, fmt.Errorf("SLA policy file %s not found", p.policyFile)
+		}
+		return advisory.DefaultSLAPolicy, nil
+	}
+
+	return policy, nil
+}
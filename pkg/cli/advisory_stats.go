@@ -0,0 +1,188 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openvex/go-vex/pkg/vex"
+	"github.com/spf13/cobra"
+	"github.com/wolfi-dev/wolfictl/pkg/advisory"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
+	"github.com/wolfi-dev/wolfictl/pkg/distro"
+	"github.com/wolfi-dev/wolfictl/pkg/scan"
+	"golang.org/x/exp/slices"
+)
+
+func AdvisoryStats() *cobra.Command {
+	p := &statsParams{}
+	cmd := &cobra.Command{
+		Use:         "stats",
+		Annotations: pagerAnnotations,
+		Short:       "report statistics and SLA breaches for advisories",
+		Long: `report statistics and SLA breaches for advisories
+
+The report includes the number of advisories by their latest status and by
+severity, the mean and median time from an advisory's first event (detection)
+to its first fixed event, and the unresolved advisories (affected or
+under_investigation) that have been open longer than the SLA for their severity.
+
+Severities are taken from the vulnerability database used by 'wolfictl scan',
+which must already have been downloaded (e.g. by running a scan). Without it,
+all advisories are counted as "Unknown" severity.
+
+SLAs are given per severity as <severity>=<duration>, where the duration is in
+days (e.g. "7d") or in Go duration syntax (e.g. "36h").`,
+		Example: `  # Weekly report as Markdown, with a tighter SLA for critical vulnerabilities
+  wolfictl advisory stats --sla Critical=3d,High=14d > report.md
+
+  # The same report as JSON
+  wolfictl advisory stats --json`,
+		SilenceErrors: true,
+		Args:          cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			slas, err := parseSLAs(p.slas)
+			if err != nil {
+				return err
+			}
+
+			advisoriesRepoDir := resolveAdvisoriesDir(p.advisoriesRepoDir)
+			if advisoriesRepoDir == "" {
+				if p.doNotDetectDistro {
+					return fmt.Errorf("no advisories repo dir specified")
+				}
+
+				d, err := distro.Detect()
+				if err != nil {
+					return fmt.Errorf("no advisories repo dir specified, and distro auto-detection failed: %w", err)
+				}
+
+				advisoriesRepoDir = d.AdvisoriesRepoDir
+				_, _ = fmt.Fprint(os.Stderr, renderDetectedDistro(d))
+			}
+
+			advisoryCfgs, err := advisoryconfigs.NewIndex(rwos.DirFS(advisoriesRepoDir))
+			if err != nil {
+				return err
+			}
+
+			severities, err := scan.VulnerabilitySeverities()
+			if err != nil {
+				_, _ = fmt.Fprintf(os.Stderr, "warning: severities are unknown: %v\n", err)
+			}
+
+			stats := advisory.ComputeStats(advisory.StatsOptions{
+				AdvisoryCfgs: advisoryCfgs,
+				Severities:   severities,
+				SLAs:         slas,
+				Now:          time.Now(),
+			})
+
+			if p.outputJSON {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(stats)
+			}
+
+			fmt.Print(renderStatsMarkdown(stats))
+			return nil
+		},
+	}
+
+	p.addFlagsTo(cmd)
+	return cmd
+}
+
+type statsParams struct {
+	doNotDetectDistro bool
+
+	advisoriesRepoDir string
+
+	slas       []string
+	outputJSON bool
+}
+
+func (p *statsParams) addFlagsTo(cmd *cobra.Command) {
+	addNoDistroDetectionFlag(&p.doNotDetectDistro, cmd)
+
+	addAdvisoriesDirFlag(&p.advisoriesRepoDir, cmd)
+
+	addSLAFlag(&p.slas, cmd)
+	cmd.Flags().BoolVar(&p.outputJSON, "json", false, "print the report as JSON instead of Markdown")
+}
+
+func addSLAFlag(val *[]string, cmd *cobra.Command) {
+	cmd.Flags().StringSliceVar(val, "sla", []string{"Critical=7d", "High=30d", "Medium=90d", "Low=180d"}, "longest time an advisory of a given severity may stay unresolved, as <severity>=<duration>")
+}
+
+// parseSLAs parses SLA flag values of the form "<severity>=<duration>".
+func parseSLAs(values []string) (map[string]time.Duration, error) {
+	slas := make(map[string]time.Duration, len(values))
+
+	for _, v := range values {
+		severity, duration, ok := strings.Cut(v, "=")
+		if !ok {
+			return nil, fmt.Errorf("SLA %q must be of the form <severity>=<duration>", v)
+		}
+
+		if !slices.Contains(scan.Severities, severity) {
+			return nil, fmt.Errorf("SLA %q has unknown severity %q (must be one of: %s)", v, severity, strings.Join(scan.Severities, ", "))
+		}
+
+		d, err := parseAge(duration)
+		if err != nil {
+			return nil, fmt.Errorf("SLA %q has invalid duration: %w", v, err)
+		}
+
+		slas[severity] = d
+	}
+
+	return slas, nil
+}
+
+func renderStatsMarkdown(s advisory.Stats) string {
+	var b strings.Builder
+
+	b.WriteString("# Advisory statistics\n\n")
+	fmt.Fprintf(&b, "Total advisories: %d\n\n", s.Total)
+
+	b.WriteString("## By status\n\n| Status | Count |\n| --- | ---: |\n")
+	statuses := vex.Statuses()
+	sort.Strings(statuses)
+	for _, status := range statuses {
+		fmt.Fprintf(&b, "| %s | %d |\n", status, s.CountsByStatus[vex.Status(status)])
+	}
+
+	b.WriteString("\n## By severity\n\n| Severity | Count |\n| --- | ---: |\n")
+	for _, sev := range scan.Severities {
+		fmt.Fprintf(&b, "| %s | %d |\n", sev, s.CountsBySeverity[sev])
+	}
+
+	b.WriteString("\n## Time to fix\n\n")
+	if s.TimeToFix.Count == 0 {
+		b.WriteString("No fixed advisories.\n")
+	} else {
+		fmt.Fprintf(&b, "Across %d fixed advisories: mean %s, median %s.\n", s.TimeToFix.Count, renderDays(s.TimeToFix.Mean), renderDays(s.TimeToFix.Median))
+	}
+
+	b.WriteString("\n## SLA breaches\n\n")
+	if len(s.SLABreaches) == 0 {
+		b.WriteString("None.\n")
+		return b.String()
+	}
+
+	b.WriteString("| Package | Vulnerability | Severity | Status | Open | SLA |\n| --- | --- | --- | --- | ---: | ---: |\n")
+	for _, breach := range s.SLABreaches {
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s |\n", breach.Package, breach.Vulnerability, breach.Severity, breach.Status, renderDays(breach.Open), renderDays(breach.SLA))
+	}
+
+	return b.String()
+}
+
+func renderDays(d time.Duration) string {
+	return fmt.Sprintf("%.1fd", d.Hours()/24)
+}
@@ -0,0 +1,240 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/openvex/go-vex/pkg/vex"
+	"github.com/spf13/cobra"
+	"github.com/wolfi-dev/wolfictl/pkg/advise"
+	"github.com/wolfi-dev/wolfictl/pkg/advisory"
+	"github.com/wolfi-dev/wolfictl/pkg/cli/components/advisory/prompt"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	buildconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/build"
+	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
+	"github.com/wolfi-dev/wolfictl/pkg/distro"
+	"github.com/wolfi-dev/wolfictl/pkg/vuln"
+	"github.com/wolfi-dev/wolfictl/pkg/vuln/nvdapi"
+)
+
+func AdvisorySuggest() *cobra.Command {
+	p := &suggestParams{}
+	cmd := &cobra.Command{
+		Use:   "suggest <package>",
+		Short: "suggest how to resolve a package's open advisories, using NVD data",
+		Long: `suggest how to resolve a package's open advisories, using NVD data
+
+For each of the package's advisories that's still affected or under
+investigation, the vulnerable products and version ranges that NVD records for
+the CVE (or the CVE alias of a GHSA or other ID, as known to OSV) are compared
+against the package and its current version. The resulting suggestion is one
+of:
+
+  - not_affected (component_not_present), if NVD lists only other products
+  - not_affected (vulnerable_code_not_present), if the package's version
+    predates the vulnerable range
+  - fixed, if the package's version is beyond the vulnerable range
+  - affected, if the package's version is within the vulnerable range
+
+Each suggestion is shown along with its reasoning, and is only recorded once
+confirmed. Use --dry-run to only show the suggestions.`,
+		SilenceErrors: true,
+		Args:          cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			packageName := args[0]
+
+			distroRepoDir := resolveDistroDir(p.distroRepoDir)
+			advisoriesRepoDir := resolveAdvisoriesDir(p.advisoriesRepoDir)
+			if distroRepoDir == "" || advisoriesRepoDir == "" {
+				if p.doNotDetectDistro {
+					return fmt.Errorf("distro repo dir and/or advisories repo dir was left unspecified")
+				}
+
+				d, err := distro.Detect()
+				if err != nil {
+					return fmt.Errorf("distro repo dir and/or advisories repo dir was left unspecified, and distro auto-detection failed: %w", err)
+				}
+
+				distroRepoDir = d.DistroRepoDir
+				advisoriesRepoDir = d.AdvisoriesRepoDir
+				_, _ = fmt.Fprint(os.Stderr, renderDetectedDistro(d))
+			}
+
+			advisoryCfgs, err := advisoryconfigs.NewIndex(rwos.DirFS(advisoriesRepoDir))
+			if err != nil {
+				return err
+			}
+
+			buildCfgs, err := buildconfigs.NewIndex(rwos.DirFS(distroRepoDir))
+			if err != nil {
+				return err
+			}
+
+			buildCfgEntries := buildCfgs.Select().WhereName(packageName).Configurations()
+			if len(buildCfgEntries) == 0 {
+				return fmt.Errorf("no build configuration found for package %q", packageName)
+			}
+			pkg := buildCfgEntries[0].Package
+			currentVersion := fmt.Sprintf("%s-r%d", pkg.Version, pkg.Epoch)
+
+			open := advisory.List(advisoryCfgs, advisory.ListFilter{
+				Package:    packageName,
+				Unresolved: true,
+			})
+			if len(open) == 0 {
+				_, _ = fmt.Fprintf(os.Stderr, "No open advisories for %s\n", packageName)
+				return nil
+			}
+
+			detector := nvdapi.NewDetector(http.DefaultClient, nvdapi.DefaultHost, resolveNVDAPIKey(p.nvdAPIKey))
+			aliasFinder := vuln.NewAliasFinder(http.DefaultClient)
+			stdin := bufio.NewReader(os.Stdin)
+
+			var recorded int
+			for _, a := range open {
+				cveID, err := resolveCVEID(cmd.Context(), aliasFinder, a.Vulnerability)
+				if err != nil {
+					return err
+				}
+				if cveID == "" {
+					fmt.Printf("%s: %s: no CVE alias found, skipping\n\n", packageName, a.Vulnerability)
+					continue
+				}
+
+				cve, err := detector.CVE(cmd.Context(), cveID)
+				if err != nil {
+					return fmt.Errorf("unable to get %s from NVD: %w", cveID, err)
+				}
+
+				match, err := detector.MatchPackage(cve, packageName)
+				if err != nil {
+					return fmt.Errorf("unable to match %s against %s: %w", cveID, packageName, err)
+				}
+
+				suggestion := advise.SuggestTriage(cve, match, packageName, currentVersion)
+				suggestion.Vulnerability = a.Vulnerability
+
+				fmt.Print(renderTriageSuggestion(suggestion, cveID, currentVersion))
+
+				if suggestion.Status == "" || suggestion.Status == a.Latest.Status || p.dryRun {
+					fmt.Println()
+					continue
+				}
+
+				fmt.Print("Record this? [y/N] ")
+				answer, err := stdin.ReadString('\n')
+				if err != nil {
+					return err
+				}
+				fmt.Println()
+				if !strings.EqualFold(strings.TrimSpace(answer), "y") {
+					continue
+				}
+
+				req := advisory.Request{
+					Package:       packageName,
+					Vulnerability: a.Vulnerability,
+					Status:        suggestion.Status,
+					Justification: suggestion.Justification,
+					FixedVersion:  suggestion.FixedVersion,
+				}
+
+				if req.Validate() != nil {
+					// e.g. an action statement is still needed for "affected"
+					m := prompt.New(prompt.Configuration{Request: req})
+					returnedModel, err := tea.NewProgram(m).Run()
+					if err != nil {
+						return err
+					}
+
+					m, ok := returnedModel.(prompt.Model)
+					if !ok {
+						return fmt.Errorf("unexpected model type: %T", returnedModel)
+					}
+					if m.EarlyExit {
+						return nil
+					}
+					req = m.Request
+				}
+
+				if _, err := advisory.Import(req, advisoryCfgs); err != nil {
+					return err
+				}
+				recorded++
+			}
+
+			_, _ = fmt.Fprintf(os.Stderr, "Recorded %d of %d suggestions\n", recorded, len(open))
+			return nil
+		},
+	}
+
+	p.addFlagsTo(cmd)
+	return cmd
+}
+
+type suggestParams struct {
+	doNotDetectDistro bool
+
+	distroRepoDir, advisoriesRepoDir string
+
+	nvdAPIKey string
+	dryRun    bool
+}
+
+func (p *suggestParams) addFlagsTo(cmd *cobra.Command) {
+	addNoDistroDetectionFlag(&p.doNotDetectDistro, cmd)
+
+	addDistroDirFlag(&p.distroRepoDir, cmd)
+	addAdvisoriesDirFlag(&p.advisoriesRepoDir, cmd)
+
+	addNVDAPIKeyFlag(&p.nvdAPIKey, cmd)
+	cmd.Flags().BoolVar(&p.dryRun, "dry-run", false, "only show the suggestions, without asking to record them")
+}
+
+// resolveCVEID returns the given vulnerability ID if it's a CVE ID, or else the
+// first CVE ID among its aliases. It returns an empty string if there's no CVE
+// alias.
+func resolveCVEID(ctx context.Context, finder *vuln.AliasFinder, id string) (string, error) {
+	if strings.HasPrefix(id, "CVE-") {
+		return id, nil
+	}
+
+	aliases, err := finder.Aliases(ctx, id)
+	if err != nil {
+		return "", fmt.Errorf("unable to find aliases for %s: %w", id, err)
+	}
+
+	for _, alias := range aliases {
+		if strings.HasPrefix(alias, "CVE-") {
+			return alias, nil
+		}
+	}
+
+	return "", nil
+}
+
+func renderTriageSuggestion(s advise.TriageSuggestion, cveID, currentVersion string) string {
+	vulnerability := s.Vulnerability
+	if cveID != s.Vulnerability {
+		vulnerability = fmt.Sprintf("%s (%s)", s.Vulnerability, cveID)
+	}
+
+	var suggestion string
+	switch s.Status {
+	case "":
+		suggestion = styleSubtle.Render("no suggestion")
+	case vex.StatusNotAffected:
+		suggestion = fmt.Sprintf("%s (%s)", s.Status, s.Justification)
+	case vex.StatusFixed:
+		suggestion = fmt.Sprintf("%s in %s", s.Status, s.FixedVersion)
+	default:
+		suggestion = string(s.Status)
+	}
+
+	return fmt.Sprintf("%s-%s: %s: %s\n  %s\n", s.Package, currentVersion, vulnerability, suggestion, styleSubtle.Render(s.Reason))
+}
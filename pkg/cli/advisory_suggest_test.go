@@ -0,0 +1,12 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdvisorySuggestIsNotPaged(t *testing.T) {
+	// The command prompts for confirmation on stdin, which a pager would hide.
+	assert.False(t, pagesOutput(AdvisorySuggest()))
+}
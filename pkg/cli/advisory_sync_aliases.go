@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wolfi-dev/wolfictl/pkg/advisory"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
+	"github.com/wolfi-dev/wolfictl/pkg/distro"
+	"github.com/wolfi-dev/wolfictl/pkg/vuln"
+)
+
+func AdvisorySyncAliases() *cobra.Command {
+	p := &syncAliasesParams{}
+	cmd := &cobra.Command{
+		Use:   "sync-aliases",
+		Short: "record the complete set of known aliases for each advisory",
+		Long: `record the complete set of known aliases for each advisory
+
+For each advisory, the aliases of its vulnerability ID are looked up in OSV
+(which includes GHSA and Go vulnerability data), following aliases of aliases,
+e.g. GHSA -> CVE -> GO. Any aliases not yet recorded in the advisory document's
+"aliases" section are added.
+
+Conflicts are reported, too: recorded aliases that aren't known to OSV, and
+advisories of the same package that are recorded under two IDs of the same
+vulnerability.`,
+		SilenceErrors: true,
+		Args:          cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			advisoriesRepoDir := resolveAdvisoriesDir(p.advisoriesRepoDir)
+			if advisoriesRepoDir == "" {
+				if p.doNotDetectDistro {
+					return fmt.Errorf("no advisories repo dir specified")
+				}
+
+				d, err := distro.Detect()
+				if err != nil {
+					return fmt.Errorf("no advisories repo dir specified, and distro auto-detection failed: %w", err)
+				}
+
+				advisoriesRepoDir = d.AdvisoriesRepoDir
+				_, _ = fmt.Fprint(os.Stderr, renderDetectedDistro(d))
+			}
+
+			advisoryCfgs, err := advisoryconfigs.NewIndex(rwos.DirFS(advisoriesRepoDir))
+			if err != nil {
+				return err
+			}
+
+			result, err := advisory.SyncAliases(cmd.Context(), advisory.SyncAliasesOptions{
+				AdvisoryCfgs: advisoryCfgs,
+				Lookup:       vuln.NewAliasFinder(http.DefaultClient),
+				Package:      p.packageName,
+				DryRun:       p.dryRun,
+			})
+			if err != nil {
+				return err
+			}
+
+			verb := "added"
+			if p.dryRun {
+				verb = "missing"
+			}
+			for _, c := range result.Changes {
+				fmt.Printf("%s: %s: %s %s\n", c.Package, c.Vulnerability, verb, strings.Join(c.Added, ", "))
+			}
+
+			for _, c := range result.Conflicts {
+				_, _ = fmt.Fprintf(os.Stderr, "conflict: %s\n", c)
+			}
+
+			if len(result.Conflicts) > 0 {
+				return fmt.Errorf("found %d alias conflicts", len(result.Conflicts))
+			}
+
+			return nil
+		},
+	}
+
+	p.addFlagsTo(cmd)
+	return cmd
+}
+
+type syncAliasesParams struct {
+	doNotDetectDistro bool
+
+	advisoriesRepoDir string
+
+	packageName string
+	dryRun      bool
+}
+
+func (p *syncAliasesParams) addFlagsTo(cmd *cobra.Command) {
+	addNoDistroDetectionFlag(&p.doNotDetectDistro, cmd)
+
+	addAdvisoriesDirFlag(&p.advisoriesRepoDir, cmd)
+
+	addPackageFlag(&p.packageName, cmd)
+	cmd.Flags().BoolVar(&p.dryRun, "dry-run", false, "only report missing aliases and conflicts, without recording anything")
+}
@@ -4,12 +4,14 @@ import (
 	"fmt"
 	"os"
 	"sort"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/samber/lo"
 	"github.com/spf13/cobra"
 	"github.com/wolfi-dev/wolfictl/pkg/advisory"
 	"github.com/wolfi-dev/wolfictl/pkg/cli/components/advisory/prompt"
+	"github.com/wolfi-dev/wolfictl/pkg/configs"
 	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
 	buildconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/build"
 	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
@@ -21,8 +23,22 @@ import (
 func AdvisoryUpdate() *cobra.Command {
 	p := &updateParams{}
 	cmd := &cobra.Command{
-		Use:           "update",
-		Short:         "append an entry to an existing package advisory",
+		Use:   "update",
+		Short: "append an entry to an existing package advisory",
+		Long: `append an entry to an existing package advisory
+
+With --packages-from, the same entry is recorded for every package listed in the
+given file (one per line, or "-" for stdin), e.g. for a vulnerability in a
+library vendored by many packages. Packages without an advisory for the
+vulnerability yet get a new one, and packages whose advisory already has the
+entry's status are left untouched. All fields must be given as flags, since
+there's no prompting in this mode.
+
+With --fixed-version-from-index, each package's fixed version is its latest
+version published in the package repository.`,
+		Example: `  # Mark a vendored library's CVE as fixed in each package's latest build
+  wolfictl advisory update -V CVE-2023-39325 --packages-from vendors-x-net.txt \
+    --status fixed --fixed-version-from-index`,
 		SilenceErrors: true,
 		Args:          cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -65,7 +81,12 @@ func AdvisoryUpdate() *cobra.Command {
 				return fmt.Errorf("unable to select packages: %w", err)
 			}
 
-			req, err := p.requestParams.advisoryRequest()
+			templates, err := p.requestParams.templates(advisoriesRepoDir)
+			if err != nil {
+				return err
+			}
+
+			req, err := p.requestParams.advisoryRequest(templates)
 			if err != nil {
 				return err
 			}
@@ -79,9 +100,33 @@ func AdvisoryUpdate() *cobra.Command {
 				apkindexes = append(apkindexes, idx)
 			}
 
+			if p.fixedVersionFromIndex && p.requestParams.fixedVersion != "" {
+				return fmt.Errorf("--fixed-version and --fixed-version-from-index are mutually exclusive")
+			}
+
+			if p.packagesFrom != "" {
+				if req.Package != "" {
+					return fmt.Errorf("--package and --packages-from are mutually exclusive")
+				}
+
+				reqs, err := p.bulkRequests(req, apkindexes)
+				if err != nil {
+					return err
+				}
+
+				return updateMany(reqs, advisoryCfgs)
+			}
+
+			if p.fixedVersionFromIndex && req.Package != "" {
+				req.FixedVersion = advisory.LatestPublishedVersion(apkindexes, req.Package)
+				if req.FixedVersion == "" {
+					return fmt.Errorf("no published versions of %q found in %s", req.Package, packageRepositoryURL)
+				}
+			}
+
 			if err := req.Validate(); err != nil {
-				if p.doNotPrompt {
-					return fmt.Errorf("not enough information to create advisory: %w", err)
+				if !canPrompt(p.doNotPrompt) {
+					return incompleteRequestError(req, err)
 				}
 
 				// prompt for missing fields
@@ -109,6 +154,7 @@ func AdvisoryUpdate() *cobra.Command {
 					AllowedPackagesFunc:        allowedPackages,
 					AllowedVulnerabilitiesFunc: allowedVulnerabilities,
 					AllowedFixedVersionsFunc:   allowedFixedVersions,
+					Templates:                  templates,
 				})
 				var returnedModel tea.Model
 				program := tea.NewProgram(m)
@@ -153,6 +199,9 @@ type updateParams struct {
 	distroRepoDir, advisoriesRepoDir string
 	archs                            []string
 	packageRepositoryURL             string
+
+	packagesFrom          string
+	fixedVersionFromIndex bool
 }
 
 func (p *updateParams) addFlagsTo(cmd *cobra.Command) {
@@ -164,4 +213,83 @@ func (p *updateParams) addFlagsTo(cmd *cobra.Command) {
 	addAdvisoriesDirFlag(&p.advisoriesRepoDir, cmd)
 	cmd.Flags().StringSliceVar(&p.archs, "arch", []string{"x86_64", "aarch64"}, "package architectures to find published versions for")
 	cmd.Flags().StringVarP(&p.packageRepositoryURL, "package-repo-url", "r", "", "URL of the APK package repository")
+	cmd.Flags().StringVar(&p.packagesFrom, "packages-from", "", "file listing the packages to update, one per line (\"-\" for stdin)")
+	cmd.Flags().BoolVar(&p.fixedVersionFromIndex, "fixed-version-from-index", false, "use each package's latest published version as the fixed version")
+}
+
+// bulkRequests returns a copy of tmpl for each package listed in the
+// --packages-from file, resolving fixed versions if requested.
+func (p *updateParams) bulkRequests(tmpl advisory.Request, apkindexes []*repository.ApkIndex) ([]advisory.Request, error) {
+	r := os.Stdin
+	if p.packagesFrom != "-" {
+		f, err := os.Open(p.packagesFrom)
+		if err != nil {
+			return nil, fmt.Errorf("unable to open package list: %w", err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	packages, err := advisory.ReadPackageList(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(packages) == 0 {
+		return nil, fmt.Errorf("no packages listed in %s", p.packagesFrom)
+	}
+
+	var unpublished []string
+	reqs := make([]advisory.Request, 0, len(packages))
+	for _, pkg := range packages {
+		req := tmpl
+		req.Package = pkg
+
+		if p.fixedVersionFromIndex {
+			req.FixedVersion = advisory.LatestPublishedVersion(apkindexes, pkg)
+			if req.FixedVersion == "" {
+				unpublished = append(unpublished, pkg)
+				continue
+			}
+		}
+
+		if err := req.Validate(); err != nil {
+			return nil, fmt.Errorf("not enough information to update advisory for %q: %w", pkg, err)
+		}
+
+		reqs = append(reqs, req)
+	}
+
+	if len(unpublished) > 0 {
+		return nil, fmt.Errorf("no published versions found for: %s", strings.Join(unpublished, ", "))
+	}
+
+	return reqs, nil
+}
+
+// updateMany records each request, creating the advisory if the package doesn't
+// have one for the vulnerability yet.
+func updateMany(reqs []advisory.Request, advisoryCfgs *configs.Index[advisoryconfigs.Document]) error {
+	var updated, unchanged int
+	for _, req := range reqs {
+		changed, err := advisory.Import(req, advisoryCfgs)
+		if err != nil {
+			return fmt.Errorf("unable to update advisory for %q: %w", req.Package, err)
+		}
+
+		if !changed {
+			unchanged++
+			continue
+		}
+
+		updated++
+		_, _ = fmt.Fprintf(os.Stderr, "%s: %s: %s\n", req.Package, req.Vulnerability, renderListItem(advisoryconfigs.Entry{
+			Status:          req.Status,
+			Justification:   req.Justification,
+			ActionStatement: req.Action,
+			FixedVersion:    req.FixedVersion,
+		}))
+	}
+
+	_, _ = fmt.Fprintf(os.Stderr, "Updated %d advisories (%d already up to date)\n", updated, unchanged)
+	return nil
 }
@@ -3,9 +3,11 @@ package cli
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/spf13/cobra"
 	"github.com/wolfi-dev/wolfictl/pkg/advisory"
+	"github.com/wolfi-dev/wolfictl/pkg/configs"
 	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
 	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
 	"github.com/wolfi-dev/wolfictl/pkg/distro"
@@ -14,40 +16,98 @@ import (
 func AdvisoryValidate() *cobra.Command {
 	p := &validateParams{}
 	cmd := &cobra.Command{
-		Use:           "validate",
-		Short:         "Validate the state of advisory data",
+		Use:   "validate",
+		Short: "Validate the state of advisory data",
+		Long: `Validate the state of advisory data
+
+Advisory files must only use known fields. Beyond that, validation checks that:
+
+  - each package has exactly one advisories file
+  - advisory IDs are CVE, GHSA, or Go vulnerability IDs
+  - each advisory's events are in chronological order
+  - each event has a known status, with the fields that status requires
+  - fixed versions are valid APK versions
+  - archived advisories haven't changed since they were archived
+
+With --check-published, each fixed event's version must also have shipped for
+each architecture, i.e. the fixed version or a later one must be in the
+architecture's published APKINDEX (see 'wolfictl advisory
+verify-fixed-versions').
+
+The exit code is 1 if any issues are found, so this can be used in CI.`,
 		SilenceErrors: true,
 		Args:          cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			advisoriesRepoDir := resolveAdvisoriesDir(p.advisoriesRepoDir)
-			if advisoriesRepoDir == "" {
+			archs, packageRepositoryURL := p.archs, p.packageRepositoryURL
+			needPublished := p.checkPublished && (len(archs) == 0 || packageRepositoryURL == "")
+			if advisoriesRepoDir == "" || needPublished {
+				unspecified := "advisories repo dir was left unspecified"
+				if needPublished {
+					unspecified = "advisories repo dir, architectures, and/or package repository URL were left unspecified"
+				}
 				if p.doNotDetectDistro {
-					return fmt.Errorf("advisories repo dir was left unspecified")
+					return fmt.Errorf("%s", unspecified)
 				}
 
 				d, err := distro.Detect()
 				if err != nil {
-					return fmt.Errorf("advisories repo dir was left unspecified, and distro auto-detection failed: %w", err)
+					return fmt.Errorf("%s, and distro auto-detection failed: %w", unspecified, err)
 				}
 
-				advisoriesRepoDir = d.AdvisoriesRepoDir
+				if advisoriesRepoDir == "" {
+					advisoriesRepoDir = d.AdvisoriesRepoDir
+				}
+				if len(archs) == 0 {
+					archs = d.SupportedArchitectures
+				}
+				if packageRepositoryURL == "" {
+					packageRepositoryURL = d.APKRepositoryURL
+				}
 				_, _ = fmt.Fprint(os.Stderr, renderDetectedDistro(d))
 			}
 
+			changedFiles, onlyChanged, err := p.changed.changedConfigFiles(advisoriesRepoDir)
+			if err != nil {
+				return err
+			}
+
 			advisoryFsys := rwos.DirFS(advisoriesRepoDir)
-			advisoryCfgs, err := advisoryconfigs.NewIndex(advisoryFsys)
+			var advisoryCfgs *configs.Index[advisoryconfigs.Document]
+			if onlyChanged {
+				advisoryCfgs, err = advisoryconfigs.NewIndexFromPaths(advisoryFsys, changedFiles...)
+			} else {
+				advisoryCfgs, err = advisoryconfigs.NewIndex(advisoryFsys)
+			}
 			if err != nil {
 				return err
 			}
 
+			var archivedCfgs *configs.Index[advisoryconfigs.Document]
+			archiveDir := filepath.Join(advisoriesRepoDir, advisory.ArchiveDir)
+			if _, err := os.Stat(archiveDir); err == nil {
+				archivedCfgs, err = advisoryconfigs.NewIndex(rwos.DirFS(archiveDir))
+				if err != nil {
+					return err
+				}
+			}
+
 			opts := advisory.ValidateOptions{
-				AdvisoryCfgs: advisoryCfgs,
+				AdvisoryCfgs:         advisoryCfgs,
+				ArchivedAdvisoryCfgs: archivedCfgs,
+			}
+
+			if p.checkPublished {
+				opts.APKIndexes, err = loadPublishedAPKINDEXes(archs, packageRepositoryURL)
+				if err != nil {
+					return err
+				}
 			}
 
 			validationErr := advisory.Validate(opts)
 			if validationErr != nil {
 				fmt.Fprintf(os.Stderr, "❌ advisory data is not valid.%s\n", validationErr)
-				os.Exit(1)
+				return &ExitCodeError{Code: 1}
 			}
 
 			fmt.Fprint(os.Stderr, "✅ advisory data is valid.\n")
@@ -63,9 +123,15 @@ func AdvisoryValidate() *cobra.Command {
 type validateParams struct {
 	doNotDetectDistro bool
 	advisoriesRepoDir string
+	changed           changedParams
+	checkPublished    bool
+	publishedParams
 }
 
 func (p *validateParams) addFlagsTo(cmd *cobra.Command) {
 	addNoDistroDetectionFlag(&p.doNotDetectDistro, cmd)
 	addAdvisoriesDirFlag(&p.advisoriesRepoDir, cmd)
+	p.changed.addFlagsTo(cmd)
+	cmd.Flags().BoolVar(&p.checkPublished, "check-published", false, "also check that each fixed version has been published, according to the package repository's APKINDEXes")
+	p.publishedParams.addFlagsTo(cmd)
 }
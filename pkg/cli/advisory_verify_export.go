@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/wolfi-dev/wolfictl/pkg/advisory"
+)
+
+func AdvisoryVerifyExport() *cobra.Command {
+	p := &verifyExportParams{}
+	cmd := &cobra.Command{
+		Use:   "verify-export <path/to/export>",
+		Short: "Verify a signed advisory data export",
+		Long: `Verify a signed advisory data export, such as a security database built with
+'wolfictl advisory db --sign'.
+
+The export's manifest must have been signed by the given identity, as recorded
+in the Rekor transparency log (or with the given key), and must list the export's current digest. By
+default, the manifest and its Sigstore bundle are expected next to the export,
+as written when signing.
+
+This requires cosign to be installed.`,
+		Example: `  wolfictl advisory verify-export security.json \
+    --certificate-identity https://github.com/wolfi-dev/advisories/.github/workflows/release.yaml@refs/heads/main`,
+		SilenceErrors: true,
+		Args:          cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			exportPath := args[0]
+
+			if p.certificateIdentity == "" && p.key == "" {
+				return fmt.Errorf("--certificate-identity or --key is required")
+			}
+
+			manifestPath := p.manifest
+			if manifestPath == "" {
+				manifestPath = exportPath + advisory.ExportManifestSuffix
+			}
+			bundlePath := p.bundle
+			if bundlePath == "" {
+				bundlePath = manifestPath + advisory.ExportBundleSuffix
+			}
+
+			if err := advisory.VerifyExportManifest(manifestPath, bundlePath, p.verificationOptions()); err != nil {
+				return err
+			}
+
+			manifest, err := advisory.ReadExportManifest(manifestPath)
+			if err != nil {
+				return err
+			}
+
+			if err := manifest.VerifyFile(exportPath); err != nil {
+				return err
+			}
+
+			fmt.Printf("✅ %s is verified\n", exportPath)
+			return nil
+		},
+	}
+
+	p.addFlagsTo(cmd)
+	return cmd
+}
+
+type verifyExportParams struct {
+	manifest string
+	bundle   string
+	verificationParams
+}
+
+func (p *verifyExportParams) addFlagsTo(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&p.manifest, "manifest", "", fmt.Sprintf("path to the export's manifest (default: the export's path with %q appended)", advisory.ExportManifestSuffix))
+	cmd.Flags().StringVar(&p.bundle, "bundle", "", fmt.Sprintf("path to the Sigstore bundle of the manifest's signature (default: the manifest's path with %q appended)", advisory.ExportBundleSuffix))
+	p.verificationParams.addFlagsTo(cmd)
+}
+
+type verificationParams struct {
+	key                   string
+	certificateIdentity   string
+	certificateOIDCIssuer string
+}
+
+func (p *verificationParams) addFlagsTo(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&p.key, "key", "", "path (or KMS URI) of the cosign public key the manifest must have been signed with, instead of keyless verification")
+	cmd.Flags().StringVar(&p.certificateIdentity, "certificate-identity", "", "identity the manifest must have been signed by, e.g. a GitHub Actions workflow URL")
+	cmd.Flags().StringVar(&p.certificateOIDCIssuer, "certificate-oidc-issuer", "https://token.actions.githubusercontent.com", "OIDC issuer of the signing identity")
+}
+
+func (p *verificationParams) verificationOptions() advisory.VerificationOptions {
+	return advisory.VerificationOptions{
+		Key:                   p.key,
+		CertificateIdentity:   p.certificateIdentity,
+		CertificateOIDCIssuer: p.certificateOIDCIssuer,
+	}
+}
+
+func addSignExportFlag(val *bool, cmd *cobra.Command) {
+	cmd.Flags().BoolVar(val, "sign", false, "write a manifest of the output's digest and sign it with cosign (keyless), recording the signature in the Rekor transparency log; requires --output and cosign")
+}
+
+// signExport writes a manifest for the exported file at the given path, and
+// signs it.
+func signExport(exportPath string) error {
+	return signExportFiles(exportPath+advisory.ExportManifestSuffix, exportPath)
+}
+
+// signExportFiles writes a manifest of the exported files at the given paths to
+// manifestPath, and signs it.
+func signExportFiles(manifestPath string, paths ...string) error {
+	manifest, err := advisory.NewExportManifest(paths...)
+	if err != nil {
+		return err
+	}
+
+	if err := manifest.Write(manifestPath); err != nil {
+		return err
+	}
+
+	bundlePath, err := advisory.SignExportManifest(manifestPath, advisory.SigningOptions{})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Signed manifest written to %s (signature bundle: %s)\n", manifestPath, bundlePath)
+	return nil
+}
@@ -0,0 +1,148 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/wolfi-dev/wolfictl/pkg/advisory"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
+	"github.com/wolfi-dev/wolfictl/pkg/distro"
+	"github.com/wolfi-dev/wolfictl/pkg/index"
+	"gitlab.alpinelinux.org/alpine/go/repository"
+)
+
+func AdvisoryVerifyFixedVersions() *cobra.Command {
+	p := &verifyFixedVersionsParams{}
+	cmd := &cobra.Command{
+		Use:   "verify-fixed-versions",
+		Short: "verify that the fixed versions claimed by advisories were published",
+		Long: `verify that the fixed versions claimed by advisories were published
+
+Each fixed event's version must have shipped for each architecture, i.e. the
+fixed version or a later version of the package (or of a subpackage built from
+it) must be in the architecture's published APKINDEX. Architectures for which
+the package isn't published at all are skipped.
+
+Advisories that claim fixes that never shipped are listed, and the exit code is
+1 if there are any, so this can be used in CI. The same check is run by
+'wolfictl advisory validate --check-published'.`,
+		Example: `  wolfictl advisory verify-fixed-versions
+
+  wolfictl advisory verify-fixed-versions --arch x86_64 -r https://packages.wolfi.dev/os`,
+		SilenceErrors: true,
+		Args:          cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			advisoriesRepoDir, archs, packageRepositoryURL, err := p.resolve()
+			if err != nil {
+				return err
+			}
+
+			apkindexes, err := loadPublishedAPKINDEXes(archs, packageRepositoryURL)
+			if err != nil {
+				return err
+			}
+
+			advisoryCfgs, err := advisoryconfigs.NewIndex(rwos.DirFS(advisoriesRepoDir))
+			if err != nil {
+				return err
+			}
+
+			unpublished := advisory.FindUnpublishedFixes(advisoryCfgs, apkindexes)
+			if len(unpublished) > 0 {
+				for _, f := range unpublished {
+					fmt.Fprintf(os.Stderr, "❌ %s\n", f)
+				}
+				return &ExitCodeError{Code: 1}
+			}
+
+			fmt.Fprint(os.Stderr, "✅ all fixed versions have been published.\n")
+
+			return nil
+		},
+	}
+
+	p.addFlagsTo(cmd)
+	return cmd
+}
+
+type verifyFixedVersionsParams struct {
+	doNotDetectDistro bool
+
+	advisoriesRepoDir string
+
+	publishedParams
+}
+
+func (p *verifyFixedVersionsParams) addFlagsTo(cmd *cobra.Command) {
+	addNoDistroDetectionFlag(&p.doNotDetectDistro, cmd)
+	addAdvisoriesDirFlag(&p.advisoriesRepoDir, cmd)
+	p.publishedParams.addFlagsTo(cmd)
+}
+
+// resolve returns the advisories repo dir, architectures, and package
+// repository URL to use, falling back to the detected distro's.
+func (p *verifyFixedVersionsParams) resolve() (string, []string, string, error) {
+	advisoriesRepoDir := resolveAdvisoriesDir(p.advisoriesRepoDir)
+	archs, packageRepositoryURL := p.archs, p.packageRepositoryURL
+	if advisoriesRepoDir == "" || len(archs) == 0 || packageRepositoryURL == "" {
+		if p.doNotDetectDistro {
+			return "", nil, "", fmt.Errorf("advisories repo dir, architectures, and/or package repository URL were left unspecified")
+		}
+
+		d, err := distro.Detect()
+		if err != nil {
+			return "", nil, "", fmt.Errorf("advisories repo dir, architectures, and/or package repository URL were left unspecified, and distro auto-detection failed: %w", err)
+		}
+
+		if advisoriesRepoDir == "" {
+			advisoriesRepoDir = d.AdvisoriesRepoDir
+		}
+		if len(archs) == 0 {
+			archs = d.SupportedArchitectures
+		}
+		if packageRepositoryURL == "" {
+			packageRepositoryURL = d.APKRepositoryURL
+		}
+
+		_, _ = fmt.Fprint(os.Stderr, renderDetectedDistro(d))
+	}
+
+	return advisoriesRepoDir, archs, packageRepositoryURL, nil
+}
+
+// publishedParams are the flags for finding the published APKINDEXes that fixed
+// versions are checked against.
+type publishedParams struct {
+	archs                []string
+	packageRepositoryURL string
+}
+
+func (p *publishedParams) addFlagsTo(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVar(&p.archs, "arch", nil, "package architectures whose published APKINDEXes to check fixed versions against (default: the distro's supported architectures)")
+	cmd.Flags().StringVarP(&p.packageRepositoryURL, "package-repo-url", "r", "", "URL of the APK package repository (default: the distro's package repository)")
+}
+
+// loadPublishedAPKINDEXes loads the package repository's APKINDEX for each
+// architecture, keyed by architecture. It refuses APKINDEXes without any
+// packages, against which no fix would look published.
+func loadPublishedAPKINDEXes(archs []string, packageRepositoryURL string) (map[string]*repository.ApkIndex, error) {
+	if len(archs) == 0 {
+		return nil, fmt.Errorf("no architectures specified (use --arch)")
+	}
+
+	apkindexes := make(map[string]*repository.ApkIndex, len(archs))
+	for _, arch := range archs {
+		idx, err := index.Index(arch, packageRepositoryURL)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load APKINDEX for %s: %w", arch, err)
+		}
+		if len(idx.Packages) == 0 {
+			return nil, fmt.Errorf("APKINDEX for %s has no packages", arch)
+		}
+		apkindexes[arch] = idx
+	}
+
+	return apkindexes, nil
+}
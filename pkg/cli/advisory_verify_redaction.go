@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/wolfi-dev/wolfictl/pkg/advisory"
+	"github.com/wolfi-dev/wolfictl/pkg/configs"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
+	"github.com/wolfi-dev/wolfictl/pkg/distro"
+)
+
+func AdvisoryVerifyRedaction() *cobra.Command {
+	p := &verifyRedactionParams{}
+	cmd := &cobra.Command{
+		Use:   "verify-redaction <exported-file> ...",
+		Short: "Verify that exported advisory data contains no internal-only data",
+		Long: `Verify that exported advisory data contains no internal-only data.
+
+Advisory events can be marked as internal-only with "internal: true", and
+individual fields can be marked as internal-only by listing them under
+"internal-fields". Exporters omit this data. This command checks the given
+exported files (e.g. a secdb or CSV export) to prove that no internal-only
+impact or action statements leaked into them.`,
+		Example:       "wolfictl advisory verify-redaction security.json advisories.csv",
+		SilenceErrors: true,
+		Args:          cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(p.advisoriesRepoDirs) == 0 {
+				if p.doNotDetectDistro {
+					return fmt.Errorf("no advisories repo dir specified")
+				}
+
+				d, err := distro.Detect()
+				if err != nil {
+					return fmt.Errorf("no advisories repo dir specified, and distro auto-detection failed: %w", err)
+				}
+
+				p.advisoriesRepoDirs = append(p.advisoriesRepoDirs, d.AdvisoriesRepoDir)
+				_, _ = fmt.Fprint(os.Stderr, renderDetectedDistro(d))
+			}
+
+			indices := make([]*configs.Index[advisoryconfigs.Document], 0, len(p.advisoriesRepoDirs))
+			for _, dir := range p.advisoriesRepoDirs {
+				index, err := advisoryconfigs.NewIndex(rwos.DirFS(dir))
+				if err != nil {
+					return fmt.Errorf("unable to index advisory configs for directory %q: %w", dir, err)
+				}
+
+				indices = append(indices, index)
+			}
+
+			leaked := false
+			for _, file := range args {
+				artifact, err := os.ReadFile(file)
+				if err != nil {
+					return fmt.Errorf("unable to read exported file: %w", err)
+				}
+
+				leaks := advisory.VerifyRedaction(artifact, indices)
+				for _, leak := range leaks {
+					fmt.Fprintf(os.Stderr, "❌ %s: %s\n", file, leak)
+				}
+				if len(leaks) > 0 {
+					leaked = true
+				}
+			}
+
+			if leaked {
+				return &ExitCodeError{Code: 1}
+			}
+
+			fmt.Fprint(os.Stderr, "✅ no internal-only advisory data found.\n")
+
+			return nil
+		},
+	}
+
+	p.addFlagsTo(cmd)
+	return cmd
+}
+
+type verifyRedactionParams struct {
+	doNotDetectDistro bool
+
+	advisoriesRepoDirs []string
+}
+
+func (p *verifyRedactionParams) addFlagsTo(cmd *cobra.Command) {
+	addNoDistroDetectionFlag(&p.doNotDetectDistro, cmd)
+
+	cmd.Flags().StringSliceVarP(&p.advisoriesRepoDirs, "advisories-repo-dir", "a", nil, "directory containing an advisories repository")
+}
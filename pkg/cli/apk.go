@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -21,6 +22,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/wolfi-dev/wolfictl/pkg/index"
+	"github.com/wolfi-dev/wolfictl/pkg/trust"
 	"gitlab.alpinelinux.org/alpine/go/repository"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/api/iterator"
@@ -86,7 +88,15 @@ func Apk() *cobra.Command {
 				return fmt.Errorf("GET %s (%d): %s", url, resp.StatusCode, b)
 			}
 
-			pkg, err := repository.ParsePackage(resp.Body)
+			b, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return err
+			}
+			if err := trust.EnforceAPK(url, b); err != nil {
+				return err
+			}
+
+			pkg, err := repository.ParsePackage(bytes.NewReader(b))
 			if err != nil {
 				return err
 			}
@@ -97,6 +107,7 @@ func Apk() *cobra.Command {
 	}
 	cmd.Flags().StringVar(&arch, "arch", "x86_64", "arch of package to get")
 	cmd.Flags().StringVar(&repo, "repo", "wolfi", "repo to get packages from")
+	cmd.AddCommand(ApkDeps())
 	return cmd
 }
 
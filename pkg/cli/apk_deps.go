@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wolfi-dev/wolfictl/pkg/apk"
+	"github.com/wolfi-dev/wolfictl/pkg/index"
+	"gitlab.alpinelinux.org/alpine/go/repository"
+)
+
+func ApkDeps() *cobra.Command {
+	var arch string
+	var repoNames []string
+	var outputJSON bool
+	cmd := &cobra.Command{
+		Use:         "deps <path/to/package.apk | package-name>",
+		Annotations: pagerAnnotations,
+		Short:       "Print the resolved dependency tree of a package",
+		Long: `Print the resolved dependency tree of a package.
+
+Dependencies are resolved using APKINDEX data from the given repositories, the
+same way apk would, including dependencies (like "so:libc.so.6") that are
+satisfied by another package's provides. When more than one package could
+satisfy a dependency, all of them are listed. Cycles are detected and marked.
+
+The package can be given by name, or as a path to a local APK file.`,
+		Example: `  wolfictl apk deps curl
+
+  wolfictl apk deps ./packages/x86_64/crane-0.15.2-r0.apk --repo wolfi --json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			r := apk.NewResolver()
+			for _, repo := range repoNames {
+				// Map a friendly string like "wolfi" to its repo URL.
+				if got, found := repos[repo]; found {
+					repo = got
+				}
+
+				idx, err := index.Index(arch, repo)
+				if err != nil {
+					return err
+				}
+				r.AddIndex(repo, idx)
+			}
+
+			var tree *apk.DependencyNode
+			if strings.HasSuffix(args[0], ".apk") {
+				f, err := os.Open(args[0])
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+
+				pkg, err := repository.ParsePackage(f)
+				if err != nil {
+					return fmt.Errorf("unable to parse apk file: %w", err)
+				}
+
+				tree = r.TreeOf(apk.RepositoryPackage{Package: pkg})
+			} else {
+				var err error
+				tree, err = r.Tree(args[0])
+				if err != nil {
+					return err
+				}
+			}
+
+			if outputJSON {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(tree)
+			}
+
+			fmt.Println(renderDependencyTree(tree))
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&arch, "arch", "x86_64", "arch of packages to resolve")
+	cmd.Flags().StringSliceVar(&repoNames, "repo", []string{"wolfi"}, "repos to resolve packages from")
+	cmd.Flags().BoolVar(&outputJSON, "json", false, "print the dependency tree as JSON")
+	return cmd
+}
+
+func renderDependencyTree(root *apk.DependencyNode) string {
+	lines := []string{fmt.Sprintf("%s %s", root.Package, root.Version)}
+	lines = append(lines, renderDependencyNodes(root.Dependencies, "")...)
+	return strings.Join(lines, "\n")
+}
+
+func renderDependencyNodes(nodes []*apk.DependencyNode, prefix string) []string {
+	var lines []string
+	for i, n := range nodes {
+		stem, childPrefix := "├── ", "│   "
+		if i == len(nodes)-1 {
+			stem, childPrefix = "└── ", "    "
+		}
+
+		lines = append(lines, prefix+stem+renderDependencyNode(n))
+		lines = append(lines, renderDependencyNodes(n.Dependencies, prefix+childPrefix)...)
+	}
+	return lines
+}
+
+func renderDependencyNode(n *apk.DependencyNode) string {
+	if n.Unresolved {
+		return fmt.Sprintf("%s ❌ unresolved", n.Dependency)
+	}
+
+	s := fmt.Sprintf("%s %s", n.Package, n.Version)
+	if n.Dependency != n.Package {
+		s = fmt.Sprintf("%s → %s", n.Dependency, s)
+	}
+	if len(n.Satisfiers) > 0 {
+		s += styleSubtle.Render(fmt.Sprintf(" (also satisfied by: %s)", strings.Join(n.Satisfiers, ", ")))
+	}
+
+	switch {
+	case n.Cycle:
+		s += " 🔁 cycle"
+	case n.Repeated:
+		s += styleSubtle.Render(" (see above)")
+	}
+
+	return s
+}
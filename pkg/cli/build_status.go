@@ -0,0 +1,199 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v50/github"
+	"github.com/savioxavier/termlink"
+	"github.com/spf13/cobra"
+	"github.com/wolfi-dev/wolfictl/pkg/buildstatus"
+	"github.com/wolfi-dev/wolfictl/pkg/gh"
+	wgit "github.com/wolfi-dev/wolfictl/pkg/git"
+	http2 "github.com/wolfi-dev/wolfictl/pkg/http"
+	"github.com/wolfi-dev/wolfictl/pkg/index"
+	"golang.org/x/oauth2"
+	"golang.org/x/time/rate"
+)
+
+func Build() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "build",
+		DisableAutoGenTag: true,
+		SilenceUsage:      true,
+		Short:             "Commands for inspecting package builds",
+	}
+
+	cmd.AddCommand(
+		BuildStatus(),
+	)
+
+	return cmd
+}
+
+type buildStatusParams struct {
+	ref           string
+	repo          string
+	archs         []string
+	repositoryURL string
+	outputJSON    bool
+}
+
+func BuildStatus() *cobra.Command {
+	p := &buildStatusParams{}
+	cmd := &cobra.Command{
+		Use:               "status <package>",
+		DisableAutoGenTag: true,
+		SilenceUsage:      true,
+		Short:             "Show whether a revision of a package has been built and published for each architecture",
+		Long: `Show whether a revision of a package has been built and published for each architecture
+
+The package version is read from the package's melange config at the given ref.
+For each architecture, the build status comes from the CI check runs reported
+to GitHub for the ref, and the publish status from whether that version is in
+the architecture's APKINDEX. The package is live once it's been built and
+published everywhere.
+
+Requires a GITHUB_TOKEN environment variable.`,
+		Example: `  wolfictl build status crane --ref 3f2c9a1
+
+  wolfictl build status crane --ref 3f2c9a1 --arch x86_64 --json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if p.ref == "" {
+				return fmt.Errorf("a --ref is required")
+			}
+			if os.Getenv("GITHUB_TOKEN") == "" {
+				return fmt.Errorf("no GITHUB_TOKEN token found")
+			}
+
+			s, err := buildStatus(cmd.Context(), args[0], p)
+			if err != nil {
+				return err
+			}
+
+			if p.outputJSON {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(s)
+			}
+
+			fmt.Println(renderBuildStatus(s))
+			return nil
+		},
+	}
+
+	p.addFlagsTo(cmd)
+	return cmd
+}
+
+func (p *buildStatusParams) addFlagsTo(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&p.ref, "ref", "", "commit SHA (or branch or tag) of the package's source repository to check")
+	cmd.Flags().StringVar(&p.repo, "repo", "https://github.com/wolfi-dev/os", "GitHub repository containing the package's melange config")
+	cmd.Flags().StringSliceVar(&p.archs, "arch", []string{"x86_64", "aarch64"}, "architectures to check")
+	cmd.Flags().StringVarP(&p.repositoryURL, "repository", "r", "https://packages.wolfi.dev/os", "URL of the APK repository the package is published to")
+	cmd.Flags().BoolVar(&p.outputJSON, "json", false, "print the status as JSON")
+}
+
+func buildStatus(ctx context.Context, pkg string, p *buildStatusParams) (*buildstatus.Status, error) {
+	logger := log.New(log.Writer(), "wolfictl build status: ", log.LstdFlags|log.Lmsgprefix)
+
+	gitURL, err := wgit.ParseGitURL(p.repo)
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := buildstatus.FetchVersion(ctx, &http.Client{Timeout: 30 * time.Second}, p.repo, p.ref, pkg)
+	if err != nil {
+		return nil, err
+	}
+
+	ts := oauth2.StaticTokenSource(
+		&oauth2.Token{AccessToken: os.Getenv("GITHUB_TOKEN")},
+	)
+	ghclient := &http2.RLHTTPClient{
+		Client: oauth2.NewClient(context.Background(), ts),
+
+		// 1 request every (n) second(s) to avoid DOS'ing server. https://docs.github.com/en/rest/guides/best-practices-for-integrators?apiVersion=2022-11-28#dealing-with-secondary-rate-limits
+		Ratelimiter: rate.NewLimiter(rate.Every(5*time.Second), 1),
+	}
+
+	gitOpts := gh.GitOptions{
+		GithubClient: github.NewClient(ghclient.Client),
+		Logger:       logger,
+	}
+
+	checkRuns, err := gitOpts.ListCheckRuns(ctx, gitURL.Organisation, gitURL.Name, p.ref)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list check runs for %s: %w", p.ref, err)
+	}
+
+	runs := make([]buildstatus.CheckRun, 0, len(checkRuns))
+	for _, r := range checkRuns {
+		runs = append(runs, buildstatus.CheckRun{
+			Name:       r.GetName(),
+			Status:     r.GetStatus(),
+			Conclusion: r.GetConclusion(),
+			URL:        r.GetHTMLURL(),
+			Started:    r.GetStartedAt().Time,
+		})
+	}
+
+	published := make(map[string]bool)
+	for _, arch := range p.archs {
+		idx, err := index.Index(arch, p.repositoryURL)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load APKINDEX for %s: %w", arch, err)
+		}
+		published[arch] = buildstatus.IsPublished(idx, pkg, version)
+	}
+
+	s := buildstatus.Aggregate(p.ref, pkg, version, p.archs, runs, published)
+	return &s, nil
+}
+
+func renderBuildStatus(s *buildstatus.Status) string {
+	lines := []string{
+		fmt.Sprintf("%s %s (%s)", s.Package, s.Version, s.Ref),
+		"",
+	}
+
+	for _, a := range s.Archs {
+		build := string(a.Build)
+		if termSupportsHyperlinks && a.BuildURL != "" {
+			build = termlink.Link(build, a.BuildURL)
+		}
+		switch a.Build {
+		case buildstatus.StateSucceeded:
+			build = styleLow.Render(build)
+		case buildstatus.StateFailed:
+			build = styleCritical.Render(build)
+		case buildstatus.StateRunning:
+			build = styleMedium.Render(build)
+		default:
+			build = styleSubtle.Render(build)
+		}
+
+		published := styleSubtle.Render("not published")
+		if a.Published {
+			published = styleLow.Render("published")
+		}
+
+		lines = append(lines, fmt.Sprintf("  %-8s build %s, %s", a.Arch, build, published))
+	}
+
+	lines = append(lines, "")
+	if s.Live() {
+		lines = append(lines, styleLow.Render("live on all architectures"))
+	} else {
+		lines = append(lines, styleMedium.Render("not yet live on all architectures"))
+	}
+
+	return strings.Join(lines, "\n")
+}
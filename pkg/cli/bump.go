@@ -5,14 +5,14 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"chainguard.dev/melange/pkg/build"
 	"github.com/spf13/cobra"
+	"github.com/wolfi-dev/wolfictl/pkg/yamledit"
 )
 
-const epochPattern = `epoch: %d`
-
 type bumpOptions struct {
 	repoDir string
 	epoch   bool
@@ -137,37 +137,24 @@ func bumpEpoch(opts bumpOptions, path string) error {
 		return nil
 	}
 
-	original, err := os.Open(path)
+	original, err := os.ReadFile(path)
 	if err != nil {
 		return fmt.Errorf("opening config file: %w", err)
 	}
 
-	scanner := bufio.NewScanner(original)
-	scanner.Split(bufio.ScanLines)
-	newFile := []string{}
-	found := false
-	old := fmt.Sprintf(epochPattern, cfg.Package.Epoch)
-	for scanner.Scan() {
-		if strings.TrimSpace(scanner.Text()) == old {
-			found = true
-			newFile = append(
-				newFile, strings.ReplaceAll(
-					scanner.Text(), old, fmt.Sprintf(epochPattern, cfg.Package.Epoch+1),
-				),
-			)
-		} else {
-			newFile = append(newFile, scanner.Text())
-		}
+	// Only the epoch changes, so the rest of the file is left exactly as it was
+	// rather than being reformatted.
+	updated, err := yamledit.ReplaceScalar(original, strconv.FormatUint(cfg.Package.Epoch+1, 10), "package", "epoch")
+	if err != nil {
+		return fmt.Errorf("unable to find epoch tag in yaml config: %w", err)
 	}
-	original.Close()
 
-	if !found {
-		return fmt.Errorf("unable to find epoch tag in yaml config")
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("checking file permissions of %s: %w", path, err)
 	}
 
-	if err := os.WriteFile(
-		path, []byte(strings.Join(newFile, "\n")+"\n"), os.FileMode(0o644),
-	); err != nil {
+	if err := os.WriteFile(path, updated, info.Mode()); err != nil {
 		return fmt.Errorf("writing %s: %w", path, err)
 	}
 
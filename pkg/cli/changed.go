@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wolfi-dev/wolfictl/pkg/git"
+	"github.com/wolfi-dev/wolfictl/pkg/melange"
+)
+
+const (
+	defaultChangedSinceRef = "origin/main"
+
+	// envVarNameForBaseRef is set by GitHub Actions for pull_request workflows.
+	envVarNameForBaseRef = "GITHUB_BASE_REF"
+)
+
+// changedParams lets a repo-wide command restrict its work to the files changed
+// relative to a git ref, so that PR CI only pays for what the PR touches.
+type changedParams struct {
+	changedSince string
+	onlyChanged  bool
+}
+
+func (p *changedParams) addFlagsTo(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&p.changedSince, "changed-since", "", "only operate on files changed since the merge base of this git ref and HEAD")
+	cmd.Flags().BoolVar(&p.onlyChanged, "only-changed", false, fmt.Sprintf("only operate on files changed on the current branch (same as --changed-since origin/$%s, or --changed-since %s if that's not set)", envVarNameForBaseRef, defaultChangedSinceRef))
+}
+
+func (p changedParams) ref() string {
+	if p.changedSince != "" {
+		return p.changedSince
+	}
+
+	if p.onlyChanged {
+		if base := os.Getenv(envVarNameForBaseRef); base != "" {
+			return "origin/" + base
+		}
+
+		return defaultChangedSinceRef
+	}
+
+	return ""
+}
+
+// changedConfigFiles returns the YAML files at the top level of dir that
+// have changed and still exist. The returned slice is never nil when ok is
+// true. If the user didn't ask to restrict work to changed files, ok is false.
+func (p changedParams) changedConfigFiles(dir string) (files []string, ok bool, err error) {
+	ref := p.ref()
+	if ref == "" {
+		return nil, false, nil
+	}
+
+	changed, err := git.ChangedFiles(dir, ref)
+	if err != nil {
+		return nil, false, fmt.Errorf("unable to determine changed files: %w", err)
+	}
+
+	files = []string{}
+	for _, f := range changed {
+		if strings.Contains(f, "/") || filepath.Ext(f) != ".yaml" {
+			continue
+		}
+
+		if _, err := os.Stat(filepath.Join(dir, f)); err != nil {
+			// e.g. the file was deleted
+			continue
+		}
+
+		files = append(files, f)
+	}
+
+	return files, true, nil
+}
+
+// changedPackageConfigs returns the paths of the melange configs at the top
+// level of the distro repo in dir that have changed, sorted. Only the changed
+// files are read. If the user didn't ask to restrict work to changed files, ok
+// is false.
+func (p changedParams) changedPackageConfigs(dir string) (paths []string, ok bool, err error) {
+	files, ok, err := p.changedConfigFiles(dir)
+	if err != nil || !ok {
+		return nil, ok, err
+	}
+
+	pkgs, err := melange.ReadPackagesFromRepo(dir, files)
+	if err != nil {
+		return nil, false, err
+	}
+
+	paths = []string{}
+	for _, pkg := range pkgs {
+		paths = append(paths, filepath.Join(dir, pkg.Filename))
+	}
+	sort.Strings(paths)
+
+	return paths, true, nil
+}
@@ -13,6 +13,7 @@ func CheckUpdate() *cobra.Command {
 	o := checks.CheckUpdateOptions{
 		Logger: log.New(log.Writer(), "wolfictl check update: ", log.LstdFlags|log.Lmsgprefix),
 	}
+	changed := changedParams{}
 
 	cmd := &cobra.Command{
 		Use:               "update",
@@ -21,11 +22,20 @@ func CheckUpdate() *cobra.Command {
 		SilenceErrors:     true,
 		Short:             "Check Wolfi update configs",
 		RunE: func(cmd *cobra.Command, files []string) error {
+			changedFiles, ok, err := changed.changedConfigFiles(o.Dir)
+			if err != nil {
+				return err
+			}
+			if ok {
+				files = append(files, changedFiles...)
+			}
+
 			return o.CheckUpdates(files)
 		},
 	}
 
 	checkUpdateFlags(cmd, &o)
+	changed.addFlagsTo(cmd)
 
 	return cmd
 }
@@ -1,22 +1,66 @@
 package cli
 
 import (
+	"fmt"
+
 	"github.com/spf13/cobra"
 	"sigs.k8s.io/release-utils/version"
 )
 
 func New() *cobra.Command {
+	var noPager bool
+	var trustPolicy string
+	var vulnLinksFile string
+	var vulnLinks map[string]string
 	cmd := &cobra.Command{
 		Use:               "wolfictl",
 		DisableAutoGenTag: true,
 		SilenceUsage:      true,
 		Short:             "A CLI helper for developing Wolfi",
+		Long: fmt.Sprintf(`A CLI helper for developing Wolfi
+
+When stdout is a terminal, the output of commands that list or show data is
+piped into a pager (like git does). The pager is taken from the %s or
+PAGER environment variables, defaulting to less. Set either to an empty string,
+or use --no-pager, to disable paging.
+
+If a trust policy is given (via --trust-policy or the %s environment
+variable), every APKINDEX and apk file downloaded from a package repository
+must be signed by a key that the policy trusts for that repository.
+
+Vulnerability IDs are hyperlinked in terminals that support it. Where they link
+to can be configured per ID prefix via --vuln-link, or a file given via
+--vuln-links or the %s environment variable.`, envVarNameForPager, envVarNameForTrustPolicy, envVarNameForVulnLinks),
+		PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
+			if err := loadTrustPolicy(trustPolicy); err != nil {
+				return err
+			}
+
+			if err := loadVulnLinks(vulnLinksFile, vulnLinks); err != nil {
+				return err
+			}
+
+			if !noPager {
+				startPager(cmd)
+			}
+
+			return nil
+		},
 	}
+	addNoPagerFlag(&noPager, cmd)
+	addTrustPolicyFlag(&trustPolicy, cmd)
+	addVulnLinkFlags(&vulnLinksFile, &vulnLinks, cmd)
+
+	registerPagerFinalizer()
 
 	cmd.AddCommand(
 		Advisory(),
+		Advise(),
 		Bump(),
+		Build(),
 		Gh(),
+		Gate(),
+		Image(),
 		Apk(),
 		Index(),
 		GenerateIndex(),
@@ -27,9 +71,11 @@ func New() *cobra.Command {
 		Check(),
 		Lint(),
 		Ls(),
+		SBOM(),
 		Scan(),
 		Update(),
 		VEX(),
+		Vuln(),
 		version.Version(),
 	)
 
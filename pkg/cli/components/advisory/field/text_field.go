@@ -7,6 +7,8 @@ import (
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sahilm/fuzzy"
+	"github.com/samber/lo"
 	"github.com/wolfi-dev/wolfictl/pkg/advisory"
 	"github.com/wolfi-dev/wolfictl/pkg/cli/styles"
 )
@@ -23,6 +25,8 @@ var ErrValueNotInAllowedSet = errors.New("value not in allowed set")
 
 type TextField struct {
 	allowedValues  []string
+	fuzzyMatching  bool
+	allowOther     bool
 	requestUpdater func(value string, req advisory.Request) advisory.Request
 
 	input                 textinput.Model
@@ -50,6 +54,15 @@ type TextFieldConfiguration struct {
 	// as suggestions when the user starts typing.
 	AllowedValues []string
 
+	// FuzzyMatching, if true, suggests the allowed values that fuzzily match the
+	// entered text (e.g. "pyt3" matches "python-3.11"), best match first, rather
+	// than only the values that start with it.
+	FuzzyMatching bool
+
+	// AllowOtherValues, if true, treats AllowedValues only as suggestions: the
+	// entered text itself is offered as the first suggestion.
+	AllowOtherValues bool
+
 	// DefaultSuggestion is the value that is shown as a suggestion when the user
 	// hasn't entered anything.
 	DefaultSuggestion string
@@ -80,6 +93,8 @@ func NewTextField(cfg TextFieldConfiguration) TextField {
 		input:             t,
 		requestUpdater:    cfg.RequestUpdater,
 		allowedValues:     cfg.AllowedValues,
+		fuzzyMatching:     cfg.FuzzyMatching,
+		allowOther:        cfg.AllowOtherValues,
 		emptyValueHelpMsg: cfg.EmptyValueHelpMsg,
 		noMatchHelpMsg:    cfg.NoMatchHelpMsg,
 		validationRules:   cfg.ValidationRules,
@@ -112,7 +127,7 @@ func (f TextField) UpdateRequest(req advisory.Request) advisory.Request {
 }
 
 func (f TextField) SubmitValue() (Field, error) {
-	if f.usingSuggestions() && f.noSuggestions() {
+	if f.usingSuggestions() && f.noSuggestions() && !f.allowOther {
 		return nil, ErrValueNotAccepted{
 			Value:  f.input.Value(),
 			Reason: ErrValueNotInAllowedSet,
@@ -213,6 +228,17 @@ func (f TextField) computeSuggestions() []string {
 		return nil
 	}
 
+	if f.fuzzyMatching {
+		matches := fuzzy.Find(v, f.allowedValues)
+
+		suggestions := make([]string, 0, len(matches))
+		for _, m := range matches {
+			suggestions = append(suggestions, m.Str)
+		}
+
+		return f.withEnteredValue(suggestions)
+	}
+
 	var suggestions []string
 
 	for _, allowedValue := range f.allowedValues {
@@ -221,7 +247,18 @@ func (f TextField) computeSuggestions() []string {
 		}
 	}
 
-	return suggestions
+	return f.withEnteredValue(suggestions)
+}
+
+// withEnteredValue puts the entered value first among the suggestions if other
+// values are allowed, so that a close match isn't picked by accident.
+func (f TextField) withEnteredValue(suggestions []string) []string {
+	v := f.input.Value()
+	if !f.allowOther || (len(suggestions) > 0 && suggestions[0] == v) {
+		return suggestions
+	}
+
+	return append([]string{v}, lo.Without(suggestions, v)...)
 }
 
 func (f TextField) suggestionWindowEnd() int {
@@ -261,7 +298,7 @@ func (f TextField) IsDone() bool {
 }
 
 func (f TextField) Value() string {
-	if !f.usingSuggestions() {
+	if !f.usingSuggestions() || (f.allowOther && f.noSuggestions()) {
 		return f.input.Value()
 	}
 
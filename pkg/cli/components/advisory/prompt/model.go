@@ -19,6 +19,11 @@ type Model struct {
 	allowedVulnerabilitiesFunc func(packageName string) []string
 	allowedFixedVersionsFunc   func(packageName string) []string
 
+	allowUnlistedVulnerabilities bool
+
+	templates     advisory.Templates
+	templateAsked bool
+
 	// input/output data
 	Request advisory.Request
 
@@ -38,6 +43,7 @@ func (m Model) newPackageFieldConfig() field.TextFieldConfiguration {
 			return req
 		},
 		AllowedValues:     allowedValues,
+		FuzzyMatching:     true,
 		EmptyValueHelpMsg: "Type to find a package.",
 		NoMatchHelpMsg:    "No matching package found.",
 		ValidationRules: []field.TextValidationRule{
@@ -70,7 +76,33 @@ func (m Model) newVulnerabilityFieldConfig() field.TextFieldConfiguration {
 			field.NotEmpty,
 			ValidCVEID,
 		},
-		AllowedValues: allowedValues,
+		AllowedValues:    allowedValues,
+		FuzzyMatching:    true,
+		AllowOtherValues: m.allowUnlistedVulnerabilities,
+		NoMatchHelpMsg:   "No matching vulnerability found.",
+	}
+}
+
+// noTemplate is the template field option for entering every field by hand.
+const noTemplate = "(none)"
+
+func (m Model) newTemplateFieldConfig() field.ListFieldConfiguration {
+	return field.ListFieldConfiguration{
+		Prompt:  "Template: ",
+		Options: append([]string{noTemplate}, m.templates.Names()...),
+		RequestUpdater: func(value string, req advisory.Request) advisory.Request {
+			t, ok := m.templates[value]
+			if !ok {
+				return req
+			}
+
+			// The status hasn't been entered yet, so applying can't fail.
+			updated, err := t.Apply(req)
+			if err != nil {
+				return req
+			}
+			return updated
+		},
 	}
 }
 
@@ -136,6 +168,15 @@ type Configuration struct {
 	AllowedPackagesFunc        func() []string
 	AllowedVulnerabilitiesFunc func(packageName string) []string
 	AllowedFixedVersionsFunc   func(packageName string) []string
+
+	// AllowUnlistedVulnerabilities lets a vulnerability ID be entered that's not
+	// among those from AllowedVulnerabilitiesFunc, which are then only
+	// suggestions.
+	AllowUnlistedVulnerabilities bool
+
+	// Templates are offered before the status is prompted for, to pre-fill the
+	// remaining fields.
+	Templates advisory.Templates
 }
 
 func New(config Configuration) Model {
@@ -145,6 +186,10 @@ func New(config Configuration) Model {
 		allowedPackagesFunc:        config.AllowedPackagesFunc,
 		allowedVulnerabilitiesFunc: config.AllowedVulnerabilitiesFunc,
 		allowedFixedVersionsFunc:   config.AllowedFixedVersionsFunc,
+
+		allowUnlistedVulnerabilities: config.AllowUnlistedVulnerabilities,
+
+		templates: config.Templates,
 	}
 
 	m, _ = m.addMissingFields()
@@ -169,6 +214,13 @@ func (m Model) addMissingFields() (Model, bool) {
 		return m, true
 	}
 
+	if m.Request.Status == "" && len(m.templates) > 0 && !m.templateAsked {
+		m.templateAsked = true
+		f := field.NewListField(m.newTemplateFieldConfig())
+		m.fields = append(m.fields, f)
+		return m, true
+	}
+
 	if m.Request.Status == "" {
 		f := field.NewListField(m.newStatusFieldConfig())
 		m.fields = append(m.fields, f)
@@ -0,0 +1,135 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wolfi-dev/wolfictl/pkg/scan"
+)
+
+func Gate() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "gate",
+		Short: "Subcommands for working with vulnerability gate policies",
+	}
+
+	cmd.AddCommand(GateSimulate())
+	return cmd
+}
+
+func GateSimulate() *cobra.Command {
+	p := &gateSimulateParams{}
+	cmd := &cobra.Command{
+		Use:   "simulate",
+		Short: "Replay past scan results against a proposed gate policy",
+		Long: `Replay past scan results against a proposed gate policy.
+
+Scan results are recorded with "wolfictl scan --history <file>". Each recorded
+result is evaluated against the --policy, and the number of results that would
+have failed is reported. If a --baseline policy (such as the one currently
+enforced) is given, the results whose outcome would change are listed too.
+
+A policy is a YAML file like:
+
+    # Fail if any finding is at least this severe.
+    fail-on: High
+    # Fail if there are more findings than this, of any severity.
+    max-findings: 20
+    # Don't count findings that have no fix available.
+    only-fixable: true
+    # Never count findings for these vulnerabilities (IDs or aliases).
+    ignore:
+      - CVE-2023-12345`,
+		Example:       `  wolfictl gate simulate --policy new-policy.yaml --baseline policy.yaml --history scan-history.jsonl`,
+		Args:          cobra.NoArgs,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			policy, err := scan.ReadGatePolicy(p.policy)
+			if err != nil {
+				return err
+			}
+
+			var baseline *scan.GatePolicy
+			if p.baseline != "" {
+				baseline, err = scan.ReadGatePolicy(p.baseline)
+				if err != nil {
+					return err
+				}
+			}
+
+			f, err := os.Open(p.history)
+			if err != nil {
+				return fmt.Errorf("unable to open scan history: %w", err)
+			}
+			defer f.Close()
+
+			records, err := scan.ReadHistory(f)
+			if err != nil {
+				return err
+			}
+			if len(records) == 0 {
+				return fmt.Errorf("no scan results found in %s", p.history)
+			}
+
+			fmt.Println(renderGateSimulation(scan.SimulateGate(*policy, baseline, records), baseline != nil))
+			return nil
+		},
+	}
+
+	p.addFlagsTo(cmd)
+	return cmd
+}
+
+type gateSimulateParams struct {
+	policy   string
+	baseline string
+	history  string
+}
+
+func (p *gateSimulateParams) addFlagsTo(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&p.policy, "policy", "", "path to the proposed gate policy")
+	cmd.Flags().StringVar(&p.baseline, "baseline", "", "path to a gate policy to compare against, such as the one currently enforced")
+	cmd.Flags().StringVar(&p.history, "history", "", "path to the scan history recorded with 'wolfictl scan --history'")
+	_ = cmd.MarkFlagRequired("policy")
+	_ = cmd.MarkFlagRequired("history")
+}
+
+func renderGateSimulation(sim scan.GateSimulation, withBaseline bool) string {
+	percent := func(n int) string {
+		return fmt.Sprintf("%.1f%%", 100*float64(n)/float64(sim.Total))
+	}
+
+	lines := []string{
+		fmt.Sprintf("Replayed %d scan results", sim.Total),
+		fmt.Sprintf("  %d would fail the proposed policy (%s)", len(sim.Failed), percent(len(sim.Failed))),
+	}
+
+	renderResults := func(heading string, results []scan.GateSimulationResult) {
+		if len(results) == 0 {
+			return
+		}
+
+		lines = append(lines, "", heading)
+		for _, r := range results {
+			lines = append(lines, fmt.Sprintf(
+				"  %s %s: %s",
+				r.Record.Target,
+				styleSubtle.Render(r.Record.Timestamp.Format("2006-01-02")),
+				strings.Join(r.Result.Reasons, "; "),
+			))
+		}
+	}
+
+	if !withBaseline {
+		renderResults("Would fail:", sim.Failed)
+		return strings.Join(lines, "\n")
+	}
+
+	lines = append(lines, fmt.Sprintf("  %d fail the baseline policy (%s)", sim.BaselineFailed, percent(sim.BaselineFailed)))
+	renderResults("Would newly fail:", sim.NewlyFailing)
+	renderResults("Would newly pass (failed the baseline because of):", sim.NewlyPassing)
+
+	return strings.Join(lines, "\n")
+}
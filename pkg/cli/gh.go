@@ -13,6 +13,7 @@ func Gh() *cobra.Command {
 	cmd.AddCommand(
 		Release(),
 		Gc(),
+		Labels(),
 	)
 
 	return cmd
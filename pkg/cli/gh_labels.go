@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v50/github"
+	"github.com/spf13/cobra"
+	"github.com/wolfi-dev/wolfictl/pkg/gh"
+	wgit "github.com/wolfi-dev/wolfictl/pkg/git"
+	http2 "github.com/wolfi-dev/wolfictl/pkg/http"
+	"golang.org/x/oauth2"
+	"golang.org/x/time/rate"
+)
+
+func Labels() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "labels",
+		DisableAutoGenTag: true,
+		SilenceUsage:      true,
+		Short:             "Commands for managing GitHub labels",
+	}
+
+	cmd.AddCommand(
+		LabelsSync(),
+	)
+
+	return cmd
+}
+
+type labelsSyncParams struct {
+	configPath string
+	dryRun     bool
+}
+
+func LabelsSync() *cobra.Command {
+	p := &labelsSyncParams{}
+	cmd := &cobra.Command{
+		Use:               "sync [repository URL...]",
+		DisableAutoGenTag: true,
+		SilenceUsage:      true,
+		Short:             "Create or update the standard set of labels across repositories",
+		Long: `Create or update the standard set of labels across repositories
+
+Labels defined in the config are created if they're missing, and their color
+and description are updated if they differ. Other labels are left alone.
+
+If no repository URLs are given, the repositories listed in the config are used.
+
+Example config:
+
+repositories:
+  - https://github.com/wolfi-dev/os
+  - https://github.com/wolfi-dev/advisories
+labels:
+  - name: automated pr
+    color: ededed
+    description: Created by automation
+`,
+		Example: `  wolfictl gh labels sync --config labels.yaml`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := gh.ReadLabelsConfig(p.configPath)
+			if err != nil {
+				return err
+			}
+
+			repos := args
+			if len(repos) == 0 {
+				repos = cfg.Repositories
+			}
+			if len(repos) == 0 {
+				return fmt.Errorf("no repositories specified, either as arguments or in %s", p.configPath)
+			}
+
+			if !p.dryRun && os.Getenv("GITHUB_TOKEN") == "" {
+				return fmt.Errorf("no GITHUB_TOKEN token found")
+			}
+
+			return syncLabels(cmd.Context(), repos, cfg.Labels, p.dryRun)
+		},
+	}
+
+	cmd.Flags().StringVar(&p.configPath, "config", "labels.yaml", "path to the labels config file")
+	cmd.Flags().BoolVar(&p.dryRun, "dry-run", false, "print the changes that would be made without making them")
+
+	return cmd
+}
+
+func syncLabels(ctx context.Context, repos []string, labels []gh.Label, dryRun bool) error {
+	logger := log.New(log.Writer(), "wolfictl gh labels sync: ", log.LstdFlags|log.Lmsgprefix)
+
+	ts := oauth2.StaticTokenSource(
+		&oauth2.Token{AccessToken: os.Getenv("GITHUB_TOKEN")},
+	)
+	ghclient := &http2.RLHTTPClient{
+		Client: oauth2.NewClient(context.Background(), ts),
+
+		// 1 request every (n) second(s) to avoid DOS'ing server. https://docs.github.com/en/rest/guides/best-practices-for-integrators?apiVersion=2022-11-28#dealing-with-secondary-rate-limits
+		Ratelimiter: rate.NewLimiter(rate.Every(5*time.Second), 1),
+	}
+
+	gitOpts := gh.GitOptions{
+		GithubClient: github.NewClient(ghclient.Client),
+		Logger:       logger,
+	}
+
+	for _, repo := range repos {
+		gitURL, err := wgit.ParseGitURL(repo)
+		if err != nil {
+			return err
+		}
+
+		result, err := gitOpts.SyncLabels(ctx, gitURL.Organisation, gitURL.Name, labels, dryRun)
+		if err != nil {
+			return err
+		}
+
+		verb := "synced"
+		if dryRun {
+			verb = "would sync"
+		}
+		logger.Printf(
+			"%s %s/%s: created [%s], updated [%s], %d unchanged",
+			verb,
+			gitURL.Organisation,
+			gitURL.Name,
+			strings.Join(result.Created, ", "),
+			strings.Join(result.Updated, ", "),
+			len(result.Unchanged),
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,156 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/spf13/cobra"
+	"github.com/wolfi-dev/wolfictl/pkg/scan"
+)
+
+func Image() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "image",
+		Short: "Subcommands for working with container images",
+	}
+
+	cmd.AddCommand(ImageDiff())
+	return cmd
+}
+
+func ImageDiff() *cobra.Command {
+	p := &imageDiffParams{}
+	cmd := &cobra.Command{
+		Use:   "diff <ref-a> <ref-b>",
+		Short: "Compare the installed packages and vulnerabilities of two images",
+		Long: `Compare the installed packages and vulnerabilities of two images.
+
+Both images are pulled from their registries and scanned. The output lists the
+APK packages that were added, removed or changed in version going from the
+first image to the second, followed by the vulnerability findings the second
+image introduces and those it resolves.`,
+		Example: `  wolfictl image diff cgr.dev/chainguard/curl:latest-20230801 cgr.dev/chainguard/curl:latest
+
+  wolfictl image diff cgr.dev/chainguard/go@sha256:... cgr.dev/chainguard/go:latest --json`,
+		Args:          cobra.ExactArgs(2),
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			platform, err := v1.ParsePlatform(p.platform)
+			if err != nil {
+				return fmt.Errorf("invalid --platform: %w", err)
+			}
+
+			distro, err := parseDistroFlag(p.distro)
+			if err != nil {
+				return err
+			}
+
+			opts := scan.Options{
+				Catalogers:         p.catalogers,
+				DisabledCatalogers: p.disabledCatalogers,
+				Distro:             distro,
+			}
+
+			results := make([]*scan.Result, len(args))
+			progress := newScanProgress(len(args), p.quiet)
+			for i, arg := range args {
+				progress.next(i, arg)
+
+				ref, err := name.ParseReference(arg)
+				if err != nil {
+					return fmt.Errorf("invalid image reference %q: %w", arg, err)
+				}
+
+				results[i], err = scan.Image(ref, *platform, opts, remote.WithAuthFromKeychain(authn.DefaultKeychain), remote.WithContext(cmd.Context()))
+				if err != nil {
+					return err
+				}
+			}
+			progress.done()
+
+			d := scan.NewImageDiff(args[0], results[0], args[1], results[1])
+
+			if p.outputJSON {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(d)
+			}
+
+			fmt.Println(renderImageDiff(d))
+			return nil
+		},
+	}
+
+	p.addFlagsTo(cmd)
+	return cmd
+}
+
+type imageDiffParams struct {
+	platform           string
+	outputJSON         bool
+	catalogers         []string
+	disabledCatalogers []string
+	distro             string
+	quiet              bool
+}
+
+func (p *imageDiffParams) addFlagsTo(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&p.platform, "platform", "linux/amd64", "platform of the images to compare, for multi-platform images")
+	cmd.Flags().BoolVar(&p.outputJSON, "json", false, "print the differences as JSON")
+	addCatalogerFlagsTo(cmd, &p.catalogers, &p.disabledCatalogers)
+	addDistroFlagTo(cmd, &p.distro)
+	addQuietFlagTo(cmd, &p.quiet)
+}
+
+func renderImageDiff(d *scan.ImageDiff) string {
+	var lines []string
+
+	lines = append(lines, fmt.Sprintf("%s → %s", d.From, d.To), "")
+
+	lines = append(lines, "Packages:")
+	if len(d.AddedPackages)+len(d.RemovedPackages)+len(d.ChangedPackages) == 0 {
+		lines = append(lines, styleSubtle.Render("  no changes"))
+	}
+	for _, pkg := range d.AddedPackages {
+		lines = append(lines, fmt.Sprintf("  + %s %s", pkg.Name, pkg.Version))
+	}
+	for _, pkg := range d.RemovedPackages {
+		lines = append(lines, fmt.Sprintf("  - %s %s", pkg.Name, pkg.Version))
+	}
+	for _, c := range d.ChangedPackages {
+		lines = append(lines, fmt.Sprintf("  ~ %s %s → %s", c.Name, c.FromVersion, c.ToVersion))
+	}
+
+	lines = append(lines, "", "New vulnerabilities:")
+	lines = append(lines, renderFindingDeltas(d.NewFindings)...)
+
+	lines = append(lines, "", "Resolved vulnerabilities:")
+	lines = append(lines, renderFindingDeltas(d.ResolvedFindings)...)
+
+	return strings.Join(lines, "\n")
+}
+
+func renderFindingDeltas(findings []scan.CanaryFinding) []string {
+	if len(findings) == 0 {
+		return []string{styleSubtle.Render("  none")}
+	}
+
+	lines := make([]string, 0, len(findings))
+	for _, f := range findings {
+		lines = append(lines, fmt.Sprintf(
+			"  %s %s %s %s",
+			renderSeverity(f.Severity),
+			hyperlinkVulnerabilityID(f.Vulnerability),
+			f.Package,
+			styleSubtle.Render(fmt.Sprintf("%s (%s)", f.Version, f.Type)),
+		))
+	}
+
+	return lines
+}
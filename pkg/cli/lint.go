@@ -12,6 +12,7 @@ type lintOptions struct {
 	verbose   bool
 	list      bool
 	skipRules []string
+	changed   changedParams
 }
 
 func Lint() *cobra.Command {
@@ -32,6 +33,7 @@ func Lint() *cobra.Command {
 	cmd.Flags().BoolVarP(&o.verbose, "verbose", "v", false, "verbose output")
 	cmd.Flags().BoolVarP(&o.list, "list", "l", false, "prints the all of available rules and exits")
 	cmd.Flags().StringArrayVarP(&o.skipRules, "skip-rule", "", []string{}, "list of rules to skip")
+	o.changed.addFlagsTo(cmd)
 
 	cmd.AddCommand(LintYam())
 
@@ -39,7 +41,11 @@ func Lint() *cobra.Command {
 }
 
 func (o lintOptions) LintCmd() error {
-	linter := lint.New(o.makeLintOptions()...)
+	opts, err := o.makeLintOptions()
+	if err != nil {
+		return err
+	}
+	linter := lint.New(opts...)
 
 	// If the list flag is set, print the list of available rules and exit.
 	if o.list {
@@ -59,15 +65,25 @@ func (o lintOptions) LintCmd() error {
 	return nil
 }
 
-func (o lintOptions) makeLintOptions() []lint.Option {
+func (o lintOptions) makeLintOptions() ([]lint.Option, error) {
 	if len(o.args) == 0 {
 		// Lint the current directory by default.
 		o.args = []string{"."}
 	}
 
-	return []lint.Option{
+	opts := []lint.Option{
 		lint.WithPath(o.args[0]),
 		lint.WithVerbose(o.verbose),
 		lint.WithSkipRules(o.skipRules),
 	}
+
+	files, ok, err := o.changed.changedConfigFiles(o.args[0])
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		opts = append(opts, lint.WithFiles(files))
+	}
+
+	return opts, nil
 }
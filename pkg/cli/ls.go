@@ -18,6 +18,7 @@ func Ls() *cobra.Command {
 	p := &lsParams{}
 	cmd := &cobra.Command{
 		Use:           "ls [packages]",
+		Annotations:   pagerAnnotations,
 		Short:         "List distro packages (experimental)",
 		SilenceErrors: true,
 		Hidden:        true,
@@ -0,0 +1,141 @@
+package cli
+
+import (
+	"os"
+	"os/exec"
+	"sync"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+const envVarNameForPager = "WOLFICTL_PAGER"
+
+// annotationPager marks a command (via its Annotations) as one whose output
+// should be paged, such as commands that list or show data. Other commands,
+// including interactive ones, are never paged.
+const annotationPager = "wolfictl/pager"
+
+var pagerAnnotations = map[string]string{annotationPager: "true"}
+
+// scanPagerAnnotations marks a scan command as paged only for its tree output,
+// and not for machine-readable output formats (see addScanOutputFlagTo).
+var scanPagerAnnotations = map[string]string{annotationPager: scanOutputTree}
+
+// pagerFinalizerOnce guards registering stopPager as a cobra finalizer, which
+// would otherwise be registered again each time New is called.
+var pagerFinalizerOnce sync.Once
+
+// activePager is the pager that stdout is currently being piped through, if any.
+var activePager *pager
+
+type pager struct {
+	cmd    *exec.Cmd
+	w      *os.File
+	stdout *os.File
+}
+
+func addNoPagerFlag(val *bool, cmd *cobra.Command) {
+	cmd.PersistentFlags().BoolVar(val, "no-pager", false, "do not pipe output into a pager")
+}
+
+// resolvePager returns the pager command to use, taken from the
+// WOLFICTL_PAGER or PAGER environment variables, in that order, and otherwise
+// less, if it's installed. It returns an empty string if paging has been
+// disabled by setting either variable to an empty string or "cat".
+func resolvePager() string {
+	var pager string
+	if v, ok := os.LookupEnv(envVarNameForPager); ok {
+		pager = v
+	} else if v, ok := os.LookupEnv("PAGER"); ok {
+		pager = v
+	} else if _, err := exec.LookPath("less"); err == nil {
+		pager = "less"
+	}
+
+	if pager == "cat" {
+		return ""
+	}
+
+	return pager
+}
+
+// startPager starts piping stdout through a pager, like git does, when stdout
+// is a terminal. As with git, less is told to exit immediately if the output
+// fits on one screen, so short output behaves as if there were no pager.
+//
+// If the pager can't be started, output is written to stdout directly.
+func startPager(cmd *cobra.Command) {
+	if !pagesOutput(cmd) {
+		return
+	}
+
+	if !term.IsTerminal(int(os.Stdout.Fd())) {
+		return
+	}
+
+	pagerCommand := resolvePager()
+	if pagerCommand == "" {
+		return
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		return
+	}
+
+	c := exec.Command("sh", "-c", pagerCommand)
+	c.Stdin = r
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	c.Env = os.Environ()
+	if _, ok := os.LookupEnv("LESS"); !ok {
+		c.Env = append(c.Env, "LESS=FRX")
+	}
+
+	if err := c.Start(); err != nil {
+		_ = r.Close()
+		_ = w.Close()
+		return
+	}
+	_ = r.Close()
+
+	activePager = &pager{cmd: c, w: w, stdout: os.Stdout}
+	os.Stdout = w
+}
+
+// pagesOutput reports whether the command's output may be piped into a pager.
+// Besides "true", the pager annotation may name the only value of the command's
+// --output flag for which output is paged.
+func pagesOutput(cmd *cobra.Command) bool {
+	switch v := cmd.Annotations[annotationPager]; v {
+	case "":
+		return false
+	case "true":
+		return true
+	default:
+		f := cmd.Flags().Lookup("output")
+		return f != nil && f.Value.String() == v
+	}
+}
+
+// registerPagerFinalizer makes sure the pager is stopped once the command has
+// run, even if it returned an error.
+func registerPagerFinalizer() {
+	pagerFinalizerOnce.Do(func() {
+		cobra.OnFinalize(stopPager)
+	})
+}
+
+// stopPager restores stdout and waits for the user to exit the pager, if one
+// was started.
+func stopPager() {
+	if activePager == nil {
+		return
+	}
+
+	os.Stdout = activePager.stdout
+	_ = activePager.w.Close()
+	_ = activePager.cmd.Wait()
+	activePager = nil
+}
@@ -0,0 +1,39 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPagesOutput(t *testing.T) {
+	scanJSON := Scan()
+	require.NoError(t, scanJSON.Flags().Set("output", scanOutputJSON))
+	scanGitHub := ScanApko()
+	require.NoError(t, scanGitHub.Flags().Set("output", scanOutputGitHub))
+
+	cases := []struct {
+		name  string
+		cmd   *cobra.Command
+		paged bool
+	}{
+		{name: "advisory list", cmd: AdvisoryList(), paged: true},
+		{name: "advisory show", cmd: AdvisoryShow(), paged: true},
+		{name: "advisory create", cmd: AdvisoryCreate(), paged: false},
+		{name: "advisory validate", cmd: AdvisoryValidate(), paged: false},
+		{name: "scan", cmd: Scan(), paged: true},
+		{name: "scan --output json", cmd: scanJSON, paged: false},
+		{name: "scan apko", cmd: ScanApko(), paged: true},
+		{name: "scan apko --output github", cmd: scanGitHub, paged: false},
+		{name: "scan canary", cmd: ScanCanary(), paged: true},
+		{name: "scan annotate", cmd: ScanAnnotate(), paged: false},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.paged, pagesOutput(tt.cmd))
+		})
+	}
+}
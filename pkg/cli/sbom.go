@@ -0,0 +1,147 @@
+package cli
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+	"github.com/wolfi-dev/wolfictl/pkg/scan"
+)
+
+func SBOM() *cobra.Command {
+	p := &sbomParams{}
+	cmd := &cobra.Command{
+		Use:   "sbom <path/to/package.apk|url|package-name>",
+		Short: "Generate an SBOM of an apk file",
+		Long: `Generate an SBOM of an apk file
+
+The apk file's contents are cataloged just as 'wolfictl scan' catalogs them,
+and the SBOM is written as SPDX JSON, or as CycloneDX JSON or XML (see
+--format). The apk file can be given as:
+
+  - a path to a local file
+  - an http(s) URL, from which the file is downloaded (and cached)
+  - a package name, whose latest version is found in --repository for --arch,
+    and downloaded (and cached)
+
+An SPDX SBOM's document namespace is derived from the apk file's digest, so
+generating the SBOM of the same file again yields the same namespace. A
+CycloneDX SBOM's top-level component is the apk itself, with its license, and
+its origin package and architecture as properties, from its .PKGINFO.`,
+		Example: `  wolfictl sbom ./packages/x86_64/crane-0.15.2-r0.apk
+
+  wolfictl sbom https://packages.wolfi.dev/os/x86_64/crane-0.15.2-r0.apk
+
+  wolfictl sbom crane --arch aarch64 -o crane.spdx.json
+
+  wolfictl sbom crane --format cyclonedx-json -o crane.cdx.json`,
+		SilenceErrors: true,
+		Args:          cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !lo.Contains(sbomCommandFormats, p.format) {
+				return fmt.Errorf("unsupported SBOM format %q (must be one of: %s)", p.format, strings.Join(sbomCommandFormats, ", "))
+			}
+
+			apkFile, err := resolveAPKInput(args[0], p.repositoryURL, p.arch)
+			if err != nil {
+				return err
+			}
+
+			f, err := os.Open(apkFile)
+			if err != nil {
+				return fmt.Errorf("failed to open apk file: %w", err)
+			}
+			defer f.Close()
+
+			h := sha256.New()
+			r := io.TeeReader(f, h)
+
+			s, apk, err := scan.APKSBOM(r, scan.Options{Catalogers: p.catalogers, DisabledCatalogers: p.disabledCatalogers})
+			if err != nil {
+				return err
+			}
+
+			// Cataloging may not have needed the whole file, but the digest does.
+			if _, err := io.Copy(io.Discard, r); err != nil {
+				return fmt.Errorf("failed to read %s: %w", apkFile, err)
+			}
+			namespace := scan.APKDocumentNamespace(p.namespaceBase, *apk, hex.EncodeToString(h.Sum(nil)))
+
+			out := os.Stdout
+			if p.outputFile != "" {
+				outFile, err := os.Create(p.outputFile)
+				if err != nil {
+					return fmt.Errorf("unable to create SBOM output file: %w", err)
+				}
+				defer outFile.Close()
+				out = outFile
+			}
+
+			if p.format == sbomFormatSPDXJSON {
+				return scan.EncodeSPDXJSON(out, s, namespace)
+			}
+			return scan.EncodeCycloneDX(out, s, p.format, *apk)
+		},
+	}
+
+	p.addFlagsTo(cmd)
+	cmd.AddCommand(SBOMDiff())
+	return cmd
+}
+
+const sbomFormatSPDXJSON = "spdx-json"
+
+// sbomCommandFormats are the formats the sbom command can write.
+var sbomCommandFormats = []string{sbomFormatSPDXJSON, "cyclonedx-json", "cyclonedx-xml"}
+
+type sbomParams struct {
+	format             string
+	arch               string
+	repositoryURL      string
+	namespaceBase      string
+	outputFile         string
+	catalogers         []string
+	disabledCatalogers []string
+}
+
+func (p *sbomParams) addFlagsTo(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&p.format, "format", sbomFormatSPDXJSON, fmt.Sprintf("format of the SBOM (%s)", strings.Join(sbomCommandFormats, ", ")))
+	addAPKInputFlagsTo(cmd, &p.arch, &p.repositoryURL)
+	cmd.Flags().StringVar(&p.namespaceBase, "namespace-base", scan.DefaultSPDXNamespaceBase, "URI under which an SPDX SBOM's document namespace is created")
+	cmd.Flags().StringVarP(&p.outputFile, "output", "o", "", "write the SBOM to this file instead of stdout")
+	addCatalogerFlagsTo(cmd, &p.catalogers, &p.disabledCatalogers)
+}
+
+func addAPKInputFlagsTo(cmd *cobra.Command, arch, repositoryURL *string) {
+	cmd.Flags().StringVar(arch, "arch", "x86_64", "architecture of the apk files to find, when given package names")
+	cmd.Flags().StringVar(repositoryURL, "repository", "https://packages.wolfi.dev/os", "package repository to find the apk files in, when given package names")
+}
+
+// resolveAPKInput returns the path to the apk file given as a path, URL, or
+// package name (whose latest version for arch is found in the repository),
+// downloading it to the cache if needed.
+func resolveAPKInput(input, repositoryURL, arch string) (string, error) {
+	switch {
+	case strings.HasPrefix(input, "https://") || strings.HasPrefix(input, "http://"):
+		// Repositories are laid out as <repository>/<arch>/<file>, so the cache is
+		// too, as for apk files found by package name.
+		return cachedAPK(input, filepath.Join(apkCacheDir, path.Base(path.Dir(input))))
+
+	case strings.HasSuffix(input, ".apk") || strings.ContainsRune(input, os.PathSeparator):
+		return input, nil
+
+	default:
+		apkFiles, err := apkFilesForPackages([]string{input}, repositoryURL, arch)
+		if err != nil {
+			return "", err
+		}
+		return apkFiles[0], nil
+	}
+}
@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/anchore/syft/syft/sbom"
+	"github.com/spf13/cobra"
+	"github.com/wolfi-dev/wolfictl/pkg/scan"
+)
+
+func SBOMDiff() *cobra.Command {
+	p := &sbomDiffParams{}
+	cmd := &cobra.Command{
+		Use:         "diff <old.apk|url|package-name> <new.apk|url|package-name>",
+		Annotations: pagerAnnotations,
+		Short:       "Compare the components cataloged in two apk files",
+		Long: `Compare the components cataloged in two apk files.
+
+Both apk files are cataloged just as 'wolfictl sbom' catalogs them, and the
+output lists the components (such as Go modules bundled in the package's
+binaries) that were added, removed, upgraded, or downgraded going from the
+first apk file to the second. This shows reviewers of a version bump exactly
+which bundled dependencies changed, not just the package's version.
+
+Components are matched by name and type. Changed versions that can't be
+ordered are listed separately. The apk files can be given as paths, URLs, or
+package names, as with 'wolfictl sbom'.`,
+		Example: `  wolfictl sbom diff crane-0.15.2-r0.apk ./packages/x86_64/crane-0.16.0-r0.apk
+
+  # Compare a locally built package against the published one
+  wolfictl sbom diff crane ./packages/x86_64/crane-0.16.0-r0.apk --json`,
+		Args:          cobra.ExactArgs(2),
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts := scan.Options{Catalogers: p.catalogers, DisabledCatalogers: p.disabledCatalogers}
+
+			var infos [2]*scan.PackageInfo
+			var sboms [2]*sbom.SBOM
+			for i, arg := range args {
+				apkFile, err := resolveAPKInput(arg, p.repositoryURL, p.arch)
+				if err != nil {
+					return err
+				}
+
+				f, err := os.Open(apkFile)
+				if err != nil {
+					return fmt.Errorf("failed to open apk file: %w", err)
+				}
+				s, info, err := scan.APKSBOM(f, opts)
+				f.Close()
+				if err != nil {
+					return err
+				}
+				infos[i], sboms[i] = info, s
+			}
+
+			d := scan.NewSBOMDiff(infos[0], sboms[0], infos[1], sboms[1])
+
+			if p.outputJSON {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(d)
+			}
+
+			fmt.Println(renderSBOMDiff(d))
+			return nil
+		},
+	}
+
+	p.addFlagsTo(cmd)
+	return cmd
+}
+
+type sbomDiffParams struct {
+	arch               string
+	repositoryURL      string
+	outputJSON         bool
+	catalogers         []string
+	disabledCatalogers []string
+}
+
+func (p *sbomDiffParams) addFlagsTo(cmd *cobra.Command) {
+	addAPKInputFlagsTo(cmd, &p.arch, &p.repositoryURL)
+	cmd.Flags().BoolVar(&p.outputJSON, "json", false, "print the differences as JSON")
+	addCatalogerFlagsTo(cmd, &p.catalogers, &p.disabledCatalogers)
+}
+
+func renderSBOMDiff(d *scan.SBOMDiff) string {
+	lines := []string{fmt.Sprintf("%s %s → %s %s", d.From.Name, d.From.Version, d.To.Name, d.To.Version), ""}
+
+	if len(d.Added)+len(d.Removed)+len(d.Upgraded)+len(d.Downgraded)+len(d.Changed) == 0 {
+		lines = append(lines, styleSubtle.Render("  no changes"))
+	}
+	for _, c := range d.Added {
+		lines = append(lines, fmt.Sprintf("  + %s %s %s", c.Name, c.Version, styleSubtle.Render("("+c.Type+")")))
+	}
+	for _, c := range d.Removed {
+		lines = append(lines, fmt.Sprintf("  - %s %s %s", c.Name, c.Version, styleSubtle.Render("("+c.Type+")")))
+	}
+	for _, c := range d.Upgraded {
+		lines = append(lines, fmt.Sprintf("  ↑ %s %s → %s %s", c.Name, c.FromVersion, c.ToVersion, styleSubtle.Render("("+c.Type+")")))
+	}
+	for _, c := range d.Downgraded {
+		lines = append(lines, fmt.Sprintf("  ↓ %s %s → %s %s", c.Name, c.FromVersion, c.ToVersion, styleSubtle.Render("("+c.Type+")")))
+	}
+	for _, c := range d.Changed {
+		lines = append(lines, fmt.Sprintf("  ~ %s %s → %s %s", c.Name, c.FromVersion, c.ToVersion, styleSubtle.Render("("+c.Type+")")))
+	}
+
+	return strings.Join(lines, "\n")
+}
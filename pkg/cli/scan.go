@@ -1,74 +1,958 @@
 package cli
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"os"
 	"path"
+	"path/filepath"
+	"runtime/pprof"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/samber/lo"
 	"github.com/savioxavier/termlink"
 	"github.com/spf13/cobra"
+	"github.com/wolfi-dev/wolfictl/pkg/index"
+	"github.com/wolfi-dev/wolfictl/pkg/melange"
 	"github.com/wolfi-dev/wolfictl/pkg/scan"
 )
 
 func Scan() *cobra.Command {
 	p := &scanParams{}
 	cmd := &cobra.Command{
-		Use:           "scan <path/to/package.apk> ...",
+		Use:           "scan <path/to/package.apk|-> ...",
 		Short:         "Scan an apk file for vulnerabilities",
-		Args:          cobra.MinimumNArgs(1),
+		Annotations:   scanPagerAnnotations,
+		Args:          cobra.ArbitraryArgs,
 		SilenceErrors: true,
+		Example: `  wolfictl scan ./packages/x86_64/crane-0.15.2-r0.apk
+
+  curl -sL https://packages.wolfi.dev/os/x86_64/crane-0.15.2-r0.apk | wolfictl scan -
+
+  wolfictl scan --sbom ./crane.spdx.json
+
+  wolfictl scan --package-config ./crane.yaml --packages-dir ./packages
+
+  wolfictl scan --package nginx --arch x86_64,aarch64
+
+  wolfictl scan --package-config ./crane.yaml --by-origin
+
+  # Scan the built apk files of the package configs changed on this branch
+  wolfictl scan --only-changed --packages-dir ./packages
+
+  wolfictl scan ./packages/x86_64/*.apk --auto-advisory under_investigation \
+    --advisories-repo-dir ../advisories --advisory-branch scan-findings
+
+  wolfictl scan ./packages/x86_64/crane-0.15.2-r0.apk --vex wolfi.openvex.json --show-suppressed`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			for _, arg := range args {
-				apkFilePath := arg
-				apkFile, err := os.Open(apkFilePath)
+			if err := validateScanOutputFormat(p.outputFormat); err != nil {
+				return err
+			}
+			if p.sbomOutputLocation != "" {
+				if err := scan.ValidateSBOMFormat(p.sbomFormat); err != nil {
+					return err
+				}
+			}
+			if err := validateGroupBy(p.groupBy); err != nil {
+				return err
+			}
+
+			stopProfile := func() {}
+			if p.profile != "" {
+				stop, err := startCPUProfile(p.profile)
+				if err != nil {
+					return err
+				}
+				stopProfile = stop
+			}
+			defer stopProfile()
+
+			if len(p.archs) == 0 {
+				return fmt.Errorf("at least one --arch is required")
+			}
+
+			if p.parallelism < 1 {
+				return fmt.Errorf("--parallelism must be at least 1")
+			}
+
+			inputs := args
+			if lo.Count(inputs, stdinInput) > 1 {
+				return fmt.Errorf("stdin (%q) can only be scanned once", stdinInput)
+			}
+
+			// inputArchs records the architecture of each input that was found for a
+			// particular --arch, as opposed to given directly.
+			inputArchs := make(map[string]string)
+
+			if p.packageConfig != "" {
+				if p.sbomInput {
+					return fmt.Errorf("--package-config cannot be used with --sbom")
+				}
+
+				for _, arch := range p.archs {
+					apkFiles, err := apkFilesForConfig(p.packageConfig, p.packagesDir, arch)
+					if err != nil {
+						return err
+					}
+					for _, f := range apkFiles {
+						inputArchs[f] = arch
+					}
+					inputs = append(inputs, apkFiles...)
+				}
+			}
+
+			distroRepoDir := resolveDistroDir(p.distroRepoDir)
+			if distroRepoDir == "" {
+				distroRepoDir = "."
+			}
+			changedConfigs, onlyChanged, err := p.changed.changedPackageConfigs(distroRepoDir)
+			if err != nil {
+				return err
+			}
+			if onlyChanged {
+				if p.sbomInput {
+					return fmt.Errorf("--changed-since and --only-changed cannot be used with --sbom")
+				}
+				if len(changedConfigs) == 0 && len(inputs) == 0 {
+					_, _ = fmt.Fprintln(os.Stderr, "No package configs have changed, nothing to scan")
+					return nil
+				}
+
+				for _, arch := range p.archs {
+					for _, cfgPath := range changedConfigs {
+						apkFiles, err := apkFilesForConfig(cfgPath, p.packagesDir, arch)
+						if err != nil {
+							return err
+						}
+						for _, f := range apkFiles {
+							inputArchs[f] = arch
+						}
+						inputs = append(inputs, apkFiles...)
+					}
+				}
+			}
+
+			if len(p.packages) > 0 {
+				if p.sbomInput {
+					return fmt.Errorf("--package cannot be used with --sbom")
+				}
+
+				for _, arch := range p.archs {
+					apkFiles, err := apkFilesForPackages(p.packages, p.repositoryURL, arch)
+					if err != nil {
+						return err
+					}
+					for _, f := range apkFiles {
+						inputArchs[f] = arch
+					}
+					inputs = append(inputs, apkFiles...)
+				}
+			}
+
+			if len(inputs) == 0 {
+				return fmt.Errorf("no files to scan: specify one or more files, or use --package-config or --package")
+			}
+
+			if p.sbomOutputLocation != "" && len(inputs) > 1 {
+				return fmt.Errorf("--sbom-out can only be used when scanning a single file")
+			}
+
+			if p.secrets && p.sbomInput {
+				return fmt.Errorf("--secrets cannot be used with --sbom")
+			}
+
+			remediators := make(map[string]*scan.Remediator)
+			if p.remediation {
+				if p.sbomInput {
+					return fmt.Errorf("--remediation cannot be used with --sbom")
+				}
+
+				for _, arch := range p.archs {
+					remediator, err := newRemediator(p.repositoryURL, arch)
+					if err != nil {
+						return err
+					}
+					remediators[arch] = remediator
+				}
+			}
+
+			ageFilter, err := newVulnerabilityAgeFilter(p.showAge, p.minAge, p.maxAge)
+			if err != nil {
+				return err
+			}
+
+			opts, err := p.scanOptions()
+			if err != nil {
+				return err
+			}
+
+			if p.autoAdvisory != "" && p.sbomInput {
+				return fmt.Errorf("--auto-advisory cannot be used with --sbom")
+			}
+			advisoryCfgs, cleanupAdvisories, err := loadScanAdvisories(p.advisoriesURL, p.advisoriesRepoDirs)
+			if err != nil {
+				return err
+			}
+			defer cleanupAdvisories()
+			advisor, err := newAutoAdvisor(p.autoAdvisory, p.advisoriesRepoDirs, p.advisoryBranch, advisoryCfgs)
+			if err != nil {
+				return err
+			}
+
+			annotations, err := scan.ReadAnnotations(p.annotations)
+			if err != nil {
+				return err
+			}
+
+			var baseline *scan.Baseline
+			if p.baseline != "" {
+				baseline, err = scan.ReadBaselineFile(p.baseline)
+				if err != nil {
+					return err
+				}
+			}
+
+			if p.showSuppressed && len(p.vexFiles) == 0 {
+				return fmt.Errorf("--show-suppressed requires --vex")
+			}
+			vexStatements, err := scan.ReadVEXFiles(p.vexFiles)
+			if err != nil {
+				return err
+			}
+
+			if p.byOrigin {
+				switch {
+				case p.sbomInput:
+					return fmt.Errorf("--by-origin cannot be used with --sbom")
+				case p.outputFormat != scanOutputTree:
+					return fmt.Errorf("--by-origin cannot be used with --output %s", p.outputFormat)
+				case p.licenses || p.licensePolicy != "":
+					return fmt.Errorf("--by-origin cannot be used with --licenses or --license-policy")
+				case p.remediation:
+					return fmt.Errorf("--by-origin cannot be used with --remediation")
+				case p.secrets:
+					return fmt.Errorf("--by-origin cannot be used with --secrets")
+				}
+			}
+
+			if p.outputFormat == scanOutputJSON && (p.licenses || p.licensePolicy != "") {
+				return fmt.Errorf("--licenses and --license-policy cannot be used with --output %s", scanOutputJSON)
+			}
+
+			var licensePolicy *scan.LicensePolicy
+			if p.licensePolicy != "" {
+				var err error
+				licensePolicy, err = scan.ReadLicensePolicy(p.licensePolicy)
 				if err != nil {
-					return fmt.Errorf("failed to open apk file: %w", err)
+					return err
 				}
+			}
+
+			var allFindings []*scan.Finding
+			findingsByArch := make(map[string][]*scan.Finding)
+			originsByArch := make(map[string]*scan.GroupByOrigin)
+			var licenseViolations int
+			var inputDigests []string
+			var scanner *scan.Scanner
+			linker := newSourceLinker(p.sourceRepository, p.outputFormat)
+			progress := newScanProgress(len(inputs), p.quiet)
+
+			// Inputs are scanned ahead, in parallel, but reported one at a time, in order.
+			scanCtx, cancelScans := context.WithCancel(cmd.Context())
+			defer cancelScans()
+			jobs := startScans(scanCtx, inputs, p.parallelism, func(input string) (*scan.Result, string, error) {
+				return p.scanFile(input, opts)
+			})
 
-				fmt.Println(path.Base(apkFilePath))
+			for i, input := range inputs {
+				arch, archKnown := inputArchs[input]
+				if !archKnown {
+					// Files given directly are assumed to be for the first --arch.
+					arch = p.archs[0]
+				}
+
+				label := inputLabel(input)
+				if archKnown && len(p.archs) > 1 {
+					label = fmt.Sprintf("%s (%s)", label, arch)
+				}
+				progress.next(i, label)
+				if !p.byOrigin && p.outputFormat != scanOutputJSON {
+					fmt.Println(label)
+				}
 
-				findings, err := scan.APK(apkFile)
+				result, digest, err := jobs[i].wait()
 				if err != nil {
 					return err
 				}
+				inputDigests = append(inputDigests, digest)
 
-				apkFile.Close()
+				// The vulnerability database is only known to be present once a
+				// scan has run.
+				if scanner == nil && (advisor != nil || p.outputFormat == scanOutputJSON) {
+					scanner, err = scan.CurrentScanner()
+					if err != nil {
+						return err
+					}
+				}
+
+				if p.sbomOutputLocation != "" {
+					err := writeSBOM(result, p.sbomOutputLocation, p.sbomFormat)
+					if err != nil {
+						return err
+					}
+				}
+
+				findings := scan.FilterByPackageType(result.Findings, p.onlyTypes, p.excludeTypes)
+				findings, err = ageFilter.apply(cmd.Context(), findings)
+				if err != nil {
+					return err
+				}
+				scannedFindings := findings
+				findings, inBaseline := baseline.Filter(findings)
+				findings, suppressed := vexStatements.Filter(result.APK, findings)
+				if err := advisor.file(result.APK, scanner, "sha256:"+digest, findings); err != nil {
+					return err
+				}
+				annotateAdvisories(advisoryCfgs, result.APK, findings)
+				annotations.Annotate(findings)
+				linker.annotate(findings)
+
+				switch {
+				case p.byOrigin:
+					// Rendered once all of each origin's apks have been scanned.
+					if originsByArch[arch] == nil {
+						originsByArch[arch] = scan.NewGroupByOrigin()
+					}
+					originsByArch[arch].Add(*result.APK, findings)
+				case p.outputFormat == scanOutputGitHub:
+					fmt.Println(renderGitHubAnnotations(inputLabel(input), findings))
+				case p.outputFormat == scanOutputJSON:
+					var shownSuppressed []scan.SuppressedFinding
+					if p.showSuppressed {
+						shownSuppressed = suppressed
+					}
+					out, err := renderJSON(inputLabel(input), result.APK, "sha256:"+digest, scanner, findings, result.Secrets, shownSuppressed, findingFilters(cmd))
+					if err != nil {
+						return err
+					}
+					fmt.Println(out)
+				case p.licenses || licensePolicy != nil:
+					pkgLicenses := scan.Licenses(result.SBOM)
+					var violations []scan.LicenseViolation
+					if licensePolicy != nil {
+						violations = licensePolicy.Violations(pkgLicenses)
+					}
+					fmt.Println(renderLicenses(pkgLicenses, violations))
+					licenseViolations += len(violations)
+				case p.summary:
+					fmt.Println(renderSummary(findings))
+				case remediators[arch] != nil:
+					fmt.Println(renderFindingsWithRemediation(findings, p.groupBy, p.maxFindingsPerPackage, remediators[arch], *result.APK))
+				default:
+					fmt.Println(renderFindings(findings, p.groupBy, p.maxFindingsPerPackage))
+				}
+
+				if inBaseline > 0 && p.outputFormat == scanOutputTree {
+					fmt.Println(styleSubtle.Render(fmt.Sprintf("%d findings already in the baseline not shown", inBaseline)))
+				}
+
+				if len(suppressed) > 0 && p.outputFormat == scanOutputTree && !p.byOrigin {
+					if p.showSuppressed {
+						fmt.Println(renderSuppressedFindings(suppressed))
+					} else {
+						fmt.Println(styleSubtle.Render(fmt.Sprintf("%d findings suppressed by VEX statements not shown (use --show-suppressed to list them)", len(suppressed))))
+					}
+				}
+
+				if p.secrets && p.outputFormat != scanOutputJSON {
+					fmt.Println(renderSecrets(result.Secrets))
+				}
 
-				if len(findings) == 0 {
-					fmt.Println("✅ No vulnerabilities found")
-				} else {
-					tree := newFindingsTree(findings)
-					fmt.Println(tree.render())
+				if p.history != "" {
+					record := scan.NewHistoryRecord(inputLabel(input), scannedFindings, time.Now())
+					if err := scan.AppendHistory(p.history, record); err != nil {
+						return err
+					}
 				}
 
-				if p.requireZeroFindings && len(findings) > 0 {
+				allFindings = append(allFindings, findings...)
+				if archKnown {
+					findingsByArch[arch] = append(findingsByArch[arch], findings...)
+				}
+
+				// Fail on the first input with findings, rather than scanning the
+				// rest. With --by-origin, nothing is rendered until all inputs are
+				// scanned, so that check waits until then.
+				if p.requireZeroFindings && !p.byOrigin && len(findings) > 0 {
 					return fmt.Errorf("more than 0 vulnerabilities found")
 				}
 			}
+			progress.done()
+
+			if p.byOrigin {
+				allFindings = nil
+				for _, arch := range p.archs {
+					g := originsByArch[arch]
+					if g == nil {
+						continue
+					}
+
+					headerArch := ""
+					if len(p.archs) > 1 {
+						headerArch = arch
+					}
+					for _, o := range g.Origins {
+						fmt.Println(originHeader(o, headerArch))
+						fmt.Println(renderOriginFindings(o, p.groupBy, p.maxFindingsPerPackage, p.summary))
+					}
+					allFindings = append(allFindings, g.Findings()...)
+				}
+			}
+
+			if len(findingsByArch) > 1 && p.outputFormat == scanOutputTree {
+				fmt.Println(renderArchSpecificFindings(scan.ArchSpecificFindings(findingsByArch)))
+			}
+
+			if err := advisor.done(); err != nil {
+				return err
+			}
+
+			if p.manifestOutputLocation != "" {
+				if err := writeScanManifest(p.manifestOutputLocation, inputs, inputDigests); err != nil {
+					return err
+				}
+			}
+
+			if licenseViolations > 0 {
+				return fmt.Errorf("%d licenses denied by the license policy found", licenseViolations)
+			}
+
+			if len(inputs) > 1 && p.outputFormat != scanOutputJSON {
+				fmt.Printf("\n%d vulnerabilities found across %d files\n", len(allFindings), len(inputs))
+			}
+
+			if p.severityExitCodes {
+				if err := severityExitError(allFindings); err != nil {
+					return err
+				}
+			}
+
+			if p.requireZeroFindings && len(allFindings) > 0 {
+				return fmt.Errorf("more than 0 vulnerabilities found")
+			}
 
 			return nil
 		},
 	}
 
 	p.addFlagsTo(cmd)
+	cmd.AddCommand(
+		ScanApko(),
+		ScanCanary(),
+		ScanAnnotate(),
+	)
 	return cmd
 }
 
 type scanParams struct {
-	requireZeroFindings bool
+	requireZeroFindings    bool
+	sbomInput              bool
+	sbomOutputLocation     string
+	sbomFormat             string
+	packageConfig          string
+	packagesDir            string
+	packages               []string
+	byOrigin               bool
+	archs                  []string
+	sourceRepository       string
+	annotations            string
+	manifestOutputLocation string
+	baseline               string
+	vexFiles               []string
+	showSuppressed         bool
+	summary                bool
+	remediation            bool
+	repositoryURL          string
+	outputFormat           string
+	severityExitCodes      bool
+	onlyTypes              []string
+	excludeTypes           []string
+	catalogers             []string
+	disabledCatalogers     []string
+	profile                string
+	licenses               bool
+	licensePolicy          string
+	secrets                bool
+	quiet                  bool
+	showAge                bool
+	minAge                 string
+	maxAge                 string
+	groupBy                string
+	maxFindingsPerPackage  int
+	distro                 string
+	autoAdvisory           string
+	advisoriesRepoDirs     []string
+	advisoriesURL          string
+	advisoryBranch         string
+	history                string
+	distroRepoDir          string
+	changed                changedParams
+	parallelism            int
 }
 
 func (p *scanParams) addFlagsTo(cmd *cobra.Command) {
-	cmd.Flags().BoolVar(&p.requireZeroFindings, "require-zero", false, "exit 1 if any vulnerabilities are found")
+	cmd.Flags().BoolVar(&p.requireZeroFindings, "require-zero", false, "exit 1 as soon as a scanned file has any vulnerabilities (with --by-origin, once all files are scanned)")
+	cmd.Flags().BoolVar(&p.sbomInput, "sbom", false, "treat input files as SBOMs (SPDX, CycloneDX, or Syft JSON) instead of apk files, skipping package cataloging")
+	cmd.Flags().StringVar(&p.sbomOutputLocation, "sbom-out", "", "write the SBOM generated during the scan to this file")
+	cmd.Flags().StringVar(&p.sbomFormat, "sbom-format", "spdx-json", fmt.Sprintf("format of the SBOM written by --sbom-out (%s)", strings.Join(scan.SBOMFormats, ", ")))
+	cmd.Flags().StringVar(&p.packageConfig, "package-config", "", "path to a melange config whose built apk files (including subpackages) should be scanned")
+	cmd.Flags().StringVar(&p.packagesDir, "packages-dir", "packages", "directory containing built apk files, organized by architecture (used with --package-config, --changed-since and --only-changed)")
+	cmd.Flags().StringSliceVar(&p.packages, "package", nil, "name of a package to scan; its latest version is found in --repository (the default Wolfi repository, unless set) and downloaded to the cache if needed")
+	cmd.Flags().StringSliceVar(&p.archs, "arch", []string{"x86_64"}, "architectures of the apk files to scan, e.g. x86_64,aarch64 (used with --package-config, --package and --remediation)")
+	cmd.Flags().BoolVar(&p.byOrigin, "by-origin", false, "collapse identical findings from apks built from the same origin package (e.g. foo, foo-doc, foo-dev), showing which subpackages each applies to")
+	cmd.Flags().BoolVar(&p.summary, "summary", false, "print a table of vulnerability counts by severity for each file instead of listing every finding")
+	addRemediationFlagsTo(cmd, &p.remediation, &p.repositoryURL)
+	cmd.MarkFlagsMutuallyExclusive("summary", "remediation")
+	addScanOutputFlagTo(cmd, &p.outputFormat)
+	addSourceRepositoryFlagTo(cmd, &p.sourceRepository)
+	addSeverityExitCodesFlagTo(cmd, &p.severityExitCodes)
+	addPackageTypeFilterFlagsTo(cmd, &p.onlyTypes, &p.excludeTypes)
+	addCatalogerFlagsTo(cmd, &p.catalogers, &p.disabledCatalogers)
+	addDistroFlagTo(cmd, &p.distro)
+	cmd.Flags().BoolVar(&p.licenses, "licenses", false, "list the licenses detected for each cataloged package instead of vulnerability findings")
+	cmd.Flags().StringVar(&p.licensePolicy, "license-policy", "", "path to a YAML file with a 'deny' list of licenses (globs allowed); packages with a denied license are flagged, and the scan fails (implies --licenses)")
+	cmd.Flags().BoolVar(&p.secrets, "secrets", false, "also search the files in each apk for secrets, such as private keys and cloud provider credentials")
+	addVulnerabilityAgeFlagsTo(cmd, &p.showAge, &p.minAge, &p.maxAge)
+	addGroupByFlagTo(cmd, &p.groupBy)
+	addMaxFindingsPerPackageFlagTo(cmd, &p.maxFindingsPerPackage)
+	addAdvisoriesDirsFlag(&p.advisoriesRepoDirs, cmd)
+	addAdvisoriesURLFlag(&p.advisoriesURL, cmd)
+	addAnnotationsFlagTo(cmd, &p.annotations)
+	addAutoAdvisoryFlagsTo(cmd, &p.autoAdvisory, &p.advisoryBranch)
+	cmd.Flags().StringVar(&p.baseline, "baseline", "", "saved scan result (from --output json) whose findings are treated as known: only findings not in it are reported and count toward --require-zero and --severity-exit-codes")
+	cmd.Flags().StringArrayVar(&p.vexFiles, "vex", nil, "OpenVEX document (in JSON) whose not_affected and fixed statements suppress matching findings, which then don't count toward --require-zero and --severity-exit-codes (can be given more than once; for each finding, the latest applicable statement wins)")
+	cmd.Flags().BoolVar(&p.showSuppressed, "show-suppressed", false, "list the findings suppressed by --vex statements separately, with the statements' status (included as \"suppressed\" with --output json)")
+	cmd.Flags().StringVar(&p.manifestOutputLocation, "manifest-out", "", "write a manifest of the wolfictl, grype, and syft versions, the vulnerability database, and the digests of the scanned files to this file, to help explain differences between scans")
+	cmd.Flags().StringVar(&p.history, "history", "", "append each file's findings to this scan history file, for use with 'wolfictl gate simulate'")
+	addQuietFlagTo(cmd, &p.quiet)
+	addDistroDirFlag(&p.distroRepoDir, cmd)
+	p.changed.addFlagsTo(cmd)
+	cmd.Flags().IntVar(&p.parallelism, "parallelism", defaultScanParallelism, "number of files to scan at once (results are still reported in order)")
+	cmd.Flags().StringVar(&p.profile, "profile", "", "write a CPU profile (in pprof format) of the scan to this file")
+}
+
+// startCPUProfile starts writing a CPU profile to the file at the given path.
+// The returned function stops profiling and closes the file.
+func startCPUProfile(location string) (func(), error) {
+	f, err := os.Create(location)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create profile output file: %w", err)
+	}
+
+	if err := pprof.StartCPUProfile(f); err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("unable to start CPU profile: %w", err)
+	}
+
+	return func() {
+		pprof.StopCPUProfile()
+		_ = f.Close()
+	}, nil
+}
+
+func (p *scanParams) scanOptions() (scan.Options, error) {
+	distro, err := parseDistroFlag(p.distro)
+	if err != nil {
+		return scan.Options{}, err
+	}
+
+	return scan.Options{
+		Catalogers:         p.catalogers,
+		DisabledCatalogers: p.disabledCatalogers,
+		Secrets:            p.secrets,
+		Distro:             distro,
+	}, nil
+}
+
+func addCatalogerFlagsTo(cmd *cobra.Command, catalogers, disabled *[]string) {
+	cmd.Flags().StringSliceVar(catalogers, "catalogers", nil, fmt.Sprintf("Syft catalogers to use when finding packages in apk files (default %s)", strings.Join(scan.DefaultCatalogers, ",")))
+	cmd.Flags().StringSliceVar(disabled, "disable-catalogers", nil, "Syft catalogers to skip, matched by partial name (e.g. javascript)")
+}
+
+func addDistroFlagTo(cmd *cobra.Command, distro *string) {
+	cmd.Flags().StringVar(distro, "distro", "", fmt.Sprintf("distro whose security data is used to match the apks' own packages, optionally with a version (e.g. wolfi, chainguard, alpine:3.19), or %q to skip distro matching (default: detected from the scanned files)", scan.DistroNone))
+}
+
+// parseDistroFlag parses the value of the --distro flag. It returns nil if the
+// flag wasn't set, meaning the distro should be detected.
+func parseDistroFlag(value string) (*scan.Distro, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	distro, err := scan.ParseDistro(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --distro: %w", err)
+	}
+
+	return distro, nil
+}
+
+func addPackageTypeFilterFlagsTo(cmd *cobra.Command, only, exclude *[]string) {
+	cmd.Flags().StringSliceVar(only, "only-type", nil, "only report findings for packages of these types (e.g. go-module, python, java-archive, npm, apk)")
+	cmd.Flags().StringSliceVar(exclude, "exclude-type", nil, "don't report findings for packages of these types (e.g. go-module, python, java-archive, npm, apk)")
+}
+
+func addRemediationFlagsTo(cmd *cobra.Command, remediation *bool, repositoryURL *string) {
+	cmd.Flags().BoolVar(remediation, "remediation", false, "show whether a published package update fixes each finding, using the repository's APKINDEX and secdb")
+	cmd.Flags().StringVar(repositoryURL, "repository", "https://packages.wolfi.dev/os", "package repository to check for fixed packages (used with --remediation)")
+}
+
+func newRemediator(repositoryURL, arch string) (*scan.Remediator, error) {
+	apkindex, err := index.Index(arch, repositoryURL)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load APKINDEX for %s: %w", arch, err)
+	}
+
+	db, err := scan.FetchSecDB(repositoryURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return scan.NewRemediator(apkindex, db), nil
+}
+
+// apkFilesForConfig returns the paths to the built apk files for the origin
+// package and all subpackages defined in the melange config at cfgPath.
+// Subpackages that weren't built for the given arch are skipped. Ranged
+// subpackages are included once per item of their range, as expanded by
+// melange when it reads the config.
+func apkFilesForConfig(cfgPath, packagesDir, arch string) ([]string, error) {
+	cfg, err := melange.ReadMelangeConfig(cfgPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read package config: %w", err)
+	}
+
+	pkg := cfg.Package
+	version := fmt.Sprintf("%s-r%d", pkg.Version, pkg.Epoch)
+	r := strings.NewReplacer(
+		"${{package.name}}", pkg.Name,
+		"${{package.version}}", pkg.Version,
+		"${{package.epoch}}", strconv.FormatUint(pkg.Epoch, 10),
+	)
+
+	names := []string{pkg.Name}
+	for i := range cfg.Subpackages {
+		names = append(names, r.Replace(cfg.Subpackages[i].Name))
+	}
+
+	var apkFiles []string
+	for _, name := range names {
+		apkFile := filepath.Join(packagesDir, arch, fmt.Sprintf("%s-%s.apk", name, version))
+		if _, err := os.Stat(apkFile); err != nil {
+			if name == pkg.Name {
+				return nil, fmt.Errorf("unable to find apk file for %s: %w", name, err)
+			}
+
+			fmt.Fprintf(os.Stderr, "⚠️  skipping subpackage %s: no apk file found at %s\n", name, apkFile)
+			continue
+		}
+
+		apkFiles = append(apkFiles, apkFile)
+	}
+
+	return apkFiles, nil
+}
+
+// scanFile scans the apk file (or, when requested, the SBOM file) at the given
+// path, or read from stdin if the path is "-". It also returns the hex-encoded
+// SHA-256 digest of the file.
+func (p *scanParams) scanFile(inputFilePath string, opts scan.Options) (*scan.Result, string, error) {
+	var inputFile io.Reader = os.Stdin
+	if inputFilePath != stdinInput {
+		f, err := os.Open(inputFilePath)
+		if err != nil {
+			if p.sbomInput {
+				return nil, "", fmt.Errorf("failed to open sbom file: %w", err)
+			}
+			return nil, "", fmt.Errorf("failed to open apk file: %w", err)
+		}
+		defer f.Close()
+		inputFile = f
+	}
+
+	h := sha256.New()
+	r := io.TeeReader(inputFile, h)
+
+	var result *scan.Result
+	var err error
+	if p.sbomInput {
+		result, err = scan.SBOM(r, opts)
+	} else {
+		result, err = scan.APK(r, opts)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	// The scan may not have needed the whole file, but the digest does.
+	if _, err := io.Copy(io.Discard, r); err != nil {
+		return nil, "", fmt.Errorf("failed to read %s: %w", inputLabel(inputFilePath), err)
+	}
+
+	return result, hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// stdinInput is the input path that means to read from stdin.
+const stdinInput = "-"
+
+// inputLabel returns the name used for the given input in output.
+func inputLabel(input string) string {
+	if input == stdinInput {
+		return "stdin"
+	}
+	return path.Base(input)
+}
+
+// writeScanManifest writes a scan manifest for the given inputs, which have the
+// given digests, to the file at the given location.
+func writeScanManifest(location string, inputs, digests []string) error {
+	dbInfo, err := scan.CurrentVulnerabilityDB()
+	if err != nil {
+		return err
+	}
+
+	m := scan.NewManifest(dbInfo, time.Now())
+	for i, input := range inputs {
+		m.AddInput(inputLabel(input), digests[i])
+	}
+
+	return m.Write(location)
+}
+
+func writeSBOM(result *scan.Result, location, format string) error {
+	// Check the format before creating the file, so that an existing file isn't
+	// truncated only to fail.
+	if err := scan.ValidateSBOMFormat(format); err != nil {
+		return err
+	}
+
+	f, err := os.Create(location)
+	if err != nil {
+		return fmt.Errorf("unable to create SBOM output file: %w", err)
+	}
+	defer f.Close()
+
+	err = scan.EncodeSBOM(f, result.SBOM, format)
+	if err != nil {
+		return fmt.Errorf("unable to write SBOM to %q: %w", location, err)
+	}
+
+	return nil
+}
+
+func renderFindings(findings []*scan.Finding, groupBy string, maxPerPackage int) string {
+	if len(findings) == 0 {
+		return "✅ No vulnerabilities found"
+	}
+
+	t := newFindingsTree(findings)
+	t.maxPerPackage = maxPerPackage
+	if groupBy == groupByVulnerability {
+		return t.renderByVulnerability()
+	}
+
+	return t.render()
+}
+
+func renderFindingsWithRemediation(findings []*scan.Finding, groupBy string, maxPerPackage int, remediator *scan.Remediator, apk scan.PackageInfo) string {
+	if len(findings) == 0 {
+		return "✅ No vulnerabilities found"
+	}
+
+	t := newFindingsTree(findings)
+	t.maxPerPackage = maxPerPackage
+	t.hint = func(f *scan.Finding) string {
+		return renderRemediation(remediator.Remediate(apk, f))
+	}
+
+	if groupBy == groupByVulnerability {
+		return t.renderByVulnerability()
+	}
+
+	return t.render()
+}
+
+// renderSuppressedFindings renders the findings suppressed by VEX statements,
+// with the status each statement asserts.
+func renderSuppressedFindings(suppressed []scan.SuppressedFinding) string {
+	lines := []string{fmt.Sprintf("🔇 %d findings suppressed by VEX statements:", len(suppressed))}
+	for _, s := range suppressed {
+		reason := string(s.Statement.Status)
+		switch {
+		case s.Statement.Justification != "":
+			reason += ": " + string(s.Statement.Justification)
+		case s.Statement.ImpactStatement != "":
+			reason += ": " + s.Statement.ImpactStatement
+		case s.Statement.StatusNotes != "":
+			reason += ": " + s.Statement.StatusNotes
+		}
+
+		lines = append(lines, fmt.Sprintf(
+			"  %s %s %s %s %s",
+			renderSeverity(s.Finding.Vulnerability.Severity),
+			renderVulnerabilityID(s.Finding.Vulnerability),
+			s.Finding.Package.Name,
+			styleSubtle.Render(s.Finding.Package.Version),
+			styleSubtle.Render("("+reason+")"),
+		))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// renderArchSpecificFindings renders the findings that were only found for some
+// of the scanned architectures.
+func renderArchSpecificFindings(specific map[string][]scan.CanaryFinding) string {
+	if len(specific) == 0 {
+		return "\nNo architecture-specific vulnerabilities found"
+	}
+
+	archs := lo.Keys(specific)
+	sort.Strings(archs)
+
+	lines := []string{"", "Architecture-specific vulnerabilities:"}
+	for _, arch := range archs {
+		lines = append(lines, fmt.Sprintf("  %s:", arch))
+		for _, f := range specific[arch] {
+			lines = append(lines, fmt.Sprintf(
+				"    %s %s %s %s",
+				renderSeverity(f.Severity),
+				hyperlinkVulnerabilityID(f.Vulnerability),
+				f.Package,
+				styleSubtle.Render(fmt.Sprintf("%s (%s)", f.Version, f.Type)),
+			))
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func renderRemediation(r scan.Remediation) string {
+	switch r.Status {
+	case scan.RemediationPublished:
+		return fmt.Sprintf("🔧 fixed in package %s-%s (already published)", r.Package, r.FixedVersion)
+	case scan.RemediationUnpublished:
+		return fmt.Sprintf("⏳ fixed in package %s-%s (not yet published)", r.Package, r.FixedVersion)
+	case scan.RemediationNotAffected:
+		return "ℹ️  advisory data says this package is not affected"
+	default:
+		return "❗ no fixed package yet"
+	}
+}
+
+// renderSummary renders a compact table of the findings' counts by severity,
+// followed by the number of affected packages and how many findings have fixes.
+func renderSummary(findings []*scan.Finding) string {
+	summary := scan.Summarize(findings)
+
+	var header, counts []string
+	for _, sev := range scan.Severities {
+		width := len(sev) + 2
+		header = append(header, renderSeverity(sev)+"  ")
+		counts = append(counts, fmt.Sprintf("%-*d", width, summary.CountsBySeverity[sev]))
+	}
+
+	lines := []string{
+		"  " + strings.Join(header, ""),
+		"  " + strings.Join(counts, ""),
+		styleSubtle.Render(fmt.Sprintf(
+			"  %d affected packages, fixes available for %d of %d vulnerabilities",
+			summary.AffectedPackages,
+			summary.FixesAvailable,
+			summary.Total,
+		)),
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// renderLicenses renders the licenses detected for each package, flagging those
+// denied by the license policy.
+func renderLicenses(pkgs []scan.PackageLicenses, violations []scan.LicenseViolation) string {
+	if len(pkgs) == 0 {
+		return "No packages found"
+	}
+
+	denied := make(map[string]map[string]bool)
+	for _, v := range violations {
+		if denied[v.Package.ID] == nil {
+			denied[v.Package.ID] = make(map[string]bool)
+		}
+		denied[v.Package.ID][v.License] = true
+	}
+
+	var lines []string
+	for _, pl := range pkgs {
+		licenses := lo.Map(pl.Licenses, func(l string, _ int) string {
+			if denied[pl.Package.ID][l] {
+				return styleCritical.Render(l + " (denied)")
+			}
+			return l
+		})
+		if len(licenses) == 0 {
+			licenses = []string{styleSubtle.Render("no license detected")}
+		}
+
+		lines = append(lines, fmt.Sprintf(
+			"  📦 %s %s %s: %s",
+			pl.Package.Name,
+			pl.Package.Version,
+			styleSubtle.Render("("+pl.Package.Type+")"),
+			strings.Join(licenses, ", "),
+		))
+	}
+
+	if len(violations) > 0 {
+		lines = append(lines, fmt.Sprintf("  ❌ %d denied licenses found", len(violations)))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// renderSecrets renders the potential secrets found in an apk file. Matched
+// values aren't shown.
+func renderSecrets(secrets []scan.SecretFinding) string {
+	if len(secrets) == 0 {
+		return "✅ No secrets found"
+	}
+
+	lines := []string{fmt.Sprintf("🔑 %d potential secrets found", len(secrets))}
+	for _, s := range secrets {
+		lines = append(lines, fmt.Sprintf("  %s %s", styleCritical.Render(s.Rule), styleSubtle.Render(fmt.Sprintf("%s:%d", s.Location, s.Line))))
+	}
+
+	return strings.Join(lines, "\n")
 }
 
 type findingsTree struct {
+	findings                    []*scan.Finding
 	findingsByPackageByLocation map[string]map[string][]*scan.Finding
 	packagesByID                map[string]scan.Package
+
+	// hint, if set, returns an additional line to render beneath each finding.
+	hint func(*scan.Finding) string
+
+	// maxPerPackage, if positive, limits how many findings are rendered for each
+	// package when grouped by location.
+	maxPerPackage int
 }
 
 func newFindingsTree(findings []*scan.Finding) *findingsTree {
@@ -88,6 +972,7 @@ func newFindingsTree(findings []*scan.Finding) *findingsTree {
 	}
 
 	return &findingsTree{
+		findings:                    findings,
 		findingsByPackageByLocation: tree,
 		packagesByID:                packagesByID,
 	}
@@ -124,7 +1009,7 @@ func (t findingsTree) render() string {
 			line := fmt.Sprintf(
 				"%s       📦 %s %s %s",
 				verticalLine,
-				pkg.Name,
+				hyperlinkPackageName(pkg),
 				pkg.Version,
 				styleSubtle.Render("("+pkg.Type+")"),
 			)
@@ -135,15 +1020,26 @@ func (t findingsTree) render() string {
 				return findings[i].Vulnerability.ID < findings[j].Vulnerability.ID
 			})
 
+			findings, hidden := truncateFindings(findings, t.maxPerPackage)
 			for _, f := range findings {
 				line := fmt.Sprintf(
-					"%s           %s %s%s",
+					"%s           %s %s%s%s%s%s",
 					verticalLine,
 					renderSeverity(f.Vulnerability.Severity),
 					renderVulnerabilityID(f.Vulnerability),
 					renderFixedIn(f.Vulnerability),
+					renderAge(f.Vulnerability),
+					renderAdvisory(f),
+					renderAnnotations(f),
 				)
 				lines = append(lines, line)
+
+				if t.hint != nil {
+					lines = append(lines, fmt.Sprintf("%s               %s", verticalLine, styleSubtle.Render(t.hint(f))))
+				}
+			}
+			if hidden > 0 {
+				lines = append(lines, fmt.Sprintf("%s           %s", verticalLine, styleSubtle.Render(fmt.Sprintf("… and %d more", hidden))))
 			}
 		}
 
@@ -199,15 +1095,12 @@ func hyperlinkVulnerabilityID(id string) string {
 		return id
 	}
 
-	switch {
-	case strings.HasPrefix(id, "CVE-"):
-		return termlink.Link(id, fmt.Sprintf("https://nvd.nist.gov/vuln/detail/%s", id))
-
-	case strings.HasPrefix(id, "GHSA-"):
-		return termlink.Link(id, fmt.Sprintf("https://github.com/advisories/%s", id))
+	url := vulnLinkTemplates.URL(id)
+	if url == "" {
+		return id
 	}
 
-	return id
+	return termlink.Link(id, url)
 }
 
 func renderFixedIn(vuln scan.Vulnerability) string {
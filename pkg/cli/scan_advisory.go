@@ -0,0 +1,202 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/openvex/go-vex/pkg/vex"
+	"github.com/spf13/cobra"
+	"github.com/wolfi-dev/wolfictl/pkg/advisory"
+	"github.com/wolfi-dev/wolfictl/pkg/configs"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
+	wgit "github.com/wolfi-dev/wolfictl/pkg/git"
+	"github.com/wolfi-dev/wolfictl/pkg/scan"
+	"golang.org/x/exp/slices"
+)
+
+func addAutoAdvisoryFlagsTo(cmd *cobra.Command, status, branch *string) {
+	cmd.Flags().StringVar(status, "auto-advisory", "", fmt.Sprintf("create an advisory with this status (only %s is supported) in the advisories repository for each finding that has no advisory yet, without prompting", vex.StatusUnderInvestigation))
+	cmd.Flags().StringVar(branch, "advisory-branch", "", "if set, commit the created advisories to a new branch with this name in the advisories repository (used with --auto-advisory)")
+}
+
+// autoAdvisor files advisories for scan findings that aren't yet covered by
+// any advisory, for use in unattended pipelines.
+type autoAdvisor struct {
+	status vex.Status
+	dir    string
+	branch string
+	cfgs   *configs.Index[advisoryconfigs.Document]
+	now    time.Time
+
+	// merged is the merged view of all the advisory data, including any other
+	// advisories repos and published advisory data. Findings with an advisory in
+	// it aren't filed again.
+	merged *configs.Index[advisoryconfigs.Document]
+
+	created []string
+
+	// paths are the advisory files (relative to dir) that were written.
+	paths []string
+}
+
+// loadScanAdvisories loads the merged advisory data published at the given URL
+// and in the given advisories repository directories (or those set via
+// environment variables). It returns nil if neither was specified. The returned function cleans up any
+// temporary files, and must be called.
+func loadScanAdvisories(advisoriesURL string, advisoriesRepoDirs []string) (*configs.Index[advisoryconfigs.Document], func(), error) {
+	advisoriesURL = resolveAdvisoriesURL(advisoriesURL)
+	dirs := resolveAdvisoriesDirs(advisoriesRepoDirs)
+	if len(dirs) == 0 && advisoriesURL == "" {
+		return nil, func() {}, nil
+	}
+
+	return advisoriesIndexAsOf(advisoriesURL, dirs, "")
+}
+
+// advisoryPackageName returns the name of the package under which advisories
+// for the given APK are recorded.
+func advisoryPackageName(apk *scan.PackageInfo) string {
+	if apk.Origin != "" {
+		return apk.Origin
+	}
+
+	return apk.Name
+}
+
+// annotateAdvisories sets each finding's Advisory to the latest advisory event
+// recorded for its vulnerability in the given APK's package, and replaces its
+// severity with the advisory's severity override, if any.
+func annotateAdvisories(cfgs *configs.Index[advisoryconfigs.Document], apk *scan.PackageInfo, findings []*scan.Finding) {
+	if cfgs == nil || apk == nil {
+		return
+	}
+
+	pkg := advisoryPackageName(apk)
+	for _, f := range findings {
+		ids := append([]string{f.Vulnerability.ID}, f.Vulnerability.Aliases...)
+		f.Advisory = advisory.LatestForPackage(cfgs, pkg, ids...)
+
+		if o := advisory.SeverityOverrideForPackage(cfgs, pkg, ids...); o != nil && o.Severity != f.Vulnerability.Severity {
+			f.Vulnerability.UpstreamSeverity = f.Vulnerability.Severity
+			f.Vulnerability.Severity = o.Severity
+		}
+	}
+}
+
+func renderAdvisory(f *scan.Finding) string {
+	if f.Advisory == nil {
+		return ""
+	}
+
+	s := fmt.Sprintf(" [%s since %s]", renderListItem(*f.Advisory), f.Advisory.Timestamp.Format("2006-01-02"))
+	if f.Vulnerability.UpstreamSeverity != "" {
+		s += fmt.Sprintf(" [severity overridden from %s]", f.Vulnerability.UpstreamSeverity)
+	}
+
+	return styleSubtle.Render(s)
+}
+
+// newAutoAdvisor returns an autoAdvisor for the --auto-advisory flag, or nil if
+// automatic advisories weren't requested.
+func newAutoAdvisor(status string, advisoriesRepoDirs []string, branch string, merged *configs.Index[advisoryconfigs.Document]) (*autoAdvisor, error) {
+	if status == "" {
+		return nil, nil
+	}
+
+	s := vex.Status(strings.ReplaceAll(status, "-", "_"))
+	if s != vex.StatusUnderInvestigation {
+		return nil, fmt.Errorf("unsupported --auto-advisory status %q (must be %s)", status, vex.StatusUnderInvestigation)
+	}
+
+	dirs := resolveAdvisoriesDirs(advisoriesRepoDirs)
+	if merged == nil || len(dirs) == 0 {
+		return nil, fmt.Errorf("--auto-advisory requires the advisories repo dir to be specified")
+	}
+
+	// Advisories are filed into the advisories repo with the highest precedence.
+	// The merged index can't be written to, even with a single repo dir, since
+	// overlaying published advisory data puts it in a temporary directory.
+	dir := dirs[len(dirs)-1]
+	cfgs, err := advisoryconfigs.NewIndex(rwos.DirFS(dir))
+	if err != nil {
+		return nil, err
+	}
+
+	return &autoAdvisor{
+		status: s,
+		dir:    dir,
+		branch: branch,
+		cfgs:   cfgs,
+		now:    time.Now(),
+		merged: merged,
+	}, nil
+}
+
+// file creates an advisory for each of the findings from the given APK whose
+// vulnerability (under any of its IDs) has no advisory for the APK's origin
+// package. Each advisory records the finding's detection by the given scanner in
+// the APK file with the given digest.
+func (a *autoAdvisor) file(apk *scan.PackageInfo, scanner *scan.Scanner, apkDigest string, findings []*scan.Finding) error {
+	if a == nil {
+		return nil
+	}
+
+	pkg := advisoryPackageName(apk)
+	for _, f := range findings {
+		ids := append([]string{f.Vulnerability.ID}, f.Vulnerability.Aliases...)
+		if advisory.LatestForPackage(a.cfgs, pkg, ids...) != nil || advisory.LatestForPackage(a.merged, pkg, ids...) != nil {
+			continue
+		}
+
+		req := advisory.Request{
+			Package:       pkg,
+			Vulnerability: f.Vulnerability.ID,
+			Status:        a.status,
+			Timestamp:     a.now,
+			Detection:     advisory.DetectionFromScan(scanner, apkDigest, f),
+		}
+		err := advisory.Create(req, advisory.CreateOptions{AdvisoryCfgs: a.cfgs})
+		if err != nil {
+			return fmt.Errorf("unable to create advisory for %s in %s: %w", f.Vulnerability.ID, pkg, err)
+		}
+
+		a.created = append(a.created, fmt.Sprintf("%s: %s", pkg, f.Vulnerability.ID))
+		for _, path := range a.cfgs.Select().WhereName(pkg).Paths() {
+			if !slices.Contains(a.paths, path) {
+				a.paths = append(a.paths, path)
+			}
+		}
+	}
+
+	return nil
+}
+
+// done reports the advisories that were created, and commits them if a branch
+// was requested. The branch is only created when there's something to commit,
+// and only the advisory files that were written are committed.
+func (a *autoAdvisor) done() error {
+	if a == nil {
+		return nil
+	}
+
+	fmt.Fprintf(os.Stderr, "created %d %s advisories\n", len(a.created), a.status)
+	for _, c := range a.created {
+		fmt.Fprintf(os.Stderr, "  %s\n", c)
+	}
+
+	if a.branch == "" || len(a.created) == 0 {
+		return nil
+	}
+
+	msg := fmt.Sprintf("Add %s advisories for new scan findings\n\n%s\n", a.status, strings.Join(a.created, "\n"))
+	hash, err := wgit.CommitToNewBranch(a.dir, a.branch, msg, a.paths)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "committed advisories to branch %s (%s)\n", a.branch, hash.String()[:7])
+	return nil
+}
@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/adrg/xdg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wolfi-dev/wolfictl/pkg/advisory"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	rwhttp "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/http"
+	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
+	"github.com/wolfi-dev/wolfictl/pkg/scan"
+)
+
+func TestAutoAdvisorWithPublishedData(t *testing.T) {
+	published := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(published, "bar.advisories.yaml"), []byte(`package:
+  name: bar
+
+advisories:
+  CVE-2023-0001:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: under_investigation
+`), 0o644))
+	var index bytes.Buffer
+	require.NoError(t, rwhttp.WriteIndex(&index, os.DirFS(published)))
+	require.NoError(t, os.WriteFile(filepath.Join(published, rwhttp.IndexFile), index.Bytes(), 0o644))
+
+	server := httptest.NewServer(http.FileServer(http.Dir(published)))
+	defer server.Close()
+
+	cacheHome := xdg.CacheHome
+	xdg.CacheHome = t.TempDir()
+	defer func() { xdg.CacheHome = cacheHome }()
+
+	repoDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, "foo.advisories.yaml"), []byte("package:\n  name: foo\n"), 0o644))
+
+	merged, cleanup, err := loadScanAdvisories(server.URL, []string{repoDir})
+	require.NoError(t, err)
+	defer cleanup()
+
+	a, err := newAutoAdvisor("under-investigation", []string{repoDir}, "", merged)
+	require.NoError(t, err)
+
+	findings := []*scan.Finding{
+		{Vulnerability: scan.Vulnerability{ID: "CVE-2023-0002"}},
+	}
+	require.NoError(t, a.file(&scan.PackageInfo{Name: "foo"}, nil, "", findings))
+	cleanup()
+
+	// The advisory was written to the repo dir, not to the temporary directory
+	// of the merged advisory data.
+	cfgs, err := advisoryconfigs.NewIndex(rwos.DirFS(repoDir))
+	require.NoError(t, err)
+	assert.NotNil(t, advisory.LatestForPackage(cfgs, "foo", "CVE-2023-0002"))
+	assert.Equal(t, []string{"foo.advisories.yaml"}, a.paths)
+}
@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/wolfi-dev/wolfictl/pkg/scan"
+)
+
+func addVulnerabilityAgeFlagsTo(cmd *cobra.Command, showAge *bool, minAge, maxAge *string) {
+	cmd.Flags().BoolVar(showAge, "show-age", false, "show how long ago each vulnerability was published (looked up via the OSV API)")
+	cmd.Flags().StringVar(minAge, "min-age", "", "only report vulnerabilities published at least this long ago, e.g. 30d or 12h (implies --show-age)")
+	cmd.Flags().StringVar(maxAge, "max-age", "", "only report vulnerabilities published at most this long ago, e.g. 30d or 12h (implies --show-age)")
+}
+
+// parseAge parses an age given as a number of days (e.g. "30d") or as a Go
+// duration (e.g. "12h"). An empty string is parsed as zero.
+func parseAge(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid age %q", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid age %q: must be a number of days (e.g. 30d) or a duration (e.g. 12h)", s)
+	}
+
+	return d, nil
+}
+
+// vulnerabilityAgeFilter annotates findings with their vulnerabilities'
+// published dates and filters them by age, as configured by the age flags.
+type vulnerabilityAgeFilter struct {
+	dates          *scan.PublishedDates
+	minAge, maxAge time.Duration
+}
+
+// newVulnerabilityAgeFilter returns a vulnerabilityAgeFilter for the given flag
+// values, or nil if published dates aren't needed.
+func newVulnerabilityAgeFilter(showAge bool, minAge, maxAge string) (*vulnerabilityAgeFilter, error) {
+	minAgeDuration, err := parseAge(minAge)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --min-age: %w", err)
+	}
+	maxAgeDuration, err := parseAge(maxAge)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --max-age: %w", err)
+	}
+
+	if !showAge && minAgeDuration == 0 && maxAgeDuration == 0 {
+		return nil, nil
+	}
+
+	return &vulnerabilityAgeFilter{
+		dates:  scan.NewPublishedDates(http.DefaultClient),
+		minAge: minAgeDuration,
+		maxAge: maxAgeDuration,
+	}, nil
+}
+
+func (f *vulnerabilityAgeFilter) apply(ctx context.Context, findings []*scan.Finding) ([]*scan.Finding, error) {
+	if f == nil {
+		return findings, nil
+	}
+
+	if err := f.dates.Annotate(ctx, findings); err != nil {
+		return nil, fmt.Errorf("unable to look up vulnerability published dates: %w", err)
+	}
+
+	return scan.FilterByAge(findings, time.Now(), f.minAge, f.maxAge), nil
+}
+
+func renderAge(vuln scan.Vulnerability) string {
+	if vuln.Published == nil {
+		return ""
+	}
+
+	days := int(vuln.Age(time.Now()).Hours() / 24)
+	return styleSubtle.Render(fmt.Sprintf(" published %dd ago", days))
+}
@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/wolfi-dev/wolfictl/pkg/scan"
+)
+
+func ScanAnnotate() *cobra.Command {
+	p := &scanAnnotateParams{}
+	cmd := &cobra.Command{
+		Use:   "annotate <vulnerability-id>",
+		Short: "Annotate findings of a vulnerability with a local triage note",
+		Long: fmt.Sprintf(`Annotate findings of a vulnerability with a local triage note.
+
+Annotations are kept in a project-local file (%s by default), separate from
+the distro's advisory data, for users who need to triage findings in packages
+they don't maintain. Later scans read the file and show each finding's
+annotations alongside it.
+
+An annotation applies to findings of the vulnerability (matched by ID or alias)
+in any package, or only in the package given by --package. Annotating the same
+vulnerability and package again replaces the previous annotation.`, scan.DefaultAnnotationsFile),
+		Example: `  wolfictl scan annotate CVE-2023-1234 --kind accepted-risk --ticket https://jira.example.com/browse/SEC-42
+
+  wolfictl scan annotate GHSA-xxxx-xxxx-xxxx --package golang.org/x/net --kind mitigated-by-config --note "HTTP/2 is disabled"`,
+		Args:          cobra.ExactArgs(1),
+		SilenceErrors: true,
+		RunE: func(_ *cobra.Command, args []string) error {
+			annotations, err := scan.ReadAnnotations(p.file)
+			if err != nil {
+				return err
+			}
+
+			err = annotations.Add(scan.Annotation{
+				Vulnerability: args[0],
+				Package:       p.packageName,
+				Kind:          p.kind,
+				Note:          p.note,
+				Ticket:        p.ticket,
+				Timestamp:     time.Now().UTC(),
+			})
+			if err != nil {
+				return err
+			}
+
+			return annotations.Write(p.file)
+		},
+	}
+
+	p.addFlagsTo(cmd)
+	return cmd
+}
+
+type scanAnnotateParams struct {
+	file        string
+	packageName string
+	kind        string
+	note        string
+	ticket      string
+}
+
+func (p *scanAnnotateParams) addFlagsTo(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&p.file, "file", scan.DefaultAnnotationsFile, "annotations file to update")
+	cmd.Flags().StringVar(&p.packageName, "package", "", "only annotate findings in the package with this name (e.g. a Go module path)")
+	cmd.Flags().StringVar(&p.kind, "kind", "", fmt.Sprintf("kind of annotation (%s)", strings.Join(scan.AnnotationKinds, ", ")))
+	_ = cmd.MarkFlagRequired("kind")
+	cmd.Flags().StringVar(&p.note, "note", "", "free-form note explaining the annotation")
+	cmd.Flags().StringVar(&p.ticket, "ticket", "", "link to where the finding is tracked, e.g. an issue")
+}
+
+func addAnnotationsFlagTo(cmd *cobra.Command, val *string) {
+	cmd.Flags().StringVar(val, "annotations", scan.DefaultAnnotationsFile, "file of local finding annotations (see 'wolfictl scan annotate') to show alongside findings, if it exists")
+}
+
+// renderAnnotations renders the finding's local annotations, if any, to follow
+// the finding in the tree output.
+func renderAnnotations(f *scan.Finding) string {
+	var rendered string
+	for _, a := range f.Annotations {
+		details := a.Kind
+		if a.Note != "" {
+			details += ": " + a.Note
+		}
+		if a.Ticket != "" {
+			details += " " + a.Ticket
+		}
+
+		rendered += styleSubtle.Render(fmt.Sprintf(" {%s}", details))
+	}
+
+	return rendered
+}
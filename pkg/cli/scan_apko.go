@@ -0,0 +1,223 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"chainguard.dev/apko/pkg/build/types"
+	"github.com/spf13/cobra"
+	"github.com/wolfi-dev/wolfictl/pkg/scan"
+	"github.com/wolfi-dev/wolfictl/pkg/trust"
+)
+
+func ScanApko() *cobra.Command {
+	p := &scanApkoParams{}
+	cmd := &cobra.Command{
+		Use:         "apko <path/to/apko.yaml>",
+		Short:       "Scan the packages that an apko configuration would install",
+		Annotations: scanPagerAnnotations,
+		Long: `Scan the packages that an apko configuration would install.
+
+The configuration's package list is resolved (including dependencies) against
+the repositories it declares, without building the image. The resolved apk
+files are then downloaded and scanned.`,
+		Example:       "wolfictl scan apko ./images/static/latest.apko.yaml",
+		Args:          cobra.ExactArgs(1),
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateScanOutputFormat(p.outputFormat); err != nil {
+				return err
+			}
+			if err := validateGroupBy(p.groupBy); err != nil {
+				return err
+			}
+
+			f, err := os.Open(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to open apko config: %w", err)
+			}
+			defer f.Close()
+
+			cfg, err := scan.DecodeApkoConfig(f)
+			if err != nil {
+				return err
+			}
+
+			arch := p.arch
+			if arch == "" {
+				arch = "x86_64"
+				if len(cfg.Archs) > 0 {
+					arch = cfg.Archs[0]
+				}
+			}
+
+			// apko allows arch names like "amd64", but repositories use APK arch names.
+			arch = types.ParseArchitecture(arch).ToAPK()
+
+			apks, err := scan.ResolveApkoConfig(cfg, arch)
+			if err != nil {
+				return err
+			}
+
+			var remediator *scan.Remediator
+			if p.remediation {
+				remediator, err = newRemediator(p.repositoryURL, arch)
+				if err != nil {
+					return err
+				}
+			}
+
+			ageFilter, err := newVulnerabilityAgeFilter(p.showAge, p.minAge, p.maxAge)
+			if err != nil {
+				return err
+			}
+
+			distro, err := parseDistroFlag(p.distro)
+			if err != nil {
+				return err
+			}
+
+			annotations, err := scan.ReadAnnotations(p.annotations)
+			if err != nil {
+				return err
+			}
+
+			var allFindings []*scan.Finding
+			linker := newSourceLinker(p.sourceRepository, p.outputFormat)
+			progress := newScanProgress(len(apks), p.quiet)
+			for i, apk := range apks {
+				name := fmt.Sprintf("%s-%s", apk.Package.Name, apk.Package.Version)
+				progress.next(i, name)
+				if p.outputFormat != scanOutputJSON {
+					fmt.Println(name)
+				}
+
+				result, err := scanRemoteAPK(apk.URL, scan.Options{
+					Catalogers:         p.catalogers,
+					DisabledCatalogers: p.disabledCatalogers,
+					Distro:             distro,
+				})
+				if err != nil {
+					return err
+				}
+
+				findings := scan.FilterByPackageType(result.Findings, p.onlyTypes, p.excludeTypes)
+				findings, err = ageFilter.apply(cmd.Context(), findings)
+				if err != nil {
+					return err
+				}
+				linker.annotate(findings)
+				annotations.Annotate(findings)
+
+				switch {
+				case p.outputFormat == scanOutputGitHub:
+					fmt.Println(renderGitHubAnnotations(apk.Package.Name, findings))
+				case p.outputFormat == scanOutputJSON:
+					out, err := renderJSON(name, result.APK, "", nil, findings, nil, nil, findingFilters(cmd))
+					if err != nil {
+						return err
+					}
+					fmt.Println(out)
+				case p.summary:
+					fmt.Println(renderSummary(findings))
+				case remediator != nil:
+					fmt.Println(renderFindingsWithRemediation(findings, p.groupBy, p.maxFindingsPerPackage, remediator, *result.APK))
+				default:
+					fmt.Println(renderFindings(findings, p.groupBy, p.maxFindingsPerPackage))
+				}
+				allFindings = append(allFindings, findings...)
+			}
+			progress.done()
+
+			if p.outputFormat != scanOutputJSON {
+				fmt.Printf("\n%d vulnerabilities found across %d packages\n", len(allFindings), len(apks))
+			}
+
+			if p.severityExitCodes {
+				if err := severityExitError(allFindings); err != nil {
+					return err
+				}
+			}
+
+			if p.requireZeroFindings && len(allFindings) > 0 {
+				return fmt.Errorf("more than 0 vulnerabilities found")
+			}
+
+			return nil
+		},
+	}
+
+	p.addFlagsTo(cmd)
+	return cmd
+}
+
+type scanApkoParams struct {
+	requireZeroFindings   bool
+	arch                  string
+	summary               bool
+	remediation           bool
+	repositoryURL         string
+	outputFormat          string
+	severityExitCodes     bool
+	onlyTypes             []string
+	excludeTypes          []string
+	catalogers            []string
+	disabledCatalogers    []string
+	quiet                 bool
+	showAge               bool
+	minAge                string
+	maxAge                string
+	groupBy               string
+	maxFindingsPerPackage int
+	distro                string
+	sourceRepository      string
+	annotations           string
+}
+
+func (p *scanApkoParams) addFlagsTo(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&p.requireZeroFindings, "require-zero", false, "exit 1 if any vulnerabilities are found")
+	cmd.Flags().StringVar(&p.arch, "arch", "", "architecture to resolve packages for (default: the first arch listed in the config, or x86_64)")
+	cmd.Flags().BoolVar(&p.summary, "summary", false, "print a table of vulnerability counts by severity for each package instead of listing every finding")
+	addRemediationFlagsTo(cmd, &p.remediation, &p.repositoryURL)
+	addScanOutputFlagTo(cmd, &p.outputFormat)
+	addSourceRepositoryFlagTo(cmd, &p.sourceRepository)
+	addSeverityExitCodesFlagTo(cmd, &p.severityExitCodes)
+	addPackageTypeFilterFlagsTo(cmd, &p.onlyTypes, &p.excludeTypes)
+	addCatalogerFlagsTo(cmd, &p.catalogers, &p.disabledCatalogers)
+	addDistroFlagTo(cmd, &p.distro)
+	addVulnerabilityAgeFlagsTo(cmd, &p.showAge, &p.minAge, &p.maxAge)
+	addGroupByFlagTo(cmd, &p.groupBy)
+	addAnnotationsFlagTo(cmd, &p.annotations)
+	addMaxFindingsPerPackageFlagTo(cmd, &p.maxFindingsPerPackage)
+	addQuietFlagTo(cmd, &p.quiet)
+}
+
+// apkDownloadClient is used to download the apk files to scan. Its timeout
+// bounds the whole download, so a stalled server can't hang the scan.
+var apkDownloadClient = &http.Client{Timeout: 5 * time.Minute}
+
+func scanRemoteAPK(url string, opts scan.Options) (*scan.Result, error) {
+	resp, err := apkDownloadClient.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download apk file: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download apk file: GET %s: %d", url, resp.StatusCode)
+	}
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download apk file: %w", err)
+	}
+	if err := trust.EnforceAPK(url, b); err != nil {
+		return nil, err
+	}
+
+	return scan.APK(bytes.NewReader(b), opts)
+}
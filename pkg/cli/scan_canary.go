@@ -0,0 +1,242 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wolfi-dev/wolfictl/pkg/apk"
+	"github.com/wolfi-dev/wolfictl/pkg/index"
+	"github.com/wolfi-dev/wolfictl/pkg/scan"
+	"golang.org/x/exp/slices"
+)
+
+func ScanCanary() *cobra.Command {
+	p := &scanCanaryParams{}
+	cmd := &cobra.Command{
+		Use:         "canary <path/to/package.apk | path/to/dir> ...",
+		Short:       "Gate publication of newly built packages on not introducing new vulnerabilities",
+		Annotations: pagerAnnotations,
+		Long: `Gate publication of newly built packages on not introducing new vulnerabilities.
+
+Each given apk file (or each apk file in each given directory, such as a local
+build output directory or a synced copy of a staging bucket) is scanned, along
+with the version of the same package that's currently published to the
+repository. The candidate fails the gate if it has any findings with a
+severity of at least --fail-on that the published version doesn't have.
+
+A verdict for each candidate is written to the --verdict file as JSON. If
+--signing-key is given, the verdict file is signed with that (RSA) key, and the
+signature is written alongside it with a ".sig" suffix.
+
+The command exits 1 if any candidate fails the gate.`,
+		Example:       `  wolfictl scan canary ./packages/x86_64 --signing-key ./wolfi-signing.rsa`,
+		Args:          cobra.MinimumNArgs(1),
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !slices.Contains(scan.Severities, p.failOn) {
+				return fmt.Errorf("invalid --fail-on severity %q (must be one of %s)", p.failOn, strings.Join(scan.Severities, ", "))
+			}
+
+			var signingKey []byte
+			if p.signingKey != "" {
+				var err error
+				signingKey, err = os.ReadFile(p.signingKey)
+				if err != nil {
+					return fmt.Errorf("unable to read signing key: %w", err)
+				}
+			}
+
+			var candidates []string
+			for _, arg := range args {
+				files, err := apkFilesAt(arg)
+				if err != nil {
+					return err
+				}
+				candidates = append(candidates, files...)
+			}
+			if len(candidates) == 0 {
+				return fmt.Errorf("no apk files found to scan")
+			}
+
+			idx, err := index.Index(p.arch, p.repositoryURL)
+			if err != nil {
+				return fmt.Errorf("unable to load APKINDEX for %s: %w", p.arch, err)
+			}
+			r := apk.NewResolver()
+			r.AddIndex(p.repositoryURL, idx)
+
+			distro, err := parseDistroFlag(p.distro)
+			if err != nil {
+				return err
+			}
+
+			opts := scan.Options{
+				Catalogers:         p.catalogers,
+				DisabledCatalogers: p.disabledCatalogers,
+				Distro:             distro,
+			}
+
+			var verdicts []*scan.CanaryVerdict
+			progress := newScanProgress(len(candidates), p.quiet)
+			for i, candidate := range candidates {
+				progress.next(i, filepath.Base(candidate))
+				fmt.Println(filepath.Base(candidate))
+
+				v, err := canaryVerdict(candidate, r, p.arch, p.failOn, opts)
+				if err != nil {
+					return err
+				}
+
+				fmt.Println(renderCanaryVerdict(v))
+				verdicts = append(verdicts, v)
+			}
+			progress.done()
+
+			if err := writeVerdicts(verdicts, p.verdictOutputLocation, signingKey); err != nil {
+				return err
+			}
+
+			var failed int
+			for _, v := range verdicts {
+				if !v.Passed {
+					failed++
+				}
+			}
+			if failed > 0 {
+				return fmt.Errorf("%d of %d packages introduce new vulnerabilities of %s severity or higher", failed, len(verdicts), p.failOn)
+			}
+
+			return nil
+		},
+	}
+
+	p.addFlagsTo(cmd)
+	return cmd
+}
+
+type scanCanaryParams struct {
+	arch                  string
+	repositoryURL         string
+	failOn                string
+	verdictOutputLocation string
+	signingKey            string
+	catalogers            []string
+	disabledCatalogers    []string
+	distro                string
+	quiet                 bool
+}
+
+func (p *scanCanaryParams) addFlagsTo(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&p.arch, "arch", "x86_64", "architecture of the apk files being scanned")
+	cmd.Flags().StringVar(&p.repositoryURL, "repository", "https://packages.wolfi.dev/os", "package repository to which the packages would be published")
+	cmd.Flags().StringVar(&p.failOn, "fail-on", "Critical", fmt.Sprintf("minimum severity of a new finding that fails the gate (%s)", strings.Join(scan.Severities, ", ")))
+	cmd.Flags().StringVar(&p.verdictOutputLocation, "verdict", "canary-verdict.json", "file to write the verdicts to")
+	cmd.Flags().StringVar(&p.signingKey, "signing-key", "", "if set, RSA key to use to sign the verdict file")
+	addCatalogerFlagsTo(cmd, &p.catalogers, &p.disabledCatalogers)
+	addDistroFlagTo(cmd, &p.distro)
+	addQuietFlagTo(cmd, &p.quiet)
+}
+
+// apkFilesAt returns the given path if it's a file, or the apk files directly
+// within it if it's a directory.
+func apkFilesAt(p string) ([]string, error) {
+	info, err := os.Stat(p)
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.IsDir() {
+		return []string{p}, nil
+	}
+
+	return filepath.Glob(filepath.Join(p, "*.apk"))
+}
+
+// canaryVerdict scans the candidate apk file and the published version of the
+// same package, if there is one, and compares their findings.
+func canaryVerdict(candidatePath string, r *apk.Resolver, arch, failOn string, opts scan.Options) (*scan.CanaryVerdict, error) {
+	f, err := os.Open(candidatePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open apk file: %w", err)
+	}
+	defer f.Close()
+
+	candidate, err := scan.APK(f, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var published *scan.PackageInfo
+	var publishedFindings []*scan.Finding
+
+	if pkg, ok := r.Resolve(candidate.APK.Name); ok && pkg.Name == candidate.APK.Name {
+		url := fmt.Sprintf("%s/%s/%s-%s.apk", strings.TrimSuffix(pkg.Repository, "/"), arch, pkg.Name, pkg.Version)
+		result, err := scanRemoteAPK(url, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		published = result.APK
+		publishedFindings = result.Findings
+	}
+
+	return scan.NewCanaryVerdict(candidate.APK, candidate.Findings, published, publishedFindings, failOn)
+}
+
+func writeVerdicts(verdicts []*scan.CanaryVerdict, location string, signingKey []byte) error {
+	var payload, signature []byte
+	if signingKey != nil {
+		var err error
+		payload, signature, err = scan.SignVerdicts(verdicts, signingKey)
+		if err != nil {
+			return err
+		}
+	} else {
+		var err error
+		payload, err = json.MarshalIndent(verdicts, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode verdicts: %w", err)
+		}
+	}
+
+	if err := os.WriteFile(location, payload, 0o644); err != nil { //nolint:gosec
+		return fmt.Errorf("unable to write verdicts: %w", err)
+	}
+
+	if signature != nil {
+		if err := os.WriteFile(location+".sig", signature, 0o644); err != nil { //nolint:gosec
+			return fmt.Errorf("unable to write verdict signature: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func renderCanaryVerdict(v *scan.CanaryVerdict) string {
+	var lines []string
+
+	if v.PublishedVersion == "" {
+		lines = append(lines, styleSubtle.Render("  not yet published; all findings are new"))
+	} else {
+		lines = append(lines, styleSubtle.Render(fmt.Sprintf("  compared with published version %s", v.PublishedVersion)))
+	}
+
+	for _, f := range v.NewFindings {
+		lines = append(lines, fmt.Sprintf("  + %s %s %s", renderSeverity(f.Severity), f.Vulnerability, styleSubtle.Render(fmt.Sprintf("(%s %s)", f.Package, f.Version))))
+	}
+	for _, f := range v.ResolvedFindings {
+		lines = append(lines, fmt.Sprintf("  - %s %s %s", renderSeverity(f.Severity), f.Vulnerability, styleSubtle.Render(fmt.Sprintf("(%s)", f.Package))))
+	}
+
+	if v.Passed {
+		lines = append(lines, fmt.Sprintf("  ✅ passed (no new findings of %s severity or higher)", v.FailOn))
+	} else {
+		lines = append(lines, fmt.Sprintf("  ❌ failed (new findings of %s severity or higher)", v.FailOn))
+	}
+
+	return strings.Join(lines, "\n")
+}
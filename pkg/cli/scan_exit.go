@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/wolfi-dev/wolfictl/pkg/scan"
+)
+
+// severityExitCodes maps the highest severity found during a scan to the exit
+// code used when --severity-exit-codes is set.
+var severityExitCodes = map[string]int{
+	"Unknown":    10,
+	"Negligible": 10,
+	"Low":        11,
+	"Medium":     12,
+	"High":       13,
+	"Critical":   14,
+}
+
+func addSeverityExitCodesFlagTo(cmd *cobra.Command, val *bool) {
+	cmd.Flags().BoolVar(val, "severity-exit-codes", false, "exit with a code indicating the highest severity found: 10 (unknown or negligible), 11 (low), 12 (medium), 13 (high), 14 (critical)")
+}
+
+// ExitCodeError is returned by a command that needs the process to exit with a
+// particular code. Returning it, rather than exiting directly, lets deferred
+// calls and finalizers (such as stopping the pager) run first. The command is
+// expected to have reported why already.
+type ExitCodeError struct {
+	Code int
+}
+
+func (e *ExitCodeError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
+// severityExitError returns an ExitCodeError with the code corresponding to the
+// highest severity among the findings, or nil if there are no findings.
+func severityExitError(findings []*scan.Finding) error {
+	highest := scan.HighestSeverity(findings)
+	if highest == "" {
+		return nil
+	}
+
+	fmt.Fprintf(os.Stderr, "highest severity found: %s\n", highest)
+	return &ExitCodeError{Code: severityExitCodes[highest]}
+}
@@ -0,0 +1,23 @@
+package cli
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wolfi-dev/wolfictl/pkg/scan"
+)
+
+func TestSeverityExitError(t *testing.T) {
+	assert.NoError(t, severityExitError(nil))
+
+	findings := []*scan.Finding{
+		{Vulnerability: scan.Vulnerability{ID: "CVE-2023-1", Severity: "Low"}},
+		{Vulnerability: scan.Vulnerability{ID: "CVE-2023-2", Severity: "High"}},
+	}
+
+	var exitErr *ExitCodeError
+	if assert.True(t, errors.As(severityExitError(findings), &exitErr)) {
+		assert.Equal(t, 13, exitErr.Code)
+	}
+}
@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wolfi-dev/wolfictl/pkg/scan"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	scanOutputTree   = "tree"
+	scanOutputGitHub = "github"
+	scanOutputJSON   = "json"
+)
+
+var scanOutputFormats = []string{scanOutputTree, scanOutputGitHub, scanOutputJSON}
+
+func addScanOutputFlagTo(cmd *cobra.Command, val *string) {
+	cmd.Flags().StringVarP(val, "output", "o", scanOutputTree, fmt.Sprintf("output format (%s)", strings.Join(scanOutputFormats, ", ")))
+}
+
+func validateScanOutputFormat(format string) error {
+	if slices.Contains(scanOutputFormats, format) {
+		return nil
+	}
+
+	return fmt.Errorf("invalid output format %q, must be one of [%s]", format, strings.Join(scanOutputFormats, ", "))
+}
+
+// renderGitHubAnnotations renders the findings for the named scan target as
+// GitHub Actions workflow commands, so that they show up as annotations on the
+// workflow run.
+//
+// See https://docs.github.com/en/actions/using-workflows/workflow-commands-for-github-actions.
+func renderGitHubAnnotations(target string, findings []*scan.Finding) string {
+	lines := make([]string, 0, len(findings))
+	for _, f := range findings {
+		title := fmt.Sprintf("%s in %s", f.Vulnerability.ID, target)
+		message := fmt.Sprintf(
+			"%s vulnerability %s found in %s %s (%s) at %s",
+			f.Vulnerability.Severity,
+			f.Vulnerability.ID,
+			f.Package.Name,
+			f.Package.Version,
+			f.Package.Type,
+			f.Package.Location,
+		)
+		if f.Vulnerability.FixedVersion != "" {
+			message += fmt.Sprintf("; fixed in %s", f.Vulnerability.FixedVersion)
+		}
+
+		lines = append(lines, fmt.Sprintf(
+			"::%s title=%s::%s",
+			githubAnnotationLevel(f.Vulnerability.Severity),
+			escapeGitHubProperty(title),
+			escapeGitHubData(message),
+		))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func githubAnnotationLevel(severity string) string {
+	switch severity {
+	case "Critical", "High":
+		return "error"
+	case "Medium":
+		return "warning"
+	default:
+		return "notice"
+	}
+}
+
+func escapeGitHubData(s string) string {
+	return strings.NewReplacer(
+		"%", "%25",
+		"\r", "%0D",
+		"\n", "%0A",
+	).Replace(s)
+}
+
+func escapeGitHubProperty(s string) string {
+	return strings.NewReplacer(
+		"%", "%25",
+		"\r", "%0D",
+		"\n", "%0A",
+		":", "%3A",
+		",", "%2C",
+	).Replace(s)
+}
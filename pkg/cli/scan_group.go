@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wolfi-dev/wolfictl/pkg/scan"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	groupByLocation      = "location"
+	groupByVulnerability = "vuln"
+)
+
+var groupByValues = []string{groupByLocation, groupByVulnerability}
+
+func addGroupByFlagTo(cmd *cobra.Command, val *string) {
+	cmd.Flags().StringVar(val, "group-by", groupByLocation, fmt.Sprintf("how to group findings in the output (%s)", strings.Join(groupByValues, ", ")))
+}
+
+func validateGroupBy(groupBy string) error {
+	if !slices.Contains(groupByValues, groupBy) {
+		return fmt.Errorf("invalid --group-by value %q (must be one of %s)", groupBy, strings.Join(groupByValues, ", "))
+	}
+
+	return nil
+}
+
+// renderByVulnerability renders the findings with each vulnerability listed
+// once, most severe first, and every affected package and location beneath it.
+func (t findingsTree) renderByVulnerability() string {
+	byVuln := make(map[string][]*scan.Finding)
+	var ids []string
+	for _, f := range t.findings {
+		id := f.Vulnerability.ID
+		if _, ok := byVuln[id]; !ok {
+			ids = append(ids, id)
+		}
+		byVuln[id] = append(byVuln[id], f)
+	}
+
+	severityRank := func(id string) int {
+		if i := slices.Index(scan.Severities, byVuln[id][0].Vulnerability.Severity); i >= 0 {
+			return i
+		}
+		return len(scan.Severities)
+	}
+	sort.SliceStable(ids, func(i, j int) bool {
+		if ri, rj := severityRank(ids[i]), severityRank(ids[j]); ri != rj {
+			return ri < rj
+		}
+		return ids[i] < ids[j]
+	})
+
+	var lines []string
+	for i, id := range ids {
+		treeStem, verticalLine := "├── ", "│"
+		if i == len(ids)-1 {
+			treeStem, verticalLine = "└── ", " "
+		}
+
+		findings := byVuln[id]
+		vuln := findings[0].Vulnerability
+		lines = append(lines, fmt.Sprintf(
+			"%s%s %s%s %s",
+			treeStem,
+			renderSeverity(vuln.Severity),
+			renderVulnerabilityID(vuln),
+			renderAge(vuln),
+			styleSubtle.Render(fmt.Sprintf("(%d affected)", len(findings))),
+		))
+
+		sort.SliceStable(findings, func(i, j int) bool {
+			if findings[i].Package.Name != findings[j].Package.Name {
+				return findings[i].Package.Name < findings[j].Package.Name
+			}
+			return findings[i].Package.Location < findings[j].Package.Location
+		})
+
+		for _, f := range findings {
+			lines = append(lines, fmt.Sprintf(
+				"%s       📦 %s %s %s%s%s%s",
+				verticalLine,
+				hyperlinkPackageName(f.Package),
+				f.Package.Version,
+				styleSubtle.Render("("+f.Package.Type+")"),
+				renderFixedIn(f.Vulnerability),
+				renderAdvisory(f),
+				renderAnnotations(f),
+			))
+			lines = append(lines, fmt.Sprintf("%s           📄 %s", verticalLine, styleSubtle.Render(f.Package.Location)))
+
+			if t.hint != nil {
+				lines = append(lines, fmt.Sprintf("%s           %s", verticalLine, styleSubtle.Render(t.hint(f))))
+			}
+		}
+
+		lines = append(lines, verticalLine)
+	}
+
+	return strings.Join(lines, "\n")
+}
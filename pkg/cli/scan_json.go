@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/wolfi-dev/wolfictl/pkg/scan"
+)
+
+// renderJSON renders the findings for the named scan target as a single line
+// of JSON, so that the output for multiple targets is a JSON Lines stream. apk
+// describes the target if it's an APK file, and may be nil. digest and scanner
+// record the target file's digest and what produced the findings, if known.
+// suppressed, if any, are the findings suppressed by VEX statements, and filters
+// the options that left findings out (see findingFilters).
+func renderJSON(target string, apk *scan.PackageInfo, digest string, scanner *scan.Scanner, findings []*scan.Finding, secrets []scan.SecretFinding, suppressed []scan.SuppressedFinding, filters []string) (string, error) {
+	if findings == nil {
+		findings = []*scan.Finding{}
+	}
+
+	b, err := json.Marshal(scan.JSONResult{
+		Target:     target,
+		APK:        apk,
+		Digest:     digest,
+		Scanner:    scanner,
+		Findings:   findings,
+		Secrets:    secrets,
+		Suppressed: suppressed,
+		Filters:    filters,
+	})
+	if err != nil {
+		return "", fmt.Errorf("unable to render findings as JSON: %w", err)
+	}
+
+	return string(b), nil
+}
+
+// findingFilterFlags are the scan flags that leave findings out of the output.
+var findingFilterFlags = []string{"only-type", "exclude-type", "min-age", "max-age", "baseline", "vex"}
+
+// findingFilters returns the names of the flags set on the given scan command
+// that leave findings out of its output, so that consumers of JSON output can
+// tell whether it lists every vulnerability the scanner detected.
+func findingFilters(cmd *cobra.Command) []string {
+	var filters []string
+	for _, name := range findingFilterFlags {
+		if cmd.Flags().Changed(name) {
+			filters = append(filters, name)
+		}
+	}
+
+	return filters
+}
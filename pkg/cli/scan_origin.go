@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/wolfi-dev/wolfictl/pkg/scan"
+)
+
+// originHeader returns the heading shown above the collapsed findings for an
+// origin package when scanning with --by-origin.
+func originHeader(o *scan.OriginFindings, arch string) string {
+	header := fmt.Sprintf("%s (%s)", o.Origin, strings.Join(o.Subpackages, ", "))
+	if arch != "" {
+		header = fmt.Sprintf("%s [%s]", header, arch)
+	}
+	return header
+}
+
+// renderOriginFindings renders the collapsed findings for an origin package,
+// noting which of the origin's subpackages each finding was found in.
+func renderOriginFindings(o *scan.OriginFindings, groupBy string, maxPerPackage int, summary bool) string {
+	if summary {
+		return renderSummary(o.Findings)
+	}
+
+	if len(o.Findings) == 0 {
+		return "✅ No vulnerabilities found"
+	}
+
+	t := newFindingsTree(o.Findings)
+	t.maxPerPackage = maxPerPackage
+	if len(o.Subpackages) > 1 {
+		t.hint = func(f *scan.Finding) string {
+			return fmt.Sprintf("found in %s", strings.Join(o.FoundIn[f], ", "))
+		}
+	}
+
+	if groupBy == groupByVulnerability {
+		return t.renderByVulnerability()
+	}
+
+	return t.render()
+}
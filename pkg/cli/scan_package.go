@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/adrg/xdg"
+	"github.com/wolfi-dev/wolfictl/pkg/apk"
+	"github.com/wolfi-dev/wolfictl/pkg/index"
+	"github.com/wolfi-dev/wolfictl/pkg/trust"
+)
+
+var apkCacheDir = filepath.Join(xdg.CacheHome, "wolfictl", "apk")
+
+// apkFilesForPackages resolves the latest version of each named package in the
+// repository's APKINDEX, and returns the paths to the apk files, downloading
+// any that aren't already cached.
+func apkFilesForPackages(names []string, repositoryURL, arch string) ([]string, error) {
+	idx, err := index.Index(arch, repositoryURL)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load APKINDEX for %s: %w", arch, err)
+	}
+	r := apk.NewResolver()
+	r.AddIndex(repositoryURL, idx)
+
+	var apkFiles []string
+	for _, name := range names {
+		pkg, ok := r.Resolve(name)
+		if !ok || pkg.Name != name {
+			return nil, fmt.Errorf("package %q not found in %s for %s", name, repositoryURL, arch)
+		}
+
+		url := fmt.Sprintf("%s/%s/%s-%s.apk", strings.TrimSuffix(pkg.Repository, "/"), arch, pkg.Name, pkg.Version)
+		p, err := cachedAPK(url, filepath.Join(apkCacheDir, arch))
+		if err != nil {
+			return nil, err
+		}
+		apkFiles = append(apkFiles, p)
+	}
+
+	return apkFiles, nil
+}
+
+// cachedAPK returns the path to the apk file at the given URL within cacheDir,
+// downloading it first if it's not there yet. Published apk files never change,
+// so a cached file is always used as is.
+func cachedAPK(url, cacheDir string) (string, error) {
+	p := filepath.Join(cacheDir, filepath.Base(url))
+	if _, err := os.Stat(p); err == nil {
+		return p, nil
+	}
+
+	resp, err := apkDownloadClient.Get(url)
+	if err != nil {
+		return "", fmt.Errorf("failed to download apk file: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to download apk file: GET %s: %d", url, resp.StatusCode)
+	}
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to download apk file: %w", err)
+	}
+	if err := trust.EnforceAPK(url, b); err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(cacheDir, 0o755); err != nil {
+		return "", fmt.Errorf("unable to create apk cache directory: %w", err)
+	}
+
+	// Write to a temp file first, so that an interrupted download is never
+	// mistaken for a cached apk file.
+	tmp, err := os.CreateTemp(cacheDir, ".download-*")
+	if err != nil {
+		return "", fmt.Errorf("unable to cache apk file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("unable to cache apk file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("unable to cache apk file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), p); err != nil {
+		return "", fmt.Errorf("unable to cache apk file: %w", err)
+	}
+
+	return p, nil
+}
@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"context"
+
+	"github.com/wolfi-dev/wolfictl/pkg/scan"
+	"golang.org/x/sync/errgroup"
+)
+
+// defaultScanParallelism is the default number of inputs scanned at once. Each
+// scan already catalogs its files in parallel, so a few at a time is enough to
+// keep the CPUs busy while others wait on I/O.
+const defaultScanParallelism = 4
+
+// scanJob is the scan of a single input, which may run ahead of the input's
+// turn to be reported.
+type scanJob struct {
+	done chan struct{}
+
+	result *scan.Result
+	digest string
+	err    error
+}
+
+// wait blocks until the scan has finished, and returns its outcome.
+func (j *scanJob) wait() (*scan.Result, string, error) {
+	<-j.done
+	return j.result, j.digest, j.err
+}
+
+// startScans starts scanning the given inputs with scanFunc, at most limit at a
+// time, in input order, and returns a job for each input. Waiting on the jobs in
+// turn reports the results in input order, just as scanning them one at a time
+// would. A failed scan doesn't stop the others, so that the first error in input
+// order is the one reported; canceling ctx stops any scans that haven't started.
+func startScans(ctx context.Context, inputs []string, limit int, scanFunc func(input string) (*scan.Result, string, error)) []*scanJob {
+	jobs := make([]*scanJob, len(inputs))
+	for i := range jobs {
+		jobs[i] = &scanJob{done: make(chan struct{})}
+	}
+
+	var g errgroup.Group
+	g.SetLimit(limit)
+
+	go func() {
+		for i, input := range inputs {
+			job, input := jobs[i], input
+			g.Go(func() error {
+				defer close(job.done)
+
+				if err := ctx.Err(); err != nil {
+					job.err = err
+					return nil
+				}
+
+				job.result, job.digest, job.err = scanFunc(input)
+				return nil
+			})
+		}
+	}()
+
+	return jobs
+}
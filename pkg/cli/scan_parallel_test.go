@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wolfi-dev/wolfictl/pkg/scan"
+)
+
+func TestStartScans(t *testing.T) {
+	inputs := []string{"a.apk", "b.apk", "c.apk", "d.apk", "e.apk"}
+
+	var running, maxRunning int32
+	fakeScan := func(input string) (*scan.Result, string, error) {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			m := atomic.LoadInt32(&maxRunning)
+			if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+				break
+			}
+		}
+
+		// Later inputs finish first, to show that results are still reported in
+		// input order.
+		time.Sleep(time.Duration(len(inputs)-int(input[0]-'a')) * 5 * time.Millisecond)
+
+		if input == "d.apk" {
+			return nil, "", errors.New("bad apk")
+		}
+		return &scan.Result{APK: &scan.PackageInfo{Name: input}}, "digest-" + input, nil
+	}
+
+	jobs := startScans(context.Background(), inputs, 2, fakeScan)
+	for i, input := range inputs {
+		result, digest, err := jobs[i].wait()
+		if input == "d.apk" {
+			assert.EqualError(t, err, "bad apk")
+			continue
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, input, result.APK.Name)
+		assert.Equal(t, "digest-"+input, digest)
+	}
+
+	assert.LessOrEqual(t, maxRunning, int32(2))
+}
+
+func TestStartScansCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var scanned int32
+	jobs := startScans(ctx, []string{"a.apk", "b.apk", "c.apk"}, 1, func(string) (*scan.Result, string, error) {
+		atomic.AddInt32(&scanned, 1)
+		cancel()
+		return &scan.Result{}, "", nil
+	})
+
+	_, _, err := jobs[0].wait()
+	assert.NoError(t, err)
+	for _, j := range jobs[1:] {
+		_, _, err := j.wait()
+		assert.ErrorIs(t, err, context.Canceled)
+	}
+	assert.Equal(t, int32(1), scanned)
+}
+
+// BenchmarkStartScans measures scanning 16 inputs whose scans each take 20ms,
+// at increasing parallelism.
+func BenchmarkStartScans(b *testing.B) {
+	inputs := make([]string, 16)
+	for i := range inputs {
+		inputs[i] = fmt.Sprintf("%d.apk", i)
+	}
+	fakeScan := func(string) (*scan.Result, string, error) {
+		time.Sleep(20 * time.Millisecond)
+		return &scan.Result{}, "", nil
+	}
+
+	for _, limit := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("parallelism=%d", limit), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				for _, j := range startScans(context.Background(), inputs, limit, fakeScan) {
+					_, _, _ = j.wait()
+				}
+			}
+		})
+	}
+}
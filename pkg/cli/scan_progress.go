@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func addQuietFlagTo(cmd *cobra.Command, val *bool) {
+	cmd.Flags().BoolVarP(val, "quiet", "q", false, "don't print scan progress to stderr")
+}
+
+// scanProgress reports progress through a batch of scans, so that long runs
+// don't look like the tool has hung.
+type scanProgress struct {
+	w     io.Writer
+	total int
+	start time.Time
+}
+
+// newScanProgress returns a scanProgress for a batch of the given size. Progress
+// isn't reported when quiet is true or when there's only one scan in the batch.
+func newScanProgress(total int, quiet bool) *scanProgress {
+	w := io.Writer(os.Stderr)
+	if quiet || total < 2 {
+		w = io.Discard
+	}
+
+	return &scanProgress{
+		w:     w,
+		total: total,
+		start: time.Now(),
+	}
+}
+
+// next reports that the scan at the given (zero-based) index in the batch is
+// starting.
+func (p *scanProgress) next(i int, name string) {
+	elapsed := time.Since(p.start)
+
+	line := fmt.Sprintf("[%d/%d] scanning %s (elapsed %s", i+1, p.total, name, elapsed.Round(time.Second))
+	if i > 0 {
+		remaining := elapsed / time.Duration(i) * time.Duration(p.total-i)
+		line += fmt.Sprintf(", about %s left", remaining.Round(time.Second))
+	}
+	line += ")"
+
+	_, _ = fmt.Fprintln(p.w, styleSubtle.Render(line))
+}
+
+// done reports that the batch is complete.
+func (p *scanProgress) done() {
+	_, _ = fmt.Fprintln(p.w, styleSubtle.Render(fmt.Sprintf("scanned %d files in %s", p.total, time.Since(p.start).Round(time.Second))))
+}
@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/savioxavier/termlink"
+	"github.com/spf13/cobra"
+	"github.com/wolfi-dev/wolfictl/pkg/scan"
+)
+
+func addSourceRepositoryFlagTo(cmd *cobra.Command, val *string) {
+	cmd.Flags().StringVar(val, "source-repository", scan.DefaultSourceRepository, "GitHub repository of the melange configs that build the scanned apk packages, used to link each apk package finding to its config")
+}
+
+// sourceLinker links APK package findings to the melange configs that build
+// them, when the output would show the links.
+type sourceLinker struct {
+	resolver *scan.SourceResolver
+	warned   bool
+}
+
+// newSourceLinker returns a sourceLinker for the given repository, or nil if
+// the given output format wouldn't show links.
+func newSourceLinker(repositoryURL, outputFormat string) *sourceLinker {
+	if outputFormat != scanOutputJSON && !(outputFormat == scanOutputTree && termSupportsHyperlinks) {
+		return nil
+	}
+
+	return &sourceLinker{resolver: scan.NewSourceResolver(repositoryURL)}
+}
+
+// annotate sets the source locations of the given findings' APK packages. Since
+// the links are only a convenience, failing to resolve them is a warning, which
+// is shown once.
+func (l *sourceLinker) annotate(findings []*scan.Finding) {
+	if l == nil {
+		return
+	}
+
+	if err := l.resolver.Annotate(findings); err != nil && !l.warned {
+		fmt.Fprintf(os.Stderr, "⚠️  unable to link some packages to their melange configs: %v\n", err)
+		l.warned = true
+	}
+}
+
+// hyperlinkPackageName links the package's name to its melange config, if
+// known.
+func hyperlinkPackageName(p scan.Package) string {
+	if !termSupportsHyperlinks || p.Source == nil {
+		return p.Name
+	}
+
+	return termlink.Link(p.Name, p.Source.URL)
+}
@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wolfi-dev/wolfictl/pkg/scan"
+)
+
+const testPackageConfig = `package:
+  name: foo
+  version: 1.2.3
+  epoch: 4
+
+data:
+  - name: modules
+    items:
+      bar: Bar module
+      baz: Baz module
+
+subpackages:
+  - name: ${{package.name}}-doc
+  - name: ${{package.name}}-dev
+  - range: modules
+    name: ${{package.name}}-${{range.key}}
+`
+
+func TestAPKFilesForConfig(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "foo.yaml")
+	require.NoError(t, os.WriteFile(cfgPath, []byte(testPackageConfig), 0o644))
+
+	packagesDir := filepath.Join(dir, "packages")
+	archDir := filepath.Join(packagesDir, "x86_64")
+	require.NoError(t, os.MkdirAll(archDir, 0o755))
+
+	// foo-dev wasn't built, so it's skipped.
+	for _, name := range []string{"foo", "foo-doc", "foo-bar", "foo-baz"} {
+		require.NoError(t, os.WriteFile(filepath.Join(archDir, name+"-1.2.3-r4.apk"), nil, 0o644))
+	}
+
+	apkFiles, err := apkFilesForConfig(cfgPath, packagesDir, "x86_64")
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(archDir, "foo-1.2.3-r4.apk"),
+		filepath.Join(archDir, "foo-doc-1.2.3-r4.apk"),
+		filepath.Join(archDir, "foo-bar-1.2.3-r4.apk"),
+		filepath.Join(archDir, "foo-baz-1.2.3-r4.apk"),
+	}, apkFiles)
+
+	_, err = apkFilesForConfig(cfgPath, packagesDir, "aarch64")
+	assert.Error(t, err, "the origin package wasn't built for aarch64")
+}
+
+func TestWriteSBOMUnsupportedFormat(t *testing.T) {
+	location := filepath.Join(t.TempDir(), "sbom.json")
+	require.NoError(t, os.WriteFile(location, []byte("existing"), 0o644))
+
+	err := writeSBOM(&scan.Result{}, location, "spdx-yaml")
+	assert.ErrorContains(t, err, `unsupported SBOM format "spdx-yaml"`)
+
+	b, err := os.ReadFile(location)
+	require.NoError(t, err)
+	assert.Equal(t, "existing", string(b), "the existing file must be left alone")
+}
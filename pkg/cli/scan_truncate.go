@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/wolfi-dev/wolfictl/pkg/scan"
+	"golang.org/x/exp/slices"
+)
+
+func addMaxFindingsPerPackageFlagTo(cmd *cobra.Command, val *int) {
+	cmd.Flags().IntVar(val, "max-findings-per-package", 0, "in tree output grouped by location, show at most this many findings (the most severe) for each package, collapsing the rest; 0 shows all (machine-readable outputs are never truncated)")
+}
+
+// truncateFindings returns the max most severe of the given findings, in their
+// original order, along with how many were left out. If max isn't positive, all
+// findings are returned.
+func truncateFindings(findings []*scan.Finding, max int) (shown []*scan.Finding, hidden int) {
+	if max <= 0 || len(findings) <= max {
+		return findings, 0
+	}
+
+	severityRank := func(f *scan.Finding) int {
+		if i := slices.Index(scan.Severities, f.Vulnerability.Severity); i >= 0 {
+			return i
+		}
+		return len(scan.Severities)
+	}
+
+	bySeverity := slices.Clone(findings)
+	sort.SliceStable(bySeverity, func(i, j int) bool {
+		return severityRank(bySeverity[i]) < severityRank(bySeverity[j])
+	})
+	keep := bySeverity[:max]
+
+	for _, f := range findings {
+		if slices.Contains(keep, f) {
+			shown = append(shown, f)
+		}
+	}
+
+	return shown, len(findings) - max
+}
@@ -0,0 +1,34 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/wolfi-dev/wolfictl/pkg/trust"
+)
+
+const envVarNameForTrustPolicy = "WOLFICTL_TRUST_POLICY"
+
+func addTrustPolicyFlag(val *string, cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVar(val, "trust-policy", "", fmt.Sprintf("path to a policy file declaring the signing keys trusted for each package repository; downloads not signed by a trusted key are rejected (can also be set with environment variable `%s`)", envVarNameForTrustPolicy))
+}
+
+// loadTrustPolicy reads the trust policy at the given path (or named by the
+// environment), if any, and enforces it for all downloads.
+func loadTrustPolicy(path string) error {
+	if path == "" {
+		path = os.Getenv(envVarNameForTrustPolicy)
+	}
+	if path == "" {
+		return nil
+	}
+
+	policy, err := trust.ReadPolicy(path)
+	if err != nil {
+		return err
+	}
+
+	trust.SetPolicy(policy)
+	return nil
+}
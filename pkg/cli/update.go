@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"net/url"
 	"os"
+	"sort"
+	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -21,16 +23,19 @@ type options struct {
 	useGitSign             bool
 	createIssues           bool
 	issueLabels            []string
+	targetBranches         map[string]string
 }
 
 func Update() *cobra.Command {
 	o := &options{}
 	cmd := &cobra.Command{
-		Use:     "update",
-		Short:   "Proposes melange package update(s) via a pull request",
-		Long:    `"Proposes melange package update(s) via a pull request".`,
-		Example: `  wolfictl update https://github.com/wolfi-dev/os`,
-		Args:    cobra.RangeArgs(1, 1),
+		Use:   "update",
+		Short: "Proposes melange package update(s) via a pull request",
+		Long:  `"Proposes melange package update(s) via a pull request".`,
+		Example: `  wolfictl update https://github.com/wolfi-dev/os
+
+  wolfictl update https://github.com/wolfi-dev/os --target-branch main=any --target-branch lts-2023=patch`,
+		Args: cobra.RangeArgs(1, 1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return o.UpdateCmd(cmd.Context(), args[0])
 		},
@@ -45,6 +50,7 @@ func Update() *cobra.Command {
 	cmd.Flags().BoolVar(&o.useGitSign, "use-gitsign", false, "enable gitsign to sign the git commits")
 	cmd.Flags().BoolVar(&o.createIssues, "create-issues", true, "creates GitHub Issues for failed package updates")
 	cmd.Flags().StringArrayVar(&o.issueLabels, "github-labels", []string{}, "Optional: provide a list of labels to apply to updater generated issues and pull requests")
+	cmd.Flags().StringToStringVar(&o.targetBranches, "target-branch", nil, fmt.Sprintf("Optional: propose updates against each of these branches (instead of --pull-request-base-branch), as branch=policy, where the policy limits which new versions are proposed for the branch (%s)", strings.Join(update.VersionPolicies, ", ")))
 
 	cmd.AddCommand(
 		Package(),
@@ -54,8 +60,6 @@ func Update() *cobra.Command {
 }
 
 func (o options) UpdateCmd(_ context.Context, repoURI string) error {
-	updateContext := update.New()
-
 	if !o.dryRun && os.Getenv("GITHUB_TOKEN") == "" {
 		return errors.New("no GITHUB_TOKEN token found")
 	}
@@ -63,10 +67,39 @@ func (o options) UpdateCmd(_ context.Context, repoURI string) error {
 	if _, err := url.ParseRequestURI(repoURI); err != nil {
 		return fmt.Errorf("failed to parse URI %s: %w", repoURI, err)
 	}
+
+	if len(o.targetBranches) == 0 {
+		return o.updateBranch(repoURI, o.pullRequestBaseBranch, update.VersionPolicyAny)
+	}
+
+	branches := make([]string, 0, len(o.targetBranches))
+	for branch, policy := range o.targetBranches {
+		if err := update.ValidateVersionPolicy(policy); err != nil {
+			return fmt.Errorf("invalid --target-branch %s: %w", branch, err)
+		}
+		branches = append(branches, branch)
+	}
+	sort.Strings(branches)
+
+	for _, branch := range branches {
+		if err := o.updateBranch(repoURI, branch, o.targetBranches[branch]); err != nil {
+			return fmt.Errorf("branch %s: %w", branch, err)
+		}
+	}
+
+	return nil
+}
+
+// updateBranch proposes updates against the given branch, allowing only the new
+// versions permitted by the given version policy.
+func (o options) updateBranch(repoURI, branch, versionPolicy string) error {
+	updateContext := update.New()
+
 	updateContext.PackageNames = o.packageNames
 	updateContext.RepoURI = repoURI
 	updateContext.DryRun = o.dryRun
-	updateContext.PullRequestBaseBranch = o.pullRequestBaseBranch
+	updateContext.PullRequestBaseBranch = branch
+	updateContext.VersionPolicy = versionPolicy
 	updateContext.PullRequestTitle = o.pullRequestTitle
 	updateContext.ReleaseMonitoringQuery = o.releaseMonitoringQuery
 	updateContext.GithubReleaseQuery = o.githubReleaseQuery
@@ -8,9 +8,8 @@ import (
 
 func VEX() *cobra.Command {
 	cmd := &cobra.Command{
-		Deprecated: "This command does nothing, and will be revmoved in a future version.",
-		Use:        "vex",
-		Short:      "Tools to generate VEX statements for Wolfi packages and images",
+		Use:   "vex",
+		Short: "Tools to generate VEX statements for Wolfi packages and images",
 		Long: `wolfictl vex: Tools to generate VEX statements for Wolfi packages and images
 		
 The vex family of subcommands interacts with Wolfi data and configuration
@@ -18,13 +17,13 @@ files to generate Vulnerability Exploitability eXchange (VEX) documents to
 inform downstream consumer how vulnerabilities impact Wolfi packages and images
 that use them. 
 
-wolfictl can generate VEX data by reading the melange configuration files
-of each package and additional information coming from external documents.
-There are currently two VEX subcommands:
+wolfictl generates VEX data from the advisory data of each package:
 
- wolfictl vex package: Generates VEX documents from a list of melange configs
+ wolfictl vex generate: Generates a VEX document from advisory data
+ wolfictl vex merge: Merges VEX documents into a single document
+ wolfictl vex attest: Signs a VEX document and attaches it to images or APKs
 
- wolfictl vex sbom: Generates a VEX document by reading an image SBOM
+The 'vex package' and 'vex sbom' subcommands are deprecated, and do nothing.
 
 For more information please see the help sections if these subcommands. To know
 more about the VEX tooling powering wolfictl see: https://openvex.dev/
@@ -34,6 +33,9 @@ more about the VEX tooling powering wolfictl see: https://openvex.dev/
 		SilenceErrors: true,
 	}
 
+	cmd.AddCommand(VEXGenerate())
+	cmd.AddCommand(VEXMerge())
+	cmd.AddCommand(VEXAttest())
 	addPackage(cmd)
 	addSBOM(cmd)
 	return cmd
@@ -41,6 +43,7 @@ more about the VEX tooling powering wolfictl see: https://openvex.dev/
 
 func addPackage(parent *cobra.Command) {
 	cmd := &cobra.Command{
+		Deprecated:    "This command does nothing, and will be removed in a future version. Use 'wolfictl vex generate' instead.",
 		Use:           "package [flags] CONFIG [CONFIG]...",
 		Example:       "wolfictl vex package --author=joe@doe.com config1.yaml config2.yaml",
 		Short:         "Generate a VEX document from package configuration files",
@@ -56,9 +59,10 @@ func addPackage(parent *cobra.Command) {
 
 func addSBOM(parent *cobra.Command) {
 	cmd := &cobra.Command{
-		Use:     "sbom [flags] sbom.spdx.json",
-		Example: "wolfictl vex sbom --author=joe@doe.com sbom.spdx.json",
-		Short:   "Generate a VEX document from wolfi packages listed in an SBOM",
+		Deprecated: "This command does nothing, and will be removed in a future version. Use 'wolfictl vex generate' instead.",
+		Use:        "sbom [flags] sbom.spdx.json",
+		Example:    "wolfictl vex sbom --author=joe@doe.com sbom.spdx.json",
+		Short:      "Generate a VEX document from wolfi packages listed in an SBOM",
 		Long: `wolfictl vex sbom: Generate a VEX document from wolfi packages listed in an SBOM
 		
 The vex sbom subcommand generates VEX documents describing how vulnerabilities
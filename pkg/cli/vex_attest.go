@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/wolfi-dev/wolfictl/pkg/advisory"
+)
+
+func VEXAttest() *cobra.Command {
+	p := &vexAttestParams{}
+	cmd := &cobra.Command{
+		Use:   "attest",
+		Short: "sign an OpenVEX document and attach it to images or APKs as an attestation",
+		Long: fmt.Sprintf(`sign an OpenVEX document and attach it to images or APKs as an attestation
+
+The document (e.g. from 'wolfictl vex generate' or 'wolfictl vex merge') is
+signed with cosign as an in-toto attestation with the %q predicate type, so
+that consumers can verify the exploitability data and who produced it:
+
+  - for each --image, the attestation is attached to the image in its registry,
+    where it can be verified with 'cosign verify-attestation --type %s'
+  - for each --apk, the attestation is written next to the APK file, with %q
+    appended to its path, since APK repositories can't carry attestations, and
+    so is a Sigstore bundle (with %q appended to the attestation's path), which
+    holds what's needed to verify it
+
+The document is validated first, so that invalid data is never signed.
+
+Unless --key is given, keyless signing is used, which records the attestation
+in the Rekor transparency log.
+
+This requires cosign to be installed.`, advisory.VEXAttestationType, advisory.VEXAttestationType, advisory.VEXAttestationSuffix, advisory.ExportBundleSuffix),
+		Example: `  wolfictl vex generate -p crane -o crane.openvex.json
+  wolfictl vex attest --vex crane.openvex.json --image cgr.dev/chainguard/crane:latest
+
+  # Attest with a cosign key to the APKs built from the package
+  wolfictl vex attest --vex crane.openvex.json --key cosign.key --apk ./packages/x86_64/crane-0.15.2-r0.apk`,
+		SilenceErrors: true,
+		Args:          cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(p.images) == 0 && len(p.apks) == 0 {
+				return fmt.Errorf("at least one --image or --apk is required")
+			}
+
+			opts := advisory.SigningOptions{Key: p.key}
+
+			for _, ref := range p.images {
+				if err := advisory.AttestVEXImage(ref, p.vexFile, opts); err != nil {
+					return err
+				}
+				_, _ = fmt.Fprintf(os.Stderr, "Attached VEX attestation to %s\n", ref)
+			}
+
+			for _, apk := range p.apks {
+				attestationPath, bundlePath, err := advisory.AttestVEXBlob(apk, p.vexFile, opts)
+				if err != nil {
+					return err
+				}
+				_, _ = fmt.Fprintf(os.Stderr, "Wrote VEX attestation of %s to %s (bundle: %s)\n", apk, attestationPath, bundlePath)
+			}
+
+			return nil
+		},
+	}
+
+	p.addFlagsTo(cmd)
+	return cmd
+}
+
+type vexAttestParams struct {
+	vexFile string
+	images  []string
+	apks    []string
+	key     string
+}
+
+func (p *vexAttestParams) addFlagsTo(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&p.vexFile, "vex", "", "path to the OpenVEX document (in JSON) to attest")
+	_ = cmd.MarkFlagRequired("vex")
+	cmd.Flags().StringArrayVar(&p.images, "image", nil, "reference of an image to attach the attestation to (can be given more than once)")
+	cmd.Flags().StringArrayVar(&p.apks, "apk", nil, "path to an APK file to write an attestation for (can be given more than once)")
+	cmd.Flags().StringVar(&p.key, "key", "", "path (or KMS URI) of a cosign private key to sign with, instead of keyless signing")
+}
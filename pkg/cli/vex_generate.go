@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/wolfi-dev/wolfictl/pkg/advisory"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
+)
+
+func VEXGenerate() *cobra.Command {
+	p := &vexGenerateParams{}
+	cmd := &cobra.Command{
+		Use:   "generate",
+		Short: "generate an OpenVEX document from advisory data",
+		Long: `generate an OpenVEX document from advisory data
+
+Each advisory of the given packages (or of all packages, with --all) becomes
+an OpenVEX statement, based on the advisory's latest public event. Products are
+identified by purls, as in pkg:apk/<namespace>/<package>:
+
+  - a fixed advisory states that the package's fixed version is fixed
+  - a not_affected advisory states that every version of the package is not
+    affected, with its justification and impact statement
+  - an affected advisory states that every version of the package is affected,
+    with its action statement
+  - an under_investigation advisory is stated as such
+
+Consumers of images built from the packages can use the document to suppress
+scanner findings that are known not to affect them.
+
+The document is dated by its latest statement (or by SOURCE_DATE_EPOCH, if
+set), and its ID is derived from its content, so regenerating it from
+unchanged advisory data yields the same document.`,
+		Example: `  wolfictl vex generate --package glibc
+
+  wolfictl vex generate --all --author "Wolfi" -o wolfi.openvex.json`,
+		SilenceErrors: true,
+		Args:          cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if p.all == (len(p.packageNames) > 0) {
+				return fmt.Errorf("exactly one of --package or --all must be given")
+			}
+
+			advisoriesRepoDir, err := p.resolveAdvisoriesDir()
+			if err != nil {
+				return err
+			}
+
+			advisoryCfgs, err := advisoryconfigs.NewIndex(rwos.DirFS(advisoriesRepoDir))
+			if err != nil {
+				return err
+			}
+
+			v, err := advisory.GenerateVEX(advisory.GenerateVEXOptions{
+				AdvisoryCfgs:     advisoryCfgs,
+				Packages:         p.packageNames,
+				ProductNamespace: p.productNamespace,
+				Author:           p.author,
+				AuthorRole:       p.role,
+			})
+			if err != nil {
+				return err
+			}
+
+			out := os.Stdout
+			if p.outputFile != "" {
+				f, err := os.Create(p.outputFile)
+				if err != nil {
+					return fmt.Errorf("unable to create VEX output file: %w", err)
+				}
+				defer f.Close()
+				out = f
+			}
+
+			if err := v.ToJSON(out); err != nil {
+				return fmt.Errorf("unable to write VEX document: %w", err)
+			}
+
+			_, _ = fmt.Fprintf(os.Stderr, "Generated %d VEX statements\n", len(v.Statements))
+			return nil
+		},
+	}
+
+	p.addFlagsTo(cmd)
+	return cmd
+}
+
+type vexGenerateParams struct {
+	advisoriesDirParams
+
+	packageNames     []string
+	all              bool
+	productNamespace string
+	author           string
+	role             string
+	outputFile       string
+}
+
+func (p *vexGenerateParams) addFlagsTo(cmd *cobra.Command) {
+	p.advisoriesDirParams.addFlagsTo(cmd)
+
+	cmd.Flags().StringSliceVarP(&p.packageNames, "package", "p", nil, "names of the packages whose advisories to generate statements for")
+	cmd.Flags().BoolVar(&p.all, "all", false, "generate statements for the advisories of all packages")
+	cmd.Flags().StringVar(&p.productNamespace, "product-namespace", "wolfi", "namespace used in the package URLs that identify the products in the VEX document")
+	cmd.Flags().StringVar(&p.author, "author", "", "author of the VEX document (default: OpenVEX's default author)")
+	cmd.Flags().StringVar(&p.role, "role", "", "role of the author of the VEX document")
+	cmd.Flags().StringVarP(&p.outputFile, "output", "o", "", "write the VEX document to this file instead of stdout")
+}
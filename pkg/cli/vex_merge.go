@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/openvex/go-vex/pkg/vex"
+	"github.com/spf13/cobra"
+	"github.com/wolfi-dev/wolfictl/pkg/advisory"
+)
+
+func VEXMerge() *cobra.Command {
+	p := &vexMergeParams{}
+	cmd := &cobra.Command{
+		Use:   "merge <file> <file>...",
+		Short: "merge OpenVEX documents into a single document",
+		Long: `merge OpenVEX documents into a single document
+
+This is useful to consolidate the VEX data of an image composed of many
+packages into one document.
+
+For each product and vulnerability, only the latest statement is kept. A
+statement without a timestamp takes its document's timestamp, and where two
+statements have the same timestamp, the one from the file given later wins.
+Statements about several products are split into one statement per product.
+
+The merged document's ID is derived from its content.`,
+		Example:       `  wolfictl vex merge glibc.openvex.json openssl.openvex.json -o image.openvex.json`,
+		SilenceErrors: true,
+		Args:          cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var docs []*vex.VEX
+			for _, path := range args {
+				doc, err := vex.Load(path)
+				if err != nil {
+					return fmt.Errorf("unable to load VEX document %q: %w", path, err)
+				}
+				docs = append(docs, doc)
+			}
+
+			v, err := advisory.MergeVEX(docs, advisory.MergeVEXOptions{
+				Author:     p.author,
+				AuthorRole: p.role,
+			})
+			if err != nil {
+				return err
+			}
+
+			out := os.Stdout
+			if p.outputFile != "" {
+				f, err := os.Create(p.outputFile)
+				if err != nil {
+					return fmt.Errorf("unable to create VEX output file: %w", err)
+				}
+				defer f.Close()
+				out = f
+			}
+
+			if err := v.ToJSON(out); err != nil {
+				return fmt.Errorf("unable to write VEX document: %w", err)
+			}
+
+			_, _ = fmt.Fprintf(os.Stderr, "Merged %d VEX documents into %d statements\n", len(docs), len(v.Statements))
+			return nil
+		},
+	}
+
+	p.addFlagsTo(cmd)
+	return cmd
+}
+
+type vexMergeParams struct {
+	author     string
+	role       string
+	outputFile string
+}
+
+func (p *vexMergeParams) addFlagsTo(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&p.author, "author", "", "author of the merged VEX document (default: OpenVEX's default author)")
+	cmd.Flags().StringVar(&p.role, "role", "", "role of the author of the merged VEX document")
+	cmd.Flags().StringVarP(&p.outputFile, "output", "o", "", "write the merged VEX document to this file instead of stdout")
+}
@@ -0,0 +1,17 @@
+package cli
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func Vuln() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "vuln",
+		SilenceErrors: true,
+		Short:         "Utilities for tracking upstream vulnerability data",
+	}
+
+	cmd.AddCommand(VulnNVDWatch())
+
+	return cmd
+}
@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/wolfi-dev/wolfictl/pkg/vuln"
+)
+
+const envVarNameForVulnLinks = "WOLFICTL_VULN_LINKS"
+
+// vulnLinkTemplates are used to hyperlink vulnerability IDs in terminal output.
+var vulnLinkTemplates = vuln.DefaultLinkTemplates
+
+func addVulnLinkFlags(file *string, links *map[string]string, cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVar(file, "vuln-links", "", fmt.Sprintf("path to a YAML file mapping vulnerability ID prefixes to URL templates (with {id} as a placeholder) used to hyperlink vulnerability IDs, under a 'links' key (can also be set with environment variable `%s`)", envVarNameForVulnLinks))
+	cmd.PersistentFlags().StringToStringVar(links, "vuln-link", nil, "URL template used to hyperlink vulnerability IDs with the given prefix, e.g. CVE-=https://vulns.example.com/{id} (takes precedence over --vuln-links)")
+}
+
+// loadVulnLinks applies the link templates from the file at the given path (or
+// named by the environment), if any, and then the given templates, on top of
+// the defaults.
+func loadVulnLinks(path string, links map[string]string) error {
+	if path == "" {
+		path = os.Getenv(envVarNameForVulnLinks)
+	}
+
+	templates := vuln.DefaultLinkTemplates
+	if path != "" {
+		fromFile, err := vuln.ReadLinkTemplates(path)
+		if err != nil {
+			return err
+		}
+		templates = templates.With(fromFile)
+	}
+
+	if err := vuln.LinkTemplates(links).Validate(); err != nil {
+		return fmt.Errorf("invalid --vuln-link: %w", err)
+	}
+
+	vulnLinkTemplates = templates.With(links)
+	return nil
+}
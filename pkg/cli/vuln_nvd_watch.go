@@ -0,0 +1,194 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+	rwos "github.com/wolfi-dev/wolfictl/pkg/configs/rwfs/os"
+	"github.com/wolfi-dev/wolfictl/pkg/distro"
+	"github.com/wolfi-dev/wolfictl/pkg/vuln/nvdapi"
+	"github.com/wolfi-dev/wolfictl/pkg/vuln/nvdwatch"
+	"golang.org/x/exp/slices"
+	"golang.org/x/oauth2"
+)
+
+func VulnNVDWatch() *cobra.Command {
+	p := &nvdWatchParams{}
+	cmd := &cobra.Command{
+		Use:   "nvd-watch",
+		Short: "Report changes to NVD and GitHub metadata for vulnerabilities that have advisories",
+		Long: `Report changes to NVD and GitHub metadata for vulnerabilities that have advisories.
+
+This command looks up the current NVD record for every CVE mentioned in the
+advisory data, and the current GitHub Security Advisory for every GHSA
+mentioned in it (as an advisory ID or an alias), and compares them with the
+state recorded the last time the command was run (see --state). Changes to
+severity, affected CPE ranges (for CVEs) or version ranges (for GHSAs), and
+descriptions are reported, and material changes (severity upgrades and range
+changes) are highlighted, since they may invalidate an existing triage
+decision.
+
+The state file is then updated with the latest data. It's replaced atomically,
+so an interrupted run leaves the previous state intact.
+
+Set GITHUB_TOKEN to avoid GitHub's low rate limit for unauthenticated requests.`,
+		SilenceErrors: true,
+		Args:          cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			advisoriesRepoDir := resolveAdvisoriesDir(p.advisoriesRepoDir)
+			if advisoriesRepoDir == "" {
+				if p.doNotDetectDistro {
+					return fmt.Errorf("no advisories repo dir specified")
+				}
+
+				d, err := distro.Detect()
+				if err != nil {
+					return fmt.Errorf("no advisories repo dir specified, and distro auto-detection failed: %w", err)
+				}
+
+				advisoriesRepoDir = d.AdvisoriesRepoDir
+				_, _ = fmt.Fprint(os.Stderr, renderDetectedDistro(d))
+			}
+
+			advisoryCfgs, err := advisoryconfigs.NewIndex(rwos.DirFS(advisoriesRepoDir))
+			if err != nil {
+				return err
+			}
+
+			packagesByID := make(map[string][]string)
+			for _, doc := range advisoryCfgs.Select().Configurations() {
+				for id := range doc.Advisories {
+					ids := append([]string{id}, doc.Aliases[id]...)
+					for _, id := range ids {
+						if !strings.HasPrefix(id, "CVE-") && !strings.HasPrefix(id, "GHSA-") {
+							continue
+						}
+						if !slices.Contains(packagesByID[id], doc.Package.Name) {
+							packagesByID[id] = append(packagesByID[id], doc.Package.Name)
+						}
+					}
+				}
+			}
+
+			previous, err := nvdwatch.Load(p.stateFile)
+			if err != nil {
+				return fmt.Errorf("unable to load state file %q: %w", p.stateFile, err)
+			}
+
+			detector := nvdapi.NewDetector(http.DefaultClient, nvdapi.DefaultHost, resolveNVDAPIKey(p.nvdAPIKey))
+
+			githubClient := http.DefaultClient
+			if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+				githubClient = oauth2.NewClient(cmd.Context(), oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token}))
+			}
+			ghsaClient := nvdwatch.NewGHSAClient(githubClient)
+			current := nvdwatch.Snapshot{
+				Timestamp: time.Now(),
+				Records:   make(map[string]nvdwatch.Record),
+			}
+
+			ids := lo.Keys(packagesByID)
+			sort.Strings(ids)
+
+			ctx := context.Background()
+			for _, id := range ids {
+				r, err := watchedRecord(ctx, detector, ghsaClient, id)
+				if err != nil {
+					log.Printf("⚠️  skipping %s: %v", id, err)
+
+					// Carry forward what we knew, so the next run still has something to compare against.
+					if r, ok := previous.Records[id]; ok {
+						current.Records[id] = r
+					}
+					continue
+				}
+
+				current.Records[id] = r
+			}
+
+			changes := nvdwatch.Compare(previous, current)
+			fmt.Print(renderNVDChanges(changes, packagesByID))
+
+			if err := nvdwatch.Save(p.stateFile, current); err != nil {
+				return fmt.Errorf("unable to save state file %q: %w", p.stateFile, err)
+			}
+
+			return nil
+		},
+	}
+
+	p.addFlagsTo(cmd)
+	return cmd
+}
+
+type nvdWatchParams struct {
+	doNotDetectDistro bool
+	advisoriesRepoDir string
+	stateFile         string
+	nvdAPIKey         string
+}
+
+func (p *nvdWatchParams) addFlagsTo(cmd *cobra.Command) {
+	addNoDistroDetectionFlag(&p.doNotDetectDistro, cmd)
+	addAdvisoriesDirFlag(&p.advisoriesRepoDir, cmd)
+	cmd.Flags().StringVar(&p.stateFile, "state", "nvd-watch.json", "file used to record NVD and GitHub metadata between runs")
+	addNVDAPIKeyFlag(&p.nvdAPIKey, cmd)
+}
+
+// watchedRecord returns the current record for the given CVE (from NVD) or GHSA
+// (from GitHub).
+func watchedRecord(ctx context.Context, detector *nvdapi.Detector, ghsaClient *nvdwatch.GHSAClient, id string) (nvdwatch.Record, error) {
+	if strings.HasPrefix(id, "GHSA-") {
+		a, err := ghsaClient.Advisory(ctx, id)
+		if err != nil {
+			return nvdwatch.Record{}, err
+		}
+		return nvdwatch.NewGHSARecord(a), nil
+	}
+
+	cve, err := detector.CVE(ctx, id)
+	if err != nil {
+		return nvdwatch.Record{}, err
+	}
+	return nvdwatch.NewRecord(cve), nil
+}
+
+func renderNVDChanges(changes []nvdwatch.Change, packagesByID map[string][]string) string {
+	if len(changes) == 0 {
+		return "No changes to NVD or GitHub metadata since the last run.\n"
+	}
+
+	var sb strings.Builder
+	for _, c := range changes {
+		marker := "  "
+		if c.Material {
+			marker = "⚠️ "
+		}
+
+		pkgs := packagesByID[c.ID]
+		sort.Strings(pkgs)
+
+		fmt.Fprintf(&sb, "%s %s (%s): %s changed\n", marker, c.ID, strings.Join(pkgs, ", "), c.Field)
+		fmt.Fprintf(&sb, "      was: %s\n", indentContinuation(c.Old))
+		fmt.Fprintf(&sb, "      now: %s\n", indentContinuation(c.New))
+	}
+
+	return sb.String()
+}
+
+func indentContinuation(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+
+	return strings.ReplaceAll(s, "\n", "\n           ")
+}
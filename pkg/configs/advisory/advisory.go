@@ -42,10 +42,34 @@ func DecodeDocument(r io.Reader) (*Document, error) {
 	return doc, nil
 }
 
+// SchemaVersion is the current version of the advisory document schema.
+// Documents without a schema version predate versioning, and are upgraded by
+// the advisory migrations.
+const SchemaVersion = "1"
+
 type Document struct {
+	// SchemaVersion is the version of the schema the document conforms to (see
+	// SchemaVersion).
+	SchemaVersion string `yaml:"schema-version,omitempty"`
+
 	Package Package `yaml:"package"`
 
+	// Archived is set once the package has been removed from the distro and its
+	// advisory data frozen. Archived documents live in a separate directory from
+	// the active ones.
+	Archived *Archived `yaml:"archived,omitempty"`
+
 	Advisories Advisories `yaml:"advisories,omitempty"`
+
+	// Aliases maps the IDs of the document's advisories to the other known IDs
+	// of the same vulnerability (e.g. a CVE ID's GHSA and GO IDs).
+	Aliases Aliases `yaml:"aliases,omitempty"`
+}
+
+// Archived records when and why a package's advisory data was archived.
+type Archived struct {
+	Timestamp time.Time `yaml:"timestamp"`
+	Reason    string    `yaml:"reason,omitempty"`
 }
 
 func (d Document) Name() string {
@@ -58,6 +82,8 @@ type Package struct {
 
 type Advisories map[string][]Entry
 
+type Aliases map[string][]string
+
 type Entry struct {
 	Timestamp       time.Time         `yaml:"timestamp"`
 	Status          vex.Status        `yaml:"status"`
@@ -65,4 +91,106 @@ type Entry struct {
 	ImpactStatement string            `yaml:"impact,omitempty"`
 	ActionStatement string            `yaml:"action,omitempty"`
 	FixedVersion    string            `yaml:"fixed-version,omitempty"`
+
+	// SeverityOverride records the distro's own assessment of the
+	// vulnerability's severity in the package, which takes precedence over the
+	// upstream severity. Once recorded, it stays in effect for later events that
+	// don't record their own.
+	SeverityOverride *SeverityOverride `yaml:"severity-override,omitempty"`
+
+	// Detection records how the vulnerability was detected in the package, for
+	// events filed from scan results.
+	Detection *Detection `yaml:"detection,omitempty"`
+
+	// PendingUpstreamFix records the upstream issue, pull request, or commit
+	// whose fix an affected package is waiting for, so that it can be polled
+	// for progress.
+	PendingUpstreamFix *PendingUpstreamFix `yaml:"pending-upstream-fix,omitempty"`
+
+	// References are links to context for the event, such as the upstream fix
+	// commit or the distro pull request that shipped the fix.
+	References []Reference `yaml:"references,omitempty"`
+
+	// Internal marks the whole event as internal-only. Internal events are left
+	// out of all data exported for public consumption.
+	Internal bool `yaml:"internal,omitempty"`
+
+	// InternalFields lists the fields of the event, by YAML key (e.g. "impact"),
+	// whose values are internal-only and are blanked in data exported for public
+	// consumption.
+	InternalFields []string `yaml:"internal-fields,omitempty"`
+}
+
+// SeverityOverride is a distro-specific assessment of a vulnerability's
+// severity, e.g. because the vulnerable code isn't network reachable as the
+// package is built and configured.
+type SeverityOverride struct {
+	// Severity is the assessed severity, e.g. "Medium".
+	Severity string `yaml:"severity"`
+
+	// CVSS is the CVSS v3 vector that the assessment is based on, if any, e.g.
+	// "CVSS:3.1/AV:L/AC:L/PR:L/UI:N/S:U/C:H/I:N/A:N".
+	CVSS string `yaml:"cvss,omitempty"`
+
+	// Rationale explains why the assessment differs from the upstream one.
+	Rationale string `yaml:"rationale"`
+}
+
+// Detection is the provenance of a scanner finding, so that a detection that
+// turns out to be wrong can be traced back to what produced it.
+type Detection struct {
+	// Scanner is the name of the scanner that reported the finding, e.g.
+	// "grype".
+	Scanner string `yaml:"scanner,omitempty"`
+
+	// ScannerVersion is the version of the scanner.
+	ScannerVersion string `yaml:"scanner-version,omitempty"`
+
+	// VulnerabilityDBBuilt is when the scanner's vulnerability database was
+	// built.
+	VulnerabilityDBBuilt time.Time `yaml:"vulnerability-db-built,omitempty"`
+
+	// PURL is the package URL of the package that the vulnerability was matched
+	// against, which may be a package embedded in the APK rather than the APK
+	// itself.
+	PURL string `yaml:"purl,omitempty"`
+
+	// APKDigest is the digest of the scanned APK file, as "sha256:<hex>".
+	APKDigest string `yaml:"apk-digest,omitempty"`
+}
+
+// PendingUpstreamFix is a reference to an upstream fix that hasn't been
+// released yet.
+type PendingUpstreamFix struct {
+	// URL is the upstream issue, pull request, or commit, e.g.
+	// "https://github.com/golang/go/issues/61234".
+	URL string `yaml:"url"`
+}
+
+// ReferenceType is the kind of resource that a Reference links to.
+type ReferenceType string
+
+const (
+	// ReferenceFixCommit is the upstream commit that fixes the vulnerability.
+	ReferenceFixCommit ReferenceType = "fix-commit"
+
+	// ReferenceUpstreamAdvisory is the upstream project's advisory for the
+	// vulnerability.
+	ReferenceUpstreamAdvisory ReferenceType = "upstream-advisory"
+
+	// ReferenceDistroPR is the distro pull request that changed the package,
+	// e.g. to ship the fix.
+	ReferenceDistroPR ReferenceType = "distro-pr"
+
+	// ReferenceOther is any other relevant resource.
+	ReferenceOther ReferenceType = "other"
+)
+
+// ReferenceTypes lists the valid reference types.
+var ReferenceTypes = []ReferenceType{ReferenceFixCommit, ReferenceUpstreamAdvisory, ReferenceDistroPR, ReferenceOther}
+
+// Reference is a link to a resource that gives context for an advisory event.
+type Reference struct {
+	Type ReferenceType `yaml:"type"`
+	URL  string        `yaml:"url"`
 }
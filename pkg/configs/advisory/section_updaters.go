@@ -18,3 +18,18 @@ func NewAdvisoriesSectionUpdater(
 
 	return configs.NewYAMLUpdateFunc[Document](yamlASTMutater)
 }
+
+func NewAliasesSectionUpdater(
+	updater configs.SectionUpdater[Aliases, Document],
+) configs.EntryUpdater[Document] {
+	yamlASTMutater := configs.NewTargetedYAMLASTMutater[Aliases, Document](
+		"aliases",
+		updater,
+		func(cfg Document, data Aliases) Document {
+			cfg.Aliases = data
+			return cfg
+		},
+	)
+
+	return configs.NewYAMLUpdateFunc[Document](yamlASTMutater)
+}
@@ -1,6 +1,7 @@
 package configs
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"io/fs"
@@ -128,22 +129,59 @@ func (i *Index[T]) Select() Selection[T] {
 }
 
 // Create creates a new configuration file at the given path, with the given
-// cfg. The new configuration is automatically added to the Index.
-func (i *Index[T]) Create(path string, cfg T) error {
-	file, err := i.fsys.Create(path)
+// cfg. The new configuration is automatically added to the Index. It fails if the
+// file already exists, e.g. because another writer created it since the Index
+// was created.
+func (i *Index[T]) Create(path string, cfg T) (err error) {
+	unlock, err := i.lock(path)
 	if err != nil {
 		return err
 	}
+	defer func() {
+		if uerr := unlock(); uerr != nil && err == nil {
+			err = fmt.Errorf("unable to unlock %q: %w", path, uerr)
+		}
+	}()
 
-	err = yaml.NewEncoder(file).Encode(cfg)
-	if err != nil {
-		return err
+	if _, err := fs.Stat(i.fsys, path); err == nil {
+		return fmt.Errorf("unable to create %q: %w", path, fs.ErrExist)
 	}
-	_ = file.Close()
 
-	err = i.format(path) // i.e. using yam
-	if err != nil {
-		return err
+	if lfs, ok := i.fsys.(rwfs.LockingFS); ok {
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		if err := enc.Encode(cfg); err != nil {
+			return err
+		}
+		if err := enc.Close(); err != nil {
+			return err
+		}
+
+		// Format before writing, so the file is only written once, atomically.
+		b, err := formatYAML(buf.Bytes())
+		if err != nil {
+			return err
+		}
+
+		if err := lfs.WriteFile(path, b); err != nil {
+			return err
+		}
+	} else {
+		file, err := i.fsys.Create(path)
+		if err != nil {
+			return err
+		}
+
+		err = yaml.NewEncoder(file).Encode(cfg)
+		if err != nil {
+			return err
+		}
+		_ = file.Close()
+
+		err = i.format(path) // i.e. using yam
+		if err != nil {
+			return err
+		}
 	}
 
 	err = i.processAndAdd(path)
@@ -154,6 +192,18 @@ func (i *Index[T]) Create(path string, cfg T) error {
 	return nil
 }
 
+// lock acquires the lock for writing the file at the given path, if the Index's
+// filesystem supports locking (see rwfs.LockingFS), and returns the function that
+// releases it.
+func (i *Index[T]) lock(path string) (func() error, error) {
+	lfs, ok := i.fsys.(rwfs.LockingFS)
+	if !ok {
+		return func() error { return nil }, nil
+	}
+
+	return lfs.Lock(path)
+}
+
 func (i *Index[T]) format(path string) error {
 	fileForFormatting, err := i.fsys.OpenAsWritable(path)
 	if err != nil {
@@ -173,15 +223,66 @@ func (i *Index[T]) format(path string) error {
 	return nil
 }
 
+// formatYAML formats the given YAML document in the same way as format, using
+// the yam configuration found in the working directory, if any.
+func formatYAML(b []byte) ([]byte, error) {
+	encOpts, err := formatted.ReadConfig()
+	if err != nil {
+		// No format config, so there's nothing to apply.
+		return b, nil
+	}
+
+	root := &yaml.Node{}
+	if err := yaml.Unmarshal(b, root); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	enc, err := formatted.NewEncoder(&buf).UseOptions(*encOpts)
+	if err != nil {
+		return nil, err
+	}
+	if err := enc.Encode(root); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
 // update updates the given entry in the index using the provided EntryUpdater.
-func (i *Index[T]) update(entry Entry[T], entryUpdater EntryUpdater[T]) error {
-	err := entryUpdater(i, entry)
+// If the Index's filesystem supports locking (see rwfs.LockingFS), the entry's
+// file is locked for the update, and the update is applied to the file's
+// current contents, so that changes made by other writers since the Index was
+// created aren't lost.
+func (i *Index[T]) update(e Entry[T], entryUpdater EntryUpdater[T]) (err error) {
+	id := e.id()
+	path := e.getPath()
+
+	unlock, err := i.lock(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if uerr := unlock(); uerr != nil && err == nil {
+			err = fmt.Errorf("unable to unlock %q: %w", path, uerr)
+		}
+	}()
+
+	if _, ok := i.fsys.(rwfs.LockingFS); ok {
+		current, err := i.process(path)
+		if err != nil {
+			return err
+		}
+		i.updateAtIndex(current, i.byID[id])
+		e = *current
+	}
+
+	err = entryUpdater(i, e)
 	if err != nil {
 		return err
 	}
 
-	id := entry.id()
-	err = i.processAndUpdate(entry.getPath(), i.byID[id])
+	err = i.processAndUpdate(path, i.byID[id])
 	if err != nil {
 		return fmt.Errorf("unable to process and update index entry for %q: %w", id, err)
 	}
@@ -222,6 +323,7 @@ func (i *Index[T]) process(path string) (*entry[T], error) {
 	if err != nil {
 		return nil, fmt.Errorf("unable to open configuration at %q: %w", path, err)
 	}
+	defer f.Close()
 
 	yamlRoot := &yaml.Node{}
 	err = yaml.NewDecoder(f).Decode(yamlRoot)
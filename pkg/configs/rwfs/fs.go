@@ -1,6 +1,7 @@
 package rwfs
 
 import (
+	"errors"
 	"io"
 	"io/fs"
 )
@@ -16,3 +17,24 @@ type File interface {
 	fs.File
 	io.Writer
 }
+
+// ErrLocked is returned when a file's lock is still held by another writer after
+// waiting for it.
+var ErrLocked = errors.New("file is locked by another writer")
+
+// LockingFS is an FS that supports concurrent writers, such as multiple
+// automation jobs writing to the same files. Writers that lock a file before
+// reading and rewriting it don't lose each other's changes, and readers never
+// see a partially written file.
+type LockingFS interface {
+	FS
+
+	// Lock acquires an exclusive lock for writing the named file, retrying while
+	// another writer holds it, and returns a function that releases the lock. If
+	// the lock can't be acquired in time, the error wraps ErrLocked.
+	Lock(name string) (unlock func() error, err error)
+
+	// WriteFile atomically replaces the contents of the named file, creating it
+	// if needed.
+	WriteFile(name string, data []byte) error
+}
@@ -0,0 +1,354 @@
+// Package http provides a read-only rwfs.FS for advisory data (or other
+// configurations) published over HTTP(S), so that it can be indexed without a
+// local git checkout.
+//
+// The published data is a flat directory of YAML documents, alongside an index
+// file (see IndexFile) that lists each document with the digest of its
+// contents. The index is fetched when the FS is created, and each document is
+// fetched only when it's opened. Both are cached on disk: a cached document
+// whose digest matches the index is used without any request, and otherwise
+// documents and the index are revalidated with their ETags.
+package http
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/wolfi-dev/wolfictl/pkg/configs/rwfs"
+)
+
+// IndexFile is the name of the published index, relative to the base URL.
+const IndexFile = "index.json.gz"
+
+// Index lists the published documents. It's published as gzip-compressed JSON.
+type Index struct {
+	Documents []IndexEntry `json:"documents"`
+}
+
+// IndexEntry is a published document.
+type IndexEntry struct {
+	// Path is the document's path, relative to the base URL.
+	Path string `json:"path"`
+
+	// Digest is the digest of the document's contents, as "sha256:<hex>".
+	Digest string `json:"digest"`
+}
+
+// FS is a read-only rwfs.FS of the documents published at a base URL.
+type FS struct {
+	baseURL  string
+	cacheDir string
+	client   *http.Client
+
+	digests map[string]string
+	modTime time.Time
+}
+
+var _ rwfs.FS = (*FS)(nil)
+
+// New fetches the index published at baseURL and returns an FS of the
+// documents it lists, cached in cacheDir.
+func New(baseURL, cacheDir string) (*FS, error) {
+	fsys := &FS{
+		baseURL:  strings.TrimSuffix(baseURL, "/"),
+		cacheDir: cacheDir,
+		client:   &http.Client{Timeout: 2 * time.Minute},
+		digests:  make(map[string]string),
+		modTime:  time.Now(),
+	}
+
+	b, err := fsys.fetch(IndexFile)
+	if err != nil {
+		return nil, err
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(b))
+	if err != nil {
+		return nil, fmt.Errorf("unable to decompress %s: %w", fsys.url(IndexFile), err)
+	}
+	defer zr.Close()
+
+	var index Index
+	if err := json.NewDecoder(zr).Decode(&index); err != nil {
+		return nil, fmt.Errorf("unable to decode %s: %w", fsys.url(IndexFile), err)
+	}
+
+	for _, doc := range index.Documents {
+		if !fs.ValidPath(doc.Path) || strings.Contains(doc.Path, "/") || doc.Path == IndexFile {
+			return nil, fmt.Errorf("%s lists invalid document path %q", fsys.url(IndexFile), doc.Path)
+		}
+		fsys.digests[doc.Path] = doc.Digest
+	}
+
+	return fsys, nil
+}
+
+// WriteIndex writes the index of the YAML documents in the top level of fsys to
+// w, for publishing alongside them.
+func WriteIndex(w io.Writer, fsys fs.FS) error {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return err
+	}
+
+	index := Index{Documents: []IndexEntry{}}
+	for _, e := range entries {
+		if !e.Type().IsRegular() || strings.HasPrefix(e.Name(), ".") || !strings.HasSuffix(e.Name(), ".yaml") {
+			continue
+		}
+
+		b, err := fs.ReadFile(fsys, e.Name())
+		if err != nil {
+			return err
+		}
+
+		index.Documents = append(index.Documents, IndexEntry{Path: e.Name(), Digest: digest(b)})
+	}
+
+	zw := gzip.NewWriter(w)
+	if err := json.NewEncoder(zw).Encode(index); err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+// Open opens the named document, fetching it unless a cached copy matches the
+// index. The root directory lists all the indexed documents.
+func (fsys *FS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+
+	if name == "." {
+		return fsys.root(), nil
+	}
+
+	want, ok := fsys.digests[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+
+	if b, err := os.ReadFile(fsys.cachePath(name)); err == nil && digest(b) == want {
+		return fsys.file(name, b), nil
+	}
+
+	b, err := fsys.fetch(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+
+	if got := digest(b); got != want {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fmt.Errorf("digest %s doesn't match the index (%s)", got, want)}
+	}
+
+	return fsys.file(name, b), nil
+}
+
+// OpenAsWritable always fails, since the FS is read-only.
+func (fsys *FS) OpenAsWritable(name string) (rwfs.File, error) {
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrPermission}
+}
+
+// Truncate always fails, since the FS is read-only.
+func (fsys *FS) Truncate(name string, _ int64) error {
+	return &fs.PathError{Op: "truncate", Path: name, Err: fs.ErrPermission}
+}
+
+// Create always fails, since the FS is read-only.
+func (fsys *FS) Create(name string) (rwfs.File, error) {
+	return nil, &fs.PathError{Op: "create", Path: name, Err: fs.ErrPermission}
+}
+
+// fetch returns the contents of the named file, revalidating any cached copy
+// with its ETag, and caches what it fetches.
+func (fsys *FS) fetch(name string) ([]byte, error) {
+	u := fsys.url(name)
+	p := fsys.cachePath(name)
+
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	cached, cacheErr := os.ReadFile(p)
+	if cacheErr == nil {
+		if etag, err := os.ReadFile(p + ".etag"); err == nil && len(etag) > 0 {
+			req.Header.Set("If-None-Match", string(etag))
+		}
+	}
+
+	resp, err := fsys.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cacheErr == nil:
+		return cached, nil
+
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("unable to fetch %s: %s", u, resp.Status)
+	}
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch %s: %w", u, err)
+	}
+
+	if err := fsys.cache(name, b, resp.Header.Get("ETag")); err != nil {
+		return nil, fmt.Errorf("unable to cache %s: %w", u, err)
+	}
+
+	return b, nil
+}
+
+// cache writes the named file's contents and ETag to the cache directory.
+func (fsys *FS) cache(name string, b []byte, etag string) error {
+	if err := os.MkdirAll(fsys.cacheDir, 0o755); err != nil {
+		return err
+	}
+
+	p := fsys.cachePath(name)
+
+	// Write to a temp file first, so that an interrupted write is never mistaken
+	// for a cached file.
+	tmp, err := os.CreateTemp(fsys.cacheDir, ".download-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), p); err != nil {
+		return err
+	}
+
+	if etag == "" {
+		if err := os.Remove(p + ".etag"); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+
+	return os.WriteFile(p+".etag", []byte(etag), 0o644) //nolint:gosec
+}
+
+func (fsys *FS) url(name string) string {
+	return fsys.baseURL + "/" + name
+}
+
+func (fsys *FS) cachePath(name string) string {
+	return filepath.Join(fsys.cacheDir, name)
+}
+
+func (fsys *FS) file(name string, b []byte) fs.File {
+	return &file{
+		Reader: bytes.NewReader(b),
+		info:   fileInfo{name: name, size: int64(len(b)), modTime: fsys.modTime},
+	}
+}
+
+func (fsys *FS) root() fs.File {
+	names := make([]string, 0, len(fsys.digests))
+	for name := range fsys.digests {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	entries := make([]fs.DirEntry, 0, len(names))
+	for _, name := range names {
+		entries = append(entries, fs.FileInfoToDirEntry(fileInfo{name: name, modTime: fsys.modTime}))
+	}
+
+	return &dir{
+		info:    fileInfo{name: ".", dir: true, modTime: fsys.modTime},
+		entries: entries,
+	}
+}
+
+func digest(b []byte) string {
+	sum := sha256.Sum256(b)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+type file struct {
+	*bytes.Reader
+	info fileInfo
+}
+
+func (f *file) Stat() (fs.FileInfo, error) { return f.info, nil }
+
+func (f *file) Close() error { return nil }
+
+type dir struct {
+	info    fileInfo
+	entries []fs.DirEntry
+}
+
+func (d *dir) Stat() (fs.FileInfo, error) { return d.info, nil }
+
+func (d *dir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.name, Err: errors.New("is a directory")}
+}
+
+func (d *dir) Close() error { return nil }
+
+func (d *dir) ReadDir(n int) ([]fs.DirEntry, error) {
+	if n <= 0 {
+		entries := d.entries
+		d.entries = nil
+		return entries, nil
+	}
+
+	if len(d.entries) == 0 {
+		return nil, io.EOF
+	}
+
+	if n > len(d.entries) {
+		n = len(d.entries)
+	}
+	entries := d.entries[:n]
+	d.entries = d.entries[n:]
+	return entries, nil
+}
+
+type fileInfo struct {
+	name    string
+	size    int64
+	dir     bool
+	modTime time.Time
+}
+
+func (fi fileInfo) Name() string       { return fi.name }
+func (fi fileInfo) Size() int64        { return fi.size }
+func (fi fileInfo) ModTime() time.Time { return fi.modTime }
+func (fi fileInfo) IsDir() bool        { return fi.dir }
+func (fi fileInfo) Sys() any           { return nil }
+
+func (fi fileInfo) Mode() fs.FileMode {
+	if fi.dir {
+		return fs.ModeDir | 0o555
+	}
+	return 0o444
+}
@@ -0,0 +1,82 @@
+package http
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
+)
+
+func TestFS(t *testing.T) {
+	const foo = `package:
+  name: foo
+
+advisories:
+  CVE-2023-1:
+    - timestamp: 2023-05-01T00:00:00Z
+      status: under_investigation
+`
+
+	published := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(published, "foo.advisories.yaml"), []byte(foo), 0o644))
+
+	var index bytes.Buffer
+	require.NoError(t, WriteIndex(&index, os.DirFS(published)))
+	require.NoError(t, os.WriteFile(filepath.Join(published, IndexFile), index.Bytes(), 0o644))
+
+	requests := make(map[string]int)
+	notModified := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path]++
+
+		b, err := os.ReadFile(filepath.Join(published, filepath.Base(r.URL.Path)))
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+
+		etag := fmt.Sprintf("%q", digest(b))
+		if r.Header.Get("If-None-Match") == etag {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Header().Set("ETag", etag)
+		_, _ = w.Write(b)
+	}))
+	defer server.Close()
+
+	cacheDir := t.TempDir()
+
+	fsys, err := New(server.URL, cacheDir)
+	require.NoError(t, err)
+
+	cfgs, err := advisoryconfigs.NewIndex(fsys)
+	require.NoError(t, err)
+	require.Len(t, cfgs.Select().Configurations(), 1)
+	assert.Equal(t, "foo", cfgs.Select().Configurations()[0].Package.Name)
+
+	_, err = fsys.Open("bar.advisories.yaml")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+
+	_, err = fsys.Create("bar.advisories.yaml")
+	assert.ErrorIs(t, err, fs.ErrPermission)
+
+	// A second FS revalidates the index, but uses the cached document as is.
+	fsys, err = New(server.URL, cacheDir)
+	require.NoError(t, err)
+	_, err = advisoryconfigs.NewIndex(fsys)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]int{"/" + IndexFile: 2, "/foo.advisories.yaml": 1}, requests)
+	assert.Equal(t, 1, notModified)
+}
@@ -0,0 +1,124 @@
+package os
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/wolfi-dev/wolfictl/pkg/configs/rwfs"
+)
+
+var (
+	// LockTimeout is how long Lock waits for another writer to release a lock.
+	LockTimeout = 30 * time.Second
+
+	// StaleLockAge is the age after which a lock is assumed to have been left
+	// behind by a writer that exited without releasing it, and is broken.
+	StaleLockAge = 5 * time.Minute
+)
+
+const (
+	lockSuffix       = ".lock"
+	minLockRetryWait = 10 * time.Millisecond
+	maxLockRetryWait = 500 * time.Millisecond
+)
+
+var _ rwfs.LockingFS = (*FS)(nil)
+
+// Lock acquires an exclusive lock for writing the named file, as a lock file
+// next to it, which works across processes and for any filesystem that supports
+// exclusive file creation. While another writer holds the lock, Lock retries
+// with exponential backoff until LockTimeout.
+func (fsys FS) Lock(name string) (func() error, error) {
+	p := fsys.fullPath(name) + lockSuffix
+	deadline := time.Now().Add(LockTimeout)
+	wait := minLockRetryWait
+
+	for {
+		f, err := os.OpenFile(p, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if err == nil {
+			_, _ = f.WriteString(strconv.Itoa(os.Getpid()) + "\n")
+			if err := f.Close(); err != nil {
+				_ = os.Remove(p)
+				return nil, fmt.Errorf("unable to lock %q: %w", name, err)
+			}
+
+			return func() error { return os.Remove(p) }, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, fmt.Errorf("unable to lock %q: %w", name, err)
+		}
+
+		if fi, err := os.Stat(p); err == nil && time.Since(fi.ModTime()) > StaleLockAge {
+			breakStaleLock(p, fi)
+			continue
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("unable to lock %q after %s: %w", name, LockTimeout, rwfs.ErrLocked)
+		}
+
+		time.Sleep(wait)
+		if wait *= 2; wait > maxLockRetryWait {
+			wait = maxLockRetryWait
+		}
+	}
+}
+
+// breakStaleLock removes the lock file at p, which stale describes, by first
+// renaming it to a name unique to this writer. Renaming is atomic, so only one of
+// any writers racing to break the stale lock gets it. A writer that instead
+// renames a lock newly acquired by another writer (having seen the stale one
+// before it was broken) notices that it isn't the stale lock and puts it back,
+// via a hard link, which unlike a rename never replaces an existing lock.
+func breakStaleLock(p string, stale fs.FileInfo) {
+	broken := fmt.Sprintf("%s.stale-%d-%d", p, os.Getpid(), time.Now().UnixNano())
+	if err := os.Rename(p, broken); err != nil {
+		// i.e. another writer broke it first
+		return
+	}
+
+	if fi, err := os.Stat(broken); err == nil && !os.SameFile(fi, stale) {
+		_ = os.Link(broken, p)
+	}
+	_ = os.Remove(broken)
+}
+
+// WriteFile atomically replaces the contents of the named file, by writing to a
+// temporary file in the same directory and renaming it over the original. The
+// original's permissions are kept.
+func (fsys FS) WriteFile(name string, data []byte) error {
+	p := fsys.fullPath(name)
+
+	perm := fs.FileMode(0o644)
+	if fi, err := os.Stat(p); err == nil {
+		perm = fi.Mode().Perm()
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(p), "."+filepath.Base(p)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), p)
+}
@@ -104,3 +104,11 @@ func (s Selection[T]) Configurations() []T {
 		return *cfg
 	})
 }
+
+// Paths returns the paths of the configuration files included in the current
+// Selection.
+func (s Selection[T]) Paths() []string {
+	return lo.Map(s.entries, func(e Entry[T], _ int) string {
+		return e.getPath()
+	})
+}
@@ -1,11 +1,13 @@
 package configs
 
 import (
+	"bytes"
 	"fmt"
 
-	"github.com/chainguard-dev/yam/pkg/yam/formatted"
 	"github.com/dprotaso/go-yit"
 	"github.com/pkg/errors"
+	"github.com/wolfi-dev/wolfictl/pkg/configs/rwfs"
+	"github.com/wolfi-dev/wolfictl/pkg/yamledit"
 	"gopkg.in/yaml.v3"
 )
 
@@ -30,6 +32,19 @@ func NewYAMLUpdateFunc[T Configuration](yamlASTMutater YAMLASTMutater[T]) EntryU
 			return err
 		}
 
+		if lfs, ok := i.fsys.(rwfs.LockingFS); ok {
+			var buf bytes.Buffer
+			if err := yamledit.Encode(&buf, root); err != nil {
+				return fmt.Errorf("unable to update %q: %w", e.getPath(), err)
+			}
+
+			if err := lfs.WriteFile(e.getPath(), buf.Bytes()); err != nil {
+				return fmt.Errorf("unable to update %q: %w", e.getPath(), err)
+			}
+
+			return nil
+		}
+
 		file, err := i.fsys.OpenAsWritable(e.getPath())
 		if err != nil {
 			return fmt.Errorf("unable to update %q: %w", e.getPath(), err)
@@ -41,11 +56,9 @@ func NewYAMLUpdateFunc[T Configuration](yamlASTMutater YAMLASTMutater[T]) EntryU
 			return fmt.Errorf("unable to update %q: %w", e.getPath(), err)
 		}
 
-		encoder := formatted.NewEncoder(file).AutomaticConfig()
-
-		err = encoder.Encode(root)
+		err = yamledit.Encode(file, root)
 		if err != nil {
-			return fmt.Errorf("unable to encode updated YAML: %w", err)
+			return fmt.Errorf("unable to update %q: %w", e.getPath(), err)
 		}
 
 		return nil
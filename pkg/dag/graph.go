@@ -713,6 +713,72 @@ func (g Graph) DependenciesOf(node string) []string {
 	return nil
 }
 
+// DependentsOf returns, for each of the given origin packages, the names of the
+// origin packages that directly depend on it or any of its subpackages, sorted
+// alphabetically. A package is not included among its own dependents.
+//
+// The graph's predecessor map is computed once per call, so callers interested
+// in several packages should pass them all at once.
+func (g Graph) DependentsOf(origins ...string) (map[string][]string, error) {
+	predecessorMap, err := g.Graph.PredecessorMap()
+	if err != nil {
+		return nil, err
+	}
+
+	dependents := make(map[string]map[string]struct{}, len(origins))
+	for _, origin := range origins {
+		dependents[origin] = make(map[string]struct{})
+	}
+
+	for node, predecessors := range predecessorMap {
+		origin, ok := g.originOf(node)
+		if !ok {
+			continue
+		}
+		originDependents, ok := dependents[origin]
+		if !ok {
+			continue
+		}
+
+		for predecessor := range predecessors {
+			v, err := g.Graph.Vertex(predecessor)
+			if err != nil {
+				return nil, err
+			}
+			c, ok := v.(*Configuration)
+			if !ok || c.Package.Name == origin {
+				continue
+			}
+			originDependents[c.Package.Name] = struct{}{}
+		}
+	}
+
+	result := make(map[string][]string, len(dependents))
+	for origin, names := range dependents {
+		sorted := make([]string, 0, len(names))
+		for name := range names {
+			sorted = append(sorted, name)
+		}
+		sort.Strings(sorted)
+		result[origin] = sorted
+	}
+	return result, nil
+}
+
+// originOf returns the name of the origin package of the given node, if the
+// node is an origin package or one of its subpackages.
+func (g Graph) originOf(node string) (string, bool) {
+	v, err := g.Graph.Vertex(node)
+	if err != nil {
+		return "", false
+	}
+	c, ok := v.(*Configuration)
+	if !ok {
+		return "", false
+	}
+	return c.Package.Name, true
+}
+
 // Packages returns a slice of the names of all origin packages, sorted alphabetically.
 func (g Graph) Packages() []string {
 	return g.packages.PackageNames()
@@ -0,0 +1,26 @@
+package gh
+
+import (
+	"context"
+
+	"github.com/google/go-github/v50/github"
+)
+
+// ListCheckRuns returns the check runs for the given ref (a SHA, branch, or tag)
+// using pagination.
+func (o GitOptions) ListCheckRuns(ctx context.Context, owner, repo, ref string) ([]*github.CheckRun, error) {
+	var runs []*github.CheckRun
+
+	err := o.handleRateLimitList(func(opt *github.ListOptions) (*github.Response, error) {
+		clo := &github.ListCheckRunsOptions{
+			ListOptions: *opt,
+		}
+		rs, resp, err := o.GithubClient.Checks.ListCheckRunsForRef(ctx, owner, repo, ref, clo)
+		if rs != nil {
+			runs = append(runs, rs.CheckRuns...)
+		}
+		return resp, err
+	})
+
+	return runs, err
+}
@@ -81,7 +81,15 @@ func (o GitOptions) HasExistingComment(ctx context.Context, r *Issues, issueNumb
 	return false, nil
 }
 
+// OpenIssue creates the given issue, after checking that its labels exist in the
+// repository (GitHub would otherwise create them on the fly).
 func (o GitOptions) OpenIssue(ctx context.Context, r *Issues) (string, error) {
+	if len(r.Labels) > 0 {
+		if err := o.CheckLabelsExist(ctx, r.Owner, r.RepoName, r.Labels); err != nil {
+			return "", err
+		}
+	}
+
 	newIssue := &github.IssueRequest{
 		Title:  github.String(r.Title),
 		Body:   github.String(r.Comment),
@@ -63,17 +63,8 @@ func TestCheckExistingIssue(t *testing.T) {
 
 func TestOpenIssue(t *testing.T) {
 	// Create a test server that simulates the GitHub API
-	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Respond with the created issue JSON
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusCreated)
-		_, err := w.Write([]byte(`{
-			"number": 1,
-			"title": "wolfi-bot/foo-package",
-			"html_url": "https://github.com/cheese/crisps/issues/1"
-		}`))
-		assert.NoError(t, err)
-	}))
+	var created bool
+	testServer := httptest.NewServer(openIssueHandler(t, &created))
 	defer testServer.Close()
 
 	// Create a mock GitHub client
@@ -105,6 +96,59 @@ func TestOpenIssue(t *testing.T) {
 	// Assert that the returned HTML URL is correct and there's no error
 	assert.NoError(t, err)
 	assert.Equal(t, "https://github.com/cheese/crisps/issues/1", htmlURL)
+	assert.True(t, created)
+}
+
+func TestOpenIssueMissingLabels(t *testing.T) {
+	var created bool
+	testServer := httptest.NewServer(openIssueHandler(t, &created))
+	defer testServer.Close()
+
+	client := github.NewClient(testServer.Client())
+	var err error
+	client.BaseURL, err = url.Parse(testServer.URL + "/")
+	assert.NoError(t, err)
+
+	gitOptions := GitOptions{
+		GithubClient: client,
+	}
+
+	issues := &Issues{
+		Owner:    "cheese",
+		RepoName: "crisps",
+		Title:    GetErrorIssueTitle("wolfi-bot", "foo-package"),
+		Labels:   []string{"prawn", "salt and vinegar"},
+	}
+	_, err = gitOptions.OpenIssue(context.Background(), issues)
+
+	assert.ErrorContains(t, err, "labels [salt and vinegar] don't exist in cheese/crisps")
+	assert.False(t, created)
+}
+
+// openIssueHandler simulates the GitHub API for opening an issue in
+// cheese/crisps, where the labels "prawn" and "cocktail" exist. created is set
+// once the issue is created.
+func openIssueHandler(t *testing.T, created *bool) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/cheese/crisps/labels", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, err := w.Write([]byte(`[{"name": "prawn"}, {"name": "cocktail"}]`))
+		assert.NoError(t, err)
+	})
+	mux.HandleFunc("/repos/cheese/crisps/issues", func(w http.ResponseWriter, r *http.Request) {
+		*created = true
+
+		// Respond with the created issue JSON
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, err := w.Write([]byte(`{
+			"number": 1,
+			"title": "wolfi-bot/foo-package",
+			"html_url": "https://github.com/cheese/crisps/issues/1"
+		}`))
+		assert.NoError(t, err)
+	})
+	return mux
 }
 
 func TestGitOptions_CloseIssue(t *testing.T) {
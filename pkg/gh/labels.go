@@ -0,0 +1,170 @@
+package gh
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/google/go-github/v50/github"
+	"gopkg.in/yaml.v3"
+)
+
+// Label is a GitHub issue and pull request label.
+type Label struct {
+	Name        string `yaml:"name"`
+	Color       string `yaml:"color"`
+	Description string `yaml:"description,omitempty"`
+}
+
+// LabelsConfig defines the standard set of labels to maintain across a set of
+// repositories.
+type LabelsConfig struct {
+	// Repositories are the URLs of the repositories to sync labels to, e.g.
+	// "https://github.com/wolfi-dev/os".
+	Repositories []string `yaml:"repositories"`
+
+	Labels []Label `yaml:"labels"`
+}
+
+// ReadLabelsConfig reads a LabelsConfig from the YAML file at the given path.
+func ReadLabelsConfig(path string) (*LabelsConfig, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open labels config: %w", err)
+	}
+	defer f.Close()
+
+	cfg := &LabelsConfig{}
+	if err := yaml.NewDecoder(f).Decode(cfg); err != nil {
+		return nil, fmt.Errorf("failed to decode labels config %s: %w", path, err)
+	}
+
+	for i, l := range cfg.Labels {
+		if l.Name == "" {
+			return nil, fmt.Errorf("label %d in %s has no name", i+1, path)
+		}
+		cfg.Labels[i].Color = strings.TrimPrefix(strings.ToLower(l.Color), "#")
+	}
+
+	return cfg, nil
+}
+
+// LabelSyncResult describes the changes made (or that would be made, for a dry
+// run) when syncing labels to a repository.
+type LabelSyncResult struct {
+	Created   []string
+	Updated   []string
+	Unchanged []string
+}
+
+func (o GitOptions) ListLabels(ctx context.Context, owner, repo string) ([]*github.Label, error) {
+	var labels []*github.Label
+
+	err := o.handleRateLimitList(func(opt *github.ListOptions) (*github.Response, error) {
+		ls, resp, err := o.GithubClient.Issues.ListLabels(ctx, owner, repo, opt)
+		labels = append(labels, ls...)
+		return resp, err
+	})
+
+	return labels, err
+}
+
+// SyncLabels creates any of the given labels that don't exist in the
+// repository, and updates the color and description of those that do, so that
+// they match. Labels in the repository that aren't given are left alone.
+func (o GitOptions) SyncLabels(ctx context.Context, owner, repo string, labels []Label, dryRun bool) (*LabelSyncResult, error) {
+	existing, err := o.ListLabels(ctx, owner, repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list labels for %s/%s: %w", owner, repo, err)
+	}
+
+	existingByName := make(map[string]*github.Label)
+	for _, l := range existing {
+		existingByName[strings.ToLower(l.GetName())] = l
+	}
+
+	result := &LabelSyncResult{}
+	for _, l := range labels {
+		want := &github.Label{
+			Name:        github.String(l.Name),
+			Color:       github.String(l.Color),
+			Description: github.String(l.Description),
+		}
+
+		current, ok := existingByName[strings.ToLower(l.Name)]
+		if !ok {
+			result.Created = append(result.Created, l.Name)
+			if dryRun {
+				continue
+			}
+
+			err := o.handleRateLimit(func() (*github.Response, error) {
+				_, resp, err := o.GithubClient.Issues.CreateLabel(ctx, owner, repo, want)
+				return resp, err
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to create label %q in %s/%s: %w", l.Name, owner, repo, err)
+			}
+			continue
+		}
+
+		if current.GetName() == l.Name && strings.EqualFold(current.GetColor(), l.Color) && current.GetDescription() == l.Description {
+			result.Unchanged = append(result.Unchanged, l.Name)
+			continue
+		}
+
+		result.Updated = append(result.Updated, l.Name)
+		if dryRun {
+			continue
+		}
+
+		err := o.handleRateLimit(func() (*github.Response, error) {
+			_, resp, err := o.GithubClient.Issues.EditLabel(ctx, owner, repo, current.GetName(), want)
+			return resp, err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to update label %q in %s/%s: %w", l.Name, owner, repo, err)
+		}
+	}
+
+	return result, nil
+}
+
+// MissingLabels returns the names among the given labels that don't exist in
+// the repository.
+func (o GitOptions) MissingLabels(ctx context.Context, owner, repo string, names []string) ([]string, error) {
+	existing, err := o.ListLabels(ctx, owner, repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list labels for %s/%s: %w", owner, repo, err)
+	}
+
+	existingNames := make(map[string]struct{})
+	for _, l := range existing {
+		existingNames[strings.ToLower(l.GetName())] = struct{}{}
+	}
+
+	var missing []string
+	for _, name := range names {
+		if _, ok := existingNames[strings.ToLower(name)]; !ok {
+			missing = append(missing, name)
+		}
+	}
+
+	return missing, nil
+}
+
+// CheckLabelsExist returns an error naming any of the given labels that don't
+// exist in the repository.
+func (o GitOptions) CheckLabelsExist(ctx context.Context, owner, repo string, names []string) error {
+	missing, err := o.MissingLabels(ctx, owner, repo, names)
+	if err != nil {
+		return err
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("labels [%s] don't exist in %s/%s, create them first (e.g. with 'wolfictl gh labels sync')", strings.Join(missing, ", "), owner, repo)
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package gh
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-github/v50/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncLabels(t *testing.T) {
+	var created, edited []string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/cheese/crisps/labels", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.Method {
+		case http.MethodGet:
+			_, err := w.Write([]byte(`[
+				{"name": "automated pr", "color": "ededed", "description": "created by automation"},
+				{"name": "Bug", "color": "d73a4a"},
+				{"name": "unmanaged", "color": "000000"}
+			]`))
+			assert.NoError(t, err)
+
+		case http.MethodPost:
+			var l github.Label
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&l))
+			created = append(created, l.GetName())
+			w.WriteHeader(http.StatusCreated)
+			assert.NoError(t, json.NewEncoder(w).Encode(l))
+		}
+	})
+	mux.HandleFunc("/repos/cheese/crisps/labels/Bug", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPatch, r.Method)
+
+		var l github.Label
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&l))
+		edited = append(edited, l.GetName())
+		w.Header().Set("Content-Type", "application/json")
+		assert.NoError(t, json.NewEncoder(w).Encode(l))
+	})
+
+	testServer := httptest.NewServer(mux)
+	defer testServer.Close()
+
+	client := github.NewClient(testServer.Client())
+	var err error
+	client.BaseURL, err = url.Parse(testServer.URL + "/")
+	require.NoError(t, err)
+
+	gitOptions := GitOptions{
+		GithubClient: client,
+	}
+
+	labels := []Label{
+		{Name: "automated pr", Color: "ededed", Description: "created by automation"},
+		{Name: "bug", Color: "d73a4a", Description: "something isn't working"},
+		{Name: "advisory", Color: "5319e7"},
+	}
+
+	result, err := gitOptions.SyncLabels(context.Background(), "cheese", "crisps", labels, false)
+	require.NoError(t, err)
+
+	assert.Equal(t, &LabelSyncResult{
+		Created:   []string{"advisory"},
+		Updated:   []string{"bug"},
+		Unchanged: []string{"automated pr"},
+	}, result)
+	assert.Equal(t, []string{"advisory"}, created)
+	assert.Equal(t, []string{"bug"}, edited)
+
+	missing, err := gitOptions.MissingLabels(context.Background(), "cheese", "crisps", []string{"bug", "advisory", "automated pr"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"advisory"}, missing)
+}
@@ -0,0 +1,125 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// CommitAsOf returns the most recent commit reachable from HEAD of the
+// repository at dir that was committed at or before the given time.
+func CommitAsOf(dir string, asOf time.Time) (*object.Commit, error) {
+	r, err := git.PlainOpen(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open git repository %s: %w", dir, err)
+	}
+
+	iter, err := r.Log(&git.LogOptions{Order: git.LogOrderCommitterTime})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get git log for %s: %w", dir, err)
+	}
+	defer iter.Close()
+
+	for {
+		c, err := iter.Next()
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("no commits in %s as of %s", dir, asOf.Format(time.RFC3339))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate git log for %s: %w", dir, err)
+		}
+
+		if !c.Committer.When.After(asOf) {
+			return c, nil
+		}
+	}
+}
+
+// CheckoutAsOf writes the files of the repository at dir, as they were at the
+// given time (see CommitAsOf), to a new temporary directory. The repository's
+// worktree isn't modified. It returns the temporary directory's path, which the
+// caller is responsible for removing, along with the commit used.
+func CheckoutAsOf(dir string, asOf time.Time) (string, *object.Commit, error) {
+	c, err := CommitAsOf(dir, asOf)
+	if err != nil {
+		return "", nil, err
+	}
+
+	tempDir, err := checkoutCommit(c)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return tempDir, c, nil
+}
+
+// CheckoutRef is like CheckoutAsOf, but writes the files as of the given git
+// revision (e.g. a branch, tag, or commit hash) instead.
+func CheckoutRef(dir, ref string) (string, *object.Commit, error) {
+	r, err := git.PlainOpen(dir)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to open git repository %s: %w", dir, err)
+	}
+
+	hash, err := r.ResolveRevision(plumbing.Revision(ref))
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to resolve %q in %s: %w", ref, dir, err)
+	}
+
+	c, err := r.CommitObject(*hash)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get commit %s: %w", hash, err)
+	}
+
+	tempDir, err := checkoutCommit(c)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return tempDir, c, nil
+}
+
+// checkoutCommit writes the files of the given commit to a new temporary
+// directory, and returns its path.
+func checkoutCommit(c *object.Commit) (string, error) {
+	tree, err := c.Tree()
+	if err != nil {
+		return "", fmt.Errorf("failed to get tree for commit %s: %w", c.Hash, err)
+	}
+
+	tempDir, err := os.MkdirTemp("", "wolfictl-as-of-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp directory: %w", err)
+	}
+
+	err = tree.Files().ForEach(func(f *object.File) error {
+		if !f.Mode.IsFile() {
+			return nil
+		}
+
+		contents, err := f.Contents()
+		if err != nil {
+			return fmt.Errorf("failed to read %s at commit %s: %w", f.Name, c.Hash, err)
+		}
+
+		p := filepath.Join(tempDir, filepath.FromSlash(f.Name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			return err
+		}
+
+		return os.WriteFile(p, []byte(contents), 0o644) //nolint:gosec
+	})
+	if err != nil {
+		_ = os.RemoveAll(tempDir)
+		return "", err
+	}
+
+	return tempDir, nil
+}
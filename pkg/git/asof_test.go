@@ -0,0 +1,110 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckoutAsOf(t *testing.T) {
+	dir := t.TempDir()
+
+	r, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+	w, err := r.Worktree()
+	require.NoError(t, err)
+
+	commit := func(contents string, when time.Time) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "foo.advisories.yaml"), []byte(contents), 0o644))
+		_, err := w.Add("foo.advisories.yaml")
+		require.NoError(t, err)
+
+		sig := &object.Signature{Name: "John Doe", Email: "john@doe.org", When: when}
+		_, err = w.Commit(contents, &git.CommitOptions{Author: sig, Committer: sig})
+		require.NoError(t, err)
+	}
+
+	jan := time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)
+	commit("first", jan)
+	commit("second", jan.AddDate(0, 1, 0))
+	commit("third", jan.AddDate(0, 2, 0))
+
+	cases := []struct {
+		name     string
+		asOf     time.Time
+		expected string
+	}{
+		{name: "exactly at a commit", asOf: jan.AddDate(0, 1, 0), expected: "second"},
+		{name: "between commits", asOf: jan.AddDate(0, 1, 15), expected: "second"},
+		{name: "after all commits", asOf: jan.AddDate(1, 0, 0), expected: "third"},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			checkoutDir, c, err := CheckoutAsOf(dir, tt.asOf)
+			require.NoError(t, err)
+			defer os.RemoveAll(checkoutDir)
+
+			assert.Equal(t, tt.expected, c.Message)
+
+			b, err := os.ReadFile(filepath.Join(checkoutDir, "foo.advisories.yaml"))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(b))
+		})
+	}
+
+	t.Run("before any commits", func(t *testing.T) {
+		_, _, err := CheckoutAsOf(dir, jan.AddDate(-1, 0, 0))
+		assert.Error(t, err)
+	})
+
+	// The worktree is left alone.
+	b, err := os.ReadFile(filepath.Join(dir, "foo.advisories.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "third", string(b))
+}
+
+func TestCheckoutRef(t *testing.T) {
+	dir := t.TempDir()
+
+	r, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+	w, err := r.Worktree()
+	require.NoError(t, err)
+
+	commit := func(contents string) plumbing.Hash {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "foo.advisories.yaml"), []byte(contents), 0o644))
+		_, err := w.Add("foo.advisories.yaml")
+		require.NoError(t, err)
+
+		sig := &object.Signature{Name: "John Doe", Email: "john@doe.org", When: time.Now()}
+		h, err := w.Commit(contents, &git.CommitOptions{Author: sig, Committer: sig})
+		require.NoError(t, err)
+		return h
+	}
+
+	first := commit("first")
+	commit("second")
+
+	for _, ref := range []string{first.String(), "HEAD~1"} {
+		checkoutDir, c, err := CheckoutRef(dir, ref)
+		require.NoError(t, err)
+		defer os.RemoveAll(checkoutDir)
+
+		assert.Equal(t, first, c.Hash)
+
+		b, err := os.ReadFile(filepath.Join(checkoutDir, "foo.advisories.yaml"))
+		require.NoError(t, err)
+		assert.Equal(t, "first", string(b))
+	}
+
+	_, _, err = CheckoutRef(dir, "no-such-branch")
+	assert.Error(t, err)
+}
@@ -0,0 +1,62 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// LineAuthors returns, for each line of the file at path (relative to dir), the
+// name of the author of the commit that last changed it, as reported by git
+// blame. The author of line n is at index n-1. Lines with uncommitted changes
+// have an empty author.
+func LineAuthors(dir, path string) ([]string, error) {
+	cmd := exec.Command("git", "blame", "--line-porcelain", "--", path) //nolint:gosec
+	cmd.Dir = dir
+	rs, err := cmd.Output()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to blame %s", path)
+	}
+
+	var (
+		authors     []string
+		author      string
+		uncommitted bool
+	)
+	scanner := bufio.NewScanner(bytes.NewReader(rs))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	header := true
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		switch {
+		case strings.HasPrefix(line, "\t"):
+			// The line's content ends its entry.
+			if uncommitted {
+				author = ""
+			}
+			authors = append(authors, author)
+			header = true
+
+		case header:
+			// The first line of an entry starts with the commit hash, which is all
+			// zeros for uncommitted changes.
+			hash, _, _ := strings.Cut(line, " ")
+			uncommitted = strings.Trim(hash, "0") == ""
+			header = false
+
+		default:
+			if name, ok := strings.CutPrefix(line, "author "); ok {
+				author = name
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrapf(err, "failed to read blame of %s", path)
+	}
+
+	return authors, nil
+}
@@ -0,0 +1,43 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLineAuthors(t *testing.T) {
+	dir := t.TempDir()
+
+	run := func(author string, args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME="+author, "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com",
+		)
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	write := func(content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "foo.yaml"), []byte(content), 0o600))
+	}
+
+	run("test", "init", "-q", "-b", "main")
+	write("a\nb\n")
+	run("Alice", "add", "-A")
+	run("Alice", "commit", "-q", "-m", "first")
+
+	write("a\nb\nc\n")
+	run("Bob", "commit", "-q", "-am", "second")
+
+	write("a\nb\nc\nd\n")
+
+	authors, err := LineAuthors(dir, "foo.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Alice", "Alice", "Bob", ""}, authors)
+}
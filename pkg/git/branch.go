@@ -0,0 +1,87 @@
+package git
+
+import (
+	"fmt"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// CommitToNewBranch creates a branch with the given name at the current HEAD of
+// the repository at dir, checks it out (keeping any local changes), and commits
+// the given paths (relative to dir) to it with the given message. No other
+// changes in the worktree are staged. The author is taken from
+// GIT_AUTHOR_NAME and GIT_AUTHOR_EMAIL if set, otherwise from the git config.
+//
+// If the commit can't be made, the original HEAD is checked out again and the
+// new branch is deleted.
+func CommitToNewBranch(dir, branch, message string, paths []string) (plumbing.Hash, error) {
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("unable to open git repository at %s: %w", dir, err)
+	}
+
+	wt, err := repo.Worktree()
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("unable to get worktree: %w", err)
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("unable to get HEAD: %w", err)
+	}
+
+	branchRef := plumbing.NewBranchReferenceName(branch)
+	err = wt.Checkout(&git.CheckoutOptions{
+		Branch: branchRef,
+		Create: true,
+		Keep:   true,
+	})
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("unable to check out new branch %s: %w", branch, err)
+	}
+
+	hash, err := commitPaths(wt, message, paths)
+	if err != nil {
+		if restoreErr := restoreHead(repo, wt, head, branchRef); restoreErr != nil {
+			return plumbing.ZeroHash, fmt.Errorf("%w (and unable to restore the original HEAD: %v)", err, restoreErr)
+		}
+		return plumbing.ZeroHash, err
+	}
+
+	return hash, nil
+}
+
+func commitPaths(wt *git.Worktree, message string, paths []string) (plumbing.Hash, error) {
+	for _, p := range paths {
+		if _, err := wt.Add(p); err != nil {
+			return plumbing.ZeroHash, fmt.Errorf("unable to stage %s: %w", p, err)
+		}
+	}
+
+	hash, err := wt.Commit(message, &git.CommitOptions{
+		Author: GetGitAuthorSignature(),
+	})
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("unable to commit: %w", err)
+	}
+
+	return hash, nil
+}
+
+// restoreHead checks out the given original HEAD again, keeping local changes,
+// and deletes the branch that was created.
+func restoreHead(repo *git.Repository, wt *git.Worktree, head *plumbing.Reference, created plumbing.ReferenceName) error {
+	opts := &git.CheckoutOptions{Keep: true}
+	if head.Name().IsBranch() {
+		opts.Branch = head.Name()
+	} else {
+		opts.Hash = head.Hash()
+	}
+
+	if err := wt.Checkout(opts); err != nil {
+		return err
+	}
+
+	return repo.Storer.RemoveReference(created)
+}
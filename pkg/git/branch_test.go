@@ -0,0 +1,84 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommitToNewBranch(t *testing.T) {
+	t.Setenv("GIT_AUTHOR_NAME", "test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@tester.com")
+
+	dir := t.TempDir()
+	r := initRepo(t, dir)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bar.advisories.yaml"), []byte("second"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "unrelated.txt"), []byte("dirty"), 0o644))
+
+	hash, err := CommitToNewBranch(dir, "new-advisories", "add bar", []string{"bar.advisories.yaml"})
+	require.NoError(t, err)
+
+	head, err := r.Head()
+	require.NoError(t, err)
+	assert.Equal(t, "refs/heads/new-advisories", head.Name().String())
+	assert.Equal(t, hash, head.Hash())
+
+	c, err := r.CommitObject(hash)
+	require.NoError(t, err)
+	assert.Equal(t, "test", c.Author.Name)
+
+	_, err = c.File("bar.advisories.yaml")
+	assert.NoError(t, err)
+
+	// unrelated changes are left alone
+	_, err = c.File("unrelated.txt")
+	assert.Error(t, err)
+	_, err = os.Stat(filepath.Join(dir, "unrelated.txt"))
+	assert.NoError(t, err)
+}
+
+func TestCommitToNewBranchRestoresHeadOnError(t *testing.T) {
+	t.Setenv("GIT_AUTHOR_NAME", "test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@tester.com")
+
+	dir := t.TempDir()
+	r := initRepo(t, dir)
+
+	original, err := r.Head()
+	require.NoError(t, err)
+
+	_, err = CommitToNewBranch(dir, "new-advisories", "add missing", []string{"missing.advisories.yaml"})
+	assert.Error(t, err)
+
+	head, err := r.Head()
+	require.NoError(t, err)
+	assert.Equal(t, original.Name(), head.Name())
+	assert.Equal(t, original.Hash(), head.Hash())
+
+	_, err = r.Reference(plumbing.NewBranchReferenceName("new-advisories"), false)
+	assert.ErrorIs(t, err, plumbing.ErrReferenceNotFound)
+}
+
+// initRepo initializes a git repository at dir with a single commit.
+func initRepo(t *testing.T, dir string) *git.Repository {
+	t.Helper()
+
+	r, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "foo.advisories.yaml"), []byte("first"), 0o644))
+	wt, err := r.Worktree()
+	require.NoError(t, err)
+	_, err = wt.Add("foo.advisories.yaml")
+	require.NoError(t, err)
+	_, err = wt.Commit("initial", &git.CommitOptions{Author: GetGitAuthorSignature()})
+	require.NoError(t, err)
+
+	return r
+}
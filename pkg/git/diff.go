@@ -0,0 +1,84 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ChangedFiles returns the paths of the files that have changed in the git
+// repository at dir since it diverged from ref (i.e. since the merge base of ref
+// and HEAD), including any uncommitted changes. Returned paths are relative to
+// dir, and changes outside of dir are omitted.
+func ChangedFiles(dir, ref string) ([]string, error) {
+	cmd := exec.Command("git", "merge-base", ref, "HEAD") //nolint:gosec
+	cmd.Dir = dir
+	rs, err := cmd.Output()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find merge base of %s and HEAD", ref)
+	}
+	base := strings.TrimSpace(string(rs))
+
+	cmd = exec.Command("git", "diff", "--name-only", "--relative", base) //nolint:gosec
+	cmd.Dir = dir
+	rs, err = cmd.Output()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to diff against %s", base)
+	}
+
+	var files []string
+	for _, line := range strings.Split(string(rs), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			files = append(files, line)
+		}
+	}
+
+	return files, nil
+}
+
+// MergeBase returns the hash of the best common ancestor of the revisions a and
+// b in the git repository at dir.
+func MergeBase(dir, a, b string) (string, error) {
+	cmd := exec.Command("git", "merge-base", a, b) //nolint:gosec
+	cmd.Dir = dir
+	rs, err := cmd.Output()
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to find merge base of %s and %s", a, b)
+	}
+
+	return strings.TrimSpace(string(rs)), nil
+}
+
+// RevisionBefore returns the hash of the most recent commit reachable from HEAD
+// in the git repository at dir that was committed before t.
+func RevisionBefore(dir string, t time.Time) (string, error) {
+	cmd := exec.Command("git", "rev-list", "-1", "--before="+t.Format(time.RFC3339), "HEAD") //nolint:gosec
+	cmd.Dir = dir
+	rs, err := cmd.Output()
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to find last commit before %s", t)
+	}
+
+	rev := strings.TrimSpace(string(rs))
+	if rev == "" {
+		return "", fmt.Errorf("no commits found before %s", t)
+	}
+
+	return rev, nil
+}
+
+// FileAtRevision returns the content of the file at path (relative to dir) as of
+// the given revision.
+func FileAtRevision(dir, rev, path string) ([]byte, error) {
+	cmd := exec.Command("git", "show", rev+":./"+path) //nolint:gosec
+	cmd.Dir = dir
+	rs, err := cmd.Output()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read %s at %s", path, rev)
+	}
+
+	return rs, nil
+}
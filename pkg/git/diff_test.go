@@ -0,0 +1,46 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangedFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	run := func(args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com",
+		)
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	write := func(name, content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	}
+
+	run("init", "-q", "-b", "main")
+	write("unchanged.yaml", "a")
+	write("modified.yaml", "a")
+	run("add", "-A")
+	run("commit", "-q", "-m", "base")
+
+	run("checkout", "-q", "-b", "feature")
+	write("modified.yaml", "b")
+	write("added.yaml", "a")
+	run("add", "-A")
+	run("commit", "-q", "-m", "change")
+	write("unchanged.yaml", "uncommitted")
+
+	files, err := ChangedFiles(dir, "main")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"added.yaml", "modified.yaml", "unchanged.yaml"}, files)
+}
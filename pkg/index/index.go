@@ -1,12 +1,14 @@
 package index
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"strings"
 
+	"github.com/wolfi-dev/wolfictl/pkg/trust"
 	"gitlab.alpinelinux.org/alpine/go/repository"
 )
 
@@ -26,7 +28,15 @@ func Index(arch, repo string) (*repository.ApkIndex, error) {
 			}
 			return nil, fmt.Errorf("GET %s (%d): %s", url, resp.StatusCode, b)
 		}
-		rc = resp.Body
+
+		b, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("GET %s: %w", url, err)
+		}
+		if err := trust.EnforceIndex(url, b); err != nil {
+			return nil, err
+		}
+		rc = io.NopCloser(bytes.NewReader(b))
 	} else {
 		f, err := os.Open(repo)
 		if err != nil {
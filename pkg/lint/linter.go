@@ -48,7 +48,13 @@ func New(opts ...Option) *Linter {
 func (l *Linter) Lint() (Result, error) {
 	rules := AllRules(l)
 
-	filesToLint, err := melange.ReadAllPackagesFromRepo(l.options.Path)
+	var filesToLint map[string]*melange.Packages
+	var err error
+	if l.options.Files != nil {
+		filesToLint, err = melange.ReadPackagesFromRepo(l.options.Path, l.options.Files)
+	} else {
+		filesToLint, err = melange.ReadAllPackagesFromRepo(l.options.Path)
+	}
 	if err != nil {
 		return Result{}, err
 	}
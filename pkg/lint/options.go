@@ -10,6 +10,10 @@ type Options struct {
 
 	// Skip rules removes the given slice of rules to be checked
 	SkipRules []string
+
+	// Files restricts linting to the given config files (relative to Path). If
+	// nil, all configs found in Path are linted.
+	Files []string
 }
 
 // Option represents a linter option.
@@ -35,3 +39,10 @@ func WithSkipRules(skipRules []string) Option {
 		o.SkipRules = skipRules
 	}
 }
+
+// WithFiles restricts linting to the given config files.
+func WithFiles(files []string) Option {
+	return func(o *Options) {
+		o.Files = files
+	}
+}
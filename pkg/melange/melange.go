@@ -104,6 +104,29 @@ func ReadAllPackagesFromRepo(dir string) (map[string]*Packages, error) {
 		return p, errors.Wrapf(err, "failed walking files in cloned directory %s", dir)
 	}
 
+	p, err = readPackages(dir, fileList)
+	if err != nil {
+		return p, err
+	}
+	fmt.Printf("found %[1]d packages\n", len(p))
+	return p, nil
+}
+
+// ReadPackagesFromRepo reads the melange configs among the given files
+// (relative to dir) at the top level of the repo in dir, without reading any
+// other config in the repo. Files that aren't melange configs are skipped.
+func ReadPackagesFromRepo(dir string, files []string) (map[string]*Packages, error) {
+	fileList := make([]string, 0, len(files))
+	for _, f := range files {
+		fileList = append(fileList, filepath.Join(dir, f))
+	}
+
+	return readPackages(dir, fileList)
+}
+
+func readPackages(dir string, fileList []string) (map[string]*Packages, error) {
+	p := make(map[string]*Packages)
+
 	for _, fi := range fileList {
 		data, err := os.ReadFile(fi)
 		if err != nil {
@@ -142,7 +165,6 @@ func ReadAllPackagesFromRepo(dir string) (map[string]*Packages, error) {
 			NoLint:   nolint,
 		}
 	}
-	fmt.Printf("found %[1]d packages\n", len(p))
 	return p, nil
 }
 
@@ -0,0 +1,150 @@
+package scan
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+const osvAPIBaseURL = "https://api.osv.dev/v1"
+
+// PublishedDates looks up when vulnerabilities were first published, using the
+// OSV API, which covers both NVD (CVE) and GitHub (GHSA) advisories.
+type PublishedDates struct {
+	client  *http.Client
+	baseURL string
+
+	mu    sync.Mutex
+	cache map[string]time.Time
+}
+
+// NewPublishedDates returns a PublishedDates that uses the given HTTP client.
+func NewPublishedDates(client *http.Client) *PublishedDates {
+	return &PublishedDates{
+		client:  client,
+		baseURL: osvAPIBaseURL,
+		cache:   make(map[string]time.Time),
+	}
+}
+
+// Lookup returns when the given vulnerability was published. It returns a zero
+// time if the vulnerability isn't known.
+func (d *PublishedDates) Lookup(ctx context.Context, id string) (time.Time, error) {
+	d.mu.Lock()
+	if t, ok := d.cache[id]; ok {
+		d.mu.Unlock()
+		return t, nil
+	}
+	d.mu.Unlock()
+
+	url := fmt.Sprintf("%s/vulns/%s", d.baseURL, id)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unable to look up %s: %w", id, err)
+	}
+	defer resp.Body.Close()
+
+	var published time.Time
+	switch resp.StatusCode {
+	case http.StatusOK:
+		var v struct {
+			Published time.Time `json:"published"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&v); err != nil {
+			return time.Time{}, fmt.Errorf("unable to decode OSV data for %s: %w", id, err)
+		}
+		published = v.Published
+	case http.StatusNotFound:
+		// Not known; leave the date unset.
+	default:
+		return time.Time{}, fmt.Errorf("unable to look up %s: GET %s: %d", id, url, resp.StatusCode)
+	}
+
+	d.mu.Lock()
+	d.cache[id] = published
+	d.mu.Unlock()
+
+	return published, nil
+}
+
+// Annotate sets the Published date of each finding's vulnerability, using the
+// earliest date known for the vulnerability's ID and aliases.
+func (d *PublishedDates) Annotate(ctx context.Context, findings []*Finding) error {
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(8)
+
+	for _, f := range findings {
+		f := f
+		g.Go(func() error {
+			ids := append([]string{f.Vulnerability.ID}, f.Vulnerability.Aliases...)
+
+			var earliest time.Time
+			for _, id := range ids {
+				t, err := d.Lookup(ctx, id)
+				if err != nil {
+					return err
+				}
+				if !t.IsZero() && (earliest.IsZero() || t.Before(earliest)) {
+					earliest = t
+				}
+			}
+
+			if !earliest.IsZero() {
+				f.Vulnerability.Published = &earliest
+			}
+			return nil
+		})
+	}
+
+	return g.Wait()
+}
+
+// Age returns how long ago the vulnerability was published, as of now. It
+// returns zero if the published date isn't known.
+func (v Vulnerability) Age(now time.Time) time.Duration {
+	if v.Published == nil {
+		return 0
+	}
+
+	return now.Sub(*v.Published)
+}
+
+// FilterByAge returns the findings whose vulnerabilities were published at
+// least minAge and at most maxAge before now. A zero minAge or maxAge means no
+// bound. Findings whose published date isn't known are kept, since their age
+// can't be judged.
+func FilterByAge(findings []*Finding, now time.Time, minAge, maxAge time.Duration) []*Finding {
+	if minAge == 0 && maxAge == 0 {
+		return findings
+	}
+
+	var result []*Finding
+	for _, f := range findings {
+		if f.Vulnerability.Published == nil {
+			result = append(result, f)
+			continue
+		}
+
+		age := f.Vulnerability.Age(now)
+		if minAge != 0 && age < minAge {
+			continue
+		}
+		if maxAge != 0 && age > maxAge {
+			continue
+		}
+
+		result = append(result, f)
+	}
+
+	return result
+}
@@ -0,0 +1,73 @@
+package scan
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublishedDatesAnnotate(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/vulns/GHSA-xxxx-yyyy-zzzz", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"id": "GHSA-xxxx-yyyy-zzzz", "published": "2023-03-01T00:00:00Z"}`))
+	})
+	mux.HandleFunc("/vulns/CVE-2023-0001", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"id": "CVE-2023-0001", "published": "2023-02-01T00:00:00Z"}`))
+	})
+	mux.HandleFunc("/vulns/", http.NotFound)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	d := NewPublishedDates(server.Client())
+	d.baseURL = server.URL
+
+	findings := []*Finding{
+		{Vulnerability: Vulnerability{ID: "GHSA-xxxx-yyyy-zzzz", Aliases: []string{"CVE-2023-0001"}}},
+		{Vulnerability: Vulnerability{ID: "CVE-2099-0001"}},
+	}
+
+	require.NoError(t, d.Annotate(context.Background(), findings))
+
+	require.NotNil(t, findings[0].Vulnerability.Published)
+	assert.Equal(t, time.Date(2023, time.February, 1, 0, 0, 0, 0, time.UTC), findings[0].Vulnerability.Published.UTC())
+	assert.Nil(t, findings[1].Vulnerability.Published)
+}
+
+func TestFilterByAge(t *testing.T) {
+	now := time.Date(2023, time.June, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	finding := func(id string, age time.Duration) *Finding {
+		v := Vulnerability{ID: id}
+		if age != 0 {
+			published := now.Add(-age)
+			v.Published = &published
+		}
+		return &Finding{Vulnerability: v}
+	}
+
+	findings := []*Finding{
+		finding("fresh", 3*day),
+		finding("month", 30*day),
+		finding("old", 400*day),
+		finding("unknown", 0),
+	}
+
+	ids := func(fs []*Finding) []string {
+		var result []string
+		for _, f := range fs {
+			result = append(result, f.Vulnerability.ID)
+		}
+		return result
+	}
+
+	assert.Equal(t, []string{"fresh", "month", "old", "unknown"}, ids(FilterByAge(findings, now, 0, 0)))
+	assert.Equal(t, []string{"month", "old", "unknown"}, ids(FilterByAge(findings, now, 7*day, 0)))
+	assert.Equal(t, []string{"fresh", "month", "unknown"}, ids(FilterByAge(findings, now, 0, 365*day)))
+	assert.Equal(t, []string{"month", "unknown"}, ids(FilterByAge(findings, now, 7*day, 365*day)))
+}
@@ -0,0 +1,152 @@
+package scan
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/samber/lo"
+	"golang.org/x/exp/slices"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultAnnotationsFile is the project-local file that holds finding
+// annotations, unless another is specified.
+const DefaultAnnotationsFile = ".wolfictl-annotations.yaml"
+
+// Annotation kinds describe how a finding has been triaged locally.
+const (
+	// AnnotationAcceptedRisk means the risk posed by the finding has been
+	// accepted.
+	AnnotationAcceptedRisk = "accepted-risk"
+
+	// AnnotationMitigatedByConfig means the vulnerability can't be exploited
+	// given how the software is configured.
+	AnnotationMitigatedByConfig = "mitigated-by-config"
+
+	// AnnotationNote is a note about the finding, without any triage decision.
+	AnnotationNote = "note"
+)
+
+// AnnotationKinds lists the valid annotation kinds.
+var AnnotationKinds = []string{AnnotationAcceptedRisk, AnnotationMitigatedByConfig, AnnotationNote}
+
+// Annotation is a local triage note about findings of a vulnerability, kept
+// by users of packages who don't maintain the distro's advisory data.
+type Annotation struct {
+	// Vulnerability is the ID of the annotated vulnerability. Findings also match
+	// if one of their vulnerability's aliases is this ID.
+	Vulnerability string `yaml:"vulnerability" json:"vulnerability"`
+
+	// Package, if set, limits the annotation to findings for the package of this
+	// name. Otherwise, the annotation applies to findings in any package.
+	Package string `yaml:"package,omitempty" json:"package,omitempty"`
+
+	// Kind is one of AnnotationKinds.
+	Kind string `yaml:"kind" json:"kind"`
+
+	Note string `yaml:"note,omitempty" json:"note,omitempty"`
+
+	// Ticket is a link to where the finding is tracked, e.g. an issue.
+	Ticket string `yaml:"ticket,omitempty" json:"ticket,omitempty"`
+
+	Timestamp time.Time `yaml:"timestamp" json:"timestamp"`
+}
+
+// Validate returns an error if the annotation is invalid.
+func (a Annotation) Validate() error {
+	if a.Vulnerability == "" {
+		return errors.New("vulnerability cannot be empty")
+	}
+
+	if !slices.Contains(AnnotationKinds, a.Kind) {
+		return fmt.Errorf("invalid kind %q (must be one of %s)", a.Kind, strings.Join(AnnotationKinds, ", "))
+	}
+
+	return nil
+}
+
+// matches reports whether the annotation applies to the given finding.
+func (a Annotation) matches(f *Finding) bool {
+	if a.Package != "" && a.Package != f.Package.Name {
+		return false
+	}
+
+	return a.Vulnerability == f.Vulnerability.ID || slices.Contains(f.Vulnerability.Aliases, a.Vulnerability)
+}
+
+// Annotations is the content of an annotations file.
+type Annotations struct {
+	Annotations []Annotation `yaml:"annotations"`
+}
+
+// ReadAnnotations reads the annotations file at the given path. A missing file
+// has no annotations.
+func ReadAnnotations(path string) (*Annotations, error) {
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &Annotations{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read annotations: %w", err)
+	}
+
+	a := &Annotations{}
+	if err := yaml.Unmarshal(b, a); err != nil {
+		return nil, fmt.Errorf("unable to parse annotations %q: %w", path, err)
+	}
+
+	for i, ann := range a.Annotations {
+		if err := ann.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid annotation %d in %q: %w", i+1, path, err)
+		}
+	}
+
+	return a, nil
+}
+
+// Add adds the given annotation, replacing any existing annotation for the same
+// vulnerability and package.
+func (a *Annotations) Add(ann Annotation) error {
+	if err := ann.Validate(); err != nil {
+		return err
+	}
+
+	a.Annotations = lo.Reject(a.Annotations, func(existing Annotation, _ int) bool {
+		return existing.Vulnerability == ann.Vulnerability && existing.Package == ann.Package
+	})
+	a.Annotations = append(a.Annotations, ann)
+
+	return nil
+}
+
+// Write writes the annotations to the file at the given path.
+func (a *Annotations) Write(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("unable to write annotations: %w", err)
+	}
+	defer f.Close()
+
+	enc := yaml.NewEncoder(f)
+	enc.SetIndent(2)
+	if err := enc.Encode(a); err != nil {
+		return fmt.Errorf("unable to write annotations: %w", err)
+	}
+
+	return enc.Close()
+}
+
+// Annotate sets the Annotations of each of the given findings to the
+// annotations that apply to it.
+func (a *Annotations) Annotate(findings []*Finding) {
+	for _, f := range findings {
+		for _, ann := range a.Annotations {
+			if ann.matches(f) {
+				f.Annotations = append(f.Annotations, ann)
+			}
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package scan
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnnotations(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DefaultAnnotationsFile)
+
+	a, err := ReadAnnotations(path)
+	require.NoError(t, err)
+	assert.Empty(t, a.Annotations)
+
+	ts := time.Date(2023, 8, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, a.Add(Annotation{Vulnerability: "CVE-2023-1234", Kind: AnnotationNote, Timestamp: ts}))
+	require.NoError(t, a.Add(Annotation{Vulnerability: "CVE-2023-1234", Kind: AnnotationAcceptedRisk, Ticket: "https://example.com/JIRA-1", Timestamp: ts}))
+	require.NoError(t, a.Add(Annotation{Vulnerability: "GHSA-xxxx", Package: "golang.org/x/net", Kind: AnnotationMitigatedByConfig, Timestamp: ts}))
+	assert.Error(t, a.Add(Annotation{Vulnerability: "CVE-2023-1234", Kind: "ignore"}))
+
+	require.NoError(t, a.Write(path))
+	a, err = ReadAnnotations(path)
+	require.NoError(t, err)
+	require.Len(t, a.Annotations, 2)
+	assert.Equal(t, AnnotationAcceptedRisk, a.Annotations[0].Kind)
+
+	findings := []*Finding{
+		{Package: Package{Name: "openssl"}, Vulnerability: Vulnerability{ID: "GHSA-yyyy", Aliases: []string{"CVE-2023-1234"}}},
+		{Package: Package{Name: "golang.org/x/net"}, Vulnerability: Vulnerability{ID: "GHSA-xxxx"}},
+		{Package: Package{Name: "golang.org/x/text"}, Vulnerability: Vulnerability{ID: "GHSA-xxxx"}},
+	}
+	a.Annotate(findings)
+
+	assert.Len(t, findings[0].Annotations, 1)
+	assert.Len(t, findings[1].Annotations, 1)
+	assert.Empty(t, findings[2].Annotations)
+}
@@ -5,7 +5,9 @@ import (
 	"io"
 	"os"
 	"path"
+	"runtime"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/adrg/xdg"
@@ -19,9 +21,12 @@ import (
 	"github.com/anchore/syft/syft"
 	"github.com/anchore/syft/syft/file"
 	"github.com/anchore/syft/syft/pkg/cataloger"
+	"github.com/anchore/syft/syft/sbom"
 	"github.com/anchore/syft/syft/source"
 	"github.com/samber/lo"
+	advisoryconfigs "github.com/wolfi-dev/wolfictl/pkg/configs/advisory"
 	"github.com/wolfi-dev/wolfictl/pkg/tar"
+	"sigs.k8s.io/release-utils/version"
 )
 
 const grypeDBListingURL = "https://toolbox-data.anchore.io/grype/databases/listing.json"
@@ -36,7 +41,9 @@ var grypeDBConfig = db.Config{
 	MaxAllowedBuiltAge:  24 * time.Hour,
 }
 
-var syftCatalogersEnabled = []string{
+// DefaultCatalogers are the Syft catalogers used to find packages within an APK
+// file, unless overridden via Options.
+var DefaultCatalogers = []string{
 	"apkdb",
 	"binary",
 	"dotnet-deps",
@@ -51,26 +58,152 @@ var syftCatalogersEnabled = []string{
 	"ruby-gemspec",
 }
 
+// Result is the outcome of scanning an APK file: the vulnerability findings,
+// along with the SBOM that was generated in order to find them.
+type Result struct {
+	Findings []*Finding
+	SBOM     *sbom.SBOM
+
+	// APK describes the scanned APK file itself, as read from its .PKGINFO. It's
+	// nil when the scan input wasn't an APK file.
+	APK *PackageInfo
+
+	// Secrets are the potential secrets found in the APK's files, if requested
+	// via Options.
+	Secrets []SecretFinding
+}
+
+// Options configures how an APK file is scanned.
+type Options struct {
+	// Catalogers are the Syft catalogers to run. If empty, DefaultCatalogers is
+	// used.
+	Catalogers []string
+
+	// DisabledCatalogers are catalogers to skip. A cataloger is skipped if its name
+	// contains any of these values, so e.g. "javascript" disables
+	// "javascript-package".
+	DisabledCatalogers []string
+
+	// Secrets enables searching the APK's files for secrets (see SecretRules).
+	Secrets bool
+
+	// Distro, if set, overrides the distro detected from the scanned files when
+	// matching packages against distro security data (see ParseDistro).
+	Distro *Distro
+}
+
+// catalogers returns the names of the catalogers to run, as determined by the
+// options.
+func (opts Options) catalogers() []string {
+	catalogers := opts.Catalogers
+	if len(catalogers) == 0 {
+		catalogers = DefaultCatalogers
+	}
+
+	return lo.Reject(catalogers, func(c string, _ int) bool {
+		return lo.SomeBy(opts.DisabledCatalogers, func(disabled string) bool {
+			return strings.Contains(c, disabled)
+		})
+	})
+}
+
 // APK scans an APK file for vulnerabilities.
-func APK(f io.Reader) ([]*Finding, error) {
-	// Create a temp directory to house the unpacked APK file
-	tempDir, err := os.MkdirTemp("", "wolfictl-scan-*")
+func APK(f io.Reader, opts Options) (*Result, error) {
+	tempDir, err := unpackAPK(f)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create temp directory: %w", err)
+		return nil, err
 	}
 	defer os.RemoveAll(tempDir)
 
-	// Unpack apk to temp directory
-	err = tar.Untar(f, tempDir)
+	// TODO: use a managed cache of APK SBOMs (Syft format)
+
+	s, pkgInfo, err := catalogAPK(tempDir, opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to unpack apk file: %w", err)
+		return nil, err
 	}
 
-	// TODO: use a managed cache of APK SBOMs (Syft format)
+	findings, err := findingsForSBOM(s, opts.Distro)
+	if err != nil {
+		return nil, err
+	}
+
+	if release := opts.Distro.release(s.Artifacts.LinuxDistribution); release != nil {
+		pkgInfo.Distro = release.ID
+	}
+
+	var secrets []SecretFinding
+	if opts.Secrets {
+		secrets, err = FindSecrets(os.DirFS(tempDir))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &Result{
+		Findings: findings,
+		SBOM:     s,
+		APK:      pkgInfo,
+		Secrets:  secrets,
+	}, nil
+}
+
+// APKSBOM generates an SBOM of an APK file, cataloging its contents just as APK
+// does, but without matching the packages against the vulnerability database.
+// It also returns the APK's package info. Of the given options, only Catalogers
+// and DisabledCatalogers apply.
+func APKSBOM(f io.Reader, opts Options) (*sbom.SBOM, *PackageInfo, error) {
+	tempDir, err := unpackAPK(f)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer os.RemoveAll(tempDir)
 
+	return catalogAPK(tempDir, opts)
+}
+
+// unpackAPK unpacks an APK file into a new temp directory, whose path is
+// returned. The caller is responsible for removing it.
+func unpackAPK(f io.Reader) (string, error) {
+	tempDir, err := os.MkdirTemp("", "wolfictl-scan-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp directory: %w", err)
+	}
+
+	if err := tar.Untar(f, tempDir); err != nil {
+		os.RemoveAll(tempDir)
+		return "", fmt.Errorf("failed to unpack apk file: %w", err)
+	}
+
+	return tempDir, nil
+}
+
+// catalogAPK generates an SBOM for the APK unpacked into the given directory,
+// using the catalogers selected by the options, and reads its .PKGINFO.
+func catalogAPK(dir string, opts Options) (*sbom.SBOM, *PackageInfo, error) {
+	pkgInfo, err := readPackageInfo(path.Join(dir, ".PKGINFO"))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	catalogers := opts.catalogers()
+	if len(catalogers) == 0 {
+		return nil, nil, fmt.Errorf("no catalogers enabled")
+	}
+
+	s, err := catalogDirectory(dir, catalogers)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return s, pkgInfo, nil
+}
+
+// catalogDirectory generates an SBOM for the contents of the given directory,
+// using the given catalogers.
+func catalogDirectory(dir string, catalogers []string) (*sbom.SBOM, error) {
 	src, err := source.NewFromDirectory(
 		source.DirectoryConfig{
-			Path: tempDir,
+			Path: dir,
 		},
 	)
 	if err != nil {
@@ -78,27 +211,52 @@ func APK(f io.Reader) ([]*Finding, error) {
 	}
 
 	cfg := cataloger.DefaultConfig()
-	cfg.Catalogers = syftCatalogersEnabled
+	cfg.Catalogers = catalogers
+	cfg.Parallelism = runtime.NumCPU()
 
-	packageCollection, _, distro, err := syft.CatalogPackages(src, cfg)
+	packageCollection, relationships, distro, err := syft.CatalogPackages(src, cfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to catalog packages: %w", err)
 	}
 
-	syftPkgs := packageCollection.Sorted()
+	return &sbom.SBOM{
+		Artifacts: sbom.Artifacts{
+			Packages:          packageCollection,
+			LinuxDistribution: distro,
+		},
+		Relationships: relationships,
+		Source:        src.Describe(),
+		Descriptor: sbom.Descriptor{
+			Name:    "wolfictl",
+			Version: version.GetVersionInfo().GitVersion,
+		},
+	}, nil
+}
+
+// loadDBMu serializes loading (and possibly updating) the vulnerability
+// database.
+var loadDBMu sync.Mutex
 
+// findingsForSBOM matches the packages described by the given SBOM against the
+// vulnerability database, using the given distro selection, if any.
+func findingsForSBOM(s *sbom.SBOM, distro *Distro) ([]*Finding, error) {
+	syftPkgs := s.Artifacts.Packages.Sorted()
+
+	// Concurrent scans mustn't update the database at the same time.
+	loadDBMu.Lock()
 	datastore, _, dbCloser, err := grype.LoadVulnerabilityDB(grypeDBConfig, true)
+	loadDBMu.Unlock()
 	if err != nil {
 		return nil, fmt.Errorf("failed to load vulnerability database: %w", err)
 	}
 	defer dbCloser.Close()
 
 	matcher := grype.DefaultVulnerabilityMatcher(*datastore)
-	sourceDescription := src.Describe()
+	sourceDescription := s.Source
 	grypePkgs := grypePkg.FromPackages(syftPkgs, grypePkg.SynthesisConfig{GenerateMissingCPEs: false})
 	matchesCollection, _, err := matcher.FindMatches(grypePkgs, grypePkg.Context{
 		Source: &sourceDescription,
-		Distro: distro,
+		Distro: distro.release(s.Artifacts.LinuxDistribution),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to find vulnerability matches: %w", err)
@@ -106,7 +264,7 @@ func APK(f io.Reader) ([]*Finding, error) {
 
 	matches := matchesCollection.Sorted()
 
-	var findings []*Finding
+	findings := make([]*Finding, 0, len(matches))
 	for i := range matches {
 		m := matches[i]
 
@@ -114,6 +272,7 @@ func APK(f io.Reader) ([]*Finding, error) {
 		if err != nil {
 			return nil, fmt.Errorf("failed to map match to finding: %w", err)
 		}
+		addAPKOrigin(&finding.Package, s)
 		findings = append(findings, finding)
 	}
 
@@ -122,23 +281,54 @@ func APK(f io.Reader) ([]*Finding, error) {
 
 // Finding represents a vulnerability finding for a single package.
 type Finding struct {
-	Package       Package
-	Vulnerability Vulnerability
+	Package       Package       `json:"package"`
+	Vulnerability Vulnerability `json:"vulnerability"`
+
+	// Advisory is the latest advisory event recorded for this finding's
+	// vulnerability in the scanned package, if any. It's only set when the
+	// caller has looked it up in advisory data.
+	Advisory *advisoryconfigs.Entry `json:"advisory,omitempty"`
+
+	// Annotations are the local annotations that apply to this finding. They're
+	// only set if looked up via Annotations.
+	Annotations []Annotation `json:"annotations,omitempty"`
 }
 
 type Package struct {
-	ID       string
-	Name     string
-	Version  string
-	Type     string
-	Location string
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Version  string `json:"version"`
+	Type     string `json:"type"`
+	Location string `json:"location"`
+
+	// PURL is the package URL of the package that the vulnerability was matched
+	// against, if known.
+	PURL string `json:"purl,omitempty"`
+
+	// Origin and Commit are the origin package and the commit of its source
+	// repository that an APK package was built from. They're only set for APK
+	// packages, when recorded in the package's metadata.
+	Origin string `json:"origin,omitempty"`
+	Commit string `json:"commit,omitempty"`
+
+	// Source is where the melange config that builds an APK package can be
+	// viewed. It's only set if looked up via a SourceResolver.
+	Source *SourceLocation `json:"source,omitempty"`
 }
 
 type Vulnerability struct {
-	ID           string
-	Severity     string
-	Aliases      []string
-	FixedVersion string
+	ID           string   `json:"id"`
+	Severity     string   `json:"severity"`
+	Aliases      []string `json:"aliases,omitempty"`
+	FixedVersion string   `json:"fixedVersion,omitempty"`
+
+	// UpstreamSeverity is the severity reported by the scanner, when Severity has
+	// been replaced by the distro's own assessment from advisory data.
+	UpstreamSeverity string `json:"upstreamSeverity,omitempty"`
+
+	// Published is when the vulnerability was first published. It's only set if
+	// looked up via PublishedDates, and the date is known.
+	Published *time.Time `json:"published,omitempty"`
 }
 
 func mapMatchToFinding(m match.Match, datastore *store.Store) (*Finding, error) {
@@ -171,6 +361,7 @@ func mapMatchToFinding(m match.Match, datastore *store.Store) (*Finding, error)
 			Version:  m.Package.Version,
 			Type:     string(m.Package.Type),
 			Location: strings.Join(locations, ", "),
+			PURL:     m.Package.PURL,
 		},
 		Vulnerability: Vulnerability{
 			ID:           m.Vulnerability.ID,
@@ -0,0 +1,42 @@
+package scan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOptionsCatalogers(t *testing.T) {
+	cases := []struct {
+		name     string
+		opts     Options
+		expected []string
+	}{
+		{
+			name:     "defaults",
+			opts:     Options{},
+			expected: DefaultCatalogers,
+		},
+		{
+			name: "explicit",
+			opts: Options{
+				Catalogers: []string{"apkdb", "go-module-binary"},
+			},
+			expected: []string{"apkdb", "go-module-binary"},
+		},
+		{
+			name: "disabled by partial name",
+			opts: Options{
+				Catalogers:         []string{"apkdb", "javascript-package", "python-package"},
+				DisabledCatalogers: []string{"javascript", "python"},
+			},
+			expected: []string{"apkdb"},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.opts.catalogers())
+		})
+	}
+}
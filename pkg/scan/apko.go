@@ -0,0 +1,105 @@
+package scan
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/wolfi-dev/wolfictl/pkg/apk"
+	"github.com/wolfi-dev/wolfictl/pkg/index"
+	"gitlab.alpinelinux.org/alpine/go/repository"
+	"gopkg.in/yaml.v3"
+)
+
+// ApkoConfig is the subset of an apko image configuration needed to determine
+// which packages an image built from it would contain.
+type ApkoConfig struct {
+	Contents struct {
+		Repositories []string `yaml:"repositories"`
+		Packages     []string `yaml:"packages"`
+	} `yaml:"contents"`
+	Archs []string `yaml:"archs"`
+}
+
+// DecodeApkoConfig reads an apko image configuration.
+func DecodeApkoConfig(r io.Reader) (*ApkoConfig, error) {
+	cfg := &ApkoConfig{}
+	if err := yaml.NewDecoder(r).Decode(cfg); err != nil {
+		return nil, fmt.Errorf("failed to decode apko config: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// ResolvedAPK is a package, as listed in a repository's APKINDEX, that an apko
+// configuration resolves to.
+type ResolvedAPK struct {
+	Package *repository.Package
+
+	// URL is where the APK file can be downloaded.
+	URL string
+}
+
+// ResolveApkoConfig determines the set of packages (including transitive
+// dependencies) that an image built from the given apko configuration would
+// contain for the given arch, without building the image. The packages are
+// resolved against the repositories declared in the configuration, and the
+// latest version of each package is chosen.
+func ResolveApkoConfig(cfg *ApkoConfig, arch string) ([]ResolvedAPK, error) {
+	r := apk.NewResolver()
+
+	for _, repo := range cfg.Contents.Repositories {
+		// Repositories can be tagged, e.g. "@local /path/to/repo".
+		if strings.HasPrefix(repo, "@") {
+			if _, untagged, ok := strings.Cut(repo, " "); ok {
+				repo = strings.TrimSpace(untagged)
+			}
+		}
+
+		idx, err := index.Index(arch, repo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load APKINDEX for repository %q: %w", repo, err)
+		}
+
+		r.AddIndex(repo, idx)
+	}
+
+	resolved := make(map[string]ResolvedAPK)
+	queue := append([]string{}, cfg.Contents.Packages...)
+
+	for len(queue) > 0 {
+		dep := queue[0]
+		queue = queue[1:]
+
+		if strings.HasPrefix(dep, "!") {
+			// conflict markers don't add anything to the image
+			continue
+		}
+
+		pkg, ok := r.Resolve(dep)
+		if !ok {
+			return nil, fmt.Errorf("unable to resolve package %q", dep)
+		}
+
+		if _, seen := resolved[pkg.Name]; seen {
+			continue
+		}
+
+		resolved[pkg.Name] = ResolvedAPK{
+			Package: pkg.Package,
+			URL:     fmt.Sprintf("%s/%s/%s-%s.apk", strings.TrimSuffix(pkg.Repository, "/"), arch, pkg.Name, pkg.Version),
+		}
+		queue = append(queue, pkg.Dependencies...)
+	}
+
+	apks := make([]ResolvedAPK, 0, len(resolved))
+	for _, resolvedAPK := range resolved {
+		apks = append(apks, resolvedAPK)
+	}
+	sort.Slice(apks, func(i, j int) bool {
+		return apks[i].Package.Name < apks[j].Package.Name
+	})
+
+	return apks, nil
+}
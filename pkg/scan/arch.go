@@ -0,0 +1,49 @@
+package scan
+
+import (
+	"sort"
+
+	"golang.org/x/exp/slices"
+)
+
+// ArchSpecificFindings compares the findings from scanning the same packages
+// built for different architectures. For each architecture, it returns the
+// findings that weren't also found for every other architecture, such as
+// findings in binaries that are only bundled for that architecture.
+// Architectures with no such findings are omitted.
+func ArchSpecificFindings(findingsByArch map[string][]*Finding) map[string][]CanaryFinding {
+	archs := make([]string, 0, len(findingsByArch))
+	for arch := range findingsByArch {
+		archs = append(archs, arch)
+	}
+	sort.Strings(archs)
+
+	result := make(map[string][]CanaryFinding)
+	for _, arch := range archs {
+		var specific []CanaryFinding
+		for _, other := range archs {
+			if other == arch {
+				continue
+			}
+
+			for _, f := range diffFindings(findingsByArch[arch], findingsByArch[other]) {
+				if !slices.Contains(specific, f) {
+					specific = append(specific, f)
+				}
+			}
+		}
+
+		if len(specific) > 0 {
+			sort.SliceStable(specific, func(i, j int) bool {
+				si, sj := slices.Index(Severities, specific[i].Severity), slices.Index(Severities, specific[j].Severity)
+				if si != sj {
+					return si < sj
+				}
+				return specific[i].Vulnerability < specific[j].Vulnerability
+			})
+			result[arch] = specific
+		}
+	}
+
+	return result
+}
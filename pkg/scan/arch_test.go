@@ -0,0 +1,30 @@
+package scan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArchSpecificFindings(t *testing.T) {
+	finding := func(id, pkg string) *Finding {
+		return &Finding{
+			Package:       Package{Name: pkg, Version: "1.0.0", Type: "go-module"},
+			Vulnerability: Vulnerability{ID: id, Severity: "High"},
+		}
+	}
+
+	specific := ArchSpecificFindings(map[string][]*Finding{
+		"x86_64":  {finding("GHSA-1", "golang.org/x/net"), finding("GHSA-2", "golang.org/x/text")},
+		"aarch64": {finding("GHSA-1", "golang.org/x/net")},
+	})
+
+	assert.NotContains(t, specific, "aarch64")
+	if assert.Len(t, specific["x86_64"], 1) {
+		assert.Equal(t, "GHSA-2", specific["x86_64"][0].Vulnerability)
+	}
+
+	assert.Empty(t, ArchSpecificFindings(map[string][]*Finding{
+		"x86_64": {finding("GHSA-1", "golang.org/x/net")},
+	}))
+}
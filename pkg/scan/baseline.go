@@ -0,0 +1,64 @@
+package scan
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// Baseline is a set of previously reported findings, such as a backlog that's
+// been accepted for now, so that scans can report only what's new.
+type Baseline struct {
+	keys map[string]struct{}
+}
+
+// ReadBaseline reads a baseline from a saved scan result: the JSON output of
+// 'wolfictl scan --output json', i.e. a stream of objects, each with a
+// "findings" array.
+func ReadBaseline(r io.Reader) (*Baseline, error) {
+	results, err := ReadJSONResults(r)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse baseline: %w", err)
+	}
+
+	b := &Baseline{keys: make(map[string]struct{})}
+	for _, result := range results {
+		for _, f := range result.Findings {
+			b.keys[versionlessFindingKey(f)] = struct{}{}
+		}
+	}
+
+	return b, nil
+}
+
+// ReadBaselineFile reads a baseline (see ReadBaseline) from the file at the
+// given path.
+func ReadBaselineFile(path string) (*Baseline, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open baseline: %w", err)
+	}
+	defer f.Close()
+
+	return ReadBaseline(f)
+}
+
+// Filter returns the findings that aren't in the baseline, and the number that
+// were. As with canary comparisons, findings are matched by vulnerability and by
+// the affected package's name and type, so a finding stays in the baseline when
+// only the package's version changes. A nil Baseline keeps all findings.
+func (b *Baseline) Filter(findings []*Finding) (newFindings []*Finding, known int) {
+	if b == nil {
+		return findings, 0
+	}
+
+	for _, f := range findings {
+		if _, ok := b.keys[versionlessFindingKey(f)]; ok {
+			known++
+			continue
+		}
+		newFindings = append(newFindings, f)
+	}
+
+	return newFindings, known
+}
@@ -0,0 +1,34 @@
+package scan
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBaseline_Filter(t *testing.T) {
+	saved := `{"target":"crane-0.15.2-r0.apk","findings":[{"package":{"name":"golang.org/x/net","version":"0.7.0","type":"go-module"},"vulnerability":{"id":"GHSA-1"}}]}
+{"target":"crane-0.15.2-r0.apk","findings":[]}
+`
+	b, err := ReadBaseline(strings.NewReader(saved))
+	require.NoError(t, err)
+
+	findings := []*Finding{
+		// Same finding, newer package version: still in the baseline.
+		{Package: Package{Name: "golang.org/x/net", Version: "0.8.0", Type: "go-module"}, Vulnerability: Vulnerability{ID: "GHSA-1"}},
+		{Package: Package{Name: "golang.org/x/net", Version: "0.8.0", Type: "go-module"}, Vulnerability: Vulnerability{ID: "GHSA-2"}},
+	}
+
+	newFindings, known := b.Filter(findings)
+	assert.Equal(t, 1, known)
+	if assert.Len(t, newFindings, 1) {
+		assert.Equal(t, "GHSA-2", newFindings[0].Vulnerability.ID)
+	}
+
+	var nilBaseline *Baseline
+	newFindings, known = nilBaseline.Filter(findings)
+	assert.Equal(t, 0, known)
+	assert.Len(t, newFindings, 2)
+}
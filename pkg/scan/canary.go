@@ -0,0 +1,210 @@
+package scan
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"golang.org/x/exp/slices"
+)
+
+// CanaryVerdict is the outcome of comparing the findings for a candidate
+// (built, but not yet published) package against the findings for the version
+// of the package that's currently published.
+type CanaryVerdict struct {
+	Package          string `json:"package"`
+	Version          string `json:"version"`
+	PublishedVersion string `json:"publishedVersion,omitempty"`
+
+	// FailOn is the minimum severity of a new finding that causes the candidate to
+	// fail the gate.
+	FailOn string `json:"failOn"`
+
+	// Passed is true if the candidate introduces no new findings with a severity of
+	// at least FailOn.
+	Passed bool `json:"passed"`
+
+	// NewFindings are the candidate's findings that the published version doesn't
+	// have.
+	NewFindings []CanaryFinding `json:"newFindings"`
+
+	// ResolvedFindings are the published version's findings that the candidate
+	// doesn't have.
+	ResolvedFindings []CanaryFinding `json:"resolvedFindings"`
+
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// CanaryFinding is a finding, as recorded in a CanaryVerdict or an ImageDiff.
+type CanaryFinding struct {
+	Vulnerability string `json:"vulnerability"`
+	Severity      string `json:"severity"`
+	Package       string `json:"package"`
+	Version       string `json:"version"`
+	Type          string `json:"type"`
+}
+
+// NewCanaryVerdict compares the candidate's findings against the published
+// version's findings and determines whether the candidate passes the gate: it
+// fails if any new finding is at least as severe as failOn (e.g. "Critical").
+// The candidate's published counterpart may be nil if the package has never
+// been published, in which case every finding is new.
+func NewCanaryVerdict(candidate *PackageInfo, candidateFindings []*Finding, published *PackageInfo, publishedFindings []*Finding, failOn string) (*CanaryVerdict, error) {
+	threshold := slices.Index(Severities, failOn)
+	if threshold < 0 {
+		return nil, fmt.Errorf("unknown severity %q (must be one of %v)", failOn, Severities)
+	}
+
+	v := &CanaryVerdict{
+		Package:          candidate.Name,
+		Version:          candidate.Version,
+		FailOn:           failOn,
+		Passed:           true,
+		NewFindings:      diffFindings(candidateFindings, publishedFindings),
+		ResolvedFindings: diffFindings(publishedFindings, candidateFindings),
+		Timestamp:        time.Now().UTC(),
+	}
+	if published != nil {
+		v.PublishedVersion = published.Version
+	}
+
+	for _, f := range v.NewFindings {
+		if i := slices.Index(Severities, f.Severity); i >= 0 && i <= threshold {
+			v.Passed = false
+			break
+		}
+	}
+
+	return v, nil
+}
+
+// versionlessFindingKey identifies a finding by its vulnerability and the
+// affected package's name and type, but not its version.
+func versionlessFindingKey(f *Finding) string {
+	return fmt.Sprintf("%s|%s|%s", f.Vulnerability.ID, f.Package.Name, f.Package.Type)
+}
+
+// diffFindings returns the findings in a that aren't in b. Findings are
+// matched by vulnerability and by the affected package's name and type, but not
+// its version, since the version is expected to change between builds.
+func diffFindings(a, b []*Finding) []CanaryFinding {
+	key := versionlessFindingKey
+
+	inB := make(map[string]struct{})
+	for _, f := range b {
+		inB[key(f)] = struct{}{}
+	}
+
+	diff := []CanaryFinding{}
+	seen := make(map[string]struct{})
+	for _, f := range a {
+		k := key(f)
+		if _, ok := inB[k]; ok {
+			continue
+		}
+		if _, ok := seen[k]; ok {
+			continue
+		}
+		seen[k] = struct{}{}
+
+		diff = append(diff, CanaryFinding{
+			Vulnerability: f.Vulnerability.ID,
+			Severity:      f.Vulnerability.Severity,
+			Package:       f.Package.Name,
+			Version:       f.Package.Version,
+			Type:          f.Package.Type,
+		})
+	}
+
+	sort.SliceStable(diff, func(i, j int) bool {
+		si, sj := slices.Index(Severities, diff[i].Severity), slices.Index(Severities, diff[j].Severity)
+		if si != sj {
+			return si < sj
+		}
+		return diff[i].Vulnerability < diff[j].Vulnerability
+	})
+
+	return diff
+}
+
+// SignVerdicts encodes the given verdicts as JSON and signs the result with the
+// given PEM-encoded RSA private key (e.g. a melange signing key). It returns
+// the encoded verdicts and the signature.
+func SignVerdicts(verdicts []*CanaryVerdict, keyPEM []byte) (payload, signature []byte, err error) {
+	key, err := parseRSAPrivateKey(keyPEM)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	payload, err = json.MarshalIndent(verdicts, "", "  ")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode verdicts: %w", err)
+	}
+
+	digest := sha256.Sum256(payload)
+	signature, err = rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to sign verdicts: %w", err)
+	}
+
+	return payload, signature, nil
+}
+
+// VerifyVerdicts checks the signature of the encoded verdicts using the given
+// PEM-encoded RSA public key, and returns the decoded verdicts.
+func VerifyVerdicts(payload, signature, pubKeyPEM []byte) ([]*CanaryVerdict, error) {
+	block, _ := pem.Decode(pubKeyPEM)
+	if block == nil {
+		return nil, errors.New("failed to decode public key: no PEM data found")
+	}
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key: %w", err)
+	}
+	rsaPub, ok := pub.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("unsupported public key type %T", pub)
+	}
+
+	digest := sha256.Sum256(payload)
+	if err := rsa.VerifyPKCS1v15(rsaPub, crypto.SHA256, digest[:], signature); err != nil {
+		return nil, fmt.Errorf("invalid verdict signature: %w", err)
+	}
+
+	var verdicts []*CanaryVerdict
+	if err := json.Unmarshal(payload, &verdicts); err != nil {
+		return nil, fmt.Errorf("failed to decode verdicts: %w", err)
+	}
+
+	return verdicts, nil
+}
+
+func parseRSAPrivateKey(keyPEM []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, errors.New("failed to decode signing key: no PEM data found")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse signing key: %w", err)
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("unsupported signing key type %T (must be RSA)", key)
+	}
+
+	return rsaKey, nil
+}
@@ -0,0 +1,131 @@
+package scan
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCanaryVerdict(t *testing.T) {
+	finding := func(id, severity, pkg, version string) *Finding {
+		return &Finding{
+			Package:       Package{Name: pkg, Version: version, Type: "go-module"},
+			Vulnerability: Vulnerability{ID: id, Severity: severity},
+		}
+	}
+
+	candidate := &PackageInfo{Name: "foo", Version: "1.2.0-r0"}
+	published := &PackageInfo{Name: "foo", Version: "1.1.0-r0"}
+
+	publishedFindings := []*Finding{
+		finding("GHSA-1", "High", "golang.org/x/net", "0.7.0"),
+		finding("GHSA-2", "Critical", "golang.org/x/text", "0.3.0"),
+	}
+
+	cases := []struct {
+		name              string
+		candidateFindings []*Finding
+		failOn            string
+		expectedPassed    bool
+		expectedNew       []string
+		expectedResolved  []string
+	}{
+		{
+			name: "no change, other than dependency versions",
+			candidateFindings: []*Finding{
+				finding("GHSA-1", "High", "golang.org/x/net", "0.8.0"),
+				finding("GHSA-2", "Critical", "golang.org/x/text", "0.3.1"),
+			},
+			failOn:           "Critical",
+			expectedPassed:   true,
+			expectedNew:      nil,
+			expectedResolved: nil,
+		},
+		{
+			name: "new high finding",
+			candidateFindings: []*Finding{
+				finding("GHSA-3", "High", "golang.org/x/crypto", "0.1.0"),
+			},
+			failOn:           "Critical",
+			expectedPassed:   true,
+			expectedNew:      []string{"GHSA-3"},
+			expectedResolved: []string{"GHSA-2", "GHSA-1"},
+		},
+		{
+			name: "new high finding, failing on high",
+			candidateFindings: []*Finding{
+				finding("GHSA-3", "High", "golang.org/x/crypto", "0.1.0"),
+			},
+			failOn:           "High",
+			expectedPassed:   false,
+			expectedNew:      []string{"GHSA-3"},
+			expectedResolved: []string{"GHSA-2", "GHSA-1"},
+		},
+		{
+			name: "new critical finding",
+			candidateFindings: []*Finding{
+				finding("GHSA-1", "High", "golang.org/x/net", "0.8.0"),
+				finding("GHSA-4", "Medium", "golang.org/x/sys", "0.1.0"),
+				finding("GHSA-5", "Critical", "golang.org/x/sys", "0.1.0"),
+			},
+			failOn:           "Critical",
+			expectedPassed:   false,
+			expectedNew:      []string{"GHSA-5", "GHSA-4"},
+			expectedResolved: []string{"GHSA-2"},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := NewCanaryVerdict(candidate, tt.candidateFindings, published, publishedFindings, tt.failOn)
+			require.NoError(t, err)
+
+			ids := func(fs []CanaryFinding) []string {
+				var result []string
+				for _, f := range fs {
+					result = append(result, f.Vulnerability)
+				}
+				return result
+			}
+
+			assert.Equal(t, tt.expectedPassed, v.Passed)
+			assert.Equal(t, tt.expectedNew, ids(v.NewFindings))
+			assert.Equal(t, tt.expectedResolved, ids(v.ResolvedFindings))
+			assert.Equal(t, "1.1.0-r0", v.PublishedVersion)
+		})
+	}
+
+	t.Run("unknown severity", func(t *testing.T) {
+		_, err := NewCanaryVerdict(candidate, nil, nil, nil, "Severe")
+		assert.Error(t, err)
+	})
+}
+
+func TestSignVerdicts(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	pubDER, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	pubPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER})
+
+	verdicts := []*CanaryVerdict{{Package: "foo", Version: "1.2.0-r0", FailOn: "Critical", Passed: true}}
+
+	payload, sig, err := SignVerdicts(verdicts, keyPEM)
+	require.NoError(t, err)
+
+	got, err := VerifyVerdicts(payload, sig, pubPEM)
+	require.NoError(t, err)
+	assert.Equal(t, "foo", got[0].Package)
+	assert.True(t, got[0].Passed)
+
+	tampered := []byte(string(payload[:len(payload)-1]) + " ")
+	_, err = VerifyVerdicts(tampered, sig, pubPEM)
+	assert.Error(t, err)
+}
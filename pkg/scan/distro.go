@@ -0,0 +1,72 @@
+package scan
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/anchore/syft/syft/linux"
+)
+
+// DistroNone is the distro selection that disables distro-based matching
+// altogether, so that findings come only from language ecosystem data.
+const DistroNone = "none"
+
+// KnownDistros lists the distro IDs that can be selected with ParseDistro, in
+// addition to DistroNone.
+var KnownDistros = []string{
+	"wolfi",
+	"chainguard",
+	"alpine",
+}
+
+// Distro selects the Linux distribution whose security data namespace is used
+// when matching the scanned APKs' own packages, in place of the distro
+// detected from the scanned files.
+type Distro struct {
+	// Release is the distro to match against. It's nil when distro-based
+	// matching is disabled.
+	Release *linux.Release
+}
+
+// ParseDistro parses a distro selection such as "wolfi", "chainguard",
+// "alpine:3.19" or "none". The version, when given, follows a colon.
+func ParseDistro(s string) (*Distro, error) {
+	if s == DistroNone {
+		return &Distro{}, nil
+	}
+
+	id, version, _ := strings.Cut(s, ":")
+	known := false
+	for _, d := range KnownDistros {
+		if id == d {
+			known = true
+			break
+		}
+	}
+	if !known {
+		return nil, fmt.Errorf("unknown distro %q (must be one of: %s, %s)", id, strings.Join(KnownDistros, ", "), DistroNone)
+	}
+
+	if id == "alpine" && version == "" {
+		return nil, fmt.Errorf("a version is required for distro %q (e.g. alpine:3.19)", id)
+	}
+
+	return &Distro{
+		Release: &linux.Release{
+			ID:        id,
+			VersionID: version,
+			Name:      id,
+		},
+	}, nil
+}
+
+// release returns the release to use for matching, given the release detected
+// in the SBOM. A nil Distro means no selection was made, so the detected
+// release is used.
+func (d *Distro) release(detected *linux.Release) *linux.Release {
+	if d == nil {
+		return detected
+	}
+
+	return d.Release
+}
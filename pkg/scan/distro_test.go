@@ -0,0 +1,46 @@
+package scan
+
+import (
+	"testing"
+
+	"github.com/anchore/syft/syft/linux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDistro(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected *linux.Release
+		wantErr  bool
+	}{
+		{input: "wolfi", expected: &linux.Release{ID: "wolfi", Name: "wolfi"}},
+		{input: "chainguard", expected: &linux.Release{ID: "chainguard", Name: "chainguard"}},
+		{input: "alpine:3.19", expected: &linux.Release{ID: "alpine", VersionID: "3.19", Name: "alpine"}},
+		{input: "none", expected: nil},
+		{input: "alpine", wantErr: true},
+		{input: "debian:12", wantErr: true},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.input, func(t *testing.T) {
+			d, err := ParseDistro(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, d.Release)
+		})
+	}
+}
+
+func TestDistro_release(t *testing.T) {
+	detected := &linux.Release{ID: "wolfi"}
+
+	var unset *Distro
+	assert.Equal(t, detected, unset.release(detected))
+
+	none := &Distro{}
+	assert.Nil(t, none.release(detected))
+}
@@ -0,0 +1,25 @@
+package scan
+
+import "golang.org/x/exp/slices"
+
+// FilterByPackageType returns the findings whose package type (e.g.
+// "go-module", "python", "apk") is included in only, if only is non-empty, and
+// isn't included in exclude.
+func FilterByPackageType(findings []*Finding, only, exclude []string) []*Finding {
+	if len(only) == 0 && len(exclude) == 0 {
+		return findings
+	}
+
+	filtered := make([]*Finding, 0, len(findings))
+	for _, f := range findings {
+		if len(only) > 0 && !slices.Contains(only, f.Package.Type) {
+			continue
+		}
+		if slices.Contains(exclude, f.Package.Type) {
+			continue
+		}
+		filtered = append(filtered, f)
+	}
+
+	return filtered
+}
@@ -0,0 +1,58 @@
+package scan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterByPackageType(t *testing.T) {
+	findings := []*Finding{
+		{Package: Package{Name: "a", Type: "apk"}},
+		{Package: Package{Name: "b", Type: "go-module"}},
+		{Package: Package{Name: "c", Type: "python"}},
+		{Package: Package{Name: "d", Type: "go-module"}},
+	}
+
+	names := func(fs []*Finding) []string {
+		var result []string
+		for _, f := range fs {
+			result = append(result, f.Package.Name)
+		}
+		return result
+	}
+
+	cases := []struct {
+		name     string
+		only     []string
+		exclude  []string
+		expected []string
+	}{
+		{
+			name:     "no filters",
+			expected: []string{"a", "b", "c", "d"},
+		},
+		{
+			name:     "only",
+			only:     []string{"go-module"},
+			expected: []string{"b", "d"},
+		},
+		{
+			name:     "exclude",
+			exclude:  []string{"python", "apk"},
+			expected: []string{"b", "d"},
+		},
+		{
+			name:     "only and exclude",
+			only:     []string{"apk", "python"},
+			exclude:  []string{"python"},
+			expected: []string{"a"},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, names(FilterByPackageType(findings, tt.only, tt.exclude)))
+		})
+	}
+}
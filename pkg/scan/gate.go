@@ -0,0 +1,158 @@
+package scan
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/exp/slices"
+	"gopkg.in/yaml.v3"
+)
+
+// GatePolicy defines which scan results are acceptable, e.g. for publishing a
+// package or an image.
+type GatePolicy struct {
+	// FailOn is the minimum severity (see Severities) of a finding that fails the
+	// gate. If empty, no finding fails the gate based on its severity alone.
+	FailOn string `yaml:"fail-on"`
+
+	// MaxFindings, if set, is the most findings (of any severity) allowed.
+	MaxFindings *int `yaml:"max-findings,omitempty"`
+
+	// OnlyFixable, if true, ignores findings that don't have a fix available.
+	OnlyFixable bool `yaml:"only-fixable,omitempty"`
+
+	// Ignore lists vulnerability IDs (matched against IDs and aliases) whose
+	// findings never count against the gate.
+	Ignore []string `yaml:"ignore,omitempty"`
+}
+
+// ReadGatePolicy reads a GatePolicy from the YAML file at the given path.
+func ReadGatePolicy(p string) (*GatePolicy, error) {
+	b, err := os.ReadFile(p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read gate policy: %w", err)
+	}
+
+	policy := &GatePolicy{}
+	if err := yaml.Unmarshal(b, policy); err != nil {
+		return nil, fmt.Errorf("failed to decode gate policy %s: %w", p, err)
+	}
+
+	if policy.FailOn != "" && !slices.Contains(Severities, policy.FailOn) {
+		return nil, fmt.Errorf("invalid gate policy %s: unknown fail-on severity %q (must be one of %v)", p, policy.FailOn, Severities)
+	}
+
+	return policy, nil
+}
+
+// GateResult is the outcome of evaluating a scan result against a GatePolicy.
+type GateResult struct {
+	Passed bool
+
+	// Reasons explains why the gate failed. It's empty if the gate passed.
+	Reasons []string
+}
+
+// Evaluate determines whether the given findings pass the gate.
+func (p GatePolicy) Evaluate(findings []HistoryFinding) GateResult {
+	threshold := slices.Index(Severities, p.FailOn)
+
+	var counted, severe int
+	for _, f := range findings {
+		if p.ignores(f) {
+			continue
+		}
+		if p.OnlyFixable && f.FixedVersion == "" {
+			continue
+		}
+
+		counted++
+		if i := slices.Index(Severities, f.Severity); threshold >= 0 && i >= 0 && i <= threshold {
+			severe++
+		}
+	}
+
+	result := GateResult{Passed: true}
+	if severe > 0 {
+		result.Passed = false
+		result.Reasons = append(result.Reasons, fmt.Sprintf("%d findings of %s severity or higher", severe, p.FailOn))
+	}
+	if p.MaxFindings != nil && counted > *p.MaxFindings {
+		result.Passed = false
+		result.Reasons = append(result.Reasons, fmt.Sprintf("%d findings, more than the %d allowed", counted, *p.MaxFindings))
+	}
+
+	return result
+}
+
+func (p GatePolicy) ignores(f HistoryFinding) bool {
+	if slices.Contains(p.Ignore, f.Vulnerability) {
+		return true
+	}
+
+	for _, alias := range f.Aliases {
+		if slices.Contains(p.Ignore, alias) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GateSimulation is the outcome of replaying past scan results against a
+// proposed GatePolicy, optionally compared with a baseline (e.g. the policy
+// currently enforced).
+type GateSimulation struct {
+	// Total is the number of scan results replayed.
+	Total int
+
+	// Failed are the scan results that fail the proposed policy.
+	Failed []GateSimulationResult
+
+	// BaselineFailed is the number of scan results that fail the baseline policy.
+	// It's only set if a baseline was given.
+	BaselineFailed int
+
+	// NewlyFailing are the scan results that fail the proposed policy but pass
+	// the baseline, and NewlyPassing the reverse. They're only set if a baseline
+	// was given.
+	NewlyFailing []GateSimulationResult
+	NewlyPassing []GateSimulationResult
+}
+
+// GateSimulationResult is a replayed scan result that failed a policy.
+type GateSimulationResult struct {
+	Record HistoryRecord
+	Result GateResult
+}
+
+// SimulateGate evaluates each of the given scan history records against the
+// proposed policy, and against the baseline policy if it's not nil.
+func SimulateGate(proposed GatePolicy, baseline *GatePolicy, records []HistoryRecord) GateSimulation {
+	sim := GateSimulation{Total: len(records)}
+
+	for _, r := range records {
+		result := proposed.Evaluate(r.Findings)
+		if !result.Passed {
+			sim.Failed = append(sim.Failed, GateSimulationResult{Record: r, Result: result})
+		}
+
+		if baseline == nil {
+			continue
+		}
+
+		baselineResult := baseline.Evaluate(r.Findings)
+		if !baselineResult.Passed {
+			sim.BaselineFailed++
+		}
+
+		switch {
+		case !result.Passed && baselineResult.Passed:
+			sim.NewlyFailing = append(sim.NewlyFailing, GateSimulationResult{Record: r, Result: result})
+		case result.Passed && !baselineResult.Passed:
+			sim.NewlyPassing = append(sim.NewlyPassing, GateSimulationResult{Record: r, Result: baselineResult})
+		}
+	}
+
+	return sim
+}
@@ -0,0 +1,77 @@
+package scan
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGatePolicy_Evaluate(t *testing.T) {
+	findings := []HistoryFinding{
+		{Vulnerability: "GHSA-1", Aliases: []string{"CVE-2023-1"}, Severity: "Critical", Package: "golang.org/x/net"},
+		{Vulnerability: "CVE-2023-2", Severity: "High", FixedVersion: "1.2.3", Package: "openssl"},
+		{Vulnerability: "CVE-2023-3", Severity: "Low", Package: "busybox"},
+	}
+	two := 2
+
+	cases := []struct {
+		name           string
+		policy         GatePolicy
+		expectedPassed bool
+	}{
+		{name: "fails on critical", policy: GatePolicy{FailOn: "Critical"}, expectedPassed: false},
+		{name: "ignored by alias", policy: GatePolicy{FailOn: "Critical", Ignore: []string{"CVE-2023-1"}}, expectedPassed: true},
+		{name: "only fixable", policy: GatePolicy{FailOn: "Critical", OnlyFixable: true}, expectedPassed: true},
+		{name: "only fixable, high", policy: GatePolicy{FailOn: "High", OnlyFixable: true}, expectedPassed: false},
+		{name: "too many findings", policy: GatePolicy{MaxFindings: &two}, expectedPassed: false},
+		{name: "no rules", policy: GatePolicy{}, expectedPassed: true},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.policy.Evaluate(findings)
+			assert.Equal(t, tt.expectedPassed, result.Passed)
+			assert.Equal(t, tt.expectedPassed, len(result.Reasons) == 0)
+		})
+	}
+}
+
+func TestSimulateGate(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "history.jsonl")
+	now := time.Date(2023, time.July, 1, 0, 0, 0, 0, time.UTC)
+
+	finding := func(id, severity string) *Finding {
+		return &Finding{
+			Package:       Package{Name: "foo", Version: "1.0.0", Type: "apk"},
+			Vulnerability: Vulnerability{ID: id, Severity: severity},
+		}
+	}
+
+	require.NoError(t, AppendHistory(p, NewHistoryRecord("a.apk", []*Finding{finding("CVE-1", "High")}, now)))
+	require.NoError(t, AppendHistory(p,
+		NewHistoryRecord("b.apk", []*Finding{finding("CVE-2", "Critical")}, now),
+		NewHistoryRecord("c.apk", nil, now),
+	))
+
+	b, err := os.ReadFile(p)
+	require.NoError(t, err)
+	records, err := ReadHistory(bytes.NewReader(b))
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, "b.apk", records[1].Target)
+
+	baseline := &GatePolicy{FailOn: "Critical"}
+	sim := SimulateGate(GatePolicy{FailOn: "High"}, baseline, records)
+
+	assert.Equal(t, 3, sim.Total)
+	assert.Len(t, sim.Failed, 2)
+	assert.Equal(t, 1, sim.BaselineFailed)
+	require.Len(t, sim.NewlyFailing, 1)
+	assert.Equal(t, "a.apk", sim.NewlyFailing[0].Record.Target)
+	assert.Empty(t, sim.NewlyPassing)
+}
@@ -0,0 +1,98 @@
+package scan
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// HistoryRecord is the stored outcome of scanning a single target (such as an
+// APK file), kept so that policies can later be evaluated against past scans.
+type HistoryRecord struct {
+	Timestamp time.Time `json:"timestamp"`
+
+	// Target identifies what was scanned, e.g. the APK file's name.
+	Target string `json:"target"`
+
+	Findings []HistoryFinding `json:"findings"`
+}
+
+// HistoryFinding is a finding, as stored in a HistoryRecord.
+type HistoryFinding struct {
+	Vulnerability string   `json:"vulnerability"`
+	Aliases       []string `json:"aliases,omitempty"`
+	Severity      string   `json:"severity"`
+	FixedVersion  string   `json:"fixedVersion,omitempty"`
+	Package       string   `json:"package"`
+	Version       string   `json:"version"`
+	Type          string   `json:"type"`
+}
+
+// NewHistoryRecord returns a HistoryRecord for the given findings.
+func NewHistoryRecord(target string, findings []*Finding, now time.Time) HistoryRecord {
+	r := HistoryRecord{
+		Timestamp: now.UTC(),
+		Target:    target,
+		Findings:  make([]HistoryFinding, 0, len(findings)),
+	}
+
+	for _, f := range findings {
+		r.Findings = append(r.Findings, HistoryFinding{
+			Vulnerability: f.Vulnerability.ID,
+			Aliases:       f.Vulnerability.Aliases,
+			Severity:      f.Vulnerability.Severity,
+			FixedVersion:  f.Vulnerability.FixedVersion,
+			Package:       f.Package.Name,
+			Version:       f.Package.Version,
+			Type:          f.Package.Type,
+		})
+	}
+
+	return r
+}
+
+// AppendHistory appends the given records to the scan history file at the given
+// path, creating it if needed. The file holds one JSON-encoded record per line.
+func AppendHistory(p string, records ...HistoryRecord) error {
+	f, err := os.OpenFile(p, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("unable to open scan history: %w", err)
+	}
+	defer f.Close()
+
+	enc := json.NewEncoder(f)
+	for _, r := range records {
+		if err := enc.Encode(r); err != nil {
+			return fmt.Errorf("unable to write scan history: %w", err)
+		}
+	}
+
+	return f.Close()
+}
+
+// ReadHistory reads the records from a scan history written by AppendHistory.
+func ReadHistory(r io.Reader) ([]HistoryRecord, error) {
+	var records []HistoryRecord
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var record HistoryRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("unable to decode scan history record on line %d: %w", line, err)
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read scan history: %w", err)
+	}
+
+	return records, nil
+}
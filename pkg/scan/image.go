@@ -0,0 +1,151 @@
+package scan
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/anchore/syft/syft/pkg"
+	"github.com/anchore/syft/syft/sbom"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/wolfi-dev/wolfictl/pkg/tar"
+)
+
+// Image scans the container image at the given reference for vulnerabilities.
+// For multi-platform images, the image for the given platform is used. The
+// image's flattened filesystem is cataloged just like an APK's contents.
+func Image(ref name.Reference, platform v1.Platform, opts Options, remoteOpts ...remote.Option) (*Result, error) {
+	remoteOpts = append(remoteOpts, remote.WithPlatform(platform))
+	img, err := remote.Image(ref, remoteOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch image %s: %w", ref, err)
+	}
+
+	tempDir, err := os.MkdirTemp("", "wolfictl-scan-image-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	rc := mutate.Extract(img)
+	defer rc.Close()
+
+	err = tar.Extract(rc, tempDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract image filesystem: %w", err)
+	}
+
+	catalogers := opts.catalogers()
+	if len(catalogers) == 0 {
+		return nil, fmt.Errorf("no catalogers enabled")
+	}
+
+	s, err := catalogDirectory(tempDir, catalogers)
+	if err != nil {
+		return nil, err
+	}
+
+	findings, err := findingsForSBOM(s, opts.Distro)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Result{
+		Findings: findings,
+		SBOM:     s,
+	}, nil
+}
+
+// ImageDiff describes how two images differ in their installed APK packages
+// and their vulnerability findings.
+type ImageDiff struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+
+	// AddedPackages are installed in To but not in From.
+	AddedPackages []InstalledPackage `json:"addedPackages"`
+
+	// RemovedPackages are installed in From but not in To.
+	RemovedPackages []InstalledPackage `json:"removedPackages"`
+
+	// ChangedPackages are installed in both, at different versions.
+	ChangedPackages []PackageChange `json:"changedPackages"`
+
+	// NewFindings are To's findings that From doesn't have.
+	NewFindings []CanaryFinding `json:"newFindings"`
+
+	// ResolvedFindings are From's findings that To doesn't have.
+	ResolvedFindings []CanaryFinding `json:"resolvedFindings"`
+}
+
+// InstalledPackage is an APK package installed in an image.
+type InstalledPackage struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// PackageChange is an APK package installed in two images at different
+// versions.
+type PackageChange struct {
+	Name        string `json:"name"`
+	FromVersion string `json:"fromVersion"`
+	ToVersion   string `json:"toVersion"`
+}
+
+// NewImageDiff compares the scan results for two images, identified by the
+// given (display) references.
+func NewImageDiff(from string, fromResult *Result, to string, toResult *Result) *ImageDiff {
+	d := &ImageDiff{
+		From:             from,
+		To:               to,
+		AddedPackages:    []InstalledPackage{},
+		RemovedPackages:  []InstalledPackage{},
+		ChangedPackages:  []PackageChange{},
+		NewFindings:      diffFindings(toResult.Findings, fromResult.Findings),
+		ResolvedFindings: diffFindings(fromResult.Findings, toResult.Findings),
+	}
+
+	fromPkgs := installedAPKs(fromResult.SBOM)
+	toPkgs := installedAPKs(toResult.SBOM)
+
+	for n, v := range toPkgs {
+		fromVersion, ok := fromPkgs[n]
+		switch {
+		case !ok:
+			d.AddedPackages = append(d.AddedPackages, InstalledPackage{Name: n, Version: v})
+		case fromVersion != v:
+			d.ChangedPackages = append(d.ChangedPackages, PackageChange{Name: n, FromVersion: fromVersion, ToVersion: v})
+		}
+	}
+	for n, v := range fromPkgs {
+		if _, ok := toPkgs[n]; !ok {
+			d.RemovedPackages = append(d.RemovedPackages, InstalledPackage{Name: n, Version: v})
+		}
+	}
+
+	sort.Slice(d.AddedPackages, func(i, j int) bool { return d.AddedPackages[i].Name < d.AddedPackages[j].Name })
+	sort.Slice(d.RemovedPackages, func(i, j int) bool { return d.RemovedPackages[i].Name < d.RemovedPackages[j].Name })
+	sort.Slice(d.ChangedPackages, func(i, j int) bool { return d.ChangedPackages[i].Name < d.ChangedPackages[j].Name })
+
+	return d
+}
+
+// installedAPKs returns the versions of the APK packages described by the SBOM,
+// by package name.
+func installedAPKs(s *sbom.SBOM) map[string]string {
+	pkgs := make(map[string]string)
+	if s == nil || s.Artifacts.Packages == nil {
+		return pkgs
+	}
+
+	for _, p := range s.Artifacts.Packages.Sorted() {
+		if p.Type == pkg.ApkPkg {
+			pkgs[p.Name] = p.Version
+		}
+	}
+
+	return pkgs
+}
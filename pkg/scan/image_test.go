@@ -0,0 +1,56 @@
+package scan
+
+import (
+	"testing"
+
+	"github.com/anchore/syft/syft/pkg"
+	"github.com/anchore/syft/syft/sbom"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewImageDiff(t *testing.T) {
+	result := func(findings []*Finding, pkgs ...pkg.Package) *Result {
+		return &Result{
+			Findings: findings,
+			SBOM: &sbom.SBOM{
+				Artifacts: sbom.Artifacts{Packages: pkg.NewCollection(pkgs...)},
+			},
+		}
+	}
+	apk := func(name, version string) pkg.Package {
+		return pkg.Package{Name: name, Version: version, Type: pkg.ApkPkg}
+	}
+	finding := func(id, severity, pkgName string) *Finding {
+		return &Finding{
+			Package:       Package{Name: pkgName, Version: "1", Type: "apk"},
+			Vulnerability: Vulnerability{ID: id, Severity: severity},
+		}
+	}
+
+	from := result(
+		[]*Finding{finding("CVE-1", "High", "openssl"), finding("CVE-2", "Low", "busybox")},
+		apk("busybox", "1.36.1-r0"),
+		apk("openssl", "3.1.1-r0"),
+		apk("zlib", "1.2.13-r3"),
+		pkg.Package{Name: "golang.org/x/net", Version: "0.7.0", Type: pkg.GoModulePkg},
+	)
+	to := result(
+		[]*Finding{finding("CVE-2", "Low", "busybox"), finding("CVE-3", "Critical", "curl")},
+		apk("busybox", "1.36.1-r0"),
+		apk("openssl", "3.1.2-r0"),
+		apk("curl", "8.2.0-r0"),
+	)
+
+	d := NewImageDiff("example.com/img:old", from, "example.com/img:new", to)
+
+	assert.Equal(t, []InstalledPackage{{Name: "curl", Version: "8.2.0-r0"}}, d.AddedPackages)
+	assert.Equal(t, []InstalledPackage{{Name: "zlib", Version: "1.2.13-r3"}}, d.RemovedPackages)
+	assert.Equal(t, []PackageChange{{Name: "openssl", FromVersion: "3.1.1-r0", ToVersion: "3.1.2-r0"}}, d.ChangedPackages)
+
+	if assert.Len(t, d.NewFindings, 1) {
+		assert.Equal(t, "CVE-3", d.NewFindings[0].Vulnerability)
+	}
+	if assert.Len(t, d.ResolvedFindings, 1) {
+		assert.Equal(t, "CVE-1", d.ResolvedFindings[0].Vulnerability)
+	}
+}
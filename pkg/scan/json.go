@@ -0,0 +1,75 @@
+package scan
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// JSONResult is the result of scanning a single target, as output by 'wolfictl
+// scan --output json'.
+type JSONResult struct {
+	Target string `json:"target"`
+
+	// APK describes the scanned APK file, if the target was one.
+	APK *PackageInfo `json:"apk,omitempty"`
+
+	// Digest is the digest of the scanned file, as "sha256:<hex>".
+	Digest string `json:"digest,omitempty"`
+
+	// Scanner identifies the scanner and vulnerability database that produced
+	// the findings.
+	Scanner *Scanner `json:"scanner,omitempty"`
+
+	Findings []*Finding      `json:"findings"`
+	Secrets  []SecretFinding `json:"secrets,omitempty"`
+
+	// Suppressed are the findings suppressed by VEX statements, if requested.
+	Suppressed []SuppressedFinding `json:"suppressed,omitempty"`
+
+	// Filters are the options (e.g. "baseline" or "vex") the scan was run with
+	// that leave findings out of Findings, in which case Findings aren't all of
+	// the vulnerabilities the scanner detected. It's empty for an unfiltered scan.
+	Filters []string `json:"filters,omitempty"`
+}
+
+// ReadJSONResults reads a saved scan result: a stream of JSON objects, one per
+// scanned target.
+func ReadJSONResults(r io.Reader) ([]JSONResult, error) {
+	var results []JSONResult
+
+	dec := json.NewDecoder(r)
+	for {
+		var result JSONResult
+		err := dec.Decode(&result)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// ReadJSONResultsFile reads a saved scan result (see ReadJSONResults) from the
+// file at the given path.
+func ReadJSONResultsFile(path string) ([]JSONResult, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open scan result: %w", err)
+	}
+	defer f.Close()
+
+	results, err := ReadJSONResults(f)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse scan result: %w", err)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,133 @@
+package scan
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/anchore/syft/syft/file"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+	"github.com/anchore/syft/syft/sbom"
+	"github.com/samber/lo"
+	"gopkg.in/yaml.v3"
+)
+
+// PackageLicenses is the set of licenses detected for a cataloged package.
+type PackageLicenses struct {
+	Package Package
+
+	// Licenses are the package's declared licenses, preferring SPDX expressions
+	// where known. It's empty if no license was detected.
+	Licenses []string
+}
+
+// Licenses returns the licenses detected for each package cataloged in the
+// given SBOM.
+func Licenses(s *sbom.SBOM) []PackageLicenses {
+	pkgs := s.Artifacts.Packages.Sorted()
+
+	result := make([]PackageLicenses, 0, len(pkgs))
+	for i := range pkgs {
+		p := pkgs[i]
+
+		licenses := lo.Uniq(lo.Map(p.Licenses.ToSlice(), func(l syftPkg.License, _ int) string {
+			if l.SPDXExpression != "" {
+				return l.SPDXExpression
+			}
+			return l.Value
+		}))
+		sort.Strings(licenses)
+
+		locations := lo.Map(p.Locations.ToSlice(), func(l file.Location, _ int) string {
+			return "/" + l.RealPath
+		})
+
+		result = append(result, PackageLicenses{
+			Package: Package{
+				ID:       string(p.ID()),
+				Name:     p.Name,
+				Version:  p.Version,
+				Type:     string(p.Type),
+				Location: strings.Join(locations, ", "),
+			},
+			Licenses: licenses,
+		})
+	}
+
+	return result
+}
+
+// LicensePolicy defines which licenses aren't allowed in scanned packages.
+type LicensePolicy struct {
+	// Deny lists the licenses that aren't allowed. Entries are matched
+	// case-insensitively against each license identifier, and may use glob
+	// patterns, e.g. "GPL-3.0-*" or "AGPL-*".
+	Deny []string `yaml:"deny"`
+}
+
+// ReadLicensePolicy reads a LicensePolicy from the YAML file at the given path.
+func ReadLicensePolicy(p string) (*LicensePolicy, error) {
+	b, err := os.ReadFile(p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read license policy: %w", err)
+	}
+
+	policy := &LicensePolicy{}
+	if err := yaml.Unmarshal(b, policy); err != nil {
+		return nil, fmt.Errorf("failed to decode license policy %s: %w", p, err)
+	}
+
+	for _, pattern := range policy.Deny {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid deny pattern %q in %s: %w", pattern, p, err)
+		}
+	}
+
+	return policy, nil
+}
+
+// LicenseViolation is a package whose license is denied by a LicensePolicy.
+type LicenseViolation struct {
+	Package Package
+	License string
+}
+
+// licenseExpressionSeparators splits SPDX license expressions (e.g. "MIT OR
+// (GPL-2.0-only WITH Classpath-exception-2.0)") into their terms.
+var licenseExpressionSeparators = regexp.MustCompile(`[()\s]+`)
+
+// Denies reports whether the policy denies the given license. For license
+// expressions, the license is denied if any identifier within it is denied.
+func (p LicensePolicy) Denies(license string) bool {
+	for _, id := range licenseExpressionSeparators.Split(license, -1) {
+		switch id {
+		case "", "AND", "OR", "WITH":
+			continue
+		}
+
+		for _, pattern := range p.Deny {
+			if ok, _ := path.Match(strings.ToLower(pattern), strings.ToLower(id)); ok {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Violations returns each package license that the policy denies.
+func (p LicensePolicy) Violations(pkgs []PackageLicenses) []LicenseViolation {
+	var violations []LicenseViolation
+	for _, pl := range pkgs {
+		for _, l := range pl.Licenses {
+			if p.Denies(l) {
+				violations = append(violations, LicenseViolation{Package: pl.Package, License: l})
+			}
+		}
+	}
+
+	return violations
+}
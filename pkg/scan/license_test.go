@@ -0,0 +1,44 @@
+package scan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLicensePolicyDenies(t *testing.T) {
+	policy := LicensePolicy{Deny: []string{"AGPL-*", "SSPL-1.0"}}
+
+	cases := []struct {
+		license  string
+		expected bool
+	}{
+		{license: "MIT", expected: false},
+		{license: "AGPL-3.0-only", expected: true},
+		{license: "agpl-3.0-or-later", expected: true},
+		{license: "sspl-1.0", expected: true},
+		{license: "GPL-3.0-only", expected: false},
+		{license: "MIT OR AGPL-3.0-only", expected: true},
+		{license: "(Apache-2.0 AND BSD-3-Clause)", expected: false},
+		{license: "GPL-2.0-only WITH Classpath-exception-2.0", expected: false},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.license, func(t *testing.T) {
+			assert.Equal(t, tt.expected, policy.Denies(tt.license))
+		})
+	}
+}
+
+func TestLicensePolicyViolations(t *testing.T) {
+	policy := LicensePolicy{Deny: []string{"GPL-3.0-*"}}
+
+	pkgs := []PackageLicenses{
+		{Package: Package{Name: "a"}, Licenses: []string{"MIT"}},
+		{Package: Package{Name: "b"}, Licenses: []string{"Apache-2.0", "GPL-3.0-or-later"}},
+		{Package: Package{Name: "c"}},
+	}
+
+	violations := policy.Violations(pkgs)
+	assert.Equal(t, []LicenseViolation{{Package: Package{Name: "b"}, License: "GPL-3.0-or-later"}}, violations)
+}
@@ -0,0 +1,135 @@
+package scan
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime/debug"
+	"time"
+
+	"github.com/anchore/grype/grype/db"
+	"sigs.k8s.io/release-utils/version"
+)
+
+// Manifest records what went into a scan, so that the results of two scans of
+// the same input can be compared and any differences explained.
+type Manifest struct {
+	Timestamp time.Time `json:"timestamp"`
+
+	WolfictlVersion string `json:"wolfictlVersion"`
+	GrypeVersion    string `json:"grypeVersion"`
+	SyftVersion     string `json:"syftVersion"`
+
+	VulnerabilityDB VulnerabilityDBInfo `json:"vulnerabilityDB"`
+
+	Inputs []ManifestInput `json:"inputs"`
+}
+
+// VulnerabilityDBInfo identifies the vulnerability database used for a scan.
+type VulnerabilityDBInfo struct {
+	Built         time.Time `json:"built"`
+	SchemaVersion int       `json:"schemaVersion"`
+	Checksum      string    `json:"checksum"`
+}
+
+// ManifestInput is a file that was scanned.
+type ManifestInput struct {
+	Name   string `json:"name"`
+	SHA256 string `json:"sha256"`
+}
+
+// NewManifest returns a Manifest with the versions of wolfictl and its scanning
+// libraries filled in, and the given vulnerability database.
+func NewManifest(dbInfo VulnerabilityDBInfo, now time.Time) *Manifest {
+	return &Manifest{
+		Timestamp:       now.UTC(),
+		WolfictlVersion: version.GetVersionInfo().GitVersion,
+		GrypeVersion:    moduleVersion("github.com/anchore/grype"),
+		SyftVersion:     moduleVersion("github.com/anchore/syft"),
+		VulnerabilityDB: dbInfo,
+		Inputs:          []ManifestInput{},
+	}
+}
+
+// AddInput records a scanned file and the hex-encoded SHA-256 digest of its
+// contents.
+func (m *Manifest) AddInput(name, sha256 string) {
+	m.Inputs = append(m.Inputs, ManifestInput{Name: name, SHA256: sha256})
+}
+
+// Write writes the manifest as JSON to the file at the given path.
+func (m *Manifest) Write(path string) error {
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to encode scan manifest: %w", err)
+	}
+
+	if err := os.WriteFile(path, append(b, '\n'), 0o644); err != nil { //nolint:gosec
+		return fmt.Errorf("unable to write scan manifest: %w", err)
+	}
+
+	return nil
+}
+
+// CurrentVulnerabilityDB returns the details of the vulnerability database that
+// scans use, which must already have been downloaded (e.g. by a scan).
+func CurrentVulnerabilityDB() (VulnerabilityDBInfo, error) {
+	curator, err := db.NewCurator(grypeDBConfig)
+	if err != nil {
+		return VulnerabilityDBInfo{}, fmt.Errorf("unable to open vulnerability database: %w", err)
+	}
+
+	status := curator.Status()
+	if status.Err != nil {
+		return VulnerabilityDBInfo{}, fmt.Errorf("unable to read vulnerability database status: %w", status.Err)
+	}
+
+	return VulnerabilityDBInfo{
+		Built:         status.Built.UTC(),
+		SchemaVersion: status.SchemaVersion,
+		Checksum:      status.Checksum,
+	}, nil
+}
+
+// Scanner identifies the vulnerability scanner that produced a set of findings.
+type Scanner struct {
+	Name            string              `json:"name"`
+	Version         string              `json:"version"`
+	VulnerabilityDB VulnerabilityDBInfo `json:"vulnerabilityDB"`
+}
+
+// CurrentScanner returns the scanner that scans use, with the vulnerability
+// database that must already have been downloaded (e.g. by a scan).
+func CurrentScanner() (*Scanner, error) {
+	dbInfo, err := CurrentVulnerabilityDB()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Scanner{
+		Name:            "grype",
+		Version:         moduleVersion("github.com/anchore/grype"),
+		VulnerabilityDB: dbInfo,
+	}, nil
+}
+
+// moduleVersion returns the version of the given module that this binary was
+// built with, or "unknown" if it can't be determined.
+func moduleVersion(path string) string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+
+	for _, dep := range info.Deps {
+		if dep.Path != path {
+			continue
+		}
+		if dep.Replace != nil {
+			return dep.Replace.Version
+		}
+		return dep.Version
+	}
+
+	return "unknown"
+}
@@ -0,0 +1,31 @@
+package scan
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManifest(t *testing.T) {
+	built := time.Date(2023, 8, 1, 1, 2, 3, 0, time.UTC)
+	m := NewManifest(VulnerabilityDBInfo{Built: built, SchemaVersion: 5, Checksum: "sha256:abc"}, time.Date(2023, 8, 2, 0, 0, 0, 0, time.UTC))
+	m.AddInput("crane-0.15.2-r0.apk", "0123abcd")
+
+	path := filepath.Join(t.TempDir(), "manifest.json")
+	require.NoError(t, m.Write(path))
+
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	var written Manifest
+	require.NoError(t, json.Unmarshal(b, &written))
+	assert.Equal(t, built, written.VulnerabilityDB.Built)
+	assert.Equal(t, "sha256:abc", written.VulnerabilityDB.Checksum)
+	assert.Equal(t, []ManifestInput{{Name: "crane-0.15.2-r0.apk", SHA256: "0123abcd"}}, written.Inputs)
+	assert.NotEmpty(t, written.GrypeVersion)
+}
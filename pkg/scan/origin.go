@@ -0,0 +1,77 @@
+package scan
+
+import "fmt"
+
+// OriginFindings are the findings for the APKs built from the same origin
+// package (e.g. foo, foo-doc, and foo-dev), with identical findings collapsed.
+type OriginFindings struct {
+	Origin string
+
+	// Subpackages are the names of the APKs of this origin that were scanned, in
+	// the order they were added.
+	Subpackages []string
+
+	// Findings are the distinct findings across all of the origin's APKs.
+	Findings []*Finding
+
+	// FoundIn maps each finding in Findings to the names of the APKs it was
+	// found in.
+	FoundIn map[*Finding][]string
+
+	keys map[string]*Finding
+}
+
+// GroupByOrigin groups scanned APKs by origin package, preserving the order in
+// which each origin was first seen.
+type GroupByOrigin struct {
+	Origins []*OriginFindings
+
+	byName map[string]*OriginFindings
+}
+
+// NewGroupByOrigin returns an empty GroupByOrigin.
+func NewGroupByOrigin() *GroupByOrigin {
+	return &GroupByOrigin{
+		byName: make(map[string]*OriginFindings),
+	}
+}
+
+// Add adds the findings from scanning the given APK. A finding is considered
+// identical to one already added for the same origin if it's for the same
+// vulnerability in the same version of the same package.
+func (g *GroupByOrigin) Add(apk PackageInfo, findings []*Finding) {
+	o, ok := g.byName[apk.Origin]
+	if !ok {
+		o = &OriginFindings{
+			Origin:  apk.Origin,
+			FoundIn: make(map[*Finding][]string),
+			keys:    make(map[string]*Finding),
+		}
+		g.byName[apk.Origin] = o
+		g.Origins = append(g.Origins, o)
+	}
+
+	o.Subpackages = append(o.Subpackages, apk.Name)
+
+	for _, f := range findings {
+		key := fmt.Sprintf("%s|%s|%s|%s", f.Vulnerability.ID, f.Package.Name, f.Package.Version, f.Package.Type)
+
+		existing, ok := o.keys[key]
+		if !ok {
+			o.keys[key] = f
+			o.Findings = append(o.Findings, f)
+			existing = f
+		}
+
+		o.FoundIn[existing] = append(o.FoundIn[existing], apk.Name)
+	}
+}
+
+// Findings returns the distinct findings across all origins.
+func (g *GroupByOrigin) Findings() []*Finding {
+	var findings []*Finding
+	for _, o := range g.Origins {
+		findings = append(findings, o.Findings...)
+	}
+	return findings
+}
@@ -0,0 +1,42 @@
+package scan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupByOrigin(t *testing.T) {
+	finding := func(id, pkg string) *Finding {
+		return &Finding{
+			Package:       Package{Name: pkg, Version: "1.0.0", Type: "go-module"},
+			Vulnerability: Vulnerability{ID: id, Severity: "High"},
+		}
+	}
+
+	g := NewGroupByOrigin()
+	g.Add(PackageInfo{Name: "foo", Origin: "foo"}, []*Finding{
+		finding("GHSA-1", "golang.org/x/net"),
+		finding("GHSA-2", "golang.org/x/text"),
+	})
+	g.Add(PackageInfo{Name: "foo-dev", Origin: "foo"}, []*Finding{
+		finding("GHSA-1", "golang.org/x/net"),
+	})
+	g.Add(PackageInfo{Name: "bar", Origin: "bar"}, []*Finding{
+		finding("GHSA-1", "golang.org/x/net"),
+	})
+
+	if assert.Len(t, g.Origins, 2) {
+		foo := g.Origins[0]
+		assert.Equal(t, "foo", foo.Origin)
+		assert.Equal(t, []string{"foo", "foo-dev"}, foo.Subpackages)
+		if assert.Len(t, foo.Findings, 2) {
+			assert.Equal(t, []string{"foo", "foo-dev"}, foo.FoundIn[foo.Findings[0]])
+			assert.Equal(t, []string{"foo"}, foo.FoundIn[foo.Findings[1]])
+		}
+
+		assert.Equal(t, "bar", g.Origins[1].Origin)
+	}
+
+	assert.Len(t, g.Findings(), 3)
+}
@@ -0,0 +1,70 @@
+package scan
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// PackageInfo is the subset of an APK's .PKGINFO metadata that's relevant to
+// scanning.
+type PackageInfo struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Origin  string `json:"origin,omitempty"`
+
+	// Arch and License are the APK's architecture and license (an SPDX license
+	// expression), if recorded.
+	Arch    string `json:"arch,omitempty"`
+	License string `json:"license,omitempty"`
+
+	// Distro is the ID of the distro (e.g. "wolfi") the APK was matched as, as
+	// selected via Options or detected from its files, if known.
+	Distro string `json:"distro,omitempty"`
+}
+
+func readPackageInfo(p string) (*PackageInfo, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open .PKGINFO: %w", err)
+	}
+	defer f.Close()
+
+	return parsePackageInfo(f)
+}
+
+func parsePackageInfo(r io.Reader) (*PackageInfo, error) {
+	info := &PackageInfo{}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), " = ")
+		if !ok {
+			continue
+		}
+
+		switch key {
+		case "pkgname":
+			info.Name = value
+		case "pkgver":
+			info.Version = value
+		case "origin":
+			info.Origin = value
+		case "arch":
+			info.Arch = value
+		case "license":
+			info.License = value
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read .PKGINFO: %w", err)
+	}
+
+	if info.Origin == "" {
+		info.Origin = info.Name
+	}
+
+	return info, nil
+}
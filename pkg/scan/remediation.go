@@ -0,0 +1,163 @@
+package scan
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+
+	apkversion "github.com/knqyf263/go-apk-version"
+	"github.com/samber/lo"
+	"github.com/wolfi-dev/wolfictl/pkg/advisory/secdb"
+	"gitlab.alpinelinux.org/alpine/go/repository"
+)
+
+// RemediationStatus describes whether a distro package update exists that
+// addresses a finding.
+type RemediationStatus string
+
+const (
+	// RemediationPublished means a package version that fixes the vulnerability
+	// has been published to the repository.
+	RemediationPublished RemediationStatus = "published"
+
+	// RemediationUnpublished means the vulnerability has been recorded as fixed
+	// in a package version that isn't in the repository yet.
+	RemediationUnpublished RemediationStatus = "unpublished"
+
+	// RemediationNotAffected means the distro's security data records that the
+	// package isn't affected by the vulnerability.
+	RemediationNotAffected RemediationStatus = "not-affected"
+
+	// RemediationNone means no fixed package is known.
+	RemediationNone RemediationStatus = "none"
+)
+
+// Remediation is the distro package update, if any, that addresses a finding.
+type Remediation struct {
+	Status RemediationStatus
+
+	// Package is the name of the package to update, e.g. "foo".
+	Package string
+
+	// FixedVersion is the first package version that fixes the vulnerability, e.g.
+	// "1.2.3-r0". It's empty unless Status is RemediationPublished or
+	// RemediationUnpublished.
+	FixedVersion string
+}
+
+// Remediator maps findings to the package updates that address them, using a
+// repository's APKINDEX and security database (secdb).
+type Remediator struct {
+	// fixesByOrigin maps origin package names to vulnerability IDs to the versions
+	// that fix them.
+	fixesByOrigin map[string]map[string]string
+
+	// latestVersions maps package names to the latest version published in the
+	// repository.
+	latestVersions map[string]string
+}
+
+// NewRemediator creates a Remediator from the given APKINDEX and secdb.
+func NewRemediator(apkindex *repository.ApkIndex, db *secdb.Database) *Remediator {
+	fixesByOrigin := make(map[string]map[string]string)
+	for _, entry := range db.Packages {
+		fixes := make(map[string]string)
+		for fixedVersion, vulnIDs := range entry.Pkg.Secfixes {
+			for _, id := range vulnIDs {
+				if existing, ok := fixes[id]; ok && !versionLess(fixedVersion, existing) {
+					continue
+				}
+				fixes[id] = fixedVersion
+			}
+		}
+		fixesByOrigin[entry.Pkg.Name] = fixes
+	}
+
+	latestVersions := make(map[string]string)
+	for _, pkg := range apkindex.Packages {
+		if existing, ok := latestVersions[pkg.Name]; ok && !versionLess(existing, pkg.Version) {
+			continue
+		}
+		latestVersions[pkg.Name] = pkg.Version
+	}
+
+	return &Remediator{
+		fixesByOrigin:  fixesByOrigin,
+		latestVersions: latestVersions,
+	}
+}
+
+// Remediate returns the package update that addresses the given finding within
+// the given APK.
+func (r *Remediator) Remediate(apk PackageInfo, f *Finding) Remediation {
+	fixes := r.fixesByOrigin[apk.Origin]
+
+	ids := append([]string{f.Vulnerability.ID}, f.Vulnerability.Aliases...)
+	var fixedVersions []string
+	for _, id := range ids {
+		if v, ok := fixes[id]; ok {
+			fixedVersions = append(fixedVersions, v)
+		}
+	}
+
+	if len(fixedVersions) == 0 {
+		return Remediation{Status: RemediationNone, Package: apk.Name}
+	}
+
+	if lo.Contains(fixedVersions, secdb.NAK) {
+		return Remediation{Status: RemediationNotAffected, Package: apk.Name}
+	}
+
+	sort.Slice(fixedVersions, func(i, j int) bool {
+		return versionLess(fixedVersions[i], fixedVersions[j])
+	})
+	fixedVersion := fixedVersions[0]
+
+	status := RemediationUnpublished
+	if latest, ok := r.latestVersions[apk.Name]; ok && !versionLess(latest, fixedVersion) {
+		status = RemediationPublished
+	}
+
+	return Remediation{
+		Status:       status,
+		Package:      apk.Name,
+		FixedVersion: fixedVersion,
+	}
+}
+
+// FetchSecDB downloads the security database published at the given package
+// repository URL.
+func FetchSecDB(repositoryURL string) (*secdb.Database, error) {
+	url := repositoryURL + "/security.json"
+
+	resp, err := http.Get(url) //nolint:gosec
+	if err != nil {
+		return nil, fmt.Errorf("failed to download secdb: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download secdb: GET %s: %d", url, resp.StatusCode)
+	}
+
+	db := &secdb.Database{}
+	if err := json.NewDecoder(resp.Body).Decode(db); err != nil {
+		return nil, fmt.Errorf("failed to decode secdb: %w", err)
+	}
+
+	return db, nil
+}
+
+func versionLess(a, b string) bool {
+	va, err := apkversion.NewVersion(a)
+	if err != nil {
+		return a < b
+	}
+	vb, err := apkversion.NewVersion(b)
+	if err != nil {
+		return a < b
+	}
+
+	return va.LessThan(vb)
+}
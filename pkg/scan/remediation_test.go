@@ -0,0 +1,88 @@
+package scan
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wolfi-dev/wolfictl/pkg/advisory/secdb"
+	"gitlab.alpinelinux.org/alpine/go/repository"
+)
+
+func TestRemediator(t *testing.T) {
+	apkindex := &repository.ApkIndex{
+		Packages: []*repository.Package{
+			{Name: "foo", Version: "1.2.3-r0"},
+			{Name: "foo", Version: "1.2.4-r0"},
+			{Name: "foo-dev", Version: "1.2.4-r0"},
+		},
+	}
+
+	db := &secdb.Database{
+		Packages: []secdb.PackageEntry{
+			{
+				Pkg: secdb.Package{
+					Name: "foo",
+					Secfixes: secdb.Secfixes{
+						secdb.NAK:  {"CVE-2023-0001"},
+						"1.2.4-r0": {"CVE-2023-0002", "CVE-2023-0003"},
+						"1.2.3-r0": {"CVE-2023-0003"},
+						"1.3.0-r0": {"CVE-2023-0004"},
+					},
+				},
+			},
+		},
+	}
+
+	r := NewRemediator(apkindex, db)
+	apk := PackageInfo{Name: "foo-dev", Version: "1.2.2-r0", Origin: "foo"}
+
+	cases := []struct {
+		vulnID   string
+		aliases  []string
+		expected Remediation
+	}{
+		{
+			vulnID:   "CVE-2023-0001",
+			expected: Remediation{Status: RemediationNotAffected, Package: "foo-dev"},
+		},
+		{
+			vulnID:   "GHSA-xxxx-xxxx-xxxx",
+			aliases:  []string{"CVE-2023-0002"},
+			expected: Remediation{Status: RemediationPublished, Package: "foo-dev", FixedVersion: "1.2.4-r0"},
+		},
+		{
+			vulnID:   "CVE-2023-0003",
+			expected: Remediation{Status: RemediationPublished, Package: "foo-dev", FixedVersion: "1.2.3-r0"},
+		},
+		{
+			vulnID:   "CVE-2023-0004",
+			expected: Remediation{Status: RemediationUnpublished, Package: "foo-dev", FixedVersion: "1.3.0-r0"},
+		},
+		{
+			vulnID:   "CVE-2023-0005",
+			expected: Remediation{Status: RemediationNone, Package: "foo-dev"},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.vulnID, func(t *testing.T) {
+			f := &Finding{Vulnerability: Vulnerability{ID: tt.vulnID, Aliases: tt.aliases}}
+			assert.Equal(t, tt.expected, r.Remediate(apk, f))
+		})
+	}
+}
+
+func TestParsePackageInfo(t *testing.T) {
+	pkginfo := `# Generated by melange
+pkgname = foo-dev
+pkgver = 1.2.3-r0
+arch = x86_64
+origin = foo
+`
+
+	info, err := parsePackageInfo(strings.NewReader(pkginfo))
+	require.NoError(t, err)
+	assert.Equal(t, &PackageInfo{Name: "foo-dev", Version: "1.2.3-r0", Origin: "foo", Arch: "x86_64"}, info)
+}
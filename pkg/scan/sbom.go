@@ -0,0 +1,202 @@
+package scan
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/anchore/syft/syft/formats"
+	"github.com/anchore/syft/syft/sbom"
+)
+
+// SBOM scans the packages described by an existing SBOM for vulnerabilities,
+// without cataloging any files. The SBOM's format is detected automatically.
+// Of the given options, only Distro applies.
+func SBOM(r io.Reader, opts Options) (*Result, error) {
+	s, _, err := formats.Decode(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode SBOM: %w", err)
+	}
+	if s == nil {
+		return nil, fmt.Errorf("failed to decode SBOM: unrecognized format")
+	}
+
+	findings, err := findingsForSBOM(s, opts.Distro)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Result{
+		Findings: findings,
+		SBOM:     s,
+	}, nil
+}
+
+// SBOMFormats lists the names of the SBOM formats that can be used with
+// EncodeSBOM.
+var SBOMFormats = []string{
+	"spdx-json",
+	"cyclonedx-json",
+	"cyclonedx-xml",
+	"syft-json",
+}
+
+// ValidateSBOMFormat returns an error if the named SBOM format can't be used
+// with EncodeSBOM.
+func ValidateSBOMFormat(formatName string) error {
+	if formats.ByName(formatName) == nil {
+		return fmt.Errorf("unsupported SBOM format %q (must be one of: %s)", formatName, strings.Join(SBOMFormats, ", "))
+	}
+
+	return nil
+}
+
+// EncodeSBOM writes the given SBOM to w, encoded in the named SBOM format (see
+// SBOMFormats).
+func EncodeSBOM(w io.Writer, s *sbom.SBOM, formatName string) error {
+	if s == nil {
+		return fmt.Errorf("no SBOM to encode")
+	}
+
+	if err := ValidateSBOMFormat(formatName); err != nil {
+		return err
+	}
+
+	err := formats.ByName(formatName).Encode(w, *s)
+	if err != nil {
+		return fmt.Errorf("failed to encode SBOM as %s: %w", formatName, err)
+	}
+
+	return nil
+}
+
+// DefaultSPDXNamespaceBase is the URI under which the document namespaces of
+// SPDX SBOMs are created, unless another is given.
+const DefaultSPDXNamespaceBase = "https://spdx.org/spdxdocs"
+
+// APKDocumentNamespace returns the SPDX document namespace of the SBOM of an APK
+// file with the given package info and hex-encoded SHA-256 digest, under the
+// given base URI. Since it's derived from the digest, generating the SBOM of the
+// same file again yields the same namespace, and different files' SBOMs don't
+// share one.
+func APKDocumentNamespace(base string, apk PackageInfo, digest string) string {
+	return fmt.Sprintf("%s/%s-%s-%s", strings.TrimSuffix(base, "/"), apk.Name, apk.Version, digest)
+}
+
+// EncodeSPDXJSON writes the given SBOM to w as SPDX JSON, with the given
+// document namespace instead of the random one Syft generates.
+func EncodeSPDXJSON(w io.Writer, s *sbom.SBOM, namespace string) error {
+	var buf bytes.Buffer
+	if err := EncodeSBOM(&buf, s, "spdx-json"); err != nil {
+		return err
+	}
+
+	doc, err := setSPDXDocumentNamespace(buf.Bytes(), namespace)
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.Write(doc); err != nil {
+		return fmt.Errorf("failed to write SBOM: %w", err)
+	}
+
+	return nil
+}
+
+// setSPDXDocumentNamespace returns the given SPDX JSON document with its
+// documentNamespace replaced.
+func setSPDXDocumentNamespace(doc []byte, namespace string) ([]byte, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(doc, &fields); err != nil {
+		return nil, fmt.Errorf("failed to decode SPDX document: %w", err)
+	}
+
+	ns, err := json.Marshal(namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode document namespace: %w", err)
+	}
+	fields["documentNamespace"] = ns
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(fields); err != nil {
+		return nil, fmt.Errorf("failed to encode SPDX document: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// EncodeCycloneDX writes the given SBOM of an APK file to w in the named
+// CycloneDX format ("cyclonedx-json" or "cyclonedx-xml"), with the APK itself,
+// as described by its package info, as the BOM's top-level component.
+func EncodeCycloneDX(w io.Writer, s *sbom.SBOM, formatName string, apk PackageInfo) error {
+	var format cdx.BOMFileFormat
+	switch formatName {
+	case "cyclonedx-json":
+		format = cdx.BOMFileFormatJSON
+	case "cyclonedx-xml":
+		format = cdx.BOMFileFormatXML
+	default:
+		return fmt.Errorf("unsupported CycloneDX format %q (must be cyclonedx-json or cyclonedx-xml)", formatName)
+	}
+
+	var buf bytes.Buffer
+	if err := EncodeSBOM(&buf, s, formatName); err != nil {
+		return err
+	}
+
+	return setCycloneDXComponent(w, &buf, format, apk)
+}
+
+// setCycloneDXComponent copies the CycloneDX BOM read from r to w, replacing its
+// top-level component (which Syft sets to the cataloged directory) with a
+// component for the APK.
+func setCycloneDXComponent(w io.Writer, r io.Reader, format cdx.BOMFileFormat, apk PackageInfo) error {
+	bom := cdx.NewBOM()
+	if err := cdx.NewBOMDecoder(r, format).Decode(bom); err != nil {
+		return fmt.Errorf("failed to decode CycloneDX BOM: %w", err)
+	}
+
+	if bom.Metadata == nil {
+		bom.Metadata = &cdx.Metadata{}
+	}
+	bom.Metadata.Component = apkComponent(apk)
+
+	if err := cdx.NewBOMEncoder(w, format).SetPretty(true).Encode(bom); err != nil {
+		return fmt.Errorf("failed to encode CycloneDX BOM: %w", err)
+	}
+
+	return nil
+}
+
+// apkComponent returns the CycloneDX component describing an APK, with its
+// origin package and architecture as properties.
+func apkComponent(apk PackageInfo) *cdx.Component {
+	c := &cdx.Component{
+		BOMRef:  fmt.Sprintf("apk:%s@%s", apk.Name, apk.Version),
+		Type:    cdx.ComponentTypeLibrary,
+		Name:    apk.Name,
+		Version: apk.Version,
+	}
+
+	if apk.License != "" {
+		c.Licenses = &cdx.Licenses{{Expression: apk.License}}
+	}
+
+	var props []cdx.Property
+	if apk.Origin != "" {
+		props = append(props, cdx.Property{Name: "wolfictl:apk:origin", Value: apk.Origin})
+	}
+	if apk.Arch != "" {
+		props = append(props, cdx.Property{Name: "wolfictl:apk:arch", Value: apk.Arch})
+	}
+	if len(props) > 0 {
+		c.Properties = &props
+	}
+
+	return c
+}
@@ -0,0 +1,173 @@
+package scan
+
+import (
+	"sort"
+
+	"github.com/anchore/syft/syft/pkg"
+	"github.com/anchore/syft/syft/sbom"
+	"github.com/hashicorp/go-version"
+	apkversion "github.com/knqyf263/go-apk-version"
+	"github.com/samber/lo"
+)
+
+// SBOMDiff describes how the components cataloged in two APK files differ, such
+// as the dependencies bundled in two versions of a package.
+type SBOMDiff struct {
+	From *PackageInfo `json:"from"`
+	To   *PackageInfo `json:"to"`
+
+	// Added are components in To but not in From.
+	Added []Component `json:"added"`
+
+	// Removed are components in From but not in To.
+	Removed []Component `json:"removed"`
+
+	// Upgraded and Downgraded are components in both, at a later or earlier
+	// version in To.
+	Upgraded   []ComponentChange `json:"upgraded"`
+	Downgraded []ComponentChange `json:"downgraded"`
+
+	// Changed are components in both, at different versions that can't be
+	// ordered.
+	Changed []ComponentChange `json:"changed"`
+}
+
+// Component is a package cataloged in an APK file.
+type Component struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Type    string `json:"type"`
+}
+
+// ComponentChange is a component cataloged in two APK files at different
+// versions.
+type ComponentChange struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	FromVersion string `json:"fromVersion"`
+	ToVersion   string `json:"toVersion"`
+}
+
+// NewSBOMDiff compares the SBOMs of two APK files, described by the given
+// package info.
+//
+// Components are matched by name and type. Where an APK has several versions of
+// a component (e.g. a Go module bundled in several binaries), a single version
+// on each side is reported as a change, and otherwise the versions only on one
+// side are reported as added or removed.
+func NewSBOMDiff(from *PackageInfo, fromSBOM *sbom.SBOM, to *PackageInfo, toSBOM *sbom.SBOM) *SBOMDiff {
+	d := &SBOMDiff{
+		From:       from,
+		To:         to,
+		Added:      []Component{},
+		Removed:    []Component{},
+		Upgraded:   []ComponentChange{},
+		Downgraded: []ComponentChange{},
+		Changed:    []ComponentChange{},
+	}
+
+	fromVersions := componentVersions(fromSBOM)
+	toVersions := componentVersions(toSBOM)
+
+	for _, k := range lo.Uniq(append(lo.Keys(fromVersions), lo.Keys(toVersions)...)) {
+		fv, tv := fromVersions[k], toVersions[k]
+		removed, added := lo.Difference(fv, tv)
+
+		if len(fv) == 1 && len(tv) == 1 && len(added) == 1 {
+			c := ComponentChange{Name: k.name, Type: k.typ, FromVersion: fv[0], ToVersion: tv[0]}
+			switch cmp, ok := compareComponentVersions(k.typ, c.FromVersion, c.ToVersion); {
+			case !ok || cmp == 0:
+				d.Changed = append(d.Changed, c)
+			case cmp < 0:
+				d.Upgraded = append(d.Upgraded, c)
+			default:
+				d.Downgraded = append(d.Downgraded, c)
+			}
+			continue
+		}
+
+		for _, v := range added {
+			d.Added = append(d.Added, Component{Name: k.name, Version: v, Type: k.typ})
+		}
+		for _, v := range removed {
+			d.Removed = append(d.Removed, Component{Name: k.name, Version: v, Type: k.typ})
+		}
+	}
+
+	sortComponents(d.Added)
+	sortComponents(d.Removed)
+	sortComponentChanges(d.Upgraded)
+	sortComponentChanges(d.Downgraded)
+	sortComponentChanges(d.Changed)
+
+	return d
+}
+
+type componentKey struct{ name, typ string }
+
+// componentVersions returns the versions of the components described by the
+// SBOM, by name and type.
+func componentVersions(s *sbom.SBOM) map[componentKey][]string {
+	versions := make(map[componentKey][]string)
+	if s == nil || s.Artifacts.Packages == nil {
+		return versions
+	}
+
+	for _, p := range s.Artifacts.Packages.Sorted() {
+		k := componentKey{name: p.Name, typ: string(p.Type)}
+		if !lo.Contains(versions[k], p.Version) {
+			versions[k] = append(versions[k], p.Version)
+		}
+	}
+
+	return versions
+}
+
+// compareComponentVersions compares two versions of a component of the given
+// type, returning -1, 0, or 1 as a is earlier than, the same as, or later than
+// b. APK versions are compared as such, and others as semantic versions. It
+// returns false if the versions can't be parsed.
+func compareComponentVersions(typ, a, b string) (int, bool) {
+	if typ == string(pkg.ApkPkg) {
+		va, err := apkversion.NewVersion(a)
+		if err != nil {
+			return 0, false
+		}
+		vb, err := apkversion.NewVersion(b)
+		if err != nil {
+			return 0, false
+		}
+		return va.Compare(vb), true
+	}
+
+	va, err := version.NewVersion(a)
+	if err != nil {
+		return 0, false
+	}
+	vb, err := version.NewVersion(b)
+	if err != nil {
+		return 0, false
+	}
+	return va.Compare(vb), true
+}
+
+func sortComponents(components []Component) {
+	sort.Slice(components, func(i, j int) bool {
+		if components[i].Name != components[j].Name {
+			return components[i].Name < components[j].Name
+		}
+		if components[i].Type != components[j].Type {
+			return components[i].Type < components[j].Type
+		}
+		return components[i].Version < components[j].Version
+	})
+}
+
+func sortComponentChanges(changes []ComponentChange) {
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Name != changes[j].Name {
+			return changes[i].Name < changes[j].Name
+		}
+		return changes[i].Type < changes[j].Type
+	})
+}
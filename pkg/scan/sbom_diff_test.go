@@ -0,0 +1,66 @@
+package scan
+
+import (
+	"testing"
+
+	"github.com/anchore/syft/syft/pkg"
+	"github.com/anchore/syft/syft/sbom"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSBOMDiff(t *testing.T) {
+	s := func(pkgs ...pkg.Package) *sbom.SBOM {
+		return &sbom.SBOM{Artifacts: sbom.Artifacts{Packages: pkg.NewCollection(pkgs...)}}
+	}
+	gomod := func(name, version string) pkg.Package {
+		return pkg.Package{Name: name, Version: version, Type: pkg.GoModulePkg}
+	}
+
+	from := s(
+		gomod("golang.org/x/net", "v0.7.0"),
+		gomod("golang.org/x/text", "v0.9.0"),
+		gomod("github.com/foo/bar", "v1.2.0"),
+		gomod("github.com/foo/baz", "v0.0.0-20230101000000-abcdef123456"),
+		gomod("github.com/old/dep", "v1.0.0"),
+		gomod("golang.org/x/sys", "v0.8.0"),
+		pkg.Package{Name: "stdlib", Version: "go1.20.4", Type: pkg.GoModulePkg},
+	)
+	to := s(
+		gomod("golang.org/x/net", "v0.10.0"),
+		gomod("golang.org/x/text", "v0.9.0"),
+		gomod("github.com/foo/bar", "v1.1.0"),
+		gomod("github.com/foo/baz", "v0.0.0-20230601000000-123456abcdef"),
+		gomod("github.com/new/dep", "v2.0.0"),
+		gomod("golang.org/x/sys", "v0.8.0"),
+		gomod("golang.org/x/sys", "v0.9.0"),
+		pkg.Package{Name: "stdlib", Version: "go1.20.5", Type: pkg.GoModulePkg},
+	)
+
+	d := NewSBOMDiff(&PackageInfo{Name: "crane", Version: "0.15.2-r0"}, from, &PackageInfo{Name: "crane", Version: "0.16.0-r0"}, to)
+
+	assert.Equal(t, "0.15.2-r0", d.From.Version)
+	assert.Equal(t, []Component{
+		{Name: "github.com/new/dep", Version: "v2.0.0", Type: "go-module"},
+		{Name: "golang.org/x/sys", Version: "v0.9.0", Type: "go-module"},
+	}, d.Added)
+	assert.Equal(t, []Component{{Name: "github.com/old/dep", Version: "v1.0.0", Type: "go-module"}}, d.Removed)
+	assert.Equal(t, []ComponentChange{
+		{Name: "github.com/foo/baz", Type: "go-module", FromVersion: "v0.0.0-20230101000000-abcdef123456", ToVersion: "v0.0.0-20230601000000-123456abcdef"},
+		{Name: "golang.org/x/net", Type: "go-module", FromVersion: "v0.7.0", ToVersion: "v0.10.0"},
+	}, d.Upgraded)
+	assert.Equal(t, []ComponentChange{{Name: "github.com/foo/bar", Type: "go-module", FromVersion: "v1.2.0", ToVersion: "v1.1.0"}}, d.Downgraded)
+	assert.Equal(t, []ComponentChange{{Name: "stdlib", Type: "go-module", FromVersion: "go1.20.4", ToVersion: "go1.20.5"}}, d.Changed)
+}
+
+func TestCompareComponentVersions(t *testing.T) {
+	cmp, ok := compareComponentVersions("apk", "1.2.3-r9", "1.2.3-r10")
+	assert.True(t, ok)
+	assert.Equal(t, -1, cmp)
+
+	cmp, ok = compareComponentVersions("python", "2.31.0", "2.28.2")
+	assert.True(t, ok)
+	assert.Equal(t, 1, cmp)
+
+	_, ok = compareComponentVersions("go-module", "go1.20.4", "go1.20.5")
+	assert.False(t, ok)
+}
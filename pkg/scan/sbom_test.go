@@ -0,0 +1,53 @@
+package scan
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPKDocumentNamespace(t *testing.T) {
+	apk := PackageInfo{Name: "crane", Version: "0.15.2-r0"}
+	assert.Equal(t, "https://spdx.org/spdxdocs/crane-0.15.2-r0-abc123", APKDocumentNamespace(DefaultSPDXNamespaceBase, apk, "abc123"))
+	assert.Equal(t, "https://example.com/sboms/crane-0.15.2-r0-abc123", APKDocumentNamespace("https://example.com/sboms/", apk, "abc123"))
+}
+
+func TestSetSPDXDocumentNamespace(t *testing.T) {
+	doc := []byte(`{"spdxVersion":"SPDX-2.3","name":"crane","documentNamespace":"https://anchore.com/syft/dir/crane-8d3e0b9a","packages":[{"name":"crane","downloadLocation":"NOASSERTION"}]}`)
+
+	got, err := setSPDXDocumentNamespace(doc, "https://spdx.org/spdxdocs/crane-0.15.2-r0-abc123")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"spdxVersion":"SPDX-2.3","name":"crane","documentNamespace":"https://spdx.org/spdxdocs/crane-0.15.2-r0-abc123","packages":[{"name":"crane","downloadLocation":"NOASSERTION"}]}`, string(got))
+
+	_, err = setSPDXDocumentNamespace([]byte("not json"), "https://spdx.org/spdxdocs/x")
+	assert.Error(t, err)
+}
+
+func TestSetCycloneDXComponent(t *testing.T) {
+	bom := `{"bomFormat":"CycloneDX","specVersion":"1.4","version":1,"metadata":{"component":{"bom-ref":"dir","type":"file","name":"/tmp/wolfictl-scan-123"}},"components":[{"type":"library","name":"golang.org/x/net","version":"v0.7.0"}]}`
+	apk := PackageInfo{Name: "crane", Version: "0.15.2-r0", Origin: "go-containerregistry", Arch: "x86_64", License: "Apache-2.0"}
+
+	var buf bytes.Buffer
+	require.NoError(t, setCycloneDXComponent(&buf, strings.NewReader(bom), cdx.BOMFileFormatJSON, apk))
+
+	got := cdx.NewBOM()
+	require.NoError(t, cdx.NewBOMDecoder(&buf, cdx.BOMFileFormatJSON).Decode(got))
+	assert.Equal(t, &cdx.Component{
+		BOMRef:   "apk:crane@0.15.2-r0",
+		Type:     cdx.ComponentTypeLibrary,
+		Name:     "crane",
+		Version:  "0.15.2-r0",
+		Licenses: &cdx.Licenses{{Expression: "Apache-2.0"}},
+		Properties: &[]cdx.Property{
+			{Name: "wolfictl:apk:origin", Value: "go-containerregistry"},
+			{Name: "wolfictl:apk:arch", Value: "x86_64"},
+		},
+	}, got.Metadata.Component)
+	if assert.NotNil(t, got.Components) {
+		assert.Len(t, *got.Components, 1)
+	}
+}
@@ -0,0 +1,115 @@
+package scan
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+)
+
+// maxSecretsFileSize is the size above which files aren't searched for secrets.
+const maxSecretsFileSize = 10 * 1024 * 1024
+
+// SecretRule is a pattern that identifies a kind of secret.
+type SecretRule struct {
+	ID      string
+	Pattern *regexp.Regexp
+}
+
+// SecretRules are the rules used to find secrets within APK files.
+var SecretRules = []SecretRule{
+	{
+		ID:      "private-key",
+		Pattern: regexp.MustCompile(`-----BEGIN (?:RSA |DSA |EC |OPENSSH |PGP |ENCRYPTED )?PRIVATE KEY(?: BLOCK)?-----`),
+	},
+	{
+		ID:      "aws-access-key-id",
+		Pattern: regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`),
+	},
+	{
+		ID:      "aws-secret-access-key",
+		Pattern: regexp.MustCompile(`(?i)aws_?secret_?access_?key["']?\s*[:=]\s*["']?[A-Za-z0-9/+=]{40}\b`),
+	},
+	{
+		ID:      "github-token",
+		Pattern: regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{82})\b`),
+	},
+	{
+		ID:      "gitlab-token",
+		Pattern: regexp.MustCompile(`\bglpat-[A-Za-z0-9_\-]{20}\b`),
+	},
+	{
+		ID:      "slack-token",
+		Pattern: regexp.MustCompile(`\bxox[abprs]-[0-9A-Za-z-]{10,}\b`),
+	},
+	{
+		ID:      "google-api-key",
+		Pattern: regexp.MustCompile(`\bAIza[0-9A-Za-z_\-]{35}\b`),
+	},
+}
+
+// SecretFinding is a potential secret found in a file.
+type SecretFinding struct {
+	// Rule is the ID of the SecretRule that matched.
+	Rule string `json:"rule"`
+
+	// Location is the absolute path of the file within the APK.
+	Location string `json:"location"`
+
+	// Line is the 1-based line number of the match.
+	Line int `json:"line"`
+}
+
+// FindSecrets searches the regular files in fsys for matches of SecretRules.
+// The matched values themselves aren't returned, so that the results can be
+// shared without further spreading the secrets.
+func FindSecrets(fsys fs.FS) ([]SecretFinding, error) {
+	var findings []SecretFinding
+
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if info.Size() > maxSecretsFileSize {
+			return nil
+		}
+
+		b, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", p, err)
+		}
+
+		for _, rule := range SecretRules {
+			for _, loc := range rule.Pattern.FindAllIndex(b, -1) {
+				findings = append(findings, SecretFinding{
+					Rule:     rule.ID,
+					Location: "/" + p,
+					Line:     bytes.Count(b[:loc[0]], []byte("\n")) + 1,
+				})
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search for secrets: %w", err)
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Location != findings[j].Location {
+			return findings[i].Location < findings[j].Location
+		}
+		return findings[i].Line < findings[j].Line
+	})
+
+	return findings, nil
+}
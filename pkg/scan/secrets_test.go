@@ -0,0 +1,35 @@
+package scan
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindSecrets(t *testing.T) {
+	// Assemble the secrets at runtime, so that this file doesn't itself trip
+	// secret scanners.
+	privateKeyHeader := "-----BEGIN OPENSSH " + "PRIVATE KEY-----"
+
+	fsys := fstest.MapFS{
+		"etc/foo.conf": {
+			Data: []byte("config:\n  name: foo\n\n  aws_access_key_id = AKIA" + "IOSFODNN7EXAMPLE\n"),
+		},
+		"usr/share/foo/id_ed25519": {
+			Data: []byte(privateKeyHeader + "\nb3BlbnNzaC1rZXktdjEAAAAABG5vbmUAAAAEbm9uZQ\n"),
+		},
+		"usr/share/foo/README": {
+			Data: []byte("Nothing to see here.\n"),
+		},
+	}
+
+	findings, err := FindSecrets(fsys)
+	require.NoError(t, err)
+
+	assert.Equal(t, []SecretFinding{
+		{Rule: "aws-access-key-id", Location: "/etc/foo.conf", Line: 4},
+		{Rule: "private-key", Location: "/usr/share/foo/id_ed25519", Line: 1},
+	}, findings)
+}
@@ -0,0 +1,186 @@
+package scan
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/anchore/syft/syft/artifact"
+	"github.com/anchore/syft/syft/pkg"
+	"github.com/anchore/syft/syft/sbom"
+)
+
+// DefaultSourceRepository is the repository containing the melange configs
+// that build Wolfi's packages.
+const DefaultSourceRepository = "https://github.com/wolfi-dev/os"
+
+// SourceLocation is where the melange config that builds a package can be
+// viewed.
+type SourceLocation struct {
+	// URL links to the config's package block, at Ref.
+	URL string `json:"url"`
+
+	// Path is the path of the config within the repository.
+	Path string `json:"path"`
+
+	// Ref is the commit (or "HEAD", if the package's commit isn't known) at which
+	// the config is linked.
+	Ref string `json:"ref"`
+
+	// Line is the line of the config's package block, or 0 if it wasn't found.
+	Line int `json:"line,omitempty"`
+}
+
+// SourceResolver finds the melange configs that build APK packages, in a
+// GitHub repository of melange configs like wolfi-dev/os.
+type SourceResolver struct {
+	repositoryURL string
+
+	// fetch returns the contents of the file at the given path and ref of the
+	// repository.
+	fetch func(ref, path string) ([]byte, error)
+
+	resolved map[string]sourceResolution
+}
+
+type sourceResolution struct {
+	location *SourceLocation
+	err      error
+}
+
+// NewSourceResolver returns a SourceResolver for the GitHub repository at the
+// given URL (e.g. DefaultSourceRepository).
+func NewSourceResolver(repositoryURL string) *SourceResolver {
+	r := &SourceResolver{
+		repositoryURL: strings.TrimSuffix(repositoryURL, "/"),
+		resolved:      make(map[string]sourceResolution),
+	}
+	r.fetch = r.fetchFromGitHub
+
+	return r
+}
+
+// Resolve returns the location of the melange config for the given origin
+// package, at the given commit of the repository, or at its default branch if
+// commit is empty. Results are cached.
+func (r *SourceResolver) Resolve(origin, commit string) (*SourceLocation, error) {
+	ref := commit
+	if ref == "" {
+		ref = "HEAD"
+	}
+	cfgPath := origin + ".yaml"
+
+	key := ref + "/" + cfgPath
+	if res, ok := r.resolved[key]; ok {
+		return res.location, res.err
+	}
+
+	b, err := r.fetch(ref, cfgPath)
+	if err != nil {
+		err = fmt.Errorf("unable to find melange config for %s: %w", origin, err)
+		r.resolved[key] = sourceResolution{err: err}
+		return nil, err
+	}
+
+	loc := &SourceLocation{
+		URL:  fmt.Sprintf("%s/blob/%s/%s", r.repositoryURL, ref, cfgPath),
+		Path: cfgPath,
+		Ref:  ref,
+		Line: packageBlockLine(b),
+	}
+	if loc.Line > 0 {
+		loc.URL += fmt.Sprintf("#L%d", loc.Line)
+	}
+
+	r.resolved[key] = sourceResolution{location: loc}
+	return loc, nil
+}
+
+// Annotate sets the Source of the package of each finding for an APK package.
+// Packages whose source can't be resolved are skipped, and the first such
+// error is returned once the rest have been annotated.
+func (r *SourceResolver) Annotate(findings []*Finding) error {
+	var firstErr error
+	for _, f := range findings {
+		if f.Package.Type != string(pkg.ApkPkg) || f.Package.Origin == "" {
+			continue
+		}
+
+		loc, err := r.Resolve(f.Package.Origin, f.Package.Commit)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+
+		f.Package.Source = loc
+	}
+
+	return firstErr
+}
+
+func (r *SourceResolver) fetchFromGitHub(ref, cfgPath string) ([]byte, error) {
+	const githubPrefix = "https://github.com/"
+	if !strings.HasPrefix(r.repositoryURL, githubPrefix) {
+		return nil, fmt.Errorf("%s is not a GitHub repository", r.repositoryURL)
+	}
+
+	url := fmt.Sprintf("https://raw.githubusercontent.com/%s/%s/%s", strings.TrimPrefix(r.repositoryURL, githubPrefix), ref, cfgPath)
+	resp, err := http.Get(url) //nolint:gosec
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %d", url, resp.StatusCode)
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+// packageBlockLine returns the 1-based line number of the top-level "package:"
+// key in the given melange config, or 0 if there isn't one.
+func packageBlockLine(cfg []byte) int {
+	scanner := bufio.NewScanner(bytes.NewReader(cfg))
+	for line := 1; scanner.Scan(); line++ {
+		if strings.HasPrefix(scanner.Text(), "package:") {
+			return line
+		}
+	}
+
+	return 0
+}
+
+// addAPKOrigin sets the origin and commit of the given APK package, as
+// recorded in the SBOM's metadata for the package.
+func addAPKOrigin(p *Package, s *sbom.SBOM) {
+	if p.Type != string(pkg.ApkPkg) {
+		return
+	}
+
+	syftPkg := s.Artifacts.Packages.Package(artifact.ID(p.ID))
+	if syftPkg == nil {
+		return
+	}
+
+	var metadata pkg.ApkMetadata
+	switch m := syftPkg.Metadata.(type) {
+	case pkg.ApkMetadata:
+		metadata = m
+	case *pkg.ApkMetadata:
+		metadata = *m
+	default:
+		return
+	}
+
+	p.Origin = metadata.OriginPackage
+	if p.Origin == "" {
+		p.Origin = metadata.Package
+	}
+	p.Commit = metadata.GitCommit
+}
@@ -0,0 +1,44 @@
+package scan
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSourceResolver(t *testing.T) {
+	var fetches int
+	r := NewSourceResolver("https://github.com/wolfi-dev/os/")
+	r.fetch = func(ref, path string) ([]byte, error) {
+		fetches++
+		if path != "openssl.yaml" {
+			return nil, fmt.Errorf("GET %s: 404", path)
+		}
+		return []byte("# Generated\n\npackage:\n  name: openssl\n  version: 3.1.2\n"), nil
+	}
+
+	findings := []*Finding{
+		{Package: Package{Name: "libcrypto3", Type: "apk", Origin: "openssl", Commit: "abc123"}},
+		{Package: Package{Name: "libssl3", Type: "apk", Origin: "openssl", Commit: "abc123"}},
+		{Package: Package{Name: "golang.org/x/net", Type: "go-module"}},
+		{Package: Package{Name: "busybox", Type: "apk", Origin: "busybox"}},
+	}
+
+	err := r.Annotate(findings)
+	assert.EqualError(t, err, "unable to find melange config for busybox: GET busybox.yaml: 404")
+
+	expected := &SourceLocation{
+		URL:  "https://github.com/wolfi-dev/os/blob/abc123/openssl.yaml#L3",
+		Path: "openssl.yaml",
+		Ref:  "abc123",
+		Line: 3,
+	}
+	assert.Equal(t, expected, findings[0].Package.Source)
+	assert.Equal(t, expected, findings[1].Package.Source)
+	assert.Nil(t, findings[2].Package.Source)
+	assert.Nil(t, findings[3].Package.Source)
+
+	// openssl was only fetched once.
+	assert.Equal(t, 2, fetches)
+}
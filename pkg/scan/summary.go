@@ -0,0 +1,81 @@
+package scan
+
+import "golang.org/x/exp/slices"
+
+// Severities lists the vulnerability severities used in findings, from most to
+// least severe.
+var Severities = []string{
+	"Critical",
+	"High",
+	"Medium",
+	"Low",
+	"Negligible",
+	"Unknown",
+}
+
+// Summary is a roll-up of a set of findings.
+type Summary struct {
+	// CountsBySeverity maps each severity (see Severities) to the number of
+	// findings with that severity. Findings with an unrecognized severity are
+	// counted as "Unknown".
+	CountsBySeverity map[string]int
+
+	// AffectedPackages is the number of distinct packages with at least one
+	// finding.
+	AffectedPackages int
+
+	// FixesAvailable is the number of findings for which a fixed version exists.
+	FixesAvailable int
+
+	// Total is the total number of findings.
+	Total int
+}
+
+// Summarize counts the given findings by severity, affected package, and fix
+// availability.
+func Summarize(findings []*Finding) Summary {
+	s := Summary{
+		CountsBySeverity: make(map[string]int),
+		Total:            len(findings),
+	}
+
+	for _, sev := range Severities {
+		s.CountsBySeverity[sev] = 0
+	}
+
+	packages := make(map[string]struct{})
+	for _, f := range findings {
+		sev := f.Vulnerability.Severity
+		if _, ok := s.CountsBySeverity[sev]; !ok {
+			sev = "Unknown"
+		}
+		s.CountsBySeverity[sev]++
+
+		packages[f.Package.ID] = struct{}{}
+
+		if f.Vulnerability.FixedVersion != "" {
+			s.FixesAvailable++
+		}
+	}
+	s.AffectedPackages = len(packages)
+
+	return s
+}
+
+// HighestSeverity returns the most severe severity among the given findings
+// (see Severities), treating unrecognized severities as "Unknown". If there are
+// no findings, it returns an empty string.
+func HighestSeverity(findings []*Finding) string {
+	if len(findings) == 0 {
+		return ""
+	}
+
+	highest := len(Severities) - 1
+	for _, f := range findings {
+		if i := slices.Index(Severities, f.Vulnerability.Severity); i >= 0 && i < highest {
+			highest = i
+		}
+	}
+
+	return Severities[highest]
+}
@@ -0,0 +1,53 @@
+package scan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSummarize(t *testing.T) {
+	findings := []*Finding{
+		{Package: Package{ID: "a"}, Vulnerability: Vulnerability{ID: "CVE-1", Severity: "Critical", FixedVersion: "1.2.3"}},
+		{Package: Package{ID: "a"}, Vulnerability: Vulnerability{ID: "CVE-2", Severity: "Low"}},
+		{Package: Package{ID: "b"}, Vulnerability: Vulnerability{ID: "CVE-3", Severity: "Low", FixedVersion: "4.5.6"}},
+		{Package: Package{ID: "c"}, Vulnerability: Vulnerability{ID: "CVE-4", Severity: ""}},
+	}
+
+	expected := Summary{
+		CountsBySeverity: map[string]int{
+			"Critical":   1,
+			"High":       0,
+			"Medium":     0,
+			"Low":        2,
+			"Negligible": 0,
+			"Unknown":    1,
+		},
+		AffectedPackages: 3,
+		FixesAvailable:   2,
+		Total:            4,
+	}
+
+	assert.Equal(t, expected, Summarize(findings))
+}
+
+func TestHighestSeverity(t *testing.T) {
+	cases := []struct {
+		severities []string
+		expected   string
+	}{
+		{severities: nil, expected: ""},
+		{severities: []string{"Low", "Critical", "Medium"}, expected: "Critical"},
+		{severities: []string{"Negligible", "Low"}, expected: "Low"},
+		{severities: []string{"", "bogus"}, expected: "Unknown"},
+	}
+
+	for _, tt := range cases {
+		var findings []*Finding
+		for _, sev := range tt.severities {
+			findings = append(findings, &Finding{Vulnerability: Vulnerability{Severity: sev}})
+		}
+
+		assert.Equal(t, tt.expected, HighestSeverity(findings))
+	}
+}
@@ -0,0 +1,227 @@
+package scan
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/openvex/go-vex/pkg/vex"
+	"golang.org/x/exp/slices"
+)
+
+// VEXStatements are the statements of OpenVEX documents, used to suppress
+// findings that the documents' authors assert don't need attention.
+type VEXStatements struct {
+	// byVulnerability indexes the statements by their vulnerability ID.
+	byVulnerability map[string][]vexStatement
+}
+
+type vexStatement struct {
+	vex.Statement
+
+	// order is the statement's position across all documents, so that among
+	// statements with the same timestamp, the one given last wins.
+	order int
+}
+
+// SuppressedFinding is a finding that was suppressed by a VEX statement.
+type SuppressedFinding struct {
+	Finding   *Finding      `json:"finding"`
+	Statement vex.Statement `json:"statement"`
+}
+
+// NewVEXStatements returns the statements of the given OpenVEX documents.
+// Statements without a timestamp take their document's.
+func NewVEXStatements(docs []*vex.VEX) *VEXStatements {
+	s := &VEXStatements{byVulnerability: make(map[string][]vexStatement)}
+
+	var order int
+	for _, doc := range docs {
+		for _, stmt := range doc.Statements {
+			if stmt.Timestamp == nil || stmt.Timestamp.IsZero() {
+				stmt.Timestamp = doc.Timestamp
+			}
+			s.byVulnerability[stmt.Vulnerability] = append(s.byVulnerability[stmt.Vulnerability], vexStatement{Statement: stmt, order: order})
+			order++
+		}
+	}
+
+	return s
+}
+
+// ReadVEXFiles reads the OpenVEX documents (in JSON) at the given paths. It
+// returns nil if no paths are given.
+func ReadVEXFiles(paths []string) (*VEXStatements, error) {
+	if len(paths) == 0 {
+		return nil, nil
+	}
+
+	var docs []*vex.VEX
+	for _, path := range paths {
+		doc, err := vex.Load(path)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read VEX document %q: %w", path, err)
+		}
+		docs = append(docs, doc)
+	}
+
+	return NewVEXStatements(docs), nil
+}
+
+// Filter returns the findings that aren't suppressed, and those that are.
+//
+// A statement applies to a finding if its vulnerability is the finding's
+// vulnerability (or one of its aliases), and one of its products is the
+// finding's package or the scanned APK, which may be nil. Products are compared
+// as package URLs, ignoring qualifiers and subpaths; a product without a
+// version applies to every version. If the statement lists subcomponents, one
+// of them must also be the finding's package.
+//
+// Of the statements that apply to a finding, the latest decides: the finding is
+// suppressed if its status is not_affected or fixed. A nil VEXStatements keeps
+// all findings.
+func (s *VEXStatements) Filter(apk *PackageInfo, findings []*Finding) (kept []*Finding, suppressed []SuppressedFinding) {
+	if s == nil {
+		return findings, nil
+	}
+
+	for _, f := range findings {
+		stmt := s.latest(apk, f)
+		if stmt != nil && (stmt.Status == vex.StatusNotAffected || stmt.Status == vex.StatusFixed) {
+			suppressed = append(suppressed, SuppressedFinding{Finding: f, Statement: stmt.Statement})
+			continue
+		}
+		kept = append(kept, f)
+	}
+
+	return kept, suppressed
+}
+
+// latest returns the latest statement that applies to the finding, or nil if
+// there is none.
+func (s *VEXStatements) latest(apk *PackageInfo, f *Finding) *vexStatement {
+	var latest *vexStatement
+
+	for _, id := range append([]string{f.Vulnerability.ID}, f.Vulnerability.Aliases...) {
+		for i := range s.byVulnerability[id] {
+			stmt := &s.byVulnerability[id][i]
+			if !stmt.appliesTo(apk, f) {
+				continue
+			}
+
+			if latest == nil || laterVEXStatement(stmt, latest) {
+				latest = stmt
+			}
+		}
+	}
+
+	return latest
+}
+
+func (stmt vexStatement) appliesTo(apk *PackageInfo, f *Finding) bool {
+	findingPURL := parseVEXProduct(f.Package.PURL)
+
+	product := slices.ContainsFunc(stmt.Products, func(p string) bool {
+		product := parseVEXProduct(p)
+		if product.matches(findingPURL) {
+			return true
+		}
+
+		return apk != nil && product.typ == "apk" && product.namespace == vexDistro(apk) && product.name == apk.Name && (product.version == "" || product.version == apk.Version)
+	})
+	if !product {
+		return false
+	}
+
+	if len(stmt.Subcomponents) == 0 {
+		return true
+	}
+
+	return slices.ContainsFunc(stmt.Subcomponents, func(sc string) bool {
+		return parseVEXProduct(sc).matches(findingPURL)
+	})
+}
+
+// defaultVEXDistro is the distro that scanned APKs are assumed to come from,
+// for matching VEX statements' apk package URLs, when their distro is unknown.
+const defaultVEXDistro = "wolfi"
+
+// vexDistro returns the purl namespace that a VEX statement's apk product must
+// have to apply to the given APK, so that e.g. a statement about Alpine's foo
+// doesn't suppress findings for Wolfi's foo.
+func vexDistro(apk *PackageInfo) string {
+	if apk.Distro == "" {
+		return defaultVEXDistro
+	}
+
+	return apk.Distro
+}
+
+// laterVEXStatement reports whether a is later than b: it has a later
+// timestamp, or the same timestamp and was given after b.
+func laterVEXStatement(a, b *vexStatement) bool {
+	ta, tb := vexStatementTime(a), vexStatementTime(b)
+	if !ta.Equal(tb) {
+		return ta.After(tb)
+	}
+	return a.order > b.order
+}
+
+func vexStatementTime(stmt *vexStatement) time.Time {
+	if stmt.Timestamp == nil {
+		return time.Time{}
+	}
+	return *stmt.Timestamp
+}
+
+// vexProduct is a package URL identifying a VEX statement's product, without
+// its qualifiers and subpath.
+type vexProduct struct {
+	typ, namespace, name, version string
+}
+
+// parseVEXProduct parses the given package URL. Anything that isn't a package
+// URL yields the zero vexProduct, which matches nothing.
+func parseVEXProduct(purl string) vexProduct {
+	rest, ok := strings.CutPrefix(purl, "pkg:")
+	if !ok {
+		return vexProduct{}
+	}
+
+	rest, _, _ = strings.Cut(rest, "#")
+	rest, _, _ = strings.Cut(rest, "?")
+
+	var p vexProduct
+	if i := strings.LastIndex(rest, "@"); i >= 0 {
+		rest, p.version = rest[:i], unescapePURLPart(rest[i+1:])
+	}
+
+	parts := strings.Split(strings.Trim(rest, "/"), "/")
+	if len(parts) < 2 {
+		return vexProduct{}
+	}
+
+	p.typ = strings.ToLower(parts[0])
+	p.name = unescapePURLPart(parts[len(parts)-1])
+	p.namespace = unescapePURLPart(strings.Join(parts[1:len(parts)-1], "/"))
+
+	return p
+}
+
+func unescapePURLPart(s string) string {
+	if u, err := url.PathUnescape(s); err == nil {
+		return u
+	}
+	return s
+}
+
+// matches reports whether the product is the given package, which has a
+// version. A product without a version matches every version.
+func (p vexProduct) matches(pkg vexProduct) bool {
+	if p.typ == "" || p.typ != pkg.typ || p.namespace != pkg.namespace || p.name != pkg.name {
+		return false
+	}
+
+	return p.version == "" || p.version == pkg.version
+}
@@ -0,0 +1,78 @@
+package scan
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openvex/go-vex/pkg/vex"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVEXStatements_Filter(t *testing.T) {
+	ts1, ts2 := time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC), time.Date(2023, 5, 2, 0, 0, 0, 0, time.UTC)
+
+	a := vex.New()
+	a.Timestamp = &ts1
+	a.Statements = []vex.Statement{
+		// Superseded by the later statement in b.
+		{Vulnerability: "CVE-2023-1", Products: []string{"pkg:apk/wolfi/crane"}, Status: vex.StatusNotAffected, Justification: vex.VulnerableCodeNotInExecutePath},
+		{Vulnerability: "CVE-2023-2", Products: []string{"pkg:apk/wolfi/crane@0.15.2-r0"}, Status: vex.StatusFixed},
+		{Vulnerability: "CVE-2023-3", Products: []string{"pkg:apk/wolfi/crane@0.15.1-r0"}, Status: vex.StatusFixed},
+		{Vulnerability: "GHSA-4", Products: []string{"pkg:golang/golang.org/x/net@v0.7.0"}, Status: vex.StatusNotAffected, ImpactStatement: "not used"},
+	}
+
+	b := vex.New()
+	b.Timestamp = &ts2
+	b.Statements = []vex.Statement{
+		{Vulnerability: "CVE-2023-1", Products: []string{"pkg:apk/wolfi/crane"}, Status: vex.StatusAffected, ActionStatement: "upgrade"},
+	}
+
+	apk := &PackageInfo{Name: "crane", Version: "0.15.2-r0"}
+	net := Package{Name: "golang.org/x/net", Version: "v0.7.0", Type: "go-module", PURL: "pkg:golang/golang.org/x/net@v0.7.0?type=module"}
+	findings := []*Finding{
+		{Package: net, Vulnerability: Vulnerability{ID: "GHSA-1", Aliases: []string{"CVE-2023-1"}}},
+		{Package: net, Vulnerability: Vulnerability{ID: "GHSA-2", Aliases: []string{"CVE-2023-2"}}},
+		{Package: net, Vulnerability: Vulnerability{ID: "GHSA-3", Aliases: []string{"CVE-2023-3"}}},
+		{Package: net, Vulnerability: Vulnerability{ID: "GHSA-4"}},
+	}
+
+	statements := NewVEXStatements([]*vex.VEX{&a, &b})
+	kept, suppressed := statements.Filter(apk, findings)
+	assert.Equal(t, []*Finding{findings[0], findings[2]}, kept)
+	if assert.Len(t, suppressed, 2) {
+		assert.Equal(t, findings[1], suppressed[0].Finding)
+		assert.Equal(t, vex.StatusFixed, suppressed[0].Statement.Status)
+		assert.Equal(t, &ts1, suppressed[0].Statement.Timestamp)
+		assert.Equal(t, findings[3], suppressed[1].Finding)
+	}
+
+	// Without the scanned APK, only statements about the findings' own packages apply.
+	kept, suppressed = statements.Filter(nil, findings)
+	assert.Len(t, kept, 3)
+	assert.Len(t, suppressed, 1)
+
+	// Statements about another distro's package of the same name don't apply.
+	alpine := vex.New()
+	alpine.Timestamp = &ts2
+	alpine.Statements = []vex.Statement{
+		{Vulnerability: "CVE-2023-3", Products: []string{"pkg:apk/alpine/crane"}, Status: vex.StatusNotAffected, Justification: vex.ComponentNotPresent},
+	}
+	withAlpine := NewVEXStatements([]*vex.VEX{&a, &b, &alpine})
+	kept, _ = withAlpine.Filter(apk, findings)
+	assert.Contains(t, kept, findings[2])
+	kept, _ = withAlpine.Filter(&PackageInfo{Name: "crane", Version: "0.15.2-r0", Distro: "wolfi"}, findings)
+	assert.Contains(t, kept, findings[2])
+	kept, _ = withAlpine.Filter(&PackageInfo{Name: "crane", Version: "0.15.2-r0", Distro: "alpine"}, findings)
+	assert.NotContains(t, kept, findings[2])
+
+	var nilStatements *VEXStatements
+	kept, suppressed = nilStatements.Filter(apk, findings)
+	assert.Equal(t, findings, kept)
+	assert.Empty(t, suppressed)
+}
+
+func TestParseVEXProduct(t *testing.T) {
+	assert.Equal(t, vexProduct{typ: "npm", namespace: "@angular", name: "core", version: "1.0.0"}, parseVEXProduct("pkg:npm/%40angular/core@1.0.0?foo=bar#sub"))
+	assert.Equal(t, vexProduct{typ: "apk", namespace: "wolfi", name: "crane"}, parseVEXProduct("pkg:apk/wolfi/crane"))
+	assert.Equal(t, vexProduct{}, parseVEXProduct("crane"))
+}
@@ -0,0 +1,118 @@
+package scan
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/anchore/grype/grype/db"
+	v5 "github.com/anchore/grype/grype/db/v5"
+	v5store "github.com/anchore/grype/grype/db/v5/store"
+	"github.com/samber/lo"
+)
+
+var cveIDPattern = regexp.MustCompile(`^CVE-(\d{4})-(\d+)$`)
+
+// RecentCVEIDs returns the IDs of the CVEs in the vulnerability database that
+// scans use, which must already have been downloaded (e.g. by a scan), that
+// were assigned in the given year or later. They're sorted newest first.
+func RecentCVEIDs(sinceYear int) ([]string, error) {
+	metadata, err := allVulnerabilityMetadata()
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(metadata))
+	for i := range metadata {
+		ids = append(ids, metadata[i].ID)
+	}
+
+	return recentCVEIDs(ids, sinceYear), nil
+}
+
+// VulnerabilitySeverities returns the severity (see Severities) of each
+// vulnerability in the vulnerability database that scans use, which must
+// already have been downloaded (e.g. by a scan), keyed by vulnerability ID.
+// Where the database has more than one severity for an ID, NVD's is used.
+func VulnerabilitySeverities() (map[string]string, error) {
+	metadata, err := allVulnerabilityMetadata()
+	if err != nil {
+		return nil, err
+	}
+
+	severities := make(map[string]string, len(metadata))
+	for i := range metadata {
+		m := metadata[i]
+		if _, ok := severities[m.ID]; ok && m.Namespace != nvdNamespace {
+			continue
+		}
+		severities[m.ID] = m.Severity
+	}
+
+	return severities, nil
+}
+
+const nvdNamespace = "nvd:cpe"
+
+func allVulnerabilityMetadata() ([]v5.VulnerabilityMetadata, error) {
+	curator, err := db.NewCurator(grypeDBConfig)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open vulnerability database: %w", err)
+	}
+
+	status := curator.Status()
+	if status.Err != nil {
+		return nil, fmt.Errorf("unable to read vulnerability database status: %w", status.Err)
+	}
+
+	s, err := v5store.New(path.Join(status.Location, "vulnerability.db"), false)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open vulnerability database: %w", err)
+	}
+	defer s.Close()
+
+	metadata, err := s.GetAllVulnerabilityMetadata()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read vulnerability metadata: %w", err)
+	}
+
+	return *metadata, nil
+}
+
+// recentCVEIDs returns the distinct CVE IDs among the given IDs that were
+// assigned in the given year or later, sorted newest first.
+func recentCVEIDs(ids []string, sinceYear int) []string {
+	type cveID struct {
+		id           string
+		year, number int
+	}
+
+	var cves []cveID
+	for _, id := range lo.Uniq(ids) {
+		m := cveIDPattern.FindStringSubmatch(id)
+		if m == nil {
+			continue
+		}
+
+		year, _ := strconv.Atoi(m[1])   //nolint:errcheck // guaranteed by the pattern
+		number, _ := strconv.Atoi(m[2]) //nolint:errcheck // guaranteed by the pattern
+		if year < sinceYear {
+			continue
+		}
+
+		cves = append(cves, cveID{id: id, year: year, number: number})
+	}
+
+	sort.Slice(cves, func(i, j int) bool {
+		if cves[i].year != cves[j].year {
+			return cves[i].year > cves[j].year
+		}
+		return cves[i].number > cves[j].number
+	})
+
+	return lo.Map(cves, func(c cveID, _ int) string {
+		return c.id
+	})
+}
@@ -0,0 +1,21 @@
+package scan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_recentCVEIDs(t *testing.T) {
+	ids := []string{
+		"CVE-2022-1234",
+		"GHSA-xxxx-yyyy-zzzz",
+		"CVE-2023-999",
+		"CVE-2023-10000",
+		"CVE-2021-5",
+		"CVE-2023-999",
+	}
+
+	assert.Equal(t, []string{"CVE-2023-10000", "CVE-2023-999", "CVE-2022-1234"}, recentCVEIDs(ids, 2022))
+	assert.Empty(t, recentCVEIDs(ids, 2024))
+}
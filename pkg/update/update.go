@@ -50,6 +50,10 @@ type Options struct {
 	GitHubHTTPClient       *http2.RLHTTPClient
 	ErrorMessages          map[string]string
 	IssueLabels            []string
+
+	// VersionPolicy limits which new versions are proposed for
+	// PullRequestBaseBranch (see VersionPolicies). Empty means any newer version.
+	VersionPolicy string
 }
 
 type NewVersionResults struct {
@@ -113,12 +117,24 @@ func (o *Options) Update() error {
 		Auth:              wgit.GetGitAuth(),
 		Depth:             1,
 	}
+	if o.PullRequestBaseBranch != "" {
+		// work from the branch that pull requests will target, which may not be the default branch
+		cloneOpts.ReferenceName = plumbing.NewBranchReferenceName(o.PullRequestBaseBranch)
+		cloneOpts.SingleBranch = true
+	}
 
 	repo, err := git.PlainClone(tempDir, false, cloneOpts)
 	if err != nil {
 		return fmt.Errorf("failed to clone repository %s into %s: %w", o.RepoURI, tempDir, err)
 	}
 
+	// fail early, rather than part way through proposing changes, if labels we'd apply don't exist
+	if !o.DryRun && len(o.IssueLabels) > 0 {
+		if err := o.checkLabelsExist(repo); err != nil {
+			return err
+		}
+	}
+
 	// get the latest upstream versions available
 	latestVersions, err := o.GetLatestVersions(tempDir, o.PackageNames)
 	if err != nil {
@@ -440,6 +456,22 @@ func (o *Options) createBranch(repo *git.Repository) (plumbing.ReferenceName, er
 	return newBranchRef.Name(), nil
 }
 
+// checkLabelsExist returns an error if any of the labels to apply to issues and
+// pull requests are missing from the repository.
+func (o *Options) checkLabelsExist(repo *git.Repository) error {
+	gitURL, err := wgit.GetRemoteURL(repo)
+	if err != nil {
+		return fmt.Errorf("failed to find git origin URL: %w", err)
+	}
+
+	gitOpts := gh.GitOptions{
+		GithubClient: github.NewClient(o.GitHubHTTPClient.Client),
+		Logger:       o.Logger,
+	}
+
+	return gitOpts.CheckLabelsExist(context.Background(), gitURL.Organisation, gitURL.Name, o.IssueLabels)
+}
+
 // commits package update changes and creates a pull request
 func (o *Options) proposeChanges(repo *git.Repository, ref plumbing.ReferenceName, packageName string, newVersion NewVersionResults) (string, error) {
 	gitURL, err := wgit.GetRemoteURL(repo)
@@ -689,6 +721,13 @@ func (o *Options) getPackagesToUpdate(latestVersions map[string]NewVersionResult
 				c.Package.Name, latestVersionSemver.Original(),
 			)
 		}
+		if currentVersionSemver.LessThan(latestVersionSemver) && !allowedByPolicy(o.VersionPolicy, currentVersionSemver, latestVersionSemver) {
+			o.Logger.Printf(
+				"%s: new version %s is not allowed by the %s version policy for branch %s, current version %s",
+				c.Package.Name, latestVersionSemver.Original(), o.VersionPolicy, o.PullRequestBaseBranch, c.Package.Version,
+			)
+			continue
+		}
 		if currentVersionSemver.LessThan(latestVersionSemver) {
 			o.Logger.Println(
 				color.GreenString(
@@ -737,6 +776,11 @@ func (o *Options) processPullRequests(updates map[string]NewVersionResults, prs
 	for _, pr := range prs {
 		prTitle := *pr.Title
 
+		// pull requests against other branches are for other release streams
+		if base := pr.GetBase().GetRef(); base != "" && o.PullRequestBaseBranch != "" && base != o.PullRequestBaseBranch {
+			continue
+		}
+
 		packageName, titleVersion, err := extractPackageVersionFromTitle(prTitle)
 		if err != nil {
 			// ignore if we can't extract a package name and version string
@@ -0,0 +1,58 @@
+package vuln
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+const osvAPIBaseURL = "https://api.osv.dev/v1"
+
+// AliasFinder looks up the aliases of vulnerabilities (e.g. the CVE ID of a
+// GHSA advisory) using the OSV API.
+type AliasFinder struct {
+	client  *http.Client
+	baseURL string
+}
+
+// NewAliasFinder returns an AliasFinder that uses the given HTTP client.
+func NewAliasFinder(client *http.Client) *AliasFinder {
+	return &AliasFinder{
+		client:  client,
+		baseURL: osvAPIBaseURL,
+	}
+}
+
+// Aliases returns the known aliases of the given vulnerability, not including
+// the ID itself. It returns nil if the vulnerability isn't known.
+func (f *AliasFinder) Aliases(ctx context.Context, id string) ([]string, error) {
+	url := fmt.Sprintf("%s/vulns/%s", f.baseURL, id)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to look up aliases of %s: %w", id, err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unable to look up aliases of %s: GET %s: %d", id, url, resp.StatusCode)
+	}
+
+	var v struct {
+		Aliases []string `json:"aliases"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&v); err != nil {
+		return nil, fmt.Errorf("unable to decode OSV data for %s: %w", id, err)
+	}
+
+	return v.Aliases, nil
+}
@@ -0,0 +1,39 @@
+package vuln
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAliasFinder_Aliases(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/vulns/GHSA-2h5h-59f5-c5x9":
+			_, _ = w.Write([]byte(`{"id":"GHSA-2h5h-59f5-c5x9","aliases":["CVE-2023-1234"]}`))
+		case "/vulns/CVE-2023-5000":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	f := NewAliasFinder(server.Client())
+	f.baseURL = server.URL
+
+	aliases, err := f.Aliases(context.Background(), "GHSA-2h5h-59f5-c5x9")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"CVE-2023-1234"}, aliases)
+
+	aliases, err = f.Aliases(context.Background(), "CVE-2023-0000")
+	require.NoError(t, err)
+	assert.Nil(t, aliases)
+
+	_, err = f.Aliases(context.Background(), "CVE-2023-5000")
+	assert.Error(t, err)
+}
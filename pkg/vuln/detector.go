@@ -2,6 +2,7 @@ package vuln
 
 import (
 	"context"
+	"time"
 
 	version "github.com/knqyf263/go-apk-version"
 )
@@ -10,6 +11,13 @@ type Detector interface {
 	VulnerabilitiesForPackages(context.Context, ...string) (map[string][]Match, error)
 }
 
+// RecentDetector is a Detector that can also match only the vulnerabilities
+// published since a given time, without looking up each package separately.
+type RecentDetector interface {
+	Detector
+	RecentVulnerabilitiesForPackages(ctx context.Context, since time.Time, packages ...string) (map[string][]Match, error)
+}
+
 type Match struct {
 	Package       Package
 	CPE           CPE
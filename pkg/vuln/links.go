@@ -0,0 +1,97 @@
+package vuln
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LinkTemplates maps vulnerability ID prefixes (e.g. "CVE-") to templates of
+// the URL at which to view a vulnerability with such an ID. Each occurrence of
+// "{id}" in a template is replaced with the vulnerability ID.
+type LinkTemplates map[string]string
+
+// DefaultLinkTemplates are the link templates used unless overridden.
+var DefaultLinkTemplates = LinkTemplates{
+	"CVE-":    "https://nvd.nist.gov/vuln/detail/{id}",
+	"GHSA-":   "https://github.com/advisories/{id}",
+	"CGA-":    "https://images.chainguard.dev/security/{id}",
+	"ALPINE-": "https://security.alpinelinux.org/vuln/{id}",
+	"GO-":     "https://pkg.go.dev/vuln/{id}",
+}
+
+// linkTemplatesFile is the format of a link templates file, e.g.:
+//
+//	links:
+//	  CVE-: https://vulns.example.com/{id}
+//	  INTERNAL-: https://vulns.example.com/internal/{id}
+type linkTemplatesFile struct {
+	Links LinkTemplates `yaml:"links"`
+}
+
+// ReadLinkTemplates reads link templates from the YAML file at the given path.
+func ReadLinkTemplates(path string) (LinkTemplates, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read link templates: %w", err)
+	}
+
+	var f linkTemplatesFile
+	if err := yaml.Unmarshal(b, &f); err != nil {
+		return nil, fmt.Errorf("unable to parse link templates %q: %w", path, err)
+	}
+
+	if err := f.Links.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid link templates %q: %w", path, err)
+	}
+
+	return f.Links, nil
+}
+
+// Validate returns an error if any prefix is empty or any template doesn't
+// include "{id}".
+func (t LinkTemplates) Validate() error {
+	for prefix, tmpl := range t {
+		if prefix == "" {
+			return fmt.Errorf("prefix cannot be empty")
+		}
+		if !strings.Contains(tmpl, "{id}") {
+			return fmt.Errorf("template for %q must include {id}", prefix)
+		}
+	}
+
+	return nil
+}
+
+// With returns a copy of t, with the given templates added, replacing any for
+// the same prefixes.
+func (t LinkTemplates) With(overrides LinkTemplates) LinkTemplates {
+	merged := make(LinkTemplates, len(t)+len(overrides))
+	for prefix, tmpl := range t {
+		merged[prefix] = tmpl
+	}
+	for prefix, tmpl := range overrides {
+		merged[prefix] = tmpl
+	}
+	return merged
+}
+
+// URL returns the URL for the vulnerability with the given ID, using the
+// template with the longest prefix that matches the ID. It returns an empty
+// string if no prefix matches.
+func (t LinkTemplates) URL(id string) string {
+	var match string
+	for prefix := range t {
+		if strings.HasPrefix(id, prefix) && len(prefix) > len(match) {
+			match = prefix
+		}
+	}
+
+	if match == "" {
+		return ""
+	}
+
+	return strings.ReplaceAll(t[match], "{id}", id)
+}
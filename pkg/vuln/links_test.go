@@ -0,0 +1,40 @@
+package vuln
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLinkTemplates_URL(t *testing.T) {
+	overrides, err := ReadLinkTemplates("testdata/links.yaml")
+	require.NoError(t, err)
+
+	templates := DefaultLinkTemplates.With(overrides)
+
+	tests := []struct {
+		id, expected string
+	}{
+		{id: "CVE-2023-1234", expected: "https://vulns.example.com/CVE-2023-1234"},
+		{id: "ACME-42", expected: "https://vulns.example.com/internal/ACME-42"},
+		{id: "GHSA-abcd-efgh-ijkl", expected: "https://github.com/advisories/GHSA-abcd-efgh-ijkl"},
+		{id: "GO-2023-1571", expected: "https://pkg.go.dev/vuln/GO-2023-1571"},
+		{id: "OSV-2023-1", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			assert.Equal(t, tt.expected, templates.URL(tt.id))
+		})
+	}
+
+	// The defaults are left untouched.
+	assert.Equal(t, "https://nvd.nist.gov/vuln/detail/CVE-2023-1234", DefaultLinkTemplates.URL("CVE-2023-1234"))
+}
+
+func TestLinkTemplates_Validate(t *testing.T) {
+	assert.NoError(t, DefaultLinkTemplates.Validate())
+	assert.Error(t, LinkTemplates{"CVE-": "https://example.com"}.Validate())
+	assert.Error(t, LinkTemplates{"": "https://example.com/{id}"}.Validate())
+}
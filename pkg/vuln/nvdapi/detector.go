@@ -19,7 +19,7 @@ import (
 	"golang.org/x/time/rate"
 )
 
-var _ vuln.Detector = (*Detector)(nil)
+var _ vuln.RecentDetector = (*Detector)(nil)
 
 type Detector struct {
 	client          *http.Client
@@ -208,6 +208,37 @@ func determineVulnMatch(cve *Cve, packageName, requestCPE string) (*vuln.Match,
 	return nil, nil
 }
 
+// MatchPackage returns the match between the CVE's vulnerable CPEs (with known
+// version ranges) and the named package, or nil if none of them are for the
+// package. The package's CPE is determined the same way as for
+// VulnerabilitiesForPackages.
+func (s *Detector) MatchPackage(cve *Cve, packageName string) (*vuln.Match, error) {
+	return determineVulnMatch(cve, packageName, s.getCPE(packageName))
+}
+
+// VulnerableProducts returns the distinct "vendor:product" pairs of the CVE's
+// vulnerable CPEs.
+func VulnerableProducts(cve *Cve) []string {
+	var products []string
+	for _, configuration := range cve.Configurations {
+		for _, node := range configuration.Nodes {
+			for _, cpeMatch := range node.CpeMatch {
+				if !cpeMatch.Vulnerable {
+					continue
+				}
+
+				cpe, err := wfn.Parse(cpeMatch.Criteria)
+				if err != nil {
+					continue
+				}
+				products = append(products, fmt.Sprintf("%s:%s", cpe.Vendor, cpe.Product))
+			}
+		}
+	}
+
+	return lo.Uniq(products)
+}
+
 func cpeStringsMatch(requestCPE, responseCPE string) (bool, error) {
 	req, err := wfn.Parse(requestCPE)
 	if err != nil {
@@ -231,11 +262,6 @@ func cpeStringsMatch(requestCPE, responseCPE string) (bool, error) {
 var ErrRateLimited = errors.New("we've been rate limited by NVD! 🙊")
 
 func (s *Detector) doSearch(ctx context.Context, cpe string) ([]Cve, error) {
-	err := s.rateLimiter.Wait(ctx)
-	if err != nil {
-		return nil, err
-	}
-
 	// TODO: Deal with pages (not urgent because the default page size is 2,000
 	//  CVEs, and we're searching for single packages at a time.)
 
@@ -244,11 +270,133 @@ func (s *Detector) doSearch(ctx context.Context, cpe string) ([]Cve, error) {
 	//  for '...*:go...' multiple times because we've pruned versions from multiple,
 	//  related packages like 'go-1.18', 'go-1.19', and 'go-1.20'.
 
+	return s.doRequest(ctx, "virtualMatchString="+cpe)
+}
+
+// maxPublishedRange is the longest publication date range NVD allows in a
+// single query.
+const maxPublishedRange = 120 * 24 * time.Hour
+
+// nvdTimeFormat is the format of the timestamps used in NVD API queries.
+const nvdTimeFormat = "2006-01-02T15:04:05.000Z"
+
+// PublishedCVEs fetches the CVEs published between since and until, following
+// NVD's paging and splitting longer ranges into the maximum range NVD allows
+// per query. This method's requests to the NVD API are constrained by the
+// Detector's configured rate limiter.
+func (s *Detector) PublishedCVEs(ctx context.Context, since, until time.Time) ([]Cve, error) {
+	var cves []Cve
+
+	for start := since.UTC(); start.Before(until); start = start.Add(maxPublishedRange) {
+		end := start.Add(maxPublishedRange)
+		if end.After(until) {
+			end = until.UTC()
+		}
+
+		for startIndex := 0; ; {
+			query := fmt.Sprintf(
+				"pubStartDate=%s&pubEndDate=%s&startIndex=%d",
+				start.Format(nvdTimeFormat),
+				end.Format(nvdTimeFormat),
+				startIndex,
+			)
+
+			page, err := s.doRequestPage(ctx, query)
+			if err != nil {
+				return nil, err
+			}
+
+			cves = append(cves, lo.Map(page.Vulnerabilities, vulnerabilityToCve)...)
+
+			startIndex += len(page.Vulnerabilities)
+			if len(page.Vulnerabilities) == 0 || startIndex >= page.TotalResults {
+				break
+			}
+		}
+	}
+
+	return cves, nil
+}
+
+// RecentVulnerabilitiesForPackages matches the CVEs published since the given
+// time against the given packages, using the same CPEs as
+// VulnerabilitiesForPackages. Unlike VulnerabilitiesForPackages, it makes
+// requests per page of recently published CVEs, not per package, which makes it
+// suited to catching new disclosures across all packages quickly. It returns a
+// map of package names to slices of vulnerability matches for that package.
+func (s *Detector) RecentVulnerabilitiesForPackages(ctx context.Context, since time.Time, packages ...string) (map[string][]vuln.Match, error) {
+	cves, err := s.PublishedCVEs(ctx, since, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("📰 %d CVEs published since %s", len(cves), since.Format(time.RFC3339))
+
+	matchesByPackage := make(map[string][]vuln.Match)
+	for _, pkg := range packages {
+		requestCPE := s.getCPE(pkg)
+
+		for i := range cves {
+			match, err := determineVulnMatch(&cves[i], pkg, requestCPE)
+			if err != nil {
+				return nil, err
+			}
+			if match == nil {
+				continue
+			}
+
+			matchesByPackage[pkg] = append(matchesByPackage[pkg], *match)
+		}
+
+		if count := len(matchesByPackage[pkg]); count >= 1 {
+			log.Printf("🤔 %s: potential CVE matches: %d", pkg, count)
+		}
+	}
+
+	return matchesByPackage, nil
+}
+
+// ErrCVENotFound is returned by CVE when NVD has no record of the requested CVE.
+var ErrCVENotFound = errors.New("CVE not found in NVD")
+
+// CVE fetches the current NVD record for the CVE with the given ID. This
+// method's requests to the NVD API are constrained by the Detector's configured
+// rate limiter.
+func (s *Detector) CVE(ctx context.Context, id string) (*Cve, error) {
+	cves, err := s.doRequest(ctx, "cveId="+id)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(cves) == 0 {
+		return nil, fmt.Errorf("%s: %w", id, ErrCVENotFound)
+	}
+
+	return &cves[0], nil
+}
+
+func (s *Detector) doRequest(ctx context.Context, query string) ([]Cve, error) {
+	cvesResponse, err := s.doRequestPage(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	cves := lo.Map(cvesResponse.Vulnerabilities, vulnerabilityToCve)
+
+	return cves, nil
+}
+
+func (s *Detector) doRequestPage(ctx context.Context, query string) (*CVEsResponse, error) {
+	err := s.rateLimiter.Wait(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	reqURL := fmt.Sprintf(
-		"https://%s%s?virtualMatchString=%s",
+		"https://%s%s?%s",
 		s.serviceHost,
 		s.serviceEndpoint,
-		cpe,
+		query,
 	)
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
@@ -283,9 +431,7 @@ func (s *Detector) doSearch(ctx context.Context, cpe string) ([]Cve, error) {
 		return nil, fmt.Errorf("unable to decode JSON response to URL %q: %w", reqURL, err)
 	}
 
-	cves := lo.Map(cvesResponse.Vulnerabilities, vulnerabilityToCve)
-
-	return cves, nil
+	return &cvesResponse, nil
 }
 
 var errNoVersionData = errors.New("CPE has no version data available")
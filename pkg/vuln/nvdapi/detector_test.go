@@ -2,6 +2,7 @@ package nvdapi
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -9,6 +10,7 @@ import (
 	"net/url"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/samber/lo"
 	"github.com/stretchr/testify/assert"
@@ -75,3 +77,47 @@ func TestDetector_VulnerabilitiesForPackages(t *testing.T) {
 func vulnMatchToCVE(vuln vuln.Match, _ int) string {
 	return vuln.Vulnerability.ID
 }
+
+func TestDetector_RecentVulnerabilitiesForPackages(t *testing.T) {
+	// Serve the brotli and libbpf test data as two pages of recently published
+	// CVEs.
+	pages := []string{"brotli", "libbpf"}
+	var queries []url.Values
+
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query())
+
+		startIndex := r.URL.Query().Get("startIndex")
+		page := 0
+		if startIndex != "0" {
+			page = 1
+		}
+
+		b, err := os.ReadFile(fmt.Sprintf("testdata/%s.json", pages[page]))
+		require.NoError(t, err)
+
+		var resp CVEsResponse
+		require.NoError(t, json.Unmarshal(b, &resp))
+		resp.StartIndex = page
+		resp.TotalResults = 1 + 2 // brotli's one CVE, plus libbpf's two
+		require.NoError(t, json.NewEncoder(w).Encode(resp))
+	}))
+	defer ts.Close()
+
+	parsedURL, err := url.Parse(ts.URL)
+	require.NoError(t, err)
+
+	detector := NewDetector(ts.Client(), parsedURL.Host, "some-api-key")
+
+	since := time.Now().Add(-24 * time.Hour)
+	vulns, err := detector.RecentVulnerabilitiesForPackages(context.Background(), since, "brotli", "libbpf", "libev")
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []string{"CVE-2020-8927"}, lo.Map(vulns["brotli"], vulnMatchToCVE))
+	assert.ElementsMatch(t, []string{"CVE-2021-45940", "CVE-2021-45941"}, lo.Map(vulns["libbpf"], vulnMatchToCVE))
+	assert.Empty(t, vulns["libev"])
+
+	require.Len(t, queries, 2)
+	assert.Equal(t, since.UTC().Format(nvdTimeFormat), queries[0].Get("pubStartDate"))
+	assert.Equal(t, "1", queries[1].Get("startIndex"))
+}
@@ -0,0 +1,96 @@
+package nvdwatch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+const githubAPIBaseURL = "https://api.github.com"
+
+// GHSAClient looks up GitHub Security Advisories (GHSAs) using the GitHub REST
+// API's global advisories endpoint.
+type GHSAClient struct {
+	client  *http.Client
+	baseURL string
+}
+
+// NewGHSAClient returns a GHSAClient that uses the given HTTP client, which
+// should be authenticated to avoid GitHub's low rate limit for unauthenticated
+// requests.
+func NewGHSAClient(client *http.Client) *GHSAClient {
+	return &GHSAClient{
+		client:  client,
+		baseURL: githubAPIBaseURL,
+	}
+}
+
+// GHSA is the subset of a GitHub Security Advisory that is meaningful for
+// triage.
+type GHSA struct {
+	ID          string `json:"ghsa_id"`
+	UpdatedAt   string `json:"updated_at"`
+	Severity    string `json:"severity"`
+	Description string `json:"description"`
+	CVSS        *struct {
+		Score float64 `json:"score"`
+	} `json:"cvss"`
+	Vulnerabilities []struct {
+		Package struct {
+			Ecosystem string `json:"ecosystem"`
+			Name      string `json:"name"`
+		} `json:"package"`
+		VulnerableVersionRange string `json:"vulnerable_version_range"`
+	} `json:"vulnerabilities"`
+}
+
+// Advisory returns the GHSA with the given ID.
+func (c *GHSAClient) Advisory(ctx context.Context, id string) (*GHSA, error) {
+	url := fmt.Sprintf("%s/advisories/%s", c.baseURL, id)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get %s: %w", id, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to get %s: GET %s: %d", id, url, resp.StatusCode)
+	}
+
+	var a GHSA
+	if err := json.NewDecoder(resp.Body).Decode(&a); err != nil {
+		return nil, fmt.Errorf("unable to decode GitHub advisory %s: %w", id, err)
+	}
+
+	return &a, nil
+}
+
+// NewGHSARecord extracts a Record from the given GHSA. The vulnerable version
+// ranges of the affected packages take the place of NVD's CPE ranges.
+func NewGHSARecord(a *GHSA) Record {
+	r := Record{
+		LastModified: a.UpdatedAt,
+		Severity:     strings.ToUpper(a.Severity),
+		Description:  a.Description,
+	}
+
+	if a.CVSS != nil {
+		r.BaseScore = a.CVSS.Score
+	}
+
+	for _, v := range a.Vulnerabilities {
+		r.Ranges = append(r.Ranges, fmt.Sprintf("%s:%s (%s)", v.Package.Ecosystem, v.Package.Name, v.VulnerableVersionRange))
+	}
+	sort.Strings(r.Ranges)
+
+	return r
+}
@@ -0,0 +1,275 @@
+// Package nvdwatch records snapshots of the NVD metadata for a set of CVEs (and
+// the GitHub Security Advisory metadata for a set of GHSAs), and reports how
+// that metadata changes between snapshots, so that triage decisions made
+// against older data can be revisited.
+package nvdwatch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/wolfi-dev/wolfictl/pkg/vuln/nvdapi"
+	"golang.org/x/exp/slices"
+)
+
+// Snapshot is the recorded state of the NVD metadata for a set of CVEs and the
+// GitHub metadata for a set of GHSAs, keyed by CVE or GHSA ID.
+type Snapshot struct {
+	Timestamp time.Time         `json:"timestamp"`
+	Records   map[string]Record `json:"records"`
+}
+
+// Record is the subset of a CVE's NVD metadata (or a GHSA's metadata) that is
+// meaningful for triage.
+type Record struct {
+	LastModified string   `json:"lastModified"`
+	Severity     string   `json:"severity,omitempty"`
+	BaseScore    float64  `json:"baseScore,omitempty"`
+	CPEs         []string `json:"cpes,omitempty"`
+	Description  string   `json:"description,omitempty"`
+
+	// Ranges are the vulnerable version ranges of the packages affected by a
+	// GHSA.
+	Ranges []string `json:"ranges,omitempty"`
+}
+
+// NewRecord extracts a Record from the given NVD CVE data.
+func NewRecord(cve *nvdapi.Cve) Record {
+	r := Record{
+		LastModified: cve.LastModified,
+	}
+
+	r.Severity, r.BaseScore = severity(cve.Metrics)
+
+	cpes := make(map[string]struct{})
+	for _, cfg := range cve.Configurations {
+		for _, node := range cfg.Nodes {
+			for _, m := range node.CpeMatch {
+				if !m.Vulnerable {
+					continue
+				}
+				cpes[renderCPEMatch(m)] = struct{}{}
+			}
+		}
+	}
+	r.CPEs = lo.Keys(cpes)
+	sort.Strings(r.CPEs)
+
+	for _, d := range cve.Descriptions {
+		if d.Lang == "en" {
+			r.Description = d.Value
+			break
+		}
+	}
+
+	return r
+}
+
+// severity returns the severity and base score from the most recent CVSS
+// version for which NVD has data.
+func severity(m nvdapi.Metrics) (string, float64) {
+	if len(m.CvssMetricV31) > 0 {
+		d := m.CvssMetricV31[0].CvssData
+		return d.BaseSeverity, d.BaseScore
+	}
+
+	if len(m.CvssMetricV30) > 0 {
+		d := m.CvssMetricV30[0].CvssData
+		return d.BaseSeverity, d.BaseScore
+	}
+
+	if len(m.CvssMetricV2) > 0 {
+		return m.CvssMetricV2[0].BaseSeverity, m.CvssMetricV2[0].CvssData.BaseScore
+	}
+
+	return "", 0
+}
+
+func renderCPEMatch(m nvdapi.CpeMatch) string {
+	var bounds []string
+	if v := m.VersionStartIncluding; v != "" {
+		bounds = append(bounds, ">="+v)
+	}
+	if v := m.VersionEndIncluding; v != "" {
+		bounds = append(bounds, "<="+v)
+	}
+	if v := m.VersionEndExcluding; v != "" {
+		bounds = append(bounds, "<"+v)
+	}
+
+	if len(bounds) == 0 {
+		return m.Criteria
+	}
+
+	return fmt.Sprintf("%s (%s)", m.Criteria, strings.Join(bounds, ", "))
+}
+
+// Field names used in Change.
+const (
+	FieldSeverity    = "severity"
+	FieldBaseScore   = "base score"
+	FieldCPEs        = "CPE ranges"
+	FieldRanges      = "affected version ranges"
+	FieldDescription = "description"
+)
+
+// Change describes a modification to one field of a CVE's metadata between two
+// snapshots.
+type Change struct {
+	ID       string
+	Field    string
+	Old, New string
+
+	// Material is true for changes that are likely to invalidate an existing
+	// triage decision, such as a severity upgrade or a change to the affected
+	// CPE ranges.
+	Material bool
+}
+
+// Compare returns the changes from the previous snapshot to the current one,
+// sorted by CVE ID. CVEs that weren't present in the previous snapshot are not
+// reported, since there's no earlier state to compare them against.
+func Compare(previous, current Snapshot) []Change {
+	var changes []Change
+
+	ids := lo.Keys(current.Records)
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		cur := current.Records[id]
+		prev, ok := previous.Records[id]
+		if !ok || prev.LastModified == cur.LastModified {
+			continue
+		}
+
+		if prev.Severity != cur.Severity {
+			changes = append(changes, Change{
+				ID:       id,
+				Field:    FieldSeverity,
+				Old:      prev.Severity,
+				New:      cur.Severity,
+				Material: severityRank(cur.Severity) > severityRank(prev.Severity),
+			})
+		} else if prev.BaseScore != cur.BaseScore {
+			changes = append(changes, Change{
+				ID:    id,
+				Field: FieldBaseScore,
+				Old:   fmt.Sprintf("%.1f", prev.BaseScore),
+				New:   fmt.Sprintf("%.1f", cur.BaseScore),
+			})
+		}
+
+		if !slices.Equal(prev.CPEs, cur.CPEs) {
+			changes = append(changes, Change{
+				ID:       id,
+				Field:    FieldCPEs,
+				Old:      strings.Join(prev.CPEs, "\n"),
+				New:      strings.Join(cur.CPEs, "\n"),
+				Material: true,
+			})
+		}
+
+		if !slices.Equal(prev.Ranges, cur.Ranges) {
+			changes = append(changes, Change{
+				ID:       id,
+				Field:    FieldRanges,
+				Old:      strings.Join(prev.Ranges, "\n"),
+				New:      strings.Join(cur.Ranges, "\n"),
+				Material: true,
+			})
+		}
+
+		if prev.Description != cur.Description {
+			changes = append(changes, Change{
+				ID:    id,
+				Field: FieldDescription,
+				Old:   prev.Description,
+				New:   cur.Description,
+			})
+		}
+	}
+
+	return changes
+}
+
+func severityRank(s string) int {
+	switch strings.ToUpper(s) {
+	case "LOW":
+		return 1
+	case "MEDIUM":
+		return 2
+	case "HIGH":
+		return 3
+	case "CRITICAL":
+		return 4
+	}
+
+	return 0
+}
+
+// Load reads a snapshot previously written by Save. If no file exists at the
+// given path, an empty snapshot is returned.
+func Load(path string) (Snapshot, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return Snapshot{Records: make(map[string]Record)}, nil
+		}
+		return Snapshot{}, err
+	}
+	defer f.Close()
+
+	return Decode(f)
+}
+
+// Decode reads a JSON-encoded snapshot.
+func Decode(r io.Reader) (Snapshot, error) {
+	var s Snapshot
+	if err := json.NewDecoder(r).Decode(&s); err != nil {
+		return Snapshot{}, fmt.Errorf("unable to decode snapshot: %w", err)
+	}
+	if s.Records == nil {
+		s.Records = make(map[string]Record)
+	}
+
+	return s, nil
+}
+
+// Save writes the snapshot as JSON to the given path. The file is written
+// atomically, by writing to a temporary file in the same directory and renaming
+// it over the original, so an interrupted run leaves the previous snapshot
+// intact.
+func Save(path string, s Snapshot) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	enc := json.NewEncoder(tmp)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(s); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
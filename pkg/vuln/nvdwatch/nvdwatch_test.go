@@ -0,0 +1,105 @@
+package nvdwatch
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompare(t *testing.T) {
+	previous := Snapshot{
+		Records: map[string]Record{
+			"CVE-2023-0001":       {LastModified: "1", Severity: "MEDIUM", BaseScore: 5.3, CPEs: []string{"a"}},
+			"CVE-2023-0002":       {LastModified: "1", Severity: "HIGH", BaseScore: 7.5, Description: "old"},
+			"CVE-2023-0003":       {LastModified: "1", Severity: "LOW", BaseScore: 2.0},
+			"GHSA-xxxx-yyyy-zzzz": {LastModified: "1", Severity: "HIGH", Ranges: []string{"npm:foo (< 1.9.0)"}},
+		},
+	}
+
+	current := Snapshot{
+		Records: map[string]Record{
+			"CVE-2023-0001":       {LastModified: "2", Severity: "CRITICAL", BaseScore: 9.8, CPEs: []string{"a", "b"}},
+			"CVE-2023-0002":       {LastModified: "2", Severity: "MEDIUM", BaseScore: 5.0, Description: "new"},
+			"CVE-2023-0003":       {LastModified: "1", Severity: "LOW", BaseScore: 2.0},
+			"CVE-2023-0004":       {LastModified: "1", Severity: "HIGH", BaseScore: 8.0},
+			"GHSA-xxxx-yyyy-zzzz": {LastModified: "2", Severity: "HIGH", Ranges: []string{"npm:foo (< 2.0.0)"}},
+		},
+	}
+
+	expected := []Change{
+		{ID: "CVE-2023-0001", Field: FieldSeverity, Old: "MEDIUM", New: "CRITICAL", Material: true},
+		{ID: "CVE-2023-0001", Field: FieldCPEs, Old: "a", New: "a\nb", Material: true},
+		{ID: "CVE-2023-0002", Field: FieldSeverity, Old: "HIGH", New: "MEDIUM"},
+		{ID: "CVE-2023-0002", Field: FieldDescription, Old: "old", New: "new"},
+		{ID: "GHSA-xxxx-yyyy-zzzz", Field: FieldRanges, Old: "npm:foo (< 1.9.0)", New: "npm:foo (< 2.0.0)", Material: true},
+	}
+
+	assert.Equal(t, expected, Compare(previous, current))
+}
+
+func TestSaveLoad(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "nvd-watch.json")
+	require.NoError(t, os.WriteFile(p, []byte("previous"), 0o644))
+
+	s := Snapshot{
+		Timestamp: time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC),
+		Records: map[string]Record{
+			"CVE-2023-0001":       {LastModified: "1", Severity: "HIGH"},
+			"GHSA-xxxx-yyyy-zzzz": {LastModified: "2", Ranges: []string{"go:example.com/foo (< 1.2.3)"}},
+		},
+	}
+	require.NoError(t, Save(p, s))
+
+	loaded, err := Load(p)
+	require.NoError(t, err)
+	assert.Equal(t, s, loaded)
+
+	// only the state file is left behind
+	entries, err := os.ReadDir(filepath.Dir(p))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestGHSAClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/advisories/GHSA-xxxx-yyyy-zzzz" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{
+  "ghsa_id": "GHSA-xxxx-yyyy-zzzz",
+  "updated_at": "2023-06-01T00:00:00Z",
+  "severity": "high",
+  "description": "bad things",
+  "cvss": {"score": 7.5},
+  "vulnerabilities": [
+    {"package": {"ecosystem": "npm", "name": "foo"}, "vulnerable_version_range": "< 2.0.0"},
+    {"package": {"ecosystem": "go", "name": "example.com/foo"}, "vulnerable_version_range": "< 1.2.3"}
+  ]
+}`))
+	}))
+	defer srv.Close()
+
+	c := NewGHSAClient(srv.Client())
+	c.baseURL = srv.URL
+
+	a, err := c.Advisory(context.Background(), "GHSA-xxxx-yyyy-zzzz")
+	require.NoError(t, err)
+	assert.Equal(t, Record{
+		LastModified: "2023-06-01T00:00:00Z",
+		Severity:     "HIGH",
+		BaseScore:    7.5,
+		Description:  "bad things",
+		Ranges:       []string{"go:example.com/foo (< 1.2.3)", "npm:foo (< 2.0.0)"},
+	}, NewGHSARecord(a))
+
+	_, err = c.Advisory(context.Background(), "GHSA-0000-0000-0000")
+	assert.Error(t, err)
+}
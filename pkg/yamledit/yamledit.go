@@ -0,0 +1,272 @@
+// Package yamledit reads, modifies and writes YAML files without losing the
+// comments, key ordering or formatting of the original document. Commands that
+// write changes back to melange configs (or any other hand-maintained YAML)
+// should go through this package rather than unmarshalling into a struct and
+// marshalling it again.
+package yamledit
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/chainguard-dev/yam/pkg/yam/formatted"
+	"gopkg.in/yaml.v3"
+)
+
+// Document is a parsed YAML document that can be modified in place and then
+// written back out.
+type Document struct {
+	root *yaml.Node
+}
+
+// Parse parses a YAML document from the given bytes.
+func Parse(b []byte) (*Document, error) {
+	root := &yaml.Node{}
+	err := yaml.Unmarshal(b, root)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse YAML: %w", err)
+	}
+
+	if root.Kind != yaml.DocumentNode || len(root.Content) == 0 {
+		return nil, fmt.Errorf("unable to parse YAML: empty document")
+	}
+
+	return &Document{root: root}, nil
+}
+
+// ReadFile parses the YAML document at the given path.
+func ReadFile(path string) (*Document, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := Parse(b)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return doc, nil
+}
+
+// Root returns the document's root node, for callers that need to make changes
+// not covered by Document's methods.
+func (d *Document) Root() *yaml.Node {
+	return d.root
+}
+
+// Get returns the node found by following the given sequence of mapping keys
+// from the top of the document, e.g. Get("package", "epoch").
+func (d *Document) Get(path ...string) (*yaml.Node, bool) {
+	node := d.root.Content[0]
+	for _, key := range path {
+		if node.Kind != yaml.MappingNode {
+			return nil, false
+		}
+
+		value := mappingValue(node, key)
+		if value == nil {
+			return nil, false
+		}
+		node = value
+	}
+
+	return node, true
+}
+
+// Set sets the value found at the given sequence of mapping keys, creating any
+// missing mappings along the way. When replacing an existing value, the
+// comments attached to it are kept.
+func (d *Document) Set(value any, path ...string) error {
+	if len(path) == 0 {
+		return fmt.Errorf("no key given")
+	}
+
+	newNode := &yaml.Node{}
+	err := newNode.Encode(value)
+	if err != nil {
+		return fmt.Errorf("unable to encode value for %q: %w", strings.Join(path, "."), err)
+	}
+
+	parent := d.root.Content[0]
+	for i, key := range path {
+		if parent.Kind != yaml.MappingNode {
+			return fmt.Errorf("unable to set %q: %q is not a mapping", strings.Join(path, "."), strings.Join(path[:i], "."))
+		}
+
+		existing := mappingValue(parent, key)
+
+		if i == len(path)-1 {
+			if existing == nil {
+				parent.Content = append(parent.Content, scalarKey(key), newNode)
+				return nil
+			}
+
+			newNode.HeadComment = existing.HeadComment
+			newNode.LineComment = existing.LineComment
+			newNode.FootComment = existing.FootComment
+			if newNode.Kind == yaml.ScalarNode && existing.Kind == yaml.ScalarNode && newNode.Tag == existing.Tag {
+				// Keep the original quoting style, e.g. for versions written as
+				// quoted strings.
+				newNode.Style = existing.Style
+			}
+			*existing = *newNode
+			return nil
+		}
+
+		if existing == nil {
+			existing = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			parent.Content = append(parent.Content, scalarKey(key), existing)
+		}
+		parent = existing
+	}
+
+	return nil
+}
+
+// Delete removes the entry found at the given sequence of mapping keys. It
+// reports whether the entry existed.
+func (d *Document) Delete(path ...string) bool {
+	if len(path) == 0 {
+		return false
+	}
+
+	parent, ok := d.Get(path[:len(path)-1]...)
+	if !ok || parent.Kind != yaml.MappingNode {
+		return false
+	}
+
+	key := path[len(path)-1]
+	for i := 0; i+1 < len(parent.Content); i += 2 {
+		if parent.Content[i].Value == key {
+			parent.Content = append(parent.Content[:i], parent.Content[i+2:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+// Encode writes the document to w, formatted according to the yam
+// configuration (.yam.yaml) found in the working directory, if any.
+func (d *Document) Encode(w io.Writer) error {
+	return Encode(w, d.root)
+}
+
+// Bytes returns the encoded document.
+func (d *Document) Bytes() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	err := d.Encode(buf)
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// WriteFile writes the document to the given path, keeping the file's existing
+// permissions if it already exists.
+func (d *Document) WriteFile(path string) error {
+	b, err := d.Bytes()
+	if err != nil {
+		return err
+	}
+
+	mode := os.FileMode(0o644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode()
+	}
+
+	err = os.WriteFile(path, b, mode)
+	if err != nil {
+		return fmt.Errorf("unable to write %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// Encode writes the given YAML node to w, formatted according to the yam
+// configuration (.yam.yaml) found in the working directory, if any.
+func Encode(w io.Writer, node *yaml.Node) error {
+	encoder := formatted.NewEncoder(w).AutomaticConfig()
+
+	err := encoder.Encode(node)
+	if err != nil {
+		return fmt.Errorf("unable to encode YAML: %w", err)
+	}
+
+	return nil
+}
+
+// ReplaceScalar returns a copy of the YAML document b in which the plain scalar
+// found at the given sequence of mapping keys is replaced by value. Unlike
+// editing a Document, nothing else in b is touched (not even formatting that yam
+// would change), so the only difference is the replaced value.
+func ReplaceScalar(b []byte, value string, path ...string) ([]byte, error) {
+	doc, err := Parse(b)
+	if err != nil {
+		return nil, err
+	}
+
+	key := strings.Join(path, ".")
+	node, ok := doc.Get(path...)
+	if !ok {
+		return nil, fmt.Errorf("unable to find %q", key)
+	}
+	if node.Kind != yaml.ScalarNode || node.Style != 0 {
+		return nil, fmt.Errorf("unable to replace %q: not a plain scalar", key)
+	}
+
+	start := offset(b, node.Line, node.Column)
+	end := start + len(node.Value)
+	if start < 0 || end > len(b) || string(b[start:end]) != node.Value {
+		return nil, fmt.Errorf("unable to replace %q: value doesn't fit on one line", key)
+	}
+
+	result := make([]byte, 0, len(b)-len(node.Value)+len(value))
+	result = append(result, b[:start]...)
+	result = append(result, value...)
+	return append(result, b[end:]...), nil
+}
+
+// offset returns the byte offset in b of the given 1-based line and column,
+// where columns count characters as yaml.v3 does, or -1 if there's no such
+// position.
+func offset(b []byte, line, column int) int {
+	i := 0
+	for l := 1; l < line; l++ {
+		n := bytes.IndexByte(b[i:], '\n')
+		if n < 0 {
+			return -1
+		}
+		i += n + 1
+	}
+
+	for c := 1; c < column; c++ {
+		if i >= len(b) || b[i] == '\n' {
+			return -1
+		}
+		_, size := utf8.DecodeRune(b[i:])
+		i += size
+	}
+
+	return i
+}
+
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+
+	return nil
+}
+
+func scalarKey(key string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}
+}
@@ -0,0 +1,94 @@
+package yamledit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocument(t *testing.T) {
+	cases := []struct {
+		name string
+		edit func(t *testing.T, doc *Document)
+	}{
+		{
+			name: "set-epoch",
+			edit: func(t *testing.T, doc *Document) {
+				require.NoError(t, doc.Set(2, "package", "epoch"))
+			},
+		},
+		{
+			name: "set-version",
+			edit: func(t *testing.T, doc *Document) {
+				require.NoError(t, doc.Set("2.13", "package", "version"))
+			},
+		},
+		{
+			name: "add-key",
+			edit: func(t *testing.T, doc *Document) {
+				require.NoError(t, doc.Set(12345, "update", "release-monitor", "identifier"))
+			},
+		},
+		{
+			name: "delete-key",
+			edit: func(t *testing.T, doc *Document) {
+				assert.True(t, doc.Delete("package", "description"))
+				assert.False(t, doc.Delete("package", "nonexistent"))
+			},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := ReadFile(filepath.Join("testdata", "config.yaml"))
+			require.NoError(t, err)
+
+			tt.edit(t, doc)
+
+			actual, err := doc.Bytes()
+			require.NoError(t, err)
+
+			expected, err := os.ReadFile(filepath.Join("testdata", tt.name+"_expected.yaml"))
+			require.NoError(t, err)
+
+			assert.Equal(t, string(expected), string(actual))
+		})
+	}
+}
+
+func TestDocument_Get(t *testing.T) {
+	doc, err := ReadFile(filepath.Join("testdata", "config.yaml"))
+	require.NoError(t, err)
+
+	node, ok := doc.Get("package", "copyright", "license")
+	require.True(t, ok)
+	assert.Equal(t, "GPL-3.0-or-later", node.Value)
+
+	_, ok = doc.Get("package", "name", "nested")
+	assert.False(t, ok)
+
+	err = doc.Set("x", "package", "name", "nested")
+	assert.Error(t, err)
+}
+
+func TestReplaceScalar(t *testing.T) {
+	b, err := os.ReadFile(filepath.Join("testdata", "unformatted.yaml"))
+	require.NoError(t, err)
+
+	actual, err := ReplaceScalar(b, "10", "package", "epoch")
+	require.NoError(t, err)
+
+	expected, err := os.ReadFile(filepath.Join("testdata", "replace-epoch_expected.yaml"))
+	require.NoError(t, err)
+
+	assert.Equal(t, string(expected), string(actual))
+
+	_, err = ReplaceScalar(b, "2.13", "package", "version")
+	assert.Error(t, err, "quoted scalars aren't replaced")
+
+	_, err = ReplaceScalar(b, "1", "package", "nonexistent")
+	assert.Error(t, err)
+}